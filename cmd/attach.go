@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -20,6 +21,16 @@ var (
 	attachDescription string
 	attachContentType string
 	attachForce       bool
+	attachRestart     bool
+)
+
+const (
+	// attachResumableThreshold is the file size above which uploads are sent
+	// in chunks that survive an interruption
+	attachResumableThreshold = 8 * 1024 * 1024
+
+	// attachChunkSize is the size of each chunk of a resumable upload
+	attachChunkSize = 4 * 1024 * 1024
 )
 
 // attachCmd represents the attach command
@@ -32,6 +43,10 @@ This command allows you to upload files and associate them with an issue.
 The files will be stored securely and will be available through the web UI
 and API endpoints.
 
+Files larger than 8MB are uploaded in chunks. If such an upload is
+interrupted, running the same command again resumes it where it stopped;
+use --restart to discard the partial upload and start over.
+
 Examples:
   issuemap attach ISSUEMAP-100 screenshot.png       # Attach single file
   issuemap attach ISSUEMAP-100 *.log                # Attach multiple files
@@ -52,6 +67,7 @@ func init() {
 	attachCmd.Flags().StringVarP(&attachDescription, "description", "d", "", "description for the attachment(s)")
 	attachCmd.Flags().StringVar(&attachContentType, "content-type", "", "override content type (MIME type) for the attachment(s)")
 	attachCmd.Flags().BoolVar(&attachForce, "force", false, "force upload even if file validation warnings occur")
+	attachCmd.Flags().BoolVar(&attachRestart, "restart", false, "discard interrupted uploads of these files and start over")
 }
 
 func runAttach(cmd *cobra.Command, issueIDStr string, filePaths []string) error {
@@ -101,6 +117,8 @@ func runAttach(cmd *cobra.Command, issueIDStr string, filePaths []string) error
 	if compressionService := services.NewCompressionService(issuemapPath, configRepo, attachmentRepo); compressionService != nil {
 		attachmentService.SetCompressionService(compressionService)
 	}
+	attachmentService.SetThumbnailService(services.NewThumbnailService(attachmentRepo))
+	uploadService := services.NewUploadService(issuemapPath, attachmentService)
 
 	// Expand file paths (handle globs)
 	expandedPaths, err := expandFilePaths(filePaths)
@@ -125,7 +143,7 @@ func runAttach(cmd *cobra.Command, issueIDStr string, filePaths []string) error
 			fmt.Printf("Uploading %s...\n", filePath)
 		}
 
-		attachment, err := uploadSingleFile(ctx, attachmentService, issueID, filePath, currentUser)
+		attachment, err := uploadSingleFile(ctx, attachmentService, uploadService, issueID, filePath, currentUser)
 		if err != nil {
 			if !noColor {
 				color.Red("✗ Failed to upload %s: %v", filePath, err)
@@ -176,6 +194,9 @@ func runAttach(cmd *cobra.Command, issueIDStr string, filePaths []string) error
 			if attachment.Description != "" {
 				fmt.Printf("    Description: %s\n", attachment.Description)
 			}
+			if attachment.Scan != nil {
+				fmt.Printf("    Scan: %s (%s)\n", attachment.Scan.Verdict, attachment.Scan.Scanner)
+			}
 		}
 	} else {
 		if !noColor {
@@ -189,7 +210,7 @@ func runAttach(cmd *cobra.Command, issueIDStr string, filePaths []string) error
 	return nil
 }
 
-func uploadSingleFile(ctx context.Context, attachmentService *services.AttachmentService, issueID entities.IssueID, filePath, uploadedBy string) (*entities.Attachment, error) {
+func uploadSingleFile(ctx context.Context, attachmentService *services.AttachmentService, uploadService *services.UploadService, issueID entities.IssueID, filePath, uploadedBy string) (*entities.Attachment, error) {
 	// Check if file exists and get info
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
@@ -215,6 +236,11 @@ func uploadSingleFile(ctx context.Context, attachmentService *services.Attachmen
 	// Get filename
 	filename := filepath.Base(filePath)
 
+	// Send large files in resumable chunks
+	if fileInfo.Size() > attachResumableThreshold {
+		return uploadResumable(ctx, uploadService, issueID, filePath, filename, fileInfo, file, uploadedBy)
+	}
+
 	// Upload attachment
 	attachment, err := attachmentService.UploadAttachment(
 		ctx,
@@ -239,6 +265,60 @@ func uploadSingleFile(ctx context.Context, attachmentService *services.Attachmen
 	return attachment, nil
 }
 
+// uploadResumable uploads a file in chunks through an upload session,
+// resuming an earlier interrupted upload of the same unchanged file
+func uploadResumable(ctx context.Context, uploadService *services.UploadService, issueID entities.IssueID, filePath, filename string, fileInfo os.FileInfo, file *os.File, uploadedBy string) (*entities.Attachment, error) {
+	source, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+	modTime := fileInfo.ModTime()
+
+	session, err := uploadService.FindResumable(ctx, issueID, source, fileInfo.Size(), modTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up interrupted uploads: %w", err)
+	}
+	if session != nil && attachRestart {
+		if err := uploadService.CancelUpload(ctx, session.ID); err != nil {
+			return nil, fmt.Errorf("failed to discard interrupted upload: %w", err)
+		}
+		session = nil
+	}
+
+	if session == nil {
+		session = entities.NewUploadSession(issueID, filename, fileInfo.Size(), uploadedBy)
+		session.Description = attachDescription
+		session.Source = source
+		session.SourceModTime = &modTime
+		if err := uploadService.CreateUpload(ctx, session); err != nil {
+			return nil, err
+		}
+	} else {
+		fmt.Printf("  Resuming interrupted upload at %.0f%%\n", session.Progress())
+	}
+
+	for {
+		if _, err := file.Seek(session.Offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		updated, attachment, err := uploadService.AppendChunk(ctx, session.ID, session.Offset, io.LimitReader(file, attachChunkSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w (run the command again to resume)", err)
+		}
+		if attachment != nil {
+			fmt.Printf("\r  %3.0f%% uploaded\n", 100.0)
+			return attachment, nil
+		}
+		if updated.Offset == session.Offset {
+			return nil, fmt.Errorf("upload made no progress at offset %d (run the command again to resume)", session.Offset)
+		}
+
+		session = updated
+		fmt.Printf("\r  %3.0f%% uploaded", session.Progress())
+	}
+}
+
 func expandFilePaths(patterns []string) ([]string, error) {
 	var expandedPaths []string
 
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	attachShowNoPager bool
+)
+
+// attachShowCmd represents the attach show command
+var attachShowCmd = &cobra.Command{
+	Use:   "show <attachment-id>",
+	Short: "Print a text attachment",
+	Long: `Print the contents of a text, Markdown, CSV or JSON attachment.
+
+When output is a terminal the content is shown through $PAGER (less by
+default). Attachment IDs are listed by 'issuemap show <issue-id>'.
+
+Examples:
+  issuemap attach show ISSUEMAP-100_1700000000_notes.txt
+  issuemap attach show ISSUEMAP-100_1700000000_data.csv --no-pager`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAttachShow(cmd, args[0])
+	},
+}
+
+func init() {
+	attachCmd.AddCommand(attachShowCmd)
+
+	attachShowCmd.Flags().BoolVar(&attachShowNoPager, "no-pager", false, "write directly to stdout instead of a pager")
+}
+
+func runAttachShow(cmd *cobra.Command, attachmentID string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(issuemapPath)
+	configRepo := storage.NewFileConfigRepository(issuemapPath)
+	attachmentRepo := storage.NewFileAttachmentRepository(issuemapPath)
+
+	storageService := services.NewStorageService(issuemapPath, configRepo, issueRepo, attachmentRepo)
+	attachmentService := services.NewAttachmentService(attachmentRepo, issueRepo, storageService, issuemapPath)
+	if compressionService := services.NewCompressionService(issuemapPath, configRepo, attachmentRepo); compressionService != nil {
+		attachmentService.SetCompressionService(compressionService)
+	}
+
+	attachment, err := attachmentService.GetAttachment(ctx, attachmentID)
+	if err != nil {
+		printError(fmt.Errorf("attachment %s not found: %w", attachmentID, err))
+		return err
+	}
+
+	if entities.PreviewKindFor(attachment.Filename, attachment.ContentType) == entities.PreviewKindNone {
+		err := fmt.Errorf("attachment %s is not a text file (%s); use the web UI or API to download it", attachment.Filename, attachment.ContentType)
+		printError(err)
+		return err
+	}
+
+	content, _, err := attachmentService.GetAttachmentContent(ctx, attachmentID)
+	if err != nil {
+		printError(fmt.Errorf("failed to read attachment: %w", err))
+		return err
+	}
+	defer content.Close()
+
+	if attachShowNoPager || !isTerminal(os.Stdout) {
+		_, err := io.Copy(os.Stdout, content)
+		return err
+	}
+
+	return pageOutput(content)
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// pageOutput streams content through $PAGER, falling back to stdout when no
+// pager is available
+func pageOutput(content io.Reader) error {
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less", "-R"}
+	}
+
+	if _, err := exec.LookPath(pager[0]); err != nil {
+		_, err := io.Copy(os.Stdout, content)
+		return err
+	}
+
+	pagerCmd := exec.Command(pager[0], pager[1:]...)
+	pagerCmd.Stdin = content
+	pagerCmd.Stdout = os.Stdout
+	pagerCmd.Stderr = os.Stderr
+	return pagerCmd.Run()
+}
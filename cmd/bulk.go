@@ -113,6 +113,12 @@ func buildBulkServices(ctx context.Context) (*services.BulkService, *services.Se
 
 	issueService := services.NewIssueService(issueRepo, configRepo, gitRepo)
 	searchService := services.NewSearchService(issueRepo)
+	if config, err := configRepo.Load(context.Background()); err == nil {
+		searchService.SetUserDirectory(config.UserDirectory())
+	}
+	if gitRepo != nil {
+		searchService.SetCurrentUser(getCurrentUser(gitRepo))
+	}
 	bulkService := services.NewBulkService(issueService, searchService, issuemapPath)
 	return bulkService, searchService, issueService, issuemapPath, nil
 }
@@ -239,7 +245,7 @@ func getCurrentUserName() string {
 	if err == nil {
 		if gitClient, err := git.NewGitClient(repoPath); err == nil {
 			if user, err := gitClient.GetAuthorInfo(context.Background()); err == nil {
-				if user.Username != "" {
+				if user = canonicalUser(user); user.Username != "" {
 					return user.Username
 				}
 			}
@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	bundleOutput        string
+	bundlePrefix        string
+	bundleOverwrite     bool
+	bundleIncludeConfig bool
+	bundleDryRun        bool
+	bundleJSON          bool
+)
+
+// bundleCmd represents the bundle command
+var bundleCmd = &cobra.Command{
+	Use:   "bundle",
+	Short: "Export and import complete projects",
+	Long: `Move a whole project between repositories without losing data.
+
+A bundle is a single versioned archive containing issues, history, dependencies,
+time entries, templates, configuration and attachment files. Attachments are
+stored once per content hash, and every entry is covered by a checksum.
+Confidential issues are bundled encrypted, exactly as they are stored.
+
+Examples:
+  issuemap bundle export -o project.bundle.tar.gz
+  issuemap bundle verify project.bundle.tar.gz
+  issuemap bundle import project.bundle.tar.gz --prefix NEWPROJ
+  issuemap bundle import project.bundle.tar.gz --dry-run`,
+}
+
+// bundleExportCmd represents the bundle export command
+var bundleExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the project to a bundle",
+	Long: `Export all project data to a bundle archive.
+
+Examples:
+  issuemap bundle export
+  issuemap bundle export -o backup.tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBundleExport(cmd, args)
+	},
+}
+
+// bundleImportCmd represents the bundle import command
+var bundleImportCmd = &cobra.Command{
+	Use:   "import <bundle-file>",
+	Short: "Import a bundle into the project",
+	Long: `Import a bundle into the current project. The bundle is verified before anything is written.
+
+Existing records are skipped unless --overwrite is given. Use --prefix to give
+imported issues a new ID prefix; references to remapped issues in dependencies,
+time entries, history and issue text are rewritten.
+
+Sealed content is bound to its issue ID, so a confidential issue can only be
+given a new ID by a recipient who can decrypt it; it is then sealed again to
+this project's recipients. Other confidential issues are skipped with a warning.
+
+Examples:
+  issuemap bundle import project.bundle.tar.gz
+  issuemap bundle import project.bundle.tar.gz --prefix NEWPROJ
+  issuemap bundle import project.bundle.tar.gz --overwrite --with-config`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBundleImport(cmd, args)
+	},
+}
+
+// bundleVerifyCmd represents the bundle verify command
+var bundleVerifyCmd = &cobra.Command{
+	Use:   "verify <bundle-file>",
+	Short: "Verify bundle integrity",
+	Long: `Check a bundle's format version and the checksum of every entry.
+
+Examples:
+  issuemap bundle verify project.bundle.tar.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBundleVerify(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bundleCmd)
+	bundleCmd.AddCommand(bundleExportCmd)
+	bundleCmd.AddCommand(bundleImportCmd)
+	bundleCmd.AddCommand(bundleVerifyCmd)
+
+	bundleExportCmd.Flags().StringVarP(&bundleOutput, "output", "o", "", "output file (default: <project>-<date>.bundle.tar.gz)")
+
+	bundleImportCmd.Flags().StringVar(&bundlePrefix, "prefix", "", "replace the ID prefix of imported issues (e.g., NEWPROJ)")
+	bundleImportCmd.Flags().BoolVar(&bundleOverwrite, "overwrite", false, "replace existing records instead of skipping them")
+	bundleImportCmd.Flags().BoolVar(&bundleIncludeConfig, "with-config", false, "also import the project configuration")
+	bundleImportCmd.Flags().BoolVar(&bundleDryRun, "dry-run", false, "preview the import without making changes")
+
+	bundleCmd.PersistentFlags().BoolVar(&bundleJSON, "json", false, "output in JSON format")
+}
+
+// newBundleService wires a bundle service for the current repository
+func newBundleService() (*services.BundleService, *git.GitClient, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	basePath := filepath.Join(repoPath, ".issuemap")
+	configRepo := storage.NewFileConfigRepository(basePath)
+
+	bundleService := services.NewBundleService(
+		basePath,
+		storage.NewFileIssueRepository(basePath),
+		storage.NewFileHistoryRepository(basePath),
+		storage.NewFileDependencyRepository(basePath),
+		storage.NewFileTimeEntryRepository(basePath),
+		configRepo,
+		storage.NewFileAttachmentRepository(basePath),
+	)
+	if dedupService := services.NewDeduplicationService(basePath, configRepo); dedupService != nil {
+		bundleService.SetDeduplicationService(dedupService)
+	}
+
+	gitClient, _ := git.NewGitClient(repoPath)
+	return bundleService, gitClient, nil
+}
+
+func runBundleExport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	bundleService, gitClient, err := newBundleService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	output := bundleOutput
+	if output == "" {
+		output = fmt.Sprintf("%s-%s.bundle.tar.gz", getCurrentProjectName(ctx), time.Now().Format("20060102-150405"))
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		printError(fmt.Errorf("failed to create bundle file: %w", err))
+		return err
+	}
+	defer file.Close()
+
+	createdBy := ""
+	if gitClient != nil {
+		createdBy = getCurrentUser(gitClient)
+	}
+
+	manifest, err := bundleService.Export(ctx, file, createdBy)
+	if err != nil {
+		os.Remove(output)
+		printError(fmt.Errorf("failed to export bundle: %w", err))
+		return err
+	}
+
+	if bundleJSON {
+		return printBundleJSON(manifest)
+	}
+
+	printSuccess(fmt.Sprintf("Exported project to %s", output))
+	printBundleCounts(manifest.Counts)
+	return nil
+}
+
+func runBundleImport(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	bundleService, _, err := newBundleService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		printError(fmt.Errorf("failed to open bundle: %w", err))
+		return err
+	}
+	defer file.Close()
+
+	result, err := bundleService.Import(ctx, file, entities.BundleImportOptions{
+		TargetPrefix:  bundlePrefix,
+		Overwrite:     bundleOverwrite,
+		IncludeConfig: bundleIncludeConfig,
+		DryRun:        bundleDryRun,
+	})
+	if err != nil {
+		printError(fmt.Errorf("failed to import bundle: %w", err))
+		return err
+	}
+
+	if bundleJSON {
+		return printBundleJSON(result)
+	}
+
+	if result.DryRun {
+		printInfo("Dry run - no changes were made")
+	} else {
+		printSuccess(fmt.Sprintf("Imported bundle %s", args[0]))
+	}
+	printBundleCounts(result.Counts)
+
+	if len(result.Remapped) > 0 {
+		fmt.Println()
+		fmt.Println("Remapped issues:")
+		from := make([]string, 0, len(result.Remapped))
+		for id := range result.Remapped {
+			from = append(from, string(id))
+		}
+		sort.Strings(from)
+		for _, id := range from {
+			fmt.Printf("  %s -> %s\n", id, colorIssueID(result.Remapped[entities.IssueID(id)]))
+		}
+	}
+
+	if len(result.Skipped) > 0 {
+		fmt.Println()
+		printWarning(fmt.Sprintf("Skipped %d existing records (use --overwrite to replace them):", len(result.Skipped)))
+		for _, skipped := range result.Skipped {
+			fmt.Printf("  - %s\n", skipped)
+		}
+	}
+
+	for _, warning := range result.Warnings {
+		printWarning(warning)
+	}
+
+	return nil
+}
+
+func runBundleVerify(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	bundleService, _, err := newBundleService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		printError(fmt.Errorf("failed to open bundle: %w", err))
+		return err
+	}
+	defer file.Close()
+
+	result, err := bundleService.Verify(ctx, file)
+	if err != nil {
+		printError(fmt.Errorf("failed to read bundle: %w", err))
+		return err
+	}
+
+	if bundleJSON {
+		if err := printBundleJSON(result); err != nil {
+			return err
+		}
+	} else if result.Valid {
+		printSuccess(fmt.Sprintf("Bundle %s is valid (format v%d, project %s)",
+			args[0], result.Manifest.FormatVersion, result.Manifest.Project))
+		printBundleCounts(result.Manifest.Counts)
+	} else {
+		printError(fmt.Errorf("bundle %s failed verification", args[0]))
+		for _, problem := range result.Errors {
+			fmt.Printf("  - %s\n", problem)
+		}
+	}
+
+	if !result.Valid {
+		return fmt.Errorf("bundle verification failed")
+	}
+	return nil
+}
+
+func printBundleCounts(counts entities.BundleCounts) {
+	fmt.Printf("  Issues:       %d", counts.Issues)
+	if counts.Confidential > 0 {
+		fmt.Printf(" (%d confidential)", counts.Confidential)
+	}
+	fmt.Println()
+	fmt.Printf("  Histories:    %d\n", counts.Histories)
+	fmt.Printf("  Dependencies: %d\n", counts.Dependencies)
+	fmt.Printf("  Time entries: %d\n", counts.TimeEntries)
+	fmt.Printf("  Templates:    %d\n", counts.Templates)
+	fmt.Printf("  Attachments:  %d", counts.Attachments)
+	if counts.Blobs > 0 {
+		fmt.Printf(" (%d unique files)", counts.Blobs)
+	}
+	fmt.Println()
+}
+
+func printBundleJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
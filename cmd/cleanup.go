@@ -15,6 +15,7 @@ import (
 	"github.com/ooyeku/issuemap/internal/app/services"
 	"github.com/ooyeku/issuemap/internal/domain/entities"
 	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
 	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
 )
 
@@ -130,6 +131,12 @@ func runCleanup(cmd *cobra.Command, args []string) error {
 
 	cleanupService := services.NewCleanupService(issuemapPath, configRepo, issueRepo, attachmentRepo)
 
+	// Use a git-backed history service so compaction checkpoints can be signed
+	if gitClient, err := git.NewGitClient(repoPath); err == nil {
+		historyRepo := storage.NewFileHistoryRepository(issuemapPath)
+		cleanupService.SetHistoryService(services.NewHistoryService(historyRepo, gitClient))
+	}
+
 	// Check if admin flags are used
 	if hasAdminFlags(cmd) {
 		return runAdminCleanup(ctx, cleanupService, issuemapPath, configRepo, issueRepo, attachmentRepo)
@@ -0,0 +1,384 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	confidentialGenerate bool
+	confidentialJSON     bool
+)
+
+// confidentialCmd represents the confidential command
+var confidentialCmd = &cobra.Command{
+	Use:   "confidential",
+	Short: "Manage encryption of confidential issues",
+	Long: `Confidential issues are stored with their description, comments and
+attachments encrypted to a set of recipients. Recipients read them as usual;
+everyone else sees "[confidential]" placeholders in list, show and the API.
+Titles and statuses stay in clear for triage unless configured otherwise.
+
+Each recipient needs an identity, a private key kept outside the repository
+(~/.issuemap_global/identity.key, or the path in ISSUEMAP_IDENTITY). Its
+public key is listed in the config:
+
+  confidential:
+    recipients:
+      - name: alice
+        public_key: x25519:...
+    seal_title: false
+    seal_status: false
+
+Existing history entries and git history are not rewritten when an issue is
+marked confidential. Run 'rekey' after changing recipients so that existing
+issues are readable by the new set.
+
+Examples:
+  issuemap confidential identity --generate
+  issuemap confidential add-recipient alice          # your own public key
+  issuemap confidential add-recipient bob x25519:...
+  issuemap create "Token leak in logs" --confidential
+  issuemap confidential mark ISSUE-001
+  issuemap confidential rekey`,
+}
+
+// confidentialIdentityCmd shows or creates the local identity
+var confidentialIdentityCmd = &cobra.Command{
+	Use:   "identity",
+	Short: "Show the public key of your identity, or generate one",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfidentialIdentity()
+	},
+}
+
+// confidentialRecipientsCmd lists recipients
+var confidentialRecipientsCmd = &cobra.Command{
+	Use:   "recipients",
+	Short: "List the recipients confidential issues are encrypted to",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfidentialRecipients()
+	},
+}
+
+// confidentialAddRecipientCmd adds a recipient
+var confidentialAddRecipientCmd = &cobra.Command{
+	Use:   "add-recipient <name> [public-key]",
+	Short: "Add a recipient; the key defaults to your own identity",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		publicKey := ""
+		if len(args) > 1 {
+			publicKey = args[1]
+		}
+		return runConfidentialAddRecipient(args[0], publicKey)
+	},
+}
+
+// confidentialRemoveRecipientCmd removes a recipient
+var confidentialRemoveRecipientCmd = &cobra.Command{
+	Use:   "remove-recipient <name>",
+	Short: "Remove a recipient",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfidentialRemoveRecipient(args[0])
+	},
+}
+
+// confidentialMarkCmd marks an issue confidential
+var confidentialMarkCmd = &cobra.Command{
+	Use:   "mark <issue-id>",
+	Short: "Encrypt an issue's content to the recipients",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfidentialSet(args[0], true)
+	},
+}
+
+// confidentialUnmarkCmd stores an issue in clear again
+var confidentialUnmarkCmd = &cobra.Command{
+	Use:   "unmark <issue-id>",
+	Short: "Store a confidential issue in clear again",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfidentialSet(args[0], false)
+	},
+}
+
+// confidentialRekeyCmd re-encrypts issues to the current recipients
+var confidentialRekeyCmd = &cobra.Command{
+	Use:   "rekey",
+	Short: "Encrypt confidential issues again to the current recipients",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfidentialRekey()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(confidentialCmd)
+	confidentialCmd.AddCommand(confidentialIdentityCmd)
+	confidentialCmd.AddCommand(confidentialRecipientsCmd)
+	confidentialCmd.AddCommand(confidentialAddRecipientCmd)
+	confidentialCmd.AddCommand(confidentialRemoveRecipientCmd)
+	confidentialCmd.AddCommand(confidentialMarkCmd)
+	confidentialCmd.AddCommand(confidentialUnmarkCmd)
+	confidentialCmd.AddCommand(confidentialRekeyCmd)
+
+	confidentialIdentityCmd.Flags().BoolVar(&confidentialGenerate, "generate", false, "generate a new identity if none exists")
+	confidentialRecipientsCmd.Flags().BoolVar(&confidentialJSON, "json", false, "output as JSON")
+}
+
+func runConfidentialIdentity() error {
+	path := entities.GetIdentityPath()
+
+	var identity *storage.ConfidentialIdentity
+	var err error
+	if confidentialGenerate {
+		identity, err = storage.GenerateConfidentialIdentity(path)
+		if err != nil {
+			printError(fmt.Errorf("failed to generate identity: %w", err))
+			return err
+		}
+		printSuccess(fmt.Sprintf("Generated identity at %s", path))
+	} else {
+		identity, err = storage.LoadConfidentialIdentity(path)
+		if os.IsNotExist(err) {
+			printInfo(fmt.Sprintf("No identity at %s. Create one with --generate.", path))
+			return nil
+		}
+		if err != nil {
+			printError(fmt.Errorf("failed to load identity: %w", err))
+			return err
+		}
+	}
+
+	fmt.Printf("%s %s\n", colorLabel("Path:"), colorValue(path))
+	fmt.Printf("%s %s\n", colorLabel("Key ID:"), colorValue(identity.KeyID()))
+	fmt.Printf("%s %s\n", colorLabel("Public key:"), identity.PublicKey())
+	return nil
+}
+
+func runConfidentialRecipients() error {
+	ctx := context.Background()
+	configRepo, err := confidentialConfigRepo()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	config, err := configRepo.Load(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to load config: %w", err))
+		return err
+	}
+	confidential := config.Confidential
+	if confidential == nil {
+		confidential = &entities.ConfidentialConfig{}
+	}
+
+	if confidentialJSON {
+		return outputJSON(confidential)
+	}
+
+	if len(confidential.Recipients) == 0 {
+		printInfo("No recipients configured. Add one with 'issuemap confidential add-recipient'.")
+		return nil
+	}
+
+	ownKeyID := ""
+	if identity, err := storage.LoadConfidentialIdentity(entities.GetIdentityPath()); err == nil {
+		ownKeyID = identity.KeyID()
+	}
+
+	fmt.Printf("%s\n\n", colorHeader("Recipients"))
+	for _, recipient := range confidential.Recipients {
+		keyID, err := storage.ParseConfidentialRecipient(recipient.PublicKey)
+		if err != nil {
+			fmt.Printf("  %-20s %s\n", recipient.Name, colorValue(fmt.Sprintf("invalid key: %v", err)))
+			continue
+		}
+		marker := ""
+		if keyID == ownKeyID {
+			marker = " (you)"
+		}
+		fmt.Printf("  %-20s %s%s\n", recipient.Name, colorValue(keyID), marker)
+	}
+
+	fmt.Printf("\n%s %s\n", colorLabel("Sealed fields:"), colorValue(fmt.Sprintf("%v", confidential.SealedFields())))
+	return nil
+}
+
+func runConfidentialAddRecipient(name, publicKey string) error {
+	ctx := context.Background()
+
+	if publicKey == "" {
+		identity, err := storage.LoadConfidentialIdentity(entities.GetIdentityPath())
+		if err != nil {
+			printError(fmt.Errorf("no public key given and no identity found; run 'issuemap confidential identity --generate': %w", err))
+			return err
+		}
+		publicKey = identity.PublicKey()
+	}
+
+	keyID, err := storage.ParseConfidentialRecipient(publicKey)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	configRepo, err := confidentialConfigRepo()
+	if err != nil {
+		printError(err)
+		return err
+	}
+	config, err := configRepo.Load(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to load config: %w", err))
+		return err
+	}
+	if config.Confidential == nil {
+		config.Confidential = &entities.ConfidentialConfig{}
+	}
+	if _, exists := config.Confidential.FindRecipient(name); exists {
+		err := fmt.Errorf("recipient %s already exists", name)
+		printError(err)
+		return err
+	}
+
+	config.Confidential.Recipients = append(config.Confidential.Recipients, entities.ConfidentialRecipient{
+		Name:      name,
+		PublicKey: publicKey,
+	})
+	if err := configRepo.Save(ctx, config); err != nil {
+		printError(fmt.Errorf("failed to save config: %w", err))
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Added recipient %s (%s)", name, keyID))
+	printInfo("Run 'issuemap confidential rekey' so existing confidential issues are readable by the new recipient.")
+	return nil
+}
+
+func runConfidentialRemoveRecipient(name string) error {
+	ctx := context.Background()
+	configRepo, err := confidentialConfigRepo()
+	if err != nil {
+		printError(err)
+		return err
+	}
+	config, err := configRepo.Load(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to load config: %w", err))
+		return err
+	}
+
+	if config.Confidential == nil {
+		err := fmt.Errorf("recipient %s not found", name)
+		printError(err)
+		return err
+	}
+	if _, exists := config.Confidential.FindRecipient(name); !exists {
+		err := fmt.Errorf("recipient %s not found", name)
+		printError(err)
+		return err
+	}
+
+	var kept []entities.ConfidentialRecipient
+	for _, recipient := range config.Confidential.Recipients {
+		if recipient.Name != name {
+			kept = append(kept, recipient)
+		}
+	}
+	config.Confidential.Recipients = kept
+	if err := configRepo.Save(ctx, config); err != nil {
+		printError(fmt.Errorf("failed to save config: %w", err))
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Removed recipient %s", name))
+	printWarning("Existing confidential issues stay readable by the removed key until they are rekeyed; content they could already read remains exposed in git history.")
+	return nil
+}
+
+func runConfidentialSet(issueArg string, confidential bool) error {
+	ctx := context.Background()
+	issueService, err := confidentialIssueService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	issueID := normalizeIssueID(issueArg)
+	issue, err := issueService.SetConfidential(ctx, issueID, confidential)
+	if err != nil {
+		printError(fmt.Errorf("failed to update %s: %w", issueID, err))
+		return err
+	}
+
+	if confidential {
+		printSuccess(fmt.Sprintf("%s is now confidential", issue.ID))
+		printWarning("Earlier history entries, existing attachments and git history still hold the content in clear.")
+	} else {
+		printSuccess(fmt.Sprintf("%s is no longer confidential", issue.ID))
+	}
+	return nil
+}
+
+func runConfidentialRekey() error {
+	ctx := context.Background()
+	issueService, err := confidentialIssueService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	rekeyed, skipped, err := issueService.RekeyConfidentialIssues(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to rekey issues: %w", err))
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Rekeyed %d confidential issue(s)", len(rekeyed)))
+	for _, id := range skipped {
+		printWarning(fmt.Sprintf("%s skipped: not readable with this identity", id))
+	}
+	return nil
+}
+
+func confidentialConfigRepo() (*storage.FileConfigRepository, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+	return storage.NewFileConfigRepository(filepath.Join(repoPath, app.ConfigDirName)), nil
+}
+
+func confidentialIssueService() (*services.IssueService, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+
+	var gitClient *git.GitClient
+	if client, err := git.NewGitClient(repoPath); err == nil {
+		gitClient = client
+	}
+	return services.NewIssueService(issueRepo, configRepo, gitClient), nil
+}
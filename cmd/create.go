@@ -16,15 +16,16 @@ import (
 )
 
 var (
-	createTitle       string
-	createDescription string
-	createType        string
-	createPriority    string
-	createAssignee    string
-	createLabels      []string
-	createMilestone   string
-	createTemplate    string
-	createInteractive bool
+	createTitle        string
+	createDescription  string
+	createType         string
+	createPriority     string
+	createAssignee     string
+	createLabels       []string
+	createMilestone    string
+	createTemplate     string
+	createInteractive  bool
+	createConfidential bool
 )
 
 // createCmd represents the create command
@@ -58,6 +59,7 @@ func init() {
 	createCmd.Flags().StringVarP(&createMilestone, "milestone", "m", "", "milestone name")
 	createCmd.Flags().StringVar(&createTemplate, "template", "", "template to use (bug, feature, task, epic)")
 	createCmd.Flags().BoolVarP(&createInteractive, "interactive", "i", false, "interactive mode")
+	createCmd.Flags().BoolVar(&createConfidential, "confidential", false, "encrypt the issue's content to the configured recipients")
 }
 
 func runCreate(_ *cobra.Command, _ []string) error {
@@ -100,11 +102,12 @@ func runCreate(_ *cobra.Command, _ []string) error {
 
 	// Build create request
 	req := services.CreateIssueRequest{
-		Title:       createTitle,
-		Description: createDescription,
-		Type:        entities.IssueType(createType),
-		Priority:    entities.Priority(createPriority),
-		Labels:      createLabels,
+		Title:        createTitle,
+		Description:  createDescription,
+		Type:         entities.IssueType(createType),
+		Priority:     entities.Priority(createPriority),
+		Labels:       createLabels,
+		Confidential: createConfidential,
 	}
 
 	if createAssignee != "" {
@@ -124,6 +127,11 @@ func runCreate(_ *cobra.Command, _ []string) error {
 		return err
 	}
 
+	var ownerHint string
+	if createAssignee == "" {
+		issue, ownerHint = assignOwnerOnCreate(ctx, repoPath, issue)
+	}
+
 	// Display success message
 	printSuccess(fmt.Sprintf(app.MsgIssueCreated, issue.ID))
 
@@ -132,6 +140,9 @@ func runCreate(_ *cobra.Command, _ []string) error {
 	fmt.Printf("Type: %s\n", issue.Type)
 	fmt.Printf("Status: %s\n", issue.Status)
 	fmt.Printf("Priority: %s\n", issue.Priority)
+	if issue.Confidential {
+		fmt.Printf("Confidential: yes\n")
+	}
 	if issue.Assignee != nil {
 		fmt.Printf("Assignee: %s\n", issue.Assignee.Username)
 	}
@@ -146,6 +157,10 @@ func runCreate(_ *cobra.Command, _ []string) error {
 		fmt.Printf("Branch: %s\n", issue.Branch)
 	}
 	fmt.Printf("Created: %s\n", issue.Timestamps.Created.Format("2006-01-02 15:04:05"))
+	if ownerHint != "" {
+		fmt.Println()
+		printInfo(ownerHint)
+	}
 
 	return nil
 }
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	gitScanSince  string
+	gitScanFull   bool
+	gitScanDryRun bool
+)
+
+// gitCmd groups commands that work on the git history
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Link the git history to issues",
+}
+
+// gitScanCmd backfills commit references into issues
+var gitScanCmd = &cobra.Command{
+	Use:   "scan",
+	Short: "Record the commits that reference each issue",
+	Long: `Walk the commits of every branch, remote-tracking branch and tag once and record
+them on the issues they reference.
+
+A commit belongs to an issue when its message mentions the issue, such as
+"AR-12: fix parser", "Refs: AR-12" or "Fixes #12", or when it is on a branch
+named after the issue, such as feature/AR-12-parser. References to issues that
+do not exist are reported.
+
+The refs that were scanned are recorded in .issuemap/metadata/git_scan.yaml, so
+the next scan only walks newer commits. Use --full to scan the whole history
+again, or --since to start after a given ref.
+
+Examples:
+  issuemap git scan                  # Scan commits made since the last scan
+  issuemap git scan --since v1.0.0   # Scan commits not reachable from v1.0.0
+  issuemap git scan --full --dry-run # Show what a full scan would record`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runGitScan(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(gitScanCmd)
+
+	gitScanCmd.Flags().StringVar(&gitScanSince, "since", "", "only scan commits not reachable from this ref")
+	gitScanCmd.Flags().BoolVar(&gitScanFull, "full", false, "scan the whole history, ignoring the last scan")
+	gitScanCmd.Flags().BoolVar(&gitScanDryRun, "dry-run", false, "show what would be recorded without changing issues")
+}
+
+func runGitScan(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	gitClient, err := git.NewGitClient(repoPath)
+	if err != nil {
+		printError(fmt.Errorf("failed to initialize git client: %w", err))
+		return err
+	}
+
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	scanService := services.NewGitScanService(storage.NewFileIssueRepository(basePath), gitClient, basePath)
+
+	result, err := scanService.Scan(ctx, services.GitScanOptions{
+		Since:  gitScanSince,
+		Full:   gitScanFull,
+		DryRun: gitScanDryRun,
+	})
+	if err != nil {
+		printError(fmt.Errorf("failed to scan commits: %w", err))
+		return err
+	}
+
+	if format == "json" {
+		return outputJSON(result)
+	}
+
+	scope := ""
+	switch {
+	case result.Since != "":
+		scope = " since " + result.Since
+	case result.Incremental:
+		scope = " since the last scan"
+	}
+	printSectionHeader(fmt.Sprintf("Scanned %d commit(s)%s", result.Commits, scope))
+
+	for _, link := range result.Links {
+		fmt.Printf("  %s  %s  %s", link.IssueID, shortHash(link.Hash), link.Message)
+		if link.Source == "branch" {
+			fmt.Print(" (branch)")
+		}
+		fmt.Println()
+	}
+
+	if len(result.Unknown) > 0 {
+		fmt.Println()
+		printWarning(fmt.Sprintf("%d reference(s) to issues that do not exist:", len(result.Unknown)))
+		for _, unknown := range result.Unknown {
+			hashes := make([]string, 0, len(unknown.Commits))
+			for _, hash := range unknown.Commits {
+				hashes = append(hashes, shortHash(hash))
+			}
+			fmt.Printf("  %s  in %s\n", unknown.Ref, strings.Join(hashes, ", "))
+		}
+	}
+
+	fmt.Println()
+	if result.DryRun {
+		printInfo(fmt.Sprintf("Dry run: would link %d commit(s) to %d issue(s)", len(result.Links), result.Issues))
+		return nil
+	}
+	printSuccess(fmt.Sprintf("Linked %d commit(s) to %d issue(s)", len(result.Links), result.Issues))
+	return nil
+}
@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+var (
+	globalJSON          bool
+	globalBackupAll     bool
+	globalBackupTags    []string
+	globalRestoreTarget string
+	globalKeyRotate     bool
+	globalKeyEnable     bool
+	globalKeyDisable    bool
+)
+
+// globalCmd represents the global command
+var globalCmd = &cobra.Command{
+	Use:   "global",
+	Short: "Manage projects, archives and backups across repositories",
+	Long: `Work with the global issuemap directory (~/.issuemap_global), which tracks
+registered projects and stores their archives and backups.`,
+}
+
+// globalBackupCmd groups the backup commands
+var globalBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Create, list, verify and restore project backups",
+	Long: `Back up a project's .issuemap directory to the global backup store.
+
+Backups are incremental: only files whose content changed since the
+project's previous backup are stored, and the backup's manifest points to
+earlier backups for the rest. Backups are encrypted with AES-256-GCM when
+encryption is enabled, using keys kept in ~/.issuemap_global/keys. Only the
+newest max_backup_retention backups of each project are kept; older backups
+that newer ones still depend on are kept until they are no longer needed.
+
+Settings in ~/.issuemap_global/config.yaml:
+
+  global_settings:
+    max_backup_retention: 30   # 0 keeps every backup
+    encryption_enabled: true
+    full_backups_only: false
+
+Examples:
+  issuemap global backup create
+  issuemap global backup list
+  issuemap global backup verify              # Verify the latest backup
+  issuemap global backup restore <id> --to /tmp/restore
+  issuemap global backup key --enable        # Encrypt new backups`,
+}
+
+var globalBackupCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Back up the current project",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGlobalBackupCreate(cmd)
+	},
+}
+
+var globalBackupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List backups of the current project",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGlobalBackupList(cmd)
+	},
+}
+
+var globalBackupVerifyCmd = &cobra.Command{
+	Use:   "verify [backup-id]",
+	Short: "Test-restore a backup and diff it against the project",
+	Long: `Restore a backup into a temporary directory, check every file against the
+hashes recorded when the backup was made and the archives against their
+checksums, then diff the restored files against the project's current
+.issuemap directory. Without an ID the project's latest backup is verified.
+
+Missing or corrupted files fail verification. Differences from the project
+are reported as changes made since the backup was taken.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		backupID := ""
+		if len(args) > 0 {
+			backupID = args[0]
+		}
+		return runGlobalBackupVerify(cmd, backupID)
+	},
+}
+
+var globalBackupRestoreCmd = &cobra.Command{
+	Use:   "restore <backup-id>",
+	Short: "Restore a backup into an empty directory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGlobalBackupRestore(cmd, args[0])
+	},
+}
+
+var globalBackupKeyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Show or rotate the backup encryption key",
+	Long: `Show whether backups are encrypted and which key new backups use.
+
+Keys are stored in ~/.issuemap_global/keys and are never deleted, so
+backups made with a rotated key can still be restored. Copy this directory
+to restore encrypted backups on another machine.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runGlobalBackupKey(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(globalCmd)
+	globalCmd.AddCommand(globalBackupCmd)
+	globalBackupCmd.AddCommand(globalBackupCreateCmd)
+	globalBackupCmd.AddCommand(globalBackupListCmd)
+	globalBackupCmd.AddCommand(globalBackupVerifyCmd)
+	globalBackupCmd.AddCommand(globalBackupRestoreCmd)
+	globalBackupCmd.AddCommand(globalBackupKeyCmd)
+
+	globalCmd.PersistentFlags().BoolVar(&globalJSON, "json", false, "output as JSON")
+	globalBackupCreateCmd.Flags().StringSliceVar(&globalBackupTags, "tag", nil, "tag the backup")
+	globalBackupListCmd.Flags().BoolVar(&globalBackupAll, "all", false, "list backups of all projects")
+	globalBackupRestoreCmd.Flags().StringVar(&globalRestoreTarget, "to", "", "directory to restore into (required)")
+	globalBackupRestoreCmd.MarkFlagRequired("to")
+	globalBackupKeyCmd.Flags().BoolVar(&globalKeyRotate, "rotate", false, "generate a new key for future backups")
+	globalBackupKeyCmd.Flags().BoolVar(&globalKeyEnable, "enable", false, "encrypt new backups")
+	globalBackupKeyCmd.Flags().BoolVar(&globalKeyDisable, "disable", false, "stop encrypting new backups")
+	globalBackupKeyCmd.MarkFlagsMutuallyExclusive("enable", "disable")
+}
+
+func runGlobalBackupCreate(cmd *cobra.Command) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	backup, err := services.NewGlobalService().BackupProject(ctx, repoPath, globalBackupTags)
+	if err != nil {
+		printError(fmt.Errorf("backup failed: %w", err))
+		return err
+	}
+
+	if globalJSON {
+		return outputJSON(backup)
+	}
+
+	kind := "Full"
+	if backup.Incremental {
+		kind = "Incremental"
+	}
+	printSuccess(fmt.Sprintf("Created backup %s", backup.ID))
+	fmt.Printf("%s %s\n", colorLabel("Type:"), colorValue(kind))
+	fmt.Printf("%s %d stored, %d total\n", colorLabel("Files:"), backup.StoredFiles, len(backup.Files))
+	fmt.Printf("%s %s\n", colorLabel("Size:"), colorValue(entities.FormatBytes(backup.Size)))
+	if backup.Encrypted {
+		fmt.Printf("%s %s\n", colorLabel("Encrypted with key:"), colorValue(backup.KeyID))
+	}
+	fmt.Printf("%s %s\n", colorLabel("Location:"), colorValue(backup.BackupPath))
+	if len(backup.Pruned) > 0 {
+		printInfo(fmt.Sprintf("Pruned %d old backups: %s", len(backup.Pruned), strings.Join(backup.Pruned, ", ")))
+	}
+
+	return nil
+}
+
+func runGlobalBackupList(cmd *cobra.Command) error {
+	ctx := context.Background()
+
+	var projectPath *string
+	if !globalBackupAll {
+		repoPath, err := findGitRoot()
+		if err != nil {
+			printError(fmt.Errorf("not in a git repository: %w", err))
+			return err
+		}
+		projectPath = &repoPath
+	}
+
+	backups, err := services.NewGlobalService().ListBackups(ctx, projectPath)
+	if err != nil {
+		printError(fmt.Errorf("failed to list backups: %w", err))
+		return err
+	}
+
+	if globalJSON {
+		return outputJSON(backups)
+	}
+
+	if len(backups) == 0 {
+		printInfo("No backups found")
+		return nil
+	}
+
+	fmt.Println(colorHeader(fmt.Sprintf("%-28s %-17s %-12s %-10s %-6s %s",
+		"ID", "CREATED", "TYPE", "SIZE", "FILES", "ENCRYPTED")))
+
+	for _, backup := range backups {
+		kind := "full"
+		if backup.Incremental {
+			kind = "incremental"
+		}
+		encrypted := "no"
+		if backup.Encrypted {
+			encrypted = "yes (" + backup.KeyID + ")"
+		}
+		fmt.Printf("%-28s %-17s %-12s %-10s %-6d %s\n",
+			backup.ID, backup.CreatedAt.Format("2006-01-02 15:04"), kind,
+			entities.FormatBytes(backup.Size), len(backup.Files), encrypted)
+	}
+
+	return nil
+}
+
+func runGlobalBackupVerify(cmd *cobra.Command, backupID string) error {
+	ctx := context.Background()
+	globalService := services.NewGlobalService()
+
+	if backupID == "" {
+		repoPath, err := findGitRoot()
+		if err != nil {
+			printError(fmt.Errorf("not in a git repository: %w", err))
+			return err
+		}
+		backups, err := globalService.ListBackups(ctx, &repoPath)
+		if err != nil {
+			printError(fmt.Errorf("failed to list backups: %w", err))
+			return err
+		}
+		if len(backups) == 0 {
+			err := fmt.Errorf("no backups found for this project")
+			printError(err)
+			return err
+		}
+		backupID = backups[0].ID
+	}
+
+	result, err := globalService.VerifyBackup(ctx, backupID)
+	if err != nil {
+		printError(fmt.Errorf("failed to verify backup: %w", err))
+		return err
+	}
+
+	if globalJSON {
+		if err := outputJSON(result); err != nil {
+			return err
+		}
+	} else {
+		displayBackupVerifyResult(result)
+	}
+
+	if !result.Valid {
+		return fmt.Errorf("backup %s failed verification", backupID)
+	}
+	return nil
+}
+
+func displayBackupVerifyResult(result *entities.BackupVerifyResult) {
+	fmt.Printf("%s %s\n", colorLabel("Backup:"), colorValue(result.BackupID))
+	fmt.Printf("%s %d\n", colorLabel("Files checked:"), result.FilesChecked)
+
+	lists := []struct {
+		title string
+		paths []string
+	}{
+		{"Missing from backup", result.Missing},
+		{"Corrupted in backup", result.Corrupted},
+		{"Added to project since backup", result.Added},
+		{"Modified in project since backup", result.Modified},
+		{"Removed from project since backup", result.Removed},
+	}
+	for _, list := range lists {
+		if len(list.paths) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d):\n", colorHeader(list.title), len(list.paths))
+		for _, path := range list.paths {
+			fmt.Printf("  %s\n", path)
+		}
+	}
+
+	for _, e := range result.Errors {
+		printError(fmt.Errorf("%s", e))
+	}
+
+	fmt.Println()
+	switch {
+	case !result.Valid:
+		printError(fmt.Errorf("backup %s failed verification", result.BackupID))
+	case result.InSync():
+		printSuccess("Backup restored cleanly and matches the project")
+	default:
+		printSuccess("Backup restored cleanly")
+		printWarning("The project has changed since this backup was taken")
+	}
+}
+
+func runGlobalBackupRestore(cmd *cobra.Command, backupID string) error {
+	ctx := context.Background()
+
+	target, err := filepath.Abs(globalRestoreTarget)
+	if err != nil {
+		printError(fmt.Errorf("invalid restore target: %w", err))
+		return err
+	}
+
+	if err := services.NewGlobalService().RestoreBackup(ctx, backupID, target); err != nil {
+		printError(fmt.Errorf("restore failed: %w", err))
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Restored backup %s to %s", backupID, target))
+	return nil
+}
+
+func runGlobalBackupKey(cmd *cobra.Command) error {
+	ctx := context.Background()
+	globalService := services.NewGlobalService()
+
+	if globalKeyEnable || globalKeyDisable {
+		if _, err := globalService.SetBackupEncryption(ctx, globalKeyEnable); err != nil {
+			printError(fmt.Errorf("failed to update backup encryption: %w", err))
+			return err
+		}
+	}
+
+	if globalKeyRotate {
+		keyID, err := globalService.RotateBackupKey(ctx)
+		if err != nil {
+			printError(fmt.Errorf("failed to rotate backup key: %w", err))
+			return err
+		}
+		if !globalJSON {
+			printSuccess(fmt.Sprintf("New backups will be encrypted with key %s", keyID))
+		}
+	}
+
+	config, err := globalService.GetGlobalConfig(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to load global config: %w", err))
+		return err
+	}
+	settings := config.GlobalSettings
+
+	if globalJSON {
+		return outputJSON(map[string]interface{}{
+			"encryption_enabled": settings.EncryptionEnabled,
+			"key_id":             settings.BackupKeyID,
+			"keys_path":          entities.GetBackupKeysPath(),
+		})
+	}
+
+	keyID := settings.BackupKeyID
+	if keyID == "" {
+		keyID = "none (generated when encryption is first used)"
+	}
+	fmt.Printf("%s %v\n", colorLabel("Encryption enabled:"), settings.EncryptionEnabled)
+	fmt.Printf("%s %s\n", colorLabel("Active key:"), colorValue(keyID))
+	fmt.Printf("%s %s\n", colorLabel("Key directory:"), colorValue(entities.GetBackupKeysPath()))
+	return nil
+}
@@ -2,8 +2,12 @@ package cmd
 
 import (
 	"context"
+	"path/filepath"
 
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
 	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
 )
 
 // getCurrentUser returns the current user from git config or falls back to unknown
@@ -11,6 +15,7 @@ func getCurrentUser(gitRepo *git.GitClient) string {
 	ctx := context.Background()
 	if gitRepo != nil {
 		if author, err := gitRepo.GetAuthorInfo(ctx); err == nil && author != nil {
+			author = canonicalUser(author)
 			if author.Username != "" {
 				return author.Username
 			}
@@ -21,3 +26,18 @@ func getCurrentUser(gitRepo *git.GitClient) string {
 	}
 	return "unknown"
 }
+
+// canonicalUser resolves a user to their handle in the user directory of
+// the current repository, returning them unchanged when they are not in it
+func canonicalUser(user *entities.User) *entities.User {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return user
+	}
+	configRepo := storage.NewFileConfigRepository(filepath.Join(repoPath, app.ConfigDirName))
+	config, err := configRepo.Load(context.Background())
+	if err != nil {
+		return user
+	}
+	return config.UserDirectory().CanonicalUser(user)
+}
@@ -337,6 +337,8 @@ func getChangeTypeIcon(changeType entities.ChangeType) string {
 			return "[LK]"
 		case entities.ChangeTypeUnlinked:
 			return "[-LK]"
+		case entities.ChangeTypeReverted:
+			return "[R]"
 		default:
 			return "[E]"
 		}
@@ -368,6 +370,8 @@ func getChangeTypeIcon(changeType entities.ChangeType) string {
 			return color.GreenString("L")
 		case entities.ChangeTypeUnlinked:
 			return color.HiBlackString("L")
+		case entities.ChangeTypeReverted:
+			return color.YellowString("↺")
 		default:
 			return color.RedString("!")
 		}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var historyDiffJSON bool
+
+// historyDiffCmd represents the history diff command
+var historyDiffCmd = &cobra.Command{
+	Use:   "diff <issue-id> <from-version> <to-version>",
+	Short: "Show the changes between two versions of an issue",
+	Long: `Reconstruct an issue at two history versions and show how its tracked
+fields (title, description, type, status, priority, assignee, milestone,
+labels and branch) differ between them.
+
+Examples:
+  issuemap history diff ISSUE-001 v3 v7
+  issuemap history diff 001 1 5 --json`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runHistoryDiff(cmd, args)
+	},
+}
+
+func init() {
+	historyCmd.AddCommand(historyDiffCmd)
+
+	historyDiffCmd.Flags().BoolVar(&historyDiffJSON, "json", false, "output in JSON format")
+}
+
+func runHistoryDiff(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	issueID := normalizeIssueID(args[0])
+
+	fromVersion, err := parseHistoryVersion(args[1])
+	if err != nil {
+		printError(err)
+		return err
+	}
+	toVersion, err := parseHistoryVersion(args[2])
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	issueService, err := newVersionedIssueService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	changes, err := issueService.DiffIssueVersions(ctx, issueID, fromVersion, toVersion)
+	if err != nil {
+		printError(fmt.Errorf("failed to diff versions: %w", err))
+		return err
+	}
+
+	if historyDiffJSON {
+		data, err := json.MarshalIndent(changes, "", "  ")
+		if err != nil {
+			printError(fmt.Errorf("failed to marshal JSON: %w", err))
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("%s v%d → v%d\n", colorIssueID(issueID), fromVersion, toVersion)
+	if len(changes) == 0 {
+		printInfo("No differences")
+		return nil
+	}
+	displayFieldChanges(changes)
+	return nil
+}
+
+// displayFieldChanges prints field changes as old → new lines
+func displayFieldChanges(changes []entities.FieldChange) {
+	for _, change := range changes {
+		oldVal := formatValue(change.OldValue)
+		newVal := formatValue(change.NewValue)
+		if noColor {
+			fmt.Printf("   - %s: %s → %s\n", change.Field, oldVal, newVal)
+			continue
+		}
+		fmt.Printf("   %s: %s → %s\n",
+			colorLabel(change.Field),
+			color.RedString(oldVal),
+			color.GreenString(newVal))
+	}
+}
+
+// parseHistoryVersion parses a history version such as "v5" or "5"
+func parseHistoryVersion(value string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(value), "v"))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid version %q: expected a positive number such as v5", value)
+	}
+	return version, nil
+}
+
+// newVersionedIssueService creates an issue service for history replay commands
+func newVersionedIssueService() (*services.IssueService, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(issuemapPath)
+	configRepo := storage.NewFileConfigRepository(issuemapPath)
+
+	if gitClient, err := git.NewGitClient(repoPath); err == nil {
+		return services.NewIssueService(issueRepo, configRepo, gitClient), nil
+	}
+	return services.NewIssueService(issueRepo, configRepo, nil), nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	historyVerifyStrict bool
+	historyVerifyJSON   bool
+)
+
+// historyVerifyCmd represents the history verify command
+var historyVerifyCmd = &cobra.Command{
+	Use:   "verify [issue-id]",
+	Short: "Verify the integrity of issue history",
+	Long: `Verify the tamper-evident hash chain of issue history.
+
+Every history entry carries a hash of its content and of the previous entry,
+so edited, reordered, removed or inserted entries are detected. Signed entries
+and checkpoints are verified with git's signing configuration (gpg.format,
+gpg.ssh.allowedSignersFile).
+
+Entries recorded before hash chaining was introduced are reported as unsealed;
+use --strict to treat them as failures. Once an issue's history is sealed, an
+entry without a hash always fails verification.
+
+To sign new entries with your commit signing key:
+  git config issuemap.signHistory true
+
+Examples:
+  issuemap history verify                  # Verify all issues
+  issuemap history verify ISSUE-001        # Verify a single issue
+  issuemap history verify --strict --json  # Fail on unsealed entries, JSON output`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runHistoryVerify(cmd, args)
+	},
+}
+
+func init() {
+	historyCmd.AddCommand(historyVerifyCmd)
+
+	historyVerifyCmd.Flags().BoolVar(&historyVerifyStrict, "strict", false, "treat unsealed (pre-chain) entries as failures")
+	historyVerifyCmd.Flags().BoolVar(&historyVerifyJSON, "json", false, "output in JSON format")
+}
+
+func runHistoryVerify(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	historyRepo := storage.NewFileHistoryRepository(issuemapPath)
+
+	var historyService *services.HistoryService
+	if gitClient, err := git.NewGitClient(repoPath); err == nil {
+		historyService = services.NewHistoryService(historyRepo, gitClient)
+	} else {
+		historyService = services.NewHistoryService(historyRepo, nil)
+	}
+
+	var results []*entities.HistoryVerification
+	if len(args) == 1 {
+		result, err := historyService.VerifyIssueHistory(ctx, normalizeIssueID(args[0]))
+		if err != nil {
+			printError(fmt.Errorf("failed to verify history: %w", err))
+			return err
+		}
+		results = append(results, result)
+	} else {
+		results, err = historyService.VerifyAllHistory(ctx)
+		if err != nil {
+			printError(fmt.Errorf("failed to verify history: %w", err))
+			return err
+		}
+	}
+
+	failed := 0
+	for _, result := range results {
+		if !result.Valid() || (historyVerifyStrict && result.Unsealed > 0) {
+			failed++
+		}
+	}
+
+	if historyVerifyJSON {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			printError(fmt.Errorf("failed to marshal JSON: %w", err))
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		displayHistoryVerification(results)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("history verification failed for %d issue(s)", failed)
+	}
+	return nil
+}
+
+func displayHistoryVerification(results []*entities.HistoryVerification) {
+	failed := 0
+	unsealed := 0
+
+	for _, result := range results {
+		unsealed += result.Unsealed
+
+		status := color.GreenString("OK")
+		if noColor {
+			status = "OK"
+		}
+		if !result.Valid() {
+			failed++
+			status = color.RedString("FAILED")
+			if noColor {
+				status = "FAILED"
+			}
+		}
+
+		details := fmt.Sprintf("%d entries, %d sealed, %d signed", result.Entries, result.Sealed, result.Signed)
+		if result.Checkpoints > 0 {
+			details += fmt.Sprintf(", %d checkpoints", result.Checkpoints)
+		}
+		if result.Unsealed > 0 {
+			details += fmt.Sprintf(", %d unsealed", result.Unsealed)
+		}
+		fmt.Printf("%-8s %s (%s)\n", status, colorIssueID(result.IssueID), details)
+
+		for _, problem := range result.Problems {
+			fmt.Printf("         - v%d %s: %s\n", problem.Version, problem.Kind, problem.Message)
+		}
+		if len(result.Signers) > 0 {
+			fmt.Printf("         signed by: %v\n", result.Signers)
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		printError(fmt.Errorf("%d of %d issue histories failed verification", failed, len(results)))
+		return
+	}
+	printSuccess(fmt.Sprintf("Verified %d issue histories", len(results)))
+	if unsealed > 0 {
+		printWarning(fmt.Sprintf("%d entries predate hash chaining and are unsealed", unsealed))
+	}
+}
@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	jobsJSON         bool
+	jobsHistoryLimit int
+)
+
+// jobsCmd represents the jobs command
+var jobsCmd = &cobra.Command{
+	Use:   "jobs",
+	Short: "List, run and inspect scheduled background jobs",
+	Long: `The server runs background jobs such as cleanup, archiving, batch
+compression, archive index rebuilds and notification digests on cron
+schedules. Schedules accept five cron fields with
+ranges, steps, lists and names (e.g. "*/15 9-17 * * mon-fri") or the macros
+@hourly, @daily, @weekly, @monthly and @yearly.
+
+Schedules can be overridden and jobs disabled in the config:
+
+  jobs:
+    schedules:
+      cleanup: "0 4 * * sun"
+      archive: "@weekly"
+    disabled: [size-check]
+    catch_up: true       # run missed jobs once when the server starts
+    history_limit: 500
+
+The digest job emails people in the user directory their unread
+notifications once a mail server is configured:
+
+  digest:
+    enabled: true
+    from: issuemap@example.com
+    smtp:
+      host: smtp.example.com
+      port: 587
+      username: issuemap   # password from ISSUEMAP_SMTP_PASSWORD
+
+Examples:
+  issuemap jobs list
+  issuemap jobs run cleanup
+  issuemap jobs history archive --limit 5`,
+}
+
+// jobsListCmd lists registered jobs
+var jobsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List jobs with their schedule, last result and next run",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runJobsList(cmd)
+	},
+}
+
+// jobsRunCmd runs a job immediately
+var jobsRunCmd = &cobra.Command{
+	Use:   "run <job>",
+	Short: "Run a job now, even if it is disabled",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runJobsRun(cmd, args[0])
+	},
+}
+
+// jobsHistoryCmd shows recent job runs
+var jobsHistoryCmd = &cobra.Command{
+	Use:   "history [job]",
+	Short: "Show recent job runs",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		return runJobsHistory(cmd, name)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(jobsCmd)
+	jobsCmd.AddCommand(jobsListCmd)
+	jobsCmd.AddCommand(jobsRunCmd)
+	jobsCmd.AddCommand(jobsHistoryCmd)
+
+	jobsCmd.PersistentFlags().BoolVar(&jobsJSON, "json", false, "output as JSON")
+	jobsHistoryCmd.Flags().IntVarP(&jobsHistoryLimit, "limit", "n", 20, "number of runs to show (0 for all)")
+}
+
+// newJobScheduler creates a scheduler with the same jobs the server registers
+func newJobScheduler() (*services.SchedulerService, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+	attachmentRepo := storage.NewFileAttachmentRepository(basePath)
+
+	storageService := services.NewStorageService(basePath, configRepo, issueRepo, attachmentRepo)
+	cleanupService := services.NewCleanupService(basePath, configRepo, issueRepo, attachmentRepo)
+	archiveService := services.NewArchiveService(basePath, issueRepo, configRepo, attachmentRepo)
+	compressionService := services.NewCompressionService(basePath, configRepo, attachmentRepo)
+	storageService.SetArchiveService(archiveService)
+	storageService.SetCompressionService(compressionService)
+
+	attachmentService := services.NewAttachmentService(attachmentRepo, issueRepo, storageService, basePath)
+	attachmentService.SetCompressionService(compressionService)
+	uploadService := services.NewUploadService(basePath, attachmentService)
+
+	scheduler := services.NewSchedulerService(storage.NewFileJobRepository(basePath), configRepo)
+	scheduler.RegisterJobs(cleanupService.ScheduledJobs(storageService)...)
+	scheduler.RegisterJobs(archiveService.ScheduledJobs()...)
+	scheduler.RegisterJobs(compressionService.ScheduledJobs()...)
+	scheduler.RegisterJobs(uploadService.ScheduledJobs()...)
+
+	historyService := services.NewHistoryService(storage.NewFileHistoryRepository(basePath), nil)
+	staleService := services.NewStaleService(issueRepo, configRepo, historyService)
+	scheduler.RegisterJobs(staleService.ScheduledJobs()...)
+
+	digestService := services.NewDigestService(storage.NewFileNotificationRepository(basePath), configRepo)
+	scheduler.RegisterJobs(digestService.ScheduledJobs()...)
+
+	return scheduler, nil
+}
+
+func runJobsList(cmd *cobra.Command) error {
+	scheduler, err := newJobScheduler()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	jobs := scheduler.ListJobs(context.Background())
+	if jobsJSON {
+		return outputJSON(jobs)
+	}
+
+	fmt.Println(colorHeader(fmt.Sprintf("%-14s %-16s %-8s %-18s %-18s %s",
+		"JOB", "SCHEDULE", "RESULT", "LAST RUN", "NEXT RUN", "DESCRIPTION")))
+
+	for _, job := range jobs {
+		lastRun, nextRun := "never", "disabled"
+		if job.State.LastRun != nil {
+			lastRun = formatRelativeTime(*job.State.LastRun)
+		}
+		if job.State.NextRun != nil && !job.State.NextRun.IsZero() {
+			nextRun = job.State.NextRun.Format("Jan 2 15:04")
+		}
+
+		fmt.Printf("%-14s %-16s %s %-18s %-18s %s\n",
+			job.Name, job.Schedule, colorJobResult(job.State.LastResult, job.Running),
+			lastRun, nextRun, colorLabel(job.Description))
+	}
+
+	return nil
+}
+
+func runJobsRun(cmd *cobra.Command, name string) error {
+	scheduler, err := newJobScheduler()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	if !jobsJSON {
+		printInfo(fmt.Sprintf("Running job %s...", name))
+	}
+
+	run, err := scheduler.RunJob(context.Background(), name)
+	if err != nil {
+		printError(fmt.Errorf("failed to run job %s: %w", name, err))
+		return err
+	}
+
+	if jobsJSON {
+		return outputJSON(run)
+	}
+
+	if run.Result == entities.JobResultFailed {
+		err := fmt.Errorf("job %s failed after %s: %s", name, run.Duration().Round(time.Millisecond), run.Error)
+		printError(err)
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Job %s completed in %s: %s", name, run.Duration().Round(time.Millisecond), run.Message))
+	return nil
+}
+
+func runJobsHistory(cmd *cobra.Command, name string) error {
+	scheduler, err := newJobScheduler()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	runs, err := scheduler.History(context.Background(), name, jobsHistoryLimit)
+	if err != nil {
+		printError(fmt.Errorf("failed to load job history: %w", err))
+		return err
+	}
+
+	if jobsJSON {
+		if runs == nil {
+			runs = []*entities.JobRun{}
+		}
+		return outputJSON(runs)
+	}
+
+	if len(runs) == 0 {
+		printInfo("No job runs recorded")
+		return nil
+	}
+
+	fmt.Println(colorHeader(fmt.Sprintf("%-20s %-14s %-10s %-8s %-10s %s",
+		"STARTED", "JOB", "TRIGGER", "RESULT", "DURATION", "MESSAGE")))
+
+	for _, run := range runs {
+		message := run.Message
+		if run.Error != "" {
+			message = run.Error
+		}
+		fmt.Printf("%-20s %-14s %-10s %s %-10s %s\n",
+			run.StartedAt.Format("2006-01-02 15:04:05"), run.Job, run.Trigger,
+			colorJobResult(run.Result, false), run.Duration().Round(time.Millisecond), message)
+	}
+
+	return nil
+}
+
+// colorJobResult formats a job result padded to the result column width
+func colorJobResult(result entities.JobResult, running bool) string {
+	text := string(result)
+	if running {
+		text = "running"
+	} else if text == "" {
+		text = "-"
+	}
+	text = fmt.Sprintf("%-8s", text)
+
+	if noColor {
+		return text
+	}
+	switch {
+	case running:
+		return color.CyanString(text)
+	case result == entities.JobResultSuccess:
+		return color.GreenString(text)
+	case result == entities.JobResultFailed:
+		return color.RedString(text)
+	}
+	return text
+}
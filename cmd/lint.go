@@ -3,16 +3,16 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/ooyeku/issuemap/internal/app"
 	"github.com/ooyeku/issuemap/internal/app/services"
 	"github.com/ooyeku/issuemap/internal/domain/entities"
-	"github.com/ooyeku/issuemap/internal/domain/repositories"
 	"github.com/ooyeku/issuemap/internal/infrastructure/git"
 	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
 )
@@ -24,6 +24,10 @@ var (
 	lintRules     []string
 	lintSkipRules []string
 	lintOutput    string
+	lintFailOn    string
+	lintMinScore  int
+	lintConfig    string
+	lintInitForce bool
 )
 
 // lintCmd represents the lint command
@@ -32,22 +36,35 @@ var lintCmd = &cobra.Command{
 	Short: "Check issues for quality and completeness",
 	Long: `Lint issues to check for missing fields, quality standards, and best practices.
 
-Checks include:
-- Required fields (title, description, type)
-- Title quality (length, format, clarity)
-- Description completeness
-- Status consistency  
-- Priority assignment
-- Time tracking completeness
-- Label and milestone usage
-- Assignee validation
+Rules are read from .issuemap/lint.yaml. Without that file the built-in rules
+check required fields, title and description quality, stale in-progress work,
+time tracking and label and milestone usage. Run 'lint init' to write the
+built-in rules to lint.yaml as a starting point.
+
+Each rule checks one field:
+  required   the field must be set
+  length     min/max characters (or items for labels and comments), min_words
+  pattern    values must match a regular expression
+  forbidden  values must not match a regular expression
+  allowed    values must be in a list, e.g. an allowed label set
+  age        created/updated must be within max_days
+  overrun    actual_hours must stay within ratio x estimated_hours
+
+Rules can be limited to issue types, statuses, priorities or labels with
+'when', and may declare a safe fix (trim, capitalize, set, remove) that --fix
+applies and records in the issue history.
+
+Use --fail-on or --min-score (or fail_on / min_score in lint.yaml) to exit
+non-zero in CI, and --output sarif for code scanning integrations.
 
 Examples:
   issuemap lint ISSUE-001                    # Lint specific issue
   issuemap lint --all                        # Lint all issues
-  ismp lint --severity error                 # Only show errors
-  issuemap lint --rules title,description    # Only check specific rules
-  issuemap lint --fix ISSUE-001              # Show suggested fixes`,
+  ismp lint --all --severity error           # Only show errors
+  issuemap lint --rules title-required       # Only check specific rules
+  issuemap lint --fix ISSUE-001              # Apply safe fixes
+  issuemap lint --all --fail-on warning      # Fail CI on warnings or errors
+  issuemap lint --all -o sarif > lint.sarif  # SARIF report for CI`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		if !lintAll && len(args) == 0 {
 			return fmt.Errorf("must specify an issue ID or use --all flag")
@@ -58,6 +75,8 @@ Examples:
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// A failed threshold is a lint result, not a usage error
+		cmd.SilenceUsage = true
 		if lintAll {
 			return runLintAll(cmd)
 		}
@@ -65,51 +84,50 @@ Examples:
 	},
 }
 
+// lintInitCmd writes the built-in rules to lint.yaml
+var lintInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write the built-in lint rules to .issuemap/lint.yaml",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLintInit(cmd)
+	},
+}
+
+// lintRulesCmd lists the rules lint will check
+var lintRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List the active lint rules",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLintRules(cmd)
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(lintCmd)
+	lintCmd.AddCommand(lintInitCmd)
+	lintCmd.AddCommand(lintRulesCmd)
+
 	lintCmd.Flags().BoolVar(&lintAll, "all", false, "lint all issues")
-	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "show suggested fixes")
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "apply safe fixes")
 	lintCmd.Flags().StringVar(&lintSeverity, "severity", "all", "minimum severity to show (info, warning, error, all)")
 	lintCmd.Flags().StringSliceVar(&lintRules, "rules", []string{}, "specific rules to check (comma-separated)")
 	lintCmd.Flags().StringSliceVar(&lintSkipRules, "skip-rules", []string{}, "rules to skip (comma-separated)")
-	lintCmd.Flags().StringVarP(&lintOutput, "output", "o", "text", "output format (text, json)")
-}
-
-type LintResult struct {
-	IssueID    entities.IssueID `json:"issue_id"`
-	Title      string           `json:"title"`
-	Violations []LintViolation  `json:"violations"`
-	Score      int              `json:"score"` // 0-100 quality score
-	Grade      string           `json:"grade"` // A, B, C, D, F
-}
+	lintCmd.PersistentFlags().StringVarP(&lintOutput, "output", "o", "text", "output format (text, json, sarif)")
+	lintCmd.Flags().StringVar(&lintFailOn, "fail-on", "", "exit non-zero on violations at or above this severity (info, warning, error, none)")
+	lintCmd.Flags().IntVar(&lintMinScore, "min-score", 0, "exit non-zero when the average score is below this value")
+	lintCmd.PersistentFlags().StringVar(&lintConfig, "config", "", "lint rule file (default .issuemap/lint.yaml)")
 
-type LintViolation struct {
-	Rule        string `json:"rule"`
-	Severity    string `json:"severity"` // info, warning, error
-	Message     string `json:"message"`
-	Field       string `json:"field"`
-	Suggestion  string `json:"suggestion,omitempty"`
-	AutoFixable bool   `json:"auto_fixable"`
+	lintInitCmd.Flags().BoolVar(&lintInitForce, "force", false, "overwrite an existing lint.yaml")
 }
 
-type LintSummary struct {
-	TotalIssues          int            `json:"total_issues"`
-	IssuesChecked        int            `json:"issues_checked"`
-	TotalViolations      int            `json:"total_violations"`
-	ViolationsBySeverity map[string]int `json:"violations_by_severity"`
-	ViolationsByRule     map[string]int `json:"violations_by_rule"`
-	AverageScore         float64        `json:"average_score"`
-	Results              []LintResult   `json:"results"`
-}
-
-func runLintAll(cmd *cobra.Command) error {
-	ctx := context.Background()
-
-	// Initialize services
+// newLintService loads the project's lint rules and creates the service
+func newLintService() (*services.LintService, error) {
 	repoPath, err := findGitRoot()
 	if err != nil {
 		printError(fmt.Errorf("not in a git repository: %w", err))
-		return err
+		return nil, err
 	}
 
 	basePath := filepath.Join(repoPath, ".issuemap")
@@ -118,538 +136,219 @@ func runLintAll(cmd *cobra.Command) error {
 	gitClient, err := git.NewGitClient(repoPath)
 	if err != nil {
 		printError(fmt.Errorf("failed to initialize git client: %w", err))
-		return err
+		return nil, err
 	}
-
 	issueService := services.NewIssueService(issueRepo, configRepo, gitClient)
 
-	// Get all issues
-	issueList, err := issueService.ListIssues(ctx, repositories.IssueFilter{})
+	configPath := filepath.Join(basePath, services.LintConfigFile)
+	if lintConfig != "" {
+		configPath = lintConfig
+		if _, err := os.Stat(configPath); err != nil {
+			printError(fmt.Errorf("lint config not found: %w", err))
+			return nil, err
+		}
+	}
+	config, err := services.LoadLintConfig(configPath)
 	if err != nil {
-		printError(fmt.Errorf("failed to list issues: %w", err))
-		return err
+		printError(fmt.Errorf("failed to load lint rules: %w", err))
+		return nil, err
 	}
 
-	if len(issueList.Issues) == 0 {
-		printInfo("No issues found.")
-		return nil
+	severity := lintSeverity
+	if severity == "all" {
+		severity = ""
 	}
-
-	// Lint all issues
-	var results []LintResult
-	for _, issue := range issueList.Issues {
-		result := lintIssue(&issue)
-		if shouldShowResult(result) {
-			results = append(results, result)
-		}
+	lintService, err := services.NewLintService(issueService, config, services.LintOptions{
+		Only:        lintRules,
+		Skip:        lintSkipRules,
+		MinSeverity: severity,
+	})
+	if err != nil {
+		printError(fmt.Errorf("invalid lint rules: %w", err))
+		return nil, err
 	}
-
-	// Create summary
-	summary := createLintSummary(results, len(issueList.Issues))
-
-	// Display results
-	displayLintSummary(summary)
-
-	return nil
+	return lintService, nil
 }
 
-func runLintIssue(cmd *cobra.Command, issueIDStr string) error {
+func runLintAll(cmd *cobra.Command) error {
 	ctx := context.Background()
-	issueID := normalizeIssueID(issueIDStr)
 
-	// Initialize services
-	repoPath, err := findGitRoot()
-	if err != nil {
-		printError(fmt.Errorf("not in a git repository: %w", err))
-		return err
-	}
-
-	basePath := filepath.Join(repoPath, ".issuemap")
-	issueRepo := storage.NewFileIssueRepository(basePath)
-	configRepo := storage.NewFileConfigRepository(basePath)
-	gitClient, err := git.NewGitClient(repoPath)
+	lintService, err := newLintService()
 	if err != nil {
-		printError(fmt.Errorf("failed to initialize git client: %w", err))
 		return err
 	}
 
-	issueService := services.NewIssueService(issueRepo, configRepo, gitClient)
-
-	// Get the issue
-	issue, err := issueService.GetIssue(ctx, issueID)
+	summary, err := lintService.LintAll(ctx, lintFix)
 	if err != nil {
-		printError(fmt.Errorf("issue %s not found", issueID))
+		printError(fmt.Errorf("failed to lint issues: %w", err))
 		return err
 	}
 
-	// Lint the issue
-	result := lintIssue(issue)
-
-	// Display result
-	displayLintResult(result)
-
-	return nil
-}
-
-func lintIssue(issue *entities.Issue) LintResult {
-	result := LintResult{
-		IssueID:    issue.ID,
-		Title:      issue.Title,
-		Violations: []LintViolation{},
-	}
-
-	// Define all lint rules
-	rules := []func(*entities.Issue) []LintViolation{
-		checkTitle,
-		checkDescription,
-		checkType,
-		checkStatus,
-		checkPriority,
-		checkAssignee,
-		checkLabels,
-		checkMilestone,
-		checkTimeTracking,
-		checkBranch,
-		checkComments,
-		checkAge,
-	}
-
-	// Apply rules
-	for _, rule := range rules {
-		violations := rule(issue)
-		for _, violation := range violations {
-			if shouldIncludeRule(violation.Rule) && shouldIncludeSeverity(violation.Severity) {
-				result.Violations = append(result.Violations, violation)
-			}
+	if err := outputLint(lintService, summary, func() {
+		if summary.TotalIssues == 0 {
+			printInfo("No issues found.")
+			return
 		}
+		displayLintSummary(*summary)
+	}); err != nil {
+		return err
 	}
-
-	// Calculate score and grade
-	result.Score = calculateQualityScore(issue, result.Violations)
-	result.Grade = calculateGrade(result.Score)
-
-	return result
-}
-
-func checkTitle(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	title := strings.TrimSpace(issue.Title)
-
-	// Required field check
-	if title == "" {
-		violations = append(violations, LintViolation{
-			Rule:        "title-required",
-			Severity:    "error",
-			Message:     "Title is required",
-			Field:       "title",
-			Suggestion:  "Add a descriptive title for this issue",
-			AutoFixable: false,
-		})
-		return violations
-	}
-
-	// Length checks
-	if len(title) < 10 {
-		violations = append(violations, LintViolation{
-			Rule:        "title-too-short",
-			Severity:    "warning",
-			Message:     "Title is too short (less than 10 characters)",
-			Field:       "title",
-			Suggestion:  "Expand the title to be more descriptive",
-			AutoFixable: false,
-		})
-	}
-
-	if len(title) > 100 {
-		violations = append(violations, LintViolation{
-			Rule:        "title-too-long",
-			Severity:    "warning",
-			Message:     "Title is too long (more than 100 characters)",
-			Field:       "title",
-			Suggestion:  "Shorten the title while keeping it descriptive",
-			AutoFixable: false,
-		})
-	}
-
-	// Format checks
-	if title[0] != strings.ToUpper(title)[0] {
-		violations = append(violations, LintViolation{
-			Rule:        "title-capitalization",
-			Severity:    "info",
-			Message:     "Title should start with a capital letter",
-			Field:       "title",
-			Suggestion:  fmt.Sprintf("Change to: %s", strings.Title(title)),
-			AutoFixable: true,
-		})
-	}
-
-	// Check for common anti-patterns
-	lowTitle := strings.ToLower(title)
-	if strings.Contains(lowTitle, "fix") && strings.Contains(lowTitle, "bug") {
-		violations = append(violations, LintViolation{
-			Rule:        "title-redundant",
-			Severity:    "info",
-			Message:     "Title contains redundant words like 'fix' and 'bug'",
-			Field:       "title",
-			Suggestion:  "Consider removing redundant words or using the type field instead",
-			AutoFixable: false,
-		})
-	}
-
-	return violations
+	return checkLintThreshold(lintService, summary)
 }
 
-func checkDescription(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	description := strings.TrimSpace(issue.Description)
-
-	// Required field check
-	if description == "" {
-		violations = append(violations, LintViolation{
-			Rule:        "description-required",
-			Severity:    "warning",
-			Message:     "Description is missing",
-			Field:       "description",
-			Suggestion:  "Add a description explaining what this issue is about",
-			AutoFixable: false,
-		})
-		return violations
-	}
-
-	// Length checks
-	if len(description) < 20 {
-		violations = append(violations, LintViolation{
-			Rule:        "description-too-short",
-			Severity:    "info",
-			Message:     "Description is very short (less than 20 characters)",
-			Field:       "description",
-			Suggestion:  "Expand the description with more details",
-			AutoFixable: false,
-		})
-	}
+func runLintIssue(cmd *cobra.Command, issueIDStr string) error {
+	ctx := context.Background()
+	issueID := normalizeIssueID(issueIDStr)
 
-	// Quality checks
-	if !containsMultipleWords(description, 5) {
-		violations = append(violations, LintViolation{
-			Rule:        "description-too-simple",
-			Severity:    "info",
-			Message:     "Description seems too simple (less than 5 words)",
-			Field:       "description",
-			Suggestion:  "Add more context and details to the description",
-			AutoFixable: false,
-		})
+	lintService, err := newLintService()
+	if err != nil {
+		return err
 	}
 
-	return violations
-}
-
-func checkType(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	if issue.Type == "" {
-		violations = append(violations, LintViolation{
-			Rule:        "type-required",
-			Severity:    "error",
-			Message:     "Issue type is required",
-			Field:       "type",
-			Suggestion:  "Set type to one of: bug, feature, task, epic",
-			AutoFixable: false,
-		})
+	issue, err := lintService.IssueService().GetIssue(ctx, issueID)
+	if err != nil {
+		printError(fmt.Errorf("issue %s not found", issueID))
+		return err
 	}
 
-	return violations
-}
-
-func checkStatus(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	// Check for stale issues
-	if issue.Status == entities.StatusInProgress {
-		daysSinceUpdate := daysSince(issue.Timestamps.Updated)
-		if daysSinceUpdate > 14 {
-			violations = append(violations, LintViolation{
-				Rule:        "status-stale-in-progress",
-				Severity:    "warning",
-				Message:     fmt.Sprintf("Issue has been in-progress for %d days", daysSinceUpdate),
-				Field:       "status",
-				Suggestion:  "Update the status or add recent activity",
-				AutoFixable: false,
-			})
+	result := lintService.LintIssue(issue)
+	if lintFix {
+		if result, err = lintService.Fix(ctx, issue, result); err != nil {
+			printError(fmt.Errorf("failed to apply fixes: %w", err))
+			return err
 		}
 	}
 
-	return violations
-}
-
-func checkPriority(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	if issue.Priority == "" {
-		violations = append(violations, LintViolation{
-			Rule:        "priority-missing",
-			Severity:    "info",
-			Message:     "Priority is not set",
-			Field:       "priority",
-			Suggestion:  "Set priority to one of: low, medium, high, critical",
-			AutoFixable: false,
-		})
-	}
-
-	return violations
-}
-
-func checkAssignee(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	if issue.Assignee == nil && issue.Status == entities.StatusInProgress {
-		violations = append(violations, LintViolation{
-			Rule:        "assignee-missing-in-progress",
-			Severity:    "warning",
-			Message:     "In-progress issue should have an assignee",
-			Field:       "assignee",
-			Suggestion:  "Assign this issue to someone or change status",
-			AutoFixable: false,
-		})
-	}
-
-	return violations
-}
-
-func checkLabels(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	if len(issue.Labels) == 0 {
-		violations = append(violations, LintViolation{
-			Rule:        "labels-missing",
-			Severity:    "info",
-			Message:     "Issue has no labels",
-			Field:       "labels",
-			Suggestion:  "Add relevant labels for categorization",
-			AutoFixable: false,
-		})
+	summary := entities.NewLintSummary([]entities.LintResult{result}, 1)
+	if err := outputLint(lintService, &summary, func() {
+		displayLintResult(result)
+	}); err != nil {
+		return err
 	}
-
-	return violations
+	return checkLintThreshold(lintService, &summary)
 }
 
-func checkMilestone(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	if issue.Milestone == nil && issue.Priority == entities.PriorityHigh {
-		violations = append(violations, LintViolation{
-			Rule:        "milestone-missing-high-priority",
-			Severity:    "info",
-			Message:     "High priority issue should have a milestone",
-			Field:       "milestone",
-			Suggestion:  "Assign this issue to a milestone",
-			AutoFixable: false,
-		})
+// outputLint writes the results in the selected format; text output is
+// produced by displayText
+func outputLint(lintService *services.LintService, summary *entities.LintSummary, displayText func()) error {
+	switch lintOutput {
+	case "json":
+		if !lintAll && len(summary.Results) == 1 {
+			return outputJSON(summary.Results[0])
+		}
+		return outputJSON(summary)
+	case "sarif":
+		sarif := entities.NewLintSARIF(lintService.Rules(), summary.Results, app.GetVersion(), ".issuemap/issues")
+		return outputJSON(sarif)
+	case "text", "":
+		displayText()
+		return nil
+	default:
+		err := fmt.Errorf("unknown output format %q (use text, json or sarif)", lintOutput)
+		printError(err)
+		return err
 	}
-
-	return violations
 }
 
-func checkTimeTracking(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	hasEstimate := issue.Metadata.EstimatedHours != nil && *issue.Metadata.EstimatedHours > 0
-	hasActual := issue.Metadata.ActualHours != nil && *issue.Metadata.ActualHours > 0
-
-	if !hasEstimate && issue.Type != entities.IssueTypeEpic {
-		violations = append(violations, LintViolation{
-			Rule:        "estimate-missing",
-			Severity:    "info",
-			Message:     "Issue has no time estimate",
-			Field:       "estimated_hours",
-			Suggestion:  "Add a time estimate for better planning",
-			AutoFixable: false,
-		})
-	}
-
-	if hasActual && hasEstimate {
-		actual := *issue.Metadata.ActualHours
-		estimate := *issue.Metadata.EstimatedHours
-		if actual > estimate*1.5 {
-			violations = append(violations, LintViolation{
-				Rule:        "time-overrun",
-				Severity:    "warning",
-				Message:     fmt.Sprintf("Actual time (%.1fh) significantly exceeds estimate (%.1fh)", actual, estimate),
-				Field:       "actual_hours",
-				Suggestion:  "Review time estimates for future similar work",
-				AutoFixable: false,
-			})
+// checkLintThreshold fails the command when the results exceed the CI
+// thresholds. Flags override the values in lint.yaml.
+func checkLintThreshold(lintService *services.LintService, summary *entities.LintSummary) error {
+	config := lintService.Config()
+	failOn, minScore := config.FailOn, config.MinScore
+	if lintFailOn != "" {
+		failOn = lintFailOn
+		if failOn == "none" {
+			failOn = ""
+		} else if entities.LintSeverityRank(failOn) == 0 {
+			err := fmt.Errorf("--fail-on must be info, warning, error or none")
+			printError(err)
+			return err
 		}
 	}
-
-	return violations
-}
-
-func checkBranch(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	if issue.Branch == "" && issue.Status == entities.StatusInProgress {
-		violations = append(violations, LintViolation{
-			Rule:        "branch-missing-in-progress",
-			Severity:    "info",
-			Message:     "In-progress issue should have an associated branch",
-			Field:       "branch",
-			Suggestion:  "Create and link a branch for this issue",
-			AutoFixable: false,
-		})
+	if lintMinScore > 0 {
+		minScore = lintMinScore
 	}
 
-	return violations
-}
-
-func checkComments(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	daysSinceCreation := daysSince(issue.Timestamps.Created)
-	if len(issue.Comments) == 0 && daysSinceCreation > 7 && issue.Status != entities.StatusClosed {
-		violations = append(violations, LintViolation{
-			Rule:        "comments-missing-old-issue",
-			Severity:    "info",
-			Message:     fmt.Sprintf("Issue is %d days old with no comments", daysSinceCreation),
-			Field:       "comments",
-			Suggestion:  "Add status updates or progress comments",
-			AutoFixable: false,
-		})
+	if reason := summary.ThresholdExceeded(failOn, minScore); reason != "" {
+		return fmt.Errorf("lint failed: %s", reason)
 	}
-
-	return violations
+	return nil
 }
 
-func checkAge(issue *entities.Issue) []LintViolation {
-	var violations []LintViolation
-
-	daysSinceCreation := daysSince(issue.Timestamps.Created)
-	if daysSinceCreation > 90 && issue.Status == entities.StatusOpen {
-		violations = append(violations, LintViolation{
-			Rule:        "issue-very-old",
-			Severity:    "warning",
-			Message:     fmt.Sprintf("Issue has been open for %d days", daysSinceCreation),
-			Field:       "status",
-			Suggestion:  "Review if this issue is still relevant or close it",
-			AutoFixable: false,
-		})
+func runLintInit(cmd *cobra.Command) error {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
 	}
 
-	return violations
-}
-
-// Helper functions
-
-func shouldShowResult(result LintResult) bool {
-	if lintSeverity == "all" {
-		return len(result.Violations) > 0
+	path := filepath.Join(repoPath, ".issuemap", services.LintConfigFile)
+	if lintConfig != "" {
+		path = lintConfig
 	}
-
-	for _, violation := range result.Violations {
-		if shouldIncludeSeverity(violation.Severity) {
-			return true
-		}
+	if _, err := os.Stat(path); err == nil && !lintInitForce {
+		err := fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		printError(err)
+		return err
 	}
-	return false
-}
 
-func shouldIncludeRule(rule string) bool {
-	if len(lintRules) > 0 {
-		return contains(lintRules, rule)
-	}
-	if len(lintSkipRules) > 0 {
-		return !contains(lintSkipRules, rule)
+	config := entities.DefaultLintConfig()
+	config.FailOn = entities.LintSeverityError
+	if err := services.SaveLintConfig(path, config); err != nil {
+		printError(fmt.Errorf("failed to write lint rules: %w", err))
+		return err
 	}
-	return true
-}
 
-func shouldIncludeSeverity(severity string) bool {
-	severityOrder := map[string]int{"info": 1, "warning": 2, "error": 3}
-	if lintSeverity == "all" {
-		return true
-	}
-	return severityOrder[severity] >= severityOrder[lintSeverity]
+	printSuccess(fmt.Sprintf("Wrote %d lint rules to %s", len(config.Rules), path))
+	return nil
 }
 
-func calculateQualityScore(issue *entities.Issue, violations []LintViolation) int {
-	score := 100
-
-	for _, violation := range violations {
-		switch violation.Severity {
-		case "error":
-			score -= 15
-		case "warning":
-			score -= 10
-		case "info":
-			score -= 5
-		}
-	}
-
-	if score < 0 {
-		score = 0
+func runLintRules(cmd *cobra.Command) error {
+	lintService, err := newLintService()
+	if err != nil {
+		return err
 	}
-	return score
-}
-
-func calculateGrade(score int) string {
-	if score >= 90 {
-		return "A"
-	} else if score >= 80 {
-		return "B"
-	} else if score >= 70 {
-		return "C"
-	} else if score >= 60 {
-		return "D"
-	}
-	return "F"
-}
 
-func createLintSummary(results []LintResult, totalIssues int) LintSummary {
-	summary := LintSummary{
-		TotalIssues:          totalIssues,
-		IssuesChecked:        len(results),
-		ViolationsBySeverity: make(map[string]int),
-		ViolationsByRule:     make(map[string]int),
-		Results:              results,
+	rules := lintService.Rules()
+	if lintOutput == "json" {
+		return outputJSON(rules)
 	}
 
-	totalScore := 0
-	for _, result := range results {
-		totalScore += result.Score
-		summary.TotalViolations += len(result.Violations)
-
-		for _, violation := range result.Violations {
-			summary.ViolationsBySeverity[violation.Severity]++
-			summary.ViolationsByRule[violation.Rule]++
+	fmt.Printf("%-32s %-8s %-16s %-10s %s\n", "RULE", "SEVERITY", "FIELD", "CHECK", "FIX")
+	fmt.Println(strings.Repeat("─", 80))
+	for _, rule := range rules {
+		fix := ""
+		if rule.Fix != nil {
+			fix = rule.Fix.Action
 		}
+		fmt.Printf("%-32s %-8s %-16s %-10s %s\n", rule.ID, rule.Severity, rule.Field, rule.Check, fix)
 	}
-
-	if len(results) > 0 {
-		summary.AverageScore = float64(totalScore) / float64(len(results))
-	}
-
-	return summary
+	fmt.Printf("\n%d rules\n", len(rules))
+	return nil
 }
 
-func displayLintResult(result LintResult) {
+func displayLintResult(result entities.LintResult) {
 	fmt.Printf("Issue: %s - %s\n", result.IssueID, result.Title)
 	fmt.Printf("Score: %d/100 (Grade: %s)\n", result.Score, result.Grade)
 	fmt.Println(strings.Repeat("─", 50))
 
+	for _, rule := range result.Fixed {
+		printSuccess(fmt.Sprintf("Fixed [%s]", rule))
+	}
+
 	if len(result.Violations) == 0 {
 		printSuccess("No violations found! ✨")
 		return
 	}
 
 	// Group by severity
-	violationsBySeverity := make(map[string][]LintViolation)
+	violationsBySeverity := make(map[string][]entities.LintViolation)
 	for _, violation := range result.Violations {
 		violationsBySeverity[violation.Severity] = append(violationsBySeverity[violation.Severity], violation)
 	}
 
 	// Display in order: error, warning, info
-	severities := []string{"error", "warning", "info"}
+	severities := []string{entities.LintSeverityError, entities.LintSeverityWarning, entities.LintSeverityInfo}
 	for _, severity := range severities {
 		violations := violationsBySeverity[severity]
 		if len(violations) == 0 {
@@ -659,8 +358,12 @@ func displayLintResult(result LintResult) {
 		fmt.Printf("\n%s (%d):\n", strings.ToUpper(severity), len(violations))
 		for _, violation := range violations {
 			icon := getSeverityIcon(violation.Severity)
-			fmt.Printf("  %s [%s] %s\n", icon, violation.Rule, violation.Message)
-			if lintFix && violation.Suggestion != "" {
+			fixable := ""
+			if violation.AutoFixable {
+				fixable = " (fixable)"
+			}
+			fmt.Printf("  %s [%s] %s%s\n", icon, violation.Rule, violation.Message, fixable)
+			if violation.Suggestion != "" {
 				fmt.Printf("    💡 %s\n", violation.Suggestion)
 			}
 		}
@@ -668,18 +371,21 @@ func displayLintResult(result LintResult) {
 	fmt.Println()
 }
 
-func displayLintSummary(summary LintSummary) {
+func displayLintSummary(summary entities.LintSummary) {
 	fmt.Printf("Lint Summary\n")
 	fmt.Println(strings.Repeat("═", 50))
 
-	fmt.Printf("Issues checked: %d/%d\n", summary.IssuesChecked, summary.TotalIssues)
+	fmt.Printf("Issues checked: %d\n", summary.IssuesChecked)
 	fmt.Printf("Total violations: %d\n", summary.TotalViolations)
+	if summary.TotalFixed > 0 {
+		fmt.Printf("Fixes applied: %d\n", summary.TotalFixed)
+	}
 	fmt.Printf("Average score: %.1f/100\n\n", summary.AverageScore)
 
 	// Show violations by severity
 	if summary.TotalViolations > 0 {
 		fmt.Printf("Violations by severity:\n")
-		severities := []string{"error", "warning", "info"}
+		severities := []string{entities.LintSeverityError, entities.LintSeverityWarning, entities.LintSeverityInfo}
 		for _, severity := range severities {
 			count := summary.ViolationsBySeverity[severity]
 			if count > 0 {
@@ -704,7 +410,10 @@ func displayLintSummary(summary LintSummary) {
 		}
 
 		sort.Slice(rules, func(i, j int) bool {
-			return rules[i].count > rules[j].count
+			if rules[i].count != rules[j].count {
+				return rules[i].count > rules[j].count
+			}
+			return rules[i].rule < rules[j].rule
 		})
 
 		for i, rule := range rules {
@@ -717,9 +426,12 @@ func displayLintSummary(summary LintSummary) {
 	}
 
 	// Show issues with violations
-	if summary.IssuesChecked > 0 {
+	if len(summary.Results) > 0 {
 		fmt.Printf("Issues with violations:\n")
 		for _, result := range summary.Results {
+			if len(result.Fixed) > 0 {
+				fmt.Printf("  %s - fixed: %s\n", result.IssueID, strings.Join(result.Fixed, ", "))
+			}
 			if len(result.Violations) > 0 {
 				fmt.Printf("  %s - Score: %d (%s) - %d violations\n",
 					result.IssueID, result.Score, result.Grade, len(result.Violations))
@@ -750,16 +462,3 @@ func getSeverityIcon(severity string) string {
 	}
 	return "•"
 }
-
-// Utility functions
-
-func containsMultipleWords(text string, minWords int) bool {
-	words := strings.Fields(text)
-	return len(words) >= minWords
-}
-
-func daysSince(t time.Time) int {
-	return int(time.Since(t).Hours() / 24)
-}
-
-// contains function is defined in import.go
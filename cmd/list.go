@@ -30,6 +30,7 @@ var (
 	listAll        bool
 	listBlocked    bool
 	listNoTruncate bool
+	listWatching   bool
 )
 
 // listCmd represents the list command
@@ -43,6 +44,7 @@ Examples:
   issuemap list --status open
   issuemap list --type bug --priority high
   issuemap list --assignee username
+  issuemap list --watching
   issuemap list --labels bug,urgent`,
 	Aliases: []string{"ls"},
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -64,6 +66,7 @@ func init() {
 	listCmd.Flags().BoolVar(&listAll, "all", false, "show all issues (no limit)")
 	listCmd.Flags().BoolVar(&listBlocked, "blocked", false, "show only blocked issues")
 	listCmd.Flags().BoolVar(&listNoTruncate, "no-truncate", false, "disable text truncation for better readability")
+	listCmd.Flags().BoolVar(&listWatching, "watching", false, "show only issues you watch")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -123,6 +126,10 @@ func runList(cmd *cobra.Command, args []string) error {
 	if len(listLabels) > 0 {
 		filter.Labels = listLabels
 	}
+	if listWatching {
+		watcher := getCurrentUser(gitRepo)
+		filter.Watcher = &watcher
+	}
 	if !listAll {
 		filter.Limit = &listLimit
 	}
@@ -239,6 +246,10 @@ func displayIssuesTable(issues []entities.Issue) {
 		if issue.HasAttachments() {
 			idStr = idStr + " [+]"
 		}
+		// Add confidential indicator
+		if issue.Confidential {
+			idStr = idStr + " [c]"
+		}
 		id := truncateString(idStr, idWidth)
 		title := truncateString(issue.Title, titleWidth)
 		issueType := truncateString(string(issue.Type), typeWidth)
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+var (
+	migrateDryRun bool
+	migrateTo     int
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade or downgrade the .issuemap data format",
+	Long: `Move the .issuemap directory between data format versions.
+
+The format version of a repository is recorded in .issuemap/metadata/version;
+repositories without it predate versioning and are at version 0. Every format
+change ships as a numbered migration. Running a repository at a newer version
+than this issuemap supports is refused until issuemap is upgraded.
+
+Examples:
+  issuemap migrate status            # Show the format version and migrations
+  issuemap migrate up --dry-run      # List the files pending migrations change
+  issuemap migrate up                # Apply all pending migrations
+  issuemap migrate down --to 0       # Revert to the unversioned format`,
+}
+
+// migrateStatusCmd shows the repository's format version
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the format version and the applied and pending migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMigrateStatus(cmd)
+	},
+}
+
+// migrateUpCmd applies pending migrations
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply pending migrations",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runMigrateUp(cmd)
+	},
+}
+
+// migrateDownCmd reverts applied migrations
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert applied migrations",
+	Long: `Revert applied migrations down to the version given with --to, by default
+the version before the current one. Use it before handing a repository back to
+an older issuemap release.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runMigrateDown(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+
+	for _, c := range []*cobra.Command{migrateUpCmd, migrateDownCmd} {
+		c.Flags().BoolVar(&migrateDryRun, "dry-run", false, "show the files that would change without writing them")
+		c.Flags().IntVar(&migrateTo, "to", -1, "format version to migrate to")
+	}
+}
+
+// newFormatMigrationService returns the migration service of the current
+// repository
+func newFormatMigrationService() (*services.FormatMigrationService, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+	return services.NewFormatMigrationService(filepath.Join(repoPath, app.ConfigDirName)), nil
+}
+
+func runMigrateStatus(cmd *cobra.Command) error {
+	migrationService, err := newFormatMigrationService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	status, err := migrationService.Status(context.Background())
+	if err != nil {
+		printError(fmt.Errorf("failed to read format version: %w", err))
+		return err
+	}
+
+	if format == "json" {
+		return outputJSON(status)
+	}
+
+	fmt.Printf("Format version: %d (latest %d)\n\n", status.Version, status.Latest)
+	fmt.Printf("%-8s %-8s %s\n", "VERSION", "STATE", "DESCRIPTION")
+	for _, migration := range status.Migrations {
+		state := "pending"
+		if migration.Applied {
+			state = "applied"
+		}
+		fmt.Printf("%-8d %-8s %s\n", migration.Version, state, migration.Description)
+	}
+	fmt.Println()
+
+	switch {
+	case status.Version > status.Latest:
+		printWarning((&entities.FormatVersionError{Version: status.Version, Supported: status.Latest}).Error())
+	case status.Pending > 0:
+		printInfo(fmt.Sprintf("%d pending migration(s); run 'issuemap migrate up' to apply them", status.Pending))
+	default:
+		printSuccess("Repository is up to date")
+	}
+	return nil
+}
+
+func runMigrateUp(cmd *cobra.Command) error {
+	migrationService, err := newFormatMigrationService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	result, err := migrationService.Up(context.Background(), migrateTo, migrateDryRun)
+	if result != nil {
+		displayFormatMigrationResult(result)
+	}
+	if err != nil {
+		printError(err)
+		return err
+	}
+	return nil
+}
+
+func runMigrateDown(cmd *cobra.Command) error {
+	migrationService, err := newFormatMigrationService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	target := migrateTo
+	if !cmd.Flags().Changed("to") {
+		status, err := migrationService.Status(context.Background())
+		if err != nil {
+			printError(fmt.Errorf("failed to read format version: %w", err))
+			return err
+		}
+		target = status.Version - 1
+	}
+
+	result, err := migrationService.Down(context.Background(), target, migrateDryRun)
+	if result != nil {
+		displayFormatMigrationResult(result)
+	}
+	if err != nil {
+		printError(err)
+		return err
+	}
+	return nil
+}
+
+// displayFormatMigrationResult prints the migrations run and their changes
+func displayFormatMigrationResult(result *entities.FormatMigrationResult) {
+	if format == "json" {
+		_ = outputJSON(result)
+		return
+	}
+
+	for _, step := range result.Steps {
+		fmt.Printf("%s %d: %s\n", step.Direction, step.Version, step.Description)
+		for _, change := range step.Changes {
+			fmt.Printf("  %s\n", change)
+		}
+		if len(step.Changes) == 0 {
+			fmt.Println("  no files changed")
+		}
+	}
+
+	switch {
+	case len(result.Steps) == 0:
+		printInfo(fmt.Sprintf("Nothing to migrate; format version is %d", result.From))
+	case result.DryRun:
+		printInfo(fmt.Sprintf("Dry run: would migrate from format version %d to %d", result.From, result.To))
+	default:
+		printSuccess(fmt.Sprintf("Migrated from format version %d to %d", result.From, result.To))
+	}
+}
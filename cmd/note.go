@@ -89,8 +89,13 @@ func runNote(cmd *cobra.Command, issueID entities.IssueID, noteText string) erro
 		return err
 	}
 
-	// Record history entry for the note
-	err = historyService.RecordIssueCommented(ctx, issueID, noteText, author)
+	// Record history entry for the note; history is stored in clear, so
+	// notes on confidential issues are not copied into it
+	historyText := noteText
+	if issue.IsSealedField(entities.SealedFieldComments) {
+		historyText = entities.ConfidentialPlaceholder
+	}
+	err = historyService.RecordIssueCommented(ctx, issueID, historyText, author)
 	if err != nil {
 		// Don't fail the operation if history fails, just warn
 		printWarning(fmt.Sprintf("warning: failed to record history: %v", err))
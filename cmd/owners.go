@@ -0,0 +1,275 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	ownersAssignUnassigned bool
+	ownersAssignDryRun     bool
+)
+
+// ownersCmd groups the code ownership commands
+var ownersCmd = &cobra.Command{
+	Use:   "owners",
+	Short: "Suggest and set assignees from code ownership",
+	Long: `Find who owns an issue from the CODEOWNERS file and the owners section of the
+config, and assign it to them.
+
+Owners are found from the files mentioned in the issue description, in text
+attachments such as stack traces, and changed by the commits linked to the
+issue, matched against CODEOWNERS. Rules in the owners section of the config
+name the owners of issues with given labels or components (the "component"
+custom field). The owner with the most evidence wins.
+
+When a team owns an issue, a member is picked with the configured strategy:
+least_loaded (fewest open issues, the default) or round_robin.
+
+  owners:
+    auto_assign: true          # assign new issues created without an assignee
+    strategy: round_robin
+    teams:
+      "@acme/backend": [alice, bob]
+    rules:
+      - labels: [docs]
+        owners: [carol]`,
+}
+
+// ownersSuggestCmd shows the owners of an issue
+var ownersSuggestCmd = &cobra.Command{
+	Use:   "suggest <issue-id>",
+	Short: "Show who owns an issue and why",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runOwnersSuggest(cmd, args)
+	},
+}
+
+// ownersAssignCmd assigns issues to their owners
+var ownersAssignCmd = &cobra.Command{
+	Use:   "assign [issue-id...]",
+	Short: "Assign issues to their owners",
+	Long: `Assign issues to their owners. Issues that already have an assignee are
+reassigned only when named explicitly.
+
+Examples:
+  issuemap owners assign ISSUE-001
+  issuemap owners assign --unassigned           # All open issues without an assignee
+  issuemap owners assign --unassigned --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runOwnersAssign(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(ownersCmd)
+	ownersCmd.AddCommand(ownersSuggestCmd)
+	ownersCmd.AddCommand(ownersAssignCmd)
+
+	ownersAssignCmd.Flags().BoolVar(&ownersAssignUnassigned, "unassigned", false, "assign all open issues without an assignee")
+	ownersAssignCmd.Flags().BoolVar(&ownersAssignDryRun, "dry-run", false, "show the assignees without changing issues")
+}
+
+// newOwnershipService wires the ownership service for the repository
+func newOwnershipService(repoPath string) (*services.OwnershipService, repositories.IssueRepository) {
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+
+	var gitRepo repositories.GitRepository
+	if gitClient, err := git.NewGitClient(repoPath); err == nil {
+		gitRepo = gitClient
+	}
+
+	ownershipService := services.NewOwnershipService(issueRepo, configRepo, gitRepo, basePath)
+	attachmentRepo := storage.NewFileAttachmentRepository(basePath)
+	storageService := services.NewStorageService(basePath, configRepo, issueRepo, attachmentRepo)
+	ownershipService.SetAttachmentService(services.NewAttachmentService(attachmentRepo, issueRepo, storageService, basePath))
+	return ownershipService, issueRepo
+}
+
+func runOwnersSuggest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+	ownershipService, issueRepo := newOwnershipService(repoPath)
+
+	issueID := normalizeIssueID(args[0])
+	issue, err := issueRepo.GetByID(ctx, issueID)
+	if err != nil {
+		printError(fmt.Errorf("issue %s not found: %w", issueID, err))
+		return err
+	}
+
+	suggestion, err := ownershipService.Suggest(ctx, issue)
+	if err != nil {
+		printError(fmt.Errorf("failed to find owners: %w", err))
+		return err
+	}
+
+	if format == "json" {
+		return outputJSON(suggestion)
+	}
+
+	printSectionHeader(fmt.Sprintf("Owners of %s", issue.ID))
+	if len(suggestion.Candidates) == 0 {
+		printInfo("No owners found: no CODEOWNERS rule or owners rule matches this issue")
+		return nil
+	}
+	for _, candidate := range suggestion.Candidates {
+		kind := ""
+		if candidate.Team {
+			kind = " (team)"
+		}
+		fmt.Printf("  %s%s\n", candidate.Owner, kind)
+		for _, evidence := range candidate.Evidence {
+			fmt.Printf("      %s\n", describeOwnershipEvidence(evidence))
+		}
+	}
+	fmt.Println()
+	printOwnerSuggestion(suggestion)
+	return nil
+}
+
+func runOwnersAssign(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if len(args) == 0 && !ownersAssignUnassigned {
+		err := fmt.Errorf("name the issues to assign or use --unassigned")
+		printError(err)
+		return err
+	}
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+	ownershipService, issueRepo := newOwnershipService(repoPath)
+
+	var issueIDs []entities.IssueID
+	for _, arg := range args {
+		issueIDs = append(issueIDs, normalizeIssueID(arg))
+	}
+	if ownersAssignUnassigned {
+		list, err := issueRepo.List(ctx, repositories.IssueFilter{})
+		if err != nil {
+			printError(fmt.Errorf("failed to list issues: %w", err))
+			return err
+		}
+		for _, issue := range list.Issues {
+			if issue.Assignee == nil && issue.Status != entities.StatusClosed && issue.Status != entities.StatusDone {
+				issueIDs = append(issueIDs, issue.ID)
+			}
+		}
+	}
+
+	var suggestions []*entities.AssignmentSuggestion
+	assigned := 0
+	for _, issueID := range issueIDs {
+		suggestion, err := ownershipService.Assign(ctx, issueID, ownersAssignDryRun)
+		if err != nil {
+			printError(fmt.Errorf("failed to assign %s: %w", issueID, err))
+			return err
+		}
+		suggestions = append(suggestions, suggestion)
+		if suggestion.Assignee != "" {
+			assigned++
+		}
+	}
+
+	if format == "json" {
+		return outputJSON(suggestions)
+	}
+
+	for _, suggestion := range suggestions {
+		if suggestion.Assignee == "" {
+			fmt.Printf("  %s  no owner found\n", suggestion.IssueID)
+			continue
+		}
+		fmt.Printf("  %s  %s%s\n", suggestion.IssueID, suggestion.Assignee, ownerSuggestionVia(suggestion))
+	}
+	fmt.Println()
+	if ownersAssignDryRun {
+		printInfo(fmt.Sprintf("Dry run: would assign %d of %d issue(s)", assigned, len(suggestions)))
+		return nil
+	}
+	printSuccess(fmt.Sprintf("Assigned %d of %d issue(s)", assigned, len(suggestions)))
+	return nil
+}
+
+// assignOwnerOnCreate assigns the owner of a new issue created without an
+// assignee when the owners section of the config enables auto_assign.
+// Otherwise it returns a hint naming the suggested owner, if any.
+func assignOwnerOnCreate(ctx context.Context, repoPath string, issue *entities.Issue) (*entities.Issue, string) {
+	ownershipService, issueRepo := newOwnershipService(repoPath)
+	config := ownershipService.Config(ctx)
+	if config == nil {
+		return issue, ""
+	}
+
+	if !config.AutoAssign {
+		suggestion, err := ownershipService.Suggest(ctx, issue)
+		if err != nil || suggestion.Assignee == "" {
+			return issue, ""
+		}
+		return issue, fmt.Sprintf("Suggested assignee: %s%s (issuemap owners assign %s)", suggestion.Assignee, ownerSuggestionVia(suggestion), issue.ID)
+	}
+
+	suggestion, err := ownershipService.Assign(ctx, issue.ID, false)
+	if err != nil {
+		printWarning(fmt.Sprintf("Failed to assign an owner: %v", err))
+		return issue, ""
+	}
+	if suggestion.Assignee == "" {
+		return issue, ""
+	}
+	if updated, err := issueRepo.GetByID(ctx, issue.ID); err == nil {
+		return updated, ""
+	}
+	return issue, ""
+}
+
+func printOwnerSuggestion(suggestion *entities.AssignmentSuggestion) {
+	if suggestion.Assignee == "" {
+		printWarning("No owner resolves to a user; configure the members of the teams in the owners section of the config")
+		return
+	}
+	printSuccess(fmt.Sprintf("Suggested assignee: %s%s", suggestion.Assignee, ownerSuggestionVia(suggestion)))
+}
+
+// ownerSuggestionVia explains how the assignee was picked
+func ownerSuggestionVia(suggestion *entities.AssignmentSuggestion) string {
+	if suggestion.Strategy != "" {
+		return fmt.Sprintf(" (%s, %s)", suggestion.Via, suggestion.Strategy)
+	}
+	if suggestion.Via != "" && entities.OwnerUsername(suggestion.Via) != suggestion.Assignee {
+		return fmt.Sprintf(" (%s)", suggestion.Via)
+	}
+	return ""
+}
+
+func describeOwnershipEvidence(evidence entities.OwnershipEvidence) string {
+	if evidence.Path == "" {
+		return evidence.Source
+	}
+	return strings.Join([]string{evidence.Source + ":", evidence.Path, "matches", evidence.Pattern}, " ")
+}
@@ -0,0 +1,346 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	prBase    string
+	prRemote  string
+	prNoPush  bool
+	prPrint   bool
+	prNoFetch bool
+)
+
+// prCmd represents the pr command
+var prCmd = &cobra.Command{
+	Use:   "pr",
+	Short: "Work with pull requests for issue branches",
+	Long: `Support teams that merge through pull requests instead of 'issuemap merge'.
+
+'pr open' pushes the issue branch, renders a pull request description from the
+issue and moves the issue to review. 'pr landed' closes issues whose changes
+reached the base branch, recognising squash and rebase merges by patch ID as
+well as merge commits. 'issuemap sync' runs the same check.
+
+A provider CLI can open the pull request directly. Configure it in
+.issuemap/config.yaml; the description body is passed on stdin:
+
+  git:
+    pull_request:
+      command: gh
+      args: [pr, create, --base, "{base}", --head, "{branch}", --title, "{title}", --body-file, "-"]
+
+Examples:
+  issuemap pr open                 # Open a pull request for the current branch
+  issuemap pr open AR-001 --print  # Print the description instead of running the provider
+  issuemap pr landed               # Close issues whose pull requests have landed`,
+}
+
+// prOpenCmd opens a pull request for an issue branch
+var prOpenCmd = &cobra.Command{
+	Use:   "open [issue-id]",
+	Short: "Push an issue branch and open a pull request for it",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runPROpen(cmd, args)
+	},
+}
+
+// prLandedCmd closes issues whose pull requests have landed
+var prLandedCmd = &cobra.Command{
+	Use:   "landed [issue-id]",
+	Short: "Close issues whose pull requests have landed on the base branch",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runPRLanded(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(prCmd)
+	prCmd.AddCommand(prOpenCmd)
+	prCmd.AddCommand(prLandedCmd)
+
+	prOpenCmd.Flags().StringVar(&prBase, "base", "", "branch the pull request merges into (default: configured base or main branch)")
+	prOpenCmd.Flags().StringVar(&prRemote, "remote", "", "remote to push the branch to (default: configured remote or origin)")
+	prOpenCmd.Flags().BoolVar(&prNoPush, "no-push", false, "do not push the branch")
+	prOpenCmd.Flags().BoolVar(&prPrint, "print", false, "print the description instead of running the configured provider")
+
+	prLandedCmd.Flags().BoolVar(&prNoFetch, "no-fetch", false, "do not fetch the base branches before checking")
+}
+
+// newPullRequestService creates the pull request service of the current
+// repository
+func newPullRequestService(repoPath string, gitClient *git.GitClient) (*services.PullRequestService, *services.IssueService) {
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(issuemapPath)
+	configRepo := storage.NewFileConfigRepository(issuemapPath)
+	issueService := services.NewIssueService(issueRepo, configRepo, gitClient)
+
+	historyService := services.NewHistoryService(storage.NewFileHistoryRepository(issuemapPath), gitClient)
+	dependencyService := services.NewDependencyService(storage.NewFileDependencyRepository(issuemapPath), issueService, historyService)
+
+	return services.NewPullRequestService(issueService, dependencyService, gitClient), issueService
+}
+
+func runPROpen(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	gitClient, err := git.NewGitClient(repoPath)
+	if err != nil {
+		printError(fmt.Errorf("failed to initialize git client: %w", err))
+		return err
+	}
+
+	currentBranch, err := gitClient.GetCurrentBranch(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to get current branch: %w", err))
+		return err
+	}
+
+	var issueID entities.IssueID
+	if len(args) > 0 {
+		issueID = normalizeIssueID(args[0])
+	} else {
+		extractedID := extractIssueFromBranch(currentBranch)
+		if extractedID == "" {
+			err := fmt.Errorf("could not detect issue ID from branch name '%s'. Please provide issue ID explicitly", currentBranch)
+			printError(err)
+			return err
+		}
+		issueID = entities.IssueID(extractedID)
+	}
+
+	pullRequestService, issueService := newPullRequestService(repoPath, gitClient)
+	issue, err := issueService.GetIssue(ctx, issueID)
+	if err != nil {
+		printError(fmt.Errorf("failed to get issue %s: %w", issueID, err))
+		return err
+	}
+
+	// The checked out branch wins when it is named after the issue; the
+	// recorded branch may be stale on branches created before it was set
+	branch := issue.Branch
+	if entities.IssueID(extractIssueFromBranch(currentBranch)) == issueID {
+		branch = currentBranch
+	}
+	if branch == "" {
+		err := fmt.Errorf("issue %s has no branch; create one with: issuemap branch %s", issueID, issueID)
+		printError(err)
+		return err
+	}
+
+	config, err := storage.NewFileConfigRepository(filepath.Join(repoPath, app.ConfigDirName)).Load(ctx)
+	if err != nil {
+		config = entities.NewDefaultConfig()
+	}
+	prConfig := config.Git.PullRequest
+	if prConfig == nil {
+		prConfig = &entities.PullRequestConfig{}
+	}
+
+	remote := prRemote
+	if remote == "" {
+		remote = prConfig.Remote
+	}
+	if remote == "" {
+		remote = entities.DefaultPullRequestRemote
+	}
+	base := prBase
+	if base == "" {
+		base = prConfig.Base
+	}
+	if base == "" {
+		if base, err = gitClient.GetMainBranch(ctx); err != nil {
+			printError(fmt.Errorf("failed to determine main branch: %w", err))
+			return err
+		}
+	}
+
+	if branch == base {
+		err := fmt.Errorf("issue %s is on %s, the base branch; create a branch with: issuemap branch %s", issueID, base, issueID)
+		printError(err)
+		return err
+	}
+
+	ref, err := pullRequestService.NewPullRequestRef(ctx, remote, branch, base)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	// The status change is committed on the branch so the pull request
+	// carries it
+	if currentBranch != branch {
+		if hasUncommittedIssuemapFiles(repoPath) {
+			if err := commitIssuemapFiles(repoPath, issueID); err != nil {
+				printWarning(fmt.Sprintf("Failed to commit .issuemap files: %v", err))
+			}
+		}
+		if err := gitClient.SwitchToBranch(ctx, branch); err != nil {
+			printError(fmt.Errorf("failed to switch to branch %s: %w", branch, err))
+			return err
+		}
+		printInfo(fmt.Sprintf("Switched to branch: %s", branch))
+	}
+
+	description, err := pullRequestService.Describe(ctx, issue)
+	if err != nil {
+		printError(fmt.Errorf("failed to render pull request description: %w", err))
+		return err
+	}
+
+	if _, err := pullRequestService.MarkOpened(ctx, issueID, ref); err != nil {
+		printError(fmt.Errorf("failed to update issue %s: %w", issueID, err))
+		return err
+	}
+	if hasUncommittedIssuemapFiles(repoPath) {
+		if err := commitIssuemapFiles(repoPath, issueID); err != nil {
+			printWarning(fmt.Sprintf("Failed to commit .issuemap files: %v", err))
+		}
+	}
+
+	if !prNoPush {
+		if err := gitClient.PushBranchUpstream(ctx, remote, branch); err != nil {
+			printError(fmt.Errorf("failed to push %s to %s: %w", branch, remote, err))
+			return err
+		}
+		printSuccess(fmt.Sprintf("Pushed %s to %s", branch, remote))
+	}
+
+	if prConfig.Command == "" || prPrint {
+		if format == "json" {
+			return outputJSON(description)
+		}
+		fmt.Println(description.Title)
+		fmt.Println()
+		fmt.Print(description.Body)
+		fmt.Println()
+		printSuccess(fmt.Sprintf("Issue %s is in review", issueID))
+		return nil
+	}
+
+	url, err := services.NewCommandPullRequestProvider(prConfig).Open(ctx, description, ref)
+	if err != nil {
+		printError(fmt.Errorf("failed to open pull request: %w", err))
+		printInfo("Print the description to open it by hand with: issuemap pr open --print --no-push")
+		return err
+	}
+	if url != "" {
+		if _, err := pullRequestService.SetURL(ctx, issueID, url); err != nil {
+			printWarning(fmt.Sprintf("Opened %s but couldn't record it on the issue: %v", url, err))
+		}
+		printSuccess(fmt.Sprintf("Opened pull request %s", url))
+	} else {
+		printSuccess("Opened pull request")
+	}
+	printSuccess(fmt.Sprintf("Issue %s is in review", issueID))
+	return nil
+}
+
+func runPRLanded(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	gitClient, err := git.NewGitClient(repoPath)
+	if err != nil {
+		printError(fmt.Errorf("failed to initialize git client: %w", err))
+		return err
+	}
+
+	pullRequestService, issueService := newPullRequestService(repoPath, gitClient)
+
+	var issues []*entities.Issue
+	if len(args) > 0 {
+		issue, err := issueService.GetIssue(ctx, normalizeIssueID(args[0]))
+		if err != nil {
+			printError(fmt.Errorf("failed to get issue: %w", err))
+			return err
+		}
+		if issue.PullRequest == nil {
+			err := fmt.Errorf("issue %s has no pull request; open one with: issuemap pr open %s", issue.ID, issue.ID)
+			printError(err)
+			return err
+		}
+		issues = append(issues, issue)
+	} else {
+		if issues, err = pullRequestService.OpenPullRequests(ctx); err != nil {
+			printError(fmt.Errorf("failed to list pull requests: %w", err))
+			return err
+		}
+	}
+
+	if !prNoFetch {
+		fetched := make(map[string]bool)
+		for _, issue := range issues {
+			ref := issue.PullRequest
+			key := ref.Remote + "/" + ref.Base
+			if ref.Remote == "" || fetched[key] {
+				continue
+			}
+			fetched[key] = true
+			if err := gitClient.FetchBranch(ctx, ref.Remote, ref.Base); err != nil {
+				printWarning(fmt.Sprintf("Failed to fetch %s: %v", key, err))
+			}
+		}
+	}
+
+	landed := checkLandedPullRequests(ctx, pullRequestService, issues)
+	if landed == 0 {
+		printInfo(fmt.Sprintf("No landed pull requests among %d open", len(issues)))
+	}
+	return nil
+}
+
+// checkLandedPullRequests closes the issues whose pull requests have landed
+// and returns how many did
+func checkLandedPullRequests(ctx context.Context, pullRequestService *services.PullRequestService, issues []*entities.Issue) int {
+	landed := 0
+	for _, issue := range issues {
+		if issue.PullRequest.Landed() {
+			printInfo(fmt.Sprintf("%s: pull request already landed as %s", issue.ID, issue.PullRequest.LandedAs))
+			continue
+		}
+
+		landing, err := pullRequestService.DetectLanding(ctx, issue)
+		if err != nil {
+			printWarning(fmt.Sprintf("%s: failed to check pull request: %v", issue.ID, err))
+			continue
+		}
+		if landing == nil {
+			continue
+		}
+
+		if err := pullRequestService.MarkLanded(ctx, issue, landing); err != nil {
+			printWarning(fmt.Sprintf("%s: pull request landed but couldn't close the issue: %v", issue.ID, err))
+			continue
+		}
+		landed++
+		printSuccess(fmt.Sprintf("%s: pull request landed on %s (%s); issue closed", issue.ID, issue.PullRequest.Base, landing.Method))
+	}
+	return landed
+}
@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	releaseFrom          string
+	releaseTo            string
+	releaseVersion       string
+	releaseTemplate      string
+	releaseChangelog     string
+	releaseMilestone     string
+	releaseWrite         bool
+	releaseTemplateForce bool
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Generate release notes and changelog entries from closed issues",
+	Long: `Generate release notes from the issues closed between two git refs.
+
+An issue is part of a release when one of its linked commits, or a commit
+referencing it, is in the range. Issues without commits are included when they
+were closed between the dates of the two refs.
+
+Issues are grouped into sections by type and label. The defaults follow Keep a
+Changelog; configure your own in .issuemap/config.yaml:
+
+  release:
+    changelog: CHANGELOG.md
+    sections:
+      - title: Security
+        labels: [security]
+      - title: Added
+        types: [feature, epic]
+      - title: Fixed
+        types: [bug]
+      - title: Changed
+
+Notes are rendered with a Go template. 'issuemap release template' writes the
+built-in one to .issuemap/templates/release_notes.md.tmpl for editing.
+
+Examples:
+  issuemap release notes --from v1.1.0 --to v1.2.0
+  issuemap release notes --from v1.2.0 --version 1.3.0 --write
+  issuemap release notes --from v1.1.0 --to v1.2.0 --write --milestone v1.2`,
+}
+
+// releaseNotesCmd renders release notes
+var releaseNotesCmd = &cobra.Command{
+	Use:   "notes",
+	Short: "Render release notes for the issues closed between two git refs",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runReleaseNotes(cmd, args)
+	},
+}
+
+// releaseTemplateCmd writes the default template for editing
+var releaseTemplateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Write the built-in release notes template so it can be edited",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runReleaseTemplate(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+	releaseCmd.AddCommand(releaseNotesCmd)
+	releaseCmd.AddCommand(releaseTemplateCmd)
+
+	releaseNotesCmd.Flags().StringVar(&releaseFrom, "from", "", "ref of the previous release (default: the newest tag before --to)")
+	releaseNotesCmd.Flags().StringVar(&releaseTo, "to", "HEAD", "ref of the release")
+	releaseNotesCmd.Flags().StringVar(&releaseVersion, "version", "", "version heading (default: --to when it is a tag, else Unreleased)")
+	releaseNotesCmd.Flags().StringVar(&releaseTemplate, "template", "", "template file (default: .issuemap/templates/release_notes.md.tmpl or the built-in template)")
+	releaseNotesCmd.Flags().BoolVar(&releaseWrite, "write", false, "write the notes into the changelog")
+	releaseNotesCmd.Flags().StringVar(&releaseChangelog, "changelog", "", "changelog file to write (default: configured changelog or CHANGELOG.md)")
+	releaseNotesCmd.Flags().StringVar(&releaseMilestone, "milestone", "", "mark this milestone as released")
+
+	releaseTemplateCmd.Flags().BoolVar(&releaseTemplateForce, "force", false, "replace an existing template")
+}
+
+// newReleaseService creates the release service of the current repository
+func newReleaseService() (*services.ReleaseService, *git.GitClient, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+	gitClient, err := git.NewGitClient(repoPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize git client: %w", err)
+	}
+
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	return services.NewReleaseService(
+		storage.NewFileIssueRepository(issuemapPath),
+		storage.NewFileConfigRepository(issuemapPath),
+		gitClient,
+		issuemapPath,
+	), gitClient, nil
+}
+
+func runReleaseNotes(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	releaseService, gitClient, err := newReleaseService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	from := releaseFrom
+	if from == "" {
+		if from, err = gitClient.PreviousTag(ctx, releaseTo); err != nil {
+			printError(fmt.Errorf("unknown git ref %q", releaseTo))
+			return err
+		}
+	}
+	version := releaseVersion
+	if version == "" {
+		version = entities.UnreleasedVersion
+		if gitClient.IsTag(ctx, releaseTo) {
+			version = releaseTo
+		}
+	}
+
+	notes, err := releaseService.Notes(ctx, version, from, releaseTo)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	text, err := releaseService.Template(ctx, releaseTemplate)
+	if err != nil {
+		printError(fmt.Errorf("failed to read release notes template: %w", err))
+		return err
+	}
+	rendered, err := entities.RenderReleaseNotes(notes, text)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	if releaseWrite {
+		path, err := releaseService.ChangelogPath(ctx, releaseChangelog)
+		if err != nil {
+			printError(err)
+			return err
+		}
+		if err := releaseService.UpdateChangelog(path, version, rendered); err != nil {
+			printError(fmt.Errorf("failed to update changelog: %w", err))
+			return err
+		}
+		if format != "json" {
+			printSuccess(fmt.Sprintf("Updated %s with %s (%d issues)", path, version, notes.Count))
+		}
+	}
+
+	if releaseMilestone != "" {
+		if err := releaseService.ReleaseMilestone(ctx, releaseMilestone, notes.Date); err != nil {
+			printError(fmt.Errorf("failed to release milestone %s: %w", releaseMilestone, err))
+			return err
+		}
+		if format != "json" {
+			printSuccess(fmt.Sprintf("Milestone %s released", releaseMilestone))
+		}
+	}
+
+	if format == "json" {
+		return outputJSON(notes)
+	}
+	if !releaseWrite {
+		fmt.Print(rendered)
+	}
+	return nil
+}
+
+func runReleaseTemplate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	releaseService, _, err := newReleaseService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	path, err := releaseService.WriteTemplate(ctx, releaseTemplateForce)
+	if err != nil {
+		printError(err)
+		return err
+	}
+	printSuccess(fmt.Sprintf("Wrote release notes template to %s", path))
+	return nil
+}
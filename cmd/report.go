@@ -377,15 +377,22 @@ func generateTableReport(entries []*entities.TimeEntry, stats *repositories.Time
 	}
 
 	// Print summary header
-	var totalDuration time.Duration
+	var totalDuration, estimatedDuration time.Duration
 	for _, entry := range entries {
 		totalDuration += entry.GetDuration()
+		if entry.IsEstimated() {
+			estimatedDuration += entry.GetDuration()
+		}
 	}
 
 	fmt.Printf("Time Tracking Report\n")
 	fmt.Printf("===================\n\n")
 	fmt.Printf("Total Entries: %d\n", len(entries))
 	fmt.Printf("Total Time: %.1f hours\n", totalDuration.Hours())
+	if estimatedDuration > 0 {
+		fmt.Printf("  Tracked: %.1f hours\n", (totalDuration - estimatedDuration).Hours())
+		fmt.Printf("  Estimated from commits: %.1f hours\n", estimatedDuration.Hours())
+	}
 	if len(entries) > 0 {
 		avgDuration := totalDuration / time.Duration(len(entries))
 		fmt.Printf("Average Time: %.1f hours\n", avgDuration.Hours())
@@ -407,15 +414,24 @@ func generateTableReport(entries []*entities.TimeEntry, stats *repositories.Time
 		if len(description) > 20 {
 			description = description[:17] + "..."
 		}
-		fmt.Printf("%-12s %-20s %-10s %8.1f %-19s %s\n",
+		// Estimated commit time is marked with ~ and the commit it came from
+		hours := fmt.Sprintf("%8.1f", entry.GetDurationHours())
+		if entry.IsEstimated() {
+			hours = fmt.Sprintf("%8s", fmt.Sprintf("~%.1f", entry.GetDurationHours()))
+			description = fmt.Sprintf("[%s] %s", shortHash(entry.CommitHash), description)
+		}
+		fmt.Printf("%-12s %-20s %-10s %s %-19s %s\n",
 			entry.IssueID,
 			truncateText(entry.Author, 20),
 			entry.Type,
-			entry.GetDurationHours(),
+			hours,
 			entry.StartTime.Format("2006-01-02 15:04"),
 			description,
 		)
 	}
+	if estimatedDuration > 0 {
+		fmt.Printf("\n~ estimated from commit timestamps\n")
+	}
 
 	// Print statistics if requested
 	if stats != nil {
@@ -483,7 +499,7 @@ func generateCSVReport(entries []*entities.TimeEntry, outputFile string) error {
 	defer writer.Flush()
 
 	// Write header
-	header := []string{"Issue ID", "Author", "Type", "Hours", "Description", "Start Time", "End Time", "Created At"}
+	header := []string{"Issue ID", "Author", "Type", "Hours", "Description", "Start Time", "End Time", "Created At", "Commit"}
 	if err := writer.Write(header); err != nil {
 		return fmt.Errorf("failed to write CSV header: %w", err)
 	}
@@ -504,6 +520,7 @@ func generateCSVReport(entries []*entities.TimeEntry, outputFile string) error {
 			entry.StartTime.Format("2006-01-02 15:04:05"),
 			endTime,
 			entry.CreatedAt.Format("2006-01-02 15:04:05"),
+			entry.CommitHash,
 		}
 
 		if err := writer.Write(record); err != nil {
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var revertTo string
+
+// revertCmd represents the revert command
+var revertCmd = &cobra.Command{
+	Use:   "revert <issue-id> --to <version>",
+	Short: "Revert an issue to an earlier version",
+	Long: `Restore the tracked fields of an issue (title, description, type, status,
+priority, assignee, milestone, labels and branch) to an earlier history version.
+
+The revert does not rewrite history: the inverse changes are applied and
+recorded as a new "reverted" history entry, so a revert can itself be reverted.
+Comments, attachments and time tracking are not affected.
+
+Examples:
+  issuemap revert ISSUE-001 --to v5
+  issuemap revert 001 --to 3`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runRevert(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(revertCmd)
+
+	revertCmd.Flags().StringVar(&revertTo, "to", "", "history version to revert to (e.g. v5)")
+	if err := revertCmd.MarkFlagRequired("to"); err != nil {
+		return
+	}
+}
+
+func runRevert(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	issueID := normalizeIssueID(args[0])
+
+	version, err := parseHistoryVersion(revertTo)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	issueService, err := newVersionedIssueService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	_, changes, err := issueService.RevertIssue(ctx, issueID, version)
+	if err != nil {
+		printError(fmt.Errorf("failed to revert issue: %w", err))
+		return err
+	}
+
+	if len(changes) == 0 {
+		printInfo(fmt.Sprintf("%s already matches version %d", issueID, version))
+		return nil
+	}
+
+	printSuccess(fmt.Sprintf("Reverted %s to version %d", issueID, version))
+	displayFieldChanges(changes)
+	return nil
+}
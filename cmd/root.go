@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -33,6 +34,39 @@ Features:
 - Template-based issue creation
 - No external dependencies`,
 	Version: app.GetVersion(),
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return checkRepositoryFormat(cmd)
+	},
+}
+
+// formatCheckExempt lists the commands that work on repositories of any
+// format version
+var formatCheckExempt = map[string]bool{
+	"migrate":    true,
+	"version":    true,
+	"help":       true,
+	"completion": true,
+	"schema":     true,
+}
+
+// checkRepositoryFormat refuses to run on a repository migrated by a newer
+// issuemap, which this build could misread or overwrite in an older format
+func checkRepositoryFormat(cmd *cobra.Command) error {
+	for c := cmd; c != nil; c = c.Parent() {
+		if formatCheckExempt[c.Name()] {
+			return nil
+		}
+	}
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil
+	}
+	if err := storage.CheckFormatVersion(filepath.Join(repoPath, app.ConfigDirName)); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+	return nil
 }
 
 // getCommandName determines the command name based on how the binary was invoked
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Show the JSON Schemas of the files under .issuemap",
+	Long: `Show the JSON Schemas describing the on-disk formats of issues, histories,
+dependencies, time entries and the project config.
+
+Every file records the format version it was written in as schema_version.
+Files written in an older version are upgraded when they are read and saved
+in the current version.
+
+Examples:
+  issuemap schema list                         # List kinds and versions
+  issuemap schema print issue                  # Print the issue schema
+  issuemap schema print time-entry > te.json   # Save a schema to a file`,
+}
+
+// schemaListCmd lists the versioned file kinds
+var schemaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the file kinds and their current schema versions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSchemaList(cmd)
+	},
+}
+
+// schemaPrintCmd prints the schema of one kind
+var schemaPrintCmd = &cobra.Command{
+	Use:       "print <kind>",
+	Short:     "Print the JSON Schema of a file kind",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"issue", "history", "dependency", "time_entry", "config"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSchemaPrint(cmd, args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+	schemaCmd.AddCommand(schemaListCmd)
+	schemaCmd.AddCommand(schemaPrintCmd)
+}
+
+func runSchemaList(cmd *cobra.Command) error {
+	if format == "json" {
+		return outputJSON(entities.ListSchemas())
+	}
+
+	fmt.Printf("%-12s %-8s %s\n", "KIND", "VERSION", "ID")
+	for _, info := range entities.ListSchemas() {
+		fmt.Printf("%-12s %-8d %s\n", info.Kind, info.Version, info.ID)
+	}
+	return nil
+}
+
+func runSchemaPrint(cmd *cobra.Command, name string) error {
+	kind, err := entities.ParseSchemaKind(name)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	schema, err := entities.GenerateJSONSchema(kind)
+	if err != nil {
+		printError(fmt.Errorf("failed to generate schema: %w", err))
+		return err
+	}
+
+	if err := outputJSON(schema); err != nil {
+		return err
+	}
+	fmt.Println()
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ooyeku/issuemap/internal/app/services"
 	"github.com/ooyeku/issuemap/internal/domain/entities"
 	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
 	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
 )
 
@@ -32,7 +33,9 @@ Field-based searches:
   type:bug              - Issues of type 'bug'
   status:open           - Open issues
   priority:high         - High priority issues
-  assignee:username     - Issues assigned to user
+  assignee:username     - Issues assigned to user (or any of their aliases)
+  assignee:@team        - Issues assigned to a team member (see 'issuemap users')
+  watcher:me            - Issues you watch (see 'issuemap watch')
   branch:feature-123    - Issues linked to branch
   labels:urgent,bug     - Issues with specific labels
 
@@ -144,6 +147,12 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	configRepo := storage.NewFileConfigRepository(issuemapPath)
 
 	searchService := services.NewSearchService(issueRepo)
+	if config, err := configRepo.Load(ctx); err == nil {
+		searchService.SetUserDirectory(config.UserDirectory())
+	}
+	if gitClient, err := git.NewGitClient(repoPath); err == nil {
+		searchService.SetCurrentUser(getCurrentUser(gitClient))
+	}
 
 	// Parse the search query
 	parsedQuery, err := searchService.ParseSearchQuery(searchQuery)
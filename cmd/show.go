@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -17,6 +18,7 @@ import (
 
 var (
 	showNoTruncate bool
+	showAt         string
 )
 
 // showCmd represents the show command
@@ -29,7 +31,9 @@ comments, commits, and all metadata.
 Examples:
   issuemap show ISSUE-001
   issuemap show 001
-  issuemap show ISSUE-001 --format json`,
+  issuemap show ISSUE-001 --format json
+  issuemap show ISSUE-001 --at v5          # As of history version 5
+  issuemap show ISSUE-001 --at 2024-03-01  # As of the end of a day`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runShow(cmd, args)
@@ -39,6 +43,7 @@ Examples:
 func init() {
 	rootCmd.AddCommand(showCmd)
 	showCmd.Flags().BoolVar(&showNoTruncate, "no-truncate", false, "disable text truncation for better readability")
+	showCmd.Flags().StringVar(&showAt, "at", "", "show the issue as of a history version (v5) or date (YYYY-MM-DD or RFC3339)")
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
@@ -63,6 +68,10 @@ func runShow(cmd *cobra.Command, args []string) error {
 
 	issueService := services.NewIssueService(issueRepo, configRepo, gitRepo)
 
+	if showAt != "" {
+		return runShowAt(ctx, issueService, issueID, showAt)
+	}
+
 	// Get the issue
 	issue, err := issueService.GetIssue(ctx, issueID)
 	if err != nil {
@@ -75,6 +84,49 @@ func runShow(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runShowAt displays an issue reconstructed from history at a version or date
+func runShowAt(ctx context.Context, issueService *services.IssueService, issueID entities.IssueID, at string) error {
+	var issue *entities.Issue
+	var version int
+
+	if v, err := parseHistoryVersion(at); err == nil {
+		version = v
+		issue, err = issueService.GetIssueAtVersion(ctx, issueID, version)
+		if err != nil {
+			printError(fmt.Errorf("failed to reconstruct issue: %w", err))
+			return err
+		}
+	} else {
+		t, err := parseHistoryDate(at)
+		if err != nil {
+			printError(err)
+			return err
+		}
+		issue, version, err = issueService.GetIssueAtTime(ctx, issueID, t)
+		if err != nil {
+			printError(fmt.Errorf("failed to reconstruct issue: %w", err))
+			return err
+		}
+	}
+
+	printInfo(fmt.Sprintf("Showing %s as of version %d (%s)", issueID, version,
+		issue.Timestamps.Updated.Format("2006-01-02 15:04:05")))
+	fmt.Println()
+	displayIssueDetails(issue)
+	return nil
+}
+
+// parseHistoryDate parses a date (end of day) or RFC3339 timestamp
+func parseHistoryDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t.Add(24*time.Hour - time.Nanosecond), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --at value %q: expected a version (v5) or date (YYYY-MM-DD or RFC3339)", value)
+}
+
 func displayIssueDetails(issue *entities.Issue) {
 	// Header with issue ID and title
 	if noColor {
@@ -98,6 +150,11 @@ func displayIssueDetails(issue *entities.Issue) {
 		fmt.Printf("%s %s\n", colorLabel("Status:"), colorStatus(issue.Status))
 		fmt.Printf("%s %s\n", colorLabel("Priority:"), colorPriority(issue.Priority))
 	}
+	if issue.Redacted {
+		formatFieldValue("Confidential", "yes (content hidden: not a recipient)")
+	} else if issue.Confidential {
+		formatFieldValue("Confidential", "yes")
+	}
 
 	// Assignment info
 	if issue.Assignee != nil {
@@ -106,6 +163,9 @@ func displayIssueDetails(issue *entities.Issue) {
 	} else {
 		formatFieldValue("Assignee", "Unassigned")
 	}
+	if len(issue.Watchers) > 0 {
+		formatFieldValue("Watchers", strings.Join(issue.Watchers, ", "))
+	}
 
 	// Labels with colors
 	if len(issue.Labels) > 0 {
@@ -199,6 +259,7 @@ func displayIssueDetails(issue *entities.Issue) {
 				if attachment.Description != "" {
 					fmt.Printf("     Description: %s\n", attachment.Description)
 				}
+				fmt.Printf("     ID: %s\n", attachment.ID)
 			} else {
 				fmt.Printf("  %s %s - %s %s\n",
 					icon, colorValue(attachment.Filename),
@@ -207,6 +268,7 @@ func displayIssueDetails(issue *entities.Issue) {
 				if attachment.Description != "" {
 					fmt.Printf("     %s %s\n", colorLabel("Description:"), attachment.Description)
 				}
+				fmt.Printf("     %s %s\n", colorLabel("ID:"), color.HiBlackString(attachment.ID))
 			}
 		}
 	}
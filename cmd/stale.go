@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	staleDryRun bool
+	staleJSON   bool
+)
+
+// staleCmd represents the stale command
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "Mark inactive issues stale and close them after a grace period",
+	Long: `Sweep open issues for inactivity. Issues with no activity for the
+configured number of days get a comment and the stale label; stale issues
+are closed after a grace period, and the label is removed again as soon as
+an issue sees new activity. Closed and done issues are ignored.
+
+The server runs the sweep daily when enabled (see 'issuemap jobs'). Policies
+are configured in the config; the first matching policy applies:
+
+  stale:
+    enabled: true
+    label: stale
+    days_until_stale: 60
+    days_until_close: 14        # 0 leaves stale issues open
+    exempt_labels: [pinned, security]
+    exempt_milestones: [v2.0]
+    policies:
+      - priorities: [critical, high]
+        days_until_stale: 120
+      - types: [epic]
+        exempt: true
+      - labels: [question]
+        days_until_stale: 14
+        days_until_close: 7
+
+Examples:
+  issuemap stale --dry-run     # Preview which issues would change
+  issuemap stale               # Run the sweep now
+  issuemap stale --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStale(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(staleCmd)
+
+	staleCmd.Flags().BoolVar(&staleDryRun, "dry-run", false, "show what would change without modifying issues")
+	staleCmd.Flags().BoolVar(&staleJSON, "json", false, "output as JSON")
+}
+
+func runStale(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+
+	var gitClient *git.GitClient
+	if client, err := git.NewGitClient(repoPath); err == nil {
+		gitClient = client
+	}
+	historyService := services.NewHistoryService(storage.NewFileHistoryRepository(basePath), gitClient)
+	staleService := services.NewStaleService(issueRepo, configRepo, historyService)
+
+	result, err := staleService.Sweep(ctx, staleDryRun)
+	if err != nil {
+		printError(fmt.Errorf("stale sweep failed: %w", err))
+		return err
+	}
+
+	if staleJSON {
+		return outputJSON(result)
+	}
+
+	displayStaleResult(result)
+	return nil
+}
+
+func displayStaleResult(result *entities.StaleSweepResult) {
+	verb := func(done, planned string) string {
+		if result.DryRun {
+			return planned
+		}
+		return done
+	}
+
+	if result.DryRun {
+		printInfo("Dry run: no issues were modified")
+	}
+
+	fmt.Printf("%s %d open issues (%d exempt)\n", colorLabel("Checked:"), result.Checked, result.Exempt)
+
+	sections := []struct {
+		title     string
+		decisions []entities.StaleDecision
+	}{
+		{verb("Marked stale", "Would mark stale"), result.Marked},
+		{verb("Closed", "Would close"), result.Closed},
+		{verb("Unmarked (new activity)", "Would unmark (new activity)"), result.Unmarked},
+	}
+
+	for _, section := range sections {
+		if len(section.decisions) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s (%d):\n", colorHeader(section.title), len(section.decisions))
+		for _, decision := range section.decisions {
+			fmt.Printf("  %s %s %s\n", colorIssueID(decision.IssueID), decision.Title,
+				colorLabel(fmt.Sprintf("(inactive %d days)", decision.InactiveDays)))
+		}
+	}
+
+	for _, e := range result.Errors {
+		printWarning(e)
+	}
+
+	if len(result.Marked)+len(result.Closed)+len(result.Unmarked) == 0 {
+		fmt.Println()
+		printSuccess("No issues to mark, close or unmark")
+	}
+}
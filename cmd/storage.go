@@ -41,7 +41,7 @@ Examples:
   issuemap storage --by-issue         # Group by issue
   issuemap storage --refresh          # Force refresh cache
   issuemap storage cleanup            # Run cleanup operation
-  issuemap storage compress           # Configure compression
+  issuemap storage compress           # Show or configure compression
   issuemap storage archive            # Archive old issues
   issuemap storage restore            # Restore from archive`,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -94,24 +94,6 @@ Examples:
 	},
 }
 
-var storageCompressCmd = &cobra.Command{
-	Use:   "compress",
-	Short: "Configure compression settings",
-	Long: `Configure and manage attachment compression.
-
-Examples:
-  issuemap storage compress                   # Show compression status
-  issuemap storage compress --enable          # Enable compression
-  issuemap storage compress --level 9         # Set compression level
-  issuemap storage compress --threshold 1MB   # Compress files over 1MB`,
-	RunE: func(cmd *cobra.Command, args []string) error {
-		// Delegate to compression command logic
-		// TODO: Implement compression logic
-		fmt.Println("Compression management functionality (placeholder)")
-		return nil
-	},
-}
-
 var storageArchiveCmd = &cobra.Command{
 	Use:   "archive",
 	Short: "Archive old issues",
@@ -150,7 +132,6 @@ func init() {
 	rootCmd.AddCommand(storageCmd)
 	storageCmd.AddCommand(storageConfigCmd)
 	storageCmd.AddCommand(storageCleanupCmd)
-	storageCmd.AddCommand(storageCompressCmd)
 	storageCmd.AddCommand(storageArchiveCmd)
 	storageCmd.AddCommand(storageRestoreCmd)
 
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	compressEnable     bool
+	compressDisable    bool
+	compressLevel      int
+	compressCodec      string
+	compressThreshold  string
+	compressRecompress bool
+	compressDryRun     bool
+	compressJSON       bool
+)
+
+var storageCompressCmd = &cobra.Command{
+	Use:   "compress",
+	Short: "Configure compression and recompress attachments",
+	Long: `Show and configure attachment compression, or convert stored attachments
+to another codec.
+
+Codecs:
+  gzip   the original codec, readable everywhere
+  zstd   better ratio and faster decompression than gzip
+  none   store attachments uncompressed
+
+Per content type and size codecs are set with storage.compression.policies in
+the config; --codec sets the default for everything else. Attachments are
+always decompressed with the codec recorded when they were compressed.
+
+Examples:
+  issuemap storage compress                          # Show compression status
+  issuemap storage compress --enable --codec zstd    # Compress new attachments with zstd
+  issuemap storage compress --level 9                # Set compression level
+  issuemap storage compress --threshold 1MB          # Compress files over 1MB
+  issuemap storage compress --recompress --codec zstd --dry-run
+  issuemap storage compress --recompress --codec zstd`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStorageCompress(cmd, args)
+	},
+}
+
+func init() {
+	storageCmd.AddCommand(storageCompressCmd)
+
+	storageCompressCmd.Flags().BoolVar(&compressEnable, "enable", false, "enable compression of new attachments")
+	storageCompressCmd.Flags().BoolVar(&compressDisable, "disable", false, "disable compression of new attachments")
+	storageCompressCmd.Flags().IntVar(&compressLevel, "level", 0, "compression level (1=fast, 9=best)")
+	storageCompressCmd.Flags().StringVar(&compressCodec, "codec", "", "codec ("+strings.Join(services.CompressionCodecNames(), ", ")+")")
+	storageCompressCmd.Flags().StringVar(&compressThreshold, "threshold", "", "minimum file size to compress (e.g., 1KB, 1MB)")
+	storageCompressCmd.Flags().BoolVar(&compressRecompress, "recompress", false, "convert stored attachments to --codec")
+	storageCompressCmd.Flags().BoolVar(&compressDryRun, "dry-run", false, "with --recompress, measure the savings without changing anything")
+	storageCompressCmd.Flags().BoolVar(&compressJSON, "json", false, "output in JSON format")
+}
+
+func runStorageCompress(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	configRepo := storage.NewFileConfigRepository(issuemapPath)
+	issueRepo := storage.NewFileIssueRepository(issuemapPath)
+	attachmentRepo := storage.NewFileAttachmentRepository(issuemapPath)
+
+	if compressCodec != "" {
+		if _, err := services.GetCompressionCodec(compressCodec); err != nil {
+			printError(err)
+			return err
+		}
+	}
+
+	if compressRecompress {
+		if compressCodec == "" {
+			err := fmt.Errorf("--recompress requires --codec")
+			printError(err)
+			return err
+		}
+		return runRecompress(ctx, services.NewCompressionService(issuemapPath, configRepo, attachmentRepo))
+	}
+
+	storageService := services.NewStorageService(issuemapPath, configRepo, issueRepo, attachmentRepo)
+	config := storageService.GetConfig()
+	compression := entities.DefaultCompressionConfig()
+	if config.CompressionConfig != nil {
+		copied := *config.CompressionConfig
+		compression = &copied
+	}
+
+	changed := false
+	if cmd.Flags().Changed("enable") {
+		compression.Enabled = compressEnable
+		changed = true
+	}
+	if cmd.Flags().Changed("disable") {
+		compression.Enabled = !compressDisable
+		changed = true
+	}
+	if cmd.Flags().Changed("level") {
+		if compressLevel < 1 || compressLevel > 9 {
+			err := fmt.Errorf("compression level must be between 1 and 9")
+			printError(err)
+			return err
+		}
+		compression.Level = compressLevel
+		changed = true
+	}
+	if compressCodec != "" {
+		compression.Codec = compressCodec
+		changed = true
+	}
+	if compressThreshold != "" {
+		size, err := parseSize(compressThreshold)
+		if err != nil {
+			printError(fmt.Errorf("invalid threshold: %w", err))
+			return err
+		}
+		compression.MinFileSize = size
+		changed = true
+	}
+
+	if changed {
+		newConfig := *config
+		newConfig.CompressionConfig = compression
+		if err := storageService.UpdateConfig(&newConfig); err != nil {
+			printError(fmt.Errorf("failed to update compression config: %w", err))
+			return err
+		}
+		printSuccess("Compression configuration updated")
+		fmt.Println()
+	}
+
+	stats := services.NewCompressionService(issuemapPath, configRepo, attachmentRepo).GetStats()
+	if compressJSON {
+		data, err := json.MarshalIndent(map[string]interface{}{
+			"config": compression,
+			"stats":  stats,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	displayCompressionConfig(compression, stats)
+	return nil
+}
+
+func runRecompress(ctx context.Context, compressionService *services.CompressionService) error {
+	result, err := compressionService.RecompressAttachments(ctx, compressCodec, compressDryRun)
+	if err != nil {
+		printError(fmt.Errorf("failed to recompress attachments: %w", err))
+		return err
+	}
+
+	if compressJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		displayRecompressionResult(result)
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d file(s) failed to recompress", len(result.Failed))
+	}
+	return nil
+}
+
+func displayCompressionConfig(config *entities.CompressionConfig, stats *entities.CompressionStats) {
+	if noColor {
+		fmt.Println("Compression Configuration:")
+		fmt.Println("=========================")
+	} else {
+		color.Cyan("Compression Configuration:")
+		color.HiBlack("=========================")
+	}
+
+	codec, _ := config.CodecFor("", "", 0)
+	formatFieldValue("Enabled", fmt.Sprintf("%v", config.Enabled))
+	formatFieldValue("Default Codec", codec)
+	formatFieldValue("Level", fmt.Sprintf("%d", config.Level))
+	formatFieldValue("Min File Size", formatConfigSize(config.MinFileSize))
+	formatFieldValue("Max File Size", formatConfigSize(config.MaxFileSize))
+	formatFieldValue("Min Savings", fmt.Sprintf("%.0f%%", config.MinCompressionRatio*100))
+
+	if len(config.Policies) > 0 {
+		fmt.Println()
+		fmt.Println("Policies:")
+		for i, policy := range config.Policies {
+			var criteria []string
+			if len(policy.ContentTypes) > 0 {
+				criteria = append(criteria, strings.Join(policy.ContentTypes, ","))
+			}
+			if len(policy.Extensions) > 0 {
+				criteria = append(criteria, strings.Join(policy.Extensions, ","))
+			}
+			if policy.MinSize > 0 {
+				criteria = append(criteria, ">= "+entities.FormatBytes(policy.MinSize))
+			}
+			if policy.MaxSize > 0 {
+				criteria = append(criteria, "<= "+entities.FormatBytes(policy.MaxSize))
+			}
+			if len(criteria) == 0 {
+				criteria = append(criteria, "everything")
+			}
+			fmt.Printf("  %d. %s -> %s\n", i+1, strings.Join(criteria, " "), policy.Codec)
+		}
+	}
+
+	if stats != nil && stats.TotalFiles > 0 {
+		fmt.Println()
+		fmt.Println("Statistics:")
+		fmt.Printf("  Files compressed: %d of %d\n", stats.CompressedFiles, stats.TotalFiles)
+		fmt.Printf("  Space saved:      %s\n", entities.FormatBytes(stats.SpaceSaved))
+	}
+}
+
+func displayRecompressionResult(result *entities.RecompressionResult) {
+	verb := "Recompressed"
+	if result.DryRun {
+		verb = "Would recompress"
+	}
+
+	fmt.Printf("%s %d file(s) for %d attachment(s) with %s\n", verb, result.Files, result.Attachments, result.Codec)
+	if result.Skipped > 0 {
+		printInfo(fmt.Sprintf("%d attachment(s) skipped (already %s or not stored locally)", result.Skipped, result.Codec))
+	}
+
+	if result.Files > 0 {
+		saved := result.BytesSaved()
+		fmt.Printf("  Before: %s\n", entities.FormatBytes(result.BytesBefore))
+		fmt.Printf("  After:  %s\n", entities.FormatBytes(result.BytesAfter))
+		if saved >= 0 {
+			fmt.Printf("  Saved:  %s (%.1f%%)\n", entities.FormatBytes(saved), float64(saved)/float64(result.BytesBefore)*100)
+		} else {
+			fmt.Printf("  Grew:   %s\n", entities.FormatBytes(-saved))
+		}
+	}
+
+	if len(result.Failed) > 0 {
+		paths := make([]string, 0, len(result.Failed))
+		for storagePath := range result.Failed {
+			paths = append(paths, storagePath)
+		}
+		sort.Strings(paths)
+
+		fmt.Println()
+		if noColor {
+			fmt.Println("Failed:")
+		} else {
+			color.Red("Failed:")
+		}
+		for _, storagePath := range paths {
+			fmt.Printf("  %s: %s\n", storagePath, result.Failed[storagePath])
+		}
+	}
+}
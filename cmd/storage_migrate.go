@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	migrateBlobTo       string
+	migrateBlobDryRun   bool
+	migrateBlobJSON     bool
+	migrateBlobEndpoint string
+	migrateBlobBucket   string
+	migrateBlobRegion   string
+	migrateBlobPrefix   string
+)
+
+var storageMigrateAttachmentsCmd = &cobra.Command{
+	Use:   "migrate-attachments",
+	Short: "Move attachment content to another blob backend",
+	Long: `Move existing attachment content to another blob backend and rewrite each
+attachment's storage path. New attachments are written to the target backend
+once the migration succeeds.
+
+Backends:
+  local   plain files under .issuemap/attachments and .issuemap/dedup
+  lfs     git-LFS pointer files under .issuemap, content in .git/lfs/objects
+  s3      an S3-compatible object store (AWS S3, MinIO, ...)
+
+S3 credentials are read from storage.blob_store.s3 in the config or from the
+AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+
+Examples:
+  issuemap storage migrate-attachments --to lfs
+  issuemap storage migrate-attachments --to s3 --endpoint http://localhost:9000 --bucket issues
+  issuemap storage migrate-attachments --to local --dry-run`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runStorageMigrateAttachments(cmd, args)
+	},
+}
+
+func init() {
+	storageCmd.AddCommand(storageMigrateAttachmentsCmd)
+
+	storageMigrateAttachmentsCmd.Flags().StringVar(&migrateBlobTo, "to", "", "target backend (local, lfs, s3)")
+	storageMigrateAttachmentsCmd.Flags().BoolVar(&migrateBlobDryRun, "dry-run", false, "show what would be moved without moving it")
+	storageMigrateAttachmentsCmd.Flags().BoolVar(&migrateBlobJSON, "json", false, "output in JSON format")
+	storageMigrateAttachmentsCmd.Flags().StringVar(&migrateBlobEndpoint, "endpoint", "", "S3 endpoint URL")
+	storageMigrateAttachmentsCmd.Flags().StringVar(&migrateBlobBucket, "bucket", "", "S3 bucket")
+	storageMigrateAttachmentsCmd.Flags().StringVar(&migrateBlobRegion, "region", "", "S3 region (default us-east-1)")
+	storageMigrateAttachmentsCmd.Flags().StringVar(&migrateBlobPrefix, "prefix", "", "S3 key prefix")
+	if err := storageMigrateAttachmentsCmd.MarkFlagRequired("to"); err != nil {
+		return
+	}
+}
+
+func runStorageMigrateAttachments(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	target, err := entities.ParseBlobBackend(migrateBlobTo)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	configRepo := storage.NewFileConfigRepository(issuemapPath)
+	issueRepo := storage.NewFileIssueRepository(issuemapPath)
+	attachmentRepo := storage.NewFileAttachmentRepository(issuemapPath)
+
+	config, err := configRepo.Load(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to load config: %w", err))
+		return err
+	}
+	if config.StorageConfig == nil {
+		config.StorageConfig = entities.DefaultStorageConfig()
+	}
+
+	blobConfig := entities.DefaultBlobStoreConfig()
+	if config.StorageConfig.BlobStore != nil {
+		copied := *config.StorageConfig.BlobStore
+		blobConfig = &copied
+	}
+	if target == entities.BlobBackendS3 {
+		s3Config := &entities.S3BlobConfig{}
+		if blobConfig.S3 != nil {
+			copied := *blobConfig.S3
+			s3Config = &copied
+		}
+		if migrateBlobEndpoint != "" {
+			s3Config.Endpoint = migrateBlobEndpoint
+		}
+		if migrateBlobBucket != "" {
+			s3Config.Bucket = migrateBlobBucket
+		}
+		if migrateBlobRegion != "" {
+			s3Config.Region = migrateBlobRegion
+		}
+		if migrateBlobPrefix != "" {
+			s3Config.Prefix = migrateBlobPrefix
+		}
+		blobConfig.S3 = s3Config
+	}
+	blobConfig.Backend = target
+
+	targetStore, err := storage.NewBlobStore(issuemapPath, target, blobConfig)
+	if err != nil {
+		printError(fmt.Errorf("failed to open %s backend: %w", target, err))
+		return err
+	}
+
+	migrationService := services.NewBlobMigrationService(attachmentRepo, issueRepo)
+	result, err := migrationService.Migrate(ctx, targetStore, migrateBlobDryRun)
+	if err != nil {
+		printError(fmt.Errorf("failed to migrate attachments: %w", err))
+		return err
+	}
+
+	// Switch new uploads to the target once everything has moved
+	if !migrateBlobDryRun && len(result.Failed) == 0 {
+		config.StorageConfig.BlobStore = blobConfig
+		if err := configRepo.Save(ctx, config); err != nil {
+			printError(fmt.Errorf("failed to save config: %w", err))
+			return err
+		}
+	}
+
+	if migrateBlobJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		displayBlobMigrationResult(result)
+	}
+
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d blob(s) failed to migrate", len(result.Failed))
+	}
+	return nil
+}
+
+func displayBlobMigrationResult(result *entities.BlobMigrationResult) {
+	verb := "Moved"
+	if result.DryRun {
+		verb = "Would move"
+	}
+
+	fmt.Printf("%s %d blob(s) for %d attachment(s) (%s) to %s\n",
+		verb, result.Blobs, result.Attachments, entities.FormatBytes(result.Bytes), result.To)
+	if result.AlreadyThere > 0 {
+		printInfo(fmt.Sprintf("%d blob(s) already in %s", result.AlreadyThere, result.To))
+	}
+
+	if len(result.Failed) > 0 {
+		paths := make([]string, 0, len(result.Failed))
+		for storagePath := range result.Failed {
+			paths = append(paths, storagePath)
+		}
+		sort.Strings(paths)
+
+		fmt.Println()
+		if noColor {
+			fmt.Println("Failed:")
+		} else {
+			color.Red("Failed:")
+		}
+		for _, storagePath := range paths {
+			fmt.Printf("  %s: %s\n", storagePath, result.Failed[storagePath])
+		}
+		printWarning("New attachments still use the previous backend; fix the failures and re-run")
+		return
+	}
+
+	if !result.DryRun {
+		printSuccess(fmt.Sprintf("New attachments will be stored in %s", result.To))
+	}
+}
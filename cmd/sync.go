@@ -139,9 +139,22 @@ func runSync(cmd *cobra.Command, args []string) error {
 		printSuccess(fmt.Sprintf("Branch %s synced successfully", branch))
 	}
 
+	// Close issues whose pull requests were squash, rebase or merge landed
+	pullRequestService := services.NewPullRequestService(issueService, nil, gitClient)
+	landedCount := 0
+	if openPRs, err := pullRequestService.OpenPullRequests(ctx); err != nil {
+		printWarning(fmt.Sprintf("Failed to check pull requests: %v", err))
+	} else if len(openPRs) > 0 {
+		fmt.Printf("\nChecking %d open pull request(s)\n", len(openPRs))
+		landedCount = checkLandedPullRequests(ctx, pullRequestService, openPRs)
+	}
+
 	// Summary
 	fmt.Printf("\nSync Summary:\n")
 	fmt.Printf("  Branches synced: %d\n", syncedCount)
+	if landedCount > 0 {
+		fmt.Printf("  Pull requests landed: %d\n", landedCount)
+	}
 	if errorCount > 0 {
 		fmt.Printf("  Errors: %d\n", errorCount)
 	}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	timeCommitsIssue       string
+	timeCommitsSince       string
+	timeCommitsGap         int
+	timeCommitsLeadIn      int
+	timeCommitsDryRun      bool
+	timeCommitsHook        bool
+	timeCommitsInstallHook bool
+	timeCommitsForce       bool
+	timeCommitsJSON        bool
+)
+
+// timeFromCommitsCmd represents the time from-commits command
+var timeFromCommitsCmd = &cobra.Command{
+	Use:   "from-commits",
+	Short: "Estimate time spent on issues from git commits",
+	Long: `Create commit-type time entries from the commits linked to each issue, either
+by referencing the issue in the message or by being made on the issue's branch.
+
+Time is estimated per author with a session gap heuristic: a commit made within
+the session gap of the author's previous commit is credited with the time since
+that commit; the first commit of a session is credited with a fixed lead-in.
+Time the author already tracked with timers or manual entries in the same window
+is not counted again, and each commit is only recorded once per issue, so the
+command can be re-run safely.
+
+Defaults come from the time_tracking section of the config
+(commit_session_gap_minutes, commit_lead_in_minutes).
+
+Examples:
+  issuemap time from-commits                       # Backfill all issues
+  issuemap time from-commits --issue ISSUE-001     # A single issue
+  issuemap time from-commits --since 2024-01-01 --dry-run
+  issuemap time from-commits --gap 90 --lead-in 15
+  issuemap time from-commits --install-hook        # Record time after every commit`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTimeFromCommits(cmd, args)
+	},
+}
+
+func init() {
+	timeCmd.AddCommand(timeFromCommitsCmd)
+
+	timeFromCommitsCmd.Flags().StringVar(&timeCommitsIssue, "issue", "", "only estimate time for this issue")
+	timeFromCommitsCmd.Flags().StringVar(&timeCommitsSince, "since", "", "only consider commits since date (YYYY-MM-DD)")
+	timeFromCommitsCmd.Flags().IntVar(&timeCommitsGap, "gap", 0, "session gap in minutes (default from config, 120)")
+	timeFromCommitsCmd.Flags().IntVar(&timeCommitsLeadIn, "lead-in", -1, "minutes credited to the first commit of a session (default from config, 30)")
+	timeFromCommitsCmd.Flags().BoolVar(&timeCommitsDryRun, "dry-run", false, "show what would be recorded without writing entries")
+	timeFromCommitsCmd.Flags().BoolVar(&timeCommitsHook, "hook", false, "only record the HEAD commit (used by the post-commit hook)")
+	timeFromCommitsCmd.Flags().BoolVar(&timeCommitsInstallHook, "install-hook", false, "install a post-commit hook that runs --hook after each commit")
+	timeFromCommitsCmd.Flags().BoolVar(&timeCommitsForce, "force", false, "replace an existing post-commit hook not installed by issuemap")
+	timeFromCommitsCmd.Flags().BoolVar(&timeCommitsJSON, "json", false, "output in JSON format")
+}
+
+func runTimeFromCommits(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	gitClient, err := git.NewGitClient(repoPath)
+	if err != nil {
+		printError(fmt.Errorf("failed to initialize git client: %w", err))
+		return err
+	}
+
+	if timeCommitsInstallHook {
+		if err := gitClient.InstallPostCommitHook(ctx, timeCommitsForce); err != nil {
+			printError(fmt.Errorf("failed to install post-commit hook: %w", err))
+			return err
+		}
+		printSuccess("Installed post-commit hook")
+		return nil
+	}
+
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	timeRepo := storage.NewFileTimeEntryRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+	activeTimerRepo := storage.NewFileActiveTimerRepository(basePath)
+	historyRepo := storage.NewFileHistoryRepository(basePath)
+
+	issueService := services.NewIssueService(issueRepo, configRepo, gitClient)
+	historyService := services.NewHistoryService(historyRepo, gitClient)
+	timeService := services.NewTimeTrackingService(timeRepo, activeTimerRepo, issueService, historyService)
+	timeService.SetGitRepository(gitClient)
+
+	var timeConfig *entities.TimeTrackingConfig
+	if config, err := configRepo.Load(ctx); err == nil {
+		timeConfig = config.TimeTracking
+	}
+
+	opts := entities.CommitTimeOptions{
+		SessionGap: timeConfig.SessionGap(),
+		LeadIn:     timeConfig.LeadIn(),
+		DryRun:     timeCommitsDryRun,
+	}
+	if timeCommitsGap > 0 {
+		opts.SessionGap = time.Duration(timeCommitsGap) * time.Minute
+	}
+	if timeCommitsLeadIn >= 0 {
+		opts.LeadIn = time.Duration(timeCommitsLeadIn) * time.Minute
+	}
+	if timeCommitsIssue != "" {
+		issueID := normalizeIssueID(timeCommitsIssue)
+		opts.IssueID = &issueID
+	}
+	if timeCommitsSince != "" {
+		since, err := time.Parse("2006-01-02", timeCommitsSince)
+		if err != nil {
+			printError(fmt.Errorf("invalid since date format (use YYYY-MM-DD): %w", err))
+			return err
+		}
+		opts.Since = &since
+	}
+	if timeCommitsHook {
+		head, err := gitClient.GetLatestCommit(ctx)
+		if err != nil {
+			return err
+		}
+		opts.Hashes = []string{head.Hash}
+	}
+
+	result, err := timeService.EstimateFromCommits(ctx, opts)
+	if err != nil {
+		printError(fmt.Errorf("failed to estimate time from commits: %w", err))
+		return err
+	}
+
+	if timeCommitsJSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			printError(fmt.Errorf("failed to marshal JSON: %w", err))
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if timeCommitsHook {
+		return nil
+	}
+
+	displayCommitTimeResult(result)
+	return nil
+}
+
+func displayCommitTimeResult(result *entities.CommitTimeResult) {
+	if len(result.Entries) > 0 {
+		fmt.Printf("%-12s %-9s %-20s %8s %-16s %s\n", "Issue", "Commit", "Author", "Hours", "Date", "Message")
+		for _, entry := range result.Entries {
+			fmt.Printf("%-12s %-9s %-20s %8.2f %-16s %s\n",
+				entry.IssueID,
+				shortHash(entry.CommitHash),
+				truncateText(entry.Author, 20),
+				entry.GetDurationHours(),
+				entry.EndTime.Format("2006-01-02 15:04"),
+				truncateText(entry.Description, 40),
+			)
+		}
+		fmt.Println()
+	}
+
+	summary := fmt.Sprintf("%d commit(s) considered, %d entries, %.2f hours", result.Commits, len(result.Entries), result.TotalTime.Hours())
+	if result.DryRun {
+		printInfo("Dry run: " + summary)
+	} else if len(result.Entries) > 0 {
+		printSuccess("Recorded " + summary)
+	} else {
+		printInfo("Nothing new to record: " + summary)
+	}
+	if result.Existing > 0 {
+		fmt.Printf("  %d already recorded\n", result.Existing)
+	}
+	if result.Overlapped > 0 {
+		fmt.Printf("  %d fully covered by timer or manual entries\n", result.Overlapped)
+	}
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	timeEditHours       float64
+	timeEditDescription string
+)
+
+// timeEditCmd represents the time edit command
+var timeEditCmd = &cobra.Command{
+	Use:   "edit <entry-id>",
+	Short: "Edit a time entry",
+	Long: `Change the hours or description of a time entry. Entry IDs are shown by
+'issuemap timesheet --format csv' and 'issuemap report --format json'.
+
+Approved and locked entries cannot be edited.
+
+Examples:
+  issuemap time edit ISSUE-001-1700000000-123-abcd --hours 1.5
+  issuemap time edit ISSUE-001-1700000000-123-abcd --description "Code review"`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTimeEdit(cmd, args)
+	},
+}
+
+// timeDeleteCmd represents the time delete command
+var timeDeleteCmd = &cobra.Command{
+	Use:   "delete <entry-id>",
+	Short: "Delete a time entry",
+	Long: `Delete a time entry and remove its hours from the issue.
+
+Approved and locked entries cannot be deleted.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTimeDelete(cmd, args)
+	},
+}
+
+func init() {
+	timeCmd.AddCommand(timeEditCmd)
+	timeCmd.AddCommand(timeDeleteCmd)
+
+	timeEditCmd.Flags().Float64Var(&timeEditHours, "hours", 0, "new duration in hours")
+	timeEditCmd.Flags().StringVarP(&timeEditDescription, "description", "d", "", "new description")
+}
+
+func runTimeEdit(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var duration *time.Duration
+	if cmd.Flags().Changed("hours") {
+		d := time.Duration(timeEditHours * float64(time.Hour))
+		duration = &d
+	}
+	var description *string
+	if cmd.Flags().Changed("description") {
+		description = &timeEditDescription
+	}
+	if duration == nil && description == nil {
+		err := fmt.Errorf("nothing to change: use --hours or --description")
+		printError(err)
+		return err
+	}
+
+	timeService, _, _, err := newTimeTrackingContext(ctx)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	entry, err := timeService.EditTimeEntry(ctx, args[0], duration, description)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Updated time entry %s (%.2f hours)", entry.ID, entry.GetDurationHours()))
+	return nil
+}
+
+func runTimeDelete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	timeService, _, _, err := newTimeTrackingContext(ctx)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	if err := timeService.DeleteTimeEntry(ctx, args[0]); err != nil {
+		printError(err)
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Deleted time entry %s", args[0]))
+	return nil
+}
@@ -0,0 +1,376 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	timesheetPeriod  string
+	timesheetDate    string
+	timesheetGroupBy []string
+	timesheetAuthor  string
+	timesheetClient  string
+	timesheetFormat  string
+	timesheetOutput  string
+)
+
+// timesheetCmd represents the timesheet command
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Generate, approve and invoice weekly or monthly timesheets",
+	Long: `Generate timesheets of logged time for a week (Monday to Sunday) or month,
+grouped by author, billable client and/or issue, with billed hours rounded
+and priced according to the billing settings in the config:
+
+  time_tracking:
+    billing:
+      currency: EUR
+      default_rate: 100
+      user_rates: {alice: 120}
+      label_rates: {"client:acme": 150}   # takes precedence over user rates
+      client_label_prefix: "client:"      # label client:acme bills to "acme"
+      client_field: client                # or a custom field, checked first
+      rounding: {increment_minutes: 15, mode: up, minimum_minutes: 15}
+
+Entries move through draft → submitted → approved → locked. Approved and
+locked entries can no longer be edited or deleted; approved entries can be
+reopened to draft, locked entries are final.
+
+Examples:
+  issuemap timesheet                                   # This week, by author
+  issuemap timesheet --period month --date 2024-03-01 --group-by client,author
+  issuemap timesheet --client acme --format html -o invoice.html
+  issuemap timesheet --format csv -o week.csv
+  issuemap timesheet submit --author alice
+  issuemap timesheet approve --period month --date 2024-03-01
+  issuemap timesheet lock --period month --date 2024-03-01`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTimesheet(cmd, args)
+	},
+}
+
+// timesheetStatusCmds move all entries on a timesheet to a new approval state
+var timesheetStatusCmds = []*cobra.Command{
+	newTimesheetStatusCmd("submit", "Submit the timesheet's entries for approval", entities.TimeEntryStatusSubmitted),
+	newTimesheetStatusCmd("approve", "Approve the timesheet's submitted entries", entities.TimeEntryStatusApproved),
+	newTimesheetStatusCmd("lock", "Lock the timesheet's approved entries", entities.TimeEntryStatusLocked),
+	newTimesheetStatusCmd("reopen", "Return the timesheet's entries to draft", entities.TimeEntryStatusDraft),
+}
+
+func newTimesheetStatusCmd(use, short string, status entities.TimeEntryStatus) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTimesheetStatus(cmd, status)
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(timesheetCmd)
+
+	timesheetCmd.PersistentFlags().StringVar(&timesheetPeriod, "period", entities.TimesheetWeek, "timesheet period (week, month)")
+	timesheetCmd.PersistentFlags().StringVar(&timesheetDate, "date", "", "a date within the period (YYYY-MM-DD, default today)")
+	timesheetCmd.PersistentFlags().StringVar(&timesheetAuthor, "author", "", "only include time logged by this author")
+	timesheetCmd.PersistentFlags().StringVar(&timesheetClient, "client", "", "only include time billed to this client")
+	timesheetCmd.Flags().StringSliceVar(&timesheetGroupBy, "group-by", []string{entities.TimesheetGroupAuthor}, "group by author, client and/or issue")
+	timesheetCmd.Flags().StringVarP(&timesheetFormat, "format", "f", "table", "output format (table, csv, html, json)")
+	timesheetCmd.Flags().StringVarP(&timesheetOutput, "output", "o", "", "write to file instead of stdout")
+
+	for _, statusCmd := range timesheetStatusCmds {
+		timesheetCmd.AddCommand(statusCmd)
+	}
+}
+
+// newTimeTrackingContext creates the time tracking service and loads billing settings
+func newTimeTrackingContext(ctx context.Context) (*services.TimeTrackingService, *entities.BillingConfig, *git.GitClient, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+	timeRepo := storage.NewFileTimeEntryRepository(basePath)
+	activeTimerRepo := storage.NewFileActiveTimerRepository(basePath)
+	historyRepo := storage.NewFileHistoryRepository(basePath)
+
+	var gitClient *git.GitClient
+	if client, err := git.NewGitClient(repoPath); err == nil {
+		gitClient = client
+	}
+
+	var issueService *services.IssueService
+	var historyService *services.HistoryService
+	if gitClient != nil {
+		issueService = services.NewIssueService(issueRepo, configRepo, gitClient)
+		historyService = services.NewHistoryService(historyRepo, gitClient)
+	} else {
+		issueService = services.NewIssueService(issueRepo, configRepo, nil)
+		historyService = services.NewHistoryService(historyRepo, nil)
+	}
+	timeService := services.NewTimeTrackingService(timeRepo, activeTimerRepo, issueService, historyService)
+
+	var timeConfig *entities.TimeTrackingConfig
+	if config, err := configRepo.Load(ctx); err == nil {
+		timeConfig = config.TimeTracking
+	}
+
+	return timeService, timeConfig.BillingSettings(), gitClient, nil
+}
+
+// buildTimesheetOptions builds timesheet options from the command line
+func buildTimesheetOptions() (entities.TimesheetOptions, error) {
+	opts := entities.TimesheetOptions{
+		Period:  strings.ToLower(timesheetPeriod),
+		Date:    time.Now(),
+		GroupBy: timesheetGroupBy,
+		Author:  timesheetAuthor,
+		Client:  timesheetClient,
+	}
+	if timesheetDate != "" {
+		date, err := time.ParseInLocation("2006-01-02", timesheetDate, time.Local)
+		if err != nil {
+			return opts, fmt.Errorf("invalid date format (use YYYY-MM-DD): %w", err)
+		}
+		opts.Date = date
+	}
+	return opts, nil
+}
+
+func runTimesheet(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	timeService, billing, _, err := newTimeTrackingContext(ctx)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	opts, err := buildTimesheetOptions()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	sheet, err := timeService.BuildTimesheet(ctx, opts, billing)
+	if err != nil {
+		printError(fmt.Errorf("failed to build timesheet: %w", err))
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if timesheetOutput != "" {
+		file, err := os.Create(timesheetOutput)
+		if err != nil {
+			printError(fmt.Errorf("failed to create output file: %w", err))
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch strings.ToLower(timesheetFormat) {
+	case "table":
+		writeTimesheetTable(out, sheet)
+	case "csv":
+		err = writeTimesheetCSV(out, sheet)
+	case "html":
+		err = writeTimesheetHTML(out, sheet)
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(sheet)
+	default:
+		err = fmt.Errorf("unsupported format: %s (supported: table, csv, html, json)", timesheetFormat)
+	}
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	if timesheetOutput != "" {
+		printSuccess(fmt.Sprintf("Timesheet saved to %s", timesheetOutput))
+	}
+	return nil
+}
+
+func runTimesheetStatus(cmd *cobra.Command, status entities.TimeEntryStatus) error {
+	ctx := context.Background()
+
+	timeService, billing, gitClient, err := newTimeTrackingContext(ctx)
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	opts, err := buildTimesheetOptions()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	result, err := timeService.SetTimesheetStatus(ctx, opts, billing, status, getCurrentUser(gitClient))
+	if err != nil {
+		printError(fmt.Errorf("failed to update timesheet: %w", err))
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("%d entries now %s", len(result.Changed), status))
+	if len(result.Unchanged) > 0 {
+		printInfo(fmt.Sprintf("%d entries were already %s", len(result.Unchanged), status))
+	}
+	if len(result.Rejected) > 0 {
+		printWarning(fmt.Sprintf("%d entries could not be changed:", len(result.Rejected)))
+		for id, reason := range result.Rejected {
+			fmt.Printf("  %s: %s\n", id, reason)
+		}
+	}
+	return nil
+}
+
+// timesheetTitle describes the timesheet period
+func timesheetTitle(sheet *entities.Timesheet) string {
+	last := sheet.End.AddDate(0, 0, -1)
+	if sheet.Period == entities.TimesheetMonth {
+		return "Timesheet " + sheet.Start.Format("January 2006")
+	}
+	return fmt.Sprintf("Timesheet %s – %s", sheet.Start.Format("2006-01-02"), last.Format("2006-01-02"))
+}
+
+func writeTimesheetTable(w io.Writer, sheet *entities.Timesheet) {
+	fmt.Fprintf(w, "%s\n", timesheetTitle(sheet))
+	fmt.Fprintf(w, "%s\n\n", strings.Repeat("=", len(timesheetTitle(sheet))))
+
+	if len(sheet.Groups) == 0 {
+		fmt.Fprintln(w, "No time entries in this period.")
+		return
+	}
+
+	for _, group := range sheet.Groups {
+		fmt.Fprintf(w, "%s\n", group.Key)
+		for _, line := range group.Lines {
+			fmt.Fprintf(w, "  %-10s %-12s %-16s %6.2f %6.2f %9.2f  %-9s %s\n",
+				line.Date.Format("2006-01-02"),
+				line.IssueID,
+				truncateText(line.Author, 16),
+				line.Hours,
+				line.BilledHours,
+				line.Amount,
+				line.Status,
+				truncateText(line.Description, 30),
+			)
+		}
+		fmt.Fprintf(w, "  %-40s %6.2f %6.2f %9.2f\n\n", "Subtotal", group.Hours, group.BilledHours, group.Amount)
+	}
+
+	fmt.Fprintf(w, "Total: %.2f hours, %.2f billed, %.2f %s\n", sheet.Hours, sheet.BilledHours, sheet.Amount, sheet.Currency)
+}
+
+func writeTimesheetCSV(w io.Writer, sheet *entities.Timesheet) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"Group", "Date", "Client", "Issue ID", "Issue Title", "Author", "Description",
+		"Hours", "Billed Hours", "Rate", "Amount", "Currency", "Status", "Entry ID"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, group := range sheet.Groups {
+		for _, line := range group.Lines {
+			record := []string{
+				group.Key,
+				line.Date.Format("2006-01-02"),
+				line.Client,
+				string(line.IssueID),
+				line.IssueTitle,
+				line.Author,
+				line.Description,
+				fmt.Sprintf("%.2f", line.Hours),
+				fmt.Sprintf("%.2f", line.BilledHours),
+				fmt.Sprintf("%.2f", line.Rate),
+				fmt.Sprintf("%.2f", line.Amount),
+				sheet.Currency,
+				string(line.Status),
+				line.EntryID,
+			}
+			if err := writer.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV record: %w", err)
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// timesheetHTMLTemplate renders an invoice-ready timesheet
+var timesheetHTMLTemplate = template.Must(template.New("timesheet").Funcs(template.FuncMap{
+	"date":  func(t time.Time) string { return t.Format("2006-01-02") },
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { border-bottom: 1px solid #d0d7de; padding: 6px 8px; text-align: left; }
+td.num, th.num { text-align: right; }
+tr.subtotal td { font-weight: bold; border-top: 2px solid #24292f; }
+.total { font-size: 1.2em; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{date .Sheet.Start}} to {{date .Last}} &middot; generated {{date .Sheet.GeneratedAt}}</p>
+{{range .Sheet.Groups}}
+<h2>{{.Key}}</h2>
+<table>
+<tr><th>Date</th><th>Issue</th><th>Author</th><th>Description</th><th class="num">Hours</th><th class="num">Billed</th><th class="num">Rate</th><th class="num">Amount</th></tr>
+{{range .Lines}}<tr><td>{{date .Date}}</td><td>{{.IssueID}} {{.IssueTitle}}</td><td>{{.Author}}</td><td>{{.Description}}</td><td class="num">{{money .Hours}}</td><td class="num">{{money .BilledHours}}</td><td class="num">{{money .Rate}}</td><td class="num">{{money .Amount}}</td></tr>
+{{end}}<tr class="subtotal"><td colspan="4">Subtotal</td><td class="num">{{money .Hours}}</td><td class="num">{{money .BilledHours}}</td><td></td><td class="num">{{money .Amount}}</td></tr>
+</table>
+{{else}}
+<p>No time entries in this period.</p>
+{{end}}
+<p class="total">Total: {{money .Sheet.BilledHours}} billed hours &middot; {{money .Sheet.Amount}} {{.Sheet.Currency}}</p>
+</body>
+</html>
+`))
+
+func writeTimesheetHTML(w io.Writer, sheet *entities.Timesheet) error {
+	data := struct {
+		Title string
+		Last  time.Time
+		Sheet *entities.Timesheet
+	}{
+		Title: timesheetTitle(sheet),
+		Last:  sheet.End.AddDate(0, 0, -1),
+		Sheet: sheet,
+	}
+	if err := timesheetHTMLTemplate.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to render HTML: %w", err)
+	}
+	return nil
+}
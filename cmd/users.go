@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	usersAddName         string
+	usersAddEmails       []string
+	usersAddAliases      []string
+	usersAddTeams        []string
+	usersAddCapacity     float64
+	usersAddTimezone     string
+	usersListTeam        string
+	usersNormalizeDry    bool
+	usersNormalizeReseal bool
+)
+
+// usersCmd groups the user directory commands
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Manage the team and user directory",
+	Long: `Manage the user directory in the users section of the config.
+
+The same person often appears as "jdoe", "John Doe" and "jdoe@corp" depending on
+their git config. The directory maps every name, email and alias of a person to
+one canonical handle, which is written to assignees, comments, history and time
+entries. It also records team membership, weekly capacity and timezone; search
+with assignee:@team to find the issues of a team's members.
+
+  users:
+    - handle: jdoe
+      name: John Doe
+      emails: [jdoe@corp.example]
+      aliases: [johnd]
+      teams: [team-backend]
+      capacity: 32
+      timezone: Europe/Berlin`,
+}
+
+// usersListCmd lists the directory
+var usersListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the people in the directory",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runUsersList(cmd, args)
+	},
+}
+
+// usersAddCmd adds or updates a person
+var usersAddCmd = &cobra.Command{
+	Use:   "add <handle>",
+	Short: "Add a person, or add identities and teams to one",
+	Long: `Add a person to the directory. When the handle exists, the given name, capacity
+and timezone replace the current ones and emails, aliases and teams are added.
+
+Examples:
+  issuemap users add jdoe --name "John Doe" --email jdoe@corp.example --team team-backend
+  issuemap users add jdoe --alias johnd --capacity 32 --timezone Europe/Berlin`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runUsersAdd(cmd, args)
+	},
+}
+
+// usersMergeCmd merges an identity into a person
+var usersMergeCmd = &cobra.Command{
+	Use:   "merge <from> <into>",
+	Short: "Merge an identity or person into another person",
+	Long: `Make <from> an identity of <into> and rewrite the stored data to use <into>'s
+handle. <from> may be a person in the directory, whose profile is folded into
+<into>'s, or any name or email found in the data.
+
+Examples:
+  issuemap users merge "John Doe" jdoe
+  issuemap users merge johnd jdoe`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runUsersMerge(cmd, args)
+	},
+}
+
+// usersNormalizeCmd rewrites existing data to canonical handles
+var usersNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Rewrite stored identities to canonical handles",
+	Long: `Rewrite the assignees, comment and commit authors of issues and the authors
+of time entries and timers to the handles of the user directory. New data is
+resolved as it is written; run this once after setting up the directory, or
+after adding identities. Identities that are not in the directory are listed
+so they can be added or merged. Approved and locked time entries are not
+changed; they are listed instead.
+
+History entries are sealed into a hash chain, so they keep the author they
+were recorded with and an entry mapping those authors to handles is added to
+the history. --reseal rewrites the authors instead and recomputes the hash
+chains, dropping any signatures; the rewrite is recorded in the history.
+
+Examples:
+  issuemap users normalize --dry-run
+  issuemap users normalize
+  issuemap users normalize --reseal`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runUsersNormalize(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(usersCmd)
+	usersCmd.AddCommand(usersListCmd)
+	usersCmd.AddCommand(usersAddCmd)
+	usersCmd.AddCommand(usersMergeCmd)
+	usersCmd.AddCommand(usersNormalizeCmd)
+
+	usersListCmd.Flags().StringVar(&usersListTeam, "team", "", "only list the members of a team")
+
+	usersAddCmd.Flags().StringVar(&usersAddName, "name", "", "display name")
+	usersAddCmd.Flags().StringSliceVar(&usersAddEmails, "email", nil, "email address (repeatable)")
+	usersAddCmd.Flags().StringSliceVar(&usersAddAliases, "alias", nil, "other name the person appears under (repeatable)")
+	usersAddCmd.Flags().StringSliceVar(&usersAddTeams, "team", nil, "team the person is in (repeatable)")
+	usersAddCmd.Flags().Float64Var(&usersAddCapacity, "capacity", 0, "hours per week available for issues")
+	usersAddCmd.Flags().StringVar(&usersAddTimezone, "timezone", "", "IANA timezone, such as Europe/Berlin")
+
+	usersNormalizeCmd.Flags().BoolVar(&usersNormalizeDry, "dry-run", false, "show what would change without writing")
+	usersNormalizeCmd.Flags().BoolVar(&usersNormalizeReseal, "reseal", false, "rewrite history authors and recompute the history hash chains")
+}
+
+// newUserService wires the user service for the current repository
+func newUserService() (*services.UserService, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, fmt.Errorf("not in a git repository: %w", err)
+	}
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	return services.NewUserService(
+		storage.NewFileConfigRepository(basePath),
+		storage.NewFileIssueRepository(basePath),
+		storage.NewFileHistoryRepository(basePath),
+		storage.NewFileTimeEntryRepository(basePath),
+		storage.NewFileActiveTimerRepository(basePath),
+	), nil
+}
+
+func runUsersList(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	userService, err := newUserService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+	directory, err := userService.Directory(ctx)
+	if err != nil {
+		printError(fmt.Errorf("failed to load the user directory: %w", err))
+		return err
+	}
+
+	users := append([]entities.UserProfile(nil), directory.Users()...)
+	if usersListTeam != "" {
+		var members []entities.UserProfile
+		for _, user := range users {
+			if user.InTeam(usersListTeam) {
+				members = append(members, user)
+			}
+		}
+		users = members
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Handle < users[j].Handle })
+
+	if format == "json" {
+		if users == nil {
+			users = []entities.UserProfile{}
+		}
+		return outputJSON(users)
+	}
+
+	if len(users) == 0 {
+		printInfo("No users in the directory. Add one with: issuemap users add <handle>")
+		return nil
+	}
+
+	printSectionHeader(fmt.Sprintf("Users (%d)", len(users)))
+	for _, user := range users {
+		fmt.Printf("  %s", user.Handle)
+		if user.Name != "" {
+			fmt.Printf("  %s", user.Name)
+		}
+		if len(user.Emails) > 0 {
+			fmt.Printf("  <%s>", strings.Join(user.Emails, ", "))
+		}
+		fmt.Println()
+		if len(user.Aliases) > 0 {
+			fmt.Printf("      aliases:  %s\n", strings.Join(user.Aliases, ", "))
+		}
+		if len(user.Teams) > 0 {
+			fmt.Printf("      teams:    %s\n", strings.Join(user.Teams, ", "))
+		}
+		if user.Capacity > 0 {
+			fmt.Printf("      capacity: %gh/week\n", user.Capacity)
+		}
+		if user.Timezone != "" {
+			fmt.Printf("      timezone: %s\n", user.Timezone)
+		}
+	}
+	if teams := directory.TeamNames(); len(teams) > 0 && usersListTeam == "" {
+		fmt.Println()
+		fmt.Printf("Teams: %s\n", strings.Join(teams, ", "))
+	}
+	return nil
+}
+
+func runUsersAdd(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if usersAddTimezone != "" {
+		if _, err := time.LoadLocation(usersAddTimezone); err != nil {
+			err = fmt.Errorf("unknown timezone %q: %w", usersAddTimezone, err)
+			printError(err)
+			return err
+		}
+	}
+	if usersAddCapacity < 0 {
+		err := fmt.Errorf("capacity cannot be negative")
+		printError(err)
+		return err
+	}
+
+	userService, err := newUserService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	profile := entities.UserProfile{
+		Handle:   args[0],
+		Name:     usersAddName,
+		Emails:   usersAddEmails,
+		Aliases:  usersAddAliases,
+		Teams:    usersAddTeams,
+		Capacity: usersAddCapacity,
+		Timezone: usersAddTimezone,
+	}
+	created, err := userService.Add(ctx, profile)
+	if err != nil {
+		printError(fmt.Errorf("failed to add user: %w", err))
+		return err
+	}
+
+	handle := strings.TrimPrefix(args[0], "@")
+	if created {
+		printSuccess(fmt.Sprintf("Added %s to the user directory", handle))
+	} else {
+		printSuccess(fmt.Sprintf("Updated %s in the user directory", handle))
+	}
+	printInfo("Run 'issuemap users normalize' to rewrite existing data to the handle")
+	return nil
+}
+
+func runUsersMerge(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	userService, err := newUserService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	result, err := userService.Merge(ctx, args[0], args[1])
+	if err != nil {
+		printError(fmt.Errorf("failed to merge users: %w", err))
+		return err
+	}
+
+	if format == "json" {
+		return outputJSON(result)
+	}
+	printSuccess(fmt.Sprintf("Merged %s into %s", args[0], strings.TrimPrefix(args[1], "@")))
+	printUserNormalization(result)
+	return nil
+}
+
+func runUsersNormalize(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	userService, err := newUserService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	result, err := userService.Normalize(ctx, usersNormalizeDry, usersNormalizeReseal)
+	if err != nil {
+		printError(fmt.Errorf("failed to normalize users: %w", err))
+		return err
+	}
+
+	if format == "json" {
+		return outputJSON(result)
+	}
+	printUserNormalization(result)
+	return nil
+}
+
+func printUserNormalization(result *entities.UserNormalization) {
+	if len(result.Renamed) > 0 {
+		printSectionHeader("Identities resolved")
+		identities := make([]string, 0, len(result.Renamed))
+		for identity := range result.Renamed {
+			identities = append(identities, identity)
+		}
+		sort.Strings(identities)
+		for _, identity := range identities {
+			fmt.Printf("  %s -> %s\n", identity, result.Renamed[identity])
+		}
+		fmt.Println()
+	}
+
+	if len(result.Unknown) > 0 {
+		printWarning("Not in the user directory: " + strings.Join(result.Unknown, ", "))
+		fmt.Println("  Add them with 'issuemap users add' or 'issuemap users merge <identity> <handle>'")
+		fmt.Println()
+	}
+
+	if len(result.LockedTimeEntries) > 0 {
+		printWarning(fmt.Sprintf("%d approved or locked time entry(s) left unchanged: %s",
+			len(result.LockedTimeEntries), strings.Join(result.LockedTimeEntries, ", ")))
+		fmt.Println("  Approved entries can be reopened with 'issuemap timesheet reopen'; locked entries are final")
+		fmt.Println()
+	}
+
+	summary := fmt.Sprintf("%d issue(s), %d time entry(s) and %d timer(s)",
+		result.Issues, result.TimeEntries, result.Timers)
+	history := fmt.Sprintf("the handles of %d history entry(s) recorded in %d history(s)",
+		result.HistoryEntries, result.Histories)
+	if result.Resealed {
+		history = fmt.Sprintf("%d history entry(s) rewritten and %d history(s) resealed",
+			result.HistoryEntries, result.Histories)
+	}
+	if result.DryRun {
+		printInfo(fmt.Sprintf("Dry run: would rewrite %s; %s", summary, history))
+		return
+	}
+	printSuccess(fmt.Sprintf("Rewrote %s; %s", summary, history))
+}
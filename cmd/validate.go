@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	validateStaged      bool
+	validateJSON        bool
+	validateInstallHook bool
+	validateForce       bool
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate [paths...]",
+	Short: "Check hand-edited issue files before committing them",
+	Long: `Validate issue, history, dependency, time entry and config files under
+.issuemap. Each file is checked against its schema (unknown fields, values of
+the wrong type, invalid types, statuses and priorities, duplicate comment IDs)
+and its references are cross-checked: dependencies and time entries must point
+to existing issues and attachments to existing blobs.
+
+Problems are reported with the file, line and column they were found at.
+Without paths every file under .issuemap is validated. The command exits
+non-zero when errors are found.
+
+Use --install-hook to run 'validate --staged' from a pre-commit hook, next to
+the commit-msg hook installed by 'issuemap init'.
+
+Examples:
+  issuemap validate                                  # Validate all files
+  issuemap validate .issuemap/issues/ISSUE-001.yaml  # Validate one file
+  issuemap validate --staged                         # Validate staged files
+  issuemap validate --install-hook                   # Install the pre-commit hook`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Invalid files are a validation result, not a usage error, and
+		// the report already describes them
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return runValidate(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().BoolVar(&validateStaged, "staged", false, "validate .issuemap files as staged for commit")
+	validateCmd.Flags().BoolVar(&validateJSON, "json", false, "output problems as JSON")
+	validateCmd.Flags().BoolVar(&validateInstallHook, "install-hook", false, "install a pre-commit hook that validates staged files")
+	validateCmd.Flags().BoolVar(&validateForce, "force", false, "replace an existing pre-commit hook not installed by issuemap")
+}
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	repoPath, err := findGitRoot()
+	if err != nil {
+		printError(fmt.Errorf("not in a git repository: %w", err))
+		return err
+	}
+
+	if validateInstallHook {
+		gitClient, err := git.NewGitClient(repoPath)
+		if err != nil {
+			printError(fmt.Errorf("failed to initialize git client: %w", err))
+			return err
+		}
+		if err := gitClient.InstallPreCommitHook(ctx, validateForce); err != nil {
+			printError(fmt.Errorf("failed to install pre-commit hook: %w", err))
+			return err
+		}
+		printSuccess("Installed pre-commit hook")
+		return nil
+	}
+
+	if validateStaged && len(args) > 0 {
+		err := fmt.Errorf("cannot specify paths when using --staged")
+		printError(err)
+		return err
+	}
+
+	basePath := filepath.Join(repoPath, app.ConfigDirName)
+	validationService := services.NewValidationService(basePath, storage.NewFileAttachmentRepository(basePath))
+
+	var report *entities.ValidationReport
+	switch {
+	case validateStaged:
+		report, err = validateStagedFiles(ctx, validationService, repoPath)
+	case len(args) > 0:
+		report, err = validationService.ValidateFiles(ctx, args)
+	default:
+		report, err = validationService.ValidateAll(ctx)
+	}
+	if err != nil {
+		printError(fmt.Errorf("failed to validate files: %w", err))
+		return err
+	}
+
+	if validateJSON {
+		if err := outputJSON(report); err != nil {
+			return err
+		}
+	} else {
+		displayValidationReport(report)
+	}
+
+	if !report.Valid() {
+		return fmt.Errorf("validation failed with %d error(s)", report.Errors)
+	}
+	return nil
+}
+
+// validateStagedFiles validates the index content of staged .issuemap files
+func validateStagedFiles(ctx context.Context, validationService *services.ValidationService, repoPath string) (*entities.ValidationReport, error) {
+	gitClient, err := git.NewGitClient(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize git client: %w", err)
+	}
+
+	files, err := gitClient.StagedFiles(ctx, app.ConfigDirName)
+	if err != nil {
+		return nil, err
+	}
+
+	report := entities.NewValidationReport()
+	for _, file := range files {
+		relPath := strings.TrimPrefix(file, app.ConfigDirName+"/")
+		if _, ok := entities.SchemaKindForPath(relPath); !ok {
+			continue
+		}
+		data, err := gitClient.ReadStagedFile(ctx, file)
+		if err != nil {
+			return nil, err
+		}
+		validationService.ValidateContent(ctx, report, file, relPath, data)
+	}
+	report.Sort()
+	return report, nil
+}
+
+func displayValidationReport(report *entities.ValidationReport) {
+	for _, problem := range report.Problems {
+		fmt.Println(problem.String())
+	}
+	if len(report.Problems) > 0 {
+		fmt.Println()
+	}
+
+	summary := fmt.Sprintf("%d file(s) checked, %d error(s), %d warning(s)",
+		report.FilesChecked, report.Errors, report.Warnings)
+	switch {
+	case !report.Valid():
+		printError(fmt.Errorf("%s", summary))
+	case report.Warnings > 0:
+		printWarning(summary)
+	default:
+		printSuccess(summary)
+	}
+}
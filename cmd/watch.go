@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var (
+	notificationsAll      bool
+	notificationsMarkRead bool
+)
+
+// watchCmd subscribes a person to an issue
+var watchCmd = &cobra.Command{
+	Use:   "watch <issue-id> [user]",
+	Short: "Watch an issue to be notified about its activity",
+	Long: `Subscribe to an issue. Watchers are notified when the issue is commented on or
+changes status. You watch the issues you create, comment on or are assigned
+to, and the issues you are @mentioned in, without running this command.
+
+Examples:
+  issuemap watch ISSUE-001              # Watch as the current git user
+  issuemap watch 001 jdoe               # Subscribe someone else
+  issuemap list --watching              # The issues you watch
+  issuemap notifications                # What happened on them`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runWatch(cmd, args, true)
+	},
+}
+
+// unwatchCmd unsubscribes a person from an issue
+var unwatchCmd = &cobra.Command{
+	Use:   "unwatch <issue-id> [user]",
+	Short: "Stop watching an issue",
+	Long: `Unsubscribe from an issue. Being mentioned or assigned again subscribes you
+again.
+
+Examples:
+  issuemap unwatch ISSUE-001
+  issuemap unwatch 001 jdoe`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runWatch(cmd, args, false)
+	},
+}
+
+// notificationsCmd lists the notifications of the current user
+var notificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Show notifications about the issues you watch",
+	Long: `Show your unread notifications: @mentions, assignments, comments and status
+changes on the issues you watch. Notifications are stored in .issuemap and
+travel with the repository, so they reach you after a pull.
+
+Examples:
+  issuemap notifications
+  issuemap notifications --mark-read
+  issuemap notifications --all`,
+	Aliases: []string{"inbox"},
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return runNotifications(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(unwatchCmd)
+	rootCmd.AddCommand(notificationsCmd)
+
+	notificationsCmd.Flags().BoolVar(&notificationsAll, "all", false, "include notifications already read")
+	notificationsCmd.Flags().BoolVar(&notificationsMarkRead, "mark-read", false, "mark the shown notifications as read")
+}
+
+// newWatchIssueService wires the issue service and returns the current user
+func newWatchIssueService() (*services.IssueService, string, error) {
+	repoPath, err := findGitRoot()
+	if err != nil {
+		return nil, "", fmt.Errorf("not in a git repository: %w", err)
+	}
+
+	issuemapPath := filepath.Join(repoPath, app.ConfigDirName)
+	issueRepo := storage.NewFileIssueRepository(issuemapPath)
+	configRepo := storage.NewFileConfigRepository(issuemapPath)
+
+	var gitRepo *git.GitClient
+	if gitClient, err := git.NewGitClient(repoPath); err == nil {
+		gitRepo = gitClient
+	}
+
+	return services.NewIssueService(issueRepo, configRepo, gitRepo), getCurrentUser(gitRepo), nil
+}
+
+func runWatch(cmd *cobra.Command, args []string, watch bool) error {
+	ctx := context.Background()
+	issueID := normalizeIssueID(args[0])
+
+	issueService, user, err := newWatchIssueService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+	if len(args) > 1 {
+		user = issueService.ResolveIdentity(ctx, args[1])
+	}
+
+	if watch {
+		added, err := issueService.Watch(ctx, issueID, user)
+		if err != nil {
+			printError(fmt.Errorf("failed to watch issue: %w", err))
+			return err
+		}
+		if added {
+			printSuccess(fmt.Sprintf("%s is now watching %s", user, issueID))
+		} else {
+			printInfo(fmt.Sprintf("%s is already watching %s", user, issueID))
+		}
+		return nil
+	}
+
+	removed, err := issueService.Unwatch(ctx, issueID, user)
+	if err != nil {
+		printError(fmt.Errorf("failed to unwatch issue: %w", err))
+		return err
+	}
+	if removed {
+		printSuccess(fmt.Sprintf("%s stopped watching %s", user, issueID))
+	} else {
+		printInfo(fmt.Sprintf("%s is not watching %s", user, issueID))
+	}
+	return nil
+}
+
+func runNotifications(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	issueService, user, err := newWatchIssueService()
+	if err != nil {
+		printError(err)
+		return err
+	}
+
+	notifications, err := issueService.Notifications(ctx, user, !notificationsAll)
+	if err != nil {
+		printError(fmt.Errorf("failed to load notifications: %w", err))
+		return err
+	}
+
+	if notificationsMarkRead && len(notifications) > 0 {
+		ids := make([]string, 0, len(notifications))
+		for _, notification := range notifications {
+			ids = append(ids, notification.ID)
+		}
+		if _, err := issueService.MarkNotificationsRead(ctx, user, ids); err != nil {
+			printError(fmt.Errorf("failed to mark notifications read: %w", err))
+			return err
+		}
+	}
+
+	if format == "json" {
+		if notifications == nil {
+			notifications = []*entities.IssueNotification{}
+		}
+		return outputJSON(notifications)
+	}
+
+	if len(notifications) == 0 {
+		if notificationsAll {
+			printInfo(fmt.Sprintf("No notifications for %s", user))
+		} else {
+			printInfo(fmt.Sprintf("No unread notifications for %s", user))
+		}
+		return nil
+	}
+
+	printSectionHeader(fmt.Sprintf("Notifications for %s (%d)", user, len(notifications)))
+	titles := make(map[entities.IssueID]string)
+	for _, notification := range notifications {
+		title, ok := titles[notification.IssueID]
+		if !ok {
+			if issue, err := issueService.GetIssue(ctx, notification.IssueID); err == nil && !issue.Redacted {
+				title = issue.Title
+			}
+			titles[notification.IssueID] = title
+		}
+
+		marker := "*"
+		if notification.Read {
+			marker = " "
+		}
+		fmt.Printf("%s %s  %s\n", marker, notification.CreatedAt.Format("2006-01-02 15:04"), notification.Summary())
+		if title != "" {
+			fmt.Printf("      %s\n", title)
+		}
+	}
+
+	if notificationsMarkRead {
+		fmt.Println()
+		printSuccess(fmt.Sprintf("Marked %d notification(s) as read", len(notifications)))
+	}
+	return nil
+}
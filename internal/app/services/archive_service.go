@@ -2,6 +2,7 @@ package services
 
 import (
 	"archive/tar"
+	"bytes"
 	"compress/gzip"
 	"context"
 	"crypto/sha256"
@@ -213,6 +214,7 @@ func (s *ArchiveService) RestoreIssues(ctx context.Context, issueIDs []entities.
 
 			// Remove from index
 			delete(index.Issues, issue.ID)
+			index.TotalIssues--
 		}
 	}
 
@@ -383,6 +385,46 @@ func (s *ArchiveService) RunAutoArchive(ctx context.Context) (*entities.ArchiveR
 	return s.ArchiveIssues(ctx, filter, false)
 }
 
+// ScheduledJobs returns the automatic archiving job
+func (s *ArchiveService) ScheduledJobs() []*Job {
+	return []*Job{
+		{
+			Name:        "archive",
+			Description: "Archive closed issues older than the configured threshold",
+			Schedule:    "0 3 * * *",
+			Enabled:     s.ShouldAutoArchive,
+			Run: func(ctx context.Context) (string, error) {
+				result, err := s.RunAutoArchive(ctx)
+				if err != nil {
+					return "", err
+				}
+				if result == nil || result.IssuesArchived == 0 {
+					return "no issues to archive", nil
+				}
+				return fmt.Sprintf("%d issues archived, %s compressed (%.1f%% compression)",
+					result.IssuesArchived, formatBytes(result.CompressedSize), result.CompressionRatio*100), nil
+			},
+		},
+		{
+			Name:        "index-rebuild",
+			Description: "Rebuild the archive index from the archive files",
+			Schedule:    "30 4 * * 0",
+			Run: func(ctx context.Context) (string, error) {
+				result, err := s.RebuildIndex(ctx)
+				if err != nil {
+					return "", err
+				}
+				message := fmt.Sprintf("%d issues indexed from %d archives, %d added, %d removed",
+					result.Issues, result.Archives, len(result.Added), len(result.Removed))
+				if len(result.Errors) > 0 {
+					return message, fmt.Errorf("%d archives could not be read", len(result.Errors))
+				}
+				return message, nil
+			},
+		},
+	}
+}
+
 // GetArchiveStats returns statistics about archives
 func (s *ArchiveService) GetArchiveStats() (*entities.ArchiveStats, error) {
 	s.mu.RLock()
@@ -442,6 +484,281 @@ func (s *ArchiveService) ListArchives() ([]string, error) {
 	return archives, nil
 }
 
+// RebuildIndex recreates the archive index from the archive files, so that an
+// index lost or left stale by an interrupted archive run matches the archives
+// again. Issues that have been restored to active storage stay out of the
+// index, and entries keep their recorded archive time and author.
+func (s *ArchiveService) RebuildIndex(ctx context.Context) (*entities.ArchiveIndexRebuildResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadIndex(); err != nil {
+		// An unreadable index is what a rebuild is for
+		s.index = entities.NewArchiveIndex()
+	}
+	previous := s.index
+
+	files, err := filepath.Glob(filepath.Join(s.archivePath, "archive_*.tar.gz"))
+	if err != nil {
+		return nil, errors.Wrap(err, "ArchiveService.RebuildIndex", "list_archives")
+	}
+	sort.Strings(files)
+
+	result := &entities.ArchiveIndexRebuildResult{}
+	index := entities.NewArchiveIndex()
+	for _, path := range files {
+		archiveFile := filepath.Base(path)
+		contents, err := s.readArchiveContents(path)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", archiveFile, err))
+			if issueIDs, ok := previous.Archives[archiveFile]; ok {
+				index.Archives[archiveFile] = issueIDs
+			}
+			for id, entry := range previous.Issues {
+				if entry.ArchiveFile == archiveFile {
+					index.Issues[id] = entry
+				}
+			}
+			continue
+		}
+		result.Archives++
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "ArchiveService.RebuildIndex", "stat_archive")
+		}
+		index.Archives[archiveFile] = contents.issueIDs
+		index.TotalCompressedSize += info.Size()
+		index.TotalOriginalSize += contents.originalSize
+
+		for _, issue := range contents.issues {
+			// Restored issues remain in their archive but are no longer archived
+			if exists, err := s.issueRepo.Exists(ctx, issue.ID); err == nil && exists {
+				continue
+			}
+
+			entry := &entities.ArchiveEntry{
+				IssueID:        issue.ID,
+				Title:          issue.Title,
+				Type:           issue.Type,
+				Status:         issue.Status,
+				ArchiveFile:    archiveFile,
+				Files:          contents.files[issue.ID],
+				CompressedSize: info.Size() / int64(len(contents.issues)),
+				OriginalSize:   contents.originalSize / int64(len(contents.issues)),
+				ArchivedAt:     info.ModTime(),
+				ArchivedBy:     "system",
+				CreatedAt:      issue.Timestamps.Created,
+				Checksum:       contents.checksum,
+			}
+			if issue.Timestamps.Closed != nil {
+				entry.ClosedAt = issue.Timestamps.Closed.UTC()
+			}
+			if old, ok := previous.Issues[issue.ID]; ok && old.ArchiveFile == archiveFile {
+				entry.ArchivedAt = old.ArchivedAt
+				entry.ArchivedBy = old.ArchivedBy
+			}
+			// Archives are read oldest first, so a re-archived issue points at its latest archive
+			index.Issues[issue.ID] = entry
+		}
+	}
+
+	for id := range index.Issues {
+		if _, ok := previous.Issues[id]; !ok {
+			result.Added = append(result.Added, id)
+		}
+	}
+	for id := range previous.Issues {
+		if _, ok := index.Issues[id]; !ok {
+			result.Removed = append(result.Removed, id)
+		}
+	}
+	sort.Slice(result.Added, func(i, j int) bool { return result.Added[i] < result.Added[j] })
+	sort.Slice(result.Removed, func(i, j int) bool { return result.Removed[i] < result.Removed[j] })
+
+	index.TotalIssues = len(index.Issues)
+	result.Issues = index.TotalIssues
+	s.index = index
+	if err := s.saveIndex(); err != nil {
+		return nil, errors.Wrap(err, "ArchiveService.RebuildIndex", "save_index")
+	}
+	return result, nil
+}
+
+// archiveContents is what an archive file holds, read back for the index
+type archiveContents struct {
+	issues       []*entities.Issue
+	issueIDs     []entities.IssueID
+	files        map[entities.IssueID][]string
+	originalSize int64
+	checksum     string
+}
+
+// readArchiveContents reads every issue of an archive and recomputes the
+// checksum written when the archive was created
+func (s *ArchiveService) readArchiveContents(path string) (*archiveContents, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gzr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gzr.Close()
+
+	contents := &archiveContents{files: make(map[entities.IssueID][]string)}
+	hasher := sha256.New()
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		hasher.Write(data)
+		contents.originalSize += int64(len(data))
+
+		switch {
+		case strings.HasPrefix(header.Name, "issues/") && strings.HasSuffix(header.Name, ".json"):
+			var issue entities.Issue
+			if err := json.Unmarshal(data, &issue); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", header.Name, err)
+			}
+			contents.issues = append(contents.issues, &issue)
+			contents.issueIDs = append(contents.issueIDs, issue.ID)
+			contents.files[issue.ID] = append(contents.files[issue.ID], header.Name)
+		case strings.HasPrefix(header.Name, "attachments/"):
+			issueID := entities.IssueID(strings.SplitN(strings.TrimPrefix(header.Name, "attachments/"), "/", 2)[0])
+			contents.files[issueID] = append(contents.files[issueID], header.Name)
+		}
+	}
+
+	contents.checksum = hex.EncodeToString(hasher.Sum(nil))
+	return contents, nil
+}
+
+// ReloadIndex reads the archive index from disk again, picking up archives
+// written by other processes
+func (s *ArchiveService) ReloadIndex() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadIndex(); err != nil {
+		return errors.Wrap(err, "ArchiveService.ReloadIndex", "load_index")
+	}
+	return nil
+}
+
+// ListArchiveSummaries describes every archive file, newest first
+func (s *ArchiveService) ListArchiveSummaries() ([]*entities.ArchiveSummary, error) {
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+
+	if index == nil {
+		return nil, errors.New("ArchiveService.ListArchiveSummaries", "no_index", fmt.Errorf("archive index not loaded"))
+	}
+
+	summaries := make([]*entities.ArchiveSummary, 0, len(index.Archives))
+	for archiveFile, issueIDs := range index.Archives {
+		summary := &entities.ArchiveSummary{
+			File:        archiveFile,
+			IssueIDs:    []entities.IssueID{},
+			TotalIssues: len(issueIDs),
+		}
+		for _, id := range issueIDs {
+			entry, ok := index.Issues[id]
+			if !ok || entry.ArchiveFile != archiveFile {
+				continue
+			}
+			summary.IssueIDs = append(summary.IssueIDs, id)
+			if entry.ArchivedAt.After(summary.ArchivedAt) {
+				summary.ArchivedAt = entry.ArchivedAt
+			}
+		}
+		if info, err := os.Stat(filepath.Join(s.archivePath, archiveFile)); err == nil {
+			summary.Size = info.Size()
+			if summary.ArchivedAt.IsZero() {
+				summary.ArchivedAt = info.ModTime()
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].ArchivedAt.Equal(summaries[j].ArchivedAt) {
+			return summaries[i].ArchivedAt.After(summaries[j].ArchivedAt)
+		}
+		return summaries[i].File > summaries[j].File
+	})
+	return summaries, nil
+}
+
+// ListArchivedIssues returns the index entries of archived issues, most
+// recently archived first. An empty archiveFile lists every archive.
+func (s *ArchiveService) ListArchivedIssues(archiveFile string) ([]*entities.ArchiveEntry, error) {
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+
+	if index == nil {
+		return nil, errors.New("ArchiveService.ListArchivedIssues", "no_index", fmt.Errorf("archive index not loaded"))
+	}
+
+	entries := []*entities.ArchiveEntry{}
+	for _, entry := range index.Issues {
+		if archiveFile != "" && entry.ArchiveFile != archiveFile {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].ArchivedAt.Equal(entries[j].ArchivedAt) {
+			return entries[i].ArchivedAt.After(entries[j].ArchivedAt)
+		}
+		return entries[i].IssueID < entries[j].IssueID
+	})
+	return entries, nil
+}
+
+// GetArchivedIssue reads an archived issue without restoring it
+func (s *ArchiveService) GetArchivedIssue(ctx context.Context, issueID entities.IssueID) (*entities.Issue, *entities.ArchiveEntry, error) {
+	s.mu.RLock()
+	index := s.index
+	s.mu.RUnlock()
+
+	if index == nil {
+		return nil, nil, errors.New("ArchiveService.GetArchivedIssue", "no_index", fmt.Errorf("archive index not loaded"))
+	}
+
+	entry, exists := index.Issues[issueID]
+	if !exists {
+		return nil, nil, errors.Wrap(errors.ErrNotFound, "ArchiveService.GetArchivedIssue", "not_archived")
+	}
+
+	issues, err := s.extractIssuesFromArchive(ctx, entry.ArchiveFile, []entities.IssueID{issueID})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "ArchiveService.GetArchivedIssue", "extract")
+	}
+	if len(issues) == 0 {
+		return nil, nil, errors.New("ArchiveService.GetArchivedIssue", "missing_issue",
+			fmt.Errorf("issue %s not found in %s", issueID, entry.ArchiveFile))
+	}
+
+	return issues[0], entry, nil
+}
+
 // findIssuesForArchival finds issues matching filter criteria
 func (s *ArchiveService) findIssuesForArchival(ctx context.Context, filter *entities.ArchiveFilter) ([]*entities.Issue, error) {
 	// Build issue filter
@@ -464,6 +781,12 @@ func (s *ArchiveService) findIssuesForArchival(ctx context.Context, filter *enti
 	now := time.Now()
 
 	for _, issue := range issueList.Issues {
+		// Archives are not encrypted, so confidential issues stay in active
+		// storage where their content remains sealed
+		if issue.Confidential {
+			continue
+		}
+
 		// Skip if specific IDs provided and this isn't one of them
 		if len(filter.IssueIDs) > 0 {
 			found := false
@@ -534,7 +857,9 @@ func (s *ArchiveService) calculateIssuesSize(issues []*entities.Issue) (int64, e
 		// Size of attachments
 		for _, attachment := range issue.Attachments {
 			if s.config.IncludeAttachments {
-				if info, err := os.Stat(filepath.Join(s.basePath, attachment.StoragePath)); err == nil {
+				if !entities.IsLocalStoragePath(attachment.StoragePath) {
+					totalSize += attachment.Size
+				} else if info, err := os.Stat(filepath.Join(s.basePath, attachment.StoragePath)); err == nil {
 					totalSize += info.Size()
 				}
 			}
@@ -628,23 +953,39 @@ func (s *ArchiveService) addIssueToArchive(tw *tar.Writer, hasher io.Writer, iss
 
 // addAttachmentToArchive adds an attachment file to the archive
 func (s *ArchiveService) addAttachmentToArchive(tw *tar.Writer, hasher io.Writer, attachment entities.Attachment) error {
-	attachmentPath := filepath.Join(s.basePath, attachment.StoragePath)
+	var file io.Reader
+	var size int64
 
-	file, err := os.Open(attachmentPath)
-	if err != nil {
-		return err // Skip missing attachments
-	}
-	defer file.Close()
+	if entities.IsLocalStoragePath(attachment.StoragePath) {
+		localFile, err := os.Open(filepath.Join(s.basePath, attachment.StoragePath))
+		if err != nil {
+			return err // Skip missing attachments
+		}
+		defer localFile.Close()
 
-	info, err := file.Stat()
-	if err != nil {
-		return err
+		info, err := localFile.Stat()
+		if err != nil {
+			return err
+		}
+		file, size = localFile, info.Size()
+	} else {
+		// Remote blobs are buffered since tar headers need the size up front
+		content, err := s.attachmentRepo.GetFile(context.Background(), attachment.StoragePath)
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+		file, size = bytes.NewReader(data), int64(len(data))
 	}
 
 	header := &tar.Header{
 		Name: fmt.Sprintf("attachments/%s/%s", attachment.IssueID, attachment.Filename),
 		Mode: 0644,
-		Size: info.Size(),
+		Size: size,
 	}
 
 	if err := tw.WriteHeader(header); err != nil {
@@ -716,6 +1057,13 @@ func (s *ArchiveService) updateIndexWithArchivedIssues(issues []*entities.Issue,
 func (s *ArchiveService) removeIssueFromActiveStorage(ctx context.Context, issue *entities.Issue) error {
 	// Delete attachments
 	for _, attachment := range issue.Attachments {
+		if attachment.Thumbnail != nil {
+			s.attachmentRepo.DeleteFile(ctx, attachment.Thumbnail.StoragePath) // Thumbnails are regenerated on demand
+		}
+		if !entities.IsLocalStoragePath(attachment.StoragePath) {
+			s.attachmentRepo.DeleteFile(ctx, attachment.StoragePath) // Ignore errors for missing blobs
+			continue
+		}
 		attachmentPath := filepath.Join(s.basePath, attachment.StoragePath)
 		os.Remove(attachmentPath) // Ignore errors for missing files
 	}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestArchiveService_RebuildIndex(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	configRepo := storage.NewFileConfigRepository(basePath)
+	require.NoError(t, configRepo.Initialize(ctx, entities.NewDefaultConfig()))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	attachmentRepo := storage.NewFileAttachmentRepository(basePath)
+
+	for _, id := range []entities.IssueID{"TEST-001", "TEST-002", "TEST-003"} {
+		issue := entities.NewIssue(id, "Old "+string(id), "", entities.IssueTypeTask)
+		issue.UpdateStatus(entities.StatusClosed)
+		require.NoError(t, issueRepo.Create(ctx, issue))
+	}
+
+	archiveService := NewArchiveService(basePath, issueRepo, configRepo, attachmentRepo)
+	archived, err := archiveService.ArchiveIssues(ctx, &entities.ArchiveFilter{IssueIDs: []entities.IssueID{"TEST-001", "TEST-002"}}, false)
+	require.NoError(t, err)
+	require.Equal(t, 2, archived.IssuesArchived)
+	_, original, err := archiveService.GetArchivedIssue(ctx, "TEST-001")
+	require.NoError(t, err)
+	_, err = archiveService.RestoreIssue(ctx, "TEST-002", false)
+	require.NoError(t, err)
+
+	// Rebuilding an intact index changes nothing
+	result, err := archiveService.RebuildIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Archives)
+	assert.Equal(t, 1, result.Issues)
+	assert.Empty(t, result.Added)
+	assert.Empty(t, result.Removed)
+
+	// A lost index is recreated from the archive, leaving out restored issues
+	require.NoError(t, os.Remove(filepath.Join(basePath, "archives", "index.json")))
+	archiveService = NewArchiveService(basePath, issueRepo, configRepo, attachmentRepo)
+	_, _, err = archiveService.GetArchivedIssue(ctx, "TEST-001")
+	require.Error(t, err)
+
+	result, err = archiveService.RebuildIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.IssueID{"TEST-001"}, result.Added)
+	assert.Empty(t, result.Errors)
+
+	issue, entry, err := archiveService.GetArchivedIssue(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Equal(t, "Old TEST-001", issue.Title)
+	assert.Equal(t, original.ArchiveFile, entry.ArchiveFile)
+	assert.Equal(t, original.Checksum, entry.Checksum, "checksum is recomputed from the archive contents")
+	assert.Equal(t, []string{"issues/TEST-001.json"}, entry.Files)
+
+	summaries, err := archiveService.ListArchiveSummaries()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 2, summaries[0].TotalIssues)
+	assert.Equal(t, []entities.IssueID{"TEST-001"}, summaries[0].IssueIDs)
+
+	// Entries for archives that are gone are dropped, corrupt archives keep theirs
+	require.NoError(t, os.WriteFile(filepath.Join(basePath, "archives", "archive_2000-01-01_000000.tar.gz"), []byte("not gzip"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(basePath, "archives", original.ArchiveFile)))
+	result, err = archiveService.RebuildIndex(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.IssueID{"TEST-001"}, result.Removed)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, 0, result.Issues)
+
+	jobs := archiveService.ScheduledJobs()
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "index-rebuild", jobs[1].Name)
+	message, err := jobs[1].Run(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "0 issues indexed from 0 archives, 0 added, 0 removed", message)
+}
+
+func TestArchiveService_BrowseAndRestore(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	configRepo := storage.NewFileConfigRepository(basePath)
+	require.NoError(t, configRepo.Initialize(ctx, entities.NewDefaultConfig()))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	attachmentRepo := storage.NewFileAttachmentRepository(basePath)
+
+	for _, id := range []entities.IssueID{"TEST-001", "TEST-002"} {
+		issue := entities.NewIssue(id, "Old "+string(id), "", entities.IssueTypeTask)
+		issue.UpdateStatus(entities.StatusClosed)
+		require.NoError(t, issueRepo.Create(ctx, issue))
+	}
+
+	archiveService := NewArchiveService(basePath, issueRepo, configRepo, attachmentRepo)
+	_, err := archiveService.ArchiveIssues(ctx, &entities.ArchiveFilter{IssueIDs: []entities.IssueID{"TEST-001", "TEST-002"}}, false)
+	require.NoError(t, err)
+
+	summaries, err := archiveService.ListArchiveSummaries()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, 2, summaries[0].TotalIssues)
+	assert.ElementsMatch(t, []entities.IssueID{"TEST-001", "TEST-002"}, summaries[0].IssueIDs)
+	assert.Positive(t, summaries[0].Size)
+
+	entries, err := archiveService.ListArchivedIssues(summaries[0].File)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+	entries, err = archiveService.ListArchivedIssues("archive_missing.tar.gz")
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// Reading an archived issue leaves it in the archive
+	issue, entry, err := archiveService.GetArchivedIssue(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Equal(t, "Old TEST-001", issue.Title)
+	assert.Equal(t, summaries[0].File, entry.ArchiveFile)
+	_, err = issueRepo.GetByID(ctx, "TEST-001")
+	assert.Error(t, err, "reading an archived issue does not restore it")
+
+	_, _, err = archiveService.GetArchivedIssue(ctx, "TEST-999")
+	assert.Error(t, err)
+
+	result, err := archiveService.RestoreIssue(ctx, "TEST-002", false)
+	require.NoError(t, err)
+	assert.Equal(t, []entities.IssueID{"TEST-002"}, result.RestoredIssues)
+	assert.Empty(t, result.Errors)
+
+	restored, err := issueRepo.GetByID(ctx, "TEST-002")
+	require.NoError(t, err)
+	assert.Equal(t, "Old TEST-002", restored.Title)
+
+	// The restored issue leaves the index, and the change is saved
+	archiveService = NewArchiveService(basePath, issueRepo, configRepo, attachmentRepo)
+	stats, err := archiveService.GetArchiveStats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.TotalArchivedIssues)
+
+	summaries, err = archiveService.ListArchiveSummaries()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, []entities.IssueID{"TEST-001"}, summaries[0].IssueIDs)
+	_, _, err = archiveService.GetArchivedIssue(ctx, "TEST-002")
+	assert.Error(t, err)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// BlobMigrationService moves attachment content between blob backends
+type BlobMigrationService struct {
+	attachmentRepo repositories.AttachmentRepository
+	issueRepo      repositories.IssueRepository
+}
+
+// NewBlobMigrationService creates a new blob migration service
+func NewBlobMigrationService(attachmentRepo repositories.AttachmentRepository, issueRepo repositories.IssueRepository) *BlobMigrationService {
+	return &BlobMigrationService{
+		attachmentRepo: attachmentRepo,
+		issueRepo:      issueRepo,
+	}
+}
+
+// Migrate copies every attachment blob that is not yet in the target store,
+// verifies the copy, rewrites the storage path of each attachment referencing
+// it and then removes the source blob. Deduplicated blobs shared by several
+// attachments are moved once.
+func (s *BlobMigrationService) Migrate(ctx context.Context, target repositories.BlobStore, dryRun bool) (*entities.BlobMigrationResult, error) {
+	attachments, err := s.attachmentRepo.ListAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "BlobMigrationService.Migrate", "list_attachments")
+	}
+
+	result := &entities.BlobMigrationResult{
+		To:     target.Backend(),
+		Failed: make(map[string]string),
+		DryRun: dryRun,
+	}
+
+	// Group attachments by the blob they reference
+	byPath := make(map[string][]*entities.Attachment)
+	for _, attachment := range attachments {
+		if attachment.StoragePath == "" {
+			continue
+		}
+		byPath[attachment.StoragePath] = append(byPath[attachment.StoragePath], attachment)
+	}
+
+	paths := make([]string, 0, len(byPath))
+	for storagePath := range byPath {
+		paths = append(paths, storagePath)
+	}
+	sort.Strings(paths)
+
+	for _, storagePath := range paths {
+		refs := byPath[storagePath]
+		backend, _ := entities.SplitStoragePath(storagePath)
+		if backend == target.Backend() {
+			result.AlreadyThere++
+			continue
+		}
+
+		if dryRun {
+			result.Blobs++
+			result.Attachments += len(refs)
+			result.Bytes += refs[0].Size
+			continue
+		}
+
+		newPath, size, err := s.moveBlob(ctx, target, storagePath)
+		if err != nil {
+			result.Failed[storagePath] = err.Error()
+			continue
+		}
+
+		// Thumbnails follow their blob; one that fails to move is dropped and
+		// regenerated on demand
+		oldThumbnail := refs[0].Thumbnail
+		thumbnail := oldThumbnail
+		if oldThumbnail != nil {
+			thumbPath, _, err := s.moveBlob(ctx, target, oldThumbnail.StoragePath)
+			if err != nil {
+				thumbnail = nil
+			} else {
+				thumbnail = &entities.AttachmentThumbnail{StoragePath: thumbPath, Width: oldThumbnail.Width, Height: oldThumbnail.Height}
+			}
+		}
+
+		if err := s.rewriteStoragePath(ctx, refs, newPath, thumbnail); err != nil {
+			result.Failed[storagePath] = err.Error()
+			continue
+		}
+
+		// Local and LFS blobs share a path on disk, so the copy has already
+		// replaced the source
+		if !sharesLocalPath(backend, target.Backend()) {
+			s.attachmentRepo.DeleteFile(ctx, storagePath)
+			if oldThumbnail != nil && thumbnail != nil {
+				s.attachmentRepo.DeleteFile(ctx, oldThumbnail.StoragePath)
+			}
+		}
+
+		result.Blobs++
+		result.Attachments += len(refs)
+		result.Bytes += size
+	}
+
+	if len(result.Failed) == 0 {
+		result.Failed = nil
+	}
+
+	return result, nil
+}
+
+// moveBlob copies the blob at storagePath into the target store and verifies it
+func (s *BlobMigrationService) moveBlob(ctx context.Context, target repositories.BlobStore, storagePath string) (string, int64, error) {
+	_, key := entities.SplitStoragePath(storagePath)
+
+	content, err := s.attachmentRepo.GetFile(ctx, storagePath)
+	if err != nil {
+		return "", 0, fmt.Errorf("read source: %w", err)
+	}
+	defer content.Close()
+
+	counter := &countingReader{reader: content}
+	newPath, err := target.Put(ctx, key, counter)
+	if err != nil {
+		return "", 0, fmt.Errorf("write target: %w", err)
+	}
+
+	exists, err := target.Exists(ctx, key)
+	if err != nil {
+		return "", 0, fmt.Errorf("verify target: %w", err)
+	}
+	if !exists {
+		return "", 0, fmt.Errorf("verify target: blob not found after upload")
+	}
+
+	return newPath, counter.n, nil
+}
+
+// rewriteStoragePath points attachments, and the copies embedded in their issues, at a new storage path
+func (s *BlobMigrationService) rewriteStoragePath(ctx context.Context, refs []*entities.Attachment, newPath string, thumbnail *entities.AttachmentThumbnail) error {
+	for _, attachment := range refs {
+		attachment.StoragePath = newPath
+		attachment.Thumbnail = thumbnail
+		if err := s.attachmentRepo.SaveMetadata(ctx, attachment); err != nil {
+			return fmt.Errorf("save metadata for %s: %w", attachment.ID, err)
+		}
+
+		issue, err := s.issueRepo.GetByID(ctx, attachment.IssueID)
+		if err != nil {
+			continue // Attachment metadata is authoritative; the issue may be archived
+		}
+		for i := range issue.Attachments {
+			if issue.Attachments[i].ID == attachment.ID {
+				issue.Attachments[i].StoragePath = newPath
+				issue.Attachments[i].Thumbnail = thumbnail
+			}
+		}
+		if err := s.issueRepo.Update(ctx, issue); err != nil {
+			return fmt.Errorf("update issue %s: %w", issue.ID, err)
+		}
+	}
+	return nil
+}
+
+// sharesLocalPath reports whether two backends keep their files at the same path on disk
+func sharesLocalPath(a, b entities.BlobBackend) bool {
+	onDisk := func(backend entities.BlobBackend) bool {
+		return backend == entities.BlobBackendLocal || backend == entities.BlobBackendLFS
+	}
+	return onDisk(a) && onDisk(b)
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
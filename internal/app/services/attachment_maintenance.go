@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ooyeku/issuemap/internal/domain/entities"
 	"github.com/ooyeku/issuemap/internal/domain/repositories"
 )
 
@@ -41,8 +42,14 @@ func (m *AttachmentMaintenance) CleanupOrphanedFiles(ctx context.Context, dryRun
 				attachmentID := strings.TrimSuffix(file.Name(), ".yaml")
 
 				// Get attachment metadata to find storage path
+				// LFS pointer files live at the key of their storage path
 				if attachment, err := m.attachmentRepo.GetMetadata(ctx, attachmentID); err == nil {
-					validAttachments[attachment.StoragePath] = true
+					_, key := entities.SplitStoragePath(attachment.StoragePath)
+					validAttachments[filepath.FromSlash(key)] = true
+					if attachment.Thumbnail != nil {
+						_, thumbKey := entities.SplitStoragePath(attachment.Thumbnail.StoragePath)
+						validAttachments[filepath.FromSlash(thumbKey)] = true
+					}
 				}
 			}
 		}
@@ -114,8 +121,7 @@ func (m *AttachmentMaintenance) CleanupOrphanedMetadata(ctx context.Context, dry
 		}
 
 		// Check if corresponding file exists
-		fullPath := filepath.Join(m.basePath, attachment.StoragePath)
-		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+		if !m.blobExists(ctx, attachment.StoragePath) {
 			log.Printf("Found orphaned metadata: %s (file: %s)", attachmentID, attachment.StoragePath)
 			orphanedCount++
 
@@ -193,6 +199,14 @@ func (m *AttachmentMaintenance) ValidateAttachmentIntegrity(ctx context.Context)
 			continue
 		}
 
+		// Remote blobs can only be checked for presence
+		if !entities.IsLocalStoragePath(attachment.StoragePath) {
+			if !m.blobExists(ctx, attachment.StoragePath) {
+				corruptedFiles[attachmentID] = fmt.Errorf("blob not found: %s", attachment.StoragePath)
+			}
+			continue
+		}
+
 		// Check if file exists and has correct size
 		fullPath := filepath.Join(m.basePath, attachment.StoragePath)
 		info, err := os.Stat(fullPath)
@@ -201,7 +215,8 @@ func (m *AttachmentMaintenance) ValidateAttachmentIntegrity(ctx context.Context)
 			continue
 		}
 
-		if info.Size() != attachment.Size {
+		// Encrypted content is larger than the original
+		if !attachment.IsEncrypted() && info.Size() != attachment.Size {
 			corruptedFiles[attachmentID] =
 				fmt.Errorf("file size mismatch: expected %d, got %d", attachment.Size, info.Size())
 		}
@@ -210,6 +225,21 @@ func (m *AttachmentMaintenance) ValidateAttachmentIntegrity(ctx context.Context)
 	return corruptedFiles, nil
 }
 
+// blobExists reports whether the content of a storage path can be read
+func (m *AttachmentMaintenance) blobExists(ctx context.Context, storagePath string) bool {
+	if entities.IsLocalStoragePath(storagePath) {
+		_, err := os.Stat(filepath.Join(m.basePath, storagePath))
+		return !os.IsNotExist(err)
+	}
+
+	content, err := m.attachmentRepo.GetFile(ctx, storagePath)
+	if err != nil {
+		return false
+	}
+	content.Close()
+	return true
+}
+
 // GetMaintenanceStats returns statistics about attachment storage
 func (m *AttachmentMaintenance) GetMaintenanceStats(ctx context.Context) (*MaintenanceStats, error) {
 	stats := &MaintenanceStats{
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// AttachmentScanner inspects uploaded content for malware
+type AttachmentScanner interface {
+	// Scan returns the verdict for content; an error verdict is returned
+	// rather than an error when the scanner itself fails
+	Scan(ctx context.Context, filename string, content io.Reader) *entities.AttachmentScan
+}
+
+// CommandScanner runs a ClamAV-compatible command with the content on stdin
+type CommandScanner struct {
+	command string
+	args    []string
+	timeout time.Duration
+}
+
+// NewCommandScanner creates a command scanner from configuration
+func NewCommandScanner(config *entities.AttachmentScannerConfig) *CommandScanner {
+	return &CommandScanner{
+		command: config.Command,
+		args:    config.Args,
+		timeout: config.Timeout(),
+	}
+}
+
+// Scan pipes content to the scanner command and interprets its exit status
+func (c *CommandScanner) Scan(ctx context.Context, filename string, content io.Reader) *entities.AttachmentScan {
+	result := &entities.AttachmentScan{
+		Scanner:   filepath.Base(c.command),
+		ScannedAt: time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.command, c.args...)
+	cmd.Stdin = content
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	err := cmd.Run()
+	switch {
+	case err == nil:
+		result.Verdict = entities.ScanVerdictClean
+	case ctx.Err() == context.DeadlineExceeded:
+		result.Verdict = entities.ScanVerdictError
+		result.Detail = fmt.Sprintf("scanner timed out after %s", c.timeout)
+	default:
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			result.Verdict = entities.ScanVerdictInfected
+			result.Signature = parseScannerSignature(output.String())
+		} else {
+			result.Verdict = entities.ScanVerdictError
+			result.Detail = strings.TrimSpace(firstLine(output.String()))
+			if result.Detail == "" {
+				result.Detail = err.Error()
+			}
+		}
+	}
+
+	return result
+}
+
+// parseScannerSignature extracts the signature name from ClamAV output such
+// as "stdin: Eicar-Test-Signature FOUND"
+func parseScannerSignature(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasSuffix(line, " FOUND") {
+			continue
+		}
+		line = strings.TrimSuffix(line, " FOUND")
+		if idx := strings.LastIndex(line, ": "); idx != -1 {
+			line = line[idx+2:]
+		}
+		return line
+	}
+	return ""
+}
+
+// firstLine returns the first non-empty line of output
+func firstLine(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			return line
+		}
+	}
+	return ""
+}
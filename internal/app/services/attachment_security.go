@@ -1,20 +1,31 @@
 package services
 
 import (
+	"bytes"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"mime"
+	"net/http"
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
 )
 
+// sniffLength is the number of leading bytes inspected for content detection
+const sniffLength = 512
+
 // AttachmentSecurity provides security validations for attachments
 type AttachmentSecurity struct {
 	maxFileSize       int64
 	allowedMimeTypes  map[string]bool
 	blockedExtensions map[string]bool
 	filenamePattern   *regexp.Regexp
+	sniffContent      bool
+	sanitizeSVG       bool
+	scannerConfig     *entities.AttachmentScannerConfig
 }
 
 // NewAttachmentSecurity creates a new attachment security validator
@@ -82,9 +93,66 @@ func NewAttachmentSecurity() *AttachmentSecurity {
 		},
 		// Only allow alphanumeric, dots, dashes, underscores, and spaces
 		filenamePattern: regexp.MustCompile(`^[a-zA-Z0-9._\- ]+$`),
+		sniffContent:    true,
+		sanitizeSVG:     true,
 	}
 }
 
+// NewAttachmentSecurityFromConfig creates a validator with the project's size
+// limit and attachment policy applied over the built-in rules
+func NewAttachmentSecurityFromConfig(config *entities.StorageConfig) *AttachmentSecurity {
+	s := NewAttachmentSecurity()
+	if config == nil {
+		return s
+	}
+
+	if config.MaxAttachmentSize > 0 {
+		s.maxFileSize = config.MaxAttachmentSize
+	}
+
+	policy := config.AttachmentPolicy
+	if policy == nil {
+		return s
+	}
+
+	for _, mimeType := range policy.AllowMimeTypes {
+		s.allowedMimeTypes[normalizeMimeType(mimeType)] = true
+	}
+	for _, mimeType := range policy.BlockMimeTypes {
+		s.allowedMimeTypes[normalizeMimeType(mimeType)] = false
+	}
+	for _, ext := range policy.AllowExtensions {
+		delete(s.blockedExtensions, normalizeExtension(ext))
+	}
+	for _, ext := range policy.BlockExtensions {
+		s.blockedExtensions[normalizeExtension(ext)] = true
+	}
+
+	s.sniffContent = !policy.DisableSniffing
+	s.sanitizeSVG = !policy.DisableSVGSanitizing
+	s.scannerConfig = policy.Scanner
+
+	return s
+}
+
+// Scanner returns the configured malware scanner, or nil if none is configured
+func (s *AttachmentSecurity) Scanner() AttachmentScanner {
+	if s.scannerConfig == nil || s.scannerConfig.Command == "" {
+		return nil
+	}
+	return NewCommandScanner(s.scannerConfig)
+}
+
+// ScannerRequired reports whether uploads must be rejected when scanning fails
+func (s *AttachmentSecurity) ScannerRequired() bool {
+	return s.scannerConfig != nil && s.scannerConfig.Required
+}
+
+// MaxFileSize returns the maximum allowed attachment size
+func (s *AttachmentSecurity) MaxFileSize() int64 {
+	return s.maxFileSize
+}
+
 // ValidateFile validates an attachment file
 func (s *AttachmentSecurity) ValidateFile(filename string, size int64, reader io.Reader) error {
 	// Validate size
@@ -108,12 +176,54 @@ func (s *AttachmentSecurity) ValidateFile(filename string, size int64, reader io
 		return err
 	}
 
-	// Additional content validation could be added here
-	// For example, checking file magic numbers
+	// Validate the actual content against its magic bytes
+	if reader != nil && s.sniffContent {
+		head := make([]byte, sniffLength)
+		n, err := io.ReadFull(reader, head)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("failed to read file content: %w", err)
+		}
+		if _, err := s.DetectContentType(filename, head[:n]); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+// DetectContentType determines the content type of an attachment from its
+// leading bytes, falling back to the type implied by its extension. It fails
+// when the content is an executable or sniffs as a type the policy blocks, so
+// a renamed binary or HTML page is caught regardless of its extension.
+func (s *AttachmentSecurity) DetectContentType(filename string, head []byte) (string, error) {
+	declared := mime.TypeByExtension(filepath.Ext(filename))
+	if declared == "" {
+		declared = "application/octet-stream"
+	}
+	if !s.sniffContent {
+		return declared, nil
+	}
+
+	if kind := executableSignature(head); kind != "" {
+		return "", fmt.Errorf("file content is a %s executable, which is not allowed", kind)
+	}
+
+	sniffed := normalizeMimeType(http.DetectContentType(head))
+	if sniffedTypeMatches(normalizeMimeType(declared), sniffed) {
+		return declared, nil
+	}
+
+	if err := s.ValidateMimeType(sniffed); err != nil {
+		return "", fmt.Errorf("file content does not match its extension: %w", err)
+	}
+	return sniffed, nil
+}
+
+// SanitizeSVGEnabled reports whether SVG images are sanitized before serving
+func (s *AttachmentSecurity) SanitizeSVGEnabled() bool {
+	return s.sanitizeSVG
+}
+
 // ValidateSize checks if file size is within limits
 func (s *AttachmentSecurity) ValidateSize(size int64) error {
 	if size <= 0 {
@@ -170,11 +280,7 @@ func (s *AttachmentSecurity) ValidateMimeType(mimeType string) error {
 		return nil // Allow empty MIME type, will be set to octet-stream
 	}
 
-	// Strip parameters from MIME type
-	if idx := strings.Index(mimeType, ";"); idx != -1 {
-		mimeType = mimeType[:idx]
-	}
-	mimeType = strings.TrimSpace(strings.ToLower(mimeType))
+	mimeType = normalizeMimeType(mimeType)
 
 	// Check if explicitly allowed or blocked
 	if allowed, exists := s.allowedMimeTypes[mimeType]; exists {
@@ -232,3 +338,199 @@ func (s *AttachmentSecurity) ValidateStoragePath(path string) error {
 
 	return nil
 }
+
+// normalizeMimeType strips parameters and lower-cases a MIME type
+func normalizeMimeType(mimeType string) string {
+	if idx := strings.Index(mimeType, ";"); idx != -1 {
+		mimeType = mimeType[:idx]
+	}
+	return strings.TrimSpace(strings.ToLower(mimeType))
+}
+
+// normalizeExtension lower-cases an extension and ensures its leading dot
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// executableSignature returns the executable format identified by the magic
+// bytes at the start of content, or "" if it is not an executable
+func executableSignature(head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, []byte("MZ")):
+		return "Windows"
+	case bytes.HasPrefix(head, []byte("\x7fELF")):
+		return "ELF"
+	case bytes.HasPrefix(head, []byte{0xfe, 0xed, 0xfa, 0xce}),
+		bytes.HasPrefix(head, []byte{0xfe, 0xed, 0xfa, 0xcf}),
+		bytes.HasPrefix(head, []byte{0xce, 0xfa, 0xed, 0xfe}),
+		bytes.HasPrefix(head, []byte{0xcf, 0xfa, 0xed, 0xfe}):
+		return "Mach-O"
+	case bytes.HasPrefix(head, []byte("#!")):
+		return "script"
+	}
+	return ""
+}
+
+// sniffedTypeMatches reports whether a sniffed content type is consistent with
+// the declared one. Sniffing only recognizes a handful of formats, so generic
+// results are consistent with any more specific declared type in their family.
+func sniffedTypeMatches(declared, sniffed string) bool {
+	if declared == sniffed || sniffed == "application/octet-stream" {
+		return true
+	}
+
+	switch sniffed {
+	case "text/plain":
+		return strings.HasPrefix(declared, "text/") ||
+			declared == "application/json" ||
+			declared == "application/xml" ||
+			declared == "application/x-yaml" ||
+			declared == "image/svg+xml" ||
+			declared == "application/octet-stream"
+	case "text/xml":
+		return declared == "application/xml" || declared == "image/svg+xml"
+	case "application/zip":
+		// Office Open XML documents are zip containers
+		return strings.HasPrefix(declared, "application/vnd.openxmlformats-officedocument.")
+	}
+	return false
+}
+
+// svgDangerousElements are removed from SVG documents together with their content
+var svgDangerousElements = map[string]bool{
+	"script":        true,
+	"foreignobject": true,
+	"iframe":        true,
+	"embed":         true,
+	"object":        true,
+}
+
+// svgAnimationElements can set attributes of other elements while the
+// document runs
+var svgAnimationElements = map[string]bool{
+	"set":              true,
+	"animate":          true,
+	"animatetransform": true,
+	"animatemotion":    true,
+}
+
+// SanitizeSVG strips scripts, event handlers, embedded HTML and javascript:
+// links from an SVG document so it can be served inline safely. Animations
+// of link attributes are removed too, as they can set a javascript: link
+// that no attribute in the document shows.
+func SanitizeSVG(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var out bytes.Buffer
+	var open []string
+	skipDepth := 0
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid SVG: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			open = append(open, xmlName(t.Name))
+			if skipDepth > 0 || svgDangerousElements[strings.ToLower(t.Name.Local)] || animatesSVGLink(t) {
+				skipDepth++
+				continue
+			}
+			out.WriteString("<" + xmlName(t.Name))
+			for _, attr := range t.Attr {
+				if unsafeSVGAttribute(attr) {
+					continue
+				}
+				out.WriteString(" " + xmlName(attr.Name) + `="`)
+				xml.EscapeText(&out, []byte(attr.Value))
+				out.WriteString(`"`)
+			}
+			out.WriteString(">")
+		case xml.EndElement:
+			// RawToken does not check nesting
+			if len(open) == 0 || open[len(open)-1] != xmlName(t.Name) {
+				return nil, fmt.Errorf("invalid SVG: unexpected end element </%s>", xmlName(t.Name))
+			}
+			open = open[:len(open)-1]
+			if skipDepth > 0 {
+				skipDepth--
+				continue
+			}
+			out.WriteString("</" + xmlName(t.Name) + ">")
+		case xml.CharData:
+			if skipDepth == 0 {
+				xml.EscapeText(&out, t)
+			}
+		case xml.Comment:
+			if skipDepth == 0 {
+				out.WriteString("<!--" + strings.ReplaceAll(string(t), "--", "") + "-->")
+			}
+		case xml.ProcInst:
+			if skipDepth == 0 && t.Target == "xml" {
+				out.WriteString("<?xml " + string(t.Inst) + "?>")
+			}
+		case xml.Directive:
+			// DOCTYPE declarations can define entities; drop them
+		}
+	}
+
+	if len(open) > 0 {
+		return nil, fmt.Errorf("invalid SVG: unclosed element <%s>", open[len(open)-1])
+	}
+
+	return out.Bytes(), nil
+}
+
+// unsafeSVGAttribute reports whether an SVG attribute can run script
+func unsafeSVGAttribute(attr xml.Attr) bool {
+	name := strings.ToLower(attr.Name.Local)
+	if strings.HasPrefix(name, "on") {
+		return true
+	}
+
+	if name == "href" || name == "src" || name == "action" || name == "formaction" {
+		value := strings.ToLower(strings.Join(strings.Fields(attr.Value), ""))
+		return strings.HasPrefix(value, "javascript:") ||
+			strings.HasPrefix(value, "vbscript:") ||
+			strings.HasPrefix(value, "data:text/html")
+	}
+
+	return false
+}
+
+// animatesSVGLink reports whether an element animates an href, such as
+// <set attributeName="xlink:href" to="javascript:...">
+func animatesSVGLink(element xml.StartElement) bool {
+	if !svgAnimationElements[strings.ToLower(element.Name.Local)] {
+		return false
+	}
+	for _, attr := range element.Attr {
+		if strings.ToLower(attr.Name.Local) != "attributename" {
+			continue
+		}
+		target := strings.ToLower(strings.TrimSpace(attr.Value))
+		if i := strings.LastIndex(target, ":"); i >= 0 {
+			target = target[i+1:]
+		}
+		if target == "href" || target == "src" {
+			return true
+		}
+	}
+	return false
+}
+
+// xmlName formats a raw token name with its namespace prefix
+func xmlName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+func TestAttachmentSecurity_DetectContentType(t *testing.T) {
+	security := NewAttachmentSecurity()
+
+	// A renamed Windows executable is caught by its magic bytes
+	_, err := security.DetectContentType("screenshot.png", []byte("MZ\x90\x00\x03\x00\x00\x00"))
+	assert.Error(t, err)
+
+	// HTML disguised as text sniffs as a blocked type
+	_, err = security.DetectContentType("notes.txt", []byte("<html><script>alert(1)</script></html>"))
+	assert.Error(t, err)
+
+	// A PNG named .jpg is allowed with its real type
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	contentType, err := security.DetectContentType("photo.jpg", png)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	// Generic sniffing results keep the declared type
+	contentType, err = security.DetectContentType("data.json", []byte(`{"a": 1}`))
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contentType)
+
+	contentType, err = security.DetectContentType("report.docx", []byte("PK\x03\x04\x14\x00"))
+	require.NoError(t, err)
+	assert.Contains(t, contentType, "wordprocessingml")
+}
+
+func TestAttachmentSecurity_Policy(t *testing.T) {
+	config := entities.DefaultStorageConfig()
+	config.MaxAttachmentSize = 50 * 1024 * 1024
+	config.AttachmentPolicy = &entities.AttachmentPolicy{
+		AllowMimeTypes:  []string{"application/zip"},
+		BlockExtensions: []string{"csv"},
+		DisableSniffing: true,
+	}
+	security := NewAttachmentSecurityFromConfig(config)
+
+	assert.NoError(t, security.ValidateSize(20*1024*1024))
+	assert.NoError(t, security.ValidateMimeType("application/zip"))
+	assert.Error(t, security.ValidateExtension("data.csv"))
+
+	// Sniffing disabled: the declared type is trusted
+	contentType, err := security.DetectContentType("tool.txt", []byte("MZ"))
+	require.NoError(t, err)
+	assert.Contains(t, contentType, "text/plain")
+
+	// The built-in defaults are unchanged
+	assert.Error(t, NewAttachmentSecurity().ValidateSize(20*1024*1024))
+}
+
+func TestSanitizeSVG(t *testing.T) {
+	svg := `<?xml version="1.0"?>
+<!DOCTYPE svg [<!ENTITY x "y">]>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" onload="alert(1)">
+  <script>alert(2)</script>
+  <foreignObject><div>html</div></foreignObject>
+  <a xlink:href="javascript:alert(3)"><rect width="10" height="10" fill="red"/></a>
+  <text x="1" y="2">a &lt; b</text>
+</svg>`
+
+	clean, err := SanitizeSVG([]byte(svg))
+	require.NoError(t, err)
+	out := string(clean)
+
+	assert.NotContains(t, out, "alert")
+	assert.NotContains(t, out, "<script")
+	assert.NotContains(t, out, "foreignObject")
+	assert.NotContains(t, out, "DOCTYPE")
+	assert.Contains(t, out, `<rect width="10" height="10" fill="red">`)
+	assert.Contains(t, out, `xmlns:xlink="http://www.w3.org/1999/xlink"`)
+	assert.Contains(t, out, "a &lt; b")
+
+	// Animations cannot set a javascript: link either
+	payloads := []string{
+		`<set attributeName="href" to="javascript:alert(4)"/>`,
+		`<animate attributeName="xlink:href" values="https://example.com;javascript:alert(5)" dur="1s"/>`,
+		`<animate attributeName=" HREF " from="#" to="javascript:alert(6)"></animate>`,
+		`<animateMotion attributeName="href" by="javascript:alert(7)"/>`,
+	}
+	for _, payload := range payloads {
+		animated := `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink"><a href="#">` +
+			payload + `<text>click</text></a><rect width="1" height="1"><animate attributeName="width" to="5" dur="1s"/></rect></svg>`
+		clean, err := SanitizeSVG([]byte(animated))
+		require.NoError(t, err, payload)
+		assert.NotContains(t, string(clean), "javascript", payload)
+		assert.Contains(t, string(clean), "<text>click</text>", payload)
+		assert.Contains(t, string(clean), `<animate attributeName="width"`, "other animations are kept")
+	}
+
+	_, err = SanitizeSVG([]byte("<svg><unclosed></svg>"))
+	assert.Error(t, err)
+}
+
+func TestCommandScanner(t *testing.T) {
+	scanner := NewCommandScanner(&entities.AttachmentScannerConfig{
+		Command: "sh",
+		Args:    []string{"-c", `if grep -q EICAR; then echo "stdin: Eicar-Test-Signature FOUND"; exit 1; fi`},
+	})
+	ctx := context.Background()
+
+	result := scanner.Scan(ctx, "a.txt", strings.NewReader("hello"))
+	assert.Equal(t, entities.ScanVerdictClean, result.Verdict)
+	assert.Equal(t, "sh", result.Scanner)
+
+	result = scanner.Scan(ctx, "a.txt", strings.NewReader("X5O EICAR test"))
+	assert.Equal(t, entities.ScanVerdictInfected, result.Verdict)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+
+	missing := NewCommandScanner(&entities.AttachmentScannerConfig{Command: "/nonexistent/clamdscan"})
+	result = missing.Scan(ctx, "a.txt", bytes.NewReader(nil))
+	assert.Equal(t, entities.ScanVerdictError, result.Verdict)
+	assert.NotEmpty(t, result.Detail)
+}
@@ -5,7 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"mime"
+	"log"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +13,7 @@ import (
 	"github.com/ooyeku/issuemap/internal/domain/entities"
 	"github.com/ooyeku/issuemap/internal/domain/errors"
 	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
 )
 
 // AttachmentService handles attachment operations
@@ -23,6 +24,8 @@ type AttachmentService struct {
 	security           *AttachmentSecurity
 	dedupService       *DeduplicationService
 	compressionService *CompressionService
+	scanner            AttachmentScanner
+	thumbnailService   *ThumbnailService
 	basePath           string
 }
 
@@ -49,13 +52,71 @@ func (s *AttachmentService) SetCompressionService(compressionService *Compressio
 	s.compressionService = compressionService
 }
 
+// SetThumbnailService sets the thumbnail service
+func (s *AttachmentService) SetThumbnailService(thumbnailService *ThumbnailService) {
+	s.thumbnailService = thumbnailService
+}
+
+// SetScanner overrides the malware scanner configured in the attachment policy
+func (s *AttachmentService) SetScanner(scanner AttachmentScanner) {
+	s.scanner = scanner
+}
+
 // UploadAttachment uploads a new attachment for an issue
 func (s *AttachmentService) UploadAttachment(ctx context.Context, issueID entities.IssueID, filename string, content io.Reader, size int64, uploadedBy string) (*entities.Attachment, error) {
+	return s.uploadAttachment(ctx, issueID, filename, content, size, "", uploadedBy)
+}
+
+// UploadAttachmentWithHash uploads a new attachment whose content hash has
+// already been computed with HashAlgorithm, as resumable uploads do while
+// receiving chunks. The content is then streamed to storage without buffering.
+func (s *AttachmentService) UploadAttachmentWithHash(ctx context.Context, issueID entities.IssueID, filename string, content io.Reader, size int64, fileHash, uploadedBy string) (*entities.Attachment, error) {
+	return s.uploadAttachment(ctx, issueID, filename, content, size, fileHash, uploadedBy)
+}
+
+// HashAlgorithm returns the algorithm deduplication uses for content hashes
+func (s *AttachmentService) HashAlgorithm() string {
+	if s.dedupService == nil {
+		return "sha256"
+	}
+	return s.dedupService.HashAlgorithm()
+}
+
+// CheckUpload verifies that an upload of the given size could be accepted for
+// an issue, before any content has been received
+func (s *AttachmentService) CheckUpload(ctx context.Context, issueID entities.IssueID, filename string, size int64) error {
+	// Content checks happen once the upload is complete
+	filename = s.security.SanitizeFilename(filename)
+	if err := s.currentSecurity().ValidateFile(filename, size, nil); err != nil {
+		return errors.Wrap(err, "AttachmentService.CheckUpload", "security_validation")
+	}
+
+	if s.storageService != nil {
+		if err := s.storageService.CheckAttachmentQuota(ctx, size); err != nil {
+			return errors.Wrap(err, "AttachmentService.CheckUpload", "quota_exceeded")
+		}
+	}
+
+	if _, err := s.issueRepo.GetByID(ctx, issueID); err != nil {
+		return errors.Wrap(err, "AttachmentService.CheckUpload", "get_issue")
+	}
+
+	return nil
+}
+
+func (s *AttachmentService) uploadAttachment(ctx context.Context, issueID entities.IssueID, filename string, content io.Reader, size int64, knownHash, uploadedBy string) (*entities.Attachment, error) {
 	// Sanitize filename first
 	filename = s.security.SanitizeFilename(filename)
+	security := s.currentSecurity()
+
+	// Peek at the leading bytes so the content itself can be validated
+	head, content, err := peekContent(content, sniffLength)
+	if err != nil {
+		return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "read_content")
+	}
 
 	// Validate file security
-	if err := s.security.ValidateFile(filename, size, content); err != nil {
+	if err := security.ValidateFile(filename, size, bytes.NewReader(head)); err != nil {
 		return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "security_validation")
 	}
 
@@ -72,39 +133,80 @@ func (s *AttachmentService) UploadAttachment(ctx context.Context, issueID entiti
 		return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "get_issue")
 	}
 
-	// Detect content type
-	contentType := mime.TypeByExtension(filepath.Ext(filename))
-	if contentType == "" {
-		contentType = "application/octet-stream"
+	// Detect content type from the content, falling back to the extension
+	contentType, err := security.DetectContentType(filename, head)
+	if err != nil {
+		return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "security_validation")
 	}
 
 	// Validate MIME type
-	if err := s.security.ValidateMimeType(contentType); err != nil {
+	if err := security.ValidateMimeType(contentType); err != nil {
 		return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "mime_validation")
 	}
 
+	// Scan for malware before anything is stored
+	var scan *entities.AttachmentScan
+	if scanner := s.scannerFor(security); scanner != nil {
+		data, err := io.ReadAll(io.LimitReader(content, security.MaxFileSize()+1))
+		if err != nil {
+			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "read_content")
+		}
+		content = bytes.NewReader(data)
+
+		scan = scanner.Scan(ctx, filename, bytes.NewReader(data))
+		switch {
+		case scan.Verdict == entities.ScanVerdictInfected:
+			return nil, errors.Wrap(fmt.Errorf("malware detected: %s", scan.Signature), "AttachmentService.UploadAttachment", "scan")
+		case scan.Verdict == entities.ScanVerdictError && security.ScannerRequired():
+			return nil, errors.Wrap(fmt.Errorf("malware scan failed: %s", scan.Detail), "AttachmentService.UploadAttachment", "scan")
+		}
+	}
+
 	// Create attachment entity
 	attachment := entities.NewAttachment(issueID, filename, contentType, size, uploadedBy)
+	attachment.Scan = scan
+
+	// Attachments to confidential issues are stored encrypted. Encrypted
+	// content is unique, so it is never deduplicated, thumbnailed or compressed.
+	if issue.Confidential {
+		config, err := storage.NewFileConfigRepository(s.basePath).Load(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "load_config")
+		}
+		sealedContent, sealed, err := storage.SealContent(content, config.Confidential)
+		if err != nil {
+			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "seal")
+		}
+		defer sealedContent.Close()
+		content = sealedContent
+		attachment.Sealed = sealed
+	}
 
 	// Check if deduplication is enabled and should be used for this file
 	var storagePath string
 	var fileHash string
 	var isNew bool
 
-	if s.dedupService != nil && s.dedupService.ShouldDeduplicate(size, contentType) {
-		// Read content into buffer for hash calculation
-		contentBuffer := &bytes.Buffer{}
-		teeReader := io.TeeReader(content, contentBuffer)
+	if !attachment.IsEncrypted() && s.dedupService != nil && s.dedupService.ShouldDeduplicate(size, contentType) {
+		hash := knownHash
+		blobContent := content
+		if hash == "" {
+			// Read content into buffer for hash calculation
+			contentBuffer := &bytes.Buffer{}
+			teeReader := io.TeeReader(content, contentBuffer)
 
-		// Calculate file hash
-		hash, actualSize, err := s.dedupService.CalculateFileHash(teeReader)
-		if err != nil {
-			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "calculate_hash")
-		}
+			// Calculate file hash
+			calculated, actualSize, err := s.dedupService.CalculateFileHash(teeReader)
+			if err != nil {
+				return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "calculate_hash")
+			}
 
-		// Verify size matches
-		if actualSize != size {
-			return nil, errors.Wrap(fmt.Errorf("file size doesn't match expected size"), "AttachmentService.UploadAttachment", "size_mismatch")
+			// Verify size matches
+			if actualSize != size {
+				return nil, errors.Wrap(fmt.Errorf("file size doesn't match expected size"), "AttachmentService.UploadAttachment", "size_mismatch")
+			}
+			hash = calculated
+			blobContent = contentBuffer
 		}
 
 		// Get or create file hash entry
@@ -114,46 +216,35 @@ func (s *AttachmentService) UploadAttachment(ctx context.Context, issueID entiti
 			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "get_file_hash")
 		}
 
-		// If this is a new file, save it to deduplicated storage
-		if isNewFile {
-			// Ensure target directory exists
-			targetDir := filepath.Dir(fileHashEntry.StoragePath)
-			if err := os.MkdirAll(targetDir, 0755); err != nil {
-				return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "create_dedup_dir")
-			}
+		// Store the content under its path relative to the .issuemap directory
+		relPath, err := filepath.Rel(s.basePath, fileHashEntry.StoragePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "get_relative_path")
+		}
+		relPath = filepath.ToSlash(relPath)
 
-			// Save file to deduplicated location
-			file, err := os.Create(fileHashEntry.StoragePath)
+		// If this is a new file, save it to deduplicated storage; otherwise
+		// share the blob's storage path, which may be in another backend
+		if isNewFile {
+			savedPath, err := s.attachmentRepo.SaveBlob(ctx, relPath, blobContent)
 			if err != nil {
-				return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "create_dedup_file")
-			}
-			defer file.Close()
-
-			if _, err := io.Copy(file, contentBuffer); err != nil {
-				os.Remove(fileHashEntry.StoragePath) // Clean up on failure
+				s.dedupService.RemoveReference(attachment.ID, hash)
 				return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "write_dedup_file")
 			}
-
-			if err := file.Sync(); err != nil {
-				os.Remove(fileHashEntry.StoragePath) // Clean up on failure
-				return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "sync_dedup_file")
-			}
+			relPath = savedPath
+		} else {
+			relPath = s.sharedBlobPath(ctx, hash, relPath)
 		}
 
 		// Add reference to the deduplicated file
 		if err := s.dedupService.AddReference(attachment.ID, issueID, hash, filename); err != nil {
 			// Clean up if this was a new file
 			if isNewFile {
-				os.Remove(fileHashEntry.StoragePath)
+				s.attachmentRepo.DeleteFile(ctx, relPath)
 			}
 			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "add_dedup_reference")
 		}
 
-		// Store relative path for compatibility with repository
-		relPath, err := filepath.Rel(s.basePath, fileHashEntry.StoragePath)
-		if err != nil {
-			return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "get_relative_path")
-		}
 		storagePath = relPath
 		fileHash = hash
 	} else {
@@ -167,6 +258,14 @@ func (s *AttachmentService) UploadAttachment(ctx context.Context, issueID entiti
 
 	attachment.StoragePath = storagePath
 
+	// Generate a thumbnail for images; like compression this is best-effort,
+	// and a failed thumbnail is generated again on request
+	if !attachment.IsEncrypted() && s.thumbnailService != nil && s.thumbnailService.CanThumbnail(attachment) {
+		if err := s.thumbnailService.Generate(ctx, attachment); err != nil {
+			log.Printf("Failed to generate thumbnail for attachment %s: %v", attachment.ID, err)
+		}
+	}
+
 	// Save metadata
 	if err := s.attachmentRepo.SaveMetadata(ctx, attachment); err != nil {
 		// Try to clean up on failure
@@ -176,6 +275,7 @@ func (s *AttachmentService) UploadAttachment(ctx context.Context, issueID entiti
 		} else {
 			// Remove traditional file
 			s.attachmentRepo.DeleteFile(ctx, storagePath)
+			s.deleteThumbnail(ctx, attachment)
 		}
 		return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "save_metadata")
 	}
@@ -190,6 +290,7 @@ func (s *AttachmentService) UploadAttachment(ctx context.Context, issueID entiti
 		} else {
 			// Remove traditional file
 			s.attachmentRepo.DeleteFile(ctx, storagePath)
+			s.deleteThumbnail(ctx, attachment)
 		}
 		s.attachmentRepo.DeleteMetadata(ctx, attachment.ID)
 		return nil, errors.Wrap(err, "AttachmentService.UploadAttachment", "update_issue")
@@ -215,6 +316,135 @@ func (s *AttachmentService) UploadAttachment(ctx context.Context, issueID entiti
 	return attachment, nil
 }
 
+// sharedBlobPath returns the storage path recorded by existing attachments of a
+// deduplicated file, falling back to its local path
+func (s *AttachmentService) sharedBlobPath(ctx context.Context, hash, localPath string) string {
+	for _, ref := range s.dedupService.GetReferences(hash) {
+		existing, err := s.attachmentRepo.GetMetadata(ctx, ref.AttachmentID)
+		if err != nil {
+			continue
+		}
+		if _, key := entities.SplitStoragePath(existing.StoragePath); key == localPath {
+			return existing.StoragePath
+		}
+	}
+	return localPath
+}
+
+// ServableContent returns the content to serve for an attachment and its length.
+// SVG images are sanitized first unless the attachment policy disables it.
+func (s *AttachmentService) ServableContent(attachment *entities.Attachment, content io.Reader) (io.Reader, int64, error) {
+	isSVG := normalizeMimeType(attachment.ContentType) == "image/svg+xml" ||
+		strings.EqualFold(filepath.Ext(attachment.Filename), ".svg")
+	if !isSVG || !s.currentSecurity().SanitizeSVGEnabled() {
+		return content, attachment.Size, nil
+	}
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "AttachmentService.ServableContent", "read")
+	}
+	sanitized, err := SanitizeSVG(data)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "AttachmentService.ServableContent", "sanitize_svg")
+	}
+
+	return bytes.NewReader(sanitized), int64(len(sanitized)), nil
+}
+
+// GetThumbnail returns the thumbnail of an image attachment, generating and
+// caching it first if the attachment predates thumbnail support
+func (s *AttachmentService) GetThumbnail(ctx context.Context, attachmentID string) (io.ReadCloser, *entities.Attachment, error) {
+	attachment, err := s.attachmentRepo.GetMetadata(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "AttachmentService.GetThumbnail", "get_metadata")
+	}
+
+	if attachment.IsEncrypted() || s.thumbnailService == nil || !s.thumbnailService.CanThumbnail(attachment) {
+		return nil, nil, errors.Wrap(errors.ErrNotFound, "AttachmentService.GetThumbnail", "unsupported")
+	}
+
+	if attachment.Thumbnail == nil {
+		if err := s.thumbnailService.Generate(ctx, attachment); err != nil {
+			return nil, nil, errors.Wrap(err, "AttachmentService.GetThumbnail", "generate")
+		}
+		if err := s.attachmentRepo.SaveMetadata(ctx, attachment); err != nil {
+			return nil, nil, errors.Wrap(err, "AttachmentService.GetThumbnail", "save_metadata")
+		}
+	}
+
+	thumbnail, err := s.thumbnailService.Open(ctx, attachment)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "AttachmentService.GetThumbnail", "open")
+	}
+
+	return thumbnail, attachment, nil
+}
+
+// GetPreview renders up to limit bytes of a text attachment for display
+func (s *AttachmentService) GetPreview(ctx context.Context, attachmentID string, limit int) (*entities.AttachmentPreview, *entities.Attachment, error) {
+	if limit <= 0 {
+		limit = entities.DefaultPreviewBytes
+	}
+
+	attachment, err := s.attachmentRepo.GetMetadata(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "AttachmentService.GetPreview", "get_metadata")
+	}
+	if entities.PreviewKindFor(attachment.Filename, attachment.ContentType) == entities.PreviewKindNone {
+		return &entities.AttachmentPreview{Kind: entities.PreviewKindNone}, attachment, nil
+	}
+
+	content, _, err := s.GetAttachmentContent(ctx, attachmentID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "AttachmentService.GetPreview", "get_content")
+	}
+	defer content.Close()
+
+	// Read one byte past the limit so truncation can be detected
+	data, err := io.ReadAll(io.LimitReader(content, int64(limit)+1))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "AttachmentService.GetPreview", "read")
+	}
+
+	return entities.BuildAttachmentPreview(attachment.Filename, attachment.ContentType, data, limit), attachment, nil
+}
+
+// deleteThumbnail removes an attachment's cached thumbnail, if any
+func (s *AttachmentService) deleteThumbnail(ctx context.Context, attachment *entities.Attachment) {
+	if attachment.Thumbnail != nil {
+		s.attachmentRepo.DeleteFile(ctx, attachment.Thumbnail.StoragePath)
+	}
+}
+
+// currentSecurity returns the validator for the project's current attachment policy
+func (s *AttachmentService) currentSecurity() *AttachmentSecurity {
+	if s.storageService == nil {
+		return s.security
+	}
+	return NewAttachmentSecurityFromConfig(s.storageService.GetConfig())
+}
+
+// scannerFor returns the malware scanner to use, if any
+func (s *AttachmentService) scannerFor(security *AttachmentSecurity) AttachmentScanner {
+	if s.scanner != nil {
+		return s.scanner
+	}
+	return security.Scanner()
+}
+
+// peekContent reads up to n leading bytes of content and returns them along
+// with a reader that still yields the full content
+func peekContent(content io.Reader, n int) ([]byte, io.Reader, error) {
+	head := make([]byte, n)
+	read, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, err
+	}
+	head = head[:read]
+	return head, io.MultiReader(bytes.NewReader(head), content), nil
+}
+
 // GetAttachment retrieves an attachment by ID
 func (s *AttachmentService) GetAttachment(ctx context.Context, attachmentID string) (*entities.Attachment, error) {
 	attachment, err := s.attachmentRepo.GetMetadata(ctx, attachmentID)
@@ -268,9 +498,40 @@ func (s *AttachmentService) GetAttachmentContent(ctx context.Context, attachment
 		return nil, nil, errors.Wrap(err, "AttachmentService.GetAttachmentContent", "get_file")
 	}
 
+	if attachment.IsEncrypted() {
+		opened, err := openSealedContent(content, attachment.Sealed)
+		if err != nil {
+			content.Close()
+			return nil, nil, errors.Wrap(err, "AttachmentService.GetAttachmentContent", "decrypt")
+		}
+		return opened, attachment, nil
+	}
+
 	return content, attachment, nil
 }
 
+// openSealedContent decrypts encrypted attachment content with the local
+// identity
+func openSealedContent(content io.ReadCloser, sealed *entities.SealedContent) (io.ReadCloser, error) {
+	identity, err := storage.LoadConfidentialIdentity(entities.GetIdentityPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	opened, err := storage.OpenContent(content, sealed, identity)
+	if err == storage.ErrNotRecipient {
+		return nil, fmt.Errorf("%w: attachment is confidential and cannot be decrypted with this identity", errors.ErrPermissionDenied)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{opened, content}, nil
+}
+
 // ListIssueAttachments lists all attachments for an issue
 func (s *AttachmentService) ListIssueAttachments(ctx context.Context, issueID entities.IssueID) ([]*entities.Attachment, error) {
 	attachments, err := s.attachmentRepo.ListByIssue(ctx, issueID)
@@ -310,12 +571,13 @@ func (s *AttachmentService) DeleteAttachment(ctx context.Context, attachmentID s
 
 	if s.dedupService != nil {
 		// Check if storage path is in dedup directory (relative path)
-		if strings.HasPrefix(attachment.StoragePath, "dedup/") {
+		_, key := entities.SplitStoragePath(attachment.StoragePath)
+		if strings.HasPrefix(key, "dedup/") {
 			isDeduplicatedFile = true
 
 			// Extract hash from storage path
 			// Remove "dedup/" prefix
-			pathWithoutPrefix := strings.TrimPrefix(attachment.StoragePath, "dedup/")
+			pathWithoutPrefix := strings.TrimPrefix(key, "dedup/")
 			// Combine directory + filename to get hash (remove the "/" separator)
 			dir := filepath.Dir(pathWithoutPrefix)
 			filename := filepath.Base(pathWithoutPrefix)
@@ -329,12 +591,20 @@ func (s *AttachmentService) DeleteAttachment(ctx context.Context, attachmentID s
 		if err := s.dedupService.RemoveReference(attachmentID, fileHash); err != nil {
 			// Log error but continue - metadata cleanup is more important
 			fmt.Printf("Warning: failed to remove deduplication reference: %v\n", err)
+		} else if !s.dedupService.HasHash(fileHash) {
+			// The deduplication service only removes local files; the
+			// thumbnail is shared by every attachment of the file
+			if !entities.IsLocalStoragePath(attachment.StoragePath) {
+				s.attachmentRepo.DeleteFile(ctx, attachment.StoragePath)
+			}
+			s.deleteThumbnail(ctx, attachment)
 		}
 	} else {
 		// Delete traditional file
 		if err := s.attachmentRepo.DeleteFile(ctx, attachment.StoragePath); err != nil {
 			// Continue even if file deletion fails
 		}
+		s.deleteThumbnail(ctx, attachment)
 	}
 
 	// Delete metadata
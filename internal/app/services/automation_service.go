@@ -173,7 +173,7 @@ func (s *AutomationService) processAssigneeRules(ctx context.Context, issue *ent
 	for _, rule := range rules {
 		if s.evaluateCondition(rule.Condition, fieldValues, issue) {
 			user := &entities.User{Username: rule.Assignee}
-			issue.SetAssignee(user)
+			issue.SetAssignee(s.issueService.userDirectory(ctx).CanonicalUser(user))
 			break // Only apply first matching rule
 		}
 	}
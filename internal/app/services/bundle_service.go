@@ -0,0 +1,697 @@
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// BundleService exports and imports complete projects as a single versioned archive
+type BundleService struct {
+	basePath       string
+	issueRepo      repositories.IssueRepository
+	historyRepo    repositories.HistoryRepository
+	dependencyRepo repositories.DependencyRepository
+	timeEntryRepo  repositories.TimeEntryRepository
+	configRepo     repositories.ConfigRepository
+	attachmentRepo repositories.AttachmentRepository
+	dedupService   *DeduplicationService
+}
+
+// NewBundleService creates a new bundle service
+func NewBundleService(
+	basePath string,
+	issueRepo repositories.IssueRepository,
+	historyRepo repositories.HistoryRepository,
+	dependencyRepo repositories.DependencyRepository,
+	timeEntryRepo repositories.TimeEntryRepository,
+	configRepo repositories.ConfigRepository,
+	attachmentRepo repositories.AttachmentRepository,
+) *BundleService {
+	return &BundleService{
+		basePath:       basePath,
+		issueRepo:      issueRepo,
+		historyRepo:    historyRepo,
+		dependencyRepo: dependencyRepo,
+		timeEntryRepo:  timeEntryRepo,
+		configRepo:     configRepo,
+		attachmentRepo: attachmentRepo,
+	}
+}
+
+// SetDeduplicationService sets the deduplication service used to key attachment blobs
+func (s *BundleService) SetDeduplicationService(dedupService *DeduplicationService) {
+	s.dedupService = dedupService
+}
+
+// Export writes the complete project state to a bundle
+func (s *BundleService) Export(ctx context.Context, w io.Writer, createdBy string) (*entities.BundleManifest, error) {
+	config, err := s.configRepo.Load(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "load_config")
+	}
+
+	manifest := entities.NewBundleManifest(config.Project.Name, createdBy)
+	manifest.HashAlgorithm = "sha256"
+	if s.dedupService != nil {
+		manifest.HashAlgorithm = s.dedupService.GetConfig().HashAlgorithm
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	bw := &bundleWriter{tw: tw, manifest: manifest}
+
+	if err := bw.writeJSON("config.json", config); err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "write_config")
+	}
+
+	templates, err := s.configRepo.ListTemplates(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "list_templates")
+	}
+	for _, template := range templates {
+		if err := bw.writeJSON(fmt.Sprintf("templates/%s.json", template.Name), template); err != nil {
+			return nil, errors.Wrap(err, "BundleService.Export", "write_template")
+		}
+		manifest.Counts.Templates++
+	}
+
+	issueList, err := s.issueRepo.List(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "list_issues")
+	}
+	for i := range issueList.Issues {
+		issue := &issueList.Issues[i]
+		if manifest.IDPrefix == "" {
+			manifest.IDPrefix, _ = entities.SplitIssueID(issue.ID)
+		}
+		// Confidential issues leave the project as stored: placeholders in
+		// the issue and the ciphertext beside it, whoever runs the export
+		stored := issue
+		if issue.Sealed != nil {
+			redacted := *issue
+			redacted.Redact()
+			stored = &redacted
+			if err := bw.writeJSON(fmt.Sprintf("sealed/%s.json", issue.ID), issue.Sealed); err != nil {
+				return nil, errors.Wrap(err, "BundleService.Export", "write_sealed")
+			}
+			manifest.Counts.Confidential++
+		}
+		if err := bw.writeJSON(fmt.Sprintf("issues/%s.json", issue.ID), stored); err != nil {
+			return nil, errors.Wrap(err, "BundleService.Export", "write_issue")
+		}
+		manifest.Counts.Issues++
+
+		for _, attachment := range issue.Attachments {
+			hash, err := s.addAttachmentBlob(bw, attachment)
+			if err != nil {
+				return nil, errors.Wrap(err, "BundleService.Export", "write_attachment")
+			}
+			manifest.Attachments[attachment.ID] = hash
+			manifest.Counts.Attachments++
+		}
+	}
+
+	if s.historyRepo != nil {
+		histories, err := s.historyRepo.GetAllHistory(ctx, repositories.HistoryFilter{})
+		if err != nil {
+			return nil, errors.Wrap(err, "BundleService.Export", "list_history")
+		}
+		for _, history := range histories {
+			if err := bw.writeJSON(fmt.Sprintf("history/%s.json", history.IssueID), history); err != nil {
+				return nil, errors.Wrap(err, "BundleService.Export", "write_history")
+			}
+			manifest.Counts.Histories++
+		}
+	}
+
+	if s.dependencyRepo != nil {
+		dependencies, err := s.dependencyRepo.List(ctx, repositories.DependencyFilter{})
+		if err != nil {
+			return nil, errors.Wrap(err, "BundleService.Export", "list_dependencies")
+		}
+		for _, dep := range dependencies {
+			if err := bw.writeJSON(fmt.Sprintf("dependencies/%s.json", dep.ID), dep); err != nil {
+				return nil, errors.Wrap(err, "BundleService.Export", "write_dependency")
+			}
+			manifest.Counts.Dependencies++
+		}
+	}
+
+	if s.timeEntryRepo != nil {
+		entries, err := s.timeEntryRepo.List(ctx, repositories.TimeEntryFilter{})
+		if err != nil {
+			return nil, errors.Wrap(err, "BundleService.Export", "list_time_entries")
+		}
+		for _, entry := range entries {
+			if err := bw.writeJSON(fmt.Sprintf("time_entries/%s.json", entry.ID), entry); err != nil {
+				return nil, errors.Wrap(err, "BundleService.Export", "write_time_entry")
+			}
+			manifest.Counts.TimeEntries++
+		}
+	}
+
+	// The manifest is written last so it can record checksums of every other entry
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "marshal_manifest")
+	}
+	if err := writeTarEntry(tw, entities.BundleManifestName, manifestData); err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "write_manifest")
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "close_tar")
+	}
+	if err := gzw.Close(); err != nil {
+		return nil, errors.Wrap(err, "BundleService.Export", "close_gzip")
+	}
+
+	return manifest, nil
+}
+
+// Verify checks a bundle's format version and the checksum of every entry
+func (s *BundleService) Verify(ctx context.Context, r io.Reader) (*entities.BundleVerifyResult, error) {
+	contents, err := readBundle(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "BundleService.Verify", "read_bundle")
+	}
+	return verifyBundleContents(contents), nil
+}
+
+// Import restores a bundle into the current project
+func (s *BundleService) Import(ctx context.Context, r io.Reader, opts entities.BundleImportOptions) (*entities.BundleImportResult, error) {
+	contents, err := readBundle(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "BundleService.Import", "read_bundle")
+	}
+
+	verification := verifyBundleContents(contents)
+	if !verification.Valid {
+		return nil, errors.New("BundleService.Import", "verify",
+			fmt.Errorf("bundle failed verification: %s", strings.Join(verification.Errors, "; ")))
+	}
+	manifest := verification.Manifest
+
+	result := &entities.BundleImportResult{DryRun: opts.DryRun}
+
+	// Decode all issues first so the full ID mapping is known before anything is written
+	var issues []*entities.Issue
+	for _, name := range contents.names("issues/") {
+		var issue entities.Issue
+		if err := json.Unmarshal(contents.files[name], &issue); err != nil {
+			return nil, errors.Wrap(err, "BundleService.Import", "parse_issue")
+		}
+		issues = append(issues, &issue)
+	}
+
+	ids := make([]entities.IssueID, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	idMap := entities.NewIssueIDMap(ids, opts.TargetPrefix)
+	if idMap.Changed() {
+		result.Remapped = idMap
+	}
+
+	if opts.IncludeConfig {
+		var config entities.Config
+		if err := json.Unmarshal(contents.files["config.json"], &config); err != nil {
+			return nil, errors.Wrap(err, "BundleService.Import", "parse_config")
+		}
+		if !opts.DryRun {
+			if err := s.configRepo.Save(ctx, &config); err != nil {
+				return nil, errors.Wrap(err, "BundleService.Import", "save_config")
+			}
+		}
+	}
+
+	if err := s.importTemplates(ctx, contents, opts, result); err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		if err := s.importIssue(ctx, contents, manifest, issue, idMap, opts, result); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.importHistories(ctx, contents, idMap, opts, result); err != nil {
+		return nil, err
+	}
+	if err := s.importDependencies(ctx, contents, idMap, opts, result); err != nil {
+		return nil, err
+	}
+	if err := s.importTimeEntries(ctx, contents, idMap, opts, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// addAttachmentBlob stores an attachment's bytes once per distinct hash
+func (s *BundleService) addAttachmentBlob(bw *bundleWriter, attachment entities.Attachment) (string, error) {
+	content, err := s.attachmentRepo.GetFile(context.Background(), attachment.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read attachment %s: %w", attachment.ID, err)
+	}
+	data, err := io.ReadAll(content)
+	content.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read attachment %s: %w", attachment.ID, err)
+	}
+
+	var hash string
+	if s.dedupService != nil {
+		hash, _, err = s.dedupService.CalculateFileHash(bytes.NewReader(data))
+		if err != nil {
+			return "", err
+		}
+	} else {
+		sum := sha256.Sum256(data)
+		hash = hex.EncodeToString(sum[:])
+	}
+
+	name := "blobs/" + hash
+	if _, exists := bw.manifest.Checksums[name]; exists {
+		return hash, nil
+	}
+	if err := bw.write(name, data); err != nil {
+		return "", err
+	}
+	bw.manifest.Counts.Blobs++
+	return hash, nil
+}
+
+func (s *BundleService) importTemplates(ctx context.Context, contents *bundleContents, opts entities.BundleImportOptions, result *entities.BundleImportResult) error {
+	existing := make(map[string]bool)
+	if templates, err := s.configRepo.ListTemplates(ctx); err == nil {
+		for _, template := range templates {
+			existing[template.Name] = true
+		}
+	}
+
+	for _, name := range contents.names("templates/") {
+		var template entities.Template
+		if err := json.Unmarshal(contents.files[name], &template); err != nil {
+			return errors.Wrap(err, "BundleService.Import", "parse_template")
+		}
+		if existing[template.Name] && !opts.Overwrite {
+			result.Skipped = append(result.Skipped, "template "+template.Name)
+			continue
+		}
+		if !opts.DryRun {
+			if err := s.configRepo.SaveTemplate(ctx, &template); err != nil {
+				return errors.Wrap(err, "BundleService.Import", "save_template")
+			}
+		}
+		result.Counts.Templates++
+	}
+	return nil
+}
+
+func (s *BundleService) importIssue(ctx context.Context, contents *bundleContents, manifest *entities.BundleManifest, issue *entities.Issue, idMap entities.IssueIDMap, opts entities.BundleImportOptions, result *entities.BundleImportResult) error {
+	sourceID := issue.ID
+	if ok, err := s.openSealedIssue(contents, issue, idMap); err != nil {
+		return errors.Wrap(err, "BundleService.Import", "open_sealed")
+	} else if !ok {
+		result.Warnings = append(result.Warnings,
+			fmt.Sprintf("%s is confidential and cannot be renamed to %s without being decrypted; skipped", sourceID, idMap.Map(sourceID)))
+		result.Skipped = append(result.Skipped, "issue "+string(sourceID))
+		return nil
+	}
+	issue.ID = idMap.Map(issue.ID)
+	issue.Title = idMap.RemapText(issue.Title)
+	issue.Description = idMap.RemapText(issue.Description)
+	for i := range issue.Comments {
+		issue.Comments[i].Text = idMap.RemapText(issue.Comments[i].Text)
+	}
+
+	exists, err := s.issueRepo.Exists(ctx, issue.ID)
+	if err != nil {
+		return errors.Wrap(err, "BundleService.Import", "check_issue")
+	}
+	if exists && !opts.Overwrite {
+		result.Skipped = append(result.Skipped, "issue "+string(issue.ID))
+		return nil
+	}
+
+	for i := range issue.Attachments {
+		attachment := &issue.Attachments[i]
+		hash, ok := manifest.Attachments[attachment.ID]
+		if !ok {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("attachment %s of %s has no blob in bundle", attachment.ID, sourceID))
+			continue
+		}
+		attachment.ID = idMap.RemapPrefixed(attachment.ID)
+		attachment.IssueID = issue.ID
+		if !opts.DryRun {
+			if err := s.restoreAttachment(ctx, attachment, contents.files["blobs/"+hash]); err != nil {
+				return errors.Wrap(err, "BundleService.Import", "restore_attachment")
+			}
+		}
+		result.Counts.Attachments++
+	}
+
+	if !opts.DryRun {
+		if exists {
+			if err := s.issueRepo.Delete(ctx, issue.ID); err != nil {
+				return errors.Wrap(err, "BundleService.Import", "replace_issue")
+			}
+		}
+		if err := s.issueRepo.Create(ctx, issue); err != nil {
+			return errors.Wrap(err, "BundleService.Import", "create_issue")
+		}
+	}
+	result.Counts.Issues++
+	return nil
+}
+
+// openSealedIssue attaches the bundled ciphertext of a confidential issue.
+// Kept under its ID the issue is stored with that ciphertext unchanged. Sealed
+// content is bound to the issue ID, so an issue that is being renamed is
+// decrypted instead and sealed again to this project's recipients when it is
+// created; it reports false when the local identity cannot decrypt it.
+func (s *BundleService) openSealedIssue(contents *bundleContents, issue *entities.Issue, idMap entities.IssueIDMap) (bool, error) {
+	data, ok := contents.files[fmt.Sprintf("sealed/%s.json", issue.ID)]
+	if !ok {
+		return true, nil
+	}
+	var sealed entities.SealedContent
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return false, err
+	}
+	issue.Sealed = &sealed
+	issue.Redacted = true
+	if idMap.Map(issue.ID) == issue.ID {
+		return true, nil
+	}
+
+	identity, err := storage.LoadConfidentialIdentity(entities.GetIdentityPath())
+	if err != nil {
+		return false, nil
+	}
+	if err := storage.OpenIssue(issue, identity); err != nil {
+		return false, nil
+	}
+	issue.Sealed = nil
+	issue.Redacted = false
+	return true, nil
+}
+
+// restoreAttachment writes an attachment blob back to storage and records its metadata
+func (s *BundleService) restoreAttachment(ctx context.Context, attachment *entities.Attachment, data []byte) error {
+	// Thumbnails are not bundled; they are regenerated on demand
+	attachment.Thumbnail = nil
+
+	var key string
+
+	if _, sourceKey := entities.SplitStoragePath(attachment.StoragePath); strings.HasPrefix(sourceKey, "dedup/") && s.dedupService != nil {
+		hash, size, err := s.dedupService.CalculateFileHash(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		fileHash, isNew, err := s.dedupService.GetOrCreateFileHash(hash, size, attachment.Filename, attachment.ContentType)
+		if err != nil {
+			return err
+		}
+		if err := s.dedupService.AddReference(attachment.ID, attachment.IssueID, hash, attachment.Filename); err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(s.basePath, fileHash.StoragePath)
+		if err != nil {
+			return err
+		}
+		if !isNew {
+			attachment.StoragePath = filepath.ToSlash(relPath)
+			return s.attachmentRepo.SaveMetadata(ctx, attachment)
+		}
+		key = filepath.ToSlash(relPath)
+	} else {
+		// Keep the stored file name but move it under the (possibly remapped) issue directory
+		filename := path.Base(sourceKey)
+		key = path.Join("attachments", string(attachment.IssueID), filename)
+	}
+
+	storagePath, err := s.attachmentRepo.SaveBlob(ctx, key, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	attachment.StoragePath = storagePath
+
+	return s.attachmentRepo.SaveMetadata(ctx, attachment)
+}
+
+func (s *BundleService) importHistories(ctx context.Context, contents *bundleContents, idMap entities.IssueIDMap, opts entities.BundleImportOptions, result *entities.BundleImportResult) error {
+	if s.historyRepo == nil {
+		return nil
+	}
+
+	for _, name := range contents.names("history/") {
+		var history entities.IssueHistory
+		if err := json.Unmarshal(contents.files[name], &history); err != nil {
+			return errors.Wrap(err, "BundleService.Import", "parse_history")
+		}
+
+		history.IssueID = idMap.Map(history.IssueID)
+		for i := range history.Entries {
+			history.Entries[i].IssueID = idMap.Map(history.Entries[i].IssueID)
+			history.Entries[i].Message = idMap.RemapText(history.Entries[i].Message)
+		}
+		if idMap.Changed() {
+			// Remapping rewrites entry content, so the hash chain has to be resealed
+			history.Reseal()
+		}
+
+		existing, err := s.historyRepo.GetHistory(ctx, history.IssueID)
+		if err == nil && len(existing.Entries) > 0 && !opts.Overwrite {
+			result.Skipped = append(result.Skipped, "history "+string(history.IssueID))
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := s.historyRepo.CreateHistory(ctx, &history); err != nil {
+				return errors.Wrap(err, "BundleService.Import", "save_history")
+			}
+		}
+		result.Counts.Histories++
+	}
+	return nil
+}
+
+func (s *BundleService) importDependencies(ctx context.Context, contents *bundleContents, idMap entities.IssueIDMap, opts entities.BundleImportOptions, result *entities.BundleImportResult) error {
+	if s.dependencyRepo == nil {
+		return nil
+	}
+
+	for _, name := range contents.names("dependencies/") {
+		var dep entities.Dependency
+		if err := json.Unmarshal(contents.files[name], &dep); err != nil {
+			return errors.Wrap(err, "BundleService.Import", "parse_dependency")
+		}
+
+		dep.SourceID = idMap.Map(dep.SourceID)
+		dep.TargetID = idMap.Map(dep.TargetID)
+		dep.ID = fmt.Sprintf("%s-%s-%s", dep.SourceID, dep.Type, dep.TargetID)
+
+		if _, err := s.dependencyRepo.GetByID(ctx, dep.ID); err == nil && !opts.Overwrite {
+			result.Skipped = append(result.Skipped, "dependency "+dep.ID)
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := s.dependencyRepo.Create(ctx, &dep); err != nil {
+				return errors.Wrap(err, "BundleService.Import", "save_dependency")
+			}
+		}
+		result.Counts.Dependencies++
+	}
+	return nil
+}
+
+func (s *BundleService) importTimeEntries(ctx context.Context, contents *bundleContents, idMap entities.IssueIDMap, opts entities.BundleImportOptions, result *entities.BundleImportResult) error {
+	if s.timeEntryRepo == nil {
+		return nil
+	}
+
+	for _, name := range contents.names("time_entries/") {
+		var entry entities.TimeEntry
+		if err := json.Unmarshal(contents.files[name], &entry); err != nil {
+			return errors.Wrap(err, "BundleService.Import", "parse_time_entry")
+		}
+
+		entry.IssueID = idMap.Map(entry.IssueID)
+		entry.ID = idMap.RemapPrefixed(entry.ID)
+
+		if _, err := s.timeEntryRepo.GetByID(ctx, entry.ID); err == nil && !opts.Overwrite {
+			result.Skipped = append(result.Skipped, "time entry "+entry.ID)
+			continue
+		}
+
+		if !opts.DryRun {
+			if err := s.timeEntryRepo.Create(ctx, &entry); err != nil {
+				return errors.Wrap(err, "BundleService.Import", "save_time_entry")
+			}
+		}
+		result.Counts.TimeEntries++
+	}
+	return nil
+}
+
+// bundleWriter writes tar entries and records their checksums in the manifest
+type bundleWriter struct {
+	tw       *tar.Writer
+	manifest *entities.BundleManifest
+}
+
+func (bw *bundleWriter) writeJSON(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return bw.write(name, data)
+}
+
+func (bw *bundleWriter) write(name string, data []byte) error {
+	if err := writeTarEntry(bw.tw, name, data); err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	bw.manifest.Checksums[name] = hex.EncodeToString(sum[:])
+	return nil
+}
+
+func writeTarEntry(tw *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: time.Now(),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// bundleContents holds every entry of a bundle in memory
+type bundleContents struct {
+	files map[string][]byte
+}
+
+// names returns the sorted entry names under the given directory prefix
+func (c *bundleContents) names(prefix string) []string {
+	var names []string
+	for name := range c.files {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func readBundle(r io.Reader) (*bundleContents, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzr.Close()
+
+	contents := &bundleContents{files: make(map[string][]byte)}
+	tr := tar.NewReader(gzr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		contents.files[path.Clean(header.Name)] = data
+	}
+	return contents, nil
+}
+
+func verifyBundleContents(contents *bundleContents) *entities.BundleVerifyResult {
+	result := &entities.BundleVerifyResult{}
+
+	manifestData, ok := contents.files[entities.BundleManifestName]
+	if !ok {
+		result.Errors = append(result.Errors, "manifest missing")
+		return result
+	}
+
+	var manifest entities.BundleManifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("invalid manifest: %v", err))
+		return result
+	}
+	result.Manifest = &manifest
+
+	if manifest.FormatVersion > entities.BundleFormatVersion {
+		result.Errors = append(result.Errors,
+			fmt.Sprintf("unsupported bundle format version %d (max %d)", manifest.FormatVersion, entities.BundleFormatVersion))
+	}
+
+	names := make([]string, 0, len(manifest.Checksums))
+	for name := range manifest.Checksums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, ok := contents.files[name]
+		if !ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: missing", name))
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != manifest.Checksums[name] {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: checksum mismatch", name))
+		}
+	}
+
+	for name := range contents.files {
+		if name == entities.BundleManifestName {
+			continue
+		}
+		if _, ok := manifest.Checksums[name]; !ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: not listed in manifest", name))
+		}
+	}
+
+	for attachmentID, hash := range manifest.Attachments {
+		if _, ok := contents.files["blobs/"+hash]; !ok {
+			result.Errors = append(result.Errors, fmt.Sprintf("attachment %s: blob %s missing", attachmentID, hash))
+		}
+	}
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
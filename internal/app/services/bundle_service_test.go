@@ -0,0 +1,241 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// bundleProject is a project on disk with the repositories a bundle touches
+type bundleProject struct {
+	basePath       string
+	configRepo     *storage.FileConfigRepository
+	issueRepo      *storage.FileIssueRepository
+	historyRepo    *storage.FileHistoryRepository
+	dependencyRepo *storage.FileDependencyRepository
+	timeEntryRepo  *storage.FileTimeEntryRepository
+	attachmentRepo *storage.FileAttachmentRepository
+	service        *BundleService
+}
+
+func newBundleProject(t *testing.T, name string) *bundleProject {
+	t.Helper()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	configRepo := storage.NewFileConfigRepository(basePath)
+	config := entities.NewDefaultConfig()
+	config.Project.Name = name
+	require.NoError(t, configRepo.Initialize(context.Background(), config))
+
+	project := &bundleProject{
+		basePath:       basePath,
+		configRepo:     configRepo,
+		issueRepo:      storage.NewFileIssueRepository(basePath),
+		historyRepo:    storage.NewFileHistoryRepository(basePath),
+		dependencyRepo: storage.NewFileDependencyRepository(basePath),
+		timeEntryRepo:  storage.NewFileTimeEntryRepository(basePath),
+		attachmentRepo: storage.NewFileAttachmentRepository(basePath),
+	}
+	project.service = NewBundleService(basePath, project.issueRepo, project.historyRepo,
+		project.dependencyRepo, project.timeEntryRepo, configRepo, project.attachmentRepo)
+	return project
+}
+
+func TestBundleService_RoundTripWithPrefixChange(t *testing.T) {
+	ctx := context.Background()
+	source := newBundleProject(t, "source")
+	historyService := NewHistoryService(source.historyRepo, nil)
+
+	parser := entities.NewIssue("OLD-001", "Parser crash", "Fails on empty input", entities.IssueTypeBug)
+	content := []byte("panic: runtime error\n")
+	storagePath, err := source.attachmentRepo.SaveFile(ctx, parser.ID, "trace.log", bytes.NewReader(content))
+	require.NoError(t, err)
+	attachment := entities.NewAttachment(parser.ID, "trace.log", "text/plain", int64(len(content)), "alice")
+	attachment.StoragePath = storagePath
+	require.NoError(t, source.attachmentRepo.SaveMetadata(ctx, attachment))
+	parser.Attachments = append(parser.Attachments, *attachment)
+	require.NoError(t, source.issueRepo.Create(ctx, parser))
+	require.NoError(t, historyService.RecordIssueCreated(ctx, parser, "alice"))
+	require.NoError(t, historyService.RecordIssueClosed(ctx, parser.ID, "duplicate of OLD-002", "bob"))
+
+	release := entities.NewIssue("OLD-002", "Release 1.0", "Needs OLD-001 fixed first", entities.IssueTypeTask)
+	require.NoError(t, source.issueRepo.Create(ctx, release))
+	require.NoError(t, historyService.RecordIssueCreated(ctx, release, "alice"))
+
+	require.NoError(t, source.dependencyRepo.Create(ctx,
+		entities.NewDependency(parser.ID, release.ID, entities.DependencyTypeBlocks, "", "alice")))
+	require.NoError(t, source.timeEntryRepo.Create(ctx,
+		entities.NewTimeEntry(parser.ID, entities.TimeEntryTypeManual, 90*time.Minute, "Debugging", "bob")))
+
+	var bundle bytes.Buffer
+	manifest, err := source.service.Export(ctx, &bundle, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "OLD", manifest.IDPrefix)
+	assert.Equal(t, entities.BundleCounts{Issues: 2, Histories: 2, Dependencies: 1, TimeEntries: 1, Templates: 5, Attachments: 1, Blobs: 1}, manifest.Counts)
+
+	target := newBundleProject(t, "target")
+	result, err := target.service.Import(ctx, bytes.NewReader(bundle.Bytes()), entities.BundleImportOptions{TargetPrefix: "NEW"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, 2, result.Counts.Issues)
+	assert.Equal(t, map[entities.IssueID]entities.IssueID{"OLD-001": "NEW-001", "OLD-002": "NEW-002"}, result.Remapped)
+
+	// Issues and the references between them carry the new prefix
+	exists, err := target.issueRepo.Exists(ctx, "OLD-001")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	imported, err := target.issueRepo.GetByID(ctx, "NEW-002")
+	require.NoError(t, err)
+	assert.Equal(t, "Needs NEW-001 fixed first", imported.Description)
+
+	// Attachments are stored under the new issue and keep their content
+	imported, err = target.issueRepo.GetByID(ctx, "NEW-001")
+	require.NoError(t, err)
+	require.Len(t, imported.Attachments, 1)
+	restored := imported.Attachments[0]
+	assert.Equal(t, entities.IssueID("NEW-001"), restored.IssueID)
+	assert.True(t, strings.HasPrefix(restored.ID, "NEW-001-att-"), restored.ID)
+	assert.Contains(t, restored.StoragePath, "attachments/NEW-001/")
+	file, err := target.attachmentRepo.GetFile(ctx, restored.StoragePath)
+	require.NoError(t, err)
+	data, err := io.ReadAll(file)
+	file.Close()
+	require.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	// History is remapped and resealed, so its hash chain still verifies
+	history, err := target.historyRepo.GetHistory(ctx, "NEW-001")
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	for _, entry := range history.Entries {
+		assert.Equal(t, entities.IssueID("NEW-001"), entry.IssueID)
+		assert.NotEmpty(t, entry.Hash)
+	}
+	assert.Contains(t, history.Entries[1].Message, "NEW-002")
+	verification := history.VerifyChain()
+	assert.True(t, verification.Valid(), verification.Problems)
+	assert.Equal(t, 2, verification.Sealed)
+
+	// Dependencies and time entries point at the remapped issues
+	dependency, err := target.dependencyRepo.GetByID(ctx, "NEW-001-blocks-NEW-002")
+	require.NoError(t, err)
+	assert.Equal(t, entities.IssueID("NEW-001"), dependency.SourceID)
+	assert.Equal(t, entities.IssueID("NEW-002"), dependency.TargetID)
+
+	issueID := entities.IssueID("NEW-001")
+	entries, err := target.timeEntryRepo.List(ctx, repositories.TimeEntryFilter{IssueID: &issueID})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, strings.HasPrefix(entries[0].ID, "NEW-001-"), entries[0].ID)
+	assert.Equal(t, 90*time.Minute, entries[0].Duration)
+
+	// Importing again skips everything that is already there
+	result, err = target.service.Import(ctx, bytes.NewReader(bundle.Bytes()), entities.BundleImportOptions{TargetPrefix: "NEW"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.BundleCounts{}, result.Counts)
+	assert.Len(t, result.Skipped, 11)
+}
+
+// sealTo makes the project seal confidential issues to the given identity
+func (p *bundleProject) sealTo(t *testing.T, name string, identity *storage.ConfidentialIdentity) {
+	t.Helper()
+	ctx := context.Background()
+	config, err := p.configRepo.Load(ctx)
+	require.NoError(t, err)
+	config.Confidential = &entities.ConfidentialConfig{
+		Recipients: []entities.ConfidentialRecipient{{Name: name, PublicKey: identity.PublicKey()}},
+	}
+	require.NoError(t, p.configRepo.Save(ctx, config))
+}
+
+func TestBundleService_ConfidentialIssuesStaySealed(t *testing.T) {
+	ctx := context.Background()
+	identityPath := filepath.Join(t.TempDir(), "identity.key")
+	alice, err := storage.GenerateConfidentialIdentity(identityPath)
+	require.NoError(t, err)
+	outsiderPath := filepath.Join(t.TempDir(), "identity.key")
+	_, err = storage.GenerateConfidentialIdentity(outsiderPath)
+	require.NoError(t, err)
+	t.Setenv("ISSUEMAP_IDENTITY", identityPath)
+
+	source := newBundleProject(t, "source")
+	source.sealTo(t, "alice", alice)
+	leak := entities.NewIssue("SEC-001", "Credential leak", "Token printed in SEC-002 logs", entities.IssueTypeBug)
+	leak.Confidential = true
+	require.NoError(t, source.issueRepo.Create(ctx, leak))
+	require.NoError(t, source.issueRepo.Create(ctx,
+		entities.NewIssue("SEC-002", "Logging cleanup", "", entities.IssueTypeTask)))
+
+	// A recipient's export carries the ciphertext, never the decrypted text
+	var bundle bytes.Buffer
+	manifest, err := source.service.Export(ctx, &bundle, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 2, manifest.Counts.Issues)
+	assert.Equal(t, 1, manifest.Counts.Confidential)
+	contents, err := readBundle(bytes.NewReader(bundle.Bytes()))
+	require.NoError(t, err)
+	for name, data := range contents.files {
+		assert.NotContains(t, string(data), "Token printed", name)
+	}
+	require.Contains(t, contents.files, "sealed/SEC-001.json")
+
+	// Someone who cannot decrypt the issue exports the same thing
+	t.Setenv("ISSUEMAP_IDENTITY", outsiderPath)
+	outsider := NewBundleService(source.basePath, storage.NewFileIssueRepository(source.basePath),
+		source.historyRepo, source.dependencyRepo, source.timeEntryRepo, source.configRepo, source.attachmentRepo)
+	var outsiderBundle bytes.Buffer
+	_, err = outsider.Export(ctx, &outsiderBundle, "mallory")
+	require.NoError(t, err)
+	outsiderContents, err := readBundle(bytes.NewReader(outsiderBundle.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, contents.files["sealed/SEC-001.json"], outsiderContents.files["sealed/SEC-001.json"])
+	assert.Equal(t, contents.files["issues/SEC-001.json"], outsiderContents.files["issues/SEC-001.json"])
+
+	// Kept under its ID the issue is imported as stored, even by an outsider
+	same := newBundleProject(t, "same")
+	result, err := same.service.Import(ctx, bytes.NewReader(outsiderBundle.Bytes()), entities.BundleImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Counts.Issues)
+	t.Setenv("ISSUEMAP_IDENTITY", identityPath)
+	imported, err := storage.NewFileIssueRepository(same.basePath).GetByID(ctx, "SEC-001")
+	require.NoError(t, err)
+	assert.False(t, imported.Redacted)
+	assert.Equal(t, "Token printed in SEC-002 logs", imported.Description)
+
+	// A recipient can rename it; it is sealed again to the new project
+	renamed := newBundleProject(t, "renamed")
+	renamed.sealTo(t, "alice", alice)
+	result, err = renamed.service.Import(ctx, bytes.NewReader(bundle.Bytes()), entities.BundleImportOptions{TargetPrefix: "NEW"})
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+	assert.Equal(t, 2, result.Counts.Issues)
+	imported, err = renamed.issueRepo.GetByID(ctx, "NEW-001")
+	require.NoError(t, err)
+	assert.True(t, imported.Confidential)
+	assert.False(t, imported.Redacted)
+	require.NotNil(t, imported.Sealed)
+	assert.Equal(t, "Token printed in NEW-002 logs", imported.Description)
+
+	// An outsider cannot, and the rest of the bundle is still imported
+	t.Setenv("ISSUEMAP_IDENTITY", outsiderPath)
+	skipped := newBundleProject(t, "skipped")
+	result, err = skipped.service.Import(ctx, bytes.NewReader(bundle.Bytes()), entities.BundleImportOptions{TargetPrefix: "NEW"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Counts.Issues)
+	assert.Contains(t, result.Skipped, "issue SEC-001")
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0], "SEC-001 is confidential")
+	exists, err := skipped.issueRepo.Exists(ctx, "NEW-001")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
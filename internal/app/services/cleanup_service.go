@@ -13,14 +13,19 @@ import (
 	"github.com/ooyeku/issuemap/internal/domain/entities"
 	"github.com/ooyeku/issuemap/internal/domain/errors"
 	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
 )
 
+// cleanupHistoryAuthor is recorded as the creator of checkpoints made by cleanup
+const cleanupHistoryAuthor = "issuemap-cleanup"
+
 // CleanupService handles data cleanup and retention
 type CleanupService struct {
 	basePath       string
 	configRepo     repositories.ConfigRepository
 	issueRepo      repositories.IssueRepository
 	attachmentRepo repositories.AttachmentRepository
+	historyService *HistoryService
 	config         *entities.CleanupConfig
 	mu             sync.RWMutex
 	lastCleanup    time.Time
@@ -49,10 +54,17 @@ func NewCleanupService(
 		configRepo:     configRepo,
 		issueRepo:      issueRepo,
 		attachmentRepo: attachmentRepo,
+		historyService: NewHistoryService(storage.NewFileHistoryRepository(basePath), nil),
 		config:         config,
 	}
 }
 
+// SetHistoryService sets the history service used to compact history.
+// Providing one with a git repository allows checkpoints to be signed.
+func (c *CleanupService) SetHistoryService(historyService *HistoryService) {
+	c.historyService = historyService
+}
+
 // RunCleanup performs a cleanup operation according to configuration
 func (c *CleanupService) RunCleanup(ctx context.Context, dryRun bool) (*entities.CleanupResult, error) {
 	c.mu.Lock()
@@ -253,7 +265,9 @@ func (c *CleanupService) cleanupOrphanedAttachments(ctx context.Context, result
 	return spaceReclaimed, nil
 }
 
-// cleanupHistory removes old history entries
+// cleanupHistory compacts old history entries into checkpoints. History is
+// append-only, so entries are never deleted outright: each run of old entries
+// is replaced by a checkpoint that keeps the issue's hash chain intact.
 func (c *CleanupService) cleanupHistory(ctx context.Context, result *entities.CleanupResult) (int64, error) {
 	if c.config.RetentionDays.History <= 0 {
 		return 0, nil
@@ -266,40 +280,63 @@ func (c *CleanupService) cleanupHistory(ctx context.Context, result *entities.Cl
 
 	var spaceReclaimed int64
 	cutoffDate := time.Now().Add(-time.Duration(c.config.RetentionDays.History) * 24 * time.Hour)
+	keep := c.config.MinimumKeep.HistoryPerIssue
 
-	err := filepath.Walk(historyPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
-		}
+	files, err := os.ReadDir(historyPath)
+	if err != nil {
+		return 0, errors.Wrap(err, "CleanupService.cleanupHistory", "failed to read history directory")
+	}
 
-		// Check if file is old enough
-		if info.ModTime().After(cutoffDate) {
-			return nil
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".yaml") {
+			continue
 		}
 
-		// TODO: Implement per-issue minimum history keeping logic
-		// For now, just clean based on age
+		path := filepath.Join(historyPath, file.Name())
+		issueID := entities.IssueID(strings.TrimSuffix(file.Name(), ".yaml"))
 
-		if !result.DryRun {
-			if err := os.Remove(path); err != nil {
-				result.Errors = append(result.Errors, fmt.Sprintf("failed to remove history file %s: %v", path, err))
-				return nil
+		before, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		if result.DryRun {
+			history, err := c.historyService.GetIssueHistory(ctx, issueID)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to read history for %s: %v", issueID, err))
+				continue
 			}
+			checkpoint, err := history.Compact(cutoffDate, keep, cleanupHistoryAuthor)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("failed to compact history for %s: %v", issueID, err))
+				continue
+			}
+			if checkpoint != nil {
+				result.ItemsCleaned.HistoryEntries += checkpoint.EntryCount
+			}
+			continue
 		}
 
-		spaceReclaimed += info.Size()
-		result.ItemsCleaned.HistoryEntries++
-		return nil
-	})
+		checkpoint, err := c.historyService.CompactIssueHistory(ctx, issueID, cutoffDate, keep, cleanupHistoryAuthor)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to compact history for %s: %v", issueID, err))
+			continue
+		}
+		if checkpoint == nil {
+			continue
+		}
 
-	if err != nil {
-		return spaceReclaimed, errors.Wrap(err, "CleanupService.cleanupHistory", "failed to walk history directory")
+		result.ItemsCleaned.HistoryEntries += checkpoint.EntryCount
+		if after, err := os.Stat(path); err == nil && after.Size() < before.Size() {
+			spaceReclaimed += before.Size() - after.Size()
+		}
 	}
 
 	return spaceReclaimed, nil
 }
 
-// cleanupTimeEntries removes old time entries
+// cleanupTimeEntries removes old time entries. Approved and locked entries
+// back timesheets that may have been billed, so they are always kept.
 func (c *CleanupService) cleanupTimeEntries(ctx context.Context, result *entities.CleanupResult) (int64, error) {
 	if c.config.RetentionDays.TimeEntries <= 0 {
 		return 0, nil
@@ -312,6 +349,7 @@ func (c *CleanupService) cleanupTimeEntries(ctx context.Context, result *entitie
 
 	var spaceReclaimed int64
 	cutoffDate := time.Now().Add(-time.Duration(c.config.RetentionDays.TimeEntries) * 24 * time.Hour)
+	timeEntryRepo := storage.NewFileTimeEntryRepository(c.basePath)
 
 	err := filepath.Walk(timeEntriesPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
@@ -323,6 +361,15 @@ func (c *CleanupService) cleanupTimeEntries(ctx context.Context, result *entitie
 			return nil
 		}
 
+		entry, err := timeEntryRepo.GetByID(ctx, strings.TrimSuffix(info.Name(), ".yaml"))
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to read time entry file %s: %v", path, err))
+			return nil
+		}
+		if !entry.IsEditable() {
+			return nil
+		}
+
 		if !result.DryRun {
 			if err := os.Remove(path); err != nil {
 				result.Errors = append(result.Errors, fmt.Sprintf("failed to remove time entry file %s: %v", path, err))
@@ -437,6 +484,62 @@ func (c *CleanupService) GetLastCleanup() time.Time {
 	return c.lastCleanup
 }
 
+// ScheduledJobs returns the cleanup jobs: the scheduled cleanup and a
+// periodic check of the size triggers
+func (c *CleanupService) ScheduledJobs(storageService *StorageService) []*Job {
+	c.mu.RLock()
+	schedule := c.config.Schedule
+	c.mu.RUnlock()
+	if schedule == "" {
+		schedule = entities.DefaultCleanupConfig().Schedule
+	}
+
+	enabled := func() bool {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.config.Enabled
+	}
+
+	return []*Job{
+		{
+			Name:        "cleanup",
+			Description: "Remove expired issues, attachments, history and time entries",
+			Schedule:    schedule,
+			Enabled:     enabled,
+			Run: func(ctx context.Context) (string, error) {
+				result, err := c.RunCleanup(ctx, false)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d items cleaned, %s reclaimed",
+					result.ItemsCleaned.Total, formatBytes(result.SpaceReclaimed)), nil
+			},
+		},
+		{
+			Name:        "size-check",
+			Description: "Run cleanup when storage exceeds the configured size triggers",
+			Schedule:    "*/15 * * * *",
+			Enabled:     enabled,
+			Timeout:     5 * time.Minute,
+			Run: func(ctx context.Context) (string, error) {
+				status, err := storageService.GetStorageStatus(ctx, false)
+				if err != nil {
+					return "", err
+				}
+				if !c.ShouldRunCleanup(status) {
+					return "storage within limits", nil
+				}
+				result, err := c.RunCleanup(ctx, false)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("size triggers exceeded: %d items cleaned, %s reclaimed",
+					result.ItemsCleaned.Total, formatBytes(result.SpaceReclaimed)), nil
+			},
+		},
+	}
+}
+
 // ShouldRunCleanup checks if cleanup should be triggered
 func (c *CleanupService) ShouldRunCleanup(storageStatus *entities.StorageStatus) bool {
 	if !c.config.Enabled {
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestCleanupService_KeepsApprovedAndLockedTimeEntries(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	configRepo := storage.NewFileConfigRepository(basePath)
+	require.NoError(t, configRepo.Initialize(ctx, entities.NewDefaultConfig()))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	timeEntryRepo := storage.NewFileTimeEntryRepository(basePath)
+
+	cleanupService := NewCleanupService(basePath, configRepo, issueRepo, storage.NewFileAttachmentRepository(basePath))
+	require.NoError(t, cleanupService.UpdateConfig(&entities.CleanupConfig{
+		Enabled:       true,
+		RetentionDays: entities.CleanupRetention{TimeEntries: 365},
+	}))
+
+	statuses := []entities.TimeEntryStatus{
+		entities.TimeEntryStatusDraft,
+		entities.TimeEntryStatusSubmitted,
+		entities.TimeEntryStatusApproved,
+		entities.TimeEntryStatusLocked,
+	}
+	old := time.Now().AddDate(-2, 0, 0)
+	ids := make(map[entities.TimeEntryStatus]string)
+	for _, status := range statuses {
+		entry := entities.NewTimeEntry("TEST-001", entities.TimeEntryTypeManual, time.Hour, "", "alice")
+		for _, next := range statuses[1:] {
+			if entry.ApprovalStatus() == status {
+				break
+			}
+			require.NoError(t, entry.SetApprovalStatus(next, "bob"))
+		}
+		require.NoError(t, timeEntryRepo.Create(ctx, entry))
+		path := filepath.Join(basePath, "time_entries", entry.ID+".yaml")
+		require.NoError(t, os.Chtimes(path, old, old))
+		ids[status] = entry.ID
+	}
+
+	result, err := cleanupService.RunCleanup(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 2, result.ItemsCleaned.TimeEntries)
+
+	for _, status := range statuses {
+		_, err := timeEntryRepo.GetByID(ctx, ids[status])
+		if status == entities.TimeEntryStatusApproved || status == entities.TimeEntryStatusLocked {
+			assert.NoError(t, err, "%s entries survive the retention sweep", status)
+		} else {
+			assert.Error(t, err, "%s entries are removed by the retention sweep", status)
+		}
+	}
+}
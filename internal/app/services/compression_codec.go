@@ -0,0 +1,152 @@
+package services
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// CompressionCodec compresses and decompresses attachment content
+type CompressionCodec interface {
+	// Name is the codec name recorded in attachment compression metadata
+	Name() string
+
+	// NewWriter returns a writer compressing into w. Level uses the gzip
+	// scale of 1 (fastest) to 9 (smallest); codecs map it to their own.
+	NewWriter(w io.Writer, level int) (io.WriteCloser, error)
+
+	// NewReader returns a reader decompressing r
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = map[string]CompressionCodec{}
+)
+
+func init() {
+	RegisterCompressionCodec(gzipCodec{})
+	RegisterCompressionCodec(zstdCodec{})
+	RegisterCompressionCodec(noneCodec{})
+}
+
+// RegisterCompressionCodec makes a codec available by name, replacing any
+// codec already registered under that name
+func RegisterCompressionCodec(codec CompressionCodec) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[codec.Name()] = codec
+}
+
+// GetCompressionCodec returns the codec registered under name
+func GetCompressionCodec(name string) (CompressionCodec, error) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	codec, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown compression codec %q", name)
+	}
+	return codec, nil
+}
+
+// CompressionCodecNames returns the names of all registered codecs
+func CompressionCodecNames() []string {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenAttachmentContent wraps stored attachment content in a reader that
+// decompresses it according to the attachment's compression metadata
+func OpenAttachmentContent(attachment *entities.Attachment, stored io.Reader) (io.ReadCloser, error) {
+	if attachment.Compression == nil || !attachment.Compression.Compressed {
+		return io.NopCloser(stored), nil
+	}
+
+	codec, err := GetCompressionCodec(attachment.Compression.Codec())
+	if err != nil {
+		return nil, err
+	}
+	return codec.NewReader(stored)
+}
+
+// gzipCodec is the original attachment codec
+type gzipCodec struct{}
+
+func (gzipCodec) Name() string { return entities.CompressionCodecGzip }
+
+func (gzipCodec) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+	return gzip.NewWriterLevel(w, level)
+}
+
+func (gzipCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// zstdCodec compresses better and faster than gzip at comparable levels
+type zstdCodec struct{}
+
+func (zstdCodec) Name() string { return entities.CompressionCodecZstd }
+
+func (zstdCodec) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	return zstd.NewWriter(w, zstd.WithEncoderLevel(zstdLevel(level)))
+}
+
+func (zstdCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return decoder.IOReadCloser(), nil
+}
+
+// zstdLevel maps a gzip-scale level onto the zstd encoder's speed presets
+func zstdLevel(level int) zstd.EncoderLevel {
+	switch {
+	case level <= 0:
+		return zstd.SpeedDefault
+	case level <= 2:
+		return zstd.SpeedFastest
+	case level <= 5:
+		return zstd.SpeedDefault
+	case level <= 7:
+		return zstd.SpeedBetterCompression
+	default:
+		return zstd.SpeedBestCompression
+	}
+}
+
+// noneCodec stores content as-is; selecting it disables compression
+type noneCodec struct{}
+
+func (noneCodec) Name() string { return entities.CompressionCodecNone }
+
+func (noneCodec) NewWriter(w io.Writer, level int) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (noneCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
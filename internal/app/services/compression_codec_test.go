@@ -0,0 +1,179 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+var compressionFixtures = []string{"server.log", "issues.json", "metrics.csv", "random.bin"}
+
+func loadCompressionFixture(tb testing.TB, name string) []byte {
+	tb.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "compression", name))
+	require.NoError(tb, err)
+	return data
+}
+
+func compressWith(tb testing.TB, codec CompressionCodec, level int, data []byte) []byte {
+	tb.Helper()
+	var compressed bytes.Buffer
+	writer, err := codec.NewWriter(&compressed, level)
+	require.NoError(tb, err)
+	_, err = writer.Write(data)
+	require.NoError(tb, err)
+	require.NoError(tb, writer.Close())
+	return compressed.Bytes()
+}
+
+func TestCompressionCodecs_RoundTrip(t *testing.T) {
+	for _, name := range CompressionCodecNames() {
+		codec, err := GetCompressionCodec(name)
+		require.NoError(t, err)
+
+		for _, fixture := range compressionFixtures {
+			data := loadCompressionFixture(t, fixture)
+			compressed := compressWith(t, codec, 6, data)
+
+			reader, err := codec.NewReader(bytes.NewReader(compressed))
+			require.NoError(t, err)
+			decompressed, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			require.NoError(t, reader.Close())
+
+			assert.Equal(t, data, decompressed, "%s round trip of %s", name, fixture)
+		}
+	}
+
+	_, err := GetCompressionCodec("lz77")
+	assert.Error(t, err)
+}
+
+func TestOpenAttachmentContent_UsesRecordedCodec(t *testing.T) {
+	data := loadCompressionFixture(t, "server.log")
+	zstd, err := GetCompressionCodec(entities.CompressionCodecZstd)
+	require.NoError(t, err)
+	compressed := compressWith(t, zstd, 6, data)
+
+	attachment := &entities.Attachment{
+		Compression: &entities.CompressionMetadata{Compressed: true, Algorithm: entities.CompressionCodecZstd},
+	}
+	reader, err := OpenAttachmentContent(attachment, bytes.NewReader(compressed))
+	require.NoError(t, err)
+	defer reader.Close()
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, decompressed)
+
+	// Metadata written before codecs were recorded is gzip
+	gzip, err := GetCompressionCodec(entities.CompressionCodecGzip)
+	require.NoError(t, err)
+	attachment.Compression.Algorithm = ""
+	reader, err = OpenAttachmentContent(attachment, bytes.NewReader(compressWith(t, gzip, 6, data)))
+	require.NoError(t, err)
+	decompressed, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, data, decompressed)
+}
+
+func TestCompressionService_RecompressAttachments(t *testing.T) {
+	basePath := t.TempDir()
+	ctx := context.Background()
+
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+	attachmentRepo := storage.NewFileAttachmentRepository(basePath)
+
+	issue := entities.NewIssue("TEST-001", "Logs", "", entities.IssueTypeBug)
+	require.NoError(t, issueRepo.Create(ctx, issue))
+
+	compressionService := NewCompressionService(basePath, configRepo, attachmentRepo)
+	attachmentService := NewAttachmentService(attachmentRepo, issueRepo, nil, basePath)
+	attachmentService.SetCompressionService(compressionService)
+
+	data := loadCompressionFixture(t, "server.log")
+	attachment, err := attachmentService.UploadAttachment(ctx, issue.ID, "server.txt", bytes.NewReader(data), int64(len(data)), "tester")
+	require.NoError(t, err)
+
+	stored, err := attachmentRepo.GetMetadata(ctx, attachment.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Compression)
+	assert.Equal(t, entities.CompressionCodecGzip, stored.Compression.Codec())
+
+	// A dry run measures without changing anything
+	result, err := compressionService.RecompressAttachments(ctx, entities.CompressionCodecZstd, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Files)
+	stored, err = attachmentRepo.GetMetadata(ctx, attachment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CompressionCodecGzip, stored.Compression.Codec())
+
+	result, err = compressionService.RecompressAttachments(ctx, entities.CompressionCodecZstd, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Files)
+	assert.Equal(t, 1, result.Attachments)
+	assert.Empty(t, result.Failed)
+
+	stored, err = attachmentRepo.GetMetadata(ctx, attachment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CompressionCodecZstd, stored.Compression.Codec())
+
+	// Content is decompressed transparently with the new codec
+	reader, _, err := attachmentService.GetAttachmentContent(ctx, attachment.ID)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	reader.Close()
+	assert.Equal(t, data, content)
+
+	// Running again has nothing to do
+	result, err = compressionService.RecompressAttachments(ctx, entities.CompressionCodecZstd, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Files)
+	assert.Equal(t, 1, result.Skipped)
+}
+
+// BenchmarkCompressionCodecs compares codecs on the fixtures. Throughput is
+// reported as MB/s of original content; ratio is compressed/original size.
+//
+//	go test ./internal/app/services -run '^$' -bench CompressionCodecs
+func BenchmarkCompressionCodecs(b *testing.B) {
+	for _, fixture := range compressionFixtures {
+		data := loadCompressionFixture(b, fixture)
+
+		for _, name := range CompressionCodecNames() {
+			codec, err := GetCompressionCodec(name)
+			require.NoError(b, err)
+			compressed := compressWith(b, codec, 6, data)
+			ratio := float64(len(compressed)) / float64(len(data))
+
+			b.Run(fixture+"/"+name+"/compress", func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				b.ReportMetric(ratio, "ratio")
+				for i := 0; i < b.N; i++ {
+					writer, _ := codec.NewWriter(io.Discard, 6)
+					writer.Write(data)
+					writer.Close()
+				}
+			})
+
+			b.Run(fixture+"/"+name+"/decompress", func(b *testing.B) {
+				b.SetBytes(int64(len(data)))
+				for i := 0; i < b.N; i++ {
+					reader, _ := codec.NewReader(bytes.NewReader(compressed))
+					io.Copy(io.Discard, reader)
+					reader.Close()
+				}
+			})
+		}
+	}
+}
@@ -1,15 +1,16 @@
 package services
 
 import (
-	"compress/gzip"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -17,6 +18,10 @@ import (
 	"github.com/ooyeku/issuemap/internal/domain/repositories"
 )
 
+// compressionBatchSize is the number of files the scheduled batch job
+// compresses per run
+const compressionBatchSize = 500
+
 // CompressionService handles file compression for attachments
 type CompressionService struct {
 	basePath       string
@@ -92,6 +97,13 @@ func (s *CompressionService) GetStats() *entities.CompressionStats {
 
 // CompressFile compresses a file if it meets the compression criteria
 func (s *CompressionService) CompressFile(sourcePath, destPath string) (*entities.CompressionResult, error) {
+	filename := filepath.Base(sourcePath)
+	return s.compressFile(sourcePath, destPath, filename, mime.TypeByExtension(filepath.Ext(filename)))
+}
+
+// compressFile compresses a file with the codec the configuration selects for
+// its name, content type and size
+func (s *CompressionService) compressFile(sourcePath, destPath, filename, contentType string) (*entities.CompressionResult, error) {
 	start := time.Now()
 	result := &entities.CompressionResult{
 		Success: false,
@@ -107,8 +119,6 @@ func (s *CompressionService) CompressFile(sourcePath, destPath string) (*entitie
 	result.OriginalSize = sourceInfo.Size()
 	result.FinalSize = sourceInfo.Size()
 
-	filename := filepath.Base(sourcePath)
-
 	// Check if file should be compressed
 	if !s.config.ShouldCompress(filename, sourceInfo.Size()) {
 		result.Success = true
@@ -117,6 +127,20 @@ func (s *CompressionService) CompressFile(sourcePath, destPath string) (*entitie
 		return result, nil
 	}
 
+	codecName, level := s.config.CodecFor(filename, contentType, sourceInfo.Size())
+	if codecName == entities.CompressionCodecNone {
+		result.Success = true
+		result.Reason = "compression policy selects no codec"
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	codec, err := GetCompressionCodec(codecName)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
 	// Calculate original checksum
 	originalChecksum, err := s.calculateChecksum(sourcePath)
 	if err != nil {
@@ -125,7 +149,7 @@ func (s *CompressionService) CompressFile(sourcePath, destPath string) (*entitie
 	}
 
 	// Create compressed version
-	compressedSize, compressedChecksum, err := s.createCompressedFile(sourcePath, destPath)
+	compressedSize, compressedChecksum, err := s.createCompressedFile(sourcePath, destPath, codec, level)
 	if err != nil {
 		result.Error = fmt.Sprintf("compression failed: %v", err)
 		return result, err
@@ -135,11 +159,11 @@ func (s *CompressionService) CompressFile(sourcePath, destPath string) (*entitie
 	compressionRatio := float64(compressedSize) / float64(sourceInfo.Size())
 
 	// Check if compression is worthwhile
-	if compressionRatio >= s.config.MinCompressionRatio {
+	if 1-compressionRatio < s.config.MinCompressionRatio {
 		// Compression didn't save enough space, remove compressed file
 		os.Remove(destPath)
 		result.Success = true
-		result.Reason = fmt.Sprintf("compression ratio %.2f%% below threshold", compressionRatio*100)
+		result.Reason = fmt.Sprintf("compression ratio %.2f%% saves less than the %.0f%% threshold", compressionRatio*100, s.config.MinCompressionRatio*100)
 		result.CompressionRatio = compressionRatio
 		result.Duration = time.Since(start)
 		return result, nil
@@ -158,8 +182,8 @@ func (s *CompressionService) CompressFile(sourcePath, destPath string) (*entitie
 		OriginalSize:       sourceInfo.Size(),
 		CompressedSize:     compressedSize,
 		CompressionRatio:   compressionRatio,
-		Algorithm:          "gzip",
-		Level:              s.config.Level,
+		Algorithm:          codec.Name(),
+		Level:              level,
 		CompressedAt:       time.Now(),
 		OriginalChecksum:   originalChecksum,
 		CompressedChecksum: compressedChecksum,
@@ -168,28 +192,36 @@ func (s *CompressionService) CompressFile(sourcePath, destPath string) (*entitie
 	return result, nil
 }
 
-// DecompressFile decompresses a gzip-compressed file
-func (s *CompressionService) DecompressFile(sourcePath, destPath string) error {
+// DecompressFile decompresses a file compressed with the named codec
+func (s *CompressionService) DecompressFile(sourcePath, destPath, codecName string) error {
 	sourceFile, err := os.Open(sourcePath)
 	if err != nil {
 		return fmt.Errorf("failed to open compressed file: %w", err)
 	}
 	defer sourceFile.Close()
 
-	gzReader, err := gzip.NewReader(sourceFile)
+	codec, err := GetCompressionCodec(codecName)
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return err
+	}
+	reader, err := codec.NewReader(sourceFile)
+	if err != nil {
+		return fmt.Errorf("failed to create %s reader: %w", codecName, err)
 	}
-	defer gzReader.Close()
+	defer reader.Close()
+
+	return writeFileFrom(reader, destPath)
+}
 
+// writeFileFrom writes all of content to destPath, removing it on failure
+func writeFileFrom(content io.Reader, destPath string) error {
 	destFile, err := os.Create(destPath)
 	if err != nil {
 		return fmt.Errorf("failed to create destination file: %w", err)
 	}
 	defer destFile.Close()
 
-	_, err = io.Copy(destFile, gzReader)
-	if err != nil {
+	if _, err := io.Copy(destFile, content); err != nil {
 		os.Remove(destPath) // Clean up on failure
 		return fmt.Errorf("failed to decompress file: %w", err)
 	}
@@ -208,10 +240,30 @@ func (s *CompressionService) CompressAttachment(ctx context.Context, attachment
 		}, nil
 	}
 
+	// Encrypted content does not compress
+	if attachment.IsEncrypted() {
+		return &entities.CompressionResult{
+			Success:      true,
+			Reason:       "attachment is encrypted",
+			OriginalSize: attachment.Size,
+			FinalSize:    attachment.Size,
+		}, nil
+	}
+
+	// Only plain local files are compressed in place
+	if !entities.IsLocalStoragePath(attachment.StoragePath) {
+		return &entities.CompressionResult{
+			Success:      true,
+			Reason:       "attachment is stored in a remote blob backend",
+			OriginalSize: attachment.Size,
+			FinalSize:    attachment.Size,
+		}, nil
+	}
+
 	sourcePath := filepath.Join(s.basePath, attachment.StoragePath)
-	compressedPath := sourcePath + ".gz"
+	compressedPath := sourcePath + ".compressed"
 
-	result, err := s.CompressFile(sourcePath, compressedPath)
+	result, err := s.compressFile(sourcePath, compressedPath, attachment.Filename, attachment.ContentType)
 	if err != nil {
 		return result, err
 	}
@@ -233,7 +285,7 @@ func (s *CompressionService) CompressAttachment(ctx context.Context, attachment
 		// Update attachment in repository
 		if err := s.attachmentRepo.SaveMetadata(ctx, attachment); err != nil {
 			// Try to restore original by decompressing
-			if decompErr := s.DecompressFile(sourcePath, sourcePath+".orig"); decompErr == nil {
+			if decompErr := s.DecompressFile(sourcePath, sourcePath+".orig", result.Metadata.Codec()); decompErr == nil {
 				os.Rename(sourcePath+".orig", sourcePath)
 			}
 			return result, fmt.Errorf("failed to update attachment metadata: %w", err)
@@ -243,17 +295,168 @@ func (s *CompressionService) CompressAttachment(ctx context.Context, attachment
 	return result, nil
 }
 
-// DecompressAttachment decompresses an attachment for retrieval
+// DecompressAttachment decompresses an attachment for retrieval, using the
+// codec recorded in its compression metadata
 func (s *CompressionService) DecompressAttachment(attachment *entities.Attachment, destPath string) error {
-	if attachment.Compression == nil || !attachment.Compression.Compressed {
-		// File is not compressed, just copy it
-		sourcePath := filepath.Join(s.basePath, attachment.StoragePath)
-		return s.copyFile(sourcePath, destPath)
+	var stored io.ReadCloser
+	if entities.IsLocalStoragePath(attachment.StoragePath) {
+		file, err := os.Open(filepath.Join(s.basePath, attachment.StoragePath))
+		if err != nil {
+			return fmt.Errorf("failed to open attachment content: %w", err)
+		}
+		stored = file
+	} else {
+		content, err := s.attachmentRepo.GetFile(context.Background(), attachment.StoragePath)
+		if err != nil {
+			return fmt.Errorf("failed to open attachment content: %w", err)
+		}
+		stored = content
 	}
+	defer stored.Close()
 
-	// File is compressed, decompress it
-	sourcePath := filepath.Join(s.basePath, attachment.StoragePath)
-	return s.DecompressFile(sourcePath, destPath)
+	content, err := OpenAttachmentContent(attachment, stored)
+	if err != nil {
+		return fmt.Errorf("failed to create decompressor: %w", err)
+	}
+	defer content.Close()
+
+	return writeFileFrom(content, destPath)
+}
+
+// RecompressAttachments converts every locally stored attachment to codec,
+// reporting the space saved. Attachments sharing a deduplicated file are
+// converted together. A dry run compresses to temporary files to measure the
+// result but leaves the stored files and metadata unchanged.
+func (s *CompressionService) RecompressAttachments(ctx context.Context, codecName string, dryRun bool) (*entities.RecompressionResult, error) {
+	start := time.Now()
+
+	codec, err := GetCompressionCodec(codecName)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	result := &entities.RecompressionResult{
+		Codec:  codec.Name(),
+		DryRun: dryRun,
+		Failed: make(map[string]string),
+	}
+
+	// Group attachments by the file they are stored in
+	byPath := make(map[string][]*entities.Attachment)
+	var paths []string
+	for _, attachment := range attachments {
+		if attachment.StoragePath == "" || attachment.IsEncrypted() {
+			continue
+		}
+		if _, ok := byPath[attachment.StoragePath]; !ok {
+			paths = append(paths, attachment.StoragePath)
+		}
+		byPath[attachment.StoragePath] = append(byPath[attachment.StoragePath], attachment)
+	}
+	sort.Strings(paths)
+
+	for _, storagePath := range paths {
+		refs := byPath[storagePath]
+
+		current := entities.CompressionCodecNone
+		if refs[0].Compression != nil && refs[0].Compression.Compressed {
+			current = refs[0].Compression.Codec()
+		}
+		if current == codec.Name() || !entities.IsLocalStoragePath(storagePath) {
+			result.Skipped += len(refs)
+			continue
+		}
+
+		before, after, err := s.recompressFile(ctx, refs, codec, dryRun)
+		if err != nil {
+			result.Failed[storagePath] = err.Error()
+			continue
+		}
+
+		result.Files++
+		result.Attachments += len(refs)
+		result.BytesBefore += before
+		result.BytesAfter += after
+	}
+
+	if len(result.Failed) == 0 {
+		result.Failed = nil
+	}
+	result.DurationSecs = time.Since(start).Seconds()
+
+	return result, nil
+}
+
+// recompressFile rewrites one stored file with codec and updates the metadata
+// of every attachment referencing it, returning the sizes before and after
+func (s *CompressionService) recompressFile(ctx context.Context, refs []*entities.Attachment, codec CompressionCodec, dryRun bool) (int64, int64, error) {
+	sourcePath := filepath.Join(s.basePath, refs[0].StoragePath)
+	tempPath := sourcePath + ".recompress"
+
+	sourceInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer source.Close()
+
+	content, err := OpenAttachmentContent(refs[0], source)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer content.Close()
+
+	level := s.config.Level
+	originalSize, compressedSize, originalChecksum, compressedChecksum, err := s.writeCompressed(content, tempPath, codec, level)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if dryRun {
+		os.Remove(tempPath)
+		return sourceInfo.Size(), compressedSize, nil
+	}
+
+	if err := os.Rename(tempPath, sourcePath); err != nil {
+		os.Remove(tempPath)
+		return 0, 0, err
+	}
+
+	var metadata *entities.CompressionMetadata
+	size := originalSize
+	if codec.Name() != entities.CompressionCodecNone {
+		metadata = &entities.CompressionMetadata{
+			Compressed:         true,
+			OriginalSize:       originalSize,
+			CompressedSize:     compressedSize,
+			CompressionRatio:   float64(compressedSize) / float64(originalSize),
+			Algorithm:          codec.Name(),
+			Level:              level,
+			CompressedAt:       time.Now(),
+			OriginalChecksum:   originalChecksum,
+			CompressedChecksum: compressedChecksum,
+		}
+		size = compressedSize
+	}
+
+	for _, attachment := range refs {
+		attachment.Compression = metadata
+		attachment.Size = size
+		if err := s.attachmentRepo.SaveMetadata(ctx, attachment); err != nil {
+			return 0, 0, fmt.Errorf("file was recompressed but metadata for %s could not be saved: %w", attachment.ID, err)
+		}
+	}
+
+	return sourceInfo.Size(), compressedSize, nil
 }
 
 // Start begins background compression workers
@@ -383,51 +586,58 @@ func (s *CompressionService) processBackgroundJob(ctx context.Context, job *enti
 	job.CompletedAt = &[]time.Time{time.Now()}[0]
 }
 
-// createCompressedFile creates a gzip-compressed version of a file
-func (s *CompressionService) createCompressedFile(sourcePath, destPath string) (int64, string, error) {
+// createCompressedFile creates a compressed version of a file
+func (s *CompressionService) createCompressedFile(sourcePath, destPath string, codec CompressionCodec, level int) (int64, string, error) {
 	sourceFile, err := os.Open(sourcePath)
 	if err != nil {
 		return 0, "", err
 	}
 	defer sourceFile.Close()
 
+	_, compressedSize, _, compressedChecksum, err := s.writeCompressed(sourceFile, destPath, codec, level)
+	return compressedSize, compressedChecksum, err
+}
+
+// writeCompressed compresses content into destPath, returning the original
+// and compressed sizes and SHA-256 checksums
+func (s *CompressionService) writeCompressed(content io.Reader, destPath string, codec CompressionCodec, level int) (int64, int64, string, string, error) {
 	destFile, err := os.Create(destPath)
 	if err != nil {
-		return 0, "", err
+		return 0, 0, "", "", err
 	}
 	defer destFile.Close()
 
-	// Create gzip writer with specified compression level
-	gzWriter, err := gzip.NewWriterLevel(destFile, s.config.Level)
+	compressedHasher := sha256.New()
+	writer, err := codec.NewWriter(io.MultiWriter(destFile, compressedHasher), level)
 	if err != nil {
 		os.Remove(destPath)
-		return 0, "", err
+		return 0, 0, "", "", err
 	}
-	defer gzWriter.Close()
 
-	// Create hasher for checksum
-	hasher := sha256.New()
-	multiWriter := io.MultiWriter(gzWriter, hasher)
-
-	// Copy and compress
-	_, err = io.Copy(multiWriter, sourceFile)
+	// Hash the original content as it is compressed
+	originalHasher := sha256.New()
+	originalSize, err := io.Copy(writer, io.TeeReader(content, originalHasher))
 	if err != nil {
+		writer.Close()
 		os.Remove(destPath)
-		return 0, "", err
+		return 0, 0, "", "", err
 	}
 
-	// Close gzip writer to flush
-	gzWriter.Close()
+	// Close the codec writer to flush
+	if err := writer.Close(); err != nil {
+		os.Remove(destPath)
+		return 0, 0, "", "", err
+	}
 
-	// Get file size
 	info, err := destFile.Stat()
 	if err != nil {
 		os.Remove(destPath)
-		return 0, "", err
+		return 0, 0, "", "", err
 	}
 
-	checksum := hex.EncodeToString(hasher.Sum(nil))
-	return info.Size(), checksum, nil
+	return originalSize, info.Size(),
+		hex.EncodeToString(originalHasher.Sum(nil)),
+		hex.EncodeToString(compressedHasher.Sum(nil)), nil
 }
 
 // calculateChecksum calculates SHA256 checksum of a file
@@ -446,24 +656,6 @@ func (s *CompressionService) calculateChecksum(filePath string) (string, error)
 	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
-// copyFile copies a file from source to destination
-func (s *CompressionService) copyFile(sourcePath, destPath string) error {
-	sourceFile, err := os.Open(sourcePath)
-	if err != nil {
-		return err
-	}
-	defer sourceFile.Close()
-
-	destFile, err := os.Create(destPath)
-	if err != nil {
-		return err
-	}
-	defer destFile.Close()
-
-	_, err = io.Copy(destFile, sourceFile)
-	return err
-}
-
 // updateStats updates compression statistics
 func (s *CompressionService) updateStats(filename string, result *entities.CompressionResult) {
 	s.mu.Lock()
@@ -511,16 +703,120 @@ func (s *CompressionService) saveStats() error {
 	return os.WriteFile(statsPath, data, 0644)
 }
 
-// RunBatchCompression compresses a batch of existing attachments
-func (s *CompressionService) RunBatchCompression(ctx context.Context, batchSize int) (*entities.CompressionResult, error) {
+// RunBatchCompression compresses up to batchSize locally stored files that
+// have not been considered for compression yet. Files that are not worth
+// compressing are marked as such so later batches move on to other files.
+func (s *CompressionService) RunBatchCompression(ctx context.Context, batchSize int) (*entities.RecompressionResult, error) {
 	start := time.Now()
-	result := &entities.CompressionResult{
-		Success: true,
+	result := &entities.RecompressionResult{
+		Codec:  s.config.Codec,
+		Failed: make(map[string]string),
 	}
 
-	// Get uncompressed attachments
-	// This would need to be implemented in the attachment repository
-	// For now, we'll return a placeholder
-	result.Duration = time.Since(start)
+	if !s.config.Enabled {
+		return result, nil
+	}
+
+	attachments, err := s.attachmentRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+
+	byPath := make(map[string][]*entities.Attachment)
+	var paths []string
+	for _, attachment := range attachments {
+		if attachment.StoragePath == "" || attachment.Compression != nil || attachment.IsEncrypted() ||
+			!entities.IsLocalStoragePath(attachment.StoragePath) {
+			continue
+		}
+		if _, ok := byPath[attachment.StoragePath]; !ok {
+			paths = append(paths, attachment.StoragePath)
+		}
+		byPath[attachment.StoragePath] = append(byPath[attachment.StoragePath], attachment)
+	}
+	sort.Strings(paths)
+
+	for i, storagePath := range paths {
+		if batchSize > 0 && i >= batchSize {
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		refs := byPath[storagePath]
+		sourcePath := filepath.Join(s.basePath, storagePath)
+		compressedPath := sourcePath + ".compressed"
+
+		fileResult, err := s.compressFile(sourcePath, compressedPath, refs[0].Filename, refs[0].ContentType)
+		if err != nil {
+			result.Failed[storagePath] = err.Error()
+			continue
+		}
+		s.updateStats(refs[0].Filename, fileResult)
+
+		// Record files that were not worth compressing so they are not
+		// evaluated again
+		metadata := &entities.CompressionMetadata{
+			OriginalSize: fileResult.OriginalSize,
+			CompressedAt: time.Now(),
+		}
+		size := fileResult.OriginalSize
+		if fileResult.Compressed {
+			if err := os.Rename(compressedPath, sourcePath); err != nil {
+				os.Remove(compressedPath)
+				result.Failed[storagePath] = err.Error()
+				continue
+			}
+			metadata = fileResult.Metadata
+			size = fileResult.FinalSize
+			result.Files++
+			result.Attachments += len(refs)
+			result.BytesBefore += fileResult.OriginalSize
+			result.BytesAfter += fileResult.FinalSize
+		} else {
+			result.Skipped += len(refs)
+		}
+
+		for _, attachment := range refs {
+			attachment.Compression = metadata
+			attachment.Size = size
+			if err := s.attachmentRepo.SaveMetadata(ctx, attachment); err != nil {
+				result.Failed[storagePath] = fmt.Sprintf("metadata for %s could not be saved: %v", attachment.ID, err)
+			}
+		}
+	}
+
+	if len(result.Failed) == 0 {
+		result.Failed = nil
+	}
+	result.DurationSecs = time.Since(start).Seconds()
+
 	return result, nil
 }
+
+// ScheduledJobs returns the batch compression job
+func (s *CompressionService) ScheduledJobs() []*Job {
+	return []*Job{
+		{
+			Name:        "compression",
+			Description: "Compress existing attachments that were stored uncompressed",
+			Schedule:    "30 1 * * *",
+			Enabled: func() bool {
+				return s.GetConfig().Enabled
+			},
+			Run: func(ctx context.Context) (string, error) {
+				result, err := s.RunBatchCompression(ctx, compressionBatchSize)
+				if err != nil {
+					return "", err
+				}
+				message := fmt.Sprintf("%d files compressed, %s saved, %d not worth compressing",
+					result.Files, formatBytes(result.BytesSaved()), result.Skipped)
+				if len(result.Failed) > 0 {
+					return message, fmt.Errorf("%d files failed to compress", len(result.Failed))
+				}
+				return message, nil
+			},
+		},
+	}
+}
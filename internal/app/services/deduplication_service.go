@@ -77,18 +77,7 @@ func (d *DeduplicationService) UpdateConfig(config *entities.DeduplicationConfig
 
 // CalculateFileHash calculates the hash of a file
 func (d *DeduplicationService) CalculateFileHash(reader io.Reader) (string, int64, error) {
-	var hasher hash.Hash
-
-	switch d.config.HashAlgorithm {
-	case "sha256":
-		hasher = sha256.New()
-	case "sha1":
-		hasher = sha1.New()
-	case "md5":
-		hasher = md5.New()
-	default:
-		hasher = sha256.New() // Default to SHA-256
-	}
+	hasher := d.NewHasher()
 
 	size, err := io.Copy(hasher, reader)
 	if err != nil {
@@ -101,6 +90,34 @@ func (d *DeduplicationService) CalculateFileHash(reader io.Reader) (string, int6
 	return hashString, size, nil
 }
 
+// HashAlgorithm returns the configured hash algorithm
+func (d *DeduplicationService) HashAlgorithm() string {
+	switch d.config.HashAlgorithm {
+	case "sha1", "md5":
+		return d.config.HashAlgorithm
+	default:
+		return "sha256" // Default to SHA-256
+	}
+}
+
+// NewHasher returns a hash for the configured algorithm, for callers that
+// compute file hashes incrementally
+func (d *DeduplicationService) NewHasher() hash.Hash {
+	return NewHasher(d.HashAlgorithm())
+}
+
+// NewHasher returns a hash for a deduplication hash algorithm name
+func NewHasher(algorithm string) hash.Hash {
+	switch algorithm {
+	case "sha1":
+		return sha1.New()
+	case "md5":
+		return md5.New()
+	default:
+		return sha256.New()
+	}
+}
+
 // CalculateFileHashFromPath calculates the hash of a file by path
 func (d *DeduplicationService) CalculateFileHashFromPath(filePath string) (string, int64, error) {
 	file, err := os.Open(filePath)
@@ -252,6 +269,25 @@ func (d *DeduplicationService) RemoveReference(attachmentID string, fileHash str
 	return nil
 }
 
+// HasHash reports whether a deduplicated file is still indexed for the hash
+func (d *DeduplicationService) HasHash(fileHash string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	_, exists := d.hashIndex[fileHash]
+	return exists
+}
+
+// GetReferences returns the attachments referencing a deduplicated file
+func (d *DeduplicationService) GetReferences(fileHash string) []*entities.FileReference {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	refs := make([]*entities.FileReference, len(d.references[fileHash]))
+	copy(refs, d.references[fileHash])
+	return refs
+}
+
 // GetDeduplicationStats calculates deduplication statistics
 func (d *DeduplicationService) GetDeduplicationStats() (*entities.DeduplicationStats, error) {
 	d.mu.RLock()
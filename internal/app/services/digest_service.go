@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// DigestSender delivers a digest email to one address
+type DigestSender interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// DigestService emails each person in the user directory a digest of the
+// notifications they have not read and that no earlier digest included
+type DigestService struct {
+	notificationRepo repositories.NotificationRepository
+	configRepo       repositories.ConfigRepository
+	config           *entities.DigestConfig
+	sender           DigestSender
+	mu               sync.Mutex
+}
+
+// NewDigestService creates a new digest service that sends through the
+// configured SMTP server
+func NewDigestService(
+	notificationRepo repositories.NotificationRepository,
+	configRepo repositories.ConfigRepository,
+) *DigestService {
+	config := entities.DefaultDigestConfig()
+
+	// Try to load config from repository
+	if configRepo != nil {
+		if cfg, err := configRepo.Load(context.Background()); err == nil && cfg != nil && cfg.Digest != nil {
+			config = cfg.Digest
+		}
+	}
+	if config.Subject == "" {
+		config.Subject = entities.DefaultDigestConfig().Subject
+	}
+
+	return &DigestService{
+		notificationRepo: notificationRepo,
+		configRepo:       configRepo,
+		config:           config,
+		sender:           &smtpDigestSender{config: config},
+	}
+}
+
+// SetSender overrides how digests are delivered
+func (s *DigestService) SetSender(sender DigestSender) {
+	s.sender = sender
+}
+
+// GetConfig returns the digest configuration
+func (s *DigestService) GetConfig() *entities.DigestConfig {
+	return s.config
+}
+
+// Send emails a digest to everyone with pending notifications and marks the
+// notifications as digested. They stay unread until their recipient reads them.
+func (s *DigestService) Send(ctx context.Context) (*entities.DigestResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.notificationRepo == nil {
+		return nil, errors.New("DigestService.Send", "no_notifications", fmt.Errorf("notifications are not available"))
+	}
+
+	result := &entities.DigestResult{Timestamp: time.Now()}
+	for _, profile := range loadUserDirectory(ctx, s.configRepo).Users() {
+		unread, err := s.notificationRepo.List(ctx, profile.Handle, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "DigestService.Send", "list_notifications")
+		}
+
+		var pending []*entities.IssueNotification
+		var ids []string
+		for _, notification := range unread {
+			if !notification.Digested {
+				pending = append(pending, notification)
+				ids = append(ids, notification.ID)
+			}
+		}
+		if len(pending) == 0 {
+			continue
+		}
+		if len(profile.Emails) == 0 {
+			result.NoEmail = append(result.NoEmail, profile.Handle)
+			continue
+		}
+
+		digest := &entities.NotificationDigest{
+			Recipient:     profile.Handle,
+			Email:         profile.Emails[0],
+			Notifications: pending,
+		}
+		if err := s.sender.Send(ctx, digest.Email, s.subject(len(pending)), digest.Body()); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", profile.Handle, err))
+			continue
+		}
+		if err := s.notificationRepo.MarkDigested(ctx, profile.Handle, ids); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: digest sent but not recorded: %v", profile.Handle, err))
+		}
+		result.Digests = append(result.Digests, digest)
+	}
+
+	return result, nil
+}
+
+// subject returns the subject line of a digest with count notifications
+func (s *DigestService) subject(count int) string {
+	if strings.Contains(s.config.Subject, "%d") {
+		return fmt.Sprintf(s.config.Subject, count)
+	}
+	return s.config.Subject
+}
+
+// ScheduledJobs returns the digest job
+func (s *DigestService) ScheduledJobs() []*Job {
+	return []*Job{
+		{
+			Name:        "digest",
+			Description: "Email each user a digest of their unread notifications",
+			Schedule:    "0 8 * * *",
+			Enabled: func() bool {
+				return s.GetConfig().Enabled
+			},
+			Run: func(ctx context.Context) (string, error) {
+				result, err := s.Send(ctx)
+				if err != nil {
+					return "", err
+				}
+				message := fmt.Sprintf("%d digests sent with %d notifications", len(result.Digests), result.NotificationCount())
+				if len(result.NoEmail) > 0 {
+					message += fmt.Sprintf(", %d users without an email", len(result.NoEmail))
+				}
+				if len(result.Errors) > 0 {
+					return message, fmt.Errorf("%d digests could not be sent", len(result.Errors))
+				}
+				return message, nil
+			},
+		},
+	}
+}
+
+// smtpDigestSender sends digests through the configured SMTP server
+type smtpDigestSender struct {
+	config *entities.DigestConfig
+}
+
+// Send delivers one digest email
+func (s *smtpDigestSender) Send(ctx context.Context, to, subject, body string) error {
+	smtpConfig := s.config.SMTP
+	if smtpConfig.Host == "" || s.config.From == "" {
+		return fmt.Errorf("digest.smtp.host and digest.from must be configured")
+	}
+	port := smtpConfig.Port
+	if port == 0 {
+		port = entities.DefaultDigestConfig().SMTP.Port
+	}
+
+	var auth smtp.Auth
+	if smtpConfig.Username != "" {
+		password := smtpConfig.Password
+		if password == "" {
+			password = os.Getenv("ISSUEMAP_SMTP_PASSWORD")
+		}
+		auth = smtp.PlainAuth("", smtpConfig.Username, password, smtpConfig.Host)
+	}
+
+	// Header values come from configuration; line breaks would inject headers
+	header := strings.NewReplacer("\r", "", "\n", "")
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nDate: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s",
+		header.Replace(s.config.From), header.Replace(to), header.Replace(subject),
+		time.Now().Format(time.RFC1123Z), strings.ReplaceAll(body, "\n", "\r\n"))
+
+	addr := net.JoinHostPort(smtpConfig.Host, strconv.Itoa(port))
+	return smtp.SendMail(addr, auth, s.config.From, []string{to}, []byte(message))
+}
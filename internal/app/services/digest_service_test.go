@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// sentDigest is a digest captured by fakeDigestSender
+type sentDigest struct {
+	to, subject, body string
+}
+
+type fakeDigestSender struct {
+	sent []sentDigest
+	fail map[string]bool
+}
+
+func (f *fakeDigestSender) Send(ctx context.Context, to, subject, body string) error {
+	if f.fail[to] {
+		return fmt.Errorf("mailbox unavailable")
+	}
+	f.sent = append(f.sent, sentDigest{to: to, subject: subject, body: body})
+	return nil
+}
+
+func TestDigestService_Send(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+
+	configRepo := storage.NewFileConfigRepository(basePath)
+	config := entities.NewDefaultConfig()
+	config.Users = []entities.UserProfile{
+		{Handle: "jdoe", Emails: []string{"jdoe@corp.example", "john@home.example"}},
+		{Handle: "carol", Emails: []string{"carol@corp.example"}},
+		{Handle: "robert"},
+		{Handle: "dave", Emails: []string{"dave@corp.example"}},
+	}
+	config.Digest = &entities.DigestConfig{Enabled: true, Subject: "%d updates"}
+	require.NoError(t, configRepo.Save(ctx, config))
+
+	notificationRepo := storage.NewFileNotificationRepository(basePath)
+	read := entities.NewIssueNotification("TEST-001", "jdoe", "carol", entities.NotificationCommented)
+	read.Read = true
+	require.NoError(t, notificationRepo.Add(ctx,
+		entities.NewIssueNotification("TEST-001", "jdoe", "carol", entities.NotificationMentioned),
+		entities.NewIssueNotification("TEST-002", "jdoe", "robert", entities.NotificationAssigned),
+		read,
+		entities.NewIssueNotification("TEST-001", "carol", "jdoe", entities.NotificationCommented),
+		entities.NewIssueNotification("TEST-003", "robert", "jdoe", entities.NotificationMentioned),
+	))
+
+	service := NewDigestService(notificationRepo, configRepo)
+	sender := &fakeDigestSender{fail: map[string]bool{"carol@corp.example": true}}
+	service.SetSender(sender)
+
+	// Unread notifications go to the first address; read ones are left out
+	result, err := service.Send(ctx)
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "jdoe@corp.example", sender.sent[0].to)
+	assert.Equal(t, "2 updates", sender.sent[0].subject)
+	assert.Contains(t, sender.sent[0].body, "carol mentioned you on TEST-001")
+	assert.Contains(t, sender.sent[0].body, "robert assigned TEST-002 to you")
+	assert.NotContains(t, sender.sent[0].body, "commented")
+	assert.Equal(t, 2, result.NotificationCount())
+	assert.Equal(t, []string{"robert"}, result.NoEmail)
+	assert.Len(t, result.Errors, 1)
+
+	// Digested notifications stay unread but are not sent again, while a
+	// failed delivery is retried on the next run
+	unread, err := notificationRepo.List(ctx, "jdoe", true)
+	require.NoError(t, err)
+	assert.Len(t, unread, 2)
+	delete(sender.fail, "carol@corp.example")
+	sender.sent = nil
+	result, err = service.Send(ctx)
+	require.NoError(t, err)
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "carol@corp.example", sender.sent[0].to)
+	assert.Empty(t, result.Errors)
+
+	jobs := service.ScheduledJobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "digest", jobs[0].Name)
+	assert.True(t, jobs[0].Enabled())
+	message, err := jobs[0].Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "0 digests sent with 0 notifications, 1 users without an email", message)
+}
+
+func TestDigestService_SealedStatusIsNotMailed(t *testing.T) {
+	ctx := context.Background()
+	identityPath := filepath.Join(t.TempDir(), "identity.key")
+	identity, err := storage.GenerateConfidentialIdentity(identityPath)
+	require.NoError(t, err)
+	t.Setenv("ISSUEMAP_IDENTITY", identityPath)
+
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+	configRepo := storage.NewFileConfigRepository(basePath)
+	config := entities.NewDefaultConfig()
+	config.Project.Name = "test"
+	config.Users = []entities.UserProfile{{Handle: "carol", Emails: []string{"carol@corp.example"}}}
+	config.Confidential = &entities.ConfidentialConfig{
+		Recipients: []entities.ConfidentialRecipient{{Name: "carol", PublicKey: identity.PublicKey()}},
+		SealStatus: true,
+	}
+	require.NoError(t, configRepo.Save(ctx, config))
+	issueService := NewIssueService(storage.NewFileIssueRepository(basePath), configRepo, nil)
+	issueService.historyService = nil
+
+	assignee := "carol"
+	issue, err := issueService.CreateIssue(ctx, CreateIssueRequest{
+		Title:        "Credential leak",
+		Assignee:     &assignee,
+		Confidential: true,
+	})
+	require.NoError(t, err)
+	_, err = issueService.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(entities.StatusInProgress)})
+	require.NoError(t, err)
+
+	service := NewDigestService(storage.NewFileNotificationRepository(basePath), configRepo)
+	sender := &fakeDigestSender{}
+	service.SetSender(sender)
+	_, err = service.Send(ctx)
+	require.NoError(t, err)
+
+	// The digest leaves the project, so it must not carry the sealed status
+	require.Len(t, sender.sent, 1)
+	assert.Contains(t, sender.sent[0].body, "system changed the status of TEST-001")
+	assert.NotContains(t, sender.sent[0].body, string(entities.StatusInProgress))
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// FormatMigration moves the .issuemap directory from format version
+// Version-1 to Version and, when Down is set, back again
+type FormatMigration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, m *FormatMigrator) error
+	Down        func(ctx context.Context, m *FormatMigrator) error
+}
+
+// formatMigrations lists every format migration in order. Append new
+// migrations here and raise entities.CurrentFormatVersion to match.
+var formatMigrations = []FormatMigration{
+	{
+		Version:     1,
+		Description: "Write issues, histories, dependencies, time entries and config in their current schema version",
+		Up:          upgradeSchemaFiles,
+		Down:        unversionSchemaFiles,
+	},
+}
+
+// FormatMigrator gives a migration access to the .issuemap directory and
+// records the files it changes. In a dry run changes are recorded but not
+// written.
+type FormatMigrator struct {
+	basePath string
+	dryRun   bool
+	changes  []string
+}
+
+// BasePath returns the .issuemap directory being migrated
+func (m *FormatMigrator) BasePath() string {
+	return m.basePath
+}
+
+// ReadFile reads a file relative to the .issuemap directory
+func (m *FormatMigrator) ReadFile(relPath string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(m.basePath, relPath))
+}
+
+// WriteFile replaces a file relative to the .issuemap directory
+func (m *FormatMigrator) WriteFile(relPath string, data []byte) error {
+	m.changes = append(m.changes, "rewrite "+filepath.ToSlash(relPath))
+	if m.dryRun {
+		return nil
+	}
+	return os.WriteFile(filepath.Join(m.basePath, relPath), data, 0644)
+}
+
+// schemaFiles returns the versioned files under the .issuemap directory,
+// relative to it and in a stable order
+func (m *FormatMigrator) schemaFiles() ([]string, error) {
+	var files []string
+	if _, err := os.Stat(filepath.Join(m.basePath, "config.yaml")); err == nil {
+		files = append(files, "config.yaml")
+	}
+	for _, dir := range []string{"issues", "history", "dependencies", "time_entries"} {
+		matches, err := filepath.Glob(filepath.Join(m.basePath, dir, "*.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			rel, err := filepath.Rel(m.basePath, match)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, rel)
+		}
+	}
+	return files, nil
+}
+
+// rewriteSchemaFiles rewrites every versioned file whose schema_version
+// differs from the one version returns for its kind
+func (m *FormatMigrator) rewriteSchemaFiles(version func(kind entities.SchemaKind) int) error {
+	files, err := m.schemaFiles()
+	if err != nil {
+		return err
+	}
+	for _, rel := range files {
+		kind, ok := entities.SchemaKindForPath(rel)
+		if !ok {
+			continue
+		}
+		data, err := m.ReadFile(rel)
+		if err != nil {
+			return err
+		}
+		current, err := storage.SchemaVersionOfData(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+		}
+		target := version(kind)
+		if current == target {
+			continue
+		}
+		rewritten, err := storage.RewriteSchemaData(kind, data, target)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.ToSlash(rel), err)
+		}
+		if err := m.WriteFile(rel, rewritten); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeSchemaFiles writes files from before schema versioning in the
+// current version, so tools reading them directly see one format
+func upgradeSchemaFiles(ctx context.Context, m *FormatMigrator) error {
+	return m.rewriteSchemaFiles(entities.CurrentSchemaVersion)
+}
+
+// unversionSchemaFiles drops schema_version again. Versions 0 and 1 of
+// every kind only differ in that field.
+func unversionSchemaFiles(ctx context.Context, m *FormatMigrator) error {
+	return m.rewriteSchemaFiles(func(entities.SchemaKind) int { return 0 })
+}
+
+// FormatMigrationService applies format migrations to a .issuemap directory
+// and keeps its metadata/version marker up to date
+type FormatMigrationService struct {
+	basePath   string
+	migrations []FormatMigration
+}
+
+// NewFormatMigrationService creates a service for the .issuemap directory at
+// basePath using the registered migrations
+func NewFormatMigrationService(basePath string) *FormatMigrationService {
+	return &FormatMigrationService{basePath: basePath, migrations: formatMigrations}
+}
+
+// latest returns the format version reached by the last migration
+func (s *FormatMigrationService) latest() int {
+	if len(s.migrations) == 0 {
+		return 0
+	}
+	return s.migrations[len(s.migrations)-1].Version
+}
+
+// Status reports the repository's format version and which migrations have
+// been applied
+func (s *FormatMigrationService) Status(ctx context.Context) (*entities.FormatStatus, error) {
+	version, err := storage.ReadFormatVersion(s.basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &entities.FormatStatus{Version: version, Latest: s.latest(), Migrations: []entities.FormatMigrationInfo{}}
+	for _, migration := range s.migrations {
+		applied := migration.Version <= version
+		if !applied {
+			status.Pending++
+		}
+		status.Migrations = append(status.Migrations, entities.FormatMigrationInfo{
+			Version:     migration.Version,
+			Description: migration.Description,
+			Applied:     applied,
+			Reversible:  migration.Down != nil,
+		})
+	}
+	return status, nil
+}
+
+// Up applies the pending migrations up to and including target, or all of
+// them when target is negative
+func (s *FormatMigrationService) Up(ctx context.Context, target int, dryRun bool) (*entities.FormatMigrationResult, error) {
+	from, err := s.currentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if target < 0 {
+		target = s.latest()
+	}
+	if target > s.latest() {
+		return nil, errors.New("FormatMigrationService.Up", "target",
+			fmt.Errorf("format version %d is unknown; this issuemap supports up to version %d", target, s.latest()))
+	}
+	if target < from {
+		return nil, errors.New("FormatMigrationService.Up", "target",
+			fmt.Errorf("repository is already at format version %d; use migrate down to go back to %d", from, target))
+	}
+
+	result := &entities.FormatMigrationResult{From: from, To: from, DryRun: dryRun, Steps: []entities.FormatMigrationStep{}}
+	for _, migration := range s.migrations {
+		if migration.Version <= from || migration.Version > target {
+			continue
+		}
+		step, err := s.apply(ctx, migration, entities.FormatMigrationUp, migration.Up, migration.Version, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.Steps = append(result.Steps, *step)
+		result.To = migration.Version
+	}
+	return result, nil
+}
+
+// Down reverts the applied migrations above target, stopping with an error
+// at the first one that cannot be reverted
+func (s *FormatMigrationService) Down(ctx context.Context, target int, dryRun bool) (*entities.FormatMigrationResult, error) {
+	from, err := s.currentVersion()
+	if err != nil {
+		return nil, err
+	}
+	if target < 0 || target > from {
+		return nil, errors.New("FormatMigrationService.Down", "target",
+			fmt.Errorf("cannot migrate down from format version %d to %d", from, target))
+	}
+
+	result := &entities.FormatMigrationResult{From: from, To: from, DryRun: dryRun, Steps: []entities.FormatMigrationStep{}}
+	for i := len(s.migrations) - 1; i >= 0; i-- {
+		migration := s.migrations[i]
+		if migration.Version > from || migration.Version <= target {
+			continue
+		}
+		if migration.Down == nil {
+			return result, errors.New("FormatMigrationService.Down", "irreversible",
+				fmt.Errorf("migration %d (%s) cannot be reverted", migration.Version, migration.Description))
+		}
+		step, err := s.apply(ctx, migration, entities.FormatMigrationDown, migration.Down, migration.Version-1, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.Steps = append(result.Steps, *step)
+		result.To = migration.Version - 1
+	}
+	return result, nil
+}
+
+// currentVersion reads the marker and refuses versions this build does not
+// know
+func (s *FormatMigrationService) currentVersion() (int, error) {
+	version, err := storage.ReadFormatVersion(s.basePath)
+	if err != nil {
+		return 0, err
+	}
+	if version > s.latest() {
+		return version, &entities.FormatVersionError{Version: version, Supported: s.latest()}
+	}
+	return version, nil
+}
+
+// apply runs one migration in one direction and records the version it
+// leaves the repository at
+func (s *FormatMigrationService) apply(ctx context.Context, migration FormatMigration, direction string,
+	run func(context.Context, *FormatMigrator) error, version int, dryRun bool) (*entities.FormatMigrationStep, error) {
+	migrator := &FormatMigrator{basePath: s.basePath, dryRun: dryRun}
+	if err := run(ctx, migrator); err != nil {
+		return nil, errors.New("FormatMigrationService.apply", "migrate",
+			fmt.Errorf("migration %d %s failed: %w", migration.Version, direction, err))
+	}
+	if !dryRun {
+		if err := storage.WriteFormatVersion(s.basePath, version); err != nil {
+			return nil, err
+		}
+	}
+
+	changes := migrator.changes
+	if changes == nil {
+		changes = []string{}
+	}
+	return &entities.FormatMigrationStep{
+		Version:     migration.Version,
+		Description: migration.Description,
+		Direction:   direction,
+		Changes:     changes,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+const unversionedIssue = `id: TEST-001
+title: Legacy issue
+type: bug
+status: open
+priority: low
+`
+
+// newFormatMigrationTestService creates a migration service over an
+// unversioned project holding one issue
+func newFormatMigrationTestService(t *testing.T) (*FormatMigrationService, string) {
+	t.Helper()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(filepath.Join(basePath, "issues"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(basePath, "issues", "TEST-001.yaml"), []byte(unversionedIssue), 0644))
+	return NewFormatMigrationService(basePath), basePath
+}
+
+func TestFormatMigrations_Registry(t *testing.T) {
+	for i, migration := range formatMigrations {
+		assert.Equal(t, i+1, migration.Version, "migrations must be numbered consecutively from 1")
+		assert.NotEmpty(t, migration.Description)
+		assert.NotNil(t, migration.Up)
+	}
+	require.NotEmpty(t, formatMigrations)
+	assert.Equal(t, entities.CurrentFormatVersion, formatMigrations[len(formatMigrations)-1].Version)
+}
+
+func TestFormatMigrationService_UpAndDown(t *testing.T) {
+	service, basePath := newFormatMigrationTestService(t)
+	ctx := context.Background()
+	issuePath := filepath.Join(basePath, "issues", "TEST-001.yaml")
+
+	status, err := service.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, status.Version)
+	assert.Equal(t, len(formatMigrations), status.Pending)
+
+	// A dry run reports the change without writing it
+	result, err := service.Up(ctx, -1, true)
+	require.NoError(t, err)
+	require.Len(t, result.Steps, len(formatMigrations))
+	assert.Contains(t, result.Steps[0].Changes, "rewrite issues/TEST-001.yaml")
+	data, err := os.ReadFile(issuePath)
+	require.NoError(t, err)
+	assert.Equal(t, unversionedIssue, string(data))
+	version, err := storage.ReadFormatVersion(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+
+	result, err = service.Up(ctx, -1, false)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CurrentFormatVersion, result.To)
+	data, err = os.ReadFile(issuePath)
+	require.NoError(t, err)
+	stamped, err := storage.SchemaVersionOfData(data)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CurrentSchemaVersion(entities.SchemaKindIssue), stamped)
+	version, err = storage.ReadFormatVersion(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CurrentFormatVersion, version)
+
+	// Applying again is a no-op
+	result, err = service.Up(ctx, -1, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Steps)
+
+	result, err = service.Down(ctx, 0, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.To)
+	data, err = os.ReadFile(issuePath)
+	require.NoError(t, err)
+	stamped, err = storage.SchemaVersionOfData(data)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stamped)
+	assert.Contains(t, string(data), "title: Legacy issue")
+	version, err = storage.ReadFormatVersion(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+}
+
+func TestFormatMigrationService_NewerFormat(t *testing.T) {
+	service, basePath := newFormatMigrationTestService(t)
+	ctx := context.Background()
+	require.NoError(t, storage.WriteFormatVersion(basePath, entities.CurrentFormatVersion+1))
+
+	var versionErr *entities.FormatVersionError
+	_, err := service.Up(ctx, -1, false)
+	assert.ErrorAs(t, err, &versionErr)
+	_, err = service.Down(ctx, 0, false)
+	assert.ErrorAs(t, err, &versionErr)
+
+	status, err := service.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CurrentFormatVersion+1, status.Version)
+}
+
+func TestFormatMigrationService_InvalidTarget(t *testing.T) {
+	service, _ := newFormatMigrationTestService(t)
+	ctx := context.Background()
+
+	_, err := service.Up(ctx, entities.CurrentFormatVersion+1, false)
+	assert.Error(t, err)
+	_, err = service.Down(ctx, 1, false)
+	assert.Error(t, err)
+}
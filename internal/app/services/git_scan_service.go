@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// GitScanOptions controls a commit scan
+type GitScanOptions struct {
+	// Since limits the scan to commits not reachable from this ref instead
+	// of those after the last scan
+	Since string
+	// Full scans the whole history, ignoring the last scan
+	Full   bool
+	DryRun bool
+}
+
+// GitScanService links commits to the issues they reference by walking the
+// history once, rather than searching it for every issue
+type GitScanService struct {
+	issueRepo repositories.IssueRepository
+	gitRepo   repositories.GitRepository
+	basePath  string
+}
+
+// NewGitScanService creates a scan service for the .issuemap directory at
+// basePath
+func NewGitScanService(issueRepo repositories.IssueRepository, gitRepo repositories.GitRepository, basePath string) *GitScanService {
+	return &GitScanService{
+		issueRepo: issueRepo,
+		gitRepo:   gitRepo,
+		basePath:  basePath,
+	}
+}
+
+// Scan walks the commits added since the last scan, or since opts.Since,
+// and records them on the issues they reference, either in their message
+// or by being on a branch named after the issue. References to issues that
+// do not exist are reported. The refs scanned are recorded so the next scan
+// only walks newer commits.
+func (s *GitScanService) Scan(ctx context.Context, opts GitScanOptions) (*entities.GitScanResult, error) {
+	result := &entities.GitScanResult{Since: opts.Since, DryRun: opts.DryRun, Links: []entities.GitScanLink{}}
+
+	tips, err := s.gitRepo.GetRefTips(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitScanService.Scan", "ref_tips")
+	}
+
+	exclude, err := s.scanStart(ctx, opts, result)
+	if err != nil {
+		return nil, err
+	}
+	commits, err := s.gitRepo.GetCommitsExcluding(ctx, exclude)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitScanService.Scan", "commits")
+	}
+	result.Commits = len(commits)
+
+	list, err := s.issueRepo.List(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, errors.Wrap(err, "GitScanService.Scan", "list_issues")
+	}
+	ids := make([]entities.IssueID, 0, len(list.Issues))
+	for _, issue := range list.Issues {
+		ids = append(ids, issue.ID)
+	}
+	resolver := entities.NewIssueReferenceResolver(ids)
+
+	// Oldest first, so commits are linked in the order they were made
+	scanned := make(map[string]repositories.Commit, len(commits))
+	found := newScanLinks()
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		scanned[commit.Hash] = commit
+		for _, ref := range commit.IssueRefs {
+			id, ok, known := resolver.Resolve(ref)
+			switch {
+			case ok:
+				found.add(id, commit, "message")
+			case known:
+				result.AddUnknownRef(ref, commit.Hash)
+			}
+		}
+	}
+
+	if err := s.scanBranches(ctx, tips, scanned, resolver, found, result); err != nil {
+		return nil, err
+	}
+
+	directory := loadUserDirectory(ctx, storage.NewFileConfigRepository(s.basePath))
+	for _, id := range found.order {
+		issue, err := s.issueRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, errors.Wrap(err, "GitScanService.Scan", "get_issue")
+		}
+
+		added := 0
+		for _, link := range found.links[id] {
+			if issue.HasCommit(link.commit.Hash) {
+				continue
+			}
+			// Appended directly: a backfill is not activity on the issue
+			issue.Commits = append(issue.Commits, entities.CommitRef{
+				Hash:    link.commit.Hash,
+				Message: strings.TrimSpace(link.commit.Message),
+				Author:  directory.Canonical(link.commit.Author),
+				Date:    link.commit.Date,
+			})
+			result.Links = append(result.Links, entities.GitScanLink{
+				IssueID: id,
+				Hash:    link.commit.Hash,
+				Message: strings.TrimSpace(firstLine(link.commit.Message)),
+				Source:  link.source,
+			})
+			added++
+		}
+		if added == 0 {
+			continue
+		}
+		result.Issues++
+		if opts.DryRun {
+			continue
+		}
+		if err := s.issueRepo.Update(ctx, issue); err != nil {
+			return nil, errors.Wrap(err, "GitScanService.Scan", "update_issue")
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := storage.WriteGitScanCursor(s.basePath, &entities.GitScanCursor{ScannedAt: time.Now(), Tips: tips}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// scanStart returns the commits whose history the scan skips
+func (s *GitScanService) scanStart(ctx context.Context, opts GitScanOptions, result *entities.GitScanResult) ([]string, error) {
+	if opts.Since != "" {
+		hash, err := s.gitRepo.ResolveCommit(ctx, opts.Since)
+		if err != nil {
+			return nil, errors.New("GitScanService.Scan", "unknown_ref",
+				fmt.Errorf("unknown git ref %q", opts.Since))
+		}
+		return []string{hash}, nil
+	}
+	if opts.Full {
+		return nil, nil
+	}
+
+	cursor, err := storage.ReadGitScanCursor(s.basePath)
+	if err != nil || cursor == nil {
+		return nil, err
+	}
+	result.Incremental = true
+
+	// Tips may have been rewritten or garbage collected since
+	seen := make(map[string]bool)
+	var exclude []string
+	for _, hash := range cursor.Tips {
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		if _, err := s.gitRepo.ResolveCommit(ctx, hash); err == nil {
+			exclude = append(exclude, hash)
+		}
+	}
+	return exclude, nil
+}
+
+// scanBranches links the scanned commits on branches named after an issue
+// to that issue
+func (s *GitScanService) scanBranches(ctx context.Context, tips map[string]string, scanned map[string]repositories.Commit,
+	resolver *entities.IssueReferenceResolver, found *scanLinks, result *entities.GitScanResult) error {
+	if len(scanned) == 0 {
+		return nil
+	}
+	mainBranch, err := s.gitRepo.GetMainBranch(ctx)
+	if err != nil {
+		return nil
+	}
+
+	refNames := make([]string, 0, len(tips))
+	for ref := range tips {
+		refNames = append(refNames, ref)
+	}
+	sort.Strings(refNames)
+
+	for _, ref := range refNames {
+		var branch string
+		switch {
+		case strings.HasPrefix(ref, "refs/heads/"):
+			branch = strings.TrimPrefix(ref, "refs/heads/")
+		case strings.HasPrefix(ref, "refs/remotes/"):
+			branch = strings.TrimPrefix(ref, "refs/remotes/")
+			if _, name, ok := strings.Cut(branch, "/"); ok && name == mainBranch {
+				continue
+			}
+		default:
+			continue
+		}
+		if branch == mainBranch {
+			continue
+		}
+
+		refs := s.gitRepo.ParseIssueReferences(branch)
+		if len(refs) == 0 {
+			continue
+		}
+		id, ok, known := resolver.Resolve(refs[0])
+		if !ok && !known {
+			continue
+		}
+
+		commits, err := s.gitRepo.GetBranchCommits(ctx, ref, mainBranch)
+		if err != nil {
+			return errors.Wrap(err, "GitScanService.Scan", "branch_commits")
+		}
+		for i := len(commits) - 1; i >= 0; i-- {
+			commit, isNew := scanned[commits[i].Hash]
+			if !isNew {
+				continue
+			}
+			if ok {
+				found.add(id, commit, "branch")
+			} else {
+				result.AddUnknownRef(refs[0], commit.Hash)
+			}
+		}
+	}
+	return nil
+}
+
+// scanLinks collects the commits found for each issue, each once
+type scanLinks struct {
+	order []entities.IssueID
+	links map[entities.IssueID][]scanLink
+}
+
+type scanLink struct {
+	commit repositories.Commit
+	source string
+}
+
+func newScanLinks() *scanLinks {
+	return &scanLinks{links: make(map[entities.IssueID][]scanLink)}
+}
+
+func (l *scanLinks) add(id entities.IssueID, commit repositories.Commit, source string) {
+	existing, seen := l.links[id]
+	if !seen {
+		l.order = append(l.order, id)
+	}
+	for _, link := range existing {
+		if link.commit.Hash == commit.Hash {
+			return
+		}
+	}
+	l.links[id] = append(existing, scanLink{commit: commit, source: source})
+}
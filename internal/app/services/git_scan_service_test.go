@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestGitScanService_Scan(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(message string) string {
+		t.Helper()
+		file := filepath.Join(dir, "work.txt")
+		f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		require.NoError(t, err)
+		_, err = f.WriteString(message + "\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		run("add", "work.txt")
+		run("commit", "-q", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+
+	run("init", "-q", "-b", "main")
+	commit("Initial commit")
+	fix := commit("TEST-1: fix parser, mentions UTF-8 and TEST-099")
+	refs := commit("Tidy up\n\nRefs: TEST-002")
+	run("checkout", "-q", "-b", "feature/TEST-003-export")
+	onBranch := commit("wip")
+	run("checkout", "-q", "main")
+
+	basePath := filepath.Join(dir, ".issuemap")
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	for _, id := range []entities.IssueID{"TEST-001", "TEST-002", "TEST-003"} {
+		require.NoError(t, issueRepo.Create(ctx, entities.NewIssue(id, string(id), "", entities.IssueTypeTask)))
+	}
+	gitClient, err := git.NewGitClient(dir)
+	require.NoError(t, err)
+	scanService := NewGitScanService(issueRepo, gitClient, basePath)
+
+	// A dry run reports without recording anything
+	result, err := scanService.Scan(ctx, GitScanOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Len(t, result.Links, 3)
+	issue, err := issueRepo.GetByID(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Empty(t, issue.Commits)
+	cursor, err := storage.ReadGitScanCursor(basePath)
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+
+	result, err = scanService.Scan(ctx, GitScanOptions{})
+	require.NoError(t, err)
+	assert.False(t, result.Incremental)
+	assert.Equal(t, 4, result.Commits)
+	assert.Equal(t, 3, result.Issues)
+	assert.Equal(t, []entities.GitScanUnknownRef{{Ref: "TEST-099", Commits: []string{fix}}}, result.Unknown)
+
+	linked := make(map[entities.IssueID][]string)
+	for _, link := range result.Links {
+		linked[link.IssueID] = append(linked[link.IssueID], link.Hash)
+	}
+	assert.Equal(t, []string{fix}, linked["TEST-001"])
+	assert.Equal(t, []string{refs}, linked["TEST-002"])
+	assert.Equal(t, []string{onBranch}, linked["TEST-003"])
+
+	issue, err = issueRepo.GetByID(ctx, "TEST-003")
+	require.NoError(t, err)
+	require.Len(t, issue.Commits, 1)
+	assert.Equal(t, "wip", issue.Commits[0].Message)
+
+	// The next scan only walks new commits
+	result, err = scanService.Scan(ctx, GitScanOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Incremental)
+	assert.Equal(t, 0, result.Commits)
+
+	more := commit("Fixes TEST-002")
+	result, err = scanService.Scan(ctx, GitScanOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Commits)
+	require.Len(t, result.Links, 1)
+	assert.Equal(t, more, result.Links[0].Hash)
+
+	// A full scan finds everything again but links each commit once
+	result, err = scanService.Scan(ctx, GitScanOptions{Full: true})
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Commits)
+	assert.Empty(t, result.Links)
+	issue, err = issueRepo.GetByID(ctx, "TEST-002")
+	require.NoError(t, err)
+	assert.Len(t, issue.Commits, 2)
+
+	_, err = scanService.Scan(ctx, GitScanOptions{Since: "v9"})
+	assert.Error(t, err)
+}
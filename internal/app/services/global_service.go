@@ -182,6 +182,9 @@ func (s *GlobalService) ArchiveIssue(ctx context.Context, issueID entities.Issue
 	if err != nil {
 		return nil, errors.Wrap(err, "GlobalService.ArchiveIssue", "get_issue")
 	}
+	if issue.Confidential {
+		return nil, errors.New("GlobalService.ArchiveIssue", "confidential", fmt.Errorf("%s is confidential and cannot be archived unencrypted", issueID))
+	}
 
 	// Get current project path
 	currentDir, err := os.Getwd()
@@ -268,6 +271,74 @@ func (s *GlobalService) BackupProject(ctx context.Context, projectPath string, t
 	return backup, nil
 }
 
+// ListBackups lists backups, newest first, optionally for a single project
+func (s *GlobalService) ListBackups(ctx context.Context, projectPath *string) ([]*entities.ProjectBackup, error) {
+	if err := s.EnsureGlobalInitialized(ctx); err != nil {
+		return nil, errors.Wrap(err, "GlobalService.ListBackups", "ensure_init")
+	}
+
+	return s.globalRepo.ListBackups(ctx, repositories.BackupFilter{ProjectPath: projectPath})
+}
+
+// VerifyBackup test-restores a backup and compares it with the project source
+func (s *GlobalService) VerifyBackup(ctx context.Context, backupID string) (*entities.BackupVerifyResult, error) {
+	if err := s.EnsureGlobalInitialized(ctx); err != nil {
+		return nil, errors.Wrap(err, "GlobalService.VerifyBackup", "ensure_init")
+	}
+
+	return s.globalRepo.VerifyBackup(ctx, backupID)
+}
+
+// RestoreBackup restores a backup's .issuemap contents into targetPath,
+// which must not already contain files
+func (s *GlobalService) RestoreBackup(ctx context.Context, backupID string, targetPath string) error {
+	if err := s.EnsureGlobalInitialized(ctx); err != nil {
+		return errors.Wrap(err, "GlobalService.RestoreBackup", "ensure_init")
+	}
+
+	if entries, err := os.ReadDir(targetPath); err == nil && len(entries) > 0 {
+		return errors.New("GlobalService.RestoreBackup", "target_not_empty",
+			fmt.Errorf("restore target %s is not empty", targetPath))
+	}
+
+	return s.globalRepo.RestoreBackup(ctx, backupID, targetPath)
+}
+
+// SetBackupEncryption enables or disables encryption of new backups. An
+// encryption key is generated the first time encryption is enabled.
+func (s *GlobalService) SetBackupEncryption(ctx context.Context, enabled bool) (*entities.GlobalConfig, error) {
+	config, err := s.GetGlobalConfig(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "GlobalService.SetBackupEncryption", "get_config")
+	}
+
+	if enabled && config.GlobalSettings.BackupKeyID == "" {
+		if _, err := s.globalRepo.RotateBackupKey(ctx); err != nil {
+			return nil, errors.Wrap(err, "GlobalService.SetBackupEncryption", "generate_key")
+		}
+		if config, err = s.globalRepo.GetConfig(ctx); err != nil {
+			return nil, errors.Wrap(err, "GlobalService.SetBackupEncryption", "reload_config")
+		}
+	}
+
+	config.GlobalSettings.EncryptionEnabled = enabled
+	if err := s.UpdateGlobalConfig(ctx, config); err != nil {
+		return nil, errors.Wrap(err, "GlobalService.SetBackupEncryption", "save_config")
+	}
+
+	return config, nil
+}
+
+// RotateBackupKey makes a new key the active backup encryption key. The
+// next backup is a full backup sealed with the new key.
+func (s *GlobalService) RotateBackupKey(ctx context.Context) (string, error) {
+	if err := s.EnsureGlobalInitialized(ctx); err != nil {
+		return "", errors.Wrap(err, "GlobalService.RotateBackupKey", "ensure_init")
+	}
+
+	return s.globalRepo.RotateBackupKey(ctx)
+}
+
 // ScanForProjects discovers projects in specified directories
 func (s *GlobalService) ScanForProjects(ctx context.Context, paths []string) ([]*entities.ProjectInfo, error) {
 	if err := s.EnsureGlobalInitialized(ctx); err != nil {
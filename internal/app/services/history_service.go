@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/ooyeku/issuemap/internal/domain/entities"
@@ -33,14 +34,14 @@ func (s *HistoryService) RecordIssueCreated(ctx context.Context, issue *entities
 		issue.ID,
 		entities.ChangeTypeCreated,
 		author,
-		fmt.Sprintf("Issue created: %s", issue.Title),
+		fmt.Sprintf("Issue created: %s", historyValue(issue, "title", issue.Title)),
 	)
 
 	// Add initial field values as "changes" for reference
-	entry.AddFieldChange("title", nil, issue.Title)
-	entry.AddFieldChange("description", nil, issue.Description)
+	entry.AddFieldChange("title", nil, historyValue(issue, "title", issue.Title))
+	entry.AddFieldChange("description", nil, historyValue(issue, "description", issue.Description))
 	entry.AddFieldChange("type", nil, string(issue.Type))
-	entry.AddFieldChange("status", nil, string(issue.Status))
+	entry.AddFieldChange("status", nil, historyValue(issue, "status", string(issue.Status)))
 	entry.AddFieldChange("priority", nil, string(issue.Priority))
 
 	if issue.Assignee != nil {
@@ -66,7 +67,7 @@ func (s *HistoryService) RecordIssueCreated(ctx context.Context, issue *entities
 		}
 	}
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordIssueUpdatedWithDetails records detailed changes to an existing issue with old and new values
@@ -83,12 +84,12 @@ func (s *HistoryService) RecordIssueUpdatedWithDetails(ctx context.Context, issu
 
 	// Check for field changes and record old/new values
 	if oldIssue.Title != newIssue.Title {
-		entry.AddFieldChange("title", oldIssue.Title, newIssue.Title)
+		entry.AddFieldChange("title", historyValue(oldIssue, "title", oldIssue.Title), historyValue(newIssue, "title", newIssue.Title))
 		changeParts = append(changeParts, "title")
 	}
 
 	if oldIssue.Description != newIssue.Description {
-		entry.AddFieldChange("description", oldIssue.Description, newIssue.Description)
+		entry.AddFieldChange("description", historyValue(oldIssue, "description", oldIssue.Description), historyValue(newIssue, "description", newIssue.Description))
 		changeParts = append(changeParts, "description")
 	}
 
@@ -98,7 +99,7 @@ func (s *HistoryService) RecordIssueUpdatedWithDetails(ctx context.Context, issu
 	}
 
 	if oldIssue.Status != newIssue.Status {
-		entry.AddFieldChange("status", string(oldIssue.Status), string(newIssue.Status))
+		entry.AddFieldChange("status", historyValue(oldIssue, "status", string(oldIssue.Status)), historyValue(newIssue, "status", string(newIssue.Status)))
 		changeParts = append(changeParts, "status")
 	}
 
@@ -171,7 +172,16 @@ func (s *HistoryService) RecordIssueUpdatedWithDetails(ctx context.Context, issu
 		}
 	}
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
+}
+
+// historyValue returns the value of a field to record in history. History is
+// stored in clear, so sealed fields of confidential issues are redacted.
+func historyValue(issue *entities.Issue, field, value string) string {
+	if issue.IsSealedField(field) {
+		return entities.ConfidentialPlaceholder
+	}
+	return value
 }
 
 // RecordIssueFieldChanged records a specific field change with old and new values
@@ -187,7 +197,7 @@ func (s *HistoryService) RecordIssueFieldChanged(ctx context.Context, issueID en
 
 	entry.AddFieldChange(field, oldValue, newValue)
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordIssueAssigned records issue assignment
@@ -203,7 +213,7 @@ func (s *HistoryService) RecordIssueAssigned(ctx context.Context, issueID entiti
 
 	entry.AddFieldChange("assignee", nil, assignee)
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordIssueUnassigned records issue unassignment
@@ -222,7 +232,7 @@ func (s *HistoryService) RecordIssueUnassigned(ctx context.Context, issueID enti
 
 	entry.AddFieldChange("assignee", previousAssignee, nil)
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordIssueClosed records issue closure
@@ -244,7 +254,7 @@ func (s *HistoryService) RecordIssueClosed(ctx context.Context, issueID entities
 		entry.SetMetadata("close_reason", reason)
 	}
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordIssueReopened records issue reopening
@@ -258,12 +268,13 @@ func (s *HistoryService) RecordIssueReopened(ctx context.Context, issueID entiti
 
 	entry.AddFieldChange("status", "closed", "open")
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
-// RecordIssueLabeled records label addition
-func (s *HistoryService) RecordIssueLabeled(ctx context.Context, issueID entities.IssueID, labels []string, author string) error {
-	message := fmt.Sprintf("Added labels: %s", joinWithComma(labels))
+// RecordIssueLabeled records label addition. The full label sets before and
+// after are recorded so the labels can be replayed from history.
+func (s *HistoryService) RecordIssueLabeled(ctx context.Context, issueID entities.IssueID, oldLabels, newLabels []string, author string) error {
+	message := fmt.Sprintf("Added labels: %s", joinWithComma(labelsNotIn(newLabels, oldLabels)))
 
 	entry := entities.NewHistoryEntry(
 		issueID,
@@ -272,9 +283,73 @@ func (s *HistoryService) RecordIssueLabeled(ctx context.Context, issueID entitie
 		message,
 	)
 
-	entry.AddFieldChange("labels", nil, labels)
+	entry.AddFieldChange("labels", oldLabels, newLabels)
+
+	return s.addEntry(ctx, entry)
+}
+
+// RecordIssueUnlabeled records label removal. The full label sets before and
+// after are recorded so the labels can be replayed from history.
+func (s *HistoryService) RecordIssueUnlabeled(ctx context.Context, issueID entities.IssueID, oldLabels, newLabels []string, author string) error {
+	message := fmt.Sprintf("Removed labels: %s", joinWithComma(labelsNotIn(oldLabels, newLabels)))
+
+	entry := entities.NewHistoryEntry(
+		issueID,
+		entities.ChangeTypeUnlabeled,
+		author,
+		message,
+	)
+
+	entry.AddFieldChange("labels", oldLabels, newLabels)
+
+	return s.addEntry(ctx, entry)
+}
 
-	return s.historyRepo.AddEntry(ctx, entry)
+// labelNames returns the names of labels in order
+func labelNames(labels []entities.Label) []string {
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		names[i] = label.Name
+	}
+	return names
+}
+
+// labelsNotIn returns the labels of a that are not in b
+func labelsNotIn(a, b []string) []string {
+	var result []string
+	for _, label := range a {
+		found := false
+		for _, other := range b {
+			if label == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, label)
+		}
+	}
+	return result
+}
+
+// RecordIssueReverted records that an issue was reverted to an earlier version
+func (s *HistoryService) RecordIssueReverted(ctx context.Context, issueID entities.IssueID, oldIssue, newIssue *entities.Issue, toVersion int, author string) error {
+	entry := entities.NewHistoryEntry(
+		issueID,
+		entities.ChangeTypeReverted,
+		author,
+		fmt.Sprintf("Reverted to version %d", toVersion),
+	)
+
+	for _, change := range entities.DiffIssues(oldIssue, newIssue) {
+		if newIssue.IsSealedField(change.Field) {
+			change.OldValue, change.NewValue = entities.ConfidentialPlaceholder, entities.ConfidentialPlaceholder
+		}
+		entry.AddFieldChange(change.Field, change.OldValue, change.NewValue)
+	}
+	entry.SetMetadata("reverted_to_version", toVersion)
+
+	return s.addEntry(ctx, entry)
 }
 
 // RecordIssueCommented records a comment addition
@@ -288,7 +363,7 @@ func (s *HistoryService) RecordIssueCommented(ctx context.Context, issueID entit
 
 	entry.SetMetadata("comment_text", comment)
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // GetIssueHistory retrieves the complete history for an issue
@@ -359,6 +434,127 @@ func (s *HistoryService) DeleteIssueHistory(ctx context.Context, issueID entitie
 	return nil
 }
 
+// VerifyIssueHistory checks the hash chain and any signatures of an issue's history
+func (s *HistoryService) VerifyIssueHistory(ctx context.Context, issueID entities.IssueID) (*entities.HistoryVerification, error) {
+	history, err := s.historyRepo.GetHistory(ctx, issueID)
+	if err != nil {
+		return nil, errors.Wrap(err, "HistoryService.VerifyIssueHistory", "get_history")
+	}
+
+	return s.verifyHistory(ctx, history), nil
+}
+
+// VerifyAllHistory checks the hash chain and any signatures of every issue's history
+func (s *HistoryService) VerifyAllHistory(ctx context.Context) ([]*entities.HistoryVerification, error) {
+	histories, err := s.historyRepo.GetAllHistory(ctx, repositories.HistoryFilter{})
+	if err != nil {
+		return nil, errors.Wrap(err, "HistoryService.VerifyAllHistory", "get_all_history")
+	}
+
+	results := make([]*entities.HistoryVerification, 0, len(histories))
+	for _, history := range histories {
+		results = append(results, s.verifyHistory(ctx, history))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].IssueID < results[j].IssueID
+	})
+
+	return results, nil
+}
+
+// CompactIssueHistory replaces entries older than before with a checkpoint,
+// keeping at least keep of the most recent entries. The checkpoint is signed
+// when history signing is enabled; if it cannot be hashed or signed the
+// history is left as it was.
+func (s *HistoryService) CompactIssueHistory(ctx context.Context, issueID entities.IssueID, before time.Time, keep int, author string) (*entities.HistoryCheckpoint, error) {
+	history, err := s.historyRepo.GetHistory(ctx, issueID)
+	if err != nil {
+		return nil, errors.Wrap(err, "HistoryService.CompactIssueHistory", "get_history")
+	}
+
+	checkpoint, err := history.Compact(before, keep, author)
+	if err != nil {
+		return nil, errors.Wrap(err, "HistoryService.CompactIssueHistory", "hash")
+	}
+	if checkpoint == nil {
+		return nil, nil
+	}
+
+	if s.gitRepo != nil && s.gitRepo.HistorySigningEnabled(ctx) {
+		signature, err := s.gitRepo.SignData(ctx, []byte(checkpoint.Hash))
+		if err != nil {
+			return nil, errors.Wrap(err, "HistoryService.CompactIssueHistory", "sign")
+		}
+		checkpoint.Signature = signature
+	}
+
+	if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
+		return nil, errors.Wrap(err, "HistoryService.CompactIssueHistory", "save_history")
+	}
+
+	return checkpoint, nil
+}
+
+// addEntry appends an entry to the issue's hash chain, signing it when
+// history signing is enabled in git config
+func (s *HistoryService) addEntry(ctx context.Context, entry *entities.HistoryEntry) error {
+	if s.gitRepo == nil || !s.gitRepo.HistorySigningEnabled(ctx) {
+		return s.historyRepo.AddEntry(ctx, entry)
+	}
+
+	history, err := s.historyRepo.GetHistory(ctx, entry.IssueID)
+	if err != nil {
+		return errors.Wrap(err, "HistoryService.addEntry", "get_history")
+	}
+
+	history.AddEntry(entry)
+	added := &history.Entries[len(history.Entries)-1]
+
+	signature, err := s.gitRepo.SignData(ctx, []byte(added.Hash))
+	if err != nil {
+		return errors.Wrap(err, "HistoryService.addEntry", "sign")
+	}
+	added.Signature = signature
+	entry.Signature = signature
+
+	return s.historyRepo.CreateHistory(ctx, history)
+}
+
+// verifyHistory runs the chain checks and verifies signatures through git
+func (s *HistoryService) verifyHistory(ctx context.Context, history *entities.IssueHistory) *entities.HistoryVerification {
+	result := history.VerifyChain()
+
+	signers := make(map[string]bool)
+	checkSignature := func(version int, entryID, hash, signature, label string) {
+		if signature == "" {
+			return
+		}
+		if s.gitRepo == nil {
+			result.AddProblem(entities.ChainProblemSignature, version, entryID, label+" is signed but git is not available to verify it")
+			return
+		}
+		signer, err := s.gitRepo.VerifySignature(ctx, []byte(hash), signature)
+		if err != nil {
+			result.AddProblem(entities.ChainProblemSignature, version, entryID, fmt.Sprintf("%s has an invalid signature: %v", label, err))
+			return
+		}
+		if !signers[signer] {
+			signers[signer] = true
+			result.Signers = append(result.Signers, signer)
+		}
+	}
+
+	for _, cp := range history.Checkpoints {
+		checkSignature(cp.FromVersion, "", cp.Hash, cp.Signature, fmt.Sprintf("checkpoint v%d-v%d", cp.FromVersion, cp.ToVersion))
+	}
+	for _, entry := range history.Entries {
+		checkSignature(entry.Version, entry.ID, entry.Hash, entry.Signature, fmt.Sprintf("entry v%d", entry.Version))
+	}
+
+	return result
+}
+
 // joinWithComma joins a slice of strings with commas
 func joinWithComma(items []string) string {
 	if len(items) == 0 {
@@ -403,7 +599,7 @@ func (s *HistoryService) RecordTimerStarted(ctx context.Context, issueID entitie
 		entry.SetMetadata("description", description)
 	}
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordTimerStopped records when a timer is stopped for an issue
@@ -420,7 +616,7 @@ func (s *HistoryService) RecordTimerStopped(ctx context.Context, issueID entitie
 	entry.SetMetadata("action", "timer_stopped")
 	entry.SetMetadata("hours_logged", fmt.Sprintf("%.2f", hours))
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordTimeLogged records when time is manually logged for an issue
@@ -443,7 +639,7 @@ func (s *HistoryService) RecordTimeLogged(ctx context.Context, issueID entities.
 		entry.SetMetadata("description", description)
 	}
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordDependencyCreated records when a dependency is created
@@ -459,7 +655,7 @@ func (s *HistoryService) RecordDependencyCreated(ctx context.Context, sourceID,
 	entry.SetMetadata("target_issue", string(targetID))
 	entry.SetMetadata("dependency_type", string(depType))
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordDependencyRemoved records when a dependency is removed
@@ -475,7 +671,7 @@ func (s *HistoryService) RecordDependencyRemoved(ctx context.Context, sourceID,
 	entry.SetMetadata("target_issue", string(targetID))
 	entry.SetMetadata("dependency_type", string(depType))
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordDependencyResolved records when a dependency is resolved
@@ -491,7 +687,7 @@ func (s *HistoryService) RecordDependencyResolved(ctx context.Context, sourceID,
 	entry.SetMetadata("target_issue", string(targetID))
 	entry.SetMetadata("dependency_type", string(depType))
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // RecordDependencyReactivated records when a dependency is reactivated
@@ -507,7 +703,7 @@ func (s *HistoryService) RecordDependencyReactivated(ctx context.Context, source
 	entry.SetMetadata("target_issue", string(targetID))
 	entry.SetMetadata("dependency_type", string(depType))
 
-	return s.historyRepo.AddEntry(ctx, entry)
+	return s.addEntry(ctx, entry)
 }
 
 // stringSlicesEqual compares two string slices for equality
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// failingSigner is a git repository with history signing enabled whose
+// signing key cannot be used
+type failingSigner struct {
+	repositories.GitRepository
+}
+
+func (failingSigner) HistorySigningEnabled(ctx context.Context) bool { return true }
+
+func (failingSigner) SignData(ctx context.Context, data []byte) (string, error) {
+	return "", fmt.Errorf("gpg: signing failed: No secret key")
+}
+
+func TestHistoryService_CompactAbortsWhenSigningFails(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+	historyRepo := storage.NewFileHistoryRepository(basePath)
+
+	for i := 0; i < 4; i++ {
+		entry := entities.NewHistoryEntry("TEST-001", entities.ChangeTypeCommented, "alice", fmt.Sprintf("note %d", i))
+		entry.Timestamp = time.Now().AddDate(0, 0, -30+i)
+		require.NoError(t, historyRepo.AddEntry(ctx, entry))
+	}
+	path := filepath.Join(basePath, "history", "TEST-001.yaml")
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	// An unsigned checkpoint would later fail verification, so nothing is written
+	checkpoint, err := NewHistoryService(historyRepo, failingSigner{}).CompactIssueHistory(ctx, "TEST-001", time.Now(), 1, "cleanup")
+	assert.Error(t, err)
+	assert.Nil(t, checkpoint)
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after))
+
+	// Without signing the history is compacted and still verifies
+	checkpoint, err = NewHistoryService(historyRepo, nil).CompactIssueHistory(ctx, "TEST-001", time.Now(), 1, "cleanup")
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, 3, checkpoint.EntryCount)
+	history, err := historyRepo.GetHistory(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Len(t, history.Entries, 1)
+	verification := history.VerifyChain()
+	assert.True(t, verification.Valid(), verification.Problems)
+}
@@ -24,6 +24,8 @@ type IssueService struct {
 	gitRepo        repositories.GitRepository
 	historyRepo    repositories.HistoryRepository
 	historyService *HistoryService
+
+	notificationRepo repositories.NotificationRepository
 }
 
 // NewIssueService creates a new issue service
@@ -45,12 +47,19 @@ func NewIssueService(
 	historyRepo := storage.NewFileHistoryRepository(basePath)
 	historyService := NewHistoryService(historyRepo, gitRepo)
 
+	// Notifications are kept next to the issues they are about
+	var notificationRepo repositories.NotificationRepository
+	if fileRepo, ok := issueRepo.(*storage.FileIssueRepository); ok {
+		notificationRepo = storage.NewFileNotificationRepository(fileRepo.GetBasePath())
+	}
+
 	return &IssueService{
-		issueRepo:      issueRepo,
-		configRepo:     configRepo,
-		gitRepo:        gitRepo,
-		historyRepo:    historyRepo,
-		historyService: historyService,
+		issueRepo:        issueRepo,
+		configRepo:       configRepo,
+		gitRepo:          gitRepo,
+		historyRepo:      historyRepo,
+		historyService:   historyService,
+		notificationRepo: notificationRepo,
 	}
 }
 
@@ -65,6 +74,9 @@ type CreateIssueRequest struct {
 	Milestone   *string                `json:"milestone,omitempty"`
 	Template    *string                `json:"template,omitempty"`
 	FieldValues map[string]interface{} `json:"field_values,omitempty"`
+
+	// Confidential issues are stored encrypted to the configured recipients
+	Confidential bool `json:"confidential,omitempty"`
 }
 
 // CreateIssue creates a new issue
@@ -143,6 +155,7 @@ func (s *IssueService) CreateIssue(ctx context.Context, req CreateIssueRequest)
 	// Create the issue
 	issue := entities.NewIssue(id, req.Title, req.Description, req.Type)
 	issue.Priority = req.Priority
+	issue.Confidential = req.Confidential
 
 	// Use default config if not loaded above
 	if config == nil {
@@ -167,7 +180,7 @@ func (s *IssueService) CreateIssue(ctx context.Context, req CreateIssueRequest)
 	// Set assignee
 	if req.Assignee != nil {
 		user := &entities.User{Username: *req.Assignee}
-		issue.SetAssignee(user)
+		issue.SetAssignee(config.UserDirectory().CanonicalUser(user))
 	}
 
 	// Set milestone
@@ -196,19 +209,25 @@ func (s *IssueService) CreateIssue(ctx context.Context, req CreateIssueRequest)
 		}
 	}
 
+	// The creator, the assignee and the people mentioned watch the issue
+	author := s.currentAuthor(ctx)
+	activity := issueActivity{
+		actor:    author,
+		mentions: s.resolveMentions(config.UserDirectory(), issue.Description),
+	}
+	if issue.Assignee != nil {
+		activity.assignee = issue.Assignee.Username
+	}
+	issue.AddWatcher(author)
+	notifications := s.watchActivity(issue, activity)
+
 	// Save the issue
 	if err := s.issueRepo.Create(ctx, issue); err != nil {
 		return nil, errors.Wrap(err, "IssueService.CreateIssue", "save")
 	}
+	s.deliverNotifications(ctx, notifications)
 
 	// Record creation in history
-	author := "system"
-	if s.gitRepo != nil {
-		if user, err := s.gitRepo.GetAuthorInfo(ctx); err == nil {
-			author = user.Username
-		}
-	}
-
 	if s.historyService != nil {
 		if err := s.historyService.RecordIssueCreated(ctx, issue, author); err != nil {
 			// Don't fail the creation if history fails, just log
@@ -240,20 +259,31 @@ func (s *IssueService) GetIssue(ctx context.Context, id entities.IssueID) (*enti
 		Branch:      issue.Branch,
 		Commits:     []entities.CommitRef{}, // Will be populated below
 		Comments:    make([]entities.Comment, len(issue.Comments)),
+		Attachments: make([]entities.Attachment, len(issue.Attachments)),
 		Metadata:    issue.Metadata,
 		Timestamps:  issue.Timestamps,
+		Watchers:    append([]string(nil), issue.Watchers...),
+
+		Confidential: issue.Confidential,
+		Sealed:       issue.Sealed,
+		Redacted:     issue.Redacted,
 	}
 	copy(issueCopy.Labels, issue.Labels)
 	copy(issueCopy.Comments, issue.Comments)
+	copy(issueCopy.Attachments, issue.Attachments)
 
 	// Update issue with latest commits if git is available.
 	// Do not gate on branch presence, since commit messages can reference issues without a branch link.
 	if s.gitRepo != nil {
 		commits, err := s.gitRepo.GetCommitsByIssue(ctx, id)
 		if err == nil {
-			// Update commits in issue copy
-			var commitRefs []entities.CommitRef
+			// Keep the commits recorded on the issue, such as those linked
+			// by 'issuemap git scan' from branches, and add the others
+			commitRefs := append([]entities.CommitRef(nil), issue.Commits...)
 			for _, commit := range commits {
+				if issue.HasCommit(commit.Hash) {
+					continue
+				}
 				commitRef := entities.CommitRef{
 					Hash:    commit.Hash,
 					Message: commit.Message,
@@ -295,6 +325,10 @@ func (s *IssueService) UpdateIssue(ctx context.Context, id entities.IssueID, upd
 			Comments:    issue.Comments,
 			Metadata:    issue.Metadata,
 			Timestamps:  issue.Timestamps,
+
+			Confidential: issue.Confidential,
+			Sealed:       issue.Sealed,
+			Redacted:     issue.Redacted,
 		}
 		copy(originalIssue.Labels, issue.Labels)
 		if issue.Assignee != nil {
@@ -319,6 +353,15 @@ func (s *IssueService) UpdateIssue(ctx context.Context, id entities.IssueID, upd
 		config = entities.NewDefaultConfig()
 	}
 
+	// Remember what watchers are told about once the updates are applied
+	previousStatus := issue.Status
+	previousDescription := issue.Description
+	previousComments := len(issue.Comments)
+	var previousAssignee string
+	if issue.Assignee != nil {
+		previousAssignee = issue.Assignee.Username
+	}
+
 	// Apply updates
 	for field, value := range updates {
 		switch field {
@@ -348,13 +391,17 @@ func (s *IssueService) UpdateIssue(ctx context.Context, id entities.IssueID, upd
 					issue.SetAssignee(nil)
 				} else {
 					user := &entities.User{Username: assignee}
-					issue.SetAssignee(user)
+					issue.SetAssignee(s.userDirectory(ctx).CanonicalUser(user))
 				}
 			}
 		case "branch":
 			if branch, ok := value.(string); ok {
 				issue.Branch = branch
 			}
+		case "pull_request":
+			if pullRequest, ok := value.(*entities.PullRequestRef); ok {
+				issue.PullRequest = pullRequest
+			}
 		case "labels":
 			if labelNames, ok := value.([]string); ok {
 				// Clear existing labels
@@ -428,6 +475,32 @@ func (s *IssueService) UpdateIssue(ctx context.Context, id entities.IssueID, upd
 		}
 	}
 
+	// Tell the watchers about the changes; commenters and the people
+	// mentioned or assigned start watching
+	author := s.currentAuthor(ctx)
+	directory := config.UserDirectory()
+	activity := issueActivity{
+		actor:    author,
+		mentions: s.newMentions(directory, previousDescription, issue.Description),
+	}
+	if issue.Assignee != nil && issue.Assignee.Username != previousAssignee {
+		activity.assignee = issue.Assignee.Username
+	}
+	if issue.Status != previousStatus {
+		activity.reason = entities.NotificationStatusChanged
+	}
+	notifications := s.watchActivity(issue, activity)
+	if len(issue.Comments) > previousComments {
+		for _, comment := range issue.Comments[previousComments:] {
+			issue.AddWatcher(comment.Author)
+			notifications = append(notifications, s.watchActivity(issue, issueActivity{
+				actor:    comment.Author,
+				reason:   entities.NotificationCommented,
+				mentions: s.resolveMentions(directory, comment.Text),
+			})...)
+		}
+	}
+
 	// Update timestamps
 	issue.Timestamps.Updated = time.Now()
 
@@ -435,15 +508,9 @@ func (s *IssueService) UpdateIssue(ctx context.Context, id entities.IssueID, upd
 	if err := s.issueRepo.Update(ctx, issue); err != nil {
 		return nil, errors.Wrap(err, "IssueService.UpdateIssue", "save")
 	}
+	s.deliverNotifications(ctx, notifications)
 
 	// Record update in history with detailed field changes
-	author := "system"
-	if s.gitRepo != nil {
-		if user, err := s.gitRepo.GetAuthorInfo(ctx); err == nil {
-			author = user.Username
-		}
-	}
-
 	if s.historyService != nil && originalIssue != nil {
 		if err := s.historyService.RecordIssueUpdatedWithDetails(ctx, issue.ID, originalIssue, issue, author); err != nil {
 			// Don't fail the update if history fails, just log
@@ -481,15 +548,47 @@ func (s *IssueService) AddComment(ctx context.Context, issueID entities.IssueID,
 		return errors.Wrap(err, "IssueService.AddComment", "get_issue")
 	}
 
+	author = s.ResolveIdentity(ctx, author)
 	issue.AddComment(author, text)
 
+	// Commenters watch the issue, and the people they mention are told
+	issue.AddWatcher(author)
+	notifications := s.watchActivity(issue, issueActivity{
+		actor:    author,
+		reason:   entities.NotificationCommented,
+		mentions: s.resolveMentions(s.userDirectory(ctx), text),
+	})
+
 	if err := s.issueRepo.Update(ctx, issue); err != nil {
 		return errors.Wrap(err, "IssueService.AddComment", "save")
 	}
+	s.deliverNotifications(ctx, notifications)
 
 	return nil
 }
 
+// ResolveIdentity returns the canonical handle of an identity, such as a
+// git name or email, from the user directory
+func (s *IssueService) ResolveIdentity(ctx context.Context, identity string) string {
+	return s.userDirectory(ctx).Canonical(identity)
+}
+
+// userDirectory returns the configured user directory, nil if there is none
+func (s *IssueService) userDirectory(ctx context.Context) *entities.UserDirectory {
+	return loadUserDirectory(ctx, s.configRepo)
+}
+
+// currentAuthor returns the canonical handle of the git user, or "system"
+// when there is none
+func (s *IssueService) currentAuthor(ctx context.Context) string {
+	if s.gitRepo != nil {
+		if user, err := s.gitRepo.GetAuthorInfo(ctx); err == nil {
+			return s.userDirectory(ctx).CanonicalUser(user).Username
+		}
+	}
+	return "system"
+}
+
 // CloseIssue closes an issue
 func (s *IssueService) CloseIssue(ctx context.Context, issueID entities.IssueID, reason string) error {
 	issue, err := s.issueRepo.GetByID(ctx, issueID)
@@ -503,9 +602,15 @@ func (s *IssueService) CloseIssue(ctx context.Context, issueID entities.IssueID,
 		issue.AddComment("system", fmt.Sprintf("Issue closed: %s", reason))
 	}
 
+	notifications := s.watchActivity(issue, issueActivity{
+		actor:  s.currentAuthor(ctx),
+		reason: entities.NotificationStatusChanged,
+	})
+
 	if err := s.issueRepo.Update(ctx, issue); err != nil {
 		return errors.Wrap(err, "IssueService.CloseIssue", "save")
 	}
+	s.deliverNotifications(ctx, notifications)
 
 	return nil
 }
@@ -520,13 +625,65 @@ func (s *IssueService) ReopenIssue(ctx context.Context, issueID entities.IssueID
 	issue.UpdateStatus(entities.StatusOpen)
 	issue.AddComment("system", "Issue reopened")
 
+	notifications := s.watchActivity(issue, issueActivity{
+		actor:  s.currentAuthor(ctx),
+		reason: entities.NotificationStatusChanged,
+	})
+
 	if err := s.issueRepo.Update(ctx, issue); err != nil {
 		return errors.Wrap(err, "IssueService.ReopenIssue", "save")
 	}
+	s.deliverNotifications(ctx, notifications)
 
 	return nil
 }
 
+// SetConfidential marks an issue confidential, sealing its content to the
+// configured recipients, or stores it in clear again
+func (s *IssueService) SetConfidential(ctx context.Context, issueID entities.IssueID, confidential bool) (*entities.Issue, error) {
+	issue, err := s.issueRepo.GetByID(ctx, issueID)
+	if err != nil {
+		return nil, errors.Wrap(err, "IssueService.SetConfidential", "get_issue")
+	}
+	if issue.Redacted {
+		return nil, errors.New("IssueService.SetConfidential", "confidential",
+			fmt.Errorf("%w: %s cannot be decrypted with this identity", errors.ErrPermissionDenied, issueID))
+	}
+
+	issue.Confidential = confidential
+	if err := s.issueRepo.Update(ctx, issue); err != nil {
+		return nil, errors.Wrap(err, "IssueService.SetConfidential", "save")
+	}
+
+	return issue, nil
+}
+
+// RekeyConfidentialIssues seals every confidential issue again to the current
+// recipients. Issues that cannot be decrypted with this identity are skipped.
+func (s *IssueService) RekeyConfidentialIssues(ctx context.Context) (rekeyed, skipped []entities.IssueID, err error) {
+	issueList, err := s.issueRepo.List(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "IssueService.RekeyConfidentialIssues", "list_issues")
+	}
+
+	for i := range issueList.Issues {
+		issue := &issueList.Issues[i]
+		if !issue.Confidential {
+			continue
+		}
+		if issue.Redacted {
+			skipped = append(skipped, issue.ID)
+			continue
+		}
+		if err := s.issueRepo.Update(ctx, issue); err != nil {
+			return rekeyed, skipped, errors.Wrap(err, "IssueService.RekeyConfidentialIssues", "save")
+		}
+		rekeyed = append(rekeyed, issue.ID)
+	}
+
+	return rekeyed, skipped, nil
+}
+
 // DeleteIssue completely removes an issue and its history
 func (s *IssueService) DeleteIssue(ctx context.Context, issueID entities.IssueID) error {
 	// First check if the issue exists
@@ -551,6 +708,170 @@ func (s *IssueService) DeleteIssue(ctx context.Context, issueID entities.IssueID
 	return nil
 }
 
+// GetIssueAtVersion reconstructs an issue as it was at a given history version
+func (s *IssueService) GetIssueAtVersion(ctx context.Context, issueID entities.IssueID, version int) (*entities.Issue, error) {
+	issue, history, err := s.getIssueWithHistory(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	past, err := history.IssueAt(issue, version)
+	if err != nil {
+		return nil, errors.Wrap(err, "IssueService.GetIssueAtVersion", "reconstruct")
+	}
+
+	return past, nil
+}
+
+// GetIssueAtTime reconstructs an issue as it was at a point in time, returning
+// the version that was current then
+func (s *IssueService) GetIssueAtTime(ctx context.Context, issueID entities.IssueID, at time.Time) (*entities.Issue, int, error) {
+	issue, history, err := s.getIssueWithHistory(ctx, issueID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	version := history.VersionAt(at)
+	if version == 0 {
+		return nil, 0, errors.New("IssueService.GetIssueAtTime", "not_found",
+			fmt.Errorf("issue %s did not exist at %s", issueID, at.Format(time.RFC3339)))
+	}
+
+	past, err := history.IssueAt(issue, version)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "IssueService.GetIssueAtTime", "reconstruct")
+	}
+
+	return past, version, nil
+}
+
+// DiffIssueVersions returns the field changes between two history versions of an issue
+func (s *IssueService) DiffIssueVersions(ctx context.Context, issueID entities.IssueID, fromVersion, toVersion int) ([]entities.FieldChange, error) {
+	issue, history, err := s.getIssueWithHistory(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	from, err := history.IssueAt(issue, fromVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "IssueService.DiffIssueVersions", "reconstruct_from")
+	}
+	to, err := history.IssueAt(issue, toVersion)
+	if err != nil {
+		return nil, errors.Wrap(err, "IssueService.DiffIssueVersions", "reconstruct_to")
+	}
+
+	return entities.DiffIssues(from, to), nil
+}
+
+// RevertIssue restores the tracked fields of an issue to an earlier version.
+// The inverse changes are recorded as a new "reverted" history entry, so the
+// revert itself can be reverted.
+func (s *IssueService) RevertIssue(ctx context.Context, issueID entities.IssueID, toVersion int) (*entities.Issue, []entities.FieldChange, error) {
+	issue, history, err := s.getIssueWithHistory(ctx, issueID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := history.IssueAt(issue, toVersion)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "IssueService.RevertIssue", "reconstruct")
+	}
+
+	// History does not record the content of sealed fields, so confidential
+	// issues keep their current values
+	if issue.Sealed != nil {
+		target.ApplySealedFields(issue.Sealed.Fields, issue.ExtractSealedFields(issue.Sealed.Fields))
+	}
+
+	changes := entities.DiffIssues(issue, target)
+	if len(changes) == 0 {
+		return issue, changes, nil
+	}
+
+	original := *issue
+	original.Labels = append([]entities.Label{}, issue.Labels...)
+
+	config, err := s.configRepo.Load(ctx)
+	if err != nil {
+		config = entities.NewDefaultConfig()
+	}
+
+	issue.Title = target.Title
+	issue.Description = target.Description
+	issue.Type = target.Type
+	issue.Priority = target.Priority
+	issue.Branch = target.Branch
+	issue.Assignee = target.Assignee
+	if target.Status != issue.Status {
+		issue.UpdateStatus(target.Status)
+		if target.Status != entities.StatusClosed {
+			issue.Timestamps.Closed = nil
+		}
+	}
+
+	issue.Milestone = target.Milestone
+	if issue.Milestone != nil && (original.Milestone == nil || original.Milestone.Name != issue.Milestone.Name) {
+		for _, configMilestone := range config.Milestones {
+			if configMilestone.Name == issue.Milestone.Name {
+				milestone := configMilestone
+				issue.Milestone = &milestone
+				break
+			}
+		}
+	}
+
+	issue.Labels = []entities.Label{}
+	for _, label := range target.Labels {
+		if label.Color == "" {
+			label.Color = "#gray"
+			for _, configLabel := range config.Labels {
+				if configLabel.Name == label.Name {
+					label = configLabel
+					break
+				}
+			}
+		}
+		issue.Labels = append(issue.Labels, label)
+	}
+
+	if err := s.issueRepo.Update(ctx, issue); err != nil {
+		return nil, nil, errors.Wrap(err, "IssueService.RevertIssue", "save")
+	}
+
+	author := s.currentAuthor(ctx)
+
+	if err := s.historyService.RecordIssueReverted(ctx, issue.ID, &original, issue, toVersion, author); err != nil {
+		return nil, nil, errors.Wrap(err, "IssueService.RevertIssue", "record_history")
+	}
+
+	return issue, changes, nil
+}
+
+// GetIssueHistory returns the recorded history of an issue
+func (s *IssueService) GetIssueHistory(ctx context.Context, issueID entities.IssueID) (*entities.IssueHistory, error) {
+	history, err := s.historyService.GetIssueHistory(ctx, issueID)
+	if err != nil {
+		return nil, errors.Wrap(err, "IssueService.GetIssueHistory", "get_history")
+	}
+	return history, nil
+}
+
+// getIssueWithHistory loads an issue together with its history
+func (s *IssueService) getIssueWithHistory(ctx context.Context, issueID entities.IssueID) (*entities.Issue, *entities.IssueHistory, error) {
+	issue, err := s.issueRepo.GetByID(ctx, issueID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "IssueService.getIssueWithHistory", "get_issue")
+	}
+
+	history, err := s.historyService.GetIssueHistory(ctx, issueID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "IssueService.getIssueWithHistory", "get_history")
+	}
+
+	return issue, history, nil
+}
+
 // CreateBranchForIssue creates a git branch for an issue
 func (s *IssueService) CreateBranchForIssue(ctx context.Context, issueID entities.IssueID, branchName string) error {
 	issue, err := s.issueRepo.GetByID(ctx, issueID)
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+// issueActivity is a change to an issue that its watchers hear about
+type issueActivity struct {
+	actor string
+
+	// reason is told to every watcher, when set
+	reason entities.NotificationReason
+
+	// assignee is the person the change assigned the issue to
+	assignee string
+
+	// mentions are the handles the change mentioned
+	mentions []string
+}
+
+// Watch subscribes a person to an issue and reports whether they were not
+// watching already
+func (s *IssueService) Watch(ctx context.Context, issueID entities.IssueID, identity string) (bool, error) {
+	issue, err := s.issueRepo.GetByID(ctx, issueID)
+	if err != nil {
+		return false, errors.Wrap(err, "IssueService.Watch", "get_issue")
+	}
+
+	if !issue.AddWatcher(s.ResolveIdentity(ctx, identity)) {
+		return false, nil
+	}
+	if err := s.issueRepo.Update(ctx, issue); err != nil {
+		return false, errors.Wrap(err, "IssueService.Watch", "save")
+	}
+	return true, nil
+}
+
+// Unwatch unsubscribes a person from an issue and reports whether they
+// were watching
+func (s *IssueService) Unwatch(ctx context.Context, issueID entities.IssueID, identity string) (bool, error) {
+	issue, err := s.issueRepo.GetByID(ctx, issueID)
+	if err != nil {
+		return false, errors.Wrap(err, "IssueService.Unwatch", "get_issue")
+	}
+
+	if !issue.RemoveWatcher(s.ResolveIdentity(ctx, identity)) {
+		return false, nil
+	}
+	if err := s.issueRepo.Update(ctx, issue); err != nil {
+		return false, errors.Wrap(err, "IssueService.Unwatch", "save")
+	}
+	return true, nil
+}
+
+// Notifications returns the notifications of a person, newest first
+func (s *IssueService) Notifications(ctx context.Context, identity string, unreadOnly bool) ([]*entities.IssueNotification, error) {
+	if s.notificationRepo == nil {
+		return nil, nil
+	}
+	notifications, err := s.notificationRepo.List(ctx, s.ResolveIdentity(ctx, identity), unreadOnly)
+	if err != nil {
+		return nil, errors.Wrap(err, "IssueService.Notifications", "list")
+	}
+	return notifications, nil
+}
+
+// MarkNotificationsRead marks notifications of a person as read, all of
+// them when no IDs are given, and returns how many were marked
+func (s *IssueService) MarkNotificationsRead(ctx context.Context, identity string, ids []string) (int, error) {
+	if s.notificationRepo == nil {
+		return 0, nil
+	}
+	marked, err := s.notificationRepo.MarkRead(ctx, s.ResolveIdentity(ctx, identity), ids)
+	if err != nil {
+		return 0, errors.Wrap(err, "IssueService.MarkNotificationsRead", "mark_read")
+	}
+	return marked, nil
+}
+
+// watchActivity subscribes the new assignee and the people mentioned in an
+// activity to the issue and returns the notifications for it. They are told
+// directly, the other watchers are told the reason, if any, and nobody is
+// told about their own activity.
+func (s *IssueService) watchActivity(issue *entities.Issue, activity issueActivity) []*entities.IssueNotification {
+	reasons := make(map[string]entities.NotificationReason)
+	var recipients []string
+	notify := func(handle string, reason entities.NotificationReason) {
+		key := strings.ToLower(strings.TrimPrefix(handle, "@"))
+		if _, ok := reasons[key]; ok || key == strings.ToLower(activity.actor) || !issue.IsWatching(handle) {
+			return
+		}
+		reasons[key] = reason
+		recipients = append(recipients, handle)
+	}
+
+	if activity.assignee != "" {
+		issue.AddWatcher(activity.assignee)
+		notify(activity.assignee, entities.NotificationAssigned)
+	}
+	for _, handle := range activity.mentions {
+		issue.AddWatcher(handle)
+		notify(handle, entities.NotificationMentioned)
+	}
+	if activity.reason != "" {
+		for _, watcher := range issue.Watchers {
+			notify(watcher, activity.reason)
+		}
+	}
+
+	var notifications []*entities.IssueNotification
+	for _, recipient := range recipients {
+		reason := reasons[strings.ToLower(strings.TrimPrefix(recipient, "@"))]
+		notification := entities.NewIssueNotification(issue.ID, recipient, activity.actor, reason)
+		// A sealed status would be stored in clear with the notification
+		if reason == entities.NotificationStatusChanged && !issue.IsSealedField(entities.SealedFieldStatus) {
+			notification.Status = issue.Status
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications
+}
+
+// resolveMentions returns the handles of the people mentioned in text.
+// A mention of a team in the user directory mentions its members.
+func (s *IssueService) resolveMentions(directory *entities.UserDirectory, text string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	add := func(handle string) {
+		if key := strings.ToLower(handle); !seen[key] {
+			seen[key] = true
+			handles = append(handles, handle)
+		}
+	}
+
+	for _, mention := range entities.ExtractMentions(text) {
+		if _, isUser := directory.Lookup(mention); !isUser {
+			if members, ok := directory.Team(mention); ok {
+				for _, member := range members {
+					add(member)
+				}
+				continue
+			}
+		}
+		add(directory.Canonical(mention))
+	}
+	return handles
+}
+
+// newMentions returns the handles mentioned in text that were not already
+// mentioned in previous
+func (s *IssueService) newMentions(directory *entities.UserDirectory, previous, text string) []string {
+	before := make(map[string]bool)
+	for _, handle := range s.resolveMentions(directory, previous) {
+		before[strings.ToLower(handle)] = true
+	}
+
+	var mentions []string
+	for _, handle := range s.resolveMentions(directory, text) {
+		if !before[strings.ToLower(handle)] {
+			mentions = append(mentions, handle)
+		}
+	}
+	return mentions
+}
+
+// deliverNotifications stores notifications for their recipients. Issue
+// changes have been saved by then, so failures are reported, not returned.
+func (s *IssueService) deliverNotifications(ctx context.Context, notifications []*entities.IssueNotification) {
+	if s.notificationRepo == nil || len(notifications) == 0 {
+		return
+	}
+	if err := s.notificationRepo.Add(ctx, notifications...); err != nil {
+		fmt.Printf("Warning: Failed to deliver notifications: %v\n", err)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestIssueService_Watchers(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+
+	configRepo := storage.NewFileConfigRepository(basePath)
+	config := entities.NewDefaultConfig()
+	config.Project.Name = "test"
+	config.Users = []entities.UserProfile{
+		{Handle: "jdoe", Aliases: []string{"johnd"}, Teams: []string{"team-backend"}},
+		{Handle: "robert", Teams: []string{"team-backend"}},
+		{Handle: "carol"},
+	}
+	require.NoError(t, configRepo.Save(ctx, config))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	issueService := NewIssueService(issueRepo, configRepo, nil)
+	issueService.historyService = nil
+
+	reasons := func(handle string) []entities.NotificationReason {
+		t.Helper()
+		notifications, err := issueService.Notifications(ctx, handle, true)
+		require.NoError(t, err)
+		var result []entities.NotificationReason
+		for _, notification := range notifications {
+			result = append(result, notification.Reason)
+		}
+		return result
+	}
+
+	// The assignee and the people mentioned watch a new issue
+	assignee := "carol"
+	issue, err := issueService.CreateIssue(ctx, CreateIssueRequest{
+		Title:       "Crash in parser",
+		Description: "Seen by @johnd, mail jdoe@corp.example",
+		Assignee:    &assignee,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol", "jdoe"}, issue.Watchers)
+	assert.Equal(t, []entities.NotificationReason{entities.NotificationAssigned}, reasons("carol"))
+	assert.Equal(t, []entities.NotificationReason{entities.NotificationMentioned}, reasons("jdoe"))
+
+	// Commenters watch, a team mention reaches its members and nobody is
+	// told about their own comment
+	require.NoError(t, issueService.AddComment(ctx, issue.ID, "robert", "Reproduced, @team-backend please look"))
+	stored, err := issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"carol", "jdoe", "robert"}, stored.Watchers)
+	assert.Equal(t, []entities.NotificationReason{entities.NotificationMentioned, entities.NotificationMentioned}, reasons("jdoe"))
+	assert.Equal(t, []entities.NotificationReason{entities.NotificationCommented, entities.NotificationAssigned}, reasons("carol"))
+	assert.Empty(t, reasons("robert"))
+
+	// Status changes reach every watcher still watching
+	removed, err := issueService.Unwatch(ctx, issue.ID, "carol")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	_, err = issueService.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(entities.StatusInProgress)})
+	require.NoError(t, err)
+	assert.Len(t, reasons("carol"), 2)
+	notifications, err := issueService.Notifications(ctx, "robert", true)
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	assert.Equal(t, "system moved TEST-001 to in-progress", notifications[0].Summary())
+
+	// Only new mentions of an edited description are notified
+	_, err = issueService.UpdateIssue(ctx, issue.ID, map[string]interface{}{"description": "Seen by @johnd and @carol"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.NotificationMentioned, reasons("carol")[0])
+	assert.Len(t, reasons("jdoe"), 3)
+
+	marked, err := issueService.MarkNotificationsRead(ctx, "@JDoe", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, marked)
+	assert.Empty(t, reasons("jdoe"))
+
+	// Watching is idempotent and resolves identities
+	added, err := issueService.Watch(ctx, issue.ID, "johnd")
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	watcher := "carol"
+	list, err := issueService.ListIssues(ctx, repositories.IssueFilter{Watcher: &watcher})
+	require.NoError(t, err)
+	assert.Len(t, list.Issues, 1)
+	watcher = "mallory"
+	list, err = issueService.ListIssues(ctx, repositories.IssueFilter{Watcher: &watcher})
+	require.NoError(t, err)
+	assert.Empty(t, list.Issues)
+}
+
+func TestIssueService_SealedStatusNotifications(t *testing.T) {
+	ctx := context.Background()
+	identityPath := filepath.Join(t.TempDir(), "identity.key")
+	identity, err := storage.GenerateConfidentialIdentity(identityPath)
+	require.NoError(t, err)
+	t.Setenv("ISSUEMAP_IDENTITY", identityPath)
+
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+	configRepo := storage.NewFileConfigRepository(basePath)
+	config := entities.NewDefaultConfig()
+	config.Project.Name = "test"
+	config.Users = []entities.UserProfile{{Handle: "carol"}}
+	config.Confidential = &entities.ConfidentialConfig{
+		Recipients: []entities.ConfidentialRecipient{{Name: "carol", PublicKey: identity.PublicKey()}},
+		SealStatus: true,
+	}
+	require.NoError(t, configRepo.Save(ctx, config))
+	issueService := NewIssueService(storage.NewFileIssueRepository(basePath), configRepo, nil)
+	issueService.historyService = nil
+
+	assignee := "carol"
+	issue, err := issueService.CreateIssue(ctx, CreateIssueRequest{
+		Title:        "Credential leak",
+		Assignee:     &assignee,
+		Confidential: true,
+	})
+	require.NoError(t, err)
+	_, err = issueService.UpdateIssue(ctx, issue.ID, map[string]interface{}{"status": string(entities.StatusInProgress)})
+	require.NoError(t, err)
+
+	// The notification says the status changed, not what it changed to
+	notifications, err := issueService.Notifications(ctx, "carol", true)
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	assert.Equal(t, entities.NotificationStatusChanged, notifications[0].Reason)
+	assert.Empty(t, notifications[0].Status)
+	assert.Equal(t, "system changed the status of TEST-001", notifications[0].Summary())
+
+	files, err := filepath.Glob(filepath.Join(basePath, "notifications", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), string(entities.StatusInProgress), file)
+	}
+}
@@ -0,0 +1,522 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// LintConfigFile is the name of the lint rule file in the .issuemap directory
+const LintConfigFile = "lint.yaml"
+
+// LintOptions narrows which violations are reported
+type LintOptions struct {
+	// Only runs just these rules; Skip runs every rule but these
+	Only []string
+	Skip []string
+
+	// MinSeverity hides violations below it; empty reports everything
+	MinSeverity string
+}
+
+// LintService checks issues against the project's lint rules and applies
+// their safe fixes
+type LintService struct {
+	issueService *IssueService
+	config       *entities.LintConfig
+	rules        []compiledLintRule
+	options      LintOptions
+	now          func() time.Time
+}
+
+type compiledLintRule struct {
+	entities.LintRule
+	pattern *regexp.Regexp
+	allowed map[string]bool
+}
+
+// LoadLintConfig reads lint rules from path. Projects without a lint.yaml
+// use the built-in rules.
+func LoadLintConfig(path string) (*entities.LintConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return entities.DefaultLintConfig(), nil
+		}
+		return nil, errors.Wrap(err, "LoadLintConfig", "read")
+	}
+
+	var config entities.LintConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.New("LoadLintConfig", "parse", fmt.Errorf("%s: %w", path, err))
+	}
+	return &config, nil
+}
+
+// SaveLintConfig writes lint rules to path
+func SaveLintConfig(path string, config *entities.LintConfig) error {
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "SaveLintConfig", "marshal")
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "SaveLintConfig", "write")
+	}
+	return nil
+}
+
+// NewLintService creates a lint service for a rule set. Invalid rules are
+// reported here rather than while linting.
+func NewLintService(issueService *IssueService, config *entities.LintConfig, options LintOptions) (*LintService, error) {
+	if config == nil {
+		config = entities.DefaultLintConfig()
+	}
+	if options.MinSeverity != "" && entities.LintSeverityRank(options.MinSeverity) == 0 {
+		return nil, errors.New("NewLintService", "invalid_severity",
+			fmt.Errorf("severity must be info, warning or error, got %q", options.MinSeverity))
+	}
+
+	rules, err := config.ActiveRules()
+	if err != nil {
+		return nil, errors.New("NewLintService", "invalid_config", err)
+	}
+
+	compiled := make([]compiledLintRule, 0, len(rules))
+	for _, rule := range rules {
+		if len(options.Only) > 0 && !containsString(options.Only, rule.ID) {
+			continue
+		}
+		if containsString(options.Skip, rule.ID) {
+			continue
+		}
+		if entities.LintSeverityRank(rule.Severity) < entities.LintSeverityRank(options.MinSeverity) {
+			continue
+		}
+
+		c := compiledLintRule{LintRule: rule}
+		if rule.Pattern != "" {
+			c.pattern = regexp.MustCompile(rule.Pattern) // validated by ActiveRules
+		}
+		if len(rule.Values) > 0 {
+			c.allowed = make(map[string]bool, len(rule.Values))
+			for _, v := range rule.Values {
+				c.allowed[v] = true
+			}
+		}
+		compiled = append(compiled, c)
+	}
+
+	return &LintService{
+		issueService: issueService,
+		config:       config,
+		rules:        compiled,
+		options:      options,
+		now:          time.Now,
+	}, nil
+}
+
+// Rules returns the rules that will be checked
+func (s *LintService) Rules() []entities.LintRule {
+	rules := make([]entities.LintRule, len(s.rules))
+	for i, rule := range s.rules {
+		rules[i] = rule.LintRule
+	}
+	return rules
+}
+
+// IssueService returns the issue service fixes are applied through
+func (s *LintService) IssueService() *IssueService {
+	return s.issueService
+}
+
+// Config returns the rule set the service was created with
+func (s *LintService) Config() *entities.LintConfig {
+	return s.config
+}
+
+// LintIssue checks an issue against every rule
+func (s *LintService) LintIssue(issue *entities.Issue) entities.LintResult {
+	result := entities.LintResult{
+		IssueID:    issue.ID,
+		Title:      issue.Title,
+		Violations: []entities.LintViolation{},
+	}
+
+	now := s.now()
+	for i := range s.rules {
+		if violation := s.rules[i].evaluate(issue, now); violation != nil {
+			result.Violations = append(result.Violations, *violation)
+		}
+	}
+
+	result.Score = lintScore(result.Violations)
+	result.Grade = lintGrade(result.Score)
+	return result
+}
+
+// LintAll checks every issue. Only issues with violations are included in
+// the summary results.
+func (s *LintService) LintAll(ctx context.Context, fix bool) (*entities.LintSummary, error) {
+	issueList, err := s.issueService.ListIssues(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, errors.Wrap(err, "LintService.LintAll", "list_issues")
+	}
+
+	var results []entities.LintResult
+	for i := range issueList.Issues {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		issue := &issueList.Issues[i]
+		result := s.LintIssue(issue)
+		if fix {
+			if result, err = s.Fix(ctx, issue, result); err != nil {
+				return nil, err
+			}
+		}
+		if len(result.Violations) > 0 || len(result.Fixed) > 0 {
+			results = append(results, result)
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].IssueID < results[j].IssueID })
+	summary := entities.NewLintSummary(results, len(issueList.Issues))
+	return &summary, nil
+}
+
+// Fix applies the safe fixes of an issue's violations through the issue
+// service, so the changes are recorded in its history, and returns the
+// result of linting the fixed issue
+func (s *LintService) Fix(ctx context.Context, issue *entities.Issue, result entities.LintResult) (entities.LintResult, error) {
+	updates, fixed := s.fixUpdates(issue, result.Violations)
+	if len(updates) == 0 {
+		return result, nil
+	}
+
+	updated, err := s.issueService.UpdateIssue(ctx, issue.ID, updates)
+	if err != nil {
+		return result, errors.Wrap(err, "LintService.Fix", "update_issue")
+	}
+
+	fixedResult := s.LintIssue(updated)
+	fixedResult.Fixed = fixed
+	return fixedResult, nil
+}
+
+// fixUpdates builds the issue updates correcting the given violations and
+// returns them with the IDs of the rules they fix. Fixes to one field are
+// applied in rule order, so a trimmed title is then capitalized.
+func (s *LintService) fixUpdates(issue *entities.Issue, violations []entities.LintViolation) (map[string]interface{}, []string) {
+	failed := make(map[string]bool, len(violations))
+	for _, v := range violations {
+		if v.AutoFixable {
+			failed[v.Rule] = true
+		}
+	}
+
+	updates := make(map[string]interface{})
+	var fixed []string
+	for i := range s.rules {
+		rule := &s.rules[i]
+		if !failed[rule.ID] {
+			continue
+		}
+
+		value, ok := rule.fixValue(issue, updates)
+		if !ok {
+			continue
+		}
+		updates[rule.Field] = value
+		fixed = append(fixed, rule.ID)
+	}
+	return updates, fixed
+}
+
+// fixValue returns the corrected field value, starting from any update an
+// earlier fix made to the same field
+func (r *compiledLintRule) fixValue(issue *entities.Issue, updates map[string]interface{}) (interface{}, bool) {
+	switch r.Fix.Action {
+	case entities.LintFixTrim, entities.LintFixCapitalize:
+		current, ok := updates[r.Field].(string)
+		if !ok {
+			current = lintTextValue(issue, r.Field)
+		}
+		next := strings.TrimSpace(current)
+		if r.Fix.Action == entities.LintFixCapitalize {
+			next = capitalizeFirst(next)
+		}
+		return next, next != current
+
+	case entities.LintFixSet:
+		if r.Field == "estimated_hours" {
+			hours, err := strconv.ParseFloat(r.Fix.Value, 64)
+			if err != nil || hours <= 0 {
+				return nil, false
+			}
+			return hours, true
+		}
+		return r.Fix.Value, true
+
+	case entities.LintFixRemove:
+		var kept []string
+		for _, label := range issue.Labels {
+			if r.allowed[label.Name] {
+				kept = append(kept, label.Name)
+			}
+		}
+		if kept == nil {
+			kept = []string{}
+		}
+		return kept, len(kept) != len(issue.Labels)
+	}
+	return nil, false
+}
+
+// evaluate checks one rule, returning nil when the issue satisfies it
+func (r *compiledLintRule) evaluate(issue *entities.Issue, now time.Time) *entities.LintViolation {
+	ageDays := daysBetween(issue.Timestamps.Created, now)
+	if !r.When.Matches(issue, ageDays) {
+		return nil
+	}
+	// A reader without the key sees placeholders in sealed fields
+	if issue.Redacted && issue.IsSealedField(r.Field) {
+		return nil
+	}
+
+	vars := map[string]string{"days": strconv.Itoa(ageDays)}
+	message, failed := r.check(issue, now, vars)
+	if !failed {
+		return nil
+	}
+
+	if r.Message != "" {
+		message = r.Message
+	}
+	replacements := make([]string, 0, len(vars)*2)
+	for k, v := range vars {
+		replacements = append(replacements, "{"+k+"}", v)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	return &entities.LintViolation{
+		Rule:        r.ID,
+		Severity:    r.Severity,
+		Message:     replacer.Replace(message),
+		Field:       r.Field,
+		Suggestion:  replacer.Replace(r.Suggestion),
+		AutoFixable: r.Fix != nil,
+	}
+}
+
+// check runs the rule's check and returns the generated message when it
+// fails. vars collects values for message placeholders.
+func (r *compiledLintRule) check(issue *entities.Issue, now time.Time, vars map[string]string) (string, bool) {
+	values := lintValues(issue, r.Field)
+
+	switch r.Check {
+	case entities.LintCheckRequired:
+		return fmt.Sprintf("%s is required", r.Field), len(values) == 0
+
+	case entities.LintCheckLength:
+		if len(values) == 0 {
+			return "", false
+		}
+		vars["min"], vars["max"] = strconv.Itoa(r.Min), strconv.Itoa(r.Max)
+		size := len(values)
+		if !r.IsListField() {
+			size = utf8.RuneCountInString(strings.TrimSpace(values[0]))
+		}
+		if r.Min > 0 && size < r.Min {
+			return fmt.Sprintf("%s is shorter than %d", r.Field, r.Min), true
+		}
+		if r.Max > 0 && size > r.Max {
+			return fmt.Sprintf("%s is longer than %d", r.Field, r.Max), true
+		}
+		if r.MinWords > 0 && !r.IsListField() && len(strings.Fields(values[0])) < r.MinWords {
+			vars["min"] = strconv.Itoa(r.MinWords)
+			return fmt.Sprintf("%s has fewer than %d words", r.Field, r.MinWords), true
+		}
+		return "", false
+
+	case entities.LintCheckPattern:
+		for _, v := range values {
+			if !r.pattern.MatchString(v) {
+				vars["values"] = v
+				return fmt.Sprintf("%s does not match %s", r.Field, r.Pattern), true
+			}
+		}
+		return "", false
+
+	case entities.LintCheckForbidden:
+		for _, v := range values {
+			if r.pattern.MatchString(v) {
+				vars["values"] = v
+				return fmt.Sprintf("%s matches forbidden pattern %s", r.Field, r.Pattern), true
+			}
+		}
+		return "", false
+
+	case entities.LintCheckAllowed:
+		var disallowed []string
+		for _, v := range values {
+			if !r.allowed[v] {
+				disallowed = append(disallowed, v)
+			}
+		}
+		vars["values"] = strings.Join(disallowed, ", ")
+		return fmt.Sprintf("%s has values that are not allowed: %s", r.Field, vars["values"]), len(disallowed) > 0
+
+	case entities.LintCheckAge:
+		since := issue.Timestamps.Created
+		if r.Field == "updated" {
+			since = issue.Timestamps.Updated
+		}
+		days := daysBetween(since, now)
+		vars["days"] = strconv.Itoa(days)
+		vars["max"] = strconv.Itoa(r.MaxDays)
+		return fmt.Sprintf("%s %d days ago (more than %d)", r.Field, days, r.MaxDays), days > r.MaxDays
+
+	case entities.LintCheckOverrun:
+		estimate, actual := issue.GetEstimatedHours(), issue.GetActualHours()
+		ratio := r.Ratio
+		if ratio <= 0 {
+			ratio = 1
+		}
+		vars["actual"] = strconv.FormatFloat(actual, 'f', 1, 64)
+		vars["estimate"] = strconv.FormatFloat(estimate, 'f', 1, 64)
+		return fmt.Sprintf("actual time %sh exceeds estimate %sh", vars["actual"], vars["estimate"]),
+			estimate > 0 && actual > estimate*ratio
+	}
+	return "", false
+}
+
+// lintValues returns the non-empty values of an issue field
+func lintValues(issue *entities.Issue, field string) []string {
+	switch field {
+	case "labels":
+		values := make([]string, 0, len(issue.Labels))
+		for _, label := range issue.Labels {
+			values = append(values, label.Name)
+		}
+		return values
+	case "comments":
+		values := make([]string, 0, len(issue.Comments))
+		for _, comment := range issue.Comments {
+			values = append(values, comment.Text)
+		}
+		return values
+	case "estimated_hours", "actual_hours":
+		hours := issue.GetEstimatedHours()
+		if field == "actual_hours" {
+			hours = issue.GetActualHours()
+		}
+		if hours <= 0 {
+			return nil
+		}
+		return []string{strconv.FormatFloat(hours, 'f', -1, 64)}
+	}
+
+	value := lintTextValue(issue, field)
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return []string{value}
+}
+
+// lintTextValue returns a single-valued issue field as text
+func lintTextValue(issue *entities.Issue, field string) string {
+	switch field {
+	case "title":
+		return issue.Title
+	case "description":
+		return issue.Description
+	case "type":
+		return string(issue.Type)
+	case "status":
+		return string(issue.Status)
+	case "priority":
+		return string(issue.Priority)
+	case "assignee":
+		if issue.Assignee != nil {
+			return issue.Assignee.Username
+		}
+	case "milestone":
+		if issue.Milestone != nil {
+			return issue.Milestone.Name
+		}
+	case "branch":
+		return issue.Branch
+	}
+	return ""
+}
+
+func capitalizeFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func daysBetween(since, now time.Time) int {
+	if since.IsZero() {
+		return 0
+	}
+	return int(now.Sub(since).Hours() / 24)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// lintScore starts every issue at 100 and deducts points per violation
+func lintScore(violations []entities.LintViolation) int {
+	score := 100
+	for _, violation := range violations {
+		switch violation.Severity {
+		case entities.LintSeverityError:
+			score -= 15
+		case entities.LintSeverityWarning:
+			score -= 10
+		case entities.LintSeverityInfo:
+			score -= 5
+		}
+	}
+	if score < 0 {
+		score = 0
+	}
+	return score
+}
+
+func lintGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
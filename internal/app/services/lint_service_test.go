@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// newLintTestService creates a lint service over an isolated project
+func newLintTestService(t *testing.T, config *entities.LintConfig) (*LintService, *storage.FileIssueRepository, *HistoryService) {
+	t.Helper()
+	basePath := t.TempDir()
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	historyService := NewHistoryService(storage.NewFileHistoryRepository(basePath), nil)
+	issueService := &IssueService{
+		issueRepo:      issueRepo,
+		configRepo:     storage.NewFileConfigRepository(basePath),
+		historyService: historyService,
+	}
+
+	lintService, err := NewLintService(issueService, config, LintOptions{})
+	require.NoError(t, err)
+	return lintService, issueRepo, historyService
+}
+
+func violatedRules(result entities.LintResult) []string {
+	rules := []string{}
+	for _, v := range result.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return rules
+}
+
+func TestLintService_Checks(t *testing.T) {
+	config := &entities.LintConfig{Rules: []entities.LintRule{
+		{ID: "bug-description", Severity: entities.LintSeverityError, Field: "description", Check: entities.LintCheckRequired,
+			When: &entities.LintCondition{Types: []entities.IssueType{entities.IssueTypeBug}}},
+		{ID: "title-length", Severity: entities.LintSeverityWarning, Field: "title", Check: entities.LintCheckLength, Min: 5, Max: 20},
+		{ID: "ticket-prefix", Severity: entities.LintSeverityInfo, Field: "title", Check: entities.LintCheckPattern, Pattern: `^\[[A-Z]+\]`},
+		{ID: "no-wip", Severity: entities.LintSeverityWarning, Field: "title", Check: entities.LintCheckForbidden, Pattern: `(?i)\bwip\b`},
+		{ID: "known-labels", Severity: entities.LintSeverityWarning, Field: "labels", Check: entities.LintCheckAllowed,
+			Values: []string{"ui", "backend"}, Message: "Unknown labels: {values}"},
+		{ID: "stale", Severity: entities.LintSeverityWarning, Field: "updated", Check: entities.LintCheckAge, MaxDays: 10,
+			Message: "No activity for {days} days"},
+	}}
+	lintService, _, _ := newLintTestService(t, config)
+
+	bug := entities.NewIssue("TEST-001", "WIP crash when saving large files", "", entities.IssueTypeBug)
+	bug.Labels = []entities.Label{{Name: "ui"}, {Name: "urgent"}}
+	bug.Timestamps.Updated = time.Now().Add(-30 * 24 * time.Hour)
+
+	result := lintService.LintIssue(bug)
+	assert.ElementsMatch(t, []string{"bug-description", "title-length", "ticket-prefix", "no-wip", "known-labels", "stale"},
+		violatedRules(result))
+	for _, v := range result.Violations {
+		switch v.Rule {
+		case "known-labels":
+			assert.Equal(t, "Unknown labels: urgent", v.Message)
+		case "stale":
+			assert.Equal(t, "No activity for 30 days", v.Message)
+		}
+	}
+	assert.Equal(t, 100-15-4*10-5, result.Score)
+	assert.Equal(t, "F", result.Grade)
+
+	task := entities.NewIssue("TEST-002", "[API] Add paging", "", entities.IssueTypeTask)
+	assert.Empty(t, lintService.LintIssue(task).Violations)
+}
+
+func TestLintService_Fix(t *testing.T) {
+	config := &entities.LintConfig{Rules: []entities.LintRule{
+		{ID: "title-whitespace", Severity: entities.LintSeverityInfo, Field: "title", Check: entities.LintCheckPattern,
+			Pattern: `^\S(.*\S)?$`, Fix: &entities.LintFix{Action: entities.LintFixTrim}},
+		{ID: "title-capitalization", Severity: entities.LintSeverityInfo, Field: "title", Check: entities.LintCheckForbidden,
+			Pattern: `^\s*\p{Ll}`, Fix: &entities.LintFix{Action: entities.LintFixCapitalize}},
+		{ID: "milestone-missing", Severity: entities.LintSeverityWarning, Field: "milestone", Check: entities.LintCheckRequired,
+			Fix: &entities.LintFix{Action: entities.LintFixSet, Value: "backlog"}},
+		{ID: "known-labels", Severity: entities.LintSeverityWarning, Field: "labels", Check: entities.LintCheckAllowed,
+			Values: []string{"ui"}, Fix: &entities.LintFix{Action: entities.LintFixRemove}},
+		{ID: "description-required", Severity: entities.LintSeverityWarning, Field: "description", Check: entities.LintCheckRequired},
+	}}
+	lintService, issueRepo, historyService := newLintTestService(t, config)
+	ctx := context.Background()
+
+	issue := entities.NewIssue("TEST-001", "  improve the editor ", "", entities.IssueTypeTask)
+	issue.Labels = []entities.Label{{Name: "ui"}, {Name: "misc"}}
+	require.NoError(t, issueRepo.Create(ctx, issue))
+
+	result := lintService.LintIssue(issue)
+	require.Len(t, result.Violations, 5)
+
+	fixed, err := lintService.Fix(ctx, issue, result)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"title-whitespace", "title-capitalization", "milestone-missing", "known-labels"}, fixed.Fixed)
+	assert.Equal(t, []string{"description-required"}, violatedRules(fixed), "unfixable violations remain")
+
+	stored, err := issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Improve the editor", stored.Title)
+	require.NotNil(t, stored.Milestone)
+	assert.Equal(t, "backlog", stored.Milestone.Name)
+	require.Len(t, stored.Labels, 1)
+	assert.Equal(t, "ui", stored.Labels[0].Name)
+
+	history, err := historyService.GetIssueHistory(ctx, issue.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, history.Entries, "fixes are recorded in the issue history")
+}
+
+func TestLintService_Options(t *testing.T) {
+	lintService, err := NewLintService(nil, nil, LintOptions{
+		Skip:        []string{"title-too-short"},
+		MinSeverity: entities.LintSeverityWarning,
+	})
+	require.NoError(t, err)
+	for _, rule := range lintService.Rules() {
+		assert.NotEqual(t, "title-too-short", rule.ID)
+		assert.NotEqual(t, entities.LintSeverityInfo, rule.Severity)
+	}
+
+	_, err = NewLintService(nil, nil, LintOptions{MinSeverity: "fatal"})
+	assert.Error(t, err)
+
+	_, err = NewLintService(nil, &entities.LintConfig{Rules: []entities.LintRule{{ID: "broken", Check: "spelling"}}}, LintOptions{})
+	assert.Error(t, err)
+}
+
+func TestLoadLintConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, LintConfigFile)
+
+	config, err := LoadLintConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, entities.DefaultLintConfig().Rules, config.Rules, "missing file uses built-in rules")
+
+	require.NoError(t, os.WriteFile(path, []byte(`
+fail_on: warning
+rules:
+  - id: feature-milestone
+    severity: warning
+    field: milestone
+    check: required
+    when:
+      types: [feature]
+`), 0644))
+	config, err = LoadLintConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, "warning", config.FailOn)
+	require.Len(t, config.Rules, 1)
+	assert.Equal(t, []entities.IssueType{entities.IssueTypeFeature}, config.Rules[0].When.Types)
+
+	require.NoError(t, SaveLintConfig(path, entities.DefaultLintConfig()))
+	config, err = LoadLintConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, entities.DefaultLintConfig().Rules, config.Rules)
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// maxOwnershipAttachmentSize limits how much of a text attachment is
+// searched for file paths
+const maxOwnershipAttachmentSize = 1 << 20
+
+// OwnershipService suggests and sets issue assignees from code ownership:
+// CODEOWNERS rules for the files an issue mentions or its commits changed,
+// and the label and component rules of the owners configuration
+type OwnershipService struct {
+	issueRepo         repositories.IssueRepository
+	configRepo        repositories.ConfigRepository
+	gitRepo           repositories.GitRepository
+	attachmentService *AttachmentService
+	basePath          string
+}
+
+// NewOwnershipService creates an ownership service for the .issuemap
+// directory at basePath. gitRepo may be nil, in which case CODEOWNERS and
+// commits are not consulted.
+func NewOwnershipService(
+	issueRepo repositories.IssueRepository,
+	configRepo repositories.ConfigRepository,
+	gitRepo repositories.GitRepository,
+	basePath string,
+) *OwnershipService {
+	return &OwnershipService{
+		issueRepo:  issueRepo,
+		configRepo: configRepo,
+		gitRepo:    gitRepo,
+		basePath:   basePath,
+	}
+}
+
+// SetAttachmentService enables searching text attachments, such as stack
+// traces, for file paths
+func (s *OwnershipService) SetAttachmentService(attachmentService *AttachmentService) {
+	s.attachmentService = attachmentService
+}
+
+// Config returns the owners configuration, which may be nil
+func (s *OwnershipService) Config(ctx context.Context) *entities.OwnersConfig {
+	config, err := s.configRepo.Load(ctx)
+	if err != nil || config == nil {
+		return nil
+	}
+	return config.Owners
+}
+
+// CodeOwners loads the configured CODEOWNERS file, or the first found in
+// the usual locations. It returns nil when there is none.
+func (s *OwnershipService) CodeOwners(ctx context.Context) (*entities.CodeOwners, string, error) {
+	root, err := s.repositoryRoot(ctx)
+	if err != nil || root == "" {
+		return nil, "", err
+	}
+
+	locations := entities.CodeOwnersLocations
+	if config := s.Config(ctx); config != nil && config.CodeOwners != "" {
+		locations = []string{config.CodeOwners}
+	}
+	for _, location := range locations {
+		path := filepath.Join(root, filepath.FromSlash(location))
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", errors.Wrap(err, "OwnershipService.CodeOwners", "read")
+		}
+		owners, err := entities.ParseCodeOwners(string(data))
+		if err != nil {
+			return nil, "", errors.Wrap(err, "OwnershipService.CodeOwners", "parse")
+		}
+		return owners, location, nil
+	}
+	return nil, "", nil
+}
+
+func (s *OwnershipService) repositoryRoot(ctx context.Context) (string, error) {
+	if s.gitRepo == nil {
+		return "", nil
+	}
+	root, err := s.gitRepo.GetRepositoryRoot(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "OwnershipService.repositoryRoot", "repository_root")
+	}
+	return root, nil
+}
+
+// Suggest finds the owners of an issue and picks the assignee without
+// recording a round-robin turn
+func (s *OwnershipService) Suggest(ctx context.Context, issue *entities.Issue) (*entities.AssignmentSuggestion, error) {
+	return s.suggest(ctx, issue, nil)
+}
+
+// Assign picks the owner of an issue and sets them as its assignee. It
+// returns the suggestion; the issue is left unchanged when no owner
+// resolves to a user. A dry run records nothing.
+func (s *OwnershipService) Assign(ctx context.Context, issueID entities.IssueID, dryRun bool) (*entities.AssignmentSuggestion, error) {
+	issue, err := s.issueRepo.GetByID(ctx, issueID)
+	if err != nil {
+		return nil, errors.Wrap(err, "OwnershipService.Assign", "get_issue")
+	}
+
+	state, err := storage.ReadAssignmentState(s.basePath)
+	if err != nil {
+		return nil, err
+	}
+	suggestion, err := s.suggest(ctx, issue, state)
+	if err != nil || suggestion.Assignee == "" || dryRun {
+		return suggestion, err
+	}
+
+	issue.SetAssignee(&entities.User{Username: suggestion.Assignee})
+	issue.AddWatcher(suggestion.Assignee)
+	if err := s.issueRepo.Update(ctx, issue); err != nil {
+		return nil, errors.Wrap(err, "OwnershipService.Assign", "update_issue")
+	}
+	if suggestion.Strategy == entities.AssignmentStrategyRoundRobin {
+		if err := storage.WriteAssignmentState(s.basePath, state); err != nil {
+			return nil, err
+		}
+	}
+
+	notification := entities.NewIssueNotification(issue.ID, suggestion.Assignee, "system", entities.NotificationAssigned)
+	if err := storage.NewFileNotificationRepository(s.basePath).Add(ctx, notification); err != nil {
+		fmt.Printf("Warning: Failed to notify %s: %v\n", suggestion.Assignee, err)
+	}
+	return suggestion, nil
+}
+
+// suggest ranks the owners of an issue and resolves the first that can be
+// resolved to a user. With a state, a round-robin pick advances it.
+func (s *OwnershipService) suggest(ctx context.Context, issue *entities.Issue, state *entities.AssignmentState) (*entities.AssignmentSuggestion, error) {
+	config := s.Config(ctx)
+	candidates := entities.NewOwnerCandidates()
+
+	if config != nil {
+		for _, rule := range config.Rules {
+			if reason, ok := rule.Matches(issue); ok {
+				candidates.Add(rule.Owners, entities.OwnershipEvidence{Source: reason})
+			}
+		}
+	}
+
+	codeOwners, _, err := s.CodeOwners(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if codeOwners != nil {
+		root, _ := s.repositoryRoot(ctx)
+		s.matchPaths(root, codeOwners, candidates, "description", entities.ExtractFilePaths(issue.Description))
+		s.matchAttachments(ctx, issue, root, codeOwners, candidates)
+		s.matchCommits(ctx, issue, codeOwners, candidates)
+	}
+
+	directory := loadUserDirectory(ctx, s.configRepo)
+	suggestion := &entities.AssignmentSuggestion{IssueID: issue.ID, Candidates: candidates.Ranked()}
+	for i := range suggestion.Candidates {
+		candidate := &suggestion.Candidates[i]
+		members, isTeam := config.TeamMembers(candidate.Owner)
+		if len(members) == 0 {
+			// Teams of the user directory, unless the owner is a person
+			if _, isUser := directory.Lookup(candidate.Owner); !isUser {
+				if teamMembers, ok := directory.Team(candidate.Owner); ok {
+					members, isTeam = teamMembers, true
+				}
+			}
+		}
+		candidate.Team = isTeam
+		if suggestion.Assignee != "" {
+			continue
+		}
+
+		if !isTeam {
+			suggestion.Assignee = directory.Canonical(entities.OwnerUsername(candidate.Owner))
+			suggestion.Via = candidate.Owner
+			continue
+		}
+		if len(members) == 0 {
+			continue
+		}
+		assignee, err := s.pickMember(ctx, config.StrategyOf(), candidate.Owner, members, state)
+		if err != nil {
+			return nil, err
+		}
+		suggestion.Assignee = assignee
+		suggestion.Via = candidate.Owner
+		suggestion.Strategy = config.StrategyOf()
+	}
+	return suggestion, nil
+}
+
+// pickMember picks a member of a team with the strategy
+func (s *OwnershipService) pickMember(ctx context.Context, strategy entities.AssignmentStrategy, team string, members []string, state *entities.AssignmentState) (string, error) {
+	directory := loadUserDirectory(ctx, s.configRepo)
+	usernames := make([]string, 0, len(members))
+	for _, member := range members {
+		usernames = append(usernames, directory.Canonical(entities.OwnerUsername(member)))
+	}
+
+	switch strategy {
+	case entities.AssignmentStrategyRoundRobin:
+		if state == nil {
+			var err error
+			if state, err = storage.ReadAssignmentState(s.basePath); err != nil {
+				return "", err
+			}
+			// Suggestions peek at the next turn without taking it
+			state = &entities.AssignmentState{RoundRobin: map[string]int{team: lastTurn(state, team)}}
+		}
+		member, next := entities.PickRoundRobin(usernames, lastTurn(state, team))
+		state.RoundRobin[team] = next
+		return member, nil
+	case entities.AssignmentStrategyLeastLoaded:
+		load, err := s.OpenIssueCounts(ctx)
+		if err != nil {
+			return "", err
+		}
+		return entities.PickLeastLoaded(usernames, load), nil
+	default:
+		return "", errors.New("OwnershipService.pickMember", "strategy",
+			fmt.Errorf("unknown assignment strategy %q (use %s or %s)", strategy, entities.AssignmentStrategyLeastLoaded, entities.AssignmentStrategyRoundRobin))
+	}
+}
+
+// lastTurn returns the index of the member a team picked last, -1 if none
+func lastTurn(state *entities.AssignmentState, team string) int {
+	if last, ok := state.RoundRobin[team]; ok {
+		return last
+	}
+	return -1
+}
+
+// OpenIssueCounts returns the number of open issues assigned to each user
+func (s *OwnershipService) OpenIssueCounts(ctx context.Context) (map[string]int, error) {
+	list, err := s.issueRepo.List(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, errors.Wrap(err, "OwnershipService.OpenIssueCounts", "list_issues")
+	}
+	counts := make(map[string]int)
+	for _, issue := range list.Issues {
+		if issue.Assignee == nil || issue.Status == entities.StatusClosed || issue.Status == entities.StatusDone {
+			continue
+		}
+		counts[issue.Assignee.Username]++
+	}
+	return counts, nil
+}
+
+// matchPaths adds the CODEOWNERS owners of each path as candidates
+func (s *OwnershipService) matchPaths(root string, codeOwners *entities.CodeOwners, candidates *entities.OwnerCandidates, source string, paths []string) {
+	for _, path := range paths {
+		relative, ok := repositoryPath(root, path)
+		if !ok {
+			continue
+		}
+		rule := codeOwners.Match(relative)
+		if rule == nil || len(rule.Owners) == 0 {
+			continue
+		}
+		candidates.Add(rule.Owners, entities.OwnershipEvidence{Source: source, Path: relative, Pattern: rule.Pattern})
+	}
+}
+
+// repositoryPath maps a path mentioned in text to a path relative to the
+// repository root. Absolute paths and paths with a module or build prefix
+// are matched by their longest suffix that exists in the repository. Other
+// paths are kept if they name a file, since they may have been removed.
+func repositoryPath(root, path string) (string, bool) {
+	path = strings.TrimPrefix(path, "./")
+	if root != "" {
+		if rel, err := filepath.Rel(root, filepath.FromSlash(path)); err == nil && filepath.IsAbs(filepath.FromSlash(path)) && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel), true
+		}
+		parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+		for i := range parts {
+			candidate := strings.Join(parts[i:], "/")
+			if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(candidate))); err == nil {
+				return candidate, true
+			}
+		}
+	}
+	if strings.HasPrefix(path, "/") || strings.Contains(path, ":") {
+		return "", false
+	}
+	return path, filepath.Ext(path) != ""
+}
+
+// matchAttachments searches the issue's text attachments for file paths
+func (s *OwnershipService) matchAttachments(ctx context.Context, issue *entities.Issue, root string, codeOwners *entities.CodeOwners, candidates *entities.OwnerCandidates) {
+	if s.attachmentService == nil {
+		return
+	}
+	attachments, err := s.attachmentService.ListIssueAttachments(ctx, issue.ID)
+	if err != nil {
+		return
+	}
+	for _, attachment := range attachments {
+		if entities.PreviewKindFor(attachment.Filename, attachment.ContentType) == entities.PreviewKindNone {
+			continue
+		}
+		content, _, err := s.attachmentService.GetAttachmentContent(ctx, attachment.ID)
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(content, maxOwnershipAttachmentSize))
+		content.Close()
+		if err != nil {
+			continue
+		}
+		s.matchPaths(root, codeOwners, candidates, "attachment "+attachment.Filename, entities.ExtractFilePaths(string(data)))
+	}
+}
+
+// matchCommits adds the owners of the files the issue's commits changed
+func (s *OwnershipService) matchCommits(ctx context.Context, issue *entities.Issue, codeOwners *entities.CodeOwners, candidates *entities.OwnerCandidates) {
+	if s.gitRepo == nil {
+		return
+	}
+	for _, commit := range issue.Commits {
+		files, err := s.gitRepo.GetCommitFiles(ctx, commit.Hash)
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			if strings.HasPrefix(file, ".issuemap/") {
+				continue
+			}
+			rule := codeOwners.Match(file)
+			if rule == nil || len(rule.Owners) == 0 {
+				continue
+			}
+			candidates.Add(rule.Owners, entities.OwnershipEvidence{Source: "commit " + shortHash(commit.Hash), Path: file, Pattern: rule.Pattern})
+		}
+	}
+}
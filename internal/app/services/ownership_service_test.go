@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestOwnershipService(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(dir, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	run("init", "-q", "-b", "main")
+	write(".github/CODEOWNERS", "* @acme/core\n/internal/parser/ @alice\nweb/ @acme/web\n")
+	write("internal/parser/lexer.go", "package parser\n")
+	write("web/app.js", "app()\n")
+	run("add", "-A")
+	run("commit", "-q", "-m", "Initial commit")
+	write("web/app.js", "app(1)\n")
+	run("commit", "-q", "-am", "Fix app")
+	webCommit := run("rev-parse", "HEAD")
+
+	basePath := filepath.Join(dir, ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+	config := entities.NewDefaultConfig()
+	config.Owners = &entities.OwnersConfig{
+		Strategy: entities.AssignmentStrategyRoundRobin,
+		Teams:    map[string][]string{"@acme/web": {"dave", "@erin"}},
+		Rules:    []entities.OwnerRule{{Labels: []string{"docs"}, Owners: []string{"carol"}}},
+	}
+	require.NoError(t, configRepo.Save(ctx, config))
+
+	gitClient, err := git.NewGitClient(dir)
+	require.NoError(t, err)
+	ownershipService := NewOwnershipService(issueRepo, configRepo, gitClient, basePath)
+
+	crash := entities.NewIssue("TEST-001", "Crash", "panic: at /home/ci/build/repo/internal/parser/lexer.go:42 +0x1f", entities.IssueTypeBug)
+	web := entities.NewIssue("TEST-002", "Button", "", entities.IssueTypeBug)
+	web.Commits = []entities.CommitRef{{Hash: webCommit}}
+	docs := entities.NewIssue("TEST-003", "Typo", "", entities.IssueTypeBug)
+	docs.AddLabel(entities.Label{Name: "docs"})
+	web2 := entities.NewIssue("TEST-004", "Layout", "Broken in web/app.js", entities.IssueTypeBug)
+	for _, issue := range []*entities.Issue{crash, web, docs, web2} {
+		require.NoError(t, issueRepo.Create(ctx, issue))
+	}
+
+	// Paths in stack traces are matched by their suffix in the repository
+	suggestion, err := ownershipService.Suggest(ctx, crash)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", suggestion.Assignee)
+	require.Len(t, suggestion.Candidates, 1)
+	assert.Equal(t, []entities.OwnershipEvidence{{Source: "description", Path: "internal/parser/lexer.go", Pattern: "/internal/parser/"}}, suggestion.Candidates[0].Evidence)
+
+	suggestion, err = ownershipService.Suggest(ctx, docs)
+	require.NoError(t, err)
+	assert.Equal(t, "carol", suggestion.Assignee)
+
+	// Suggestions peek at the next round-robin turn without taking it
+	for i := 0; i < 2; i++ {
+		suggestion, err = ownershipService.Suggest(ctx, web)
+		require.NoError(t, err)
+		assert.Equal(t, "dave", suggestion.Assignee)
+		assert.Equal(t, "@acme/web", suggestion.Via)
+		assert.True(t, suggestion.Candidates[0].Team)
+	}
+
+	suggestion, err = ownershipService.Assign(ctx, web.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, "dave", suggestion.Assignee)
+	suggestion, err = ownershipService.Assign(ctx, web2.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, "erin", suggestion.Assignee)
+	suggestion, err = ownershipService.Assign(ctx, web2.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, "erin", suggestion.Assignee)
+
+	stored, err := issueRepo.GetByID(ctx, web2.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.Assignee)
+	assert.Equal(t, "erin", stored.Assignee.Username)
+
+	state, err := storage.ReadAssignmentState(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, 1, state.RoundRobin["@acme/web"])
+
+	// Least loaded picks the member with the fewest open issues
+	config.Owners.Strategy = entities.AssignmentStrategyLeastLoaded
+	config.Owners.Teams["@acme/web"] = []string{"dave", "erin", "frank"}
+	require.NoError(t, configRepo.Save(ctx, config))
+	suggestion, err = ownershipService.Suggest(ctx, web)
+	require.NoError(t, err)
+	assert.Equal(t, "frank", suggestion.Assignee)
+	assert.Equal(t, entities.AssignmentStrategyLeastLoaded, suggestion.Strategy)
+
+	// Teams without members do not resolve to an assignee
+	other := entities.NewIssue("TEST-005", "Build", "Fails in main.go", entities.IssueTypeBug)
+	suggestion, err = ownershipService.Suggest(ctx, other)
+	require.NoError(t, err)
+	require.Len(t, suggestion.Candidates, 1)
+	assert.Equal(t, "@acme/core", suggestion.Candidates[0].Owner)
+	assert.Empty(t, suggestion.Assignee)
+}
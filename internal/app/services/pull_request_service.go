@@ -0,0 +1,239 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// PullRequestProvider opens pull requests with a code hosting service
+type PullRequestProvider interface {
+	// Open creates the pull request and returns its URL when the provider
+	// reports one
+	Open(ctx context.Context, description *entities.PullRequestDescription, ref *entities.PullRequestRef) (string, error)
+}
+
+// CommandPullRequestProvider opens pull requests by running a provider CLI,
+// such as gh, with the description body on stdin
+type CommandPullRequestProvider struct {
+	config *entities.PullRequestConfig
+}
+
+// NewCommandPullRequestProvider creates a command provider from configuration
+func NewCommandPullRequestProvider(config *entities.PullRequestConfig) *CommandPullRequestProvider {
+	return &CommandPullRequestProvider{config: config}
+}
+
+// pullRequestURL matches the URL provider CLIs print for a new pull request
+var pullRequestURL = regexp.MustCompile(`https?://\S+`)
+
+// Open runs the configured command and returns the last URL it printed
+func (p *CommandPullRequestProvider) Open(ctx context.Context, description *entities.PullRequestDescription, ref *entities.PullRequestRef) (string, error) {
+	cmd := exec.CommandContext(ctx, p.config.Command, p.config.ExpandArgs(description, ref)...)
+	cmd.Stdin = strings.NewReader(description.Body)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		detail := strings.TrimSpace(output.String())
+		if detail == "" {
+			detail = err.Error()
+		}
+		return "", fmt.Errorf("%s failed: %s", p.config.Command, detail)
+	}
+
+	urls := pullRequestURL.FindAllString(output.String(), -1)
+	if len(urls) == 0 {
+		return "", nil
+	}
+	return urls[len(urls)-1], nil
+}
+
+// PullRequestService supports a pull request based workflow: it renders
+// pull request descriptions, records opened pull requests on their issues
+// and closes issues once their changes land on the base branch
+type PullRequestService struct {
+	issueService      *IssueService
+	dependencyService *DependencyService
+	gitRepo           repositories.GitRepository
+}
+
+// NewPullRequestService creates a pull request service. dependencyService
+// may be nil, in which case descriptions list no dependencies.
+func NewPullRequestService(issueService *IssueService, dependencyService *DependencyService, gitRepo repositories.GitRepository) *PullRequestService {
+	return &PullRequestService{
+		issueService:      issueService,
+		dependencyService: dependencyService,
+		gitRepo:           gitRepo,
+	}
+}
+
+// Describe renders the pull request title and body of an issue
+func (s *PullRequestService) Describe(ctx context.Context, issue *entities.Issue) (*entities.PullRequestDescription, error) {
+	var dependencies []entities.PullRequestDependency
+	if s.dependencyService != nil {
+		info, err := s.dependencyService.GetBlockingInfo(ctx, issue.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "PullRequestService.Describe", "dependencies")
+		}
+		for _, id := range info.BlockedBy {
+			dependencies = append(dependencies, s.describeDependency(ctx, "Blocked by", id))
+		}
+		for _, id := range info.Blocking {
+			dependencies = append(dependencies, s.describeDependency(ctx, "Blocks", id))
+		}
+	}
+	return entities.RenderPullRequest(issue, dependencies), nil
+}
+
+// describeDependency looks up the title and status of a related issue
+func (s *PullRequestService) describeDependency(ctx context.Context, relation string, id entities.IssueID) entities.PullRequestDependency {
+	dependency := entities.PullRequestDependency{Relation: relation, ID: id}
+	if issue, err := s.issueService.GetIssue(ctx, id); err == nil {
+		dependency.Title = issue.Title
+		dependency.Status = issue.Status
+	}
+	return dependency
+}
+
+// NewPullRequestRef checks that branch has commits that are not on base and
+// returns the reference to record for its pull request
+func (s *PullRequestService) NewPullRequestRef(ctx context.Context, remote, branch, base string) (*entities.PullRequestRef, error) {
+	commits, err := s.gitRepo.GetBranchCommits(ctx, branch, base)
+	if err != nil {
+		return nil, errors.Wrap(err, "PullRequestService.NewPullRequestRef", "branch_commits")
+	}
+	if len(commits) == 0 {
+		return nil, errors.New("PullRequestService.NewPullRequestRef", "no_commits",
+			fmt.Errorf("branch %s has no commits that are not on %s", branch, base))
+	}
+
+	head, err := s.gitRepo.ResolveCommit(ctx, branch)
+	if err != nil {
+		return nil, errors.Wrap(err, "PullRequestService.NewPullRequestRef", "resolve_head")
+	}
+
+	return &entities.PullRequestRef{
+		Remote:   remote,
+		Branch:   branch,
+		Base:     base,
+		Head:     head,
+		OpenedAt: time.Now(),
+	}, nil
+}
+
+// MarkOpened records the pull request on the issue and moves it to review
+func (s *PullRequestService) MarkOpened(ctx context.Context, issueID entities.IssueID, ref *entities.PullRequestRef) (*entities.Issue, error) {
+	return s.issueService.UpdateIssue(ctx, issueID, map[string]interface{}{
+		"status":       string(entities.StatusReview),
+		"pull_request": ref,
+	})
+}
+
+// SetURL records the URL the provider reported for the issue's pull request
+func (s *PullRequestService) SetURL(ctx context.Context, issueID entities.IssueID, url string) (*entities.Issue, error) {
+	issue, err := s.issueService.GetIssue(ctx, issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.PullRequest == nil {
+		return nil, errors.New("PullRequestService.SetURL", "no_pull_request",
+			fmt.Errorf("issue %s has no pull request", issueID))
+	}
+	ref := *issue.PullRequest
+	ref.URL = url
+	return s.issueService.UpdateIssue(ctx, issueID, map[string]interface{}{"pull_request": &ref})
+}
+
+// OpenPullRequests returns the issues with a pull request that has not
+// landed yet
+func (s *PullRequestService) OpenPullRequests(ctx context.Context) ([]*entities.Issue, error) {
+	list, err := s.issueService.ListIssues(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var open []*entities.Issue
+	for i := range list.Issues {
+		issue := &list.Issues[i]
+		if issue.PullRequest != nil && !issue.PullRequest.Landed() && issue.Status != entities.StatusClosed {
+			open = append(open, issue)
+		}
+	}
+	return open, nil
+}
+
+// BaseRef returns the ref the pull request's base is compared at: the
+// remote-tracking branch when it exists, the local branch otherwise
+func (s *PullRequestService) BaseRef(ctx context.Context, ref *entities.PullRequestRef) string {
+	if ref.Remote != "" {
+		remoteBase := ref.Remote + "/" + ref.Base
+		if _, err := s.gitRepo.ResolveCommit(ctx, remoteBase); err == nil {
+			return remoteBase
+		}
+	}
+	return ref.Base
+}
+
+// headRef returns the newest known tip of the pull request's branch: the
+// remote-tracking branch, the local branch, or the commit recorded when it
+// was opened once the branch has been deleted
+func (s *PullRequestService) headRef(ctx context.Context, ref *entities.PullRequestRef) string {
+	candidates := []string{ref.Branch}
+	if ref.Remote != "" {
+		candidates = append([]string{ref.Remote + "/" + ref.Branch}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if _, err := s.gitRepo.ResolveCommit(ctx, candidate); err == nil {
+			return candidate
+		}
+	}
+	return ref.Head
+}
+
+// DetectLanding reports whether the issue's pull request has landed on its
+// base, including squash and rebase merges, and nil if it has not
+func (s *PullRequestService) DetectLanding(ctx context.Context, issue *entities.Issue) (*repositories.BranchLanding, error) {
+	ref := issue.PullRequest
+	if ref == nil {
+		return nil, errors.New("PullRequestService.DetectLanding", "no_pull_request",
+			fmt.Errorf("issue %s has no pull request", issue.ID))
+	}
+	return s.gitRepo.FindLanding(ctx, s.headRef(ctx, ref), s.BaseRef(ctx, ref))
+}
+
+// MarkLanded records how the pull request landed and closes the issue
+func (s *PullRequestService) MarkLanded(ctx context.Context, issue *entities.Issue, landing *repositories.BranchLanding) error {
+	ref := *issue.PullRequest
+	now := time.Now()
+	ref.LandedAt = &now
+	ref.LandedAs = landing.Method
+	ref.LandedCommits = landing.Commits
+
+	if _, err := s.issueService.UpdateIssue(ctx, issue.ID, map[string]interface{}{"pull_request": &ref}); err != nil {
+		return err
+	}
+
+	reason := fmt.Sprintf("Pull request for %s landed on %s (%s)", ref.Branch, ref.Base, landing.Method)
+	if len(landing.Commits) > 0 {
+		reason = fmt.Sprintf("Pull request for %s landed on %s (%s, %s)", ref.Branch, ref.Base, landing.Method, shortHash(landing.Commits[len(landing.Commits)-1]))
+	}
+	return s.issueService.CloseIssue(ctx, issue.ID, reason)
+}
+
+// shortHash abbreviates a commit hash for messages
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
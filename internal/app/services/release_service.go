@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// ReleaseService builds release notes from the issues closed between two
+// git refs and keeps the changelog and milestones in step with releases
+type ReleaseService struct {
+	issueRepo  repositories.IssueRepository
+	configRepo repositories.ConfigRepository
+	gitRepo    repositories.GitRepository
+	basePath   string
+}
+
+// NewReleaseService creates a release service for the .issuemap directory
+// at basePath
+func NewReleaseService(
+	issueRepo repositories.IssueRepository,
+	configRepo repositories.ConfigRepository,
+	gitRepo repositories.GitRepository,
+	basePath string,
+) *ReleaseService {
+	return &ReleaseService{
+		issueRepo:  issueRepo,
+		configRepo: configRepo,
+		gitRepo:    gitRepo,
+		basePath:   basePath,
+	}
+}
+
+// config returns the release configuration, which may be nil
+func (s *ReleaseService) config(ctx context.Context) *entities.ReleaseConfig {
+	config, err := s.configRepo.Load(ctx)
+	if err != nil || config == nil {
+		return nil
+	}
+	return config.Release
+}
+
+// Notes collects the issues closed between from and to and groups them
+// into the configured sections. An empty from starts at the first commit.
+func (s *ReleaseService) Notes(ctx context.Context, version, from, to string) (*entities.ReleaseNotes, error) {
+	issues, date, err := s.ClosedBetween(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sections := entities.ReleaseSectionsOf(s.config(ctx))
+	return entities.NewReleaseNotes(version, from, to, date, sections, issues), nil
+}
+
+// ClosedBetween returns the closed issues whose changes landed between the
+// refs from and to, and the date of the release at to. An issue with known
+// commits is included when one of them is in the range, either linked on
+// the issue or referencing it in its message. Issues no commit knows of are
+// included when they were closed after from and no later than to.
+func (s *ReleaseService) ClosedBetween(ctx context.Context, from, to string) ([]*entities.Issue, time.Time, error) {
+	if _, err := s.gitRepo.ResolveCommit(ctx, to); err != nil {
+		return nil, time.Time{}, errors.New("ReleaseService.ClosedBetween", "unknown_ref",
+			fmt.Errorf("unknown git ref %q", to))
+	}
+	if from != "" {
+		if _, err := s.gitRepo.ResolveCommit(ctx, from); err != nil {
+			return nil, time.Time{}, errors.New("ReleaseService.ClosedBetween", "unknown_ref",
+				fmt.Errorf("unknown git ref %q", from))
+		}
+	}
+
+	commits, err := s.gitRepo.GetBranchCommits(ctx, to, from)
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "ReleaseService.ClosedBetween", "commits")
+	}
+	inRange := make(map[string]bool, len(commits))
+	referenced := make(map[entities.IssueID]bool)
+	for _, commit := range commits {
+		inRange[commit.Hash] = true
+		for _, ref := range commit.IssueRefs {
+			referenced[entities.IssueID(ref)] = true
+		}
+	}
+
+	// Issues referenced by earlier commits shipped in an earlier release,
+	// whenever they were closed
+	released := make(map[entities.IssueID]bool)
+	if from != "" {
+		earlier, err := s.gitRepo.GetBranchCommits(ctx, from, "")
+		if err != nil {
+			return nil, time.Time{}, errors.Wrap(err, "ReleaseService.ClosedBetween", "commits")
+		}
+		for _, commit := range earlier {
+			for _, ref := range commit.IssueRefs {
+				released[entities.IssueID(ref)] = true
+			}
+		}
+	}
+
+	// A tag dates the release; anything else is being released now
+	releaseDate := time.Now()
+	if s.gitRepo.IsTag(ctx, to) {
+		if releaseDate, err = s.gitRepo.CommitTime(ctx, to); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	var since time.Time
+	if from != "" {
+		if since, err = s.gitRepo.CommitTime(ctx, from); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+
+	list, err := s.issueRepo.List(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, time.Time{}, errors.Wrap(err, "ReleaseService.ClosedBetween", "list_issues")
+	}
+
+	var issues []*entities.Issue
+	for i := range list.Issues {
+		issue := &list.Issues[i]
+		if issue.Status != entities.StatusClosed && issue.Status != entities.StatusDone {
+			continue
+		}
+
+		if len(issue.Commits) > 0 || referenced[issue.ID] || released[issue.ID] {
+			if referenced[issue.ID] || hasCommitIn(issue.Commits, inRange) {
+				issues = append(issues, issue)
+			}
+			continue
+		}
+
+		// Git dates have a resolution of one second, so an issue closed
+		// just before the release commit may look a fraction later
+		closed := issue.Timestamps.Closed
+		if closed != nil && closed.After(since) && !closed.Truncate(time.Second).After(releaseDate) {
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].ID < issues[j].ID
+	})
+	return issues, releaseDate, nil
+}
+
+// hasCommitIn reports whether one of the commits is in the set of full
+// hashes. Linked commits may be recorded abbreviated.
+func hasCommitIn(commits []entities.CommitRef, hashes map[string]bool) bool {
+	for _, commit := range commits {
+		if commit.Hash == "" {
+			continue
+		}
+		if hashes[commit.Hash] {
+			return true
+		}
+		for hash := range hashes {
+			if strings.HasPrefix(hash, commit.Hash) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TemplatePath returns the notes template path inside .issuemap
+func (s *ReleaseService) TemplatePath(ctx context.Context) string {
+	path := entities.DefaultReleaseTemplate
+	if config := s.config(ctx); config != nil && config.Template != "" {
+		path = config.Template
+	}
+	return filepath.Join(s.basePath, filepath.FromSlash(path))
+}
+
+// Template returns the text of the template at path, the configured
+// template when path is empty, or the built-in template when no
+// configured template has been written
+func (s *ReleaseService) Template(ctx context.Context, path string) (string, error) {
+	explicit := path != ""
+	if !explicit {
+		path = s.TemplatePath(ctx)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return entities.DefaultReleaseNotesTemplate, nil
+		}
+		return "", errors.Wrap(err, "ReleaseService.Template", "read")
+	}
+	return string(data), nil
+}
+
+// WriteTemplate writes the built-in template to the configured path so it
+// can be edited, refusing to replace an existing template unless force is set
+func (s *ReleaseService) WriteTemplate(ctx context.Context, force bool) (string, error) {
+	path := s.TemplatePath(ctx)
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", errors.New("ReleaseService.WriteTemplate", "exists",
+			fmt.Errorf("%s already exists; use --force to replace it", path))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", errors.Wrap(err, "ReleaseService.WriteTemplate", "mkdir")
+	}
+	if err := os.WriteFile(path, []byte(entities.DefaultReleaseNotesTemplate), 0644); err != nil {
+		return "", errors.Wrap(err, "ReleaseService.WriteTemplate", "write")
+	}
+	return path, nil
+}
+
+// ChangelogPath returns the changelog path, relative paths resolved against
+// the repository root
+func (s *ReleaseService) ChangelogPath(ctx context.Context, path string) (string, error) {
+	if path == "" {
+		path = entities.DefaultChangelog
+		if config := s.config(ctx); config != nil && config.Changelog != "" {
+			path = config.Changelog
+		}
+	}
+	if filepath.IsAbs(path) {
+		return path, nil
+	}
+	root, err := s.gitRepo.GetRepositoryRoot(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "ReleaseService.ChangelogPath", "repository_root")
+	}
+	return filepath.Join(root, path), nil
+}
+
+// UpdateChangelog writes the rendered notes of version into the changelog
+// at path, creating it if needed
+func (s *ReleaseService) UpdateChangelog(path, version, section string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "ReleaseService.UpdateChangelog", "read")
+	}
+	updated := entities.UpdateChangelog(string(existing), version, section)
+	if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+		return errors.Wrap(err, "ReleaseService.UpdateChangelog", "write")
+	}
+	return nil
+}
+
+// ReleaseMilestone marks a milestone released at the given time, adding it
+// to the configuration if it is not listed yet
+func (s *ReleaseService) ReleaseMilestone(ctx context.Context, name string, at time.Time) error {
+	config, err := s.configRepo.Load(ctx)
+	if err != nil {
+		return errors.Wrap(err, "ReleaseService.ReleaseMilestone", "load_config")
+	}
+
+	found := false
+	for i := range config.Milestones {
+		if config.Milestones[i].Name == name {
+			config.Milestones[i].ReleasedAt = &at
+			found = true
+			break
+		}
+	}
+	if !found {
+		config.Milestones = append(config.Milestones, entities.Milestone{Name: name, ReleasedAt: &at})
+	}
+
+	if err := s.configRepo.Save(ctx, config); err != nil {
+		return errors.Wrap(err, "ReleaseService.ReleaseMilestone", "save_config")
+	}
+	return nil
+}
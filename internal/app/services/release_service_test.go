@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/git"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestReleaseService_Notes(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	commit := func(file, message string) string {
+		t.Helper()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(message+"\n"), 0644))
+		run("add", file)
+		run("commit", "-q", "-m", message)
+		return run("rev-parse", "HEAD")
+	}
+
+	run("init", "-q", "-b", "main")
+	oldFix := commit("a.txt", "TEST-001: old fix")
+	run("tag", "v1.0.0")
+	linked := commit("b.txt", "Add export")
+	commit("c.txt", "Fixes TEST-003")
+	run("tag", "v1.1.0")
+
+	basePath := filepath.Join(dir, ".issuemap")
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+	require.NoError(t, configRepo.Initialize(ctx, entities.NewDefaultConfig()))
+	gitClient, err := git.NewGitClient(dir)
+	require.NoError(t, err)
+
+	closedIssue := func(id, title string, issueType entities.IssueType, commits ...string) *entities.Issue {
+		issue := entities.NewIssue(entities.IssueID(id), title, "", issueType)
+		issue.UpdateStatus(entities.StatusClosed)
+		for _, hash := range commits {
+			issue.Commits = append(issue.Commits, entities.CommitRef{Hash: hash})
+		}
+		require.NoError(t, issueRepo.Create(ctx, issue))
+		return issue
+	}
+	closedIssue("TEST-001", "Old fix", entities.IssueTypeBug, oldFix)
+	closedIssue("TEST-002", "Export", entities.IssueTypeFeature, linked[:8])
+	closedIssue("TEST-003", "Crash", entities.IssueTypeBug)
+	open := entities.NewIssue("TEST-004", "Still open", "", entities.IssueTypeBug)
+	open.Commits = []entities.CommitRef{{Hash: linked}}
+	require.NoError(t, issueRepo.Create(ctx, open))
+
+	releaseService := NewReleaseService(issueRepo, configRepo, gitClient, basePath)
+	notes, err := releaseService.Notes(ctx, "1.1.0", "v1.0.0", "v1.1.0")
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, notes.Count)
+	require.Len(t, notes.Sections, 2)
+	assert.Equal(t, "Added", notes.Sections[0].Title)
+	assert.Equal(t, entities.IssueID("TEST-002"), notes.Sections[0].Issues[0].ID)
+	assert.Equal(t, "Fixed", notes.Sections[1].Title)
+	assert.Equal(t, entities.IssueID("TEST-003"), notes.Sections[1].Issues[0].ID)
+
+	// Issues without commits fall back to their close time; nothing was
+	// closed after the v1.1.0 commit
+	later := closedIssue("TEST-005", "Docs", entities.IssueTypeTask)
+	notes, err = releaseService.Notes(ctx, entities.UnreleasedVersion, "v1.1.0", "HEAD")
+	require.NoError(t, err)
+	require.Equal(t, 1, notes.Count)
+	assert.Equal(t, later.ID, notes.Sections[0].Issues[0].ID)
+
+	_, err = releaseService.Notes(ctx, "1.1.0", "v0.9.0", "v1.1.0")
+	assert.Error(t, err)
+
+	// Releasing a milestone adds it to the configuration
+	releasedAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, releaseService.ReleaseMilestone(ctx, "v1.1", releasedAt))
+	config, err := configRepo.Load(ctx)
+	require.NoError(t, err)
+	require.Len(t, config.Milestones, 1)
+	require.NotNil(t, config.Milestones[0].ReleasedAt)
+	assert.True(t, config.Milestones[0].ReleasedAt.Equal(releasedAt))
+
+	// The changelog is created at the repository root
+	path, err := releaseService.ChangelogPath(ctx, "")
+	require.NoError(t, err)
+	require.NoError(t, releaseService.UpdateChangelog(path, "1.1.0", "## [1.1.0] - 2026-03-01\n"))
+	data, err := os.ReadFile(filepath.Join(dir, entities.DefaultChangelog))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "## [Unreleased]\n\n## [1.1.0] - 2026-03-01\n")
+}
@@ -2,45 +2,153 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"sort"
 	"strconv"
-	"strings"
 	"sync"
 	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
 )
 
-// SchedulerService handles scheduled tasks like cleanup and archiving
+// defaultJobTimeout bounds a single job run when the job does not set one
+const defaultJobTimeout = 30 * time.Minute
+
+// JobFunc runs a scheduled job and returns a short summary of what it did
+type JobFunc func(ctx context.Context) (string, error)
+
+// Job is a named unit of scheduled work. Services describe their jobs and
+// the scheduler runs them on their cron schedule.
+type Job struct {
+	Name        string
+	Description string
+
+	// Schedule is the default cron expression; jobs.schedules in the project
+	// config overrides it
+	Schedule string
+
+	// Timeout bounds a single run; zero uses defaultJobTimeout
+	Timeout time.Duration
+
+	// Enabled reports whether the feature behind the job is switched on.
+	// A nil Enabled means the job is always enabled.
+	Enabled func() bool
+
+	Run JobFunc
+}
+
+// registeredJob is a job with its parsed schedule and current state
+type registeredJob struct {
+	job      *Job
+	schedule *entities.CronSchedule
+	state    *entities.JobState
+}
+
+// SchedulerService runs registered jobs on cron schedules, records each run
+// and catches up on runs missed while the server was down
 type SchedulerService struct {
-	cleanupService *CleanupService
-	storageService *StorageService
-	archiveService *ArchiveService
-	ctx            context.Context
-	cancel         context.CancelFunc
-	wg             sync.WaitGroup
-	running        bool
-	mu             sync.RWMutex
+	jobRepo    repositories.JobRepository
+	configRepo repositories.ConfigRepository
+	config     *entities.JobsConfig
+	jobs       map[string]*registeredJob
+	running    map[string]bool
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	started    bool
+	mu         sync.RWMutex
 }
 
 // NewSchedulerService creates a new scheduler service
-func NewSchedulerService(cleanupService *CleanupService, storageService *StorageService, archiveService *ArchiveService) *SchedulerService {
+func NewSchedulerService(jobRepo repositories.JobRepository, configRepo repositories.ConfigRepository) *SchedulerService {
+	config := entities.DefaultJobsConfig()
+
+	if configRepo != nil {
+		if cfg, err := configRepo.Load(context.Background()); err == nil && cfg != nil && cfg.Jobs != nil {
+			config = cfg.Jobs
+		}
+	}
+	if config.HistoryLimit <= 0 {
+		config.HistoryLimit = entities.DefaultJobHistoryLimit
+	}
+
 	return &SchedulerService{
-		cleanupService: cleanupService,
-		storageService: storageService,
-		archiveService: archiveService,
+		jobRepo:    jobRepo,
+		configRepo: configRepo,
+		config:     config,
+		jobs:       make(map[string]*registeredJob),
+		running:    make(map[string]bool),
+	}
+}
+
+// RegisterJob adds a job to the registry
+func (s *SchedulerService) RegisterJob(job *Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("SchedulerService.RegisterJob", "invalid_job", fmt.Errorf("job must have a name and a run function"))
+	}
+
+	expression := job.Schedule
+	if override, ok := s.config.Schedules[job.Name]; ok && override != "" {
+		expression = override
+	}
+
+	schedule, err := entities.ParseCronSchedule(expression)
+	if err != nil {
+		return errors.Wrap(err, "SchedulerService.RegisterJob", "invalid_schedule")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return errors.New("SchedulerService.RegisterJob", "duplicate_job", fmt.Errorf("job %s is already registered", job.Name))
+	}
+
+	s.jobs[job.Name] = &registeredJob{
+		job:      job,
+		schedule: schedule,
+		state:    &entities.JobState{Name: job.Name},
+	}
+	return nil
+}
+
+// RegisterJobs registers several jobs, logging any that are rejected
+func (s *SchedulerService) RegisterJobs(jobs ...*Job) {
+	for _, job := range jobs {
+		if err := s.RegisterJob(job); err != nil {
+			log.Printf("Failed to register job %s: %v", job.Name, err)
+		}
 	}
 }
 
-// Start begins the scheduler
+// Start loads job state, runs any jobs that missed a scheduled run while the
+// server was down and begins the scheduler loop
 func (s *SchedulerService) Start() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if s.running {
+	if s.started {
 		return nil
 	}
 
+	s.loadStates()
 	s.ctx, s.cancel = context.WithCancel(context.Background())
-	s.running = true
+	s.started = true
+
+	now := time.Now()
+	for _, rj := range s.jobs {
+		if s.config.CatchUp && s.isEnabled(rj) && rj.state.LastRun != nil {
+			if missed := rj.schedule.Next(*rj.state.LastRun); !missed.IsZero() && missed.Before(now) {
+				log.Printf("Job %s missed its run at %s; catching up", rj.job.Name, missed.Format(time.RFC3339))
+				s.startRun(rj, entities.JobTriggerCatchUp)
+			}
+		}
+		next := rj.schedule.Next(now)
+		rj.state.NextRun = &next
+	}
 
 	s.wg.Add(1)
 	go s.schedulerLoop()
@@ -52,16 +160,15 @@ func (s *SchedulerService) Start() error {
 // Stop gracefully stops the scheduler
 func (s *SchedulerService) Stop() {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	if !s.running {
+	if !s.started {
+		s.mu.Unlock()
 		return
 	}
-
 	s.cancel()
-	s.running = false
+	s.started = false
+	s.mu.Unlock()
 
-	// Wait for scheduler to finish with timeout
+	// Wait for the loop and running jobs to finish with timeout
 	done := make(chan struct{})
 	go func() {
 		s.wg.Wait()
@@ -80,170 +187,212 @@ func (s *SchedulerService) Stop() {
 func (s *SchedulerService) IsRunning() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return s.running
+	return s.started
 }
 
-// schedulerLoop is the main loop that checks for scheduled tasks
-func (s *SchedulerService) schedulerLoop() {
-	defer s.wg.Done()
+// ListJobs returns every registered job with its state, sorted by name
+func (s *SchedulerService) ListJobs(ctx context.Context) []*entities.JobInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	ticker := time.NewTicker(1 * time.Minute) // Check every minute
-	defer ticker.Stop()
+	if !s.started {
+		s.loadStates()
+	}
 
-	for {
-		select {
-		case <-s.ctx.Done():
-			return
-		case <-ticker.C:
-			s.checkScheduledTasks()
+	now := time.Now()
+	jobs := make([]*entities.JobInfo, 0, len(s.jobs))
+	for _, rj := range s.jobs {
+		state := *rj.state
+		enabled := s.isEnabled(rj)
+		state.NextRun = nil
+		if enabled {
+			next := rj.schedule.Next(now)
+			if rj.state.NextRun != nil && s.started {
+				next = *rj.state.NextRun
+			}
+			state.NextRun = &next
 		}
+
+		jobs = append(jobs, &entities.JobInfo{
+			Name:        rj.job.Name,
+			Description: rj.job.Description,
+			Schedule:    rj.schedule.String(),
+			Enabled:     enabled,
+			Running:     s.running[rj.job.Name],
+			State:       &state,
+		})
 	}
-}
 
-// checkScheduledTasks checks if any scheduled tasks need to run
-func (s *SchedulerService) checkScheduledTasks() {
-	config := s.cleanupService.GetConfig()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
 
-	// Check if automatic cleanup is enabled
-	if !config.Enabled {
-		return
+// RunJob runs a job immediately and waits for it to finish. Manual runs are
+// allowed even when the job is disabled.
+func (s *SchedulerService) RunJob(ctx context.Context, name string) (*entities.JobRun, error) {
+	s.mu.Lock()
+	rj, ok := s.jobs[name]
+	if !ok {
+		s.mu.Unlock()
+		return nil, errors.Wrap(fmt.Errorf("job %s %w", name, errors.ErrNotFound), "SchedulerService.RunJob", "job_not_found")
 	}
+	if s.running[name] {
+		s.mu.Unlock()
+		return nil, errors.New("SchedulerService.RunJob", "job_running", fmt.Errorf("job %s is already running", name))
+	}
+	if !s.started {
+		s.loadStates()
+	}
+	s.running[name] = true
+	s.mu.Unlock()
 
-	// Check if it's time to run cleanup based on schedule
-	if s.shouldRunCleanup(config.Schedule) {
-		log.Println("Running scheduled cleanup...")
-
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-		defer cancel()
+	return s.execute(ctx, rj, entities.JobTriggerManual), nil
+}
 
-		result, err := s.cleanupService.RunCleanup(ctx, false)
-		if err != nil {
-			log.Printf("Scheduled cleanup failed: %v", err)
-		} else {
-			log.Printf("Scheduled cleanup completed: %d items cleaned, %s reclaimed",
-				result.ItemsCleaned.Total, formatBytes(result.SpaceReclaimed))
+// History returns the most recent runs first, optionally for a single job
+func (s *SchedulerService) History(ctx context.Context, name string, limit int) ([]*entities.JobRun, error) {
+	if name != "" {
+		s.mu.RLock()
+		_, ok := s.jobs[name]
+		s.mu.RUnlock()
+		if !ok {
+			return nil, errors.Wrap(fmt.Errorf("job %s %w", name, errors.ErrNotFound), "SchedulerService.History", "job_not_found")
 		}
 	}
 
-	// Check size-based triggers
-	s.checkSizeTriggers()
-
-	// Check if automatic archiving should run
-	s.checkArchiving()
-}
+	if s.jobRepo == nil {
+		return nil, nil
+	}
 
-// shouldRunCleanup checks if cleanup should run based on schedule
-func (s *SchedulerService) shouldRunCleanup(schedule string) bool {
-	if schedule == "" {
-		return false
+	runs, err := s.jobRepo.ListRuns(ctx, name, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "SchedulerService.History", "list_runs")
 	}
+	return runs, nil
+}
 
-	now := time.Now()
-	lastCleanup := s.cleanupService.GetLastCleanup()
+// schedulerLoop is the main loop that checks for due jobs
+func (s *SchedulerService) schedulerLoop() {
+	defer s.wg.Done()
 
-	// Simple schedule parsing (cron-like)
-	// Format: "0 2 * * *" = daily at 2 AM
-	// Format: "0 */6 * * *" = every 6 hours
-	// Format: "*/30 * * * *" = every 30 minutes
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
 
-	parts := strings.Fields(schedule)
-	if len(parts) != 5 {
-		log.Printf("Invalid schedule format: %s", schedule)
-		return false
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.runDueJobs(now)
+		}
 	}
+}
+
+// runDueJobs starts every enabled job whose next run time has passed
+func (s *SchedulerService) runDueJobs(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	minute, hour, day, month, weekday := parts[0], parts[1], parts[2], parts[3], parts[4]
+	for _, rj := range s.jobs {
+		if rj.state.NextRun == nil || now.Before(*rj.state.NextRun) {
+			continue
+		}
 
-	// Check if we've already run cleanup recently enough
-	if s.hasRunRecently(schedule, lastCleanup, now) {
-		return false
+		next := rj.schedule.Next(now)
+		rj.state.NextRun = &next
+
+		if !s.isEnabled(rj) || s.running[rj.job.Name] {
+			continue
+		}
+		s.startRun(rj, entities.JobTriggerSchedule)
 	}
+}
 
-	// Simple matching for common patterns
-	return s.matchesSchedule(now, minute, hour, day, month, weekday)
+// startRun runs a job in the background. The caller must hold s.mu.
+func (s *SchedulerService) startRun(rj *registeredJob, trigger entities.JobTrigger) {
+	s.running[rj.job.Name] = true
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.execute(s.ctx, rj, trigger)
+	}()
 }
 
-// hasRunRecently checks if cleanup has run recently enough for the given schedule
-func (s *SchedulerService) hasRunRecently(schedule string, lastRun, now time.Time) bool {
-	if lastRun.IsZero() {
-		return false
+// execute runs a job that has been marked running and records the result
+func (s *SchedulerService) execute(ctx context.Context, rj *registeredJob, trigger entities.JobTrigger) *entities.JobRun {
+	timeout := rj.job.Timeout
+	if timeout == 0 {
+		timeout = defaultJobTimeout
 	}
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-	// Determine minimum interval based on schedule
-	var minInterval time.Duration
+	run := entities.NewJobRun(rj.job.Name, trigger)
+	message, err := s.safeRun(runCtx, rj.job)
+	run.Finish(message, err)
 
-	if strings.Contains(schedule, "*/") {
-		// Handle interval patterns like */30 * * * * (every 30 minutes)
-		if strings.HasPrefix(schedule, "*/") {
-			if minutes, err := strconv.Atoi(strings.TrimPrefix(strings.Fields(schedule)[0], "*/")); err == nil {
-				minInterval = time.Duration(minutes) * time.Minute
-			}
-		} else if strings.Contains(schedule, "*/") {
-			// Handle hourly intervals like 0 */6 * * * (every 6 hours)
-			fields := strings.Fields(schedule)
-			if len(fields) > 1 && strings.HasPrefix(fields[1], "*/") {
-				if hours, err := strconv.Atoi(strings.TrimPrefix(fields[1], "*/")); err == nil {
-					minInterval = time.Duration(hours) * time.Hour
-				}
-			}
+	// Manual runs report their result to the caller instead
+	if trigger != entities.JobTriggerManual {
+		if err != nil {
+			log.Printf("Job %s failed: %v", rj.job.Name, err)
+		} else {
+			log.Printf("Job %s completed: %s", rj.job.Name, message)
 		}
-	} else {
-		// Default to daily for fixed schedules
-		minInterval = 23 * time.Hour // Allow some flexibility
 	}
 
-	if minInterval == 0 {
-		minInterval = 23 * time.Hour // Default to daily
+	s.mu.Lock()
+	delete(s.running, rj.job.Name)
+	rj.state.Record(run)
+	state := *rj.state
+	s.mu.Unlock()
+
+	if s.jobRepo != nil {
+		if err := s.jobRepo.AppendRun(context.Background(), run, s.config.HistoryLimit); err != nil {
+			log.Printf("Failed to record run of job %s: %v", rj.job.Name, err)
+		}
+		if err := s.jobRepo.SaveState(context.Background(), &state); err != nil {
+			log.Printf("Failed to save state of job %s: %v", rj.job.Name, err)
+		}
 	}
 
-	return now.Sub(lastRun) < minInterval
+	return run
 }
 
-// matchesSchedule performs simple schedule matching
-func (s *SchedulerService) matchesSchedule(now time.Time, minute, hour, day, month, weekday string) bool {
-	// For now, implement basic daily scheduling
-	// A full cron implementation would be more complex
-
-	if hour == "*" {
-		return true // Run every hour (simplified)
-	}
-
-	if targetHour, err := strconv.Atoi(hour); err == nil {
-		if now.Hour() == targetHour {
-			if minute == "*" {
-				return true
-			}
-			if targetMinute, err := strconv.Atoi(minute); err == nil {
-				return now.Minute() == targetMinute
-			}
+// safeRun runs a job, turning a panic into an error so one faulty job cannot
+// take down the scheduler
+func (s *SchedulerService) safeRun(ctx context.Context, job *Job) (message string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
 		}
-	}
+	}()
+	return job.Run(ctx)
+}
 
-	return false
+// isEnabled reports whether a job should run on its schedule
+func (s *SchedulerService) isEnabled(rj *registeredJob) bool {
+	if s.config.IsDisabled(rj.job.Name) {
+		return false
+	}
+	return rj.job.Enabled == nil || rj.job.Enabled()
 }
 
-// checkSizeTriggers checks if cleanup should be triggered by size limits
-func (s *SchedulerService) checkSizeTriggers() {
-	// Get current storage status
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+// loadStates replaces in-memory job state with the persisted state. The
+// caller must hold s.mu.
+func (s *SchedulerService) loadStates() {
+	if s.jobRepo == nil {
+		return
+	}
 
-	storageStatus, err := s.storageService.GetStorageStatus(ctx, false)
+	states, err := s.jobRepo.LoadStates(context.Background())
 	if err != nil {
-		log.Printf("Failed to get storage status for size triggers: %v", err)
+		log.Printf("Failed to load job state: %v", err)
 		return
 	}
 
-	// Check if cleanup should be triggered
-	if s.cleanupService.ShouldRunCleanup(storageStatus) {
-		log.Println("Running cleanup due to size triggers...")
-
-		result, err := s.cleanupService.RunCleanup(ctx, false)
-		if err != nil {
-			log.Printf("Size-triggered cleanup failed: %v", err)
-		} else {
-			log.Printf("Size-triggered cleanup completed: %d items cleaned, %s reclaimed",
-				result.ItemsCleaned.Total, formatBytes(result.SpaceReclaimed))
+	for name, rj := range s.jobs {
+		if state, ok := states[name]; ok {
+			rj.state = state
 		}
 	}
 }
@@ -267,35 +416,3 @@ func formatBytes(bytes int64) string {
 		return strconv.FormatInt(bytes, 10) + " bytes"
 	}
 }
-
-// checkArchiving checks if automatic archiving should run
-func (s *SchedulerService) checkArchiving() {
-	if s.archiveService == nil {
-		return
-	}
-
-	// Check if automatic archiving is enabled
-	if !s.archiveService.ShouldAutoArchive() {
-		return
-	}
-
-	log.Println("Running automatic archiving...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-	defer cancel()
-
-	result, err := s.archiveService.RunAutoArchive(ctx)
-	if err != nil {
-		log.Printf("Automatic archiving failed: %v", err)
-		return
-	}
-
-	if result != nil && result.IssuesArchived > 0 {
-		log.Printf("Automatic archiving completed: %d issues archived, %s compressed (%.1f%% compression)",
-			result.IssuesArchived,
-			formatBytes(result.CompressedSize),
-			result.CompressionRatio*100)
-	} else {
-		log.Println("Automatic archiving completed: no issues to archive")
-	}
-}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func countingJob(name, schedule string, runs *int32) *Job {
+	return &Job{
+		Name:     name,
+		Schedule: schedule,
+		Run: func(ctx context.Context) (string, error) {
+			n := atomic.AddInt32(runs, 1)
+			return fmt.Sprintf("run %d", n), nil
+		},
+	}
+}
+
+func TestSchedulerService_RegisterJob(t *testing.T) {
+	scheduler := NewSchedulerService(nil, nil)
+
+	var runs int32
+	require.NoError(t, scheduler.RegisterJob(countingJob("sweep", "*/5 * * * *", &runs)))
+	assert.Error(t, scheduler.RegisterJob(countingJob("sweep", "@daily", &runs)), "duplicate name")
+	assert.Error(t, scheduler.RegisterJob(countingJob("bad", "* * *", &runs)), "invalid schedule")
+	assert.Error(t, scheduler.RegisterJob(&Job{Name: "no-run", Schedule: "@daily"}))
+
+	jobs := scheduler.ListJobs(context.Background())
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "sweep", jobs[0].Name)
+	assert.True(t, jobs[0].Enabled)
+	require.NotNil(t, jobs[0].State.NextRun)
+	assert.True(t, jobs[0].State.NextRun.After(time.Now()))
+}
+
+func TestSchedulerService_RunJobRecordsHistory(t *testing.T) {
+	ctx := context.Background()
+	jobRepo := storage.NewFileJobRepository(t.TempDir())
+	scheduler := NewSchedulerService(jobRepo, nil)
+
+	var runs int32
+	require.NoError(t, scheduler.RegisterJob(countingJob("sweep", "@daily", &runs)))
+	require.NoError(t, scheduler.RegisterJob(&Job{
+		Name:     "broken",
+		Schedule: "@daily",
+		Run: func(ctx context.Context) (string, error) {
+			panic("boom")
+		},
+	}))
+
+	run, err := scheduler.RunJob(ctx, "sweep")
+	require.NoError(t, err)
+	assert.Equal(t, entities.JobResultSuccess, run.Result)
+	assert.Equal(t, entities.JobTriggerManual, run.Trigger)
+	assert.Equal(t, "run 1", run.Message)
+
+	run, err = scheduler.RunJob(ctx, "broken")
+	require.NoError(t, err)
+	assert.Equal(t, entities.JobResultFailed, run.Result)
+	assert.Contains(t, run.Error, "boom")
+
+	_, err = scheduler.RunJob(ctx, "missing")
+	assert.Error(t, err)
+
+	// A fresh scheduler sees the persisted state and history
+	scheduler = NewSchedulerService(jobRepo, nil)
+	require.NoError(t, scheduler.RegisterJob(countingJob("sweep", "@daily", &runs)))
+
+	jobs := scheduler.ListJobs(ctx)
+	require.Len(t, jobs, 1)
+	require.NotNil(t, jobs[0].State.LastRun)
+	assert.Equal(t, 1, jobs[0].State.RunCount)
+	assert.Equal(t, entities.JobResultSuccess, jobs[0].State.LastResult)
+
+	history, err := scheduler.History(ctx, "", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "broken", history[0].Job, "most recent first")
+
+	history, err = scheduler.History(ctx, "sweep", 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestSchedulerService_CatchUpMissedRuns(t *testing.T) {
+	ctx := context.Background()
+	jobRepo := storage.NewFileJobRepository(t.TempDir())
+
+	now := time.Now()
+	twoDaysAgo := now.Add(-48 * time.Hour)
+	require.NoError(t, jobRepo.SaveState(ctx, &entities.JobState{Name: "missed", LastRun: &twoDaysAgo}))
+	require.NoError(t, jobRepo.SaveState(ctx, &entities.JobState{Name: "current", LastRun: &now}))
+	require.NoError(t, jobRepo.SaveState(ctx, &entities.JobState{Name: "disabled", LastRun: &twoDaysAgo}))
+
+	var missed, current, disabled int32
+	scheduler := NewSchedulerService(jobRepo, nil)
+	scheduler.RegisterJobs(
+		countingJob("missed", "@daily", &missed),
+		countingJob("current", "@yearly", &current),
+		countingJob("never-run", "@daily", &current),
+	)
+	disabledJob := countingJob("disabled", "@daily", &disabled)
+	disabledJob.Enabled = func() bool { return false }
+	require.NoError(t, scheduler.RegisterJob(disabledJob))
+
+	require.NoError(t, scheduler.Start())
+	scheduler.Stop()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&missed))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&current))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&disabled))
+
+	history, err := scheduler.History(ctx, "missed", 0)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, entities.JobTriggerCatchUp, history[0].Trigger)
+}
+
+func TestSchedulerService_RunDueJobs(t *testing.T) {
+	scheduler := NewSchedulerService(nil, nil)
+
+	var due, notDue int32
+	scheduler.RegisterJobs(
+		countingJob("due", "*/5 * * * *", &due),
+		countingJob("not-due", "@yearly", &notDue),
+	)
+	// Start computes the next run times; stop the loop so the test drives
+	// the clock
+	require.NoError(t, scheduler.Start())
+	scheduler.Stop()
+
+	// Advance the clock past the next five-minute mark
+	scheduler.runDueJobs(time.Now().Add(6 * time.Minute))
+	scheduler.wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&due))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&notDue))
+}
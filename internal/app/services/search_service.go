@@ -15,7 +15,9 @@ import (
 
 // SearchService provides advanced search capabilities
 type SearchService struct {
-	issueRepo repositories.IssueRepository
+	issueRepo   repositories.IssueRepository
+	users       *entities.UserDirectory
+	currentUser string
 }
 
 // NewSearchService creates a new search service
@@ -25,6 +27,41 @@ func NewSearchService(issueRepo repositories.IssueRepository) *SearchService {
 	}
 }
 
+// SetUserDirectory resolves assignee filters through the user directory:
+// aliases and emails find the person's issues, and assignee:@team finds
+// the issues of the team's members
+func (s *SearchService) SetUserDirectory(users *entities.UserDirectory) {
+	s.users = users
+}
+
+// SetCurrentUser sets the handle that "me" stands for in assignee and
+// watcher filters
+func (s *SearchService) SetCurrentUser(handle string) {
+	s.currentUser = handle
+}
+
+// resolveMe replaces "me" with the current user
+func (s *SearchService) resolveMe(value string) (string, error) {
+	if !strings.EqualFold(value, "me") {
+		return value, nil
+	}
+	if s.currentUser == "" {
+		return "", fmt.Errorf("cannot resolve \"me\": no current user")
+	}
+	return s.currentUser, nil
+}
+
+// resolveAssignee returns the handle an assignee filter stands for, or the
+// members of the team it names
+func (s *SearchService) resolveAssignee(value string) (string, []string) {
+	if _, isUser := s.users.Lookup(value); !isUser && strings.HasPrefix(value, "@") {
+		if members, ok := s.users.Team(value); ok {
+			return "", members
+		}
+	}
+	return s.users.Canonical(value), nil
+}
+
 // SearchQuery represents a parsed search query
 type SearchQuery struct {
 	Text         string                 `json:"text,omitempty"`
@@ -166,8 +203,12 @@ func (s *SearchService) parseFieldFilter(sq *SearchQuery, field, value string) e
 		}
 		sq.Filters["priority"] = entities.Priority(value)
 
-	case "assignee":
-		sq.Filters["assignee"] = value
+	case "assignee", "watcher":
+		value, err := s.resolveMe(value)
+		if err != nil {
+			return err
+		}
+		sq.Filters[field] = value
 
 	case "branch":
 		sq.Filters["branch"] = value
@@ -220,7 +261,17 @@ func (s *SearchService) ExecuteSearch(ctx context.Context, searchQuery *SearchQu
 	}
 
 	if assignee, ok := searchQuery.Filters["assignee"].(string); ok {
-		filter.Assignee = &assignee
+		handle, team := s.resolveAssignee(assignee)
+		if team != nil {
+			filter.Assignees = team
+		} else {
+			filter.Assignee = &handle
+		}
+	}
+
+	if watcher, ok := searchQuery.Filters["watcher"].(string); ok {
+		handle := s.users.Canonical(watcher)
+		filter.Watcher = &handle
 	}
 
 	if branch, ok := searchQuery.Filters["branch"].(string); ok {
@@ -313,7 +364,23 @@ func (s *SearchService) matchesNegatedFilter(issue entities.Issue, field string,
 		}
 	case "assignee":
 		if filterAssignee, ok := searchQuery.Filters["assignee"].(string); ok {
-			return issue.Assignee != nil && issue.Assignee.Username == filterAssignee
+			if issue.Assignee == nil {
+				return false
+			}
+			handle, team := s.resolveAssignee(filterAssignee)
+			if team != nil {
+				for _, member := range team {
+					if issue.Assignee.Username == member {
+						return true
+					}
+				}
+				return false
+			}
+			return issue.Assignee.Username == handle
+		}
+	case "watcher":
+		if filterWatcher, ok := searchQuery.Filters["watcher"].(string); ok {
+			return issue.IsWatching(s.users.Canonical(filterWatcher))
 		}
 	}
 	return false
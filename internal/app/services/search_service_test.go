@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestSearchService_AssigneeDirectory(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+
+	for id, assignee := range map[entities.IssueID]string{"TEST-001": "jdoe", "TEST-002": "robert", "TEST-003": "carol", "TEST-004": ""} {
+		issue := entities.NewIssue(id, string(id), "", entities.IssueTypeTask)
+		if assignee != "" {
+			issue.SetAssignee(&entities.User{Username: assignee})
+		}
+		require.NoError(t, issueRepo.Create(ctx, issue))
+	}
+
+	searchService := NewSearchService(issueRepo)
+	searchService.SetUserDirectory(entities.NewUserDirectory([]entities.UserProfile{
+		{Handle: "jdoe", Name: "John Doe", Teams: []string{"team-backend"}},
+		{Handle: "robert", Aliases: []string{"bob"}, Teams: []string{"team-backend"}},
+		{Handle: "carol", Teams: []string{"team-web"}},
+	}))
+
+	search := func(query string) []entities.IssueID {
+		t.Helper()
+		parsed, err := searchService.ParseSearchQuery(query)
+		require.NoError(t, err)
+		result, err := searchService.ExecuteSearch(ctx, parsed)
+		require.NoError(t, err)
+		var ids []entities.IssueID
+		for _, issue := range result.Issues {
+			ids = append(ids, issue.ID)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids
+	}
+
+	assert.Equal(t, []entities.IssueID{"TEST-001", "TEST-002"}, search("assignee:@team-backend"))
+	assert.Equal(t, []entities.IssueID{"TEST-003"}, search("assignee:@team-web"))
+	assert.Equal(t, []entities.IssueID{"TEST-002"}, search("assignee:bob"))
+	assert.Equal(t, []entities.IssueID{"TEST-001"}, search("assignee:@JDoe"))
+	assert.Empty(t, search("assignee:@team-ops"))
+}
+
+func TestSearchService_Watcher(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+
+	for id, watchers := range map[entities.IssueID][]string{"TEST-001": {"jdoe", "carol"}, "TEST-002": {"carol"}, "TEST-003": nil} {
+		issue := entities.NewIssue(id, string(id), "", entities.IssueTypeTask)
+		issue.Watchers = watchers
+		require.NoError(t, issueRepo.Create(ctx, issue))
+	}
+
+	searchService := NewSearchService(issueRepo)
+	searchService.SetUserDirectory(entities.NewUserDirectory([]entities.UserProfile{
+		{Handle: "jdoe", Aliases: []string{"johnd"}},
+	}))
+
+	_, err := searchService.ParseSearchQuery("watcher:me")
+	assert.Error(t, err, "me needs a current user")
+
+	searchService.SetCurrentUser("carol")
+	search := func(query string) int {
+		t.Helper()
+		parsed, err := searchService.ParseSearchQuery(query)
+		require.NoError(t, err)
+		result, err := searchService.ExecuteSearch(ctx, parsed)
+		require.NoError(t, err)
+		return len(result.Issues)
+	}
+
+	assert.Equal(t, 2, search("watcher:me"))
+	assert.Equal(t, 1, search("watcher:johnd"))
+	assert.Equal(t, 0, search("watcher:mallory"))
+}
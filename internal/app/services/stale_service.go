@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// StaleService marks inactive issues stale, closes them after a grace period
+// and lifts the stale label when new activity appears
+type StaleService struct {
+	issueRepo      repositories.IssueRepository
+	configRepo     repositories.ConfigRepository
+	historyService *HistoryService
+	config         *entities.StaleConfig
+	mu             sync.Mutex
+}
+
+// NewStaleService creates a new stale service
+func NewStaleService(
+	issueRepo repositories.IssueRepository,
+	configRepo repositories.ConfigRepository,
+	historyService *HistoryService,
+) *StaleService {
+	config := entities.DefaultStaleConfig()
+
+	// Try to load config from repository
+	if configRepo != nil {
+		if cfg, err := configRepo.Load(context.Background()); err == nil && cfg != nil && cfg.Stale != nil {
+			config = cfg.Stale
+		}
+	}
+
+	// Fill in settings missing from older or partial configs
+	defaults := entities.DefaultStaleConfig()
+	if config.Label == "" {
+		config.Label = defaults.Label
+	}
+	if config.Author == "" {
+		config.Author = defaults.Author
+	}
+	if config.StaleMessage == "" {
+		config.StaleMessage = defaults.StaleMessage
+	}
+	if config.CloseMessage == "" {
+		config.CloseMessage = defaults.CloseMessage
+	}
+
+	return &StaleService{
+		issueRepo:      issueRepo,
+		configRepo:     configRepo,
+		historyService: historyService,
+		config:         config,
+	}
+}
+
+// GetConfig returns the stale configuration
+func (s *StaleService) GetConfig() *entities.StaleConfig {
+	return s.config
+}
+
+// Sweep evaluates every open issue against the stale policy. A dry run
+// reports what would change without modifying any issue.
+func (s *StaleService) Sweep(ctx context.Context, dryRun bool) (*entities.StaleSweepResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issueList, err := s.issueRepo.List(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return nil, errors.Wrap(err, "StaleService.Sweep", "list_issues")
+	}
+
+	now := time.Now()
+	result := &entities.StaleSweepResult{
+		DryRun:    dryRun,
+		Marked:    []entities.StaleDecision{},
+		Closed:    []entities.StaleDecision{},
+		Unmarked:  []entities.StaleDecision{},
+		Timestamp: now,
+	}
+
+	for i := range issueList.Issues {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+
+		issue := &issueList.Issues[i]
+		if issue.Status == entities.StatusClosed || issue.Status == entities.StatusDone {
+			continue
+		}
+		result.Checked++
+
+		decision := s.config.Evaluate(issue, now)
+		if decision.Action == entities.StaleActionNone {
+			continue
+		}
+		if decision.Action == entities.StaleActionExempt {
+			result.Exempt++
+			continue
+		}
+
+		if !dryRun {
+			if err := s.apply(ctx, issue, decision); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", issue.ID, err))
+				continue
+			}
+		}
+
+		switch decision.Action {
+		case entities.StaleActionMark:
+			result.Marked = append(result.Marked, decision)
+		case entities.StaleActionClose:
+			result.Closed = append(result.Closed, decision)
+		case entities.StaleActionUnmark:
+			result.Unmarked = append(result.Unmarked, decision)
+		}
+	}
+
+	return result, nil
+}
+
+// apply carries out a decision on an issue and records it in the history
+func (s *StaleService) apply(ctx context.Context, issue *entities.Issue, decision entities.StaleDecision) error {
+	author := s.config.Author
+	oldLabels := labelNames(issue.Labels)
+
+	switch decision.Action {
+	case entities.StaleActionMark:
+		message := s.config.FormatStaleMessage(decision.DaysUntilClose)
+		issue.AddComment(author, message)
+		issue.AddLabel(s.staleLabel(ctx))
+		if err := s.issueRepo.Update(ctx, issue); err != nil {
+			return err
+		}
+		s.recordHistory(func() error {
+			if err := s.historyService.RecordIssueLabeled(ctx, issue.ID, oldLabels, labelNames(issue.Labels), author); err != nil {
+				return err
+			}
+			return s.historyService.RecordIssueCommented(ctx, issue.ID, message, author)
+		})
+
+	case entities.StaleActionClose:
+		issue.AddComment(author, s.config.CloseMessage)
+		issue.UpdateStatus(entities.StatusClosed)
+		if err := s.issueRepo.Update(ctx, issue); err != nil {
+			return err
+		}
+		s.recordHistory(func() error {
+			return s.historyService.RecordIssueClosed(ctx, issue.ID, "stale", author)
+		})
+
+	case entities.StaleActionUnmark:
+		issue.RemoveLabel(s.config.Label)
+		if err := s.issueRepo.Update(ctx, issue); err != nil {
+			return err
+		}
+		s.recordHistory(func() error {
+			return s.historyService.RecordIssueUnlabeled(ctx, issue.ID, oldLabels, labelNames(issue.Labels), author)
+		})
+	}
+
+	return nil
+}
+
+// recordHistory records history entries when a history service is
+// available. The issue change has already been saved, so a history failure
+// is reported but does not undo it.
+func (s *StaleService) recordHistory(record func() error) {
+	if s.historyService == nil {
+		return
+	}
+	if err := record(); err != nil {
+		fmt.Printf("Warning: Failed to record stale sweep in history: %v\n", err)
+	}
+}
+
+// staleLabel returns the stale label, using its configured color if the
+// project defines it
+func (s *StaleService) staleLabel(ctx context.Context) entities.Label {
+	if s.configRepo != nil {
+		if cfg, err := s.configRepo.Load(ctx); err == nil && cfg != nil {
+			for _, label := range cfg.Labels {
+				if label.Name == s.config.Label {
+					return label
+				}
+			}
+		}
+	}
+	return entities.Label{Name: s.config.Label, Color: "#ededed"}
+}
+
+// ScheduledJobs returns the stale sweep job
+func (s *StaleService) ScheduledJobs() []*Job {
+	return []*Job{
+		{
+			Name:        "stale",
+			Description: "Mark inactive issues stale and close them after a grace period",
+			Schedule:    "0 6 * * *",
+			Enabled: func() bool {
+				return s.GetConfig().Enabled
+			},
+			Run: func(ctx context.Context) (string, error) {
+				result, err := s.Sweep(ctx, false)
+				if err != nil {
+					return "", err
+				}
+				message := fmt.Sprintf("%d marked stale, %d closed, %d unmarked",
+					len(result.Marked), len(result.Closed), len(result.Unmarked))
+				if len(result.Errors) > 0 {
+					return message, fmt.Errorf("%d issues could not be updated", len(result.Errors))
+				}
+				return message, nil
+			},
+		},
+	}
+}
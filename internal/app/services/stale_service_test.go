@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// writeIssueFile saves an issue as-is, bypassing the repository so its
+// timestamps can be set in the past
+func writeIssueFile(t *testing.T, basePath string, issue *entities.Issue) {
+	t.Helper()
+	data, err := yaml.Marshal(issue)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(basePath, "issues", string(issue.ID)+".yaml"), data, 0644))
+}
+
+func TestStaleService_Sweep(t *testing.T) {
+	basePath := t.TempDir()
+	ctx := context.Background()
+
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	historyService := NewHistoryService(storage.NewFileHistoryRepository(basePath), nil)
+	staleService := NewStaleService(issueRepo, nil, historyService)
+	config := staleService.GetConfig()
+
+	longAgo := time.Now().Add(-90 * 24 * time.Hour)
+	old := entities.NewIssue("TEST-001", "Forgotten bug", "", entities.IssueTypeBug)
+	old.Timestamps.Created = longAgo
+	old.Timestamps.Updated = longAgo
+	require.NoError(t, issueRepo.Create(ctx, old))
+
+	pinned := entities.NewIssue("TEST-002", "Pinned roadmap item", "", entities.IssueTypeFeature)
+	pinned.Labels = []entities.Label{{Name: "pinned"}}
+	pinned.Timestamps.Updated = longAgo
+	require.NoError(t, issueRepo.Create(ctx, pinned))
+
+	fresh := entities.NewIssue("TEST-003", "Fresh issue", "", entities.IssueTypeTask)
+	require.NoError(t, issueRepo.Create(ctx, fresh))
+
+	// A dry run changes nothing
+	result, err := staleService.Sweep(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, result.Marked, 1)
+	stored, err := issueRepo.GetByID(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Labels)
+
+	result, err = staleService.Sweep(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Checked)
+	assert.Equal(t, 1, result.Exempt)
+	require.Len(t, result.Marked, 1)
+	assert.Equal(t, old.ID, result.Marked[0].IssueID)
+
+	stored, err = issueRepo.GetByID(ctx, old.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Labels, 1)
+	assert.Equal(t, "stale", stored.Labels[0].Name)
+	require.Len(t, stored.Comments, 1)
+	assert.Equal(t, config.Author, stored.Comments[0].Author)
+
+	// Sweeping again right away leaves the marked issue alone
+	result, err = staleService.Sweep(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Marked)
+	assert.Empty(t, result.Unmarked)
+	assert.Empty(t, result.Closed)
+
+	// Once the grace period has passed the issue is closed
+	markedAt := time.Now().Add(-15 * 24 * time.Hour)
+	stored.Comments[0].Date = markedAt
+	stored.Timestamps.Updated = markedAt
+	writeIssueFile(t, basePath, stored)
+
+	result, err = staleService.Sweep(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, result.Closed, 1)
+
+	stored, err = issueRepo.GetByID(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusClosed, stored.Status)
+	require.NotNil(t, stored.Timestamps.Closed)
+
+	history, err := historyService.GetIssueHistory(ctx, old.ID)
+	require.NoError(t, err)
+	closedEntries := history.GetEntriesByType(entities.ChangeTypeClosed)
+	require.Len(t, closedEntries, 1)
+	assert.Equal(t, config.Author, closedEntries[0].Author)
+	assert.Len(t, history.GetEntriesByType(entities.ChangeTypeLabeled), 1)
+}
+
+func TestStaleService_UnmarksOnActivity(t *testing.T) {
+	basePath := t.TempDir()
+	ctx := context.Background()
+
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	historyService := NewHistoryService(storage.NewFileHistoryRepository(basePath), nil)
+	staleService := NewStaleService(issueRepo, nil, historyService)
+
+	longAgo := time.Now().Add(-90 * 24 * time.Hour)
+	issue := entities.NewIssue("TEST-001", "Forgotten bug", "", entities.IssueTypeBug)
+	issue.Timestamps.Updated = longAgo
+	require.NoError(t, issueRepo.Create(ctx, issue))
+
+	result, err := staleService.Sweep(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, result.Marked, 1)
+
+	// Someone comments a few days after the issue was marked
+	stored, err := issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	markedAt := time.Now().Add(-3 * 24 * time.Hour)
+	stored.Comments[0].Date = markedAt
+	stored.Timestamps.Updated = markedAt
+	writeIssueFile(t, basePath, stored)
+	require.NoError(t, NewIssueService(issueRepo, nil, nil).AddComment(ctx, issue.ID, "alice", "Still reproducible"))
+
+	result, err = staleService.Sweep(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, result.Unmarked, 1)
+
+	stored, err = issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Labels)
+	assert.Equal(t, entities.StatusOpen, stored.Status)
+
+	history, err := historyService.GetIssueHistory(ctx, issue.ID)
+	require.NoError(t, err)
+	assert.Len(t, history.GetEntriesByType(entities.ChangeTypeUnlabeled), 1)
+}
+
+func TestStaleService_HistoryReplaysLabels(t *testing.T) {
+	basePath := t.TempDir()
+	ctx := context.Background()
+
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	historyService := NewHistoryService(storage.NewFileHistoryRepository(basePath), nil)
+	staleService := NewStaleService(issueRepo, nil, historyService)
+
+	longAgo := time.Now().Add(-90 * 24 * time.Hour)
+	issue := entities.NewIssue("TEST-001", "Forgotten bug", "", entities.IssueTypeBug)
+	issue.Labels = []entities.Label{{Name: "bug"}, {Name: "backend"}}
+	issue.Timestamps.Updated = longAgo
+	require.NoError(t, issueRepo.Create(ctx, issue))
+	require.NoError(t, historyService.RecordIssueCreated(ctx, issue, "alice"))
+
+	result, err := staleService.Sweep(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, result.Marked, 1)
+
+	// New activity after marking lifts the stale label again
+	stored, err := issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	markedAt := time.Now().Add(-3 * 24 * time.Hour)
+	stored.Comments[0].Date = markedAt
+	stored.AddComment("alice", "Still reproducible")
+	stored.Timestamps.Updated = time.Now()
+	writeIssueFile(t, basePath, stored)
+
+	result, err = staleService.Sweep(ctx, false)
+	require.NoError(t, err)
+	require.Len(t, result.Unmarked, 1)
+
+	current, err := issueRepo.GetByID(ctx, issue.ID)
+	require.NoError(t, err)
+	history, err := historyService.GetIssueHistory(ctx, issue.ID)
+	require.NoError(t, err)
+
+	labelsAt := func(changeType entities.ChangeType) []string {
+		t.Helper()
+		entries := history.GetEntriesByType(changeType)
+		require.Len(t, entries, 1)
+		replayed, err := history.IssueAt(current, entries[0].Version)
+		require.NoError(t, err)
+		return labelNames(replayed.Labels)
+	}
+
+	// Existing labels survive at every version, not just the stale label
+	assert.Equal(t, []string{"bug", "backend"}, labelsAt(entities.ChangeTypeCreated))
+	assert.Equal(t, []string{"bug", "backend", "stale"}, labelsAt(entities.ChangeTypeLabeled))
+	assert.Equal(t, []string{"bug", "backend"}, labelsAt(entities.ChangeTypeUnlabeled))
+
+	labeled := history.GetEntriesByType(entities.ChangeTypeLabeled)[0]
+	assert.Equal(t, "Added labels: stale", labeled.Message)
+	unlabeled := history.GetEntriesByType(entities.ChangeTypeUnlabeled)[0]
+	assert.Equal(t, "Removed labels: stale", unlabeled.Message)
+}
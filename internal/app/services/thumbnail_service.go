@@ -0,0 +1,161 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif"  // register GIF decoder
+	_ "image/jpeg" // register JPEG decoder
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register WebP decoder
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+const (
+	// DefaultThumbnailSize is the maximum width and height of a thumbnail
+	DefaultThumbnailSize = 256
+
+	// maxThumbnailSourcePixels guards against decompression bombs
+	maxThumbnailSourcePixels = 40 * 1000 * 1000
+
+	// maxThumbnailHeaderBytes bounds how much is read to find an image's
+	// dimensions; JPEG metadata segments can push them past the first block
+	maxThumbnailHeaderBytes = 1 << 20
+
+	// thumbnailSuffix is appended to a blob's key to form its thumbnail's key
+	thumbnailSuffix = ".thumb.png"
+)
+
+// thumbnailContentTypes lists the image formats that can be decoded in pure Go
+var thumbnailContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ThumbnailService generates thumbnails for image attachments and caches them
+// next to the attachment's blob
+type ThumbnailService struct {
+	attachmentRepo repositories.AttachmentRepository
+	size           int
+}
+
+// NewThumbnailService creates a new thumbnail service
+func NewThumbnailService(attachmentRepo repositories.AttachmentRepository) *ThumbnailService {
+	return &ThumbnailService{
+		attachmentRepo: attachmentRepo,
+		size:           DefaultThumbnailSize,
+	}
+}
+
+// CanThumbnail reports whether a thumbnail can be generated for an attachment
+func (t *ThumbnailService) CanThumbnail(attachment *entities.Attachment) bool {
+	return attachment.Type == entities.AttachmentTypeImage &&
+		thumbnailContentTypes[normalizeMimeType(attachment.ContentType)]
+}
+
+// Generate creates the thumbnail for an attachment and records it on the
+// attachment. The caller is responsible for saving the attachment metadata.
+func (t *ThumbnailService) Generate(ctx context.Context, attachment *entities.Attachment) error {
+	if !t.CanThumbnail(attachment) {
+		return errors.Wrap(fmt.Errorf("thumbnails are not supported for %s", attachment.ContentType), "ThumbnailService.Generate", "unsupported")
+	}
+
+	content, err := t.attachmentRepo.GetFile(ctx, attachment.StoragePath)
+	if err != nil {
+		return errors.Wrap(err, "ThumbnailService.Generate", "get_file")
+	}
+	defer content.Close()
+
+	reader, err := OpenAttachmentContent(attachment, content)
+	if err != nil {
+		return errors.Wrap(err, "ThumbnailService.Generate", "decompress")
+	}
+	defer reader.Close()
+
+	thumbnail, err := t.render(reader)
+	if err != nil {
+		return errors.Wrap(err, "ThumbnailService.Generate", "render")
+	}
+
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, thumbnail); err != nil {
+		return errors.Wrap(err, "ThumbnailService.Generate", "encode")
+	}
+
+	_, key := entities.SplitStoragePath(attachment.StoragePath)
+	storagePath, err := t.attachmentRepo.SaveBlob(ctx, key+thumbnailSuffix, &encoded)
+	if err != nil {
+		return errors.Wrap(err, "ThumbnailService.Generate", "save")
+	}
+
+	bounds := thumbnail.Bounds()
+	attachment.Thumbnail = &entities.AttachmentThumbnail{
+		StoragePath: storagePath,
+		Width:       bounds.Dx(),
+		Height:      bounds.Dy(),
+	}
+
+	return nil
+}
+
+// Open returns the cached thumbnail of an attachment
+func (t *ThumbnailService) Open(ctx context.Context, attachment *entities.Attachment) (io.ReadCloser, error) {
+	if attachment.Thumbnail == nil {
+		return nil, errors.Wrap(errors.ErrNotFound, "ThumbnailService.Open", "no_thumbnail")
+	}
+	return t.attachmentRepo.GetFile(ctx, attachment.Thumbnail.StoragePath)
+}
+
+// render decodes an image and scales it to fit within the thumbnail size.
+// The dimensions are read from a bounded header first, so oversized images
+// are rejected before their pixel data is read.
+func (t *ThumbnailService) render(r io.Reader) (image.Image, error) {
+	var header bytes.Buffer
+	config, _, err := image.DecodeConfig(io.TeeReader(io.LimitReader(r, maxThumbnailHeaderBytes), &header))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > maxThumbnailSourcePixels {
+		return nil, fmt.Errorf("image dimensions %dx%d are not supported", config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(io.MultiReader(&header, r))
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := fitWithin(config.Width, config.Height, t.size)
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Over, nil)
+
+	return dst, nil
+}
+
+// fitWithin scales width and height down to fit a square of the given size,
+// preserving the aspect ratio. Images that already fit are left unchanged.
+func fitWithin(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		scaled := height * size / width
+		if scaled < 1 {
+			scaled = 1
+		}
+		return size, scaled
+	}
+	scaled := width * size / height
+	if scaled < 1 {
+		scaled = 1
+	}
+	return scaled, size
+}
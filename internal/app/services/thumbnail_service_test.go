@@ -0,0 +1,84 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+func TestFitWithin(t *testing.T) {
+	w, h := fitWithin(100, 50, 256)
+	assert.Equal(t, 100, w)
+	assert.Equal(t, 50, h)
+
+	w, h = fitWithin(1024, 512, 256)
+	assert.Equal(t, 256, w)
+	assert.Equal(t, 128, h)
+
+	w, h = fitWithin(300, 1200, 256)
+	assert.Equal(t, 64, w)
+	assert.Equal(t, 256, h)
+
+	// Extreme aspect ratios keep at least one pixel
+	w, h = fitWithin(10000, 1, 256)
+	assert.Equal(t, 256, w)
+	assert.Equal(t, 1, h)
+}
+
+func TestThumbnailService_Render(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 800, 400))
+	for x := 0; x < 800; x++ {
+		src.Set(x, 200, color.RGBA{R: 255, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, src))
+
+	service := NewThumbnailService(nil)
+	thumbnail, err := service.render(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultThumbnailSize, thumbnail.Bounds().Dx())
+	assert.Equal(t, DefaultThumbnailSize/2, thumbnail.Bounds().Dy())
+
+	// Data that isn't an image is rejected
+	_, err = service.render(strings.NewReader("not an image"))
+	assert.Error(t, err)
+}
+
+func TestThumbnailService_RenderRejectsOversizedImages(t *testing.T) {
+	// A PNG header declaring 100000x100000 pixels, followed by pixel data
+	ihdr := []byte("IHDR")
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 100000)
+	ihdr = binary.BigEndian.AppendUint32(ihdr, 100000)
+	ihdr = append(ihdr, 8, 2, 0, 0, 0)
+	var header bytes.Buffer
+	header.WriteString("\x89PNG\r\n\x1a\n")
+	require.NoError(t, binary.Write(&header, binary.BigEndian, uint32(len(ihdr)-4)))
+	header.Write(ihdr)
+	require.NoError(t, binary.Write(&header, binary.BigEndian, crc32.ChecksumIEEE(ihdr)))
+
+	body := &countingReader{reader: io.MultiReader(&header, bytes.NewReader(make([]byte, 4*maxThumbnailHeaderBytes)))}
+	_, err := NewThumbnailService(nil).render(body)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "100000x100000")
+	assert.LessOrEqual(t, body.n, int64(maxThumbnailHeaderBytes), "pixel data is not read")
+}
+
+func TestThumbnailService_CanThumbnail(t *testing.T) {
+	service := NewThumbnailService(nil)
+
+	assert.True(t, service.CanThumbnail(&entities.Attachment{Type: entities.AttachmentTypeImage, ContentType: "image/png"}))
+	assert.True(t, service.CanThumbnail(&entities.Attachment{Type: entities.AttachmentTypeImage, ContentType: "image/webp"}))
+	assert.False(t, service.CanThumbnail(&entities.Attachment{Type: entities.AttachmentTypeImage, ContentType: "image/svg+xml"}))
+	assert.False(t, service.CanThumbnail(&entities.Attachment{Type: entities.AttachmentTypeText, ContentType: "text/plain"}))
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ooyeku/issuemap/internal/domain/entities"
@@ -15,6 +16,7 @@ type TimeTrackingService struct {
 	activeTimerRepo repositories.ActiveTimerRepository
 	issueService    *IssueService
 	historyService  *HistoryService
+	gitRepo         repositories.GitRepository
 }
 
 // NewTimeTrackingService creates a new time tracking service
@@ -32,8 +34,24 @@ func NewTimeTrackingService(
 	}
 }
 
+// SetGitRepository sets the git repository used to estimate time from commits
+func (s *TimeTrackingService) SetGitRepository(gitRepo repositories.GitRepository) {
+	s.gitRepo = gitRepo
+}
+
+// resolveAuthor returns the canonical handle of an author from the user
+// directory
+func (s *TimeTrackingService) resolveAuthor(ctx context.Context, author string) string {
+	if s.issueService == nil {
+		return author
+	}
+	return s.issueService.ResolveIdentity(ctx, author)
+}
+
 // StartTimer starts a timer for the given issue and author
 func (s *TimeTrackingService) StartTimer(ctx context.Context, issueID entities.IssueID, author, description string) (*entities.ActiveTimer, error) {
+	author = s.resolveAuthor(ctx, author)
+
 	// Check if issue exists
 	_, err := s.issueService.GetIssue(ctx, issueID)
 	if err != nil {
@@ -62,6 +80,8 @@ func (s *TimeTrackingService) StartTimer(ctx context.Context, issueID entities.I
 
 // StopTimer stops the active timer for the given author and creates a time entry
 func (s *TimeTrackingService) StopTimer(ctx context.Context, author string) (*entities.TimeEntry, error) {
+	author = s.resolveAuthor(ctx, author)
+
 	// Get active timer
 	activeTimer, err := s.activeTimerRepo.GetByAuthor(ctx, author)
 	if err != nil {
@@ -108,6 +128,8 @@ func (s *TimeTrackingService) StopTimer(ctx context.Context, author string) (*en
 
 // LogTime manually logs time for an issue
 func (s *TimeTrackingService) LogTime(ctx context.Context, issueID entities.IssueID, author string, duration time.Duration, description string) (*entities.TimeEntry, error) {
+	author = s.resolveAuthor(ctx, author)
+
 	// Check if issue exists
 	issue, err := s.issueService.GetIssue(ctx, issueID)
 	if err != nil {
@@ -141,7 +163,7 @@ func (s *TimeTrackingService) LogTime(ctx context.Context, issueID entities.Issu
 
 // GetActiveTimer returns the active timer for an author
 func (s *TimeTrackingService) GetActiveTimer(ctx context.Context, author string) (*entities.ActiveTimer, error) {
-	return s.activeTimerRepo.GetByAuthor(ctx, author)
+	return s.activeTimerRepo.GetByAuthor(ctx, s.resolveAuthor(ctx, author))
 }
 
 // GetTimeEntries returns time entries with filtering
@@ -167,6 +189,8 @@ func (s *TimeTrackingService) GetActiveTimers(ctx context.Context) ([]*entities.
 // ForceStopTimer stops any active timer for the given issue and creates a time entry
 // using the provided author as the one who stopped it (overriding the original starter)
 func (s *TimeTrackingService) ForceStopTimer(ctx context.Context, issueID entities.IssueID, stoppingAuthor string) (*entities.TimeEntry, error) {
+	stoppingAuthor = s.resolveAuthor(ctx, stoppingAuthor)
+
 	// Get all active timers and find one for this issue
 	activeTimers, err := s.activeTimerRepo.List(ctx)
 	if err != nil {
@@ -224,3 +248,309 @@ func (s *TimeTrackingService) ForceStopTimer(ctx context.Context, issueID entiti
 
 	return timeEntry, nil
 }
+
+// EstimateFromCommits creates commit-type time entries for commits that
+// reference an issue or were made on its branch. Durations come from the
+// session gap heuristic, minus any time the author already tracked with
+// timers or manual entries, and each commit is only recorded once per issue.
+func (s *TimeTrackingService) EstimateFromCommits(ctx context.Context, opts entities.CommitTimeOptions) (*entities.CommitTimeResult, error) {
+	if s.gitRepo == nil {
+		return nil, fmt.Errorf("git repository not available")
+	}
+
+	var issues []entities.Issue
+	if opts.IssueID != nil {
+		issue, err := s.issueService.GetIssue(ctx, *opts.IssueID)
+		if err != nil {
+			return nil, fmt.Errorf("issue not found: %w", err)
+		}
+		issues = append(issues, *issue)
+	} else {
+		issueList, err := s.issueService.ListIssues(ctx, repositories.IssueFilter{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list issues: %w", err)
+		}
+		issues = issueList.Issues
+	}
+
+	mainBranch, _ := s.gitRepo.GetMainBranch(ctx)
+
+	commitIssues := make(map[string][]entities.IssueID)
+	commits := make(map[string]repositories.Commit)
+	addCommit := func(issueID entities.IssueID, commit repositories.Commit) {
+		for _, existing := range commitIssues[commit.Hash] {
+			if existing == issueID {
+				return
+			}
+		}
+		commitIssues[commit.Hash] = append(commitIssues[commit.Hash], issueID)
+		commits[commit.Hash] = commit
+	}
+
+	for _, issue := range issues {
+		referenced, err := s.gitRepo.GetCommitsByIssue(ctx, issue.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits for %s: %w", issue.ID, err)
+		}
+		for _, commit := range referenced {
+			addCommit(issue.ID, commit)
+		}
+
+		if issue.Branch == "" || issue.Branch == mainBranch {
+			continue
+		}
+		if exists, err := s.gitRepo.BranchExists(ctx, issue.Branch); err != nil || !exists {
+			continue
+		}
+		onBranch, err := s.gitRepo.GetBranchCommits(ctx, issue.Branch, mainBranch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commits on branch %s: %w", issue.Branch, err)
+		}
+		for _, commit := range onBranch {
+			addCommit(issue.ID, commit)
+		}
+	}
+
+	existing, err := s.timeEntryRepo.List(ctx, repositories.TimeEntryFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	recorded := make(map[string]bool)
+	tracked := make(map[string][]*entities.TimeEntry)
+	for _, entry := range existing {
+		if entry.Type == entities.TimeEntryTypeCommit {
+			recorded[string(entry.IssueID)+"@"+entry.CommitHash] = true
+			continue
+		}
+		tracked[entry.Author] = append(tracked[entry.Author], entry)
+	}
+
+	onlyHashes := make(map[string]bool)
+	for _, hash := range opts.Hashes {
+		onlyHashes[hash] = true
+	}
+
+	marks := make([]entities.CommitMark, 0, len(commits))
+	for _, commit := range commits {
+		marks = append(marks, entities.CommitMark{Hash: commit.Hash, Author: s.resolveAuthor(ctx, commit.Author), Time: commit.Date})
+	}
+
+	result := &entities.CommitTimeResult{DryRun: opts.DryRun}
+	for _, window := range entities.EstimateCommitWindows(marks, opts.SessionGap, opts.LeadIn) {
+		hash := window.Commit.Hash
+		if len(onlyHashes) > 0 && !onlyHashes[hash] {
+			continue
+		}
+		if opts.Since != nil && window.End.Before(*opts.Since) {
+			continue
+		}
+		result.Commits++
+
+		issueIDs := commitIssues[hash]
+		duration := entities.UncoveredDuration(window.Start, window.End, tracked[window.Commit.Author])
+		share := (duration / time.Duration(len(issueIDs))).Round(time.Second)
+		if share <= 0 {
+			result.Overlapped++
+			continue
+		}
+
+		for _, issueID := range issueIDs {
+			if recorded[string(issueID)+"@"+hash] {
+				result.Existing++
+				continue
+			}
+			description := strings.TrimSpace(strings.SplitN(commits[hash].Message, "\n", 2)[0])
+			entry := entities.NewCommitEntry(issueID, hash, description, window.Commit.Author, window.Start, window.End, share)
+			result.Entries = append(result.Entries, entry)
+			result.TotalTime += share
+		}
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	type issueAuthor struct {
+		issueID entities.IssueID
+		author  string
+	}
+	hoursByIssue := make(map[entities.IssueID]float64)
+	commitsByAuthor := make(map[issueAuthor]int)
+	hoursByAuthor := make(map[issueAuthor]float64)
+	for _, entry := range result.Entries {
+		if err := s.timeEntryRepo.Create(ctx, entry); err != nil {
+			return nil, fmt.Errorf("failed to create time entry: %w", err)
+		}
+		hoursByIssue[entry.IssueID] += entry.GetDurationHours()
+		key := issueAuthor{entry.IssueID, entry.Author}
+		commitsByAuthor[key]++
+		hoursByAuthor[key] += entry.GetDurationHours()
+	}
+
+	for issueID, hours := range hoursByIssue {
+		issue, err := s.issueService.GetIssue(ctx, issueID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get issue: %w", err)
+		}
+		updates := map[string]interface{}{
+			"actual_hours": issue.GetActualHours() + hours,
+		}
+		if _, err := s.issueService.UpdateIssue(ctx, issueID, updates); err != nil {
+			return nil, fmt.Errorf("failed to update issue actual hours: %w", err)
+		}
+	}
+
+	if s.historyService != nil {
+		for key, count := range commitsByAuthor {
+			s.historyService.RecordTimeLogged(ctx, key.issueID, key.author, hoursByAuthor[key],
+				fmt.Sprintf("Estimated from %d commit(s)", count))
+		}
+	}
+
+	return result, nil
+}
+
+// EditTimeEntry changes the duration and/or description of a time entry.
+// Approved and locked entries cannot be edited.
+func (s *TimeTrackingService) EditTimeEntry(ctx context.Context, id string, duration *time.Duration, description *string) (*entities.TimeEntry, error) {
+	entry, err := s.timeEntryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("time entry not found: %w", err)
+	}
+	if !entry.IsEditable() {
+		return nil, fmt.Errorf("time entry %s is %s and cannot be edited", id, entry.ApprovalStatus())
+	}
+
+	delta := 0.0
+	if duration != nil {
+		if *duration <= 0 {
+			return nil, fmt.Errorf("duration must be positive")
+		}
+		delta = duration.Hours() - entry.GetDurationHours()
+		entry.Duration = *duration
+		if entry.EndTime != nil {
+			endTime := entry.StartTime.Add(*duration)
+			entry.EndTime = &endTime
+		}
+	}
+	if description != nil {
+		entry.Description = *description
+	}
+
+	if err := s.timeEntryRepo.Update(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to update time entry: %w", err)
+	}
+
+	if delta != 0 {
+		if err := s.adjustActualHours(ctx, entry.IssueID, delta); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
+
+// DeleteTimeEntry removes a time entry. Approved and locked entries cannot be deleted.
+func (s *TimeTrackingService) DeleteTimeEntry(ctx context.Context, id string) error {
+	entry, err := s.timeEntryRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("time entry not found: %w", err)
+	}
+	if !entry.IsEditable() {
+		return fmt.Errorf("time entry %s is %s and cannot be deleted", id, entry.ApprovalStatus())
+	}
+
+	if err := s.timeEntryRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	return s.adjustActualHours(ctx, entry.IssueID, -entry.GetDurationHours())
+}
+
+// BuildTimesheet generates the timesheet for a week or month
+func (s *TimeTrackingService) BuildTimesheet(ctx context.Context, opts entities.TimesheetOptions, billing *entities.BillingConfig) (*entities.Timesheet, error) {
+	start, end, err := entities.PeriodBounds(opts.Period, opts.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := repositories.TimeEntryFilter{DateFrom: &start, DateTo: &end}
+	if opts.Author != "" {
+		filter.Author = &opts.Author
+	}
+	entries, err := s.timeEntryRepo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+
+	issues := make(map[entities.IssueID]*entities.Issue)
+	for _, entry := range entries {
+		if _, ok := issues[entry.IssueID]; ok {
+			continue
+		}
+		// Time on deleted issues is still billed, just without issue details
+		issue, _ := s.issueService.GetIssue(ctx, entry.IssueID)
+		issues[entry.IssueID] = issue
+	}
+
+	return entities.BuildTimesheet(entries, issues, opts, billing)
+}
+
+// SetTimesheetStatus moves every entry on a timesheet to a new approval state.
+// Entries that cannot make the transition are reported rather than failing the batch.
+func (s *TimeTrackingService) SetTimesheetStatus(ctx context.Context, opts entities.TimesheetOptions, billing *entities.BillingConfig, status entities.TimeEntryStatus, by string) (*entities.TimesheetStatusChange, error) {
+	sheet, err := s.BuildTimesheet(ctx, opts, billing)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.TimesheetStatusChange{
+		Status:   status,
+		Rejected: make(map[string]string),
+	}
+	for _, group := range sheet.Groups {
+		for _, line := range group.Lines {
+			if line.Status == status {
+				result.Unchanged = append(result.Unchanged, line.EntryID)
+				continue
+			}
+
+			entry, err := s.timeEntryRepo.GetByID(ctx, line.EntryID)
+			if err != nil {
+				result.Rejected[line.EntryID] = err.Error()
+				continue
+			}
+			if err := entry.SetApprovalStatus(status, by); err != nil {
+				result.Rejected[line.EntryID] = err.Error()
+				continue
+			}
+			if err := s.timeEntryRepo.Update(ctx, entry); err != nil {
+				return nil, fmt.Errorf("failed to update time entry: %w", err)
+			}
+			result.Changed = append(result.Changed, line.EntryID)
+		}
+	}
+
+	return result, nil
+}
+
+// adjustActualHours adds delta hours to an issue's actual hours
+func (s *TimeTrackingService) adjustActualHours(ctx context.Context, issueID entities.IssueID, delta float64) error {
+	issue, err := s.issueService.GetIssue(ctx, issueID)
+	if err != nil {
+		// The issue may have been deleted; the entry change still stands
+		return nil
+	}
+
+	actualHours := issue.GetActualHours() + delta
+	if actualHours < 0 {
+		actualHours = 0
+	}
+	updates := map[string]interface{}{
+		"actual_hours": actualHours,
+	}
+	if _, err := s.issueService.UpdateIssue(ctx, issueID, updates); err != nil {
+		return fmt.Errorf("failed to update issue actual hours: %w", err)
+	}
+	return nil
+}
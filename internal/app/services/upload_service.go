@@ -0,0 +1,416 @@
+package services
+
+import (
+	"context"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+const (
+	// uploadsDirName is the directory, under the issuemap directory, holding
+	// in-progress uploads
+	uploadsDirName = "uploads"
+
+	// partialUploadSuffix is the extension of an upload's partial data file
+	partialUploadSuffix = ".part"
+)
+
+var uploadIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// UploadService manages resumable attachment uploads. Chunks are streamed to
+// a partial file next to a small session record; the content hash is updated
+// as each chunk arrives, and the attachment is created once every byte has
+// been received.
+type UploadService struct {
+	uploadsPath       string
+	attachmentService *AttachmentService
+	ttl               time.Duration
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewUploadService creates a new upload service
+func NewUploadService(basePath string, attachmentService *AttachmentService) *UploadService {
+	return &UploadService{
+		uploadsPath:       filepath.Join(basePath, uploadsDirName),
+		attachmentService: attachmentService,
+		ttl:               entities.DefaultUploadSessionTTL,
+		locks:             make(map[string]*sync.Mutex),
+	}
+}
+
+// CreateUpload starts a new upload session. The declared size is checked
+// against the attachment size limit and storage quota before any content is
+// accepted.
+func (s *UploadService) CreateUpload(ctx context.Context, session *entities.UploadSession) error {
+	if err := s.attachmentService.CheckUpload(ctx, session.IssueID, session.Filename, session.Size); err != nil {
+		return errors.Wrap(err, "UploadService.CreateUpload", "check_upload")
+	}
+
+	// Drop abandoned sessions so partial files don't accumulate
+	s.CleanupExpired()
+
+	if err := os.MkdirAll(s.uploadsPath, 0755); err != nil {
+		return errors.Wrap(err, "UploadService.CreateUpload", "create_dir")
+	}
+
+	// Partial uploads are local state and should never be committed
+	ignorePath := filepath.Join(s.uploadsPath, ".gitignore")
+	if _, err := os.Stat(ignorePath); os.IsNotExist(err) {
+		if err := os.WriteFile(ignorePath, []byte("*\n"), 0644); err != nil {
+			return errors.Wrap(err, "UploadService.CreateUpload", "create_gitignore")
+		}
+	}
+
+	hasher := NewHasher(s.attachmentService.HashAlgorithm())
+	state, err := marshalHashState(hasher)
+	if err != nil {
+		return errors.Wrap(err, "UploadService.CreateUpload", "hash_state")
+	}
+	session.HashAlgorithm = s.attachmentService.HashAlgorithm()
+	session.HashState = state
+	session.Offset = 0
+
+	part, err := os.OpenFile(s.partPath(session.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrap(err, "UploadService.CreateUpload", "create_part")
+	}
+	part.Close()
+
+	if err := s.saveSession(session); err != nil {
+		os.Remove(s.partPath(session.ID))
+		return errors.Wrap(err, "UploadService.CreateUpload", "save_session")
+	}
+
+	return nil
+}
+
+// GetUpload returns an upload session by ID
+func (s *UploadService) GetUpload(ctx context.Context, uploadID string) (*entities.UploadSession, error) {
+	session, err := s.loadSession(uploadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "UploadService.GetUpload", "load_session")
+	}
+	return session, nil
+}
+
+// ListUploads returns all in-progress upload sessions, oldest first
+func (s *UploadService) ListUploads(ctx context.Context) ([]*entities.UploadSession, error) {
+	entries, err := os.ReadDir(s.uploadsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "UploadService.ListUploads", "read_dir")
+	}
+
+	var sessions []*entities.UploadSession
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+			continue
+		}
+		session, err := s.loadSession(strings.TrimSuffix(entry.Name(), ".yaml"))
+		if err != nil {
+			continue // Skip unreadable sessions
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+// FindResumable returns the unfinished session started from a local file, or
+// nil if there is none or the file has changed since
+func (s *UploadService) FindResumable(ctx context.Context, issueID entities.IssueID, source string, size int64, modTime time.Time) (*entities.UploadSession, error) {
+	sessions, err := s.ListUploads(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(sessions) - 1; i >= 0; i-- {
+		session := sessions[i]
+		if session.IssueID == issueID && session.MatchesSource(source, size, modTime) &&
+			!session.IsExpired(time.Now(), s.ttl) {
+			return session, nil
+		}
+	}
+	return nil, nil
+}
+
+// AppendChunk writes a chunk of content at offset, which must equal the
+// number of bytes already received. Bytes read before a failure are kept, so
+// an interrupted chunk resumes from wherever it stopped. When the final byte
+// arrives the attachment is created and returned.
+func (s *UploadService) AppendChunk(ctx context.Context, uploadID string, offset int64, chunk io.Reader) (*entities.UploadSession, *entities.Attachment, error) {
+	lock := s.lockFor(uploadID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	session, err := s.loadSession(uploadID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "UploadService.AppendChunk", "load_session")
+	}
+	if offset != session.Offset {
+		return session, nil, errors.New("UploadService.AppendChunk", "offset_mismatch",
+			fmt.Errorf("upload offset is %d, not %d", session.Offset, offset))
+	}
+
+	if !session.IsComplete() {
+		if err := s.writeChunk(session, chunk); err != nil {
+			return session, nil, errors.Wrap(err, "UploadService.AppendChunk", "write_chunk")
+		}
+	}
+
+	if !session.IsComplete() {
+		return session, nil, nil
+	}
+
+	// An empty chunk at the final offset retries a failed finalization
+	attachment, err := s.finalize(ctx, session)
+	if err != nil {
+		return session, nil, errors.Wrap(err, "UploadService.AppendChunk", "finalize")
+	}
+	return session, attachment, nil
+}
+
+// CancelUpload discards an upload session and its partial data
+func (s *UploadService) CancelUpload(ctx context.Context, uploadID string) error {
+	lock := s.lockFor(uploadID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := s.loadSession(uploadID); err != nil {
+		return errors.Wrap(err, "UploadService.CancelUpload", "load_session")
+	}
+	s.removeSession(uploadID)
+	return nil
+}
+
+// CleanupExpired removes sessions that have been idle longer than the TTL,
+// returning how many were removed
+func (s *UploadService) CleanupExpired() int {
+	sessions, err := s.ListUploads(context.Background())
+	if err != nil {
+		return 0
+	}
+
+	removed := 0
+	now := time.Now()
+	for _, session := range sessions {
+		if session.IsExpired(now, s.ttl) {
+			s.removeSession(session.ID)
+			removed++
+		}
+	}
+	return removed
+}
+
+// ScheduledJobs returns the job that expires abandoned upload sessions
+func (s *UploadService) ScheduledJobs() []*Job {
+	return []*Job{
+		{
+			Name:        "upload-expiry",
+			Description: "Discard resumable uploads idle longer than the session TTL",
+			Schedule:    "@hourly",
+			Run: func(ctx context.Context) (string, error) {
+				return fmt.Sprintf("%d expired uploads removed", s.CleanupExpired()), nil
+			},
+		},
+	}
+}
+
+// writeChunk appends a chunk to the partial file and records the new offset
+// and hash state
+func (s *UploadService) writeChunk(session *entities.UploadSession, chunk io.Reader) error {
+	hasher := NewHasher(session.HashAlgorithm)
+	if err := unmarshalHashState(hasher, session.HashState); err != nil {
+		return err
+	}
+
+	part, err := os.OpenFile(s.partPath(session.ID), os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer part.Close()
+
+	// Drop bytes written after the last recorded offset, e.g. by a crash
+	// between writing data and saving the session
+	if err := part.Truncate(session.Offset); err != nil {
+		return err
+	}
+	if _, err := part.Seek(session.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	n, copyErr := io.Copy(io.MultiWriter(part, hasher), io.LimitReader(chunk, session.Remaining()))
+	if n > 0 {
+		if err := part.Sync(); err != nil {
+			return err
+		}
+		state, err := marshalHashState(hasher)
+		if err != nil {
+			return err
+		}
+		session.Offset += n
+		session.HashState = state
+		session.UpdatedAt = time.Now()
+		if err := s.saveSession(session); err != nil {
+			return err
+		}
+	}
+	if copyErr != nil {
+		return copyErr
+	}
+
+	// Content beyond the declared length means the client is sending a
+	// different file; discard the upload rather than keep a truncated copy
+	if session.IsComplete() {
+		var extra [1]byte
+		if n, _ := chunk.Read(extra[:]); n > 0 {
+			s.removeSession(session.ID)
+			return fmt.Errorf("upload exceeds declared length of %d bytes", session.Size)
+		}
+	}
+
+	return nil
+}
+
+// finalize creates the attachment from a complete upload and removes the session
+func (s *UploadService) finalize(ctx context.Context, session *entities.UploadSession) (*entities.Attachment, error) {
+	// The quota may have been used up while the upload was in progress
+	if err := s.attachmentService.CheckUpload(ctx, session.IssueID, session.Filename, session.Size); err != nil {
+		return nil, err
+	}
+
+	hasher := NewHasher(session.HashAlgorithm)
+	if err := unmarshalHashState(hasher, session.HashState); err != nil {
+		return nil, err
+	}
+
+	// Deduplication may have switched algorithms since the upload started
+	fileHash := ""
+	if session.HashAlgorithm == s.attachmentService.HashAlgorithm() {
+		fileHash = hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	part, err := os.Open(s.partPath(session.ID))
+	if err != nil {
+		return nil, err
+	}
+	defer part.Close()
+
+	attachment, err := s.attachmentService.UploadAttachmentWithHash(ctx, session.IssueID, session.Filename, part, session.Size, fileHash, session.UploadedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Description != "" {
+		if err := s.attachmentService.UpdateDescription(ctx, attachment.ID, session.Description); err == nil {
+			attachment.Description = session.Description
+		}
+	}
+
+	session.AttachmentID = attachment.ID
+	s.removeSession(session.ID)
+
+	return attachment, nil
+}
+
+func (s *UploadService) lockFor(uploadID string) *sync.Mutex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lock, ok := s.locks[uploadID]
+	if !ok {
+		lock = &sync.Mutex{}
+		s.locks[uploadID] = lock
+	}
+	return lock
+}
+
+func (s *UploadService) sessionPath(uploadID string) string {
+	return filepath.Join(s.uploadsPath, uploadID+".yaml")
+}
+
+func (s *UploadService) partPath(uploadID string) string {
+	return filepath.Join(s.uploadsPath, uploadID+partialUploadSuffix)
+}
+
+func (s *UploadService) loadSession(uploadID string) (*entities.UploadSession, error) {
+	if !uploadIDPattern.MatchString(uploadID) {
+		return nil, fmt.Errorf("invalid upload ID: %s", uploadID)
+	}
+
+	data, err := os.ReadFile(s.sessionPath(uploadID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrap(errors.ErrNotFound, "UploadService.loadSession", uploadID)
+		}
+		return nil, err
+	}
+
+	var session entities.UploadSession
+	if err := yaml.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (s *UploadService) saveSession(session *entities.UploadSession) error {
+	data, err := yaml.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := s.sessionPath(session.ID) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.sessionPath(session.ID))
+}
+
+func (s *UploadService) removeSession(uploadID string) {
+	os.Remove(s.partPath(uploadID))
+	os.Remove(s.sessionPath(uploadID))
+
+	s.mu.Lock()
+	delete(s.locks, uploadID)
+	s.mu.Unlock()
+}
+
+// marshalHashState captures a running hash so it can resume in a later request
+func marshalHashState(hasher hash.Hash) ([]byte, error) {
+	marshaler, ok := hasher.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("hash does not support resumption")
+	}
+	return marshaler.MarshalBinary()
+}
+
+func unmarshalHashState(hasher hash.Hash, state []byte) error {
+	unmarshaler, ok := hasher.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("hash does not support resumption")
+	}
+	return unmarshaler.UnmarshalBinary(state)
+}
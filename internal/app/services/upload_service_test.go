@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+// failingReader returns its data and then an error, like a dropped connection
+type failingReader struct {
+	data []byte
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, errors.New("connection reset")
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func newTestUploadService(t *testing.T) (*UploadService, *AttachmentService, entities.IssueID) {
+	t.Helper()
+	basePath := t.TempDir()
+	ctx := context.Background()
+
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	configRepo := storage.NewFileConfigRepository(basePath)
+	attachmentRepo := storage.NewFileAttachmentRepository(basePath)
+
+	issue := entities.NewIssue("TEST-001", "Upload target", "", entities.IssueTypeTask)
+	require.NoError(t, issueRepo.Create(ctx, issue))
+
+	storageService := NewStorageService(basePath, configRepo, issueRepo, attachmentRepo)
+	attachmentService := NewAttachmentService(attachmentRepo, issueRepo, storageService, basePath)
+
+	return NewUploadService(basePath, attachmentService), attachmentService, issue.ID
+}
+
+func TestUploadService_ResumesInterruptedUpload(t *testing.T) {
+	uploads, attachments, issueID := newTestUploadService(t)
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("log line\n"), 1000)
+	session := entities.NewUploadSession(issueID, "server.txt", int64(len(content)), "tester")
+	session.Description = "Server log"
+	require.NoError(t, uploads.CreateUpload(ctx, session))
+
+	// The connection drops part way through the first chunk; what arrived is kept
+	_, _, err := uploads.AppendChunk(ctx, session.ID, 0, &failingReader{data: content[:3000]})
+	require.Error(t, err)
+
+	progress, err := uploads.GetUpload(ctx, session.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3000), progress.Offset)
+
+	// A chunk at the wrong offset is rejected
+	_, _, err = uploads.AppendChunk(ctx, session.ID, 0, bytes.NewReader(content))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "offset_mismatch")
+
+	// Resume at the reported offset
+	updated, attachment, err := uploads.AppendChunk(ctx, session.ID, 3000, bytes.NewReader(content[3000:6000]))
+	require.NoError(t, err)
+	assert.Nil(t, attachment)
+	assert.Equal(t, int64(6000), updated.Offset)
+
+	_, attachment, err = uploads.AppendChunk(ctx, session.ID, 6000, bytes.NewReader(content[6000:]))
+	require.NoError(t, err)
+	require.NotNil(t, attachment)
+	assert.Equal(t, "Server log", attachment.Description)
+
+	// The session is gone and the attachment holds the full content
+	_, err = uploads.GetUpload(ctx, session.ID)
+	assert.Error(t, err)
+
+	reader, _, err := attachments.GetAttachmentContent(ctx, attachment.ID)
+	require.NoError(t, err)
+	defer reader.Close()
+	stored, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, content, stored)
+}
+
+func TestUploadService_IncrementalHash(t *testing.T) {
+	uploads, _, issueID := newTestUploadService(t)
+	ctx := context.Background()
+
+	content := []byte("hello, resumable world")
+	session := entities.NewUploadSession(issueID, "hello.txt", int64(len(content)), "tester")
+	require.NoError(t, uploads.CreateUpload(ctx, session))
+
+	_, _, err := uploads.AppendChunk(ctx, session.ID, 0, bytes.NewReader(content[:5]))
+	require.NoError(t, err)
+
+	progress, err := uploads.GetUpload(ctx, session.ID)
+	require.NoError(t, err)
+
+	hasher := NewHasher(progress.HashAlgorithm)
+	require.NoError(t, unmarshalHashState(hasher, progress.HashState))
+	hasher.Write(content[5:])
+
+	expected := sha256.Sum256(content)
+	assert.Equal(t, hex.EncodeToString(expected[:]), hex.EncodeToString(hasher.Sum(nil)))
+}
+
+func TestUploadService_RejectsOversizedUploads(t *testing.T) {
+	uploads, _, issueID := newTestUploadService(t)
+	ctx := context.Background()
+
+	session := entities.NewUploadSession(issueID, "core.txt", 1<<40, "tester")
+	assert.Error(t, uploads.CreateUpload(ctx, session))
+
+	// Content beyond the declared length is refused
+	session = entities.NewUploadSession(issueID, "small.txt", 4, "tester")
+	require.NoError(t, uploads.CreateUpload(ctx, session))
+	_, _, err := uploads.AppendChunk(ctx, session.ID, 0, bytes.NewReader([]byte("too long")))
+	assert.Error(t, err)
+	_, err = uploads.GetUpload(ctx, session.ID)
+	assert.Error(t, err)
+}
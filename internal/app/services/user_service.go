@@ -0,0 +1,356 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// loadUserDirectory returns the configured user directory, nil when there
+// is no config
+func loadUserDirectory(ctx context.Context, configRepo repositories.ConfigRepository) *entities.UserDirectory {
+	if configRepo == nil {
+		return nil
+	}
+	config, err := configRepo.Load(ctx)
+	if err != nil {
+		return nil
+	}
+	return config.UserDirectory()
+}
+
+// UserService manages the user directory and rewrites the identities
+// stored in issues, history and time tracking to canonical handles
+type UserService struct {
+	configRepo    repositories.ConfigRepository
+	issueRepo     repositories.IssueRepository
+	historyRepo   repositories.HistoryRepository
+	timeEntryRepo repositories.TimeEntryRepository
+	timerRepo     repositories.ActiveTimerRepository
+}
+
+// NewUserService creates a new user service
+func NewUserService(
+	configRepo repositories.ConfigRepository,
+	issueRepo repositories.IssueRepository,
+	historyRepo repositories.HistoryRepository,
+	timeEntryRepo repositories.TimeEntryRepository,
+	timerRepo repositories.ActiveTimerRepository,
+) *UserService {
+	return &UserService{
+		configRepo:    configRepo,
+		issueRepo:     issueRepo,
+		historyRepo:   historyRepo,
+		timeEntryRepo: timeEntryRepo,
+		timerRepo:     timerRepo,
+	}
+}
+
+// Directory returns the user directory
+func (s *UserService) Directory(ctx context.Context) (*entities.UserDirectory, error) {
+	config, err := s.configRepo.Load(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "UserService.Directory", "load_config")
+	}
+	return config.UserDirectory(), nil
+}
+
+// Add adds a person to the directory. When the handle exists the profile is
+// updated: name, capacity and timezone are replaced when given, emails,
+// aliases and teams are added. It reports whether the person is new.
+func (s *UserService) Add(ctx context.Context, profile entities.UserProfile) (bool, error) {
+	profile.Handle = strings.TrimPrefix(strings.TrimSpace(profile.Handle), "@")
+	if profile.Handle == "" {
+		return false, errors.New("UserService.Add", "validation", fmt.Errorf("a handle is required"))
+	}
+
+	config, err := s.configRepo.Load(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "UserService.Add", "load_config")
+	}
+
+	created := true
+	for i := range config.Users {
+		existing := &config.Users[i]
+		if !strings.EqualFold(existing.Handle, profile.Handle) {
+			continue
+		}
+		created = false
+		if profile.Name != "" {
+			existing.Name = profile.Name
+		}
+		if profile.Capacity != 0 {
+			existing.Capacity = profile.Capacity
+		}
+		if profile.Timezone != "" {
+			existing.Timezone = profile.Timezone
+		}
+		profile.Name, profile.Capacity, profile.Timezone = "", 0, ""
+		profile.Handle = existing.Handle
+		existing.Absorb(profile)
+		break
+	}
+	if created {
+		config.Users = append(config.Users, profile)
+	}
+
+	if err := entities.ValidateUserProfiles(config.Users); err != nil {
+		return false, errors.New("UserService.Add", "validation", err)
+	}
+	if err := s.configRepo.Save(ctx, config); err != nil {
+		return false, errors.Wrap(err, "UserService.Add", "save_config")
+	}
+	return created, nil
+}
+
+// Merge makes from an identity of the person into. When from is a person in
+// the directory, their profile is folded into into's and removed; otherwise
+// from, such as a git name found in the data, becomes an alias. The stored
+// data is then normalized.
+func (s *UserService) Merge(ctx context.Context, from, into string) (*entities.UserNormalization, error) {
+	config, err := s.configRepo.Load(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "UserService.Merge", "load_config")
+	}
+
+	directory := config.UserDirectory()
+	target, ok := directory.Lookup(into)
+	if !ok {
+		return nil, errors.New("UserService.Merge", "not_found",
+			fmt.Errorf("%s is not in the user directory; add them with 'issuemap users add'", into))
+	}
+	targetHandle := target.Handle
+	source, sourceKnown := directory.Lookup(from)
+	if sourceKnown && source.Handle == targetHandle {
+		return nil, errors.New("UserService.Merge", "validation", fmt.Errorf("%s is already an identity of %s", from, targetHandle))
+	}
+
+	users := make([]entities.UserProfile, 0, len(config.Users))
+	var absorbed *entities.UserProfile
+	for _, user := range config.Users {
+		if sourceKnown && user.Handle == source.Handle {
+			user := user
+			absorbed = &user
+			continue
+		}
+		users = append(users, user)
+	}
+	for i := range users {
+		if users[i].Handle != targetHandle {
+			continue
+		}
+		if absorbed != nil {
+			users[i].Absorb(*absorbed)
+		} else {
+			users[i].Aliases = append(users[i].Aliases, from)
+		}
+	}
+	config.Users = users
+
+	if err := entities.ValidateUserProfiles(config.Users); err != nil {
+		return nil, errors.New("UserService.Merge", "validation", err)
+	}
+	if err := s.configRepo.Save(ctx, config); err != nil {
+		return nil, errors.Wrap(err, "UserService.Merge", "save_config")
+	}
+	return s.Normalize(ctx, false, false)
+}
+
+// Normalize rewrites the assignees, comment and commit authors of issues,
+// and the authors and approvers of time entries and timers to canonical
+// handles. History keeps its sealed authors and records their handles,
+// unless reseal rewrites them. A dry run only reports. Confidential issues
+// that cannot be decrypted, and time entries that are approved or locked,
+// are skipped.
+func (s *UserService) Normalize(ctx context.Context, dryRun, reseal bool) (*entities.UserNormalization, error) {
+	directory, err := s.Directory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &entities.UserNormalization{DryRun: dryRun, Resealed: reseal}
+
+	if err := s.normalizeIssues(ctx, directory, result); err != nil {
+		return nil, err
+	}
+	if err := s.normalizeHistory(ctx, directory, reseal, result); err != nil {
+		return nil, err
+	}
+	if err := s.normalizeTimeEntries(ctx, directory, result); err != nil {
+		return nil, err
+	}
+	if err := s.normalizeTimers(ctx, directory, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *UserService) normalizeIssues(ctx context.Context, directory *entities.UserDirectory, result *entities.UserNormalization) error {
+	list, err := s.issueRepo.List(ctx, repositories.IssueFilter{})
+	if err != nil {
+		return errors.Wrap(err, "UserService.Normalize", "list_issues")
+	}
+	for i := range list.Issues {
+		issue := &list.Issues[i]
+		if issue.Redacted {
+			continue
+		}
+
+		changed := false
+		resolve := func(identity *string) {
+			if canonical := result.Resolve(directory, *identity); canonical != *identity {
+				*identity = canonical
+				changed = true
+			}
+		}
+		if issue.Assignee != nil {
+			resolved := directory.CanonicalUser(issue.Assignee)
+			result.Resolve(directory, issue.Assignee.Username)
+			if *resolved != *issue.Assignee {
+				issue.Assignee = resolved
+				changed = true
+			}
+		}
+		for j := range issue.Comments {
+			resolve(&issue.Comments[j].Author)
+		}
+		for j := range issue.Commits {
+			resolve(&issue.Commits[j].Author)
+		}
+		// Identities of the same person collapse into one watcher
+		watchers := issue.Watchers
+		issue.Watchers = nil
+		for _, watcher := range watchers {
+			resolve(&watcher)
+			issue.AddWatcher(watcher)
+		}
+		if len(issue.Watchers) != len(watchers) {
+			changed = true
+		}
+
+		if !changed {
+			continue
+		}
+		result.Issues++
+		if result.DryRun {
+			continue
+		}
+		if err := s.issueRepo.Update(ctx, issue); err != nil {
+			return errors.Wrap(err, "UserService.Normalize", "update_issue")
+		}
+	}
+	return nil
+}
+
+// normalizeHistory records who the authors of history entries are. Entries
+// are sealed into the hash chain, so their authors are left as recorded and
+// an entry mapping them to handles is appended instead. With reseal the
+// authors are rewritten, the chain is recomputed and the rewrite is recorded.
+func (s *UserService) normalizeHistory(ctx context.Context, directory *entities.UserDirectory, reseal bool, result *entities.UserNormalization) error {
+	histories, err := s.historyRepo.GetAllHistory(ctx, repositories.HistoryFilter{})
+	if err != nil {
+		return errors.Wrap(err, "UserService.Normalize", "list_history")
+	}
+	for _, history := range histories {
+		recorded := history.MappedIdentities()
+		mapping := make(map[string]string)
+		changed := 0
+		for i := range history.Entries {
+			entry := &history.Entries[i]
+			canonical := result.Resolve(directory, entry.Author)
+			if canonical == entry.Author || (!reseal && recorded[entry.Author] == canonical) {
+				continue
+			}
+			mapping[entry.Author] = canonical
+			if reseal {
+				entry.Author = canonical
+			}
+			changed++
+		}
+		if changed == 0 {
+			continue
+		}
+		result.HistoryEntries += changed
+		result.Histories++
+		if result.DryRun {
+			continue
+		}
+		if reseal {
+			history.Reseal()
+		}
+		history.AddEntry(entities.NewIdentityMappingEntry(history.IssueID, mapping, reseal))
+		if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
+			return errors.Wrap(err, "UserService.Normalize", "save_history")
+		}
+	}
+	return nil
+}
+
+func (s *UserService) normalizeTimeEntries(ctx context.Context, directory *entities.UserDirectory, result *entities.UserNormalization) error {
+	entries, err := s.timeEntryRepo.List(ctx, repositories.TimeEntryFilter{})
+	if err != nil {
+		return errors.Wrap(err, "UserService.Normalize", "list_time_entries")
+	}
+	for _, entry := range entries {
+		changed := false
+		if canonical := result.Resolve(directory, entry.Author); canonical != entry.Author {
+			entry.Author = canonical
+			changed = true
+		}
+		if entry.ApprovedBy != "" {
+			if canonical := result.Resolve(directory, entry.ApprovedBy); canonical != entry.ApprovedBy {
+				entry.ApprovedBy = canonical
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if !entry.IsEditable() {
+			result.LockedTimeEntries = append(result.LockedTimeEntries, entry.ID)
+			continue
+		}
+		result.TimeEntries++
+		if result.DryRun {
+			continue
+		}
+		if err := s.timeEntryRepo.Update(ctx, entry); err != nil {
+			return errors.Wrap(err, "UserService.Normalize", "update_time_entry")
+		}
+	}
+	return nil
+}
+
+// normalizeTimers moves running timers to their canonical author. A timer
+// is left alone when the person already has one under their handle.
+func (s *UserService) normalizeTimers(ctx context.Context, directory *entities.UserDirectory, result *entities.UserNormalization) error {
+	timers, err := s.timerRepo.List(ctx)
+	if err != nil {
+		return errors.Wrap(err, "UserService.Normalize", "list_timers")
+	}
+	for _, timer := range timers {
+		original := timer.Author
+		canonical := result.Resolve(directory, original)
+		if canonical == original {
+			continue
+		}
+		if existing, err := s.timerRepo.GetByAuthor(ctx, canonical); err == nil && existing != nil {
+			continue
+		}
+		result.Timers++
+		if result.DryRun {
+			continue
+		}
+		if err := s.timerRepo.Delete(ctx, timer.IssueID, original); err != nil {
+			return errors.Wrap(err, "UserService.Normalize", "delete_timer")
+		}
+		timer.Author = canonical
+		if err := s.timerRepo.Create(ctx, timer); err != nil {
+			return errors.Wrap(err, "UserService.Normalize", "create_timer")
+		}
+	}
+	return nil
+}
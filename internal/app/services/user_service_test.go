@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func TestUserService(t *testing.T) {
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	require.NoError(t, os.MkdirAll(basePath, 0755))
+
+	configRepo := storage.NewFileConfigRepository(basePath)
+	require.NoError(t, configRepo.Save(ctx, entities.NewDefaultConfig()))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	historyRepo := storage.NewFileHistoryRepository(basePath)
+	timeEntryRepo := storage.NewFileTimeEntryRepository(basePath)
+	timerRepo := storage.NewFileActiveTimerRepository(basePath)
+	userService := NewUserService(configRepo, issueRepo, historyRepo, timeEntryRepo, timerRepo)
+
+	// Data written before the directory existed
+	issue := entities.NewIssue("TEST-001", "Crash", "", entities.IssueTypeBug)
+	issue.SetAssignee(&entities.User{Username: "John Doe", Email: "jdoe@corp.example"})
+	issue.AddComment("jdoe@corp.example", "Looking into it")
+	issue.AddComment("system", "Status changed")
+	issue.Commits = []entities.CommitRef{{Hash: "abc123", Author: "johnd"}}
+	issue.Watchers = []string{"John Doe", "jdoe"}
+	require.NoError(t, issueRepo.Create(ctx, issue))
+	require.NoError(t, historyRepo.AddEntry(ctx, entities.NewHistoryEntry("TEST-001", entities.ChangeTypeCreated, "John Doe", "Issue created")))
+	entry := entities.NewTimeEntry("TEST-001", entities.TimeEntryTypeManual, time.Hour, "", "bob")
+	require.NoError(t, timeEntryRepo.Create(ctx, entry))
+	approved := entities.NewTimeEntry("TEST-001", entities.TimeEntryTypeManual, time.Hour, "", "johnd")
+	require.NoError(t, approved.SetApprovalStatus(entities.TimeEntryStatusSubmitted, "johnd"))
+	require.NoError(t, approved.SetApprovalStatus(entities.TimeEntryStatusApproved, "jdoe"))
+	require.NoError(t, timeEntryRepo.Create(ctx, approved))
+	require.NoError(t, timerRepo.Create(ctx, entities.NewActiveTimer("TEST-001", "", "John Doe")))
+
+	created, err := userService.Add(ctx, entities.UserProfile{Handle: "@jdoe", Name: "John Doe", Emails: []string{"jdoe@corp.example"}})
+	require.NoError(t, err)
+	assert.True(t, created)
+	created, err = userService.Add(ctx, entities.UserProfile{Handle: "jdoe", Aliases: []string{"johnd"}, Timezone: "Europe/Berlin"})
+	require.NoError(t, err)
+	assert.False(t, created)
+	_, err = userService.Add(ctx, entities.UserProfile{Handle: "robert", Aliases: []string{"johnd"}})
+	assert.Error(t, err, "an identity cannot belong to two people")
+
+	directory, err := userService.Directory(ctx)
+	require.NoError(t, err)
+	require.Len(t, directory.Users(), 1)
+	assert.Equal(t, entities.UserProfile{
+		Handle: "jdoe", Name: "John Doe", Emails: []string{"jdoe@corp.example"}, Aliases: []string{"johnd"}, Timezone: "Europe/Berlin",
+	}, directory.Users()[0])
+
+	// A dry run reports without writing
+	result, err := userService.Normalize(ctx, true, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Issues)
+	assert.Equal(t, 1, result.HistoryEntries)
+	assert.Equal(t, 0, result.TimeEntries)
+	assert.Equal(t, []string{approved.ID}, result.LockedTimeEntries)
+	assert.Equal(t, 1, result.Timers)
+	assert.Equal(t, []string{"bob"}, result.Unknown)
+	stored, err := issueRepo.GetByID(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", stored.Assignee.Username)
+
+	_, err = userService.Normalize(ctx, false, false)
+	require.NoError(t, err)
+	stored, err = issueRepo.GetByID(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Equal(t, &entities.User{Username: "jdoe", Email: "jdoe@corp.example"}, stored.Assignee)
+	assert.Equal(t, "jdoe", stored.Comments[0].Author)
+	assert.Equal(t, "system", stored.Comments[1].Author)
+	assert.Equal(t, "jdoe", stored.Commits[0].Author)
+	assert.Equal(t, []string{"jdoe"}, stored.Watchers)
+
+	// Sealed history keeps its authors; an appended entry maps them to handles
+	history, err := historyRepo.GetHistory(ctx, "TEST-001")
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 2)
+	assert.Equal(t, "John Doe", history.Entries[0].Author)
+	assert.Equal(t, "Mapped identities to handles: John Doe -> jdoe", history.Entries[1].Message)
+	assert.Equal(t, map[string]string{"John Doe": "jdoe"}, history.MappedIdentities())
+	verification := history.VerifyChain()
+	assert.True(t, verification.Valid(), verification.Problems)
+	// Approved time entries cannot be edited, so they keep their author
+	stillApproved, err := timeEntryRepo.GetByID(ctx, approved.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "johnd", stillApproved.Author)
+	timer, err := timerRepo.GetByAuthor(ctx, "jdoe")
+	require.NoError(t, err)
+	assert.Equal(t, entities.IssueID("TEST-001"), timer.IssueID)
+
+	// A recorded mapping is not recorded again
+	result, err = userService.Normalize(ctx, false, false)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.HistoryEntries)
+	history, err = historyRepo.GetHistory(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Len(t, history.Entries, 2)
+
+	// Resealing rewrites the authors and records that it did
+	result, err = userService.Normalize(ctx, false, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.HistoryEntries)
+	assert.Equal(t, 1, result.Histories)
+	history, err = historyRepo.GetHistory(ctx, "TEST-001")
+	require.NoError(t, err)
+	require.Len(t, history.Entries, 3)
+	assert.Equal(t, "jdoe", history.Entries[0].Author)
+	assert.Equal(t, true, history.Entries[2].Metadata["resealed"])
+	verification = history.VerifyChain()
+	assert.True(t, verification.Valid(), verification.Problems)
+	assert.Equal(t, 3, verification.Sealed)
+
+	// Merging an identity found in the data rewrites it
+	_, err = userService.Merge(ctx, "mallory", "jdoe")
+	require.NoError(t, err)
+	_, err = userService.Add(ctx, entities.UserProfile{Handle: "robert", Aliases: []string{"bob"}, Teams: []string{"team-backend"}})
+	require.NoError(t, err)
+	result, err = userService.Merge(ctx, "robert", "jdoe")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.TimeEntries)
+	assert.Empty(t, result.Unknown)
+
+	directory, err = userService.Directory(ctx)
+	require.NoError(t, err)
+	require.Len(t, directory.Users(), 1)
+	assert.Equal(t, []string{"johnd", "mallory", "robert", "bob"}, directory.Users()[0].Aliases)
+	assert.Equal(t, []string{"team-backend"}, directory.Users()[0].Teams)
+	entries, err := timeEntryRepo.GetByAuthor(ctx, "jdoe", repositories.TimeEntryFilter{})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	_, err = userService.Merge(ctx, "bob", "jdoe")
+	assert.Error(t, err, "already an identity")
+	_, err = userService.Merge(ctx, "jdoe", "nobody")
+	assert.Error(t, err)
+}
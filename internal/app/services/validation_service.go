@@ -0,0 +1,573 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// ValidationService checks hand-edited files under the .issuemap directory
+// before they are committed. Files are parsed into YAML nodes first so that
+// problems can be reported with the line and column they were found at.
+type ValidationService struct {
+	basePath    string
+	maintenance *AttachmentMaintenance
+
+	config   *entities.Config
+	issueIDs map[entities.IssueID]bool
+}
+
+// NewValidationService creates a validation service for the .issuemap
+// directory at basePath. Attachment blobs are looked up through
+// attachmentRepo; when it is nil only local blobs can be checked.
+func NewValidationService(basePath string, attachmentRepo repositories.AttachmentRepository) *ValidationService {
+	return &ValidationService{
+		basePath:    basePath,
+		maintenance: NewAttachmentMaintenance(attachmentRepo, basePath),
+	}
+}
+
+// validatedDirs are the directories whose YAML files are validated
+var validatedDirs = []string{"issues", "history", "dependencies", "time_entries"}
+
+// ValidateAll validates every issue, history, dependency, time entry and
+// config file in the .issuemap directory
+func (s *ValidationService) ValidateAll(ctx context.Context) (*entities.ValidationReport, error) {
+	paths := []string{filepath.Join(s.basePath, "config.yaml")}
+	for _, dir := range validatedDirs {
+		matches, err := filepath.Glob(filepath.Join(s.basePath, dir, "*.yaml"))
+		if err != nil {
+			return nil, errors.Wrap(err, "ValidationService.ValidateAll", "glob")
+		}
+		paths = append(paths, matches...)
+	}
+	return s.ValidateFiles(ctx, paths)
+}
+
+// ValidateFiles validates files and directories on disk. Paths outside the
+// .issuemap directory and files that are not IssueMap data are skipped.
+// Problems name files relative to the repository root.
+func (s *ValidationService) ValidateFiles(ctx context.Context, paths []string) (*entities.ValidationReport, error) {
+	report := entities.NewValidationReport()
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "ValidationService.ValidateFiles", "stat")
+		}
+
+		files := []string{path}
+		if info.IsDir() {
+			files = nil
+			err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if !d.IsDir() {
+					files = append(files, p)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, errors.Wrap(err, "ValidationService.ValidateFiles", "walk")
+			}
+		}
+
+		for _, file := range files {
+			relPath, ok := s.relPath(file)
+			if !ok {
+				continue
+			}
+			if _, ok := entities.SchemaKindForPath(relPath); !ok {
+				continue
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, errors.Wrap(err, "ValidationService.ValidateFiles", "read")
+			}
+			displayPath := filepath.ToSlash(filepath.Join(filepath.Base(s.basePath), relPath))
+			s.ValidateContent(ctx, report, displayPath, relPath, data)
+		}
+	}
+
+	report.Sort()
+	return report, nil
+}
+
+// ValidateContent validates the content of a single file and adds its
+// problems to report. relPath is the file's path relative to the .issuemap
+// directory and decides how it is checked; displayPath is used in problems.
+func (s *ValidationService) ValidateContent(ctx context.Context, report *entities.ValidationReport, displayPath, relPath string, data []byte) {
+	kind, ok := entities.SchemaKindForPath(relPath)
+	if !ok {
+		return
+	}
+	report.FilesChecked++
+
+	v := &fileValidator{report: report, file: displayPath}
+	if !v.parse(data) {
+		return
+	}
+
+	var header struct {
+		SchemaVersion int `yaml:"schema_version"`
+	}
+	if err := v.root.Decode(&header); err == nil && header.SchemaVersion > entities.CurrentSchemaVersion(kind) {
+		v.errorf((&entities.SchemaVersionError{Kind: kind, Version: header.SchemaVersion,
+			Supported: entities.CurrentSchemaVersion(kind)}).Error(), "schema_version")
+		return
+	}
+
+	name := strings.TrimSuffix(filepath.Base(filepath.FromSlash(relPath)), ".yaml")
+	switch kind {
+	case entities.SchemaKindIssue:
+		var issue entities.Issue
+		if v.decode(data, &issue) {
+			s.checkIssue(ctx, v, name, &issue)
+		}
+	case entities.SchemaKindHistory:
+		var history entities.IssueHistory
+		if v.decode(data, &history) {
+			s.checkHistory(v, name, &history)
+		}
+	case entities.SchemaKindDependency:
+		var dependency entities.Dependency
+		if v.decode(data, &dependency) {
+			s.checkDependency(v, name, &dependency)
+		}
+	case entities.SchemaKindTimeEntry:
+		var entry entities.TimeEntry
+		if v.decode(data, &entry) {
+			s.checkTimeEntry(v, name, &entry)
+		}
+	case entities.SchemaKindConfig:
+		var config entities.Config
+		if v.decode(data, &config) {
+			s.checkConfig(v, &config)
+		}
+	}
+}
+
+func (s *ValidationService) checkIssue(ctx context.Context, v *fileValidator, name string, issue *entities.Issue) {
+	switch {
+	case issue.ID == "":
+		v.errorf("id is required", "id")
+	case string(issue.ID) != name:
+		v.errorf(fmt.Sprintf("id %q does not match file name %q", issue.ID, name+".yaml"), "id")
+	}
+	if strings.TrimSpace(issue.Title) == "" {
+		v.errorf("title is required", "title")
+	}
+
+	switch {
+	case issue.Type == "":
+		v.errorf("type is required", "type")
+	case !containsValue(issueTypes, issue.Type):
+		v.errorf(fmt.Sprintf("invalid type %q (expected %s)", issue.Type, joinValues(issueTypes)), "type")
+	}
+
+	statuses := s.statuses()
+	switch {
+	case issue.Status == "":
+		v.errorf("status is required", "status")
+	case issue.Status == entities.Status(entities.ConfidentialPlaceholder) && issue.Sealed != nil:
+	case !containsValue(statuses, issue.Status):
+		v.errorf(fmt.Sprintf("invalid status %q (expected %s)", issue.Status, joinValues(statuses)), "status")
+	}
+
+	switch {
+	case issue.Priority == "":
+		v.errorf("priority is required", "priority")
+	case !containsValue(priorities, issue.Priority):
+		v.errorf(fmt.Sprintf("invalid priority %q (expected %s)", issue.Priority, joinValues(priorities)), "priority")
+	}
+
+	seen := make(map[int]int)
+	for i, comment := range issue.Comments {
+		if comment.ID <= 0 {
+			v.errorf(fmt.Sprintf("comment id must be positive, got %d", comment.ID), "comments", i, "id")
+			continue
+		}
+		if first, ok := seen[comment.ID]; ok {
+			v.errorf(fmt.Sprintf("duplicate comment id %d (also used by comments[%d])", comment.ID, first), "comments", i, "id")
+			continue
+		}
+		seen[comment.ID] = i
+	}
+
+	for i, attachment := range issue.Attachments {
+		if attachment.IssueID != "" && attachment.IssueID != issue.ID {
+			v.warnf(fmt.Sprintf("attachment belongs to issue %s", attachment.IssueID), "attachments", i, "issue_id")
+		}
+		if attachment.StoragePath == "" {
+			v.errorf("storage_path is required", "attachments", i, "storage_path")
+			continue
+		}
+		if !entities.IsLocalStoragePath(attachment.StoragePath) && s.maintenance.attachmentRepo == nil {
+			continue
+		}
+		if !s.maintenance.blobExists(ctx, attachment.StoragePath) {
+			v.errorf(fmt.Sprintf("attachment blob %s not found", attachment.StoragePath), "attachments", i, "storage_path")
+		}
+	}
+}
+
+func (s *ValidationService) checkHistory(v *fileValidator, name string, history *entities.IssueHistory) {
+	switch {
+	case history.IssueID == "":
+		v.errorf("issue_id is required", "issue_id")
+	case string(history.IssueID) != name:
+		v.errorf(fmt.Sprintf("issue_id %q does not match file name %q", history.IssueID, name+".yaml"), "issue_id")
+	}
+
+	previous := 0
+	for i, entry := range history.Entries {
+		if entry.IssueID != history.IssueID {
+			v.errorf(fmt.Sprintf("entry belongs to issue %q", entry.IssueID), "entries", i, "issue_id")
+		}
+		if entry.Type == "" {
+			v.errorf("type is required", "entries", i, "type")
+		}
+		if entry.Version <= previous {
+			v.errorf(fmt.Sprintf("version %d does not follow version %d", entry.Version, previous), "entries", i, "version")
+		}
+		previous = entry.Version
+	}
+	if len(history.Entries) > 0 && history.CurrentVersion != previous {
+		v.warnf(fmt.Sprintf("current_version %d does not match the last entry version %d", history.CurrentVersion, previous), "current_version")
+	}
+}
+
+func (s *ValidationService) checkDependency(v *fileValidator, name string, dependency *entities.Dependency) {
+	switch {
+	case dependency.ID == "":
+		v.errorf("id is required", "id")
+	case dependency.ID != name:
+		v.errorf(fmt.Sprintf("id %q does not match file name %q", dependency.ID, name+".yaml"), "id")
+	}
+
+	switch {
+	case dependency.Type == "":
+		v.errorf("type is required", "type")
+	case !containsValue(dependencyTypes, dependency.Type):
+		v.errorf(fmt.Sprintf("invalid type %q (expected %s)", dependency.Type, joinValues(dependencyTypes)), "type")
+	}
+	switch {
+	case dependency.Status == "":
+		v.errorf("status is required", "status")
+	case !containsValue(dependencyStatuses, dependency.Status):
+		v.errorf(fmt.Sprintf("invalid status %q (expected %s)", dependency.Status, joinValues(dependencyStatuses)), "status")
+	}
+	if dependency.CreatedBy == "" {
+		v.errorf("created_by is required", "created_by")
+	}
+
+	if dependency.SourceID != "" && dependency.SourceID == dependency.TargetID {
+		v.errorf("an issue cannot depend on itself", "target_id")
+	}
+	for _, ref := range []struct {
+		field string
+		id    entities.IssueID
+	}{{"source_id", dependency.SourceID}, {"target_id", dependency.TargetID}} {
+		switch {
+		case ref.id == "":
+			v.errorf(ref.field+" is required", ref.field)
+		case !s.issueExists(ref.id):
+			v.errorf(fmt.Sprintf("issue %s does not exist", ref.id), ref.field)
+		}
+	}
+}
+
+func (s *ValidationService) checkTimeEntry(v *fileValidator, name string, entry *entities.TimeEntry) {
+	switch {
+	case entry.ID == "":
+		v.errorf("id is required", "id")
+	case entry.ID != name:
+		v.errorf(fmt.Sprintf("id %q does not match file name %q", entry.ID, name+".yaml"), "id")
+	}
+
+	switch {
+	case entry.IssueID == "":
+		v.errorf("issue_id is required", "issue_id")
+	case !s.issueExists(entry.IssueID):
+		v.errorf(fmt.Sprintf("issue %s does not exist", entry.IssueID), "issue_id")
+	}
+
+	switch {
+	case entry.Type == "":
+		v.errorf("type is required", "type")
+	case !containsValue(timeEntryTypes, entry.Type):
+		v.errorf(fmt.Sprintf("invalid type %q (expected %s)", entry.Type, joinValues(timeEntryTypes)), "type")
+	}
+	if entry.Status != "" && !containsValue(timeEntryStatuses, entry.Status) {
+		v.errorf(fmt.Sprintf("invalid status %q (expected %s)", entry.Status, joinValues(timeEntryStatuses)), "status")
+	}
+	if entry.Duration <= 0 {
+		v.errorf("duration must be positive", "duration")
+	}
+	if entry.Author == "" {
+		v.errorf("author is required", "author")
+	}
+	if entry.EndTime != nil && entry.EndTime.Before(entry.StartTime) {
+		v.errorf("end_time is before start_time", "end_time")
+	}
+}
+
+func (s *ValidationService) checkConfig(v *fileValidator, config *entities.Config) {
+	seen := make(map[entities.Status]bool)
+	for i, status := range config.Workflow.Statuses {
+		if status == "" {
+			v.errorf("status cannot be empty", "workflow", "statuses", i)
+			continue
+		}
+		if seen[status] {
+			v.errorf(fmt.Sprintf("duplicate status %q", status), "workflow", "statuses", i)
+		}
+		seen[status] = true
+	}
+
+	statuses := config.Workflow.Statuses
+	if len(statuses) == 0 {
+		statuses = builtinStatuses
+	}
+	if config.Workflow.DefaultStatus != "" && !containsValue(statuses, config.Workflow.DefaultStatus) {
+		v.errorf(fmt.Sprintf("default_status %q is not one of the workflow statuses", config.Workflow.DefaultStatus),
+			"workflow", "default_status")
+	}
+}
+
+// statuses returns the statuses allowed by the project config
+func (s *ValidationService) statuses() []entities.Status {
+	if s.config == nil {
+		s.config = &entities.Config{}
+		if data, err := os.ReadFile(filepath.Join(s.basePath, "config.yaml")); err == nil {
+			_ = yaml.Unmarshal(data, s.config)
+		}
+	}
+	if len(s.config.Workflow.Statuses) == 0 {
+		return builtinStatuses
+	}
+	return s.config.Workflow.Statuses
+}
+
+// issueExists reports whether an issue is stored in the project or one of
+// its archives
+func (s *ValidationService) issueExists(id entities.IssueID) bool {
+	if s.issueIDs == nil {
+		s.issueIDs = make(map[entities.IssueID]bool)
+		matches, _ := filepath.Glob(filepath.Join(s.basePath, "issues", "*.yaml"))
+		for _, match := range matches {
+			s.issueIDs[entities.IssueID(strings.TrimSuffix(filepath.Base(match), ".yaml"))] = true
+		}
+		if data, err := os.ReadFile(filepath.Join(s.basePath, "archives", "index.json")); err == nil {
+			var index entities.ArchiveIndex
+			if json.Unmarshal(data, &index) == nil {
+				for archived := range index.Issues {
+					s.issueIDs[archived] = true
+				}
+			}
+		}
+	}
+	return s.issueIDs[id]
+}
+
+// relPath returns a path relative to the .issuemap directory
+func (s *ValidationService) relPath(path string) (string, bool) {
+	base, err := filepath.Abs(s.basePath)
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+var (
+	issueTypes = []entities.IssueType{
+		entities.IssueTypeBug, entities.IssueTypeFeature, entities.IssueTypeTask, entities.IssueTypeEpic,
+	}
+	priorities = []entities.Priority{
+		entities.PriorityLow, entities.PriorityMedium, entities.PriorityHigh, entities.PriorityCritical,
+	}
+	builtinStatuses = []entities.Status{
+		entities.StatusOpen, entities.StatusInProgress, entities.StatusReview, entities.StatusDone, entities.StatusClosed,
+	}
+	dependencyTypes = []entities.DependencyType{
+		entities.DependencyTypeBlocks, entities.DependencyTypeRequires,
+	}
+	dependencyStatuses = []entities.DependencyStatus{
+		entities.DependencyStatusActive, entities.DependencyStatusResolved, entities.DependencyStatusIgnored,
+	}
+	timeEntryTypes = []entities.TimeEntryType{
+		entities.TimeEntryTypeManual, entities.TimeEntryTypeTimer, entities.TimeEntryTypeCommit,
+	}
+	timeEntryStatuses = []entities.TimeEntryStatus{
+		entities.TimeEntryStatusDraft, entities.TimeEntryStatusSubmitted,
+		entities.TimeEntryStatusApproved, entities.TimeEntryStatusLocked,
+	}
+)
+
+func containsValue[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func joinValues[T ~string](values []T) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = string(v)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// fileValidator reports problems in one file at the positions of its YAML
+// nodes
+type fileValidator struct {
+	report *entities.ValidationReport
+	file   string
+	root   *yaml.Node
+}
+
+// yamlErrorLine matches the line prefix of YAML parser and decoder errors
+var yamlErrorLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// unknownField matches strict decoding errors for fields the schema lacks
+var unknownField = regexp.MustCompile(`^field (\S+) not found in type`)
+
+// parse reads the YAML document, reporting syntax errors
+func (v *fileValidator) parse(data []byte) bool {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		v.decodeError(err.Error(), entities.ValidationSeverityError)
+		return false
+	}
+	if len(doc.Content) == 0 {
+		v.report.Add(entities.ValidationProblem{File: v.file, Severity: entities.ValidationSeverityError, Message: "file is empty"})
+		return false
+	}
+	v.root = doc.Content[0]
+	if v.root.Kind != yaml.MappingNode {
+		v.report.Add(entities.ValidationProblem{File: v.file, Line: v.root.Line, Column: v.root.Column,
+			Severity: entities.ValidationSeverityError, Message: "expected a mapping at the top level"})
+		return false
+	}
+	return true
+}
+
+// decode decodes the document into out, reporting values of the wrong type
+// as errors and fields the schema does not know as warnings. It returns
+// false when nothing could be decoded.
+func (v *fileValidator) decode(data []byte, out interface{}) bool {
+	if err := v.root.Decode(out); err != nil {
+		typeErr, ok := err.(*yaml.TypeError)
+		if !ok {
+			v.decodeError(err.Error(), entities.ValidationSeverityError)
+			return false
+		}
+		for _, message := range typeErr.Errors {
+			v.decodeError(message, entities.ValidationSeverityError)
+		}
+	}
+
+	// A second, strict pass finds fields the schema does not know
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	strict := reflect.New(reflect.TypeOf(out).Elem()).Interface()
+	if typeErr, ok := decoder.Decode(strict).(*yaml.TypeError); ok {
+		for _, message := range typeErr.Errors {
+			if m := yamlErrorLine.FindStringSubmatch(message); m != nil && unknownField.MatchString(m[2]) {
+				v.decodeError(message, entities.ValidationSeverityWarning)
+			}
+		}
+	}
+	return true
+}
+
+// decodeError reports a YAML error message, taking its position from the
+// "line N:" prefix
+func (v *fileValidator) decodeError(message, severity string) {
+	problem := entities.ValidationProblem{File: v.file, Severity: severity, Message: strings.TrimPrefix(message, "yaml: ")}
+	if m := yamlErrorLine.FindStringSubmatch(message); m != nil {
+		problem.Line, _ = strconv.Atoi(m[1])
+		problem.Message = m[2]
+		if f := unknownField.FindStringSubmatch(m[2]); f != nil {
+			problem.Field = f[1]
+			problem.Message = "unknown field"
+		}
+	}
+	v.report.Add(problem)
+}
+
+func (v *fileValidator) errorf(message string, path ...interface{}) {
+	v.add(entities.ValidationSeverityError, message, path)
+}
+
+func (v *fileValidator) warnf(message string, path ...interface{}) {
+	v.add(entities.ValidationSeverityWarning, message, path)
+}
+
+// add reports a problem at the node found by following path, a list of
+// mapping keys and sequence indexes. When part of the path is missing the
+// closest existing node is used.
+func (v *fileValidator) add(severity, message string, path []interface{}) {
+	node, found := v.root, true
+	var field strings.Builder
+	for _, step := range path {
+		var next *yaml.Node
+		switch step := step.(type) {
+		case string:
+			if field.Len() > 0 {
+				field.WriteByte('.')
+			}
+			field.WriteString(step)
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == step {
+						next = node.Content[i+1]
+						break
+					}
+				}
+			}
+		case int:
+			fmt.Fprintf(&field, "[%d]", step)
+			if node.Kind == yaml.SequenceNode && step < len(node.Content) {
+				next = node.Content[step]
+			}
+		}
+		if found && next != nil {
+			node = next
+		} else {
+			found = false
+		}
+	}
+
+	problem := entities.ValidationProblem{File: v.file, Line: node.Line, Column: node.Column,
+		Severity: severity, Field: field.String(), Message: message}
+	v.report.Add(problem)
+}
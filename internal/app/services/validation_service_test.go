@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// newValidationTestService creates a validation service over a project
+// with the given files, keyed by path relative to .issuemap
+func newValidationTestService(t *testing.T, files map[string]string) (*ValidationService, string) {
+	t.Helper()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	for name, content := range files {
+		path := filepath.Join(basePath, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return NewValidationService(basePath, nil), basePath
+}
+
+// problemsByField indexes problems by their field
+func problemsByField(report *entities.ValidationReport) map[string]entities.ValidationProblem {
+	problems := make(map[string]entities.ValidationProblem)
+	for _, problem := range report.Problems {
+		problems[problem.Field] = problem
+	}
+	return problems
+}
+
+const validIssue = `id: TEST-001
+title: Crash on save
+type: bug
+status: open
+priority: high
+comments:
+  - id: 1
+    author: alice
+    text: Reproduced
+`
+
+func TestValidationService_Issue(t *testing.T) {
+	service, _ := newValidationTestService(t, map[string]string{
+		"issues/TEST-001.yaml":         validIssue,
+		"attachments/TEST-001/log.txt": "log",
+	})
+	ctx := context.Background()
+
+	report := entities.NewValidationReport()
+	service.ValidateContent(ctx, report, "TEST-001.yaml", "issues/TEST-001.yaml", []byte(validIssue))
+	assert.Empty(t, report.Problems)
+
+	report = entities.NewValidationReport()
+	service.ValidateContent(ctx, report, "TEST-001.yaml", "issues/TEST-002.yaml", []byte(`id: TEST-001
+title: Crash on save
+type: bug
+status: finished
+priority: urgent
+owner: bob
+comments:
+  - id: 1
+    text: first
+  - id: 1
+    text: second
+attachments:
+  - id: att
+    storage_path: attachments/TEST-001/log.txt
+  - id: gone
+    storage_path: attachments/TEST-001/missing.txt
+`))
+
+	problems := problemsByField(report)
+	assert.Equal(t, 1, problems["id"].Line, "id does not match the file name")
+	assert.Equal(t, entities.ValidationProblem{File: "TEST-001.yaml", Line: 4, Column: 9,
+		Severity: entities.ValidationSeverityError, Field: "status",
+		Message: `invalid status "finished" (expected open, in-progress, review, done, closed)`}, problems["status"])
+	assert.Equal(t, 5, problems["priority"].Line)
+	assert.Equal(t, entities.ValidationSeverityWarning, problems["owner"].Severity)
+	assert.Equal(t, 6, problems["owner"].Line)
+	assert.Equal(t, 10, problems["comments[1].id"].Line)
+	assert.Contains(t, problems["comments[1].id"].Message, "duplicate comment id 1")
+	assert.Equal(t, 16, problems["attachments[1].storage_path"].Line)
+	assert.NotContains(t, problems, "attachments[0].storage_path")
+	assert.Equal(t, 5, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+}
+
+func TestValidationService_CustomStatuses(t *testing.T) {
+	service, _ := newValidationTestService(t, map[string]string{
+		"config.yaml": "workflow:\n  statuses: [open, blocked, closed]\n  default_status: open\n",
+	})
+
+	report := entities.NewValidationReport()
+	service.ValidateContent(context.Background(), report, "TEST-001.yaml", "issues/TEST-001.yaml",
+		[]byte("id: TEST-001\ntitle: Waiting\ntype: task\nstatus: blocked\npriority: low\n"))
+	assert.Empty(t, report.Problems)
+}
+
+func TestValidationService_SyntaxAndTypeErrors(t *testing.T) {
+	service, _ := newValidationTestService(t, nil)
+	ctx := context.Background()
+
+	report := entities.NewValidationReport()
+	service.ValidateContent(ctx, report, "bad.yaml", "issues/bad.yaml", []byte("id: bad\ntitle: [unclosed\n"))
+	require.Len(t, report.Problems, 1)
+	assert.NotZero(t, report.Problems[0].Line, "syntax errors carry the parser position")
+	assert.Equal(t, entities.ValidationSeverityError, report.Problems[0].Severity)
+
+	report = entities.NewValidationReport()
+	service.ValidateContent(ctx, report, "te.yaml", "time_entries/te.yaml", []byte(`id: te
+issue_id: TEST-001
+type: manual
+duration: lots
+author: alice
+`))
+	require.NotEmpty(t, report.Problems)
+	assert.Equal(t, 4, report.Problems[0].Line)
+	assert.Contains(t, report.Problems[0].Message, "cannot unmarshal")
+}
+
+func TestValidationService_References(t *testing.T) {
+	service, basePath := newValidationTestService(t, map[string]string{
+		"issues/TEST-001.yaml": validIssue,
+		"dependencies/TEST-001-blocks-TEST-009.yaml": `id: TEST-001-blocks-TEST-009
+source_id: TEST-001
+target_id: TEST-009
+type: blocks
+status: active
+created_by: alice
+`,
+		"time_entries/te_1.yaml": `id: te_1
+issue_id: TEST-001
+type: manual
+duration: 1h
+author: alice
+`,
+		"archives/index.json": `{"issues": {"TEST-009": {"issue_id": "TEST-009"}}}`,
+	})
+
+	report, err := service.ValidateAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.FilesChecked)
+	assert.Empty(t, report.Problems, "archived issues can be referenced")
+
+	require.NoError(t, os.Remove(filepath.Join(basePath, "archives", "index.json")))
+	service = NewValidationService(basePath, nil)
+	report, err = service.ValidateFiles(context.Background(), []string{filepath.Join(basePath, "dependencies")})
+	require.NoError(t, err)
+	require.Len(t, report.Problems, 1)
+	problem := report.Problems[0]
+	assert.Equal(t, ".issuemap/dependencies/TEST-001-blocks-TEST-009.yaml", problem.File)
+	assert.Equal(t, "target_id", problem.Field)
+	assert.Equal(t, 3, problem.Line)
+	assert.Equal(t, "issue TEST-009 does not exist", problem.Message)
+}
+
+func TestValidationService_NewerSchemaVersion(t *testing.T) {
+	service, _ := newValidationTestService(t, nil)
+
+	report := entities.NewValidationReport()
+	service.ValidateContent(context.Background(), report, "TEST-001.yaml", "issues/TEST-001.yaml",
+		[]byte("schema_version: 99\nid: TEST-001\n"))
+	require.Len(t, report.Problems, 1)
+	assert.Equal(t, "schema_version", report.Problems[0].Field)
+	assert.Equal(t, 1, report.Problems[0].Line)
+	assert.Contains(t, report.Problems[0].Message, "upgrade issuemap")
+}
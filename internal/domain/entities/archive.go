@@ -120,6 +120,24 @@ func NewArchiveIndex() *ArchiveIndex {
 	}
 }
 
+// ArchiveSummary describes one archive file
+type ArchiveSummary struct {
+	// Archive filename
+	File string `json:"file"`
+
+	// Issues still archived in this file; restored issues are not listed
+	IssueIDs []IssueID `json:"issue_ids"`
+
+	// Number of issues originally written to the archive
+	TotalIssues int `json:"total_issues"`
+
+	// Size of the archive file
+	Size int64 `json:"size"`
+
+	// When the archive was written
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
 // ArchiveResult represents the result of an archive operation
 type ArchiveResult struct {
 	// Operation timestamp
@@ -174,6 +192,25 @@ type ArchiveRestoreResult struct {
 	Duration time.Duration `json:"duration"`
 }
 
+// ArchiveIndexRebuildResult represents the result of rebuilding the archive
+// index from the archive files
+type ArchiveIndexRebuildResult struct {
+	// Number of archive files read
+	Archives int `json:"archives"`
+
+	// Number of issues in the rebuilt index
+	Issues int `json:"issues"`
+
+	// Issues found in archives that the index did not list
+	Added []IssueID `json:"added,omitempty"`
+
+	// Issues the index listed in archives that no longer contain them
+	Removed []IssueID `json:"removed,omitempty"`
+
+	// Archives that could not be read; their index entries are kept
+	Errors []string `json:"errors,omitempty"`
+}
+
 // ArchiveStats represents statistics about archives
 type ArchiveStats struct {
 	// Total number of archives
@@ -32,6 +32,28 @@ type Attachment struct {
 
 	// Compression metadata
 	Compression *CompressionMetadata `yaml:"compression,omitempty" json:"compression,omitempty"`
+
+	// Malware scanner verdict
+	Scan *AttachmentScan `yaml:"scan,omitempty" json:"scan,omitempty"`
+
+	// Thumbnail generated for image attachments
+	Thumbnail *AttachmentThumbnail `yaml:"thumbnail,omitempty" json:"thumbnail,omitempty"`
+
+	// Sealed holds the wrapped content key of attachments to confidential
+	// issues, whose stored content is encrypted
+	Sealed *SealedContent `yaml:"sealed,omitempty" json:"sealed,omitempty"`
+}
+
+// IsEncrypted reports whether the attachment's stored content is encrypted
+func (a *Attachment) IsEncrypted() bool {
+	return a.Sealed != nil
+}
+
+// AttachmentThumbnail describes a cached thumbnail image
+type AttachmentThumbnail struct {
+	StoragePath string `yaml:"storage_path" json:"-"`
+	Width       int    `yaml:"width" json:"width"`
+	Height      int    `yaml:"height" json:"height"`
 }
 
 // NewAttachment creates a new attachment
@@ -110,10 +132,6 @@ func (a *Attachment) Validate() error {
 	if a.Size < 0 {
 		return fmt.Errorf("file size cannot be negative")
 	}
-	// Max file size: 10MB
-	maxSize := int64(10 * 1024 * 1024)
-	if a.Size > maxSize {
-		return fmt.Errorf("file size exceeds maximum allowed size of 10MB")
-	}
+	// The maximum size is enforced by the project's attachment policy
 	return nil
 }
@@ -0,0 +1,65 @@
+package entities
+
+import "time"
+
+// AttachmentPolicy holds per-project overrides of the built-in attachment
+// security rules. The maximum size comes from StorageConfig.MaxAttachmentSize.
+type AttachmentPolicy struct {
+	// MIME types to allow or block in addition to the built-in list
+	AllowMimeTypes []string `yaml:"allow_mime_types,omitempty" json:"allow_mime_types,omitempty"`
+	BlockMimeTypes []string `yaml:"block_mime_types,omitempty" json:"block_mime_types,omitempty"`
+
+	// Extensions (with leading dot) to allow or block in addition to the built-in list
+	AllowExtensions []string `yaml:"allow_extensions,omitempty" json:"allow_extensions,omitempty"`
+	BlockExtensions []string `yaml:"block_extensions,omitempty" json:"block_extensions,omitempty"`
+
+	// Trust the declared content type instead of detecting it from the content
+	DisableSniffing bool `yaml:"disable_sniffing,omitempty" json:"disable_sniffing,omitempty"`
+
+	// Serve SVG images as uploaded instead of stripping scripts first
+	DisableSVGSanitizing bool `yaml:"disable_svg_sanitizing,omitempty" json:"disable_svg_sanitizing,omitempty"`
+
+	// External malware scanner run on every upload
+	Scanner *AttachmentScannerConfig `yaml:"scanner,omitempty" json:"scanner,omitempty"`
+}
+
+// AttachmentScannerConfig configures a command-line malware scanner. The file
+// content is piped to the command's stdin; following the ClamAV convention
+// (clamscan/clamdscan with "-"), exit status 0 means clean, 1 means infected
+// and anything else is an error.
+type AttachmentScannerConfig struct {
+	Command        string   `yaml:"command" json:"command"`
+	Args           []string `yaml:"args,omitempty" json:"args,omitempty"`
+	TimeoutSeconds int      `yaml:"timeout_seconds,omitempty" json:"timeout_seconds,omitempty"`
+	// Reject uploads when the scanner fails instead of accepting them unscanned
+	Required bool `yaml:"required,omitempty" json:"required,omitempty"`
+}
+
+// DefaultScannerTimeout bounds a scanner run when no timeout is configured
+const DefaultScannerTimeout = 60 * time.Second
+
+// Timeout returns the scanner timeout
+func (c *AttachmentScannerConfig) Timeout() time.Duration {
+	if c == nil || c.TimeoutSeconds <= 0 {
+		return DefaultScannerTimeout
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// ScanVerdict is the outcome of scanning an attachment
+type ScanVerdict string
+
+const (
+	ScanVerdictClean    ScanVerdict = "clean"
+	ScanVerdictInfected ScanVerdict = "infected"
+	ScanVerdictError    ScanVerdict = "error"
+)
+
+// AttachmentScan records the scanner verdict for an attachment
+type AttachmentScan struct {
+	Scanner   string      `yaml:"scanner" json:"scanner"`
+	Verdict   ScanVerdict `yaml:"verdict" json:"verdict"`
+	Signature string      `yaml:"signature,omitempty" json:"signature,omitempty"`
+	Detail    string      `yaml:"detail,omitempty" json:"detail,omitempty"`
+	ScannedAt time.Time   `yaml:"scanned_at" json:"scanned_at"`
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// PreviewKind identifies how an attachment preview should be rendered
+type PreviewKind string
+
+const (
+	PreviewKindText     PreviewKind = "text"
+	PreviewKindMarkdown PreviewKind = "markdown"
+	PreviewKindCSV      PreviewKind = "csv"
+	PreviewKindJSON     PreviewKind = "json"
+	PreviewKindNone     PreviewKind = "none"
+)
+
+// Preview limits keep responses small for large attachments
+const (
+	DefaultPreviewBytes = 256 * 1024
+	MaxPreviewCSVRows   = 500
+)
+
+// AttachmentPreview is a text rendering of an attachment for display. Content
+// is raw text; clients must escape it (or sanitize rendered Markdown).
+type AttachmentPreview struct {
+	Kind      PreviewKind `json:"kind"`
+	Content   string      `json:"content,omitempty"`
+	Rows      [][]string  `json:"rows,omitempty"`
+	Truncated bool        `json:"truncated"`
+}
+
+// PreviewKindFor returns the preview kind for an attachment
+func PreviewKindFor(filename, contentType string) PreviewKind {
+	contentType = strings.ToLower(contentType)
+	if idx := strings.Index(contentType, ";"); idx != -1 {
+		contentType = strings.TrimSpace(contentType[:idx])
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(filename)); {
+	case ext == ".md" || ext == ".markdown" || contentType == "text/markdown" || contentType == "text/x-markdown":
+		return PreviewKindMarkdown
+	case ext == ".csv" || contentType == "text/csv":
+		return PreviewKindCSV
+	case ext == ".json" || contentType == "application/json":
+		return PreviewKindJSON
+	case strings.HasPrefix(contentType, "text/"),
+		contentType == "application/xml",
+		contentType == "application/x-yaml",
+		ext == ".txt", ext == ".log", ext == ".yaml", ext == ".yml", ext == ".xml":
+		return PreviewKindText
+	}
+	return PreviewKindNone
+}
+
+// BuildAttachmentPreview renders up to limit bytes of an attachment's content.
+// Binary content yields a PreviewKindNone preview.
+func BuildAttachmentPreview(filename, contentType string, data []byte, limit int) *AttachmentPreview {
+	preview := &AttachmentPreview{Kind: PreviewKindFor(filename, contentType)}
+	if preview.Kind == PreviewKindNone {
+		return preview
+	}
+
+	if limit <= 0 {
+		limit = DefaultPreviewBytes
+	}
+	if len(data) > limit {
+		data = data[:limit]
+		// Don't split a multi-byte character
+		for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+		preview.Truncated = true
+	}
+
+	if bytes.IndexByte(data, 0) != -1 || !utf8.Valid(data) {
+		return &AttachmentPreview{Kind: PreviewKindNone}
+	}
+
+	switch preview.Kind {
+	case PreviewKindJSON:
+		var indented bytes.Buffer
+		if !preview.Truncated && json.Indent(&indented, data, "", "  ") == nil {
+			preview.Content = indented.String()
+		} else {
+			preview.Content = string(data)
+		}
+	case PreviewKindCSV:
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+		for len(preview.Rows) < MaxPreviewCSVRows {
+			record, err := reader.Read()
+			if err != nil {
+				// A truncated final line fails to parse; show what was read
+				break
+			}
+			preview.Rows = append(preview.Rows, record)
+		}
+		if len(preview.Rows) == MaxPreviewCSVRows {
+			if _, err := reader.Read(); err == nil {
+				preview.Truncated = true
+			}
+		}
+		if len(preview.Rows) == 0 {
+			preview.Kind = PreviewKindText
+			preview.Content = string(data)
+		}
+	default:
+		preview.Content = string(data)
+	}
+
+	return preview
+}
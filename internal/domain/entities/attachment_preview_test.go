@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewKindFor(t *testing.T) {
+	assert.Equal(t, PreviewKindMarkdown, PreviewKindFor("README.md", "text/plain"))
+	assert.Equal(t, PreviewKindCSV, PreviewKindFor("data.csv", "text/plain; charset=utf-8"))
+	assert.Equal(t, PreviewKindJSON, PreviewKindFor("payload", "application/json"))
+	assert.Equal(t, PreviewKindText, PreviewKindFor("build.log", "application/octet-stream"))
+	assert.Equal(t, PreviewKindNone, PreviewKindFor("photo.png", "image/png"))
+}
+
+func TestBuildAttachmentPreview(t *testing.T) {
+	// JSON is pretty-printed
+	preview := BuildAttachmentPreview("data.json", "application/json", []byte(`{"a":1}`), 0)
+	assert.Equal(t, PreviewKindJSON, preview.Kind)
+	assert.Equal(t, "{\n  \"a\": 1\n}", preview.Content)
+	assert.False(t, preview.Truncated)
+
+	// CSV is parsed into rows
+	preview = BuildAttachmentPreview("data.csv", "text/csv", []byte("name,count\nfoo,1\n\"bar, baz\",2\n"), 0)
+	assert.Equal(t, PreviewKindCSV, preview.Kind)
+	require.Len(t, preview.Rows, 3)
+	assert.Equal(t, []string{"bar, baz", "2"}, preview.Rows[2])
+
+	// Content past the limit is truncated without splitting characters
+	preview = BuildAttachmentPreview("notes.txt", "text/plain", []byte(strings.Repeat("é", 10)), 5)
+	assert.True(t, preview.Truncated)
+	assert.Equal(t, "éé", preview.Content)
+
+	// Binary content is not previewed
+	preview = BuildAttachmentPreview("notes.txt", "text/plain", []byte("ab\x00cd"), 0)
+	assert.Equal(t, PreviewKindNone, preview.Kind)
+}
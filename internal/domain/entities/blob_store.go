@@ -0,0 +1,88 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BlobBackend identifies where attachment content is stored
+type BlobBackend string
+
+const (
+	// BlobBackendLocal stores content as plain files under .issuemap
+	BlobBackendLocal BlobBackend = "local"
+	// BlobBackendLFS stores git-LFS pointer files under .issuemap and the
+	// content in the repository's LFS object store (.git/lfs/objects)
+	BlobBackendLFS BlobBackend = "lfs"
+	// BlobBackendS3 stores content in an S3-compatible object store
+	BlobBackendS3 BlobBackend = "s3"
+)
+
+// ParseBlobBackend parses a blob backend name
+func ParseBlobBackend(value string) (BlobBackend, error) {
+	switch backend := BlobBackend(strings.ToLower(value)); backend {
+	case BlobBackendLocal, BlobBackendLFS, BlobBackendS3:
+		return backend, nil
+	}
+	return "", fmt.Errorf("unknown blob backend %q (local, lfs, s3)", value)
+}
+
+// BlobStoreConfig selects and configures the attachment blob backend
+type BlobStoreConfig struct {
+	Backend BlobBackend   `yaml:"backend" json:"backend"`
+	S3      *S3BlobConfig `yaml:"s3,omitempty" json:"s3,omitempty"`
+}
+
+// S3BlobConfig configures an S3-compatible object store. Credentials fall back
+// to the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables so
+// they need not be committed with the config.
+type S3BlobConfig struct {
+	Endpoint        string `yaml:"endpoint" json:"endpoint"`
+	Bucket          string `yaml:"bucket" json:"bucket"`
+	Region          string `yaml:"region,omitempty" json:"region,omitempty"`
+	Prefix          string `yaml:"prefix,omitempty" json:"prefix,omitempty"`
+	AccessKeyID     string `yaml:"access_key_id,omitempty" json:"access_key_id,omitempty"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty" json:"-"`
+}
+
+// DefaultBlobStoreConfig returns the default (local) blob store configuration
+func DefaultBlobStoreConfig() *BlobStoreConfig {
+	return &BlobStoreConfig{Backend: BlobBackendLocal}
+}
+
+// SplitStoragePath splits an attachment storage path into its backend and the
+// blob key. Local paths have no scheme; other backends use "<backend>:<key>".
+func SplitStoragePath(storagePath string) (BlobBackend, string) {
+	for _, backend := range []BlobBackend{BlobBackendLFS, BlobBackendS3} {
+		prefix := string(backend) + ":"
+		if strings.HasPrefix(storagePath, prefix) {
+			return backend, strings.TrimPrefix(storagePath, prefix)
+		}
+	}
+	return BlobBackendLocal, storagePath
+}
+
+// JoinStoragePath builds the storage path of a blob key in a backend
+func JoinStoragePath(backend BlobBackend, key string) string {
+	if backend == BlobBackendLocal || backend == "" {
+		return key
+	}
+	return string(backend) + ":" + key
+}
+
+// IsLocalStoragePath reports whether a storage path refers to a plain local file
+func IsLocalStoragePath(storagePath string) bool {
+	backend, _ := SplitStoragePath(storagePath)
+	return backend == BlobBackendLocal
+}
+
+// BlobMigrationResult reports the outcome of moving attachment blobs between backends
+type BlobMigrationResult struct {
+	To           BlobBackend       `json:"to"`
+	Blobs        int               `json:"blobs"`
+	Attachments  int               `json:"attachments"`
+	Bytes        int64             `json:"bytes"`
+	AlreadyThere int               `json:"already_there"`
+	Failed       map[string]string `json:"failed,omitempty"`
+	DryRun       bool              `json:"dry_run"`
+}
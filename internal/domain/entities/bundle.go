@@ -0,0 +1,168 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// BundleFormatVersion is the current version of the project bundle format
+const BundleFormatVersion = 1
+
+// BundleManifestName is the name of the manifest entry inside a bundle
+const BundleManifestName = "manifest.json"
+
+// BundleManifest describes the contents of a project bundle
+type BundleManifest struct {
+	// Version of the bundle format
+	FormatVersion int `json:"format_version"`
+
+	// When and by whom the bundle was created
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by,omitempty"`
+
+	// Source project information
+	Project  string `json:"project"`
+	IDPrefix string `json:"id_prefix,omitempty"`
+
+	// Hash algorithm used to key attachment blobs
+	HashAlgorithm string `json:"hash_algorithm"`
+
+	// Number of records of each kind in the bundle
+	Counts BundleCounts `json:"counts"`
+
+	// Attachment ID -> blob hash
+	Attachments map[string]string `json:"attachments"`
+
+	// Bundle entry path -> SHA-256 checksum of its content
+	Checksums map[string]string `json:"checksums"`
+}
+
+// BundleCounts holds the number of records of each kind in a bundle
+type BundleCounts struct {
+	Issues       int `json:"issues"`
+	Histories    int `json:"histories"`
+	Dependencies int `json:"dependencies"`
+	TimeEntries  int `json:"time_entries"`
+	Templates    int `json:"templates"`
+	Attachments  int `json:"attachments"`
+	Blobs        int `json:"blobs"`
+
+	// Issues among Issues whose sealed content is bundled encrypted
+	Confidential int `json:"confidential,omitempty"`
+}
+
+// NewBundleManifest creates an empty manifest for the current format version
+func NewBundleManifest(project, createdBy string) *BundleManifest {
+	return &BundleManifest{
+		FormatVersion: BundleFormatVersion,
+		CreatedAt:     time.Now(),
+		CreatedBy:     createdBy,
+		Project:       project,
+		Attachments:   make(map[string]string),
+		Checksums:     make(map[string]string),
+	}
+}
+
+// BundleImportOptions controls how a bundle is imported
+type BundleImportOptions struct {
+	// Replace the ID prefix of imported issues (e.g. OLD-001 -> NEW-001)
+	TargetPrefix string `json:"target_prefix,omitempty"`
+
+	// Replace existing records instead of skipping them
+	Overwrite bool `json:"overwrite"`
+
+	// Also import the project configuration
+	IncludeConfig bool `json:"include_config"`
+
+	// Preview the import without writing anything
+	DryRun bool `json:"dry_run"`
+}
+
+// BundleImportResult summarises the outcome of a bundle import
+type BundleImportResult struct {
+	Counts   BundleCounts        `json:"counts"`
+	Remapped map[IssueID]IssueID `json:"remapped,omitempty"`
+	Skipped  []string            `json:"skipped,omitempty"`
+	Warnings []string            `json:"warnings,omitempty"`
+	DryRun   bool                `json:"dry_run"`
+}
+
+// BundleVerifyResult reports the integrity of a bundle
+type BundleVerifyResult struct {
+	Valid    bool            `json:"valid"`
+	Manifest *BundleManifest `json:"manifest,omitempty"`
+	Errors   []string        `json:"errors,omitempty"`
+}
+
+// IssueIDMap maps issue IDs from a source project to a target project
+type IssueIDMap map[IssueID]IssueID
+
+// SplitIssueID splits an issue ID into its prefix and number parts
+func SplitIssueID(id IssueID) (string, string) {
+	s := string(id)
+	idx := strings.LastIndex(s, "-")
+	if idx <= 0 {
+		return "", s
+	}
+	return s[:idx], s[idx+1:]
+}
+
+// NewIssueIDMap builds a mapping that replaces the prefix of each ID with targetPrefix.
+// An empty targetPrefix produces an identity mapping.
+func NewIssueIDMap(ids []IssueID, targetPrefix string) IssueIDMap {
+	m := make(IssueIDMap, len(ids))
+	for _, id := range ids {
+		if targetPrefix == "" {
+			m[id] = id
+			continue
+		}
+		_, number := SplitIssueID(id)
+		m[id] = IssueID(targetPrefix + "-" + number)
+	}
+	return m
+}
+
+// Map returns the mapped ID, or the original ID if it is not part of the mapping
+func (m IssueIDMap) Map(id IssueID) IssueID {
+	if mapped, ok := m[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+// Changed reports whether the mapping renames any issue
+func (m IssueIDMap) Changed() bool {
+	for from, to := range m {
+		if from != to {
+			return true
+		}
+	}
+	return false
+}
+
+// RemapPrefixed rewrites keys derived from an issue ID, such as attachment
+// and time entry IDs of the form <issue-id>-<suffix>
+func (m IssueIDMap) RemapPrefixed(key string) string {
+	for from, to := range m {
+		if key == string(from) {
+			return string(to)
+		}
+		if strings.HasPrefix(key, string(from)+"-") {
+			return string(to) + strings.TrimPrefix(key, string(from))
+		}
+	}
+	return key
+}
+
+var issueIDTextPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]*-\d+\b`)
+
+// RemapText rewrites references to mapped issue IDs inside free text
+func (m IssueIDMap) RemapText(text string) string {
+	if !m.Changed() {
+		return text
+	}
+	return issueIDTextPattern.ReplaceAllStringFunc(text, func(ref string) string {
+		return string(m.Map(IssueID(ref)))
+	})
+}
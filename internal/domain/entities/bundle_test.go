@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitIssueID(t *testing.T) {
+	prefix, number := SplitIssueID("PROJ_A-012")
+	assert.Equal(t, "PROJ_A", prefix)
+	assert.Equal(t, "012", number)
+
+	prefix, number = SplitIssueID("42")
+	assert.Equal(t, "", prefix)
+	assert.Equal(t, "42", number)
+}
+
+func TestIssueIDMap(t *testing.T) {
+	ids := []IssueID{"OLD-001", "OLD-002"}
+
+	identity := NewIssueIDMap(ids, "")
+	assert.False(t, identity.Changed())
+	assert.Equal(t, IssueID("OLD-001"), identity.Map("OLD-001"))
+	assert.Equal(t, "see OLD-002", identity.RemapText("see OLD-002"))
+
+	m := NewIssueIDMap(ids, "NEW")
+	assert.True(t, m.Changed())
+	assert.Equal(t, IssueID("NEW-001"), m.Map("OLD-001"))
+	assert.Equal(t, IssueID("OTHER-009"), m.Map("OTHER-009"))
+	assert.Equal(t, "Blocked by NEW-002, see OTHER-009", m.RemapText("Blocked by OLD-002, see OTHER-009"))
+	assert.Equal(t, "NEW-001-att-123", m.RemapPrefixed("OLD-001-att-123"))
+	assert.Equal(t, "OLD-0011-x", m.RemapPrefixed("OLD-0011-x"))
+}
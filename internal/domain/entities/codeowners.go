@@ -0,0 +1,156 @@
+package entities
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeOwnersLocations are the paths, relative to the repository root, where
+// a CODEOWNERS file is looked up in order
+var CodeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// CodeOwnersRule is a line of a CODEOWNERS file
+type CodeOwnersRule struct {
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+	Line    int      `json:"line"`
+
+	matcher *regexp.Regexp
+}
+
+// CodeOwners is a parsed CODEOWNERS file
+type CodeOwners struct {
+	Rules []CodeOwnersRule `json:"rules"`
+}
+
+// ParseCodeOwners parses a CODEOWNERS file. Patterns follow the gitignore
+// syntax used by GitHub and GitLab; GitLab section headers are skipped.
+func ParseCodeOwners(data string) (*CodeOwners, error) {
+	owners := &CodeOwners{}
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		matcher, err := codeOwnersMatcher(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("CODEOWNERS line %d: invalid pattern %q: %w", lineNumber, fields[0], err)
+		}
+		owners.Rules = append(owners.Rules, CodeOwnersRule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			Line:    lineNumber,
+			matcher: matcher,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+// Match returns the rule that decides the owners of a path, relative to the
+// repository root. As in CODEOWNERS, the last matching rule wins. It returns
+// nil when no rule matches; a matching rule without owners means the path
+// has none.
+func (c *CodeOwners) Match(path string) *CodeOwnersRule {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "./"), "/")
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].matcher.MatchString(path) {
+			return &c.Rules[i]
+		}
+	}
+	return nil
+}
+
+// codeOwnersMatcher compiles a CODEOWNERS pattern. A pattern without a
+// slash other than a trailing one matches at any depth. Everything below a
+// matching directory matches too, unless the last segment has a wildcard:
+// docs/* matches the files directly in docs, not those in its subdirectories.
+func codeOwnersMatcher(pattern string) (*regexp.Regexp, error) {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	directory := strings.HasSuffix(pattern, "/")
+	pattern = strings.Trim(pattern, "/")
+	lastSegment := pattern[strings.LastIndex(pattern, "/")+1:]
+	recursive := directory || !strings.ContainsAny(lastSegment, "*?")
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	if !anchored {
+		expr.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(pattern[i])))
+		}
+	}
+	if recursive {
+		expr.WriteString("(?:/.*)?")
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// filePathPattern matches file paths mentioned in text and stack traces,
+// with an optional line and column suffix
+var filePathPattern = regexp.MustCompile(`(?:[A-Za-z]:)?[\w.@~+-]*(?:[/\\][\w.@~+-]+)+|[\w@+-][\w.@+-]*\.[A-Za-z][A-Za-z0-9]{0,7}\b`)
+
+// urlPattern matches URLs, which are not file paths
+var urlPattern = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://\S+`)
+
+// sourceExtensions are the extensions that make a bare file name, such as
+// main.go, count as a path
+var sourceExtensions = map[string]bool{
+	"c": true, "cc": true, "cpp": true, "cs": true, "css": true, "dart": true, "ex": true, "exs": true,
+	"go": true, "h": true, "hpp": true, "html": true, "java": true, "js": true, "json": true, "jsx": true,
+	"kt": true, "lua": true, "m": true, "md": true, "php": true, "pl": true, "proto": true, "py": true,
+	"rb": true, "rs": true, "scala": true, "scss": true, "sh": true, "sql": true, "swift": true, "tf": true,
+	"toml": true, "ts": true, "tsx": true, "vue": true, "xml": true, "yaml": true, "yml": true,
+}
+
+// ExtractFilePaths returns the file paths mentioned in text, such as a
+// description or stack trace, in order of appearance. Line numbers are
+// dropped; paths are returned as written, possibly absolute.
+func ExtractFilePaths(text string) []string {
+	text = urlPattern.ReplaceAllString(text, " ")
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, match := range filePathPattern.FindAllString(text, -1) {
+		path := strings.ReplaceAll(match, `\`, "/")
+		path = strings.TrimRight(path, ".,;:)'\"")
+		if !strings.Contains(path, "/") {
+			ext := path[strings.LastIndex(path, ".")+1:]
+			if !sourceExtensions[strings.ToLower(ext)] {
+				continue
+			}
+		}
+		if path == "" || strings.Trim(path, "./") == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+	return paths
+}
@@ -0,0 +1,104 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	owners, err := ParseCodeOwners(`# Default owners
+*                       @acme/core
+
+[Frontend]
+/web/                   @acme/web  # the web team
+*.md                    @carol docs@example.com
+/internal/**/parser/    @alice
+/vendor/
+`)
+	require.NoError(t, err)
+	require.Len(t, owners.Rules, 5)
+	assert.Equal(t, "/web/", owners.Rules[1].Pattern)
+	assert.Equal(t, []string{"@acme/web"}, owners.Rules[1].Owners)
+	assert.Equal(t, 5, owners.Rules[1].Line)
+	assert.Equal(t, []string{"@carol", "docs@example.com"}, owners.Rules[2].Owners)
+
+	tests := []struct {
+		path    string
+		pattern string
+	}{
+		{"main.go", "*"},
+		{"web/app.js", "/web/"},
+		{"web/src/deep/app.js", "/web/"},
+		{"cmd/web/app.js", "*"},
+		{"README.md", "*.md"},
+		{"web/README.md", "*.md"},
+		{"internal/parser/lexer.go", "/internal/**/parser/"},
+		{"internal/lang/go/parser/lexer.go", "/internal/**/parser/"},
+		{"./internal/parserx/lexer.go", "*"},
+		{"vendor/lib/lib.go", "/vendor/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rule := owners.Match(tt.path)
+			require.NotNil(t, rule)
+			assert.Equal(t, tt.pattern, rule.Pattern)
+		})
+	}
+
+	// A matching rule without owners removes ownership
+	assert.Empty(t, owners.Match("vendor/lib/lib.go").Owners)
+
+	empty, err := ParseCodeOwners("/docs/ @carol\n")
+	require.NoError(t, err)
+	assert.Nil(t, empty.Match("main.go"))
+}
+
+func TestCodeOwnersMatcher(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"docs/*", "docs/index.md", true},
+		{"docs/*", "docs/guide/setup.md", false},
+		{"docs/*", "src/docs/index.md", false},
+		{"/apps/", "apps/web/main.go", true},
+		{"/apps/", "apps/main.go", true},
+		{"/apps/", "services/apps/main.go", false},
+		{"**/logs", "logs", true},
+		{"**/logs", "logs/app.log", true},
+		{"**/logs", "build/logs/app.log", true},
+		{"**/logs", "build/logsx/app.log", false},
+		{"*.js", "web/app.js", true},
+		{"*.js", "web/app.js/index.html", false},
+		{"docs/**", "docs/guide/setup.md", true},
+		{"/build/logs/", "build/logs/2024/app.log", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			matcher, err := codeOwnersMatcher(tt.pattern)
+			require.NoError(t, err)
+			assert.Equal(t, tt.match, matcher.MatchString(tt.path), matcher.String())
+		})
+	}
+}
+
+func TestExtractFilePaths(t *testing.T) {
+	text := `The parser panics on empty input, see internal/parser/lexer.go:42.
+
+goroutine 1 [running]:
+main.parse(...)
+	/home/ci/build/issuemap/cmd/root.go:17 +0x1f
+Also touched README.md and web\src\app.ts, docs at https://example.com/docs/guide.md.
+Version 1.2.3 and e.g. are not files; neither is v2.0.`
+
+	assert.Equal(t, []string{
+		"internal/parser/lexer.go",
+		"/home/ci/build/issuemap/cmd/root.go",
+		"README.md",
+		"web/src/app.ts",
+	}, ExtractFilePaths(text))
+	assert.Empty(t, ExtractFilePaths("nothing to see here"))
+}
@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// CommitTimeOptions controls estimating time entries from commits
+type CommitTimeOptions struct {
+	SessionGap time.Duration `json:"session_gap"`
+	LeadIn     time.Duration `json:"lead_in"`
+	IssueID    *IssueID      `json:"issue_id,omitempty"`
+	Since      *time.Time    `json:"since,omitempty"`
+	Hashes     []string      `json:"hashes,omitempty"` // Only create entries for these commits
+	DryRun     bool          `json:"dry_run"`
+}
+
+// CommitTimeResult reports the outcome of estimating time from commits
+type CommitTimeResult struct {
+	Entries    []*TimeEntry  `json:"entries"`
+	Commits    int           `json:"commits"`
+	Existing   int           `json:"existing"`   // Already recorded for the commit and issue
+	Overlapped int           `json:"overlapped"` // Fully covered by timer or manual entries
+	TotalTime  time.Duration `json:"total_time"`
+	DryRun     bool          `json:"dry_run"`
+}
+
+// CommitMark is a commit reduced to what time estimation needs
+type CommitMark struct {
+	Hash   string    `json:"hash"`
+	Author string    `json:"author"`
+	Time   time.Time `json:"time"`
+}
+
+// CommitWindow is the working window attributed to a single commit
+type CommitWindow struct {
+	Commit       CommitMark `json:"commit"`
+	Start        time.Time  `json:"start"`
+	End          time.Time  `json:"end"`
+	FirstSession bool       `json:"first_in_session"`
+}
+
+// Duration returns the length of the window
+func (w CommitWindow) Duration() time.Duration {
+	return w.End.Sub(w.Start)
+}
+
+// EstimateCommitWindows applies the session gap heuristic to a set of commits.
+// Commits are grouped per author and ordered by time; a commit made within gap
+// of the author's previous commit is credited with the time since that commit,
+// otherwise it starts a new session and is credited with leadIn.
+func EstimateCommitWindows(commits []CommitMark, gap, leadIn time.Duration) []CommitWindow {
+	byAuthor := make(map[string][]CommitMark)
+	seen := make(map[string]bool)
+	for _, commit := range commits {
+		if seen[commit.Hash] {
+			continue
+		}
+		seen[commit.Hash] = true
+		byAuthor[commit.Author] = append(byAuthor[commit.Author], commit)
+	}
+
+	var windows []CommitWindow
+	for _, authorCommits := range byAuthor {
+		sort.Slice(authorCommits, func(i, j int) bool {
+			return authorCommits[i].Time.Before(authorCommits[j].Time)
+		})
+		for i, commit := range authorCommits {
+			window := CommitWindow{Commit: commit, End: commit.Time}
+			if i > 0 && commit.Time.Sub(authorCommits[i-1].Time) <= gap {
+				window.Start = authorCommits[i-1].Time
+			} else {
+				window.Start = commit.Time.Add(-leadIn)
+				window.FirstSession = true
+			}
+			windows = append(windows, window)
+		}
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].End.Before(windows[j].End)
+	})
+	return windows
+}
+
+// UncoveredDuration returns how much of [start, end) is not covered by any of
+// the given entries, so estimated time never double-counts tracked time
+func UncoveredDuration(start, end time.Time, entries []*TimeEntry) time.Duration {
+	if !end.After(start) {
+		return 0
+	}
+
+	type span struct{ from, to time.Time }
+	var covered []span
+	for _, entry := range entries {
+		from, to := entry.Interval()
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			covered = append(covered, span{from, to})
+		}
+	}
+	sort.Slice(covered, func(i, j int) bool {
+		return covered[i].from.Before(covered[j].from)
+	})
+
+	remaining := end.Sub(start)
+	var cursor time.Time
+	for _, s := range covered {
+		if s.from.Before(cursor) {
+			s.from = cursor
+		}
+		if s.to.After(s.from) {
+			remaining -= s.to.Sub(s.from)
+			cursor = s.to
+		}
+	}
+	return remaining
+}
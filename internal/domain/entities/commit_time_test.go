@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateCommitWindows(t *testing.T) {
+	base := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	commits := []CommitMark{
+		{Hash: "c3", Author: "alice", Time: base.Add(5 * time.Hour)},
+		{Hash: "c1", Author: "alice", Time: base},
+		{Hash: "c2", Author: "alice", Time: base.Add(45 * time.Minute)},
+		{Hash: "b1", Author: "bob", Time: base.Add(30 * time.Minute)},
+		{Hash: "c1", Author: "alice", Time: base},
+	}
+
+	windows := EstimateCommitWindows(commits, 2*time.Hour, 30*time.Minute)
+	require.Len(t, windows, 4)
+
+	byHash := make(map[string]CommitWindow)
+	for _, window := range windows {
+		byHash[window.Commit.Hash] = window
+	}
+
+	// First commit of a session gets the lead-in
+	assert.True(t, byHash["c1"].FirstSession)
+	assert.Equal(t, 30*time.Minute, byHash["c1"].Duration())
+
+	// Within the gap: time since the previous commit by the same author
+	assert.False(t, byHash["c2"].FirstSession)
+	assert.Equal(t, 45*time.Minute, byHash["c2"].Duration())
+
+	// Beyond the gap: a new session
+	assert.True(t, byHash["c3"].FirstSession)
+	assert.Equal(t, 30*time.Minute, byHash["c3"].Duration())
+
+	// Other authors do not affect the session
+	assert.True(t, byHash["b1"].FirstSession)
+}
+
+func TestUncoveredDuration(t *testing.T) {
+	start := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	timerEnd := start.Add(90 * time.Minute)
+	timer := &TimeEntry{Type: TimeEntryTypeTimer, StartTime: start.Add(30 * time.Minute), EndTime: &timerEnd}
+	manual := &TimeEntry{Type: TimeEntryTypeManual, StartTime: start.Add(time.Hour), Duration: time.Hour}
+
+	assert.Equal(t, 2*time.Hour, UncoveredDuration(start, end, nil))
+	assert.Equal(t, time.Hour, UncoveredDuration(start, end, []*TimeEntry{timer}))
+	// Overlapping entries are only subtracted once
+	assert.Equal(t, 30*time.Minute, UncoveredDuration(start, end, []*TimeEntry{timer, manual}))
+	assert.Equal(t, time.Duration(0), UncoveredDuration(end, start, nil))
+}
@@ -34,6 +34,102 @@ type CompressionConfig struct {
 
 	// Batch size for background compression
 	BackgroundBatchSize int `yaml:"background_batch_size" json:"background_batch_size"`
+
+	// Default codec: "gzip", "zstd" or "none"
+	Codec string `yaml:"codec,omitempty" json:"codec,omitempty"`
+
+	// Per content type and size codec choices; the first matching policy wins
+	Policies []CompressionPolicy `yaml:"policies,omitempty" json:"policies,omitempty"`
+}
+
+// Compression codec names
+const (
+	CompressionCodecGzip = "gzip"
+	CompressionCodecZstd = "zstd"
+	CompressionCodecNone = "none"
+)
+
+// CompressionPolicy selects a codec for attachments matching its content
+// types, extensions and size range. Empty criteria match everything.
+type CompressionPolicy struct {
+	// Content types to match; "text/*" matches a whole family
+	ContentTypes []string `yaml:"content_types,omitempty" json:"content_types,omitempty"`
+
+	// File extensions to match, including the dot
+	Extensions []string `yaml:"extensions,omitempty" json:"extensions,omitempty"`
+
+	// Size range to match in bytes; 0 means unbounded
+	MinSize int64 `yaml:"min_size,omitempty" json:"min_size,omitempty"`
+	MaxSize int64 `yaml:"max_size,omitempty" json:"max_size,omitempty"`
+
+	// Codec to use, and its level (0 uses the configured level)
+	Codec string `yaml:"codec" json:"codec"`
+	Level int    `yaml:"level,omitempty" json:"level,omitempty"`
+}
+
+// Matches reports whether the policy applies to an attachment
+func (p *CompressionPolicy) Matches(filename, contentType string, size int64) bool {
+	if p.MinSize > 0 && size < p.MinSize {
+		return false
+	}
+	if p.MaxSize > 0 && size > p.MaxSize {
+		return false
+	}
+
+	if len(p.Extensions) > 0 {
+		ext := strings.ToLower(filepath.Ext(filename))
+		matched := false
+		for _, candidate := range p.Extensions {
+			if strings.ToLower(candidate) == ext {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(p.ContentTypes) > 0 {
+		contentType = strings.ToLower(contentType)
+		if idx := strings.Index(contentType, ";"); idx != -1 {
+			contentType = strings.TrimSpace(contentType[:idx])
+		}
+		matched := false
+		for _, candidate := range p.ContentTypes {
+			candidate = strings.ToLower(candidate)
+			if candidate == contentType ||
+				(strings.HasSuffix(candidate, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(candidate, "*"))) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CodecFor returns the codec and level to use for an attachment
+func (c *CompressionConfig) CodecFor(filename, contentType string, size int64) (string, int) {
+	for i := range c.Policies {
+		policy := &c.Policies[i]
+		if policy.Matches(filename, contentType, size) {
+			level := policy.Level
+			if level == 0 {
+				level = c.Level
+			}
+			return policy.Codec, level
+		}
+	}
+
+	codec := c.Codec
+	if codec == "" {
+		codec = CompressionCodecGzip
+	}
+	return codec, c.Level
 }
 
 // DefaultCompressionConfig returns default compression settings
@@ -69,6 +165,7 @@ func DefaultCompressionConfig() *CompressionConfig {
 		},
 		BackgroundCompression: true,
 		BackgroundBatchSize:   10,
+		Codec:                 CompressionCodecGzip,
 	}
 }
 
@@ -117,7 +214,7 @@ type CompressionMetadata struct {
 	// Compression ratio (0.0-1.0, lower is better compression)
 	CompressionRatio float64 `json:"compression_ratio"`
 
-	// Compression algorithm used
+	// Compression algorithm used; empty means gzip, the original codec
 	Algorithm string `json:"algorithm"`
 
 	// Compression level used
@@ -133,6 +230,32 @@ type CompressionMetadata struct {
 	CompressedChecksum string `json:"compressed_checksum"`
 }
 
+// Codec returns the codec the attachment was compressed with
+func (m *CompressionMetadata) Codec() string {
+	if m.Algorithm == "" {
+		return CompressionCodecGzip
+	}
+	return m.Algorithm
+}
+
+// RecompressionResult summarizes converting attachments to another codec
+type RecompressionResult struct {
+	Codec        string            `json:"codec"`
+	DryRun       bool              `json:"dry_run"`
+	Files        int               `json:"files"`
+	Attachments  int               `json:"attachments"`
+	Skipped      int               `json:"skipped"`
+	BytesBefore  int64             `json:"bytes_before"`
+	BytesAfter   int64             `json:"bytes_after"`
+	Failed       map[string]string `json:"failed,omitempty"`
+	DurationSecs float64           `json:"duration_seconds"`
+}
+
+// BytesSaved returns the space saved by recompression; negative if it grew
+func (r *RecompressionResult) BytesSaved() int64 {
+	return r.BytesBefore - r.BytesAfter
+}
+
 // CompressionResult represents the result of a compression operation
 type CompressionResult struct {
 	// Whether compression was successful
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionConfig_CodecFor(t *testing.T) {
+	config := DefaultCompressionConfig()
+	config.Policies = []CompressionPolicy{
+		{ContentTypes: []string{"text/*"}, MinSize: 1024 * 1024, Codec: CompressionCodecZstd, Level: 9},
+		{Extensions: []string{".json"}, Codec: CompressionCodecZstd},
+		{ContentTypes: []string{"application/pdf"}, Codec: CompressionCodecNone},
+	}
+
+	codec, level := config.CodecFor("big.log", "text/plain; charset=utf-8", 5*1024*1024)
+	assert.Equal(t, CompressionCodecZstd, codec)
+	assert.Equal(t, 9, level)
+
+	// Small text falls through to the default codec and level
+	codec, level = config.CodecFor("small.log", "text/plain", 2048)
+	assert.Equal(t, CompressionCodecGzip, codec)
+	assert.Equal(t, config.Level, level)
+
+	codec, level = config.CodecFor("data.JSON", "application/json", 2048)
+	assert.Equal(t, CompressionCodecZstd, codec)
+	assert.Equal(t, config.Level, level)
+
+	codec, _ = config.CodecFor("doc.pdf", "application/pdf", 2048)
+	assert.Equal(t, CompressionCodecNone, codec)
+
+	// Configs written before codecs existed use gzip
+	config = &CompressionConfig{Level: 6}
+	codec, _ = config.CodecFor("a.txt", "text/plain", 10)
+	assert.Equal(t, CompressionCodecGzip, codec)
+}
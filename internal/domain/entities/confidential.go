@@ -0,0 +1,218 @@
+package entities
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ConfidentialPlaceholder replaces the sealed content of a confidential issue
+// for readers who do not hold a recipient key
+const ConfidentialPlaceholder = "[confidential]"
+
+// Fields of a confidential issue that can be sealed
+const (
+	SealedFieldTitle       = "title"
+	SealedFieldDescription = "description"
+	SealedFieldComments    = "comments"
+	SealedFieldStatus      = "status"
+)
+
+// ConfidentialConfig lists the recipients confidential issues are encrypted to
+type ConfidentialConfig struct {
+	// Recipients whose public keys can decrypt confidential issues
+	Recipients []ConfidentialRecipient `yaml:"recipients" json:"recipients"`
+
+	// Descriptions, comments and attachment contents are always sealed.
+	// Titles and statuses stay in clear for triage unless these are set.
+	SealTitle  bool `yaml:"seal_title,omitempty" json:"seal_title,omitempty"`
+	SealStatus bool `yaml:"seal_status,omitempty" json:"seal_status,omitempty"`
+}
+
+// ConfidentialRecipient is a person or machine that can read confidential
+// issues
+type ConfidentialRecipient struct {
+	Name      string `yaml:"name" json:"name"`
+	PublicKey string `yaml:"public_key" json:"public_key"`
+}
+
+// SealedFields returns the issue fields encrypted under this configuration
+func (c *ConfidentialConfig) SealedFields() []string {
+	fields := []string{SealedFieldDescription, SealedFieldComments}
+	if c.SealTitle {
+		fields = append(fields, SealedFieldTitle)
+	}
+	if c.SealStatus {
+		fields = append(fields, SealedFieldStatus)
+	}
+	return fields
+}
+
+// FindRecipient returns the recipient with the given name
+func (c *ConfidentialConfig) FindRecipient(name string) (*ConfidentialRecipient, bool) {
+	for i := range c.Recipients {
+		if c.Recipients[i].Name == name {
+			return &c.Recipients[i], true
+		}
+	}
+	return nil, false
+}
+
+// SealedContent holds encrypted content and its content key wrapped for each
+// recipient. Issues keep the sealed fields in Ciphertext; attachments keep
+// their encrypted content in the stored file.
+type SealedContent struct {
+	Fields     []string          `yaml:"fields,omitempty" json:"fields,omitempty"`
+	Recipients []SealedRecipient `yaml:"recipients" json:"recipients"`
+	Ciphertext string            `yaml:"ciphertext,omitempty" json:"ciphertext,omitempty"`
+}
+
+// SealedRecipient is the content key wrapped for one recipient's public key
+type SealedRecipient struct {
+	Name       string `yaml:"name,omitempty" json:"name,omitempty"`
+	KeyID      string `yaml:"key_id" json:"key_id"`
+	Ephemeral  string `yaml:"ephemeral" json:"ephemeral"`
+	WrappedKey string `yaml:"wrapped_key" json:"wrapped_key"`
+}
+
+// HasField reports whether a field is sealed
+func (s *SealedContent) HasField(field string) bool {
+	for _, f := range s.Fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfidentialFields are the sealed fields of an issue, encrypted together
+type ConfidentialFields struct {
+	Title       *string   `yaml:"title,omitempty"`
+	Description *string   `yaml:"description,omitempty"`
+	Comments    []Comment `yaml:"comments,omitempty"`
+	Status      *Status   `yaml:"status,omitempty"`
+}
+
+// IsSealedField reports whether a field of a confidential issue is kept
+// encrypted. Issues that have not been saved yet treat every sealable field
+// as sealed.
+func (i *Issue) IsSealedField(field string) bool {
+	if !i.Confidential {
+		return false
+	}
+	if i.Sealed == nil {
+		return true
+	}
+	return i.Sealed.HasField(field)
+}
+
+// ExtractSealedFields returns the values of the given fields
+func (i *Issue) ExtractSealedFields(fields []string) *ConfidentialFields {
+	sealed := &ConfidentialFields{}
+	for _, field := range fields {
+		switch field {
+		case SealedFieldTitle:
+			title := i.Title
+			sealed.Title = &title
+		case SealedFieldDescription:
+			description := i.Description
+			sealed.Description = &description
+		case SealedFieldComments:
+			sealed.Comments = append([]Comment{}, i.Comments...)
+		case SealedFieldStatus:
+			status := i.Status
+			sealed.Status = &status
+		}
+	}
+	return sealed
+}
+
+// ApplySealedFields restores decrypted field values
+func (i *Issue) ApplySealedFields(fields []string, sealed *ConfidentialFields) {
+	for _, field := range fields {
+		switch field {
+		case SealedFieldTitle:
+			if sealed.Title != nil {
+				i.Title = *sealed.Title
+			}
+		case SealedFieldDescription:
+			if sealed.Description != nil {
+				i.Description = *sealed.Description
+			}
+		case SealedFieldComments:
+			i.Comments = append([]Comment{}, sealed.Comments...)
+		case SealedFieldStatus:
+			if sealed.Status != nil {
+				i.Status = *sealed.Status
+			}
+		}
+	}
+}
+
+// ClearSealedFields blanks the given fields so they are not written in
+// clear. A sealed title or status is replaced by the placeholder so the
+// issue remains valid.
+func (i *Issue) ClearSealedFields(fields []string) {
+	for _, field := range fields {
+		switch field {
+		case SealedFieldTitle:
+			i.Title = ConfidentialPlaceholder
+		case SealedFieldDescription:
+			i.Description = ""
+		case SealedFieldComments:
+			i.Comments = []Comment{}
+		case SealedFieldStatus:
+			i.Status = Status(ConfidentialPlaceholder)
+		}
+	}
+}
+
+// Redact shows placeholders in place of sealed content for readers who
+// cannot decrypt it
+func (i *Issue) Redact() {
+	if i.Sealed == nil {
+		return
+	}
+	i.ClearSealedFields(i.Sealed.Fields)
+	if i.Sealed.HasField(SealedFieldDescription) {
+		i.Description = ConfidentialPlaceholder
+	}
+	i.Redacted = true
+}
+
+// SealedContentChanged reports whether a redacted issue has been edited in a
+// field its reader could not see
+func (i *Issue) SealedContentChanged() bool {
+	if !i.Redacted || i.Sealed == nil {
+		return false
+	}
+	for _, field := range i.Sealed.Fields {
+		switch field {
+		case SealedFieldTitle:
+			if i.Title != ConfidentialPlaceholder {
+				return true
+			}
+		case SealedFieldDescription:
+			if i.Description != ConfidentialPlaceholder {
+				return true
+			}
+		case SealedFieldComments:
+			if len(i.Comments) > 0 {
+				return true
+			}
+		case SealedFieldStatus:
+			if i.Status != Status(ConfidentialPlaceholder) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetIdentityPath returns the path of the private key used to read
+// confidential issues. ISSUEMAP_IDENTITY overrides the default location.
+func GetIdentityPath() string {
+	if path := os.Getenv("ISSUEMAP_IDENTITY"); path != "" {
+		return path
+	}
+	return filepath.Join(GetGlobalDir(), "identity.key")
+}
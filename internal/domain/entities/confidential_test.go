@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfidentialConfig_SealedFields(t *testing.T) {
+	config := &ConfidentialConfig{}
+	assert.Equal(t, []string{SealedFieldDescription, SealedFieldComments}, config.SealedFields())
+
+	config.SealTitle = true
+	config.SealStatus = true
+	assert.Equal(t, []string{SealedFieldDescription, SealedFieldComments, SealedFieldTitle, SealedFieldStatus}, config.SealedFields())
+}
+
+func TestIssue_IsSealedField(t *testing.T) {
+	issue := NewIssue("TEST-001", "Leak", "details", IssueTypeBug)
+	assert.False(t, issue.IsSealedField(SealedFieldDescription))
+
+	// Before the first save every sealable field is treated as sealed
+	issue.Confidential = true
+	assert.True(t, issue.IsSealedField(SealedFieldTitle))
+
+	issue.Sealed = &SealedContent{Fields: []string{SealedFieldDescription, SealedFieldComments}}
+	assert.True(t, issue.IsSealedField(SealedFieldDescription))
+	assert.False(t, issue.IsSealedField(SealedFieldTitle))
+}
+
+func TestIssue_RedactAndDetectChanges(t *testing.T) {
+	issue := NewIssue("TEST-001", "Leak", "details", IssueTypeBug)
+	issue.AddComment("alice", "secret comment")
+	issue.Confidential = true
+	issue.Sealed = &SealedContent{Fields: []string{SealedFieldDescription, SealedFieldComments, SealedFieldStatus}}
+
+	issue.Redact()
+	assert.True(t, issue.Redacted)
+	assert.Equal(t, "Leak", issue.Title)
+	assert.Equal(t, ConfidentialPlaceholder, issue.Description)
+	assert.Empty(t, issue.Comments)
+	assert.Equal(t, Status(ConfidentialPlaceholder), issue.Status)
+	assert.NoError(t, issue.Validate())
+	assert.False(t, issue.SealedContentChanged())
+
+	// Clear fields may still be edited
+	issue.Title = "Renamed"
+	issue.AddLabel(Label{Name: "security"})
+	assert.False(t, issue.SealedContentChanged())
+
+	issue.AddComment("bob", "cannot see the rest")
+	assert.True(t, issue.SealedContentChanged())
+}
+
+func TestIssue_SealedFieldsRoundTrip(t *testing.T) {
+	issue := NewIssue("TEST-001", "Leak", "details", IssueTypeBug)
+	issue.AddComment("alice", "secret comment")
+	fields := []string{SealedFieldTitle, SealedFieldDescription, SealedFieldComments}
+
+	sealed := issue.ExtractSealedFields(fields)
+	issue.ClearSealedFields(fields)
+	assert.Equal(t, ConfidentialPlaceholder, issue.Title)
+	assert.Empty(t, issue.Description)
+
+	issue.ApplySealedFields(fields, sealed)
+	assert.Equal(t, "Leak", issue.Title)
+	assert.Equal(t, "details", issue.Description)
+	assert.Len(t, issue.Comments, 1)
+}
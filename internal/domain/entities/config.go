@@ -2,16 +2,27 @@ package entities
 
 // Config represents the project configuration
 type Config struct {
-	Project       ProjectConfig     `yaml:"project" json:"project"`
-	Workflow      WorkflowConfig    `yaml:"workflow" json:"workflow"`
-	Templates     TemplatesConfig   `yaml:"templates" json:"templates"`
-	Labels        []Label           `yaml:"labels" json:"labels"`
-	Milestones    []Milestone       `yaml:"milestones" json:"milestones"`
-	Git           GitConfig         `yaml:"git" json:"git"`
-	UI            UIConfig          `yaml:"ui" json:"ui"`
-	SavedSearches map[string]string `yaml:"saved_searches,omitempty" json:"saved_searches,omitempty"`
-	StorageConfig *StorageConfig    `yaml:"storage,omitempty" json:"storage,omitempty"`
-	ArchiveConfig *ArchiveConfig    `yaml:"archive,omitempty" json:"archive,omitempty"`
+	// SchemaVersion is the format version the file was written in
+	SchemaVersion int `yaml:"schema_version,omitempty" json:"schema_version,omitempty"`
+
+	Project       ProjectConfig       `yaml:"project" json:"project"`
+	Workflow      WorkflowConfig      `yaml:"workflow" json:"workflow"`
+	Templates     TemplatesConfig     `yaml:"templates" json:"templates"`
+	Labels        []Label             `yaml:"labels" json:"labels"`
+	Milestones    []Milestone         `yaml:"milestones" json:"milestones"`
+	Git           GitConfig           `yaml:"git" json:"git"`
+	UI            UIConfig            `yaml:"ui" json:"ui"`
+	SavedSearches map[string]string   `yaml:"saved_searches,omitempty" json:"saved_searches,omitempty"`
+	StorageConfig *StorageConfig      `yaml:"storage,omitempty" json:"storage,omitempty"`
+	ArchiveConfig *ArchiveConfig      `yaml:"archive,omitempty" json:"archive,omitempty"`
+	TimeTracking  *TimeTrackingConfig `yaml:"time_tracking,omitempty" json:"time_tracking,omitempty"`
+	Jobs          *JobsConfig         `yaml:"jobs,omitempty" json:"jobs,omitempty"`
+	Stale         *StaleConfig        `yaml:"stale,omitempty" json:"stale,omitempty"`
+	Confidential  *ConfidentialConfig `yaml:"confidential,omitempty" json:"confidential,omitempty"`
+	Release       *ReleaseConfig      `yaml:"release,omitempty" json:"release,omitempty"`
+	Owners        *OwnersConfig       `yaml:"owners,omitempty" json:"owners,omitempty"`
+	Users         []UserProfile       `yaml:"users,omitempty" json:"users,omitempty"`
+	Digest        *DigestConfig       `yaml:"digest,omitempty" json:"digest,omitempty"`
 }
 
 // ProjectConfig contains project-specific settings
@@ -39,6 +50,9 @@ type GitConfig struct {
 	AutoCloseKeywords   []string     `yaml:"auto_close_keywords" json:"auto_close_keywords"`
 	DefaultBranchPrefix string       `yaml:"default_branch_prefix" json:"default_branch_prefix"`
 	BranchConfig        BranchConfig `yaml:"branch_config" json:"branch_config"`
+
+	// PullRequest configures 'issuemap pr'
+	PullRequest *PullRequestConfig `yaml:"pull_request,omitempty" json:"pull_request,omitempty"`
 }
 
 // BranchConfig contains branch naming and management settings
@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed five-field cron expression (minute, hour, day of
+// month, month, day of week). Fields accept *, single values, ranges (1-5),
+// steps (*/15, 10-40/10, 5/20), comma-separated lists and month/day names.
+// The macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly are also accepted.
+type CronSchedule struct {
+	Expression string
+
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record an unrestricted field. As in standard cron, when
+	// both day fields are restricted a time matches if either one does.
+	domAny, dowAny bool
+}
+
+// cronMaxLookahead bounds the search for the next matching time so that
+// expressions which can never match (e.g. 30 February) terminate
+const cronMaxLookahead = 5 * 366 * 24 * time.Hour
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinuteField = cronField{name: "minute", min: 0, max: 59}
+	cronHourField   = cronField{name: "hour", min: 0, max: 23}
+	cronDomField    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonthField  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day of week allows 7 as an alias for Sunday
+	cronDowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// ParseCronSchedule parses a cron expression or macro
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	expr := strings.TrimSpace(expression)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	} else if strings.HasPrefix(expr, "@") {
+		return nil, fmt.Errorf("unknown cron macro %q", expression)
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields, got %d", expression, len(fields))
+	}
+
+	schedule := &CronSchedule{Expression: strings.TrimSpace(expression)}
+	var err error
+	if schedule.minute, _, err = cronMinuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hour, _, err = cronHourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.dom, schedule.domAny, err = cronDomField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.month, _, err = cronMonthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.dow, schedule.dowAny, err = cronDowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// Fold 7 (Sunday) onto 0
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+		schedule.dow &^= 1 << 7
+	}
+
+	return schedule, nil
+}
+
+// parse converts one field to a bitset of allowed values. The boolean reports
+// whether the field was an unrestricted "*".
+func (f cronField) parse(field string) (uint64, bool, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		if part == "" {
+			return 0, false, fmt.Errorf("empty %s value in %q", f.name, field)
+		}
+
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			rangePart = part[:i]
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, false, fmt.Errorf("invalid %s step in %q", f.name, part)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			lo, hi = f.min, f.max
+			if f.max == 7 {
+				hi = 6
+			}
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = f.value(bounds[0]); err != nil {
+				return 0, false, err
+			}
+			if hi, err = f.value(bounds[1]); err != nil {
+				return 0, false, err
+			}
+			if lo > hi {
+				return 0, false, fmt.Errorf("invalid %s range %q", f.name, rangePart)
+			}
+		default:
+			var err error
+			if lo, err = f.value(rangePart); err != nil {
+				return 0, false, err
+			}
+			hi = lo
+			// "5/20" means every 20 starting at 5
+			if strings.Contains(part, "/") {
+				hi = f.max
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+
+	return bits, field == "*" || field == "?", nil
+}
+
+// value parses a single number or name within the field's bounds
+func (f cronField) value(s string) (int, error) {
+	if n, ok := f.names[strings.ToLower(s)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s value %q", f.name, s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%s value %d out of range %d-%d", f.name, n, f.min, f.max)
+	}
+	return n, nil
+}
+
+// Matches reports whether t, truncated to the minute, is a scheduled time
+func (c *CronSchedule) Matches(t time.Time) bool {
+	return c.minute&(1<<uint(t.Minute())) != 0 &&
+		c.hour&(1<<uint(t.Hour())) != 0 &&
+		c.month&(1<<uint(t.Month())) != 0 &&
+		c.dayMatches(t)
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the first scheduled time strictly after t, or the zero time if
+// the expression never matches
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	next := t.Truncate(time.Minute).Add(time.Minute)
+	limit := next.Add(cronMaxLookahead)
+
+	for next.Before(limit) {
+		if c.month&(1<<uint(next.Month())) == 0 {
+			next = time.Date(next.Year(), next.Month()+1, 1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if !c.dayMatches(next) {
+			next = time.Date(next.Year(), next.Month(), next.Day()+1, 0, 0, 0, 0, next.Location())
+			continue
+		}
+		if c.hour&(1<<uint(next.Hour())) == 0 {
+			next = time.Date(next.Year(), next.Month(), next.Day(), next.Hour()+1, 0, 0, 0, next.Location())
+			continue
+		}
+		if c.minute&(1<<uint(next.Minute())) == 0 {
+			next = next.Add(time.Minute)
+			continue
+		}
+		return next
+	}
+
+	return time.Time{}
+}
+
+// String returns the original expression
+func (c *CronSchedule) String() string {
+	return c.Expression
+}
@@ -0,0 +1,94 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cronTime(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	tests := []struct {
+		expression string
+		after      string
+		want       string
+	}{
+		{"0 2 * * *", "2024-03-10 01:30", "2024-03-10 02:00"},
+		{"0 2 * * *", "2024-03-10 02:00", "2024-03-11 02:00"},
+		{"*/15 * * * *", "2024-03-10 10:07", "2024-03-10 10:15"},
+		{"0 */6 * * *", "2024-03-10 07:00", "2024-03-10 12:00"},
+		{"10-40/10 9 * * *", "2024-03-10 09:25", "2024-03-10 09:30"},
+		{"5/20 * * * *", "2024-03-10 09:46", "2024-03-10 10:05"},
+		{"0 9 * * mon-fri", "2024-03-08 10:00", "2024-03-11 09:00"}, // Friday to Monday
+		{"0 0 1,15 * *", "2024-03-02 00:00", "2024-03-15 00:00"},
+		{"0 0 * FEB *", "2024-03-02 00:00", "2025-02-01 00:00"},
+		{"0 0 29 2 *", "2024-03-01 00:00", "2028-02-29 00:00"},
+		{"0 0 * * 7", "2024-03-10 00:00", "2024-03-17 00:00"}, // 7 is Sunday
+		{"@daily", "2024-03-10 13:00", "2024-03-11 00:00"},
+		{"@hourly", "2024-03-10 13:00", "2024-03-10 14:00"},
+		{"@weekly", "2024-03-10 13:00", "2024-03-17 00:00"},
+		{"@monthly", "2024-12-10 13:00", "2025-01-01 00:00"},
+		{"@yearly", "2024-12-10 13:00", "2025-01-01 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression+" after "+tt.after, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+			require.NoError(t, err)
+
+			next := schedule.Next(cronTime(tt.after))
+			assert.Equal(t, cronTime(tt.want), next)
+			assert.True(t, schedule.Matches(next))
+		})
+	}
+}
+
+func TestCronSchedule_DayOfMonthOrDayOfWeek(t *testing.T) {
+	// With both day fields restricted, either one matching is enough
+	schedule, err := ParseCronSchedule("0 0 13 * fri")
+	require.NoError(t, err)
+
+	assert.True(t, schedule.Matches(cronTime("2024-03-13 00:00")))  // Wednesday the 13th
+	assert.True(t, schedule.Matches(cronTime("2024-03-15 00:00")))  // Friday
+	assert.False(t, schedule.Matches(cronTime("2024-03-14 00:00"))) // Thursday
+
+	// With only one restricted, the other must not widen the match
+	schedule, err = ParseCronSchedule("0 0 13 * *")
+	require.NoError(t, err)
+	assert.False(t, schedule.Matches(cronTime("2024-03-15 00:00")))
+}
+
+func TestCronSchedule_NeverMatches(t *testing.T) {
+	schedule, err := ParseCronSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(cronTime("2024-01-01 00:00")).IsZero())
+}
+
+func TestParseCronSchedule_Invalid(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"1,,2 * * * *",
+		"@fortnightly",
+	} {
+		_, err := ParseCronSchedule(expression)
+		assert.Error(t, err, expression)
+	}
+}
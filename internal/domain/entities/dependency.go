@@ -24,6 +24,9 @@ const (
 
 // Dependency represents a dependency relationship between two issues
 type Dependency struct {
+	// SchemaVersion is the format version the file was written in
+	SchemaVersion int `yaml:"schema_version,omitempty" json:"schema_version,omitempty"`
+
 	ID          string           `yaml:"id" json:"id"`
 	SourceID    IssueID          `yaml:"source_id" json:"source_id"`       // The issue that has the dependency
 	TargetID    IssueID          `yaml:"target_id" json:"target_id"`       // The issue being depended upon
@@ -0,0 +1,85 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DigestConfig configures the notification digest emails
+type DigestConfig struct {
+	// Enable the scheduled digest; 'issuemap jobs run digest' always sends it
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// From is the sender address of digest emails
+	From string `yaml:"from" json:"from"`
+
+	// Subject of digest emails; %d is replaced with the notification count
+	Subject string `yaml:"subject" json:"subject"`
+
+	SMTP DigestSMTPConfig `yaml:"smtp" json:"smtp"`
+}
+
+// DigestSMTPConfig is the mail server digests are sent through
+type DigestSMTPConfig struct {
+	Host     string `yaml:"host" json:"host"`
+	Port     int    `yaml:"port" json:"port"`
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+
+	// Password of the mail account; ISSUEMAP_SMTP_PASSWORD is used when empty
+	Password string `yaml:"password,omitempty" json:"password,omitempty"`
+}
+
+// DefaultDigestConfig returns the default digest configuration
+func DefaultDigestConfig() *DigestConfig {
+	return &DigestConfig{
+		Enabled: false,
+		Subject: "IssueMap: %d new notifications",
+		SMTP: DigestSMTPConfig{
+			Port: 587,
+		},
+	}
+}
+
+// NotificationDigest collects the notifications of one person that have not
+// been sent in a digest yet
+type NotificationDigest struct {
+	Recipient     string               `json:"recipient"`
+	Email         string               `json:"email"`
+	Notifications []*IssueNotification `json:"notifications"`
+}
+
+// Body renders the digest as plain text, oldest notification first. Like
+// the notifications it lists, it carries no issue content.
+func (d *NotificationDigest) Body() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nThere has been activity on issues you watch:\n\n", d.Recipient)
+	for i := len(d.Notifications) - 1; i >= 0; i-- {
+		notification := d.Notifications[i]
+		fmt.Fprintf(&b, "  %s  %s\n", notification.CreatedAt.Format("2006-01-02 15:04"), notification.Summary())
+	}
+	b.WriteString("\nRun 'issuemap notifications' to see them and 'issuemap notifications --mark-read' to clear them.\n")
+	return b.String()
+}
+
+// DigestResult summarises a digest run
+type DigestResult struct {
+	Timestamp time.Time `json:"timestamp"`
+
+	// Digests that were sent
+	Digests []*NotificationDigest `json:"digests"`
+
+	// People with pending notifications but no email in the user directory
+	NoEmail []string `json:"no_email,omitempty"`
+
+	Errors []string `json:"errors,omitempty"`
+}
+
+// NotificationCount returns the number of notifications across all digests
+func (r *DigestResult) NotificationCount() int {
+	count := 0
+	for _, digest := range r.Digests {
+		count += len(digest.Notifications)
+	}
+	return count
+}
@@ -0,0 +1,26 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationDigest_Body(t *testing.T) {
+	older := NewIssueNotification("TEST-001", "carol", "jdoe", NotificationMentioned)
+	older.CreatedAt = time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	newer := NewIssueNotification("TEST-002", "carol", "robert", NotificationStatusChanged)
+	newer.Status = StatusDone
+	newer.CreatedAt = time.Date(2025, 3, 2, 14, 0, 0, 0, time.UTC)
+
+	// Notifications are listed newest first and rendered oldest first
+	digest := &NotificationDigest{Recipient: "carol", Email: "carol@corp.example", Notifications: []*IssueNotification{newer, older}}
+	body := digest.Body()
+
+	assert.True(t, strings.HasPrefix(body, "Hi carol,"))
+	first := strings.Index(body, "2025-03-01 09:30  jdoe mentioned you on TEST-001")
+	second := strings.Index(body, "2025-03-02 14:00  robert moved TEST-002 to done")
+	assert.True(t, first > 0 && second > first, body)
+}
@@ -0,0 +1,62 @@
+package entities
+
+import "fmt"
+
+// CurrentFormatVersion is the version of the .issuemap directory layout this
+// build writes. It is recorded in metadata/version and raised by every
+// registered format migration; repositories without the marker predate it
+// and are at version 0.
+const CurrentFormatVersion = 1
+
+// FormatVersionError is returned when a repository was migrated by a newer
+// issuemap than the one running
+type FormatVersionError struct {
+	Version   int
+	Supported int
+}
+
+func (e *FormatVersionError) Error() string {
+	return fmt.Sprintf("this repository uses issuemap data format version %d but this issuemap supports up to version %d; "+
+		"upgrade issuemap (go install github.com/ooyeku/issuemap@latest) or run 'issuemap migrate down --to %d' with the newer release",
+		e.Version, e.Supported, e.Supported)
+}
+
+// Directions a format migration can be applied in
+const (
+	FormatMigrationUp   = "up"
+	FormatMigrationDown = "down"
+)
+
+// FormatMigrationInfo describes a registered format migration
+type FormatMigrationInfo struct {
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	Applied     bool   `json:"applied"`
+	Reversible  bool   `json:"reversible"`
+}
+
+// FormatStatus describes the format version of a repository and the
+// migrations known to this build
+type FormatStatus struct {
+	Version    int                   `json:"version"`
+	Latest     int                   `json:"latest"`
+	Pending    int                   `json:"pending"`
+	Migrations []FormatMigrationInfo `json:"migrations"`
+}
+
+// FormatMigrationStep records one migration applied, or planned in a dry
+// run, and the files it changed
+type FormatMigrationStep struct {
+	Version     int      `json:"version"`
+	Description string   `json:"description"`
+	Direction   string   `json:"direction"`
+	Changes     []string `json:"changes"`
+}
+
+// FormatMigrationResult summarises a migrate up or down run
+type FormatMigrationResult struct {
+	From   int                   `json:"from"`
+	To     int                   `json:"to"`
+	DryRun bool                  `json:"dry_run"`
+	Steps  []FormatMigrationStep `json:"steps"`
+}
@@ -0,0 +1,155 @@
+package entities
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GitScanCursor records where the last commit scan stopped: the commit every
+// ref pointed to. Later scans skip commits reachable from these tips.
+type GitScanCursor struct {
+	ScannedAt time.Time         `yaml:"scanned_at" json:"scanned_at"`
+	Tips      map[string]string `yaml:"tips" json:"tips"`
+}
+
+// GitScanLink is a commit found for an issue by a scan
+type GitScanLink struct {
+	IssueID IssueID `json:"issue_id"`
+	Hash    string  `json:"hash"`
+	Message string  `json:"message"`
+	// Source is "message" for references in the commit message and
+	// "branch" for commits on a branch named after the issue
+	Source string `json:"source"`
+}
+
+// GitScanUnknownRef is a reference to an issue that does not exist
+type GitScanUnknownRef struct {
+	Ref     string   `json:"ref"`
+	Commits []string `json:"commits"`
+}
+
+// GitScanResult summarises a commit scan
+type GitScanResult struct {
+	Since       string              `json:"since,omitempty"`
+	Incremental bool                `json:"incremental"`
+	DryRun      bool                `json:"dry_run"`
+	Commits     int                 `json:"commits"`
+	Links       []GitScanLink       `json:"links"`
+	Issues      int                 `json:"issues"`
+	Unknown     []GitScanUnknownRef `json:"unknown,omitempty"`
+}
+
+// issueRefPattern splits an issue reference into its project prefix and
+// number
+var issueRefPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)-(\d+)$`)
+
+// IssueReferenceResolver maps the references found in commit messages and
+// branch names to existing issues
+type IssueReferenceResolver struct {
+	ids      map[IssueID]bool
+	byNumber map[string]map[int]IssueID
+	numbers  map[int][]IssueID
+}
+
+// NewIssueReferenceResolver creates a resolver for the given issues
+func NewIssueReferenceResolver(ids []IssueID) *IssueReferenceResolver {
+	r := &IssueReferenceResolver{
+		ids:      make(map[IssueID]bool, len(ids)),
+		byNumber: make(map[string]map[int]IssueID),
+		numbers:  make(map[int][]IssueID),
+	}
+	for _, id := range ids {
+		r.ids[id] = true
+		prefix, number, ok := splitIssueRef(string(id))
+		if !ok {
+			continue
+		}
+		if r.byNumber[prefix] == nil {
+			r.byNumber[prefix] = make(map[int]IssueID)
+		}
+		r.byNumber[prefix][number] = id
+		r.numbers[number] = append(r.numbers[number], id)
+	}
+	return r
+}
+
+// Resolve returns the issue a reference names. PROJECT-7 matches PROJECT-007,
+// and #7 matches the only issue numbered 7. known reports whether the
+// reference looks like an issue of this repository, so that an unresolved
+// reference is worth reporting; references such as UTF-8 are not.
+func (r *IssueReferenceResolver) Resolve(ref string) (id IssueID, found, known bool) {
+	ref = strings.TrimSpace(ref)
+	if r.ids[IssueID(ref)] {
+		return IssueID(ref), true, true
+	}
+
+	if strings.HasPrefix(ref, "#") {
+		number, err := strconv.Atoi(ref[1:])
+		if err != nil {
+			return "", false, false
+		}
+		if matches := r.numbers[number]; len(matches) == 1 {
+			return matches[0], true, true
+		}
+		return "", false, false
+	}
+
+	prefix, number, ok := splitIssueRef(strings.ToUpper(ref))
+	if !ok {
+		return "", false, false
+	}
+	numbers, known := r.byNumber[prefix]
+	if !known {
+		return "", false, false
+	}
+	if id, found := numbers[number]; found {
+		return id, true, true
+	}
+	return "", false, true
+}
+
+func splitIssueRef(ref string) (string, int, bool) {
+	match := issueRefPattern.FindStringSubmatch(ref)
+	if match == nil {
+		return "", 0, false
+	}
+	number, err := strconv.Atoi(match[2])
+	if err != nil {
+		return "", 0, false
+	}
+	return match[1], number, true
+}
+
+// AddUnknownRef records a commit referencing an issue that does not exist
+func (r *GitScanResult) AddUnknownRef(ref, hash string) {
+	for i := range r.Unknown {
+		if r.Unknown[i].Ref != ref {
+			continue
+		}
+		for _, existing := range r.Unknown[i].Commits {
+			if existing == hash {
+				return
+			}
+		}
+		r.Unknown[i].Commits = append(r.Unknown[i].Commits, hash)
+		return
+	}
+	r.Unknown = append(r.Unknown, GitScanUnknownRef{Ref: ref, Commits: []string{hash}})
+	sort.Slice(r.Unknown, func(i, j int) bool {
+		return r.Unknown[i].Ref < r.Unknown[j].Ref
+	})
+}
+
+// HasCommit reports whether the issue already links the commit. Linked
+// hashes may be abbreviated.
+func (i *Issue) HasCommit(hash string) bool {
+	for _, commit := range i.Commits {
+		if commit.Hash != "" && (commit.Hash == hash || strings.HasPrefix(hash, commit.Hash)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueReferenceResolver(t *testing.T) {
+	resolver := NewIssueReferenceResolver([]IssueID{"AR-001", "AR-002", "WEB-002", "LEGACY"})
+
+	tests := []struct {
+		ref   string
+		id    IssueID
+		found bool
+		known bool
+	}{
+		{"AR-001", "AR-001", true, true},
+		{"AR-1", "AR-001", true, true},
+		{"ar-2", "AR-002", true, true},
+		{"#1", "AR-001", true, true},
+		{"#2", "", false, false}, // ambiguous between projects
+		{"AR-099", "", false, true},
+		{"UTF-8", "", false, false},
+		{"LEGACY", "LEGACY", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			id, found, known := resolver.Resolve(tt.ref)
+			assert.Equal(t, tt.id, id)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.known, known)
+		})
+	}
+}
+
+func TestGitScanResultAddUnknownRef(t *testing.T) {
+	result := &GitScanResult{}
+	result.AddUnknownRef("AR-099", "bbb")
+	result.AddUnknownRef("AR-050", "aaa")
+	result.AddUnknownRef("AR-099", "ccc")
+	result.AddUnknownRef("AR-099", "bbb")
+
+	assert.Equal(t, []GitScanUnknownRef{
+		{Ref: "AR-050", Commits: []string{"aaa"}},
+		{Ref: "AR-099", Commits: []string{"bbb", "ccc"}},
+	}, result.Unknown)
+}
+
+func TestIssueHasCommit(t *testing.T) {
+	issue := NewIssue("AR-001", "Test", "", IssueTypeTask)
+	issue.Commits = []CommitRef{{Hash: "abc1234"}, {Hash: "fedcba9876"}}
+
+	assert.True(t, issue.HasCommit("abc1234"))
+	assert.True(t, issue.HasCommit("abc1234ffffffff"))
+	assert.True(t, issue.HasCommit("fedcba9876"))
+	assert.False(t, issue.HasCommit("fedcba"))
+	assert.False(t, issue.HasCommit("0123456"))
+}
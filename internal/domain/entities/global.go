@@ -49,6 +49,8 @@ type GlobalSettings struct {
 	MaxBackupRetention   int                  `yaml:"max_backup_retention" json:"max_backup_retention"`
 	CompressionEnabled   bool                 `yaml:"compression_enabled" json:"compression_enabled"`
 	EncryptionEnabled    bool                 `yaml:"encryption_enabled" json:"encryption_enabled"`
+	FullBackupsOnly      bool                 `yaml:"full_backups_only" json:"full_backups_only"`
+	BackupKeyID          string               `yaml:"backup_key_id,omitempty" json:"backup_key_id,omitempty"`
 	NotificationSettings NotificationSettings `yaml:"notification_settings" json:"notification_settings"`
 }
 
@@ -98,6 +100,65 @@ type ProjectBackup struct {
 	Metadata         BackupMetadata `yaml:"metadata" json:"metadata"`
 	CompressionRatio float64        `yaml:"compression_ratio,omitempty" json:"compression_ratio,omitempty"`
 	Tags             []string       `yaml:"tags,omitempty" json:"tags,omitempty"`
+
+	// Encrypted backups are sealed with the keyring key identified by KeyID
+	Encrypted bool   `yaml:"encrypted,omitempty" json:"encrypted,omitempty"`
+	KeyID     string `yaml:"key_id,omitempty" json:"key_id,omitempty"`
+
+	// Incremental backups only store files that changed since BaseBackupID;
+	// Files lists every file in the backup and which archive holds it
+	Incremental  bool         `yaml:"incremental,omitempty" json:"incremental,omitempty"`
+	BaseBackupID string       `yaml:"base_backup_id,omitempty" json:"base_backup_id,omitempty"`
+	StoredFiles  int          `yaml:"stored_files,omitempty" json:"stored_files,omitempty"`
+	Files        []BackupFile `yaml:"files,omitempty" json:"files,omitempty"`
+
+	// Pruned lists backups removed by retention when this backup was created
+	Pruned []string `yaml:"-" json:"pruned,omitempty"`
+}
+
+// BackupFile records a file in a backup and the backup whose archive holds
+// its content
+type BackupFile struct {
+	Path     string `yaml:"path" json:"path"`
+	Hash     string `yaml:"hash" json:"hash"`
+	Size     int64  `yaml:"size" json:"size"`
+	BackupID string `yaml:"backup_id" json:"backup_id"`
+}
+
+// ReferencedBackups returns the IDs of other backups whose archives this
+// backup needs to be restored
+func (b *ProjectBackup) ReferencedBackups() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, file := range b.Files {
+		if file.BackupID != b.ID && !seen[file.BackupID] {
+			seen[file.BackupID] = true
+			ids = append(ids, file.BackupID)
+		}
+	}
+	return ids
+}
+
+// BackupVerifyResult reports a test restore of a backup. Missing and
+// corrupted files make a backup invalid; differences from the project
+// source only show what changed since the backup was taken.
+type BackupVerifyResult struct {
+	BackupID     string    `json:"backup_id"`
+	ProjectPath  string    `json:"project_path"`
+	Valid        bool      `json:"valid"`
+	FilesChecked int       `json:"files_checked"`
+	Missing      []string  `json:"missing,omitempty"`
+	Corrupted    []string  `json:"corrupted,omitempty"`
+	Added        []string  `json:"added,omitempty"`
+	Modified     []string  `json:"modified,omitempty"`
+	Removed      []string  `json:"removed,omitempty"`
+	Errors       []string  `json:"errors,omitempty"`
+	VerifiedAt   time.Time `json:"verified_at"`
+}
+
+// InSync reports whether the restored backup matches the project source
+func (r *BackupVerifyResult) InSync() bool {
+	return len(r.Added) == 0 && len(r.Modified) == 0 && len(r.Removed) == 0
 }
 
 // BackupMetadata contains rich metadata about the backup
@@ -265,6 +326,11 @@ func GetBackupPath() string {
 	return filepath.Join(GetGlobalDir(), "backups")
 }
 
+// GetBackupKeysPath returns the path where backup encryption keys are stored
+func GetBackupKeysPath() string {
+	return filepath.Join(GetGlobalDir(), "keys")
+}
+
 // GetConfigPath returns the path to the global config file
 func GetConfigPath() string {
 	return filepath.Join(GetGlobalDir(), "config.yaml")
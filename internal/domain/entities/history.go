@@ -22,6 +22,7 @@ const (
 	ChangeTypeUnmilestoned ChangeType = "unmilestoned"
 	ChangeTypeLinked       ChangeType = "linked"
 	ChangeTypeUnlinked     ChangeType = "unlinked"
+	ChangeTypeReverted     ChangeType = "reverted"
 )
 
 // FieldChange represents a change to a specific field
@@ -33,24 +34,38 @@ type FieldChange struct {
 
 // HistoryEntry represents a single change event in an issue's history
 type HistoryEntry struct {
-	ID        string                 `json:"id" yaml:"id"`                                 // Unique identifier for this history entry
-	IssueID   IssueID                `json:"issue_id" yaml:"issue_id"`                     // The issue this change belongs to
-	Version   int                    `json:"version" yaml:"version"`                       // Version number of the issue after this change
-	Type      ChangeType             `json:"type" yaml:"type"`                             // Type of change
-	Author    string                 `json:"author" yaml:"author"`                         // Who made the change
-	Timestamp time.Time              `json:"timestamp" yaml:"timestamp"`                   // When the change was made
-	Message   string                 `json:"message" yaml:"message"`                       // Human-readable description of the change
-	Changes   []FieldChange          `json:"changes" yaml:"changes"`                       // Detailed field changes
-	Metadata  map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"` // Additional context
+	ID        string                 `json:"id" yaml:"id"`                                   // Unique identifier for this history entry
+	IssueID   IssueID                `json:"issue_id" yaml:"issue_id"`                       // The issue this change belongs to
+	Version   int                    `json:"version" yaml:"version"`                         // Version number of the issue after this change
+	Type      ChangeType             `json:"type" yaml:"type"`                               // Type of change
+	Author    string                 `json:"author" yaml:"author"`                           // Who made the change
+	Timestamp time.Time              `json:"timestamp" yaml:"timestamp"`                     // When the change was made
+	Message   string                 `json:"message" yaml:"message"`                         // Human-readable description of the change
+	Changes   []FieldChange          `json:"changes" yaml:"changes"`                         // Detailed field changes
+	Metadata  map[string]interface{} `json:"metadata,omitempty" yaml:"metadata,omitempty"`   // Additional context
+	PrevHash  string                 `json:"prev_hash,omitempty" yaml:"prev_hash,omitempty"` // Hash of the previous entry in this issue's chain
+	Hash      string                 `json:"hash,omitempty" yaml:"hash,omitempty"`           // Hash of this entry's content, including PrevHash
+	Signature string                 `json:"signature,omitempty" yaml:"signature,omitempty"` // Optional detached signature over Hash
 }
 
 // IssueHistory represents the complete version history of an issue
 type IssueHistory struct {
+	// SchemaVersion is the format version the file was written in
+	SchemaVersion int `yaml:"schema_version,omitempty" json:"schema_version,omitempty"`
+
 	IssueID        IssueID        `json:"issue_id" yaml:"issue_id"`
 	CurrentVersion int            `json:"current_version" yaml:"current_version"`
 	CreatedAt      time.Time      `json:"created_at" yaml:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at" yaml:"updated_at"`
 	Entries        []HistoryEntry `json:"entries" yaml:"entries"`
+
+	// Checkpoints summarise entries that were compacted out of Entries
+	Checkpoints []HistoryCheckpoint `json:"checkpoints,omitempty" yaml:"checkpoints,omitempty"`
+
+	// SealedFrom is the first version recorded with a hash. Every later
+	// entry must be sealed, so stripping hashes does not pass as history
+	// that predates hash chaining.
+	SealedFrom int `json:"sealed_from,omitempty" yaml:"sealed_from,omitempty"`
 }
 
 // NewHistoryEntry creates a new history entry
@@ -94,10 +109,18 @@ func NewIssueHistory(issueID IssueID) *IssueHistory {
 	}
 }
 
-// AddEntry adds a new history entry and increments the version
+// AddEntry adds a new history entry, increments the version and links the
+// entry into the issue's hash chain
 func (h *IssueHistory) AddEntry(entry *HistoryEntry) {
 	h.CurrentVersion++
 	entry.Version = h.CurrentVersion
+	h.RecordSealedFrom()
+	if h.SealedFrom == 0 {
+		h.SealedFrom = entry.Version
+	}
+	entry.PrevHash = h.HeadHash()
+	entry.Signature = ""
+	entry.Hash = entry.ComputeHash()
 	h.Entries = append(h.Entries, *entry)
 	h.UpdatedAt = time.Now()
 }
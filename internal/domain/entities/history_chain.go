@@ -0,0 +1,304 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HistoryCheckpoint summarises a run of history entries that were compacted
+// away. It takes the place of those entries in the issue's hash chain: its
+// PrevHash is the PrevHash of the first compacted entry and HeadHash is the
+// Hash of the last one, so the remaining entries still link up.
+type HistoryCheckpoint struct {
+	FromVersion    int                `json:"from_version" yaml:"from_version"`
+	ToVersion      int                `json:"to_version" yaml:"to_version"`
+	EntryCount     int                `json:"entry_count" yaml:"entry_count"`
+	FirstTimestamp time.Time          `json:"first_timestamp" yaml:"first_timestamp"`
+	LastTimestamp  time.Time          `json:"last_timestamp" yaml:"last_timestamp"`
+	ChangesByType  map[ChangeType]int `json:"changes_by_type" yaml:"changes_by_type"`
+	Authors        []string           `json:"authors" yaml:"authors"`
+	CreatedAt      time.Time          `json:"created_at" yaml:"created_at"`
+	CreatedBy      string             `json:"created_by" yaml:"created_by"`
+	PrevHash       string             `json:"prev_hash" yaml:"prev_hash"`
+	HeadHash       string             `json:"head_hash" yaml:"head_hash"`
+	Hash           string             `json:"hash" yaml:"hash"`
+	Signature      string             `json:"signature,omitempty" yaml:"signature,omitempty"`
+}
+
+// HistoryChainProblemKind classifies a problem found while verifying a history chain
+type HistoryChainProblemKind string
+
+const (
+	ChainProblemModified   HistoryChainProblemKind = "modified"    // Content no longer matches its hash
+	ChainProblemBrokenLink HistoryChainProblemKind = "broken_link" // PrevHash does not match the previous hash
+	ChainProblemGap        HistoryChainProblemKind = "gap"         // Versions are missing
+	ChainProblemReordered  HistoryChainProblemKind = "reordered"   // Versions are out of order or duplicated
+	ChainProblemTruncated  HistoryChainProblemKind = "truncated"   // Entries missing from the end
+	ChainProblemUnsealed   HistoryChainProblemKind = "unsealed"    // Entry without a hash after sealed entries
+	ChainProblemSignature  HistoryChainProblemKind = "bad_signature"
+)
+
+// HistoryChainProblem describes a single integrity problem in an issue's history
+type HistoryChainProblem struct {
+	Kind    HistoryChainProblemKind `json:"kind"`
+	Version int                     `json:"version"`
+	EntryID string                  `json:"entry_id,omitempty"`
+	Message string                  `json:"message"`
+}
+
+// HistoryVerification is the result of verifying one issue's history
+type HistoryVerification struct {
+	IssueID     IssueID               `json:"issue_id"`
+	Entries     int                   `json:"entries"`
+	Checkpoints int                   `json:"checkpoints"`
+	Sealed      int                   `json:"sealed"`
+	Unsealed    int                   `json:"unsealed"`
+	Signed      int                   `json:"signed"`
+	Signers     []string              `json:"signers,omitempty"`
+	Problems    []HistoryChainProblem `json:"problems,omitempty"`
+}
+
+// Valid reports whether no problems were found
+func (v *HistoryVerification) Valid() bool {
+	return len(v.Problems) == 0
+}
+
+// AddProblem records an integrity problem
+func (v *HistoryVerification) AddProblem(kind HistoryChainProblemKind, version int, entryID, message string) {
+	v.Problems = append(v.Problems, HistoryChainProblem{
+		Kind:    kind,
+		Version: version,
+		EntryID: entryID,
+		Message: message,
+	})
+}
+
+// ComputeHash returns the hash of the entry's content, excluding Hash and
+// Signature. It is empty if the content cannot be hashed, which verification
+// reports as a modified entry.
+func (h *HistoryEntry) ComputeHash() string {
+	content := *h
+	content.Hash = ""
+	content.Signature = ""
+	hash, _ := canonicalHash(content)
+	return hash
+}
+
+// ComputeHash returns the hash of the checkpoint's content, excluding Hash
+// and Signature. It is empty if the content cannot be hashed.
+func (c *HistoryCheckpoint) ComputeHash() string {
+	hash, _ := c.hashContent()
+	return hash
+}
+
+// hashContent hashes the checkpoint's content, excluding Hash and Signature
+func (c *HistoryCheckpoint) hashContent() (string, error) {
+	content := *c
+	content.Hash = ""
+	content.Signature = ""
+	return canonicalHash(content)
+}
+
+// HeadHash returns the hash that the next entry should link to
+func (h *IssueHistory) HeadHash() string {
+	if len(h.Entries) > 0 {
+		return h.Entries[len(h.Entries)-1].Hash
+	}
+	if len(h.Checkpoints) > 0 {
+		return h.Checkpoints[len(h.Checkpoints)-1].HeadHash
+	}
+	return ""
+}
+
+// Reseal recomputes the hash chain over all entries and drops signatures.
+// It is only meant for deliberate rewrites such as ID remapping on import.
+func (h *IssueHistory) Reseal() {
+	prevHash := ""
+	if len(h.Checkpoints) > 0 {
+		prevHash = h.Checkpoints[len(h.Checkpoints)-1].HeadHash
+	}
+	for i := range h.Entries {
+		h.Entries[i].PrevHash = prevHash
+		h.Entries[i].Signature = ""
+		h.Entries[i].Hash = h.Entries[i].ComputeHash()
+		prevHash = h.Entries[i].Hash
+	}
+	h.RecordSealedFrom()
+}
+
+// RecordSealedFrom sets SealedFrom to the first sealed version when it is
+// not set yet, such as for histories sealed before the marker existed
+func (h *IssueHistory) RecordSealedFrom() {
+	if h.SealedFrom == 0 {
+		h.SealedFrom = h.firstSealedVersion()
+	}
+}
+
+// firstSealedVersion returns the first version covered by a hash, or 0 when
+// nothing is sealed
+func (h *IssueHistory) firstSealedVersion() int {
+	for _, cp := range h.Checkpoints {
+		if cp.HeadHash != "" {
+			return cp.FromVersion
+		}
+	}
+	for _, entry := range h.Entries {
+		if entry.Hash != "" {
+			return entry.Version
+		}
+	}
+	return 0
+}
+
+// VerifyChain checks the hash chain, version sequence and checkpoints of the history.
+// Signatures are not checked here since that requires git.
+func (h *IssueHistory) VerifyChain() *HistoryVerification {
+	result := &HistoryVerification{
+		IssueID:     h.IssueID,
+		Entries:     len(h.Entries),
+		Checkpoints: len(h.Checkpoints),
+	}
+
+	expectedVersion := 1
+	prevHash := ""
+
+	for _, cp := range h.Checkpoints {
+		label := fmt.Sprintf("checkpoint v%d-v%d", cp.FromVersion, cp.ToVersion)
+		if cp.FromVersion != expectedVersion {
+			result.AddProblem(ChainProblemGap, cp.FromVersion, "",
+				fmt.Sprintf("%s starts at v%d, expected v%d", label, cp.FromVersion, expectedVersion))
+		}
+		if cp.PrevHash != prevHash {
+			result.AddProblem(ChainProblemBrokenLink, cp.FromVersion, "", label+" does not link to the previous checkpoint")
+		}
+		if cp.ComputeHash() != cp.Hash {
+			result.AddProblem(ChainProblemModified, cp.FromVersion, "", label+" has been modified")
+		}
+		expectedVersion = cp.ToVersion + 1
+		prevHash = cp.HeadHash
+	}
+
+	sealedSeen := false
+	for _, entry := range h.Entries {
+		if entry.Version < expectedVersion {
+			result.AddProblem(ChainProblemReordered, entry.Version, entry.ID,
+				fmt.Sprintf("version %d appears after version %d", entry.Version, expectedVersion-1))
+		} else if entry.Version > expectedVersion {
+			result.AddProblem(ChainProblemGap, entry.Version, entry.ID,
+				fmt.Sprintf("versions %d-%d are missing", expectedVersion, entry.Version-1))
+		}
+		expectedVersion = entry.Version + 1
+
+		if entry.Hash == "" {
+			if sealedSeen {
+				result.AddProblem(ChainProblemUnsealed, entry.Version, entry.ID, "entry has no hash but follows sealed entries")
+			} else if h.SealedFrom > 0 && entry.Version >= h.SealedFrom {
+				result.AddProblem(ChainProblemUnsealed, entry.Version, entry.ID,
+					fmt.Sprintf("entry has no hash but the history is sealed from v%d", h.SealedFrom))
+			} else {
+				result.Unsealed++
+			}
+			prevHash = ""
+			continue
+		}
+
+		sealedSeen = true
+		result.Sealed++
+		if entry.Signature != "" {
+			result.Signed++
+		}
+		if entry.PrevHash != prevHash {
+			result.AddProblem(ChainProblemBrokenLink, entry.Version, entry.ID, "entry does not link to the previous entry")
+		}
+		if entry.ComputeHash() != entry.Hash {
+			result.AddProblem(ChainProblemModified, entry.Version, entry.ID, "entry content does not match its hash")
+		}
+		prevHash = entry.Hash
+	}
+
+	if (len(h.Entries) > 0 || len(h.Checkpoints) > 0) && h.CurrentVersion != expectedVersion-1 {
+		result.AddProblem(ChainProblemTruncated, h.CurrentVersion, "",
+			fmt.Sprintf("current version is %d but the last recorded version is %d", h.CurrentVersion, expectedVersion-1))
+	}
+
+	return result
+}
+
+// Compact replaces entries older than before with a checkpoint, always keeping
+// at least keep of the most recent entries. It returns nil if nothing was
+// compacted, and leaves the history unchanged if the checkpoint cannot be hashed.
+func (h *IssueHistory) Compact(before time.Time, keep int, createdBy string) (*HistoryCheckpoint, error) {
+	limit := len(h.Entries) - keep
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	count := 0
+	for count < limit && h.Entries[count].Timestamp.Before(before) {
+		count++
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	compacted := h.Entries[:count]
+	checkpoint := HistoryCheckpoint{
+		FromVersion:    compacted[0].Version,
+		ToVersion:      compacted[count-1].Version,
+		EntryCount:     count,
+		FirstTimestamp: compacted[0].Timestamp,
+		LastTimestamp:  compacted[count-1].Timestamp,
+		ChangesByType:  make(map[ChangeType]int),
+		CreatedAt:      time.Now(),
+		CreatedBy:      createdBy,
+		PrevHash:       compacted[0].PrevHash,
+		HeadHash:       compacted[count-1].Hash,
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range compacted {
+		checkpoint.ChangesByType[entry.Type]++
+		if !seen[entry.Author] {
+			seen[entry.Author] = true
+			checkpoint.Authors = append(checkpoint.Authors, entry.Author)
+		}
+	}
+	hash, err := checkpoint.hashContent()
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash checkpoint: %w", err)
+	}
+	checkpoint.Hash = hash
+
+	h.Checkpoints = append(h.Checkpoints, checkpoint)
+	h.Entries = append([]HistoryEntry{}, h.Entries[count:]...)
+	h.UpdatedAt = time.Now()
+
+	return &h.Checkpoints[len(h.Checkpoints)-1], nil
+}
+
+// canonicalHash hashes a value in a form that survives a YAML round trip, so
+// hashes computed before saving match those computed after loading
+func canonicalHash(v interface{}) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	var generic interface{}
+	if err := yaml.Unmarshal(data, &generic); err != nil {
+		return "", err
+	}
+
+	canonical, err := json.Marshal(generic)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,238 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrackedIssueFields lists the issue fields whose changes are recorded in history
+// and can therefore be reconstructed for earlier versions
+var TrackedIssueFields = []string{
+	"title", "description", "type", "status", "priority",
+	"assignee", "milestone", "labels", "branch",
+}
+
+// VersionAt returns the latest version recorded at or before t, or 0 if the
+// issue had no history yet
+func (h *IssueHistory) VersionAt(t time.Time) int {
+	version := 0
+	for _, entry := range h.Entries {
+		if !entry.Timestamp.After(t) && entry.Version > version {
+			version = entry.Version
+		}
+	}
+	if version == 0 && len(h.Checkpoints) > 0 {
+		// Everything up to the last checkpoint has been compacted away
+		for _, cp := range h.Checkpoints {
+			if !cp.LastTimestamp.After(t) {
+				version = cp.ToVersion
+			}
+		}
+	}
+	return version
+}
+
+// IssueAt reconstructs the issue as it was at the given version.
+//
+// For each tracked field the value is taken from the last change at or before
+// the version; if the field only changed later, the old value of the first
+// later change is used; otherwise the field never changed and the current
+// value applies. Fields that history does not track keep their current values.
+func (h *IssueHistory) IssueAt(current *Issue, version int) (*Issue, error) {
+	if version < 1 || version > h.CurrentVersion {
+		return nil, fmt.Errorf("version %d does not exist (current version is %d)", version, h.CurrentVersion)
+	}
+	if len(h.Checkpoints) > 0 && version <= h.Checkpoints[len(h.Checkpoints)-1].ToVersion {
+		return nil, fmt.Errorf("version %d has been compacted into a checkpoint", version)
+	}
+
+	entries := make([]HistoryEntry, len(h.Entries))
+	copy(entries, h.Entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Version < entries[j].Version
+	})
+
+	issue := *current
+	issue.Labels = append([]Label{}, current.Labels...)
+
+	for _, field := range TrackedIssueFields {
+		value, found := fieldValueAt(entries, field, version)
+		if found {
+			applyIssueFieldValue(&issue, current, field, value)
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.Version == version {
+			issue.Timestamps.Updated = entry.Timestamp
+		}
+	}
+	if issue.Status != StatusClosed {
+		issue.Timestamps.Closed = nil
+	}
+
+	return &issue, nil
+}
+
+// DiffIssues returns the changes to tracked fields between two issue states
+func DiffIssues(from, to *Issue) []FieldChange {
+	fromValues := IssueFieldValues(from)
+	toValues := IssueFieldValues(to)
+
+	var changes []FieldChange
+	for _, field := range TrackedIssueFields {
+		if field == "labels" {
+			oldLabels := fromValues[field].([]string)
+			newLabels := toValues[field].([]string)
+			if strings.Join(oldLabels, "\x00") != strings.Join(newLabels, "\x00") {
+				changes = append(changes, FieldChange{Field: field, OldValue: oldLabels, NewValue: newLabels})
+			}
+			continue
+		}
+		if fromValues[field] != toValues[field] {
+			changes = append(changes, FieldChange{Field: field, OldValue: fromValues[field], NewValue: toValues[field]})
+		}
+	}
+	return changes
+}
+
+// IssueFieldValues returns the tracked fields of an issue in the form they are recorded in history
+func IssueFieldValues(issue *Issue) map[string]interface{} {
+	assignee := ""
+	if issue.Assignee != nil {
+		assignee = issue.Assignee.Username
+	}
+	milestone := ""
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Name
+	}
+	labels := make([]string, len(issue.Labels))
+	for i, label := range issue.Labels {
+		labels[i] = label.Name
+	}
+
+	return map[string]interface{}{
+		"title":       issue.Title,
+		"description": issue.Description,
+		"type":        string(issue.Type),
+		"status":      string(issue.Status),
+		"priority":    string(issue.Priority),
+		"assignee":    assignee,
+		"milestone":   milestone,
+		"labels":      labels,
+		"branch":      issue.Branch,
+	}
+}
+
+// fieldValueAt finds the value a field had at the given version
+func fieldValueAt(entries []HistoryEntry, field string, version int) (interface{}, bool) {
+	var value interface{}
+	found := false
+
+	for _, entry := range entries {
+		if entry.Version > version {
+			break
+		}
+		for _, change := range entry.Changes {
+			if change.Field == field {
+				value = change.NewValue
+				found = true
+			}
+		}
+	}
+	if found {
+		return value, true
+	}
+
+	for _, entry := range entries {
+		if entry.Version <= version {
+			continue
+		}
+		for _, change := range entry.Changes {
+			if change.Field == field {
+				return change.OldValue, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// applyIssueFieldValue sets a tracked field from a recorded history value
+func applyIssueFieldValue(issue, current *Issue, field string, value interface{}) {
+	switch field {
+	case "title":
+		issue.Title = historyString(value)
+	case "description":
+		issue.Description = historyString(value)
+	case "type":
+		issue.Type = IssueType(historyString(value))
+	case "status":
+		issue.Status = Status(historyString(value))
+	case "priority":
+		issue.Priority = Priority(historyString(value))
+	case "branch":
+		issue.Branch = historyString(value)
+	case "assignee":
+		name := historyString(value)
+		switch {
+		case name == "":
+			issue.Assignee = nil
+		case current.Assignee != nil && current.Assignee.Username == name:
+			issue.Assignee = current.Assignee
+		default:
+			issue.Assignee = &User{Username: name}
+		}
+	case "milestone":
+		name := historyString(value)
+		switch {
+		case name == "":
+			issue.Milestone = nil
+		case current.Milestone != nil && current.Milestone.Name == name:
+			issue.Milestone = current.Milestone
+		default:
+			issue.Milestone = &Milestone{Name: name}
+		}
+	case "labels":
+		issue.Labels = []Label{}
+		for _, name := range historyStrings(value) {
+			label := Label{Name: name}
+			for _, existing := range current.Labels {
+				if existing.Name == name {
+					label = existing
+					break
+				}
+			}
+			issue.Labels = append(issue.Labels, label)
+		}
+	}
+}
+
+// historyString converts a recorded history value to a string
+func historyString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// historyStrings converts a recorded history value to a string slice. Values
+// loaded from YAML arrive as []interface{}.
+func historyStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, historyString(item))
+		}
+		return result
+	}
+	return nil
+}
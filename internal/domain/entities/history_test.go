@@ -0,0 +1,187 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func newChainedHistory(t *testing.T, n int) *IssueHistory {
+	t.Helper()
+	history := NewIssueHistory("TEST-001")
+	for i := 0; i < n; i++ {
+		entry := NewHistoryEntry("TEST-001", ChangeTypeUpdated, "alice", "change")
+		entry.AddFieldChange("priority", "low", "high")
+		entry.SetMetadata("git_branch", "main")
+		entry.Timestamp = time.Now().Add(time.Duration(i-n) * time.Hour)
+		history.AddEntry(entry)
+	}
+	return history
+}
+
+func TestIssueHistory_HashChain(t *testing.T) {
+	history := newChainedHistory(t, 3)
+
+	assert.Empty(t, history.Entries[0].PrevHash)
+	assert.Equal(t, history.Entries[0].Hash, history.Entries[1].PrevHash)
+	assert.Equal(t, history.Entries[1].Hash, history.Entries[2].PrevHash)
+
+	result := history.VerifyChain()
+	assert.True(t, result.Valid())
+	assert.Equal(t, 3, result.Sealed)
+}
+
+func TestIssueHistory_HashChainSurvivesYAML(t *testing.T) {
+	history := newChainedHistory(t, 2)
+
+	data, err := yaml.Marshal(history)
+	require.NoError(t, err)
+
+	var loaded IssueHistory
+	require.NoError(t, yaml.Unmarshal(data, &loaded))
+
+	assert.True(t, loaded.VerifyChain().Valid())
+}
+
+func TestIssueHistory_VerifyChainDetectsTampering(t *testing.T) {
+	t.Run("modified", func(t *testing.T) {
+		history := newChainedHistory(t, 3)
+		history.Entries[1].Message = "edited"
+
+		result := history.VerifyChain()
+		require.Len(t, result.Problems, 1)
+		assert.Equal(t, ChainProblemModified, result.Problems[0].Kind)
+	})
+
+	t.Run("reordered", func(t *testing.T) {
+		history := newChainedHistory(t, 3)
+		history.Entries[1], history.Entries[2] = history.Entries[2], history.Entries[1]
+
+		result := history.VerifyChain()
+		assert.False(t, result.Valid())
+		assert.Contains(t, problemKinds(result), ChainProblemReordered)
+	})
+
+	t.Run("gap", func(t *testing.T) {
+		history := newChainedHistory(t, 3)
+		history.Entries = append(history.Entries[:1], history.Entries[2:]...)
+
+		result := history.VerifyChain()
+		assert.Contains(t, problemKinds(result), ChainProblemGap)
+		assert.Contains(t, problemKinds(result), ChainProblemBrokenLink)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		history := newChainedHistory(t, 3)
+		history.Entries = history.Entries[:2]
+
+		result := history.VerifyChain()
+		assert.Equal(t, []HistoryChainProblemKind{ChainProblemTruncated}, problemKinds(result))
+	})
+}
+
+func TestIssueHistory_LegacyEntriesAreUnsealed(t *testing.T) {
+	history := NewIssueHistory("TEST-001")
+	history.CurrentVersion = 1
+	history.Entries = []HistoryEntry{{ID: "hist_1", IssueID: "TEST-001", Version: 1, Type: ChangeTypeCreated}}
+	history.AddEntry(NewHistoryEntry("TEST-001", ChangeTypeUpdated, "alice", "change"))
+
+	result := history.VerifyChain()
+	assert.True(t, result.Valid())
+	assert.Equal(t, 1, result.Unsealed)
+	assert.Equal(t, 1, result.Sealed)
+}
+
+func TestIssueHistory_StrippedHashesAreNotUnsealed(t *testing.T) {
+	history := newChainedHistory(t, 3)
+	assert.Equal(t, 1, history.SealedFrom)
+
+	for i := range history.Entries {
+		history.Entries[i].Hash = ""
+		history.Entries[i].PrevHash = ""
+	}
+
+	result := history.VerifyChain()
+	assert.False(t, result.Valid())
+	assert.Equal(t, 0, result.Unsealed)
+	assert.Equal(t, []HistoryChainProblemKind{ChainProblemUnsealed, ChainProblemUnsealed, ChainProblemUnsealed}, problemKinds(result))
+}
+
+func TestIssueHistory_Compact(t *testing.T) {
+	history := newChainedHistory(t, 5)
+
+	checkpoint, err := history.Compact(time.Now(), 2, "cleanup")
+	require.NoError(t, err)
+	require.NotNil(t, checkpoint)
+	assert.Equal(t, 1, checkpoint.FromVersion)
+	assert.Equal(t, 3, checkpoint.ToVersion)
+	assert.Equal(t, 3, checkpoint.EntryCount)
+	assert.Len(t, history.Entries, 2)
+	assert.True(t, history.VerifyChain().Valid())
+
+	// New entries keep linking after compaction
+	history.AddEntry(NewHistoryEntry("TEST-001", ChangeTypeCommented, "bob", "note"))
+	assert.True(t, history.VerifyChain().Valid())
+
+	// Nothing more to compact while keeping the minimum
+	checkpoint, err = history.Compact(time.Now(), 3, "cleanup")
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	history.Checkpoints[0].EntryCount = 1
+	assert.Contains(t, problemKinds(history.VerifyChain()), ChainProblemModified)
+}
+
+func TestIssueHistory_IssueAt(t *testing.T) {
+	history := NewIssueHistory("TEST-001")
+	created := NewHistoryEntry("TEST-001", ChangeTypeCreated, "alice", "created")
+	history.AddEntry(created)
+
+	retitled := NewHistoryEntry("TEST-001", ChangeTypeUpdated, "alice", "retitled")
+	retitled.AddFieldChange("title", "First", "Second")
+	history.AddEntry(retitled)
+
+	labelled := NewHistoryEntry("TEST-001", ChangeTypeUpdated, "bob", "labelled")
+	labelled.AddFieldChange("labels", []interface{}{}, []interface{}{"bug"})
+	labelled.AddFieldChange("status", "open", "in-progress")
+	history.AddEntry(labelled)
+
+	current := &Issue{
+		ID:     "TEST-001",
+		Title:  "Second",
+		Status: StatusInProgress,
+		Labels: []Label{{Name: "bug", Color: "#f00"}},
+	}
+
+	v1, err := history.IssueAt(current, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "First", v1.Title)
+	assert.Equal(t, StatusOpen, v1.Status)
+	assert.Empty(t, v1.Labels)
+
+	v2, err := history.IssueAt(current, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "Second", v2.Title)
+	assert.Equal(t, StatusOpen, v2.Status)
+
+	changes := DiffIssues(v1, current)
+	fields := make([]string, len(changes))
+	for i, change := range changes {
+		fields[i] = change.Field
+	}
+	assert.Equal(t, []string{"title", "status", "labels"}, fields)
+
+	_, err = history.IssueAt(current, 4)
+	assert.Error(t, err)
+}
+
+func problemKinds(result *HistoryVerification) []HistoryChainProblemKind {
+	var kinds []HistoryChainProblemKind
+	for _, problem := range result.Problems {
+		kinds = append(kinds, problem.Kind)
+	}
+	return kinds
+}
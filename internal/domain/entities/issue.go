@@ -101,6 +101,7 @@ type Milestone struct {
 	Name        string     `yaml:"name" json:"name"`
 	Description string     `yaml:"description,omitempty" json:"description,omitempty"`
 	DueDate     *time.Time `yaml:"due_date,omitempty" json:"due_date,omitempty"`
+	ReleasedAt  *time.Time `yaml:"released_at,omitempty" json:"released_at,omitempty"`
 }
 
 // CommitRef represents a reference to a git commit
@@ -135,6 +136,9 @@ type Timestamps struct {
 
 // Issue represents a single issue in the system
 type Issue struct {
+	// SchemaVersion is the format version the file was written in
+	SchemaVersion int `yaml:"schema_version,omitempty" json:"schema_version,omitempty"`
+
 	ID          IssueID       `yaml:"id" json:"id"`
 	Title       string        `yaml:"title" json:"title"`
 	Description string        `yaml:"description" json:"description"`
@@ -150,6 +154,19 @@ type Issue struct {
 	Attachments []Attachment  `yaml:"attachments" json:"attachments"`
 	Metadata    IssueMetadata `yaml:"metadata" json:"metadata"`
 	Timestamps  Timestamps    `yaml:"timestamps" json:"timestamps"`
+
+	// PullRequest tracks the pull request opened for the issue's branch
+	PullRequest *PullRequestRef `yaml:"pull_request,omitempty" json:"pull_request,omitempty"`
+
+	// Watchers are the handles notified about activity on the issue
+	Watchers []string `yaml:"watchers,omitempty" json:"watchers,omitempty"`
+
+	// Confidential issues are stored with their content encrypted to the
+	// configured recipients. Redacted is set when the reader could not
+	// decrypt the content and sees placeholders instead.
+	Confidential bool           `yaml:"confidential,omitempty" json:"confidential,omitempty"`
+	Sealed       *SealedContent `yaml:"sealed,omitempty" json:"-"`
+	Redacted     bool           `yaml:"-" json:"redacted,omitempty"`
 }
 
 // NewIssue creates a new issue with default values
@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultJobHistoryLimit is the number of job runs kept in the history
+const DefaultJobHistoryLimit = 500
+
+// JobTrigger records why a job ran
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+	JobTriggerCatchUp  JobTrigger = "catch-up"
+)
+
+// JobResult is the outcome of a job run
+type JobResult string
+
+const (
+	JobResultSuccess JobResult = "success"
+	JobResultFailed  JobResult = "failed"
+)
+
+// JobsConfig configures the scheduled job registry. Schedules override a
+// job's default cron expression by job name.
+type JobsConfig struct {
+	// Schedules maps job names to cron expressions or macros such as @daily
+	Schedules map[string]string `yaml:"schedules,omitempty" json:"schedules,omitempty"`
+
+	// Disabled lists jobs that should not run on their schedule
+	Disabled []string `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// CatchUp runs a job once at startup when a scheduled run was missed
+	// while the server was down
+	CatchUp bool `yaml:"catch_up" json:"catch_up"`
+
+	// HistoryLimit is the number of runs kept in the job history
+	HistoryLimit int `yaml:"history_limit" json:"history_limit"`
+}
+
+// DefaultJobsConfig returns the default job configuration
+func DefaultJobsConfig() *JobsConfig {
+	return &JobsConfig{
+		CatchUp:      true,
+		HistoryLimit: DefaultJobHistoryLimit,
+	}
+}
+
+// IsDisabled reports whether the named job has been disabled
+func (c *JobsConfig) IsDisabled(name string) bool {
+	for _, disabled := range c.Disabled {
+		if disabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// JobState is the persisted scheduling state of a job
+type JobState struct {
+	Name         string        `yaml:"name" json:"name"`
+	LastRun      *time.Time    `yaml:"last_run,omitempty" json:"last_run,omitempty"`
+	NextRun      *time.Time    `yaml:"next_run,omitempty" json:"next_run,omitempty"`
+	LastResult   JobResult     `yaml:"last_result,omitempty" json:"last_result,omitempty"`
+	LastMessage  string        `yaml:"last_message,omitempty" json:"last_message,omitempty"`
+	LastDuration time.Duration `yaml:"last_duration,omitempty" json:"last_duration,omitempty"`
+	RunCount     int           `yaml:"run_count" json:"run_count"`
+	FailureCount int           `yaml:"failure_count" json:"failure_count"`
+}
+
+// Record updates the state with a finished run
+func (s *JobState) Record(run *JobRun) {
+	finished := run.FinishedAt
+	s.LastRun = &finished
+	s.LastResult = run.Result
+	s.LastMessage = run.Message
+	if run.Result == JobResultFailed {
+		s.LastMessage = run.Error
+		s.FailureCount++
+	}
+	s.LastDuration = run.Duration()
+	s.RunCount++
+}
+
+// JobRun records a single execution of a job
+type JobRun struct {
+	ID         string     `yaml:"id" json:"id"`
+	Job        string     `yaml:"job" json:"job"`
+	Trigger    JobTrigger `yaml:"trigger" json:"trigger"`
+	StartedAt  time.Time  `yaml:"started_at" json:"started_at"`
+	FinishedAt time.Time  `yaml:"finished_at" json:"finished_at"`
+	Result     JobResult  `yaml:"result" json:"result"`
+	Message    string     `yaml:"message,omitempty" json:"message,omitempty"`
+	Error      string     `yaml:"error,omitempty" json:"error,omitempty"`
+}
+
+// NewJobRun starts a run record for a job
+func NewJobRun(job string, trigger JobTrigger) *JobRun {
+	now := time.Now()
+	return &JobRun{
+		ID:        fmt.Sprintf("%s-%d", job, now.UnixNano()),
+		Job:       job,
+		Trigger:   trigger,
+		StartedAt: now,
+	}
+}
+
+// Finish completes the run with the job's message and error
+func (r *JobRun) Finish(message string, err error) {
+	r.FinishedAt = time.Now()
+	r.Message = message
+	r.Result = JobResultSuccess
+	if err != nil {
+		r.Result = JobResultFailed
+		r.Error = err.Error()
+	}
+}
+
+// Duration returns how long the run took
+func (r *JobRun) Duration() time.Duration {
+	if r.FinishedAt.IsZero() {
+		return 0
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// JobInfo describes a registered job and its current state
+type JobInfo struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Schedule    string    `json:"schedule"`
+	Enabled     bool      `json:"enabled"`
+	Running     bool      `json:"running"`
+	State       *JobState `json:"state"`
+}
@@ -0,0 +1,229 @@
+package entities
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// JSONSchemaDialect is the JSON Schema draft the published schemas use
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaBaseURI is where the versioned schemas are published in the
+// repository
+const jsonSchemaBaseURI = "https://github.com/ooyeku/issuemap/blob/main/docs/schemas/"
+
+// JSONSchema is the subset of JSON Schema needed to describe the files
+// under .issuemap
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	Const                interface{}            `json:"const,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+}
+
+// schemaDefinition describes the entity type a kind is stored as
+type schemaDefinition struct {
+	title       string
+	description string
+	value       interface{}
+	required    []string
+}
+
+var schemaDefinitions = map[SchemaKind]schemaDefinition{
+	SchemaKindIssue: {
+		title:       "IssueMap issue",
+		description: "An issue stored in .issuemap/issues/<id>.yaml",
+		value:       Issue{},
+		required:    []string{"id", "title", "type", "status", "priority"},
+	},
+	SchemaKindHistory: {
+		title:       "IssueMap issue history",
+		description: "The change history of an issue stored in .issuemap/history/<id>.yaml",
+		value:       IssueHistory{},
+		required:    []string{"issue_id", "entries"},
+	},
+	SchemaKindDependency: {
+		title:       "IssueMap dependency",
+		description: "A dependency between two issues stored in .issuemap/dependencies/<id>.yaml",
+		value:       Dependency{},
+		required:    []string{"id", "source_id", "target_id", "type", "status", "created_by"},
+	},
+	SchemaKindTimeEntry: {
+		title:       "IssueMap time entry",
+		description: "Time logged against an issue stored in .issuemap/time_entries/<id>.yaml",
+		value:       TimeEntry{},
+		required:    []string{"id", "issue_id", "type", "duration", "author"},
+	},
+	SchemaKindConfig: {
+		title:       "IssueMap project configuration",
+		description: "Project settings stored in .issuemap/config.yaml",
+		value:       Config{},
+	},
+}
+
+// schemaEnums lists the allowed values of string types with a fixed set of
+// values. Statuses are configurable per project and are not listed.
+var schemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(IssueTypeBug):                  {"bug", "feature", "task", "epic"},
+	reflect.TypeOf(PriorityLow):                   {"low", "medium", "high", "critical"},
+	reflect.TypeOf(DependencyTypeBlocks):          {"blocks", "requires"},
+	reflect.TypeOf(DependencyStatusActive):        {"active", "resolved", "ignored"},
+	reflect.TypeOf(TimeEntryTypeManual):           {"manual", "timer", "commit"},
+	reflect.TypeOf(TimeEntryStatusDraft):          {"draft", "submitted", "approved", "locked"},
+	reflect.TypeOf(AttachmentTypeImage):           {"image", "document", "text", "other"},
+	reflect.TypeOf(BlobBackendLocal):              {"local", "lfs", "s3"},
+	reflect.TypeOf(LandingMethodMerge):            {"merge", "squash", "rebase"},
+	reflect.TypeOf(AssignmentStrategyLeastLoaded): {"least_loaded", "round_robin"},
+	reflect.TypeOf(ChangeTypeCreated):             {"created", "updated", "closed", "reopened", "assigned", "unassigned", "labeled", "unlabeled", "commented", "milestoned", "unmilestoned", "linked", "unlinked", "reverted"},
+}
+
+// durationPattern matches durations as written by time.Duration.String
+const durationPattern = `^(0|-?([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// SchemaID returns the $id of the published schema for a kind and version
+func SchemaID(kind SchemaKind, version int) string {
+	return fmt.Sprintf("%s%s.v%d.json", jsonSchemaBaseURI, kind, version)
+}
+
+// SchemaInfo describes the current format of a kind
+type SchemaInfo struct {
+	Kind    SchemaKind `json:"kind"`
+	Version int        `json:"version"`
+	ID      string     `json:"id"`
+}
+
+// ListSchemas describes the current format of every kind
+func ListSchemas() []SchemaInfo {
+	var infos []SchemaInfo
+	for _, kind := range SchemaKinds() {
+		version := CurrentSchemaVersion(kind)
+		infos = append(infos, SchemaInfo{Kind: kind, Version: version, ID: SchemaID(kind, version)})
+	}
+	return infos
+}
+
+// GenerateJSONSchema builds the JSON Schema of the current format of a kind
+// from its entity type's yaml tags
+func GenerateJSONSchema(kind SchemaKind) (*JSONSchema, error) {
+	definition, ok := schemaDefinitions[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown schema kind %q", kind)
+	}
+	version := CurrentSchemaVersion(kind)
+
+	generator := &jsonSchemaGenerator{defs: make(map[string]*JSONSchema)}
+	schema := generator.structSchema(reflect.TypeOf(definition.value))
+	schema.Schema = JSONSchemaDialect
+	schema.ID = SchemaID(kind, version)
+	schema.Title = definition.title
+	schema.Description = fmt.Sprintf("%s (format version %d)", definition.description, version)
+	schema.Required = definition.required
+	schema.Properties["schema_version"] = &JSONSchema{
+		Type:        "integer",
+		Description: "Format version the file was written in; files without it predate versioning",
+		Const:       version,
+	}
+	if len(generator.defs) > 0 {
+		schema.Defs = generator.defs
+	}
+	return schema, nil
+}
+
+type jsonSchemaGenerator struct {
+	defs map[string]*JSONSchema
+}
+
+// typeSchema returns the schema of a Go type as it is encoded in YAML
+func (g *jsonSchemaGenerator) typeSchema(t reflect.Type) *JSONSchema {
+	if values, ok := schemaEnums[t]; ok {
+		return &JSONSchema{Type: "string", Enum: values}
+	}
+	switch t {
+	case reflect.TypeOf(time.Time{}):
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case reflect.TypeOf(time.Duration(0)):
+		return &JSONSchema{OneOf: []*JSONSchema{
+			{Type: "string", Pattern: durationPattern},
+			{Type: "integer", Description: "nanoseconds"},
+		}}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.typeSchema(t.Elem())
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string"}
+		}
+		return &JSONSchema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			// Reserve the name first so recursive types terminate
+			g.defs[t.Name()] = &JSONSchema{}
+			*g.defs[t.Name()] = *g.structSchema(t)
+		}
+		return &JSONSchema{Ref: "#/$defs/" + t.Name()}
+	}
+	return &JSONSchema{}
+}
+
+// structSchema returns the object schema of a struct's yaml fields
+func (g *jsonSchemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+	schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(options, "inline") {
+			inline := field.Type
+			if inline.Kind() == reflect.Ptr {
+				inline = inline.Elem()
+			}
+			if inline.Kind() == reflect.Struct {
+				for key, value := range g.structSchema(inline).Properties {
+					schema.Properties[key] = value
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		schema.Properties[name] = g.typeSchema(field.Type)
+	}
+	return schema
+}
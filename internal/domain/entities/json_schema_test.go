@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	schema, err := GenerateJSONSchema(SchemaKindIssue)
+	require.NoError(t, err)
+
+	assert.Equal(t, JSONSchemaDialect, schema.Schema)
+	assert.Equal(t, SchemaID(SchemaKindIssue, CurrentSchemaVersion(SchemaKindIssue)), schema.ID)
+	assert.Equal(t, []string{"id", "title", "type", "status", "priority"}, schema.Required)
+	assert.Equal(t, CurrentSchemaVersion(SchemaKindIssue), schema.Properties["schema_version"].Const)
+
+	assert.Equal(t, []string{"bug", "feature", "task", "epic"}, schema.Properties["type"].Enum)
+	assert.Empty(t, schema.Properties["status"].Enum, "statuses are configurable")
+	assert.NotContains(t, schema.Properties, "redacted", "fields not stored in YAML are left out")
+
+	comments := schema.Properties["comments"]
+	assert.Equal(t, "array", comments.Type)
+	assert.Equal(t, "#/$defs/Comment", comments.Items.Ref)
+	require.Contains(t, schema.Defs, "Comment")
+	assert.Equal(t, "integer", schema.Defs["Comment"].Properties["id"].Type)
+	assert.Equal(t, "date-time", schema.Defs["Comment"].Properties["date"].Format)
+
+	_, err = GenerateJSONSchema("attachment")
+	assert.Error(t, err)
+}
+
+// The published schemas under docs/schemas are what other tools read. A
+// change to an entity type must be reflected there, with a version bump and
+// migration when the change is incompatible.
+func TestPublishedSchemasUpToDate(t *testing.T) {
+	for _, info := range ListSchemas() {
+		t.Run(string(info.Kind), func(t *testing.T) {
+			schema, err := GenerateJSONSchema(info.Kind)
+			require.NoError(t, err)
+			generated, err := json.MarshalIndent(schema, "", "  ")
+			require.NoError(t, err)
+
+			name := fmt.Sprintf("%s.v%d.json", info.Kind, info.Version)
+			published, err := os.ReadFile(filepath.Join("..", "..", "..", "docs", "schemas", name))
+			require.NoError(t, err, "docs/schemas/%s is missing", name)
+			assert.Equal(t, string(generated)+"\n", string(published),
+				"docs/schemas/%s is out of date: regenerate it with 'issuemap schema print %s'", name, info.Kind)
+		})
+	}
+}
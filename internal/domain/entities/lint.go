@@ -0,0 +1,497 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Lint severities, from least to most severe
+const (
+	LintSeverityInfo    = "info"
+	LintSeverityWarning = "warning"
+	LintSeverityError   = "error"
+)
+
+// Lint checks a rule can perform on an issue field
+const (
+	// LintCheckRequired fails when the field is empty
+	LintCheckRequired = "required"
+	// LintCheckLength bounds the length of a text field, or the number of
+	// items in a list field
+	LintCheckLength = "length"
+	// LintCheckPattern fails when a value does not match the pattern
+	LintCheckPattern = "pattern"
+	// LintCheckForbidden fails when a value matches the pattern
+	LintCheckForbidden = "forbidden"
+	// LintCheckAllowed fails when a value is not one of the allowed values
+	LintCheckAllowed = "allowed"
+	// LintCheckAge fails when the created or updated timestamp is older than
+	// the allowed number of days
+	LintCheckAge = "age"
+	// LintCheckOverrun fails when logged time exceeds the estimate by more
+	// than the allowed ratio
+	LintCheckOverrun = "overrun"
+)
+
+// Lint fix actions. Only fixes that cannot lose information are offered.
+const (
+	// LintFixTrim removes surrounding whitespace
+	LintFixTrim = "trim"
+	// LintFixCapitalize upper-cases the first letter
+	LintFixCapitalize = "capitalize"
+	// LintFixSet fills an empty field with the fix value
+	LintFixSet = "set"
+	// LintFixRemove drops list values that are not allowed
+	LintFixRemove = "remove"
+)
+
+// Issue fields lint rules can check. created and updated refer to the
+// issue timestamps and are only used by age checks.
+var lintFields = map[string]bool{
+	"title": true, "description": true, "type": true, "status": true,
+	"priority": true, "assignee": true, "labels": true, "milestone": true,
+	"branch": true, "estimated_hours": true, "actual_hours": true,
+	"comments": true, "created": true, "updated": true,
+}
+
+// lintListFields hold several values
+var lintListFields = map[string]bool{"labels": true, "comments": true}
+
+// lintSettableFields can be filled by a set fix
+var lintSettableFields = map[string]bool{
+	"type": true, "status": true, "priority": true, "assignee": true,
+	"milestone": true, "branch": true, "estimated_hours": true,
+}
+
+// LintConfig is the project's lint rule set, read from .issuemap/lint.yaml
+type LintConfig struct {
+	// IncludeDefaults adds the built-in rules. Rules in this file replace
+	// built-in rules with the same ID.
+	IncludeDefaults bool `yaml:"include_defaults,omitempty" json:"include_defaults,omitempty"`
+
+	// FailOn is the lowest severity that makes lint exit non-zero; empty
+	// never fails on severity
+	FailOn string `yaml:"fail_on,omitempty" json:"fail_on,omitempty"`
+
+	// MinScore makes lint exit non-zero when the average quality score is
+	// below it; 0 disables the check
+	MinScore int `yaml:"min_score,omitempty" json:"min_score,omitempty"`
+
+	Rules []LintRule `yaml:"rules" json:"rules"`
+}
+
+// LintRule is a single declarative check
+type LintRule struct {
+	ID          string `yaml:"id" json:"id"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+	Severity    string `yaml:"severity" json:"severity"`
+	Field       string `yaml:"field" json:"field"`
+	Check       string `yaml:"check" json:"check"`
+	Disabled    bool   `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+
+	// When limits the rule to matching issues
+	When *LintCondition `yaml:"when,omitempty" json:"when,omitempty"`
+
+	// Parameters of the check
+	Pattern  string   `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+	Min      int      `yaml:"min,omitempty" json:"min,omitempty"`
+	Max      int      `yaml:"max,omitempty" json:"max,omitempty"`
+	MinWords int      `yaml:"min_words,omitempty" json:"min_words,omitempty"`
+	Values   []string `yaml:"values,omitempty" json:"values,omitempty"`
+	MaxDays  int      `yaml:"max_days,omitempty" json:"max_days,omitempty"`
+	Ratio    float64  `yaml:"ratio,omitempty" json:"ratio,omitempty"`
+
+	// Message and Suggestion replace the generated text. {days}, {values},
+	// {min}, {max}, {actual} and {estimate} are filled in where they apply.
+	Message    string `yaml:"message,omitempty" json:"message,omitempty"`
+	Suggestion string `yaml:"suggestion,omitempty" json:"suggestion,omitempty"`
+
+	// Fix is applied by lint --fix
+	Fix *LintFix `yaml:"fix,omitempty" json:"fix,omitempty"`
+}
+
+// LintCondition selects the issues a rule applies to. Empty criteria match
+// every issue; all non-empty criteria must match.
+type LintCondition struct {
+	Types           []IssueType `yaml:"types,omitempty" json:"types,omitempty"`
+	ExcludeTypes    []IssueType `yaml:"exclude_types,omitempty" json:"exclude_types,omitempty"`
+	Statuses        []Status    `yaml:"statuses,omitempty" json:"statuses,omitempty"`
+	ExcludeStatuses []Status    `yaml:"exclude_statuses,omitempty" json:"exclude_statuses,omitempty"`
+	Priorities      []Priority  `yaml:"priorities,omitempty" json:"priorities,omitempty"`
+	Labels          []string    `yaml:"labels,omitempty" json:"labels,omitempty"`
+	MinAgeDays      int         `yaml:"min_age_days,omitempty" json:"min_age_days,omitempty"`
+}
+
+// LintFix describes how a violation is corrected automatically
+type LintFix struct {
+	Action string `yaml:"action" json:"action"`
+	Value  string `yaml:"value,omitempty" json:"value,omitempty"`
+}
+
+// LintViolation is a rule an issue does not satisfy
+type LintViolation struct {
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Field       string `json:"field"`
+	Suggestion  string `json:"suggestion,omitempty"`
+	AutoFixable bool   `json:"auto_fixable"`
+}
+
+// LintResult is the outcome of linting one issue
+type LintResult struct {
+	IssueID    IssueID         `json:"issue_id"`
+	Title      string          `json:"title"`
+	Violations []LintViolation `json:"violations"`
+	Score      int             `json:"score"` // 0-100 quality score
+	Grade      string          `json:"grade"` // A, B, C, D, F
+	Fixed      []string        `json:"fixed,omitempty"`
+}
+
+// LintSummary aggregates the results of linting many issues
+type LintSummary struct {
+	TotalIssues          int            `json:"total_issues"`
+	IssuesChecked        int            `json:"issues_checked"`
+	TotalViolations      int            `json:"total_violations"`
+	TotalFixed           int            `json:"total_fixed,omitempty"`
+	ViolationsBySeverity map[string]int `json:"violations_by_severity"`
+	ViolationsByRule     map[string]int `json:"violations_by_rule"`
+	AverageScore         float64        `json:"average_score"`
+	Results              []LintResult   `json:"results"`
+}
+
+// LintSeverityRank orders severities; unknown severities rank 0
+func LintSeverityRank(severity string) int {
+	switch severity {
+	case LintSeverityInfo:
+		return 1
+	case LintSeverityWarning:
+		return 2
+	case LintSeverityError:
+		return 3
+	}
+	return 0
+}
+
+// NewLintSummary aggregates results. totalIssues is the number of issues
+// linted, including those without violations.
+func NewLintSummary(results []LintResult, totalIssues int) LintSummary {
+	summary := LintSummary{
+		TotalIssues:          totalIssues,
+		IssuesChecked:        totalIssues,
+		ViolationsBySeverity: make(map[string]int),
+		ViolationsByRule:     make(map[string]int),
+		Results:              results,
+	}
+	if summary.Results == nil {
+		summary.Results = []LintResult{}
+	}
+
+	totalScore := 0
+	for _, result := range results {
+		totalScore += result.Score
+		summary.TotalViolations += len(result.Violations)
+		summary.TotalFixed += len(result.Fixed)
+		for _, violation := range result.Violations {
+			summary.ViolationsBySeverity[violation.Severity]++
+			summary.ViolationsByRule[violation.Rule]++
+		}
+	}
+
+	// Issues without violations score 100
+	if totalIssues > 0 {
+		totalScore += 100 * (totalIssues - len(results))
+		summary.AverageScore = float64(totalScore) / float64(totalIssues)
+	}
+	return summary
+}
+
+// ThresholdExceeded reports why the summary fails the given thresholds, or
+// an empty string if it passes. failOn is the lowest failing severity and
+// minScore the lowest acceptable average score.
+func (s *LintSummary) ThresholdExceeded(failOn string, minScore int) string {
+	if rank := LintSeverityRank(failOn); rank > 0 {
+		count := 0
+		for severity, n := range s.ViolationsBySeverity {
+			if LintSeverityRank(severity) >= rank {
+				count += n
+			}
+		}
+		if count > 0 {
+			return fmt.Sprintf("%d violation(s) at or above %s severity", count, failOn)
+		}
+	}
+	if minScore > 0 && s.TotalIssues > 0 && s.AverageScore < float64(minScore) {
+		return fmt.Sprintf("average score %.1f is below %d", s.AverageScore, minScore)
+	}
+	return ""
+}
+
+// Matches reports whether the condition applies to an issue. ageDays is the
+// age of the issue in days.
+func (c *LintCondition) Matches(issue *Issue, ageDays int) bool {
+	if c == nil {
+		return true
+	}
+	if len(c.Types) > 0 && !containsValue(c.Types, issue.Type) {
+		return false
+	}
+	if containsValue(c.ExcludeTypes, issue.Type) {
+		return false
+	}
+	if len(c.Statuses) > 0 && !containsValue(c.Statuses, issue.Status) {
+		return false
+	}
+	if containsValue(c.ExcludeStatuses, issue.Status) {
+		return false
+	}
+	if len(c.Priorities) > 0 && !containsValue(c.Priorities, issue.Priority) {
+		return false
+	}
+	if len(c.Labels) > 0 && !issueHasAnyLabel(issue, c.Labels) {
+		return false
+	}
+	if c.MinAgeDays > 0 && ageDays < c.MinAgeDays {
+		return false
+	}
+	return true
+}
+
+func containsValue[T comparable](values []T, value T) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsListField reports whether the rule's field holds several values
+func (r *LintRule) IsListField() bool {
+	return lintListFields[r.Field]
+}
+
+// Validate checks the rule for mistakes that would make it meaningless
+func (r *LintRule) Validate() error {
+	if strings.TrimSpace(r.ID) == "" {
+		return fmt.Errorf("rule id is required")
+	}
+	if LintSeverityRank(r.Severity) == 0 {
+		return fmt.Errorf("rule %s: severity must be info, warning or error", r.ID)
+	}
+	if !lintFields[r.Field] {
+		return fmt.Errorf("rule %s: unknown field %q", r.ID, r.Field)
+	}
+
+	timestamp := r.Field == "created" || r.Field == "updated"
+	if timestamp != (r.Check == LintCheckAge) {
+		return fmt.Errorf("rule %s: age checks apply to the created and updated fields only", r.ID)
+	}
+
+	switch r.Check {
+	case LintCheckRequired:
+	case LintCheckLength:
+		if r.Min <= 0 && r.Max <= 0 && r.MinWords <= 0 {
+			return fmt.Errorf("rule %s: length check needs min, max or min_words", r.ID)
+		}
+		if r.Max > 0 && r.Min > r.Max {
+			return fmt.Errorf("rule %s: min is greater than max", r.ID)
+		}
+	case LintCheckPattern, LintCheckForbidden:
+		if r.Pattern == "" {
+			return fmt.Errorf("rule %s: %s check needs a pattern", r.ID, r.Check)
+		}
+		if _, err := regexp.Compile(r.Pattern); err != nil {
+			return fmt.Errorf("rule %s: invalid pattern: %w", r.ID, err)
+		}
+	case LintCheckAllowed:
+		if len(r.Values) == 0 {
+			return fmt.Errorf("rule %s: allowed check needs values", r.ID)
+		}
+	case LintCheckAge:
+		if r.MaxDays <= 0 {
+			return fmt.Errorf("rule %s: age check needs max_days", r.ID)
+		}
+	case LintCheckOverrun:
+		if r.Field != "actual_hours" {
+			return fmt.Errorf("rule %s: overrun checks apply to actual_hours", r.ID)
+		}
+	default:
+		return fmt.Errorf("rule %s: unknown check %q", r.ID, r.Check)
+	}
+
+	if r.Fix != nil {
+		return r.validateFix()
+	}
+	return nil
+}
+
+func (r *LintRule) validateFix() error {
+	switch r.Fix.Action {
+	case LintFixTrim, LintFixCapitalize:
+		if r.Field != "title" && r.Field != "description" && r.Field != "branch" {
+			return fmt.Errorf("rule %s: %s fixes apply to title, description and branch", r.ID, r.Fix.Action)
+		}
+	case LintFixSet:
+		if r.Check != LintCheckRequired || !lintSettableFields[r.Field] {
+			return fmt.Errorf("rule %s: set fixes fill required %s fields", r.ID, settableFieldList())
+		}
+		if strings.TrimSpace(r.Fix.Value) == "" {
+			return fmt.Errorf("rule %s: set fix needs a value", r.ID)
+		}
+	case LintFixRemove:
+		if r.Check != LintCheckAllowed || r.Field != "labels" {
+			return fmt.Errorf("rule %s: remove fixes apply to allowed checks on labels", r.ID)
+		}
+	default:
+		return fmt.Errorf("rule %s: unknown fix action %q", r.ID, r.Fix.Action)
+	}
+	return nil
+}
+
+func settableFieldList() string {
+	return "type, status, priority, assignee, milestone, branch and estimated_hours"
+}
+
+// ActiveRules validates the configuration and returns the rules to run,
+// with built-in rules merged in when requested
+func (c *LintConfig) ActiveRules() ([]LintRule, error) {
+	if c.FailOn != "" && LintSeverityRank(c.FailOn) == 0 {
+		return nil, fmt.Errorf("fail_on must be info, warning or error")
+	}
+
+	var rules []LintRule
+	if c.IncludeDefaults {
+		overridden := make(map[string]bool, len(c.Rules))
+		for _, rule := range c.Rules {
+			overridden[rule.ID] = true
+		}
+		for _, rule := range DefaultLintConfig().Rules {
+			if !overridden[rule.ID] {
+				rules = append(rules, rule)
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(c.Rules))
+	for _, rule := range c.Rules {
+		if seen[rule.ID] {
+			return nil, fmt.Errorf("rule %s is defined more than once", rule.ID)
+		}
+		seen[rule.ID] = true
+		rules = append(rules, rule)
+	}
+
+	active := rules[:0]
+	for _, rule := range rules {
+		if rule.Disabled {
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		active = append(active, rule)
+	}
+	return active, nil
+}
+
+// DefaultLintConfig returns the built-in rules used when a project has no
+// lint.yaml
+func DefaultLintConfig() *LintConfig {
+	return &LintConfig{
+		Rules: []LintRule{
+			{
+				ID: "title-required", Severity: LintSeverityError, Field: "title", Check: LintCheckRequired,
+				Message: "Title is required", Suggestion: "Add a descriptive title for this issue",
+			},
+			{
+				ID: "title-whitespace", Severity: LintSeverityInfo, Field: "title", Check: LintCheckPattern,
+				Pattern: `^\S(.*\S)?$`, Message: "Title has leading or trailing whitespace",
+				Suggestion: "Remove the surrounding whitespace", Fix: &LintFix{Action: LintFixTrim},
+			},
+			{
+				ID: "title-too-short", Severity: LintSeverityWarning, Field: "title", Check: LintCheckLength, Min: 10,
+				Message: "Title is too short (less than {min} characters)", Suggestion: "Expand the title to be more descriptive",
+			},
+			{
+				ID: "title-too-long", Severity: LintSeverityWarning, Field: "title", Check: LintCheckLength, Max: 100,
+				Message: "Title is too long (more than {max} characters)", Suggestion: "Shorten the title while keeping it descriptive",
+			},
+			{
+				ID: "title-capitalization", Severity: LintSeverityInfo, Field: "title", Check: LintCheckForbidden,
+				Pattern: `^\s*\p{Ll}`, Message: "Title should start with a capital letter",
+				Suggestion: "Capitalize the first letter", Fix: &LintFix{Action: LintFixCapitalize},
+			},
+			{
+				ID: "title-redundant", Severity: LintSeverityInfo, Field: "title", Check: LintCheckForbidden,
+				Pattern: `(?is)fix.*bug|bug.*fix`, Message: "Title contains redundant words like 'fix' and 'bug'",
+				Suggestion: "Consider removing redundant words or using the type field instead",
+			},
+			{
+				ID: "description-required", Severity: LintSeverityWarning, Field: "description", Check: LintCheckRequired,
+				Message: "Description is missing", Suggestion: "Add a description explaining what this issue is about",
+			},
+			{
+				ID: "description-too-short", Severity: LintSeverityInfo, Field: "description", Check: LintCheckLength, Min: 20,
+				Message: "Description is very short (less than {min} characters)", Suggestion: "Expand the description with more details",
+			},
+			{
+				ID: "description-too-simple", Severity: LintSeverityInfo, Field: "description", Check: LintCheckLength, MinWords: 5,
+				Message: "Description seems too simple (less than {min} words)", Suggestion: "Add more context and details to the description",
+			},
+			{
+				ID: "type-required", Severity: LintSeverityError, Field: "type", Check: LintCheckRequired,
+				Message: "Issue type is required", Suggestion: "Set type to one of: bug, feature, task, epic",
+			},
+			{
+				ID: "status-stale-in-progress", Severity: LintSeverityWarning, Field: "updated", Check: LintCheckAge, MaxDays: 14,
+				When:    &LintCondition{Statuses: []Status{StatusInProgress}},
+				Message: "Issue has been in-progress for {days} days", Suggestion: "Update the status or add recent activity",
+			},
+			{
+				ID: "priority-missing", Severity: LintSeverityInfo, Field: "priority", Check: LintCheckRequired,
+				Message: "Priority is not set", Suggestion: "Set priority to one of: low, medium, high, critical",
+			},
+			{
+				ID: "assignee-missing-in-progress", Severity: LintSeverityWarning, Field: "assignee", Check: LintCheckRequired,
+				When:    &LintCondition{Statuses: []Status{StatusInProgress}},
+				Message: "In-progress issue should have an assignee", Suggestion: "Assign this issue to someone or change status",
+			},
+			{
+				ID: "labels-missing", Severity: LintSeverityInfo, Field: "labels", Check: LintCheckRequired,
+				Message: "Issue has no labels", Suggestion: "Add relevant labels for categorization",
+			},
+			{
+				ID: "milestone-missing-high-priority", Severity: LintSeverityInfo, Field: "milestone", Check: LintCheckRequired,
+				When:    &LintCondition{Priorities: []Priority{PriorityHigh}},
+				Message: "High priority issue should have a milestone", Suggestion: "Assign this issue to a milestone",
+			},
+			{
+				ID: "estimate-missing", Severity: LintSeverityInfo, Field: "estimated_hours", Check: LintCheckRequired,
+				When:    &LintCondition{ExcludeTypes: []IssueType{IssueTypeEpic}},
+				Message: "Issue has no time estimate", Suggestion: "Add a time estimate for better planning",
+			},
+			{
+				ID: "time-overrun", Severity: LintSeverityWarning, Field: "actual_hours", Check: LintCheckOverrun, Ratio: 1.5,
+				Message:    "Actual time ({actual}h) significantly exceeds estimate ({estimate}h)",
+				Suggestion: "Review time estimates for future similar work",
+			},
+			{
+				ID: "branch-missing-in-progress", Severity: LintSeverityInfo, Field: "branch", Check: LintCheckRequired,
+				When:    &LintCondition{Statuses: []Status{StatusInProgress}},
+				Message: "In-progress issue should have an associated branch", Suggestion: "Create and link a branch for this issue",
+			},
+			{
+				ID: "comments-missing-old-issue", Severity: LintSeverityInfo, Field: "comments", Check: LintCheckRequired,
+				When:    &LintCondition{ExcludeStatuses: []Status{StatusClosed}, MinAgeDays: 8},
+				Message: "Issue is {days} days old with no comments", Suggestion: "Add status updates or progress comments",
+			},
+			{
+				ID: "issue-very-old", Severity: LintSeverityWarning, Field: "created", Check: LintCheckAge, MaxDays: 90,
+				When:    &LintCondition{Statuses: []Status{StatusOpen}},
+				Message: "Issue has been open for {days} days", Suggestion: "Review if this issue is still relevant or close it",
+			},
+		},
+	}
+}
@@ -0,0 +1,152 @@
+package entities
+
+import (
+	"path"
+	"sort"
+)
+
+// SARIF 2.1.0 output lets CI systems show lint violations as code scanning
+// results. Only the parts of the format lint needs are modelled.
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is the top-level SARIF document
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is the output of one tool invocation
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool and its rules
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the tool component that produced the results
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes a lint rule
+type SARIFRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     SARIFMessage       `json:"shortDescription"`
+	DefaultConfiguration SARIFConfiguration `json:"defaultConfiguration"`
+}
+
+// SARIFConfiguration holds a rule's default level
+type SARIFConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFResult is a single violation
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFMessage is plain message text
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFLocation points at the issue file and the issue itself
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []SARIFLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+// SARIFPhysicalLocation is a file relative to the repository root
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+}
+
+// SARIFArtifactLocation is the URI of a file
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFLogicalLocation names the issue a result belongs to
+type SARIFLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// SARIFLevel maps a lint severity to a SARIF level
+func SARIFLevel(severity string) string {
+	switch severity {
+	case LintSeverityError:
+		return "error"
+	case LintSeverityWarning:
+		return "warning"
+	}
+	return "note"
+}
+
+// NewLintSARIF converts lint results into a SARIF log. issuesDir is the
+// repository-relative directory holding issue files.
+func NewLintSARIF(rules []LintRule, results []LintResult, toolVersion, issuesDir string) *SARIFLog {
+	driver := SARIFDriver{
+		Name:           "issuemap-lint",
+		Version:        toolVersion,
+		InformationURI: "https://github.com/ooyeku/issuemap",
+		Rules:          []SARIFRule{},
+	}
+
+	ruleIndex := make(map[string]int, len(rules))
+	for _, rule := range rules {
+		description := rule.Description
+		if description == "" {
+			description = rule.Message
+		}
+		if description == "" {
+			description = rule.ID
+		}
+		ruleIndex[rule.ID] = len(driver.Rules)
+		driver.Rules = append(driver.Rules, SARIFRule{
+			ID:                   rule.ID,
+			ShortDescription:     SARIFMessage{Text: description},
+			DefaultConfiguration: SARIFConfiguration{Level: SARIFLevel(rule.Severity)},
+		})
+	}
+
+	sorted := append([]LintResult{}, results...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].IssueID < sorted[j].IssueID })
+
+	run := SARIFRun{Tool: SARIFTool{Driver: driver}, Results: []SARIFResult{}}
+	for _, result := range sorted {
+		uri := path.Join(issuesDir, string(result.IssueID)+".yaml")
+		for _, violation := range result.Violations {
+			index, ok := ruleIndex[violation.Rule]
+			if !ok {
+				index = -1
+			}
+			run.Results = append(run.Results, SARIFResult{
+				RuleID:    violation.Rule,
+				RuleIndex: index,
+				Level:     SARIFLevel(violation.Severity),
+				Message:   SARIFMessage{Text: string(result.IssueID) + ": " + violation.Message},
+				Locations: []SARIFLocation{{
+					PhysicalLocation: SARIFPhysicalLocation{ArtifactLocation: SARIFArtifactLocation{URI: uri}},
+					LogicalLocations: []SARIFLogicalLocation{{Name: string(result.IssueID), Kind: "object"}},
+				}},
+			})
+		}
+	}
+
+	return &SARIFLog{Version: SARIFVersion, Schema: SARIFSchema, Runs: []SARIFRun{run}}
+}
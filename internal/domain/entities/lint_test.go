@@ -0,0 +1,125 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultLintConfig_Valid(t *testing.T) {
+	rules, err := DefaultLintConfig().ActiveRules()
+	require.NoError(t, err)
+	assert.NotEmpty(t, rules)
+}
+
+func TestLintConfig_ActiveRules(t *testing.T) {
+	config := &LintConfig{
+		IncludeDefaults: true,
+		Rules: []LintRule{
+			{ID: "title-too-short", Severity: LintSeverityError, Field: "title", Check: LintCheckLength, Min: 20},
+			{ID: "labels-missing", Disabled: true},
+			{ID: "bug-labels", Severity: LintSeverityWarning, Field: "labels", Check: LintCheckAllowed,
+				Values: []string{"bug", "ui"}, When: &LintCondition{Types: []IssueType{IssueTypeBug}}},
+		},
+	}
+
+	rules, err := config.ActiveRules()
+	require.NoError(t, err)
+
+	byID := make(map[string]LintRule)
+	for _, rule := range rules {
+		byID[rule.ID] = rule
+	}
+	assert.Equal(t, 20, byID["title-too-short"].Min, "project rule replaces the built-in one")
+	assert.NotContains(t, byID, "labels-missing", "disabled rules are dropped")
+	assert.Contains(t, byID, "bug-labels")
+	assert.Contains(t, byID, "title-required")
+}
+
+func TestLintRule_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		rule LintRule
+	}{
+		{"missing id", LintRule{Severity: LintSeverityInfo, Field: "title", Check: LintCheckRequired}},
+		{"bad severity", LintRule{ID: "r", Severity: "fatal", Field: "title", Check: LintCheckRequired}},
+		{"unknown field", LintRule{ID: "r", Severity: LintSeverityInfo, Field: "owner", Check: LintCheckRequired}},
+		{"unknown check", LintRule{ID: "r", Severity: LintSeverityInfo, Field: "title", Check: "spelling"}},
+		{"bad pattern", LintRule{ID: "r", Severity: LintSeverityInfo, Field: "title", Check: LintCheckPattern, Pattern: "("}},
+		{"empty length", LintRule{ID: "r", Severity: LintSeverityInfo, Field: "title", Check: LintCheckLength}},
+		{"age on text", LintRule{ID: "r", Severity: LintSeverityInfo, Field: "title", Check: LintCheckAge, MaxDays: 3}},
+		{"set on title", LintRule{ID: "r", Severity: LintSeverityInfo, Field: "title", Check: LintCheckRequired,
+			Fix: &LintFix{Action: LintFixSet, Value: "x"}}},
+		{"remove without allowed", LintRule{ID: "r", Severity: LintSeverityInfo, Field: "labels", Check: LintCheckRequired,
+			Fix: &LintFix{Action: LintFixRemove}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, tt.rule.Validate())
+		})
+	}
+
+	valid := LintRule{ID: "priority", Severity: LintSeverityInfo, Field: "priority", Check: LintCheckRequired,
+		Fix: &LintFix{Action: LintFixSet, Value: "medium"}}
+	assert.NoError(t, valid.Validate())
+}
+
+func TestLintCondition_Matches(t *testing.T) {
+	issue := NewIssue("TEST-001", "Crash on save", "", IssueTypeBug)
+	issue.Priority = PriorityHigh
+
+	var none *LintCondition
+	assert.True(t, none.Matches(issue, 0))
+	assert.True(t, (&LintCondition{Types: []IssueType{IssueTypeBug}, Priorities: []Priority{PriorityHigh}}).Matches(issue, 0))
+	assert.False(t, (&LintCondition{ExcludeTypes: []IssueType{IssueTypeBug}}).Matches(issue, 0))
+	assert.False(t, (&LintCondition{Statuses: []Status{StatusInProgress}}).Matches(issue, 0))
+	assert.False(t, (&LintCondition{MinAgeDays: 3}).Matches(issue, 2))
+	assert.True(t, (&LintCondition{MinAgeDays: 3}).Matches(issue, 3))
+}
+
+func TestLintSummary_ThresholdExceeded(t *testing.T) {
+	results := []LintResult{{
+		IssueID: "TEST-001",
+		Violations: []LintViolation{
+			{Rule: "labels-missing", Severity: LintSeverityInfo},
+			{Rule: "description-required", Severity: LintSeverityWarning},
+		},
+		Score: 85,
+	}}
+	summary := NewLintSummary(results, 2)
+	assert.Equal(t, 92.5, summary.AverageScore, "issues without violations score 100")
+
+	assert.Empty(t, summary.ThresholdExceeded("", 0))
+	assert.Empty(t, summary.ThresholdExceeded(LintSeverityError, 0))
+	assert.Contains(t, summary.ThresholdExceeded(LintSeverityWarning, 0), "1 violation")
+	assert.Contains(t, summary.ThresholdExceeded(LintSeverityInfo, 0), "2 violation")
+	assert.Empty(t, summary.ThresholdExceeded("", 90))
+	assert.Contains(t, summary.ThresholdExceeded("", 95), "below 95")
+}
+
+func TestNewLintSARIF(t *testing.T) {
+	rules := []LintRule{
+		{ID: "title-required", Severity: LintSeverityError, Message: "Title is required"},
+		{ID: "labels-missing", Severity: LintSeverityInfo},
+	}
+	results := []LintResult{{
+		IssueID:    "TEST-002",
+		Violations: []LintViolation{{Rule: "labels-missing", Severity: LintSeverityInfo, Message: "Issue has no labels"}},
+	}}
+
+	log := NewLintSARIF(rules, results, "1.0.0", ".issuemap/issues")
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+	assert.Equal(t, SARIFVersion, log.Version)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "error", run.Tool.Driver.Rules[0].DefaultConfiguration.Level)
+
+	require.Len(t, run.Results, 1)
+	result := run.Results[0]
+	assert.Equal(t, "labels-missing", result.RuleID)
+	assert.Equal(t, 1, result.RuleIndex)
+	assert.Equal(t, "note", result.Level)
+	assert.Equal(t, ".issuemap/issues/TEST-002.yaml", result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "TEST-002", result.Locations[0].LogicalLocations[0].Name)
+}
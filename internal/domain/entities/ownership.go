@@ -0,0 +1,181 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+)
+
+// AssignmentStrategy picks the assignee among the members of a team
+type AssignmentStrategy string
+
+const (
+	// AssignmentStrategyLeastLoaded picks the member with the fewest open issues
+	AssignmentStrategyLeastLoaded AssignmentStrategy = "least_loaded"
+	// AssignmentStrategyRoundRobin picks members in turn
+	AssignmentStrategyRoundRobin AssignmentStrategy = "round_robin"
+)
+
+// ComponentField is the custom field naming the component of an issue
+const ComponentField = "component"
+
+// OwnersConfig configures code ownership based assignment
+type OwnersConfig struct {
+	// AutoAssign sets the assignee of new issues created without one;
+	// otherwise the owners are only suggested
+	AutoAssign bool `yaml:"auto_assign,omitempty" json:"auto_assign,omitempty"`
+	// CodeOwners is the CODEOWNERS file relative to the repository root,
+	// by default the first of CodeOwnersLocations that exists
+	CodeOwners string `yaml:"codeowners,omitempty" json:"codeowners,omitempty"`
+	// Strategy picks a member when a team owns an issue
+	Strategy AssignmentStrategy `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+	// Teams maps team names, such as @org/backend, to usernames. Teams of
+	// the user directory are used for owners not listed here.
+	Teams map[string][]string `yaml:"teams,omitempty" json:"teams,omitempty"`
+	// Rules assign owners by label and component
+	Rules []OwnerRule `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// OwnerRule names the owners of issues with one of the labels or
+// components
+type OwnerRule struct {
+	Labels     []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Components []string `yaml:"components,omitempty" json:"components,omitempty"`
+	Owners     []string `yaml:"owners" json:"owners"`
+}
+
+// Matches reports whether the rule applies to an issue and why
+func (r OwnerRule) Matches(issue *Issue) (string, bool) {
+	for _, label := range r.Labels {
+		for _, issueLabel := range issue.Labels {
+			if strings.EqualFold(issueLabel.Name, label) {
+				return "label " + issueLabel.Name, true
+			}
+		}
+	}
+	component := issue.Metadata.CustomFields[ComponentField]
+	for _, c := range r.Components {
+		if component != "" && strings.EqualFold(component, c) {
+			return "component " + component, true
+		}
+	}
+	return "", false
+}
+
+// StrategyOf returns the configured strategy, least loaded by default
+func (c *OwnersConfig) StrategyOf() AssignmentStrategy {
+	if c == nil || c.Strategy == "" {
+		return AssignmentStrategyLeastLoaded
+	}
+	return c.Strategy
+}
+
+// TeamMembers returns the members of a team, and whether owner names a
+// team. Owners of the form @org/team are teams even when their members are
+// not configured.
+func (c *OwnersConfig) TeamMembers(owner string) ([]string, bool) {
+	if c != nil {
+		if members, ok := c.Teams[owner]; ok {
+			return members, true
+		}
+	}
+	return nil, strings.HasPrefix(owner, "@") && strings.Contains(owner, "/")
+}
+
+// OwnerUsername returns the username of a CODEOWNERS owner: @alice is
+// alice, emails are kept as they are
+func OwnerUsername(owner string) string {
+	return strings.TrimPrefix(owner, "@")
+}
+
+// OwnershipEvidence is one reason an owner was suggested
+type OwnershipEvidence struct {
+	// Source is where the match came from, such as "description",
+	// "attachment trace.log", "commit 1a2b3c4d" or "label backend"
+	Source string `json:"source"`
+	// Path is the file that matched a CODEOWNERS rule, if any
+	Path    string `json:"path,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// OwnerCandidate is an owner with the evidence for them
+type OwnerCandidate struct {
+	Owner    string              `json:"owner"`
+	Team     bool                `json:"team,omitempty"`
+	Evidence []OwnershipEvidence `json:"evidence"`
+}
+
+// AssignmentSuggestion is the outcome of matching an issue against owners
+type AssignmentSuggestion struct {
+	IssueID    IssueID          `json:"issue_id"`
+	Candidates []OwnerCandidate `json:"candidates"`
+	// Assignee is the user picked, empty when no candidate resolves to one
+	Assignee string `json:"assignee,omitempty"`
+	// Via is the candidate the assignee was picked from, a team or the user
+	Via      string             `json:"via,omitempty"`
+	Strategy AssignmentStrategy `json:"strategy,omitempty"`
+}
+
+// OwnerCandidates collects evidence by owner and ranks owners by the
+// amount of evidence, earlier owners first on ties
+type OwnerCandidates struct {
+	candidates []OwnerCandidate
+	index      map[string]int
+}
+
+// NewOwnerCandidates creates an empty candidate list
+func NewOwnerCandidates() *OwnerCandidates {
+	return &OwnerCandidates{index: make(map[string]int)}
+}
+
+// Add records evidence for each owner
+func (c *OwnerCandidates) Add(owners []string, evidence OwnershipEvidence) {
+	for _, owner := range owners {
+		i, ok := c.index[owner]
+		if !ok {
+			i = len(c.candidates)
+			c.index[owner] = i
+			c.candidates = append(c.candidates, OwnerCandidate{Owner: owner})
+		}
+		c.candidates[i].Evidence = append(c.candidates[i].Evidence, evidence)
+	}
+}
+
+// Ranked returns the candidates, most evidence first
+func (c *OwnerCandidates) Ranked() []OwnerCandidate {
+	ranked := append([]OwnerCandidate{}, c.candidates...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return len(ranked[i].Evidence) > len(ranked[j].Evidence)
+	})
+	return ranked
+}
+
+// AssignmentState is the round-robin position of each team, kept between
+// assignments
+type AssignmentState struct {
+	RoundRobin map[string]int `yaml:"round_robin" json:"round_robin"`
+}
+
+// PickLeastLoaded returns the member with the fewest open issues, the first
+// listed on ties
+func PickLeastLoaded(members []string, load map[string]int) string {
+	best := ""
+	for _, member := range members {
+		if best == "" || load[member] < load[best] {
+			best = member
+		}
+	}
+	return best
+}
+
+// PickRoundRobin returns the member after the one picked last, and its
+// index to record as the new last pick. last is -1 before the first pick.
+func PickRoundRobin(members []string, last int) (string, int) {
+	if len(members) == 0 {
+		return "", last
+	}
+	next := (last + 1) % len(members)
+	if next < 0 {
+		next = 0
+	}
+	return members[next], next
+}
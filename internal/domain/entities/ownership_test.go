@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerRule_Matches(t *testing.T) {
+	issue := NewIssue("TEST-001", "Broken docs", "", IssueTypeBug)
+	issue.AddLabel(Label{Name: "Docs"})
+	issue.Metadata.CustomFields = map[string]string{ComponentField: "api"}
+
+	reason, ok := OwnerRule{Labels: []string{"docs"}}.Matches(issue)
+	assert.True(t, ok)
+	assert.Equal(t, "label Docs", reason)
+
+	reason, ok = OwnerRule{Components: []string{"API"}}.Matches(issue)
+	assert.True(t, ok)
+	assert.Equal(t, "component api", reason)
+
+	_, ok = OwnerRule{Labels: []string{"ui"}, Components: []string{"web"}}.Matches(issue)
+	assert.False(t, ok)
+}
+
+func TestOwnersConfig_TeamMembers(t *testing.T) {
+	config := &OwnersConfig{Teams: map[string][]string{"@acme/web": {"dave", "@erin"}, "frontend": {"dave"}}}
+
+	members, team := config.TeamMembers("@acme/web")
+	assert.True(t, team)
+	assert.Equal(t, []string{"dave", "@erin"}, members)
+
+	members, team = config.TeamMembers("frontend")
+	assert.True(t, team)
+	assert.Equal(t, []string{"dave"}, members)
+
+	members, team = config.TeamMembers("@acme/core")
+	assert.True(t, team)
+	assert.Empty(t, members)
+
+	_, team = config.TeamMembers("@alice")
+	assert.False(t, team)
+
+	var unset *OwnersConfig
+	assert.Equal(t, AssignmentStrategyLeastLoaded, unset.StrategyOf())
+	_, team = unset.TeamMembers("@alice")
+	assert.False(t, team)
+}
+
+func TestOwnerCandidates_Ranked(t *testing.T) {
+	candidates := NewOwnerCandidates()
+	candidates.Add([]string{"@alice", "@bob"}, OwnershipEvidence{Source: "description", Path: "a.go"})
+	candidates.Add([]string{"@bob"}, OwnershipEvidence{Source: "commit 1a2b3c4d", Path: "b.go"})
+	candidates.Add([]string{"@carol"}, OwnershipEvidence{Source: "label docs"})
+
+	ranked := candidates.Ranked()
+	var owners []string
+	for _, candidate := range ranked {
+		owners = append(owners, candidate.Owner)
+	}
+	assert.Equal(t, []string{"@bob", "@alice", "@carol"}, owners)
+	assert.Len(t, ranked[0].Evidence, 2)
+}
+
+func TestPickAssignee(t *testing.T) {
+	members := []string{"alice", "bob", "carol"}
+
+	assert.Equal(t, "bob", PickLeastLoaded(members, map[string]int{"alice": 3, "bob": 1, "carol": 1}))
+	assert.Equal(t, "alice", PickLeastLoaded(members, nil))
+	assert.Empty(t, PickLeastLoaded(nil, nil))
+
+	member, last := PickRoundRobin(members, -1)
+	assert.Equal(t, "alice", member)
+	member, last = PickRoundRobin(members, last)
+	assert.Equal(t, "bob", member)
+	member, last = PickRoundRobin(members, 2)
+	assert.Equal(t, "alice", member)
+	assert.Equal(t, 0, last)
+
+	// A shrunken team starts over rather than going out of range
+	member, _ = PickRoundRobin([]string{"alice"}, 5)
+	assert.Equal(t, "alice", member)
+}
@@ -0,0 +1,166 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// LandingMethod is how a pull request's commits reached its base branch
+type LandingMethod string
+
+const (
+	LandingMethodMerge  LandingMethod = "merge"  // the branch tip is on the base, by merge commit or fast-forward
+	LandingMethodSquash LandingMethod = "squash" // one base commit carries the whole branch diff
+	LandingMethodRebase LandingMethod = "rebase" // every branch commit was replayed onto the base
+)
+
+// PullRequestRef records the pull request opened for an issue's branch
+type PullRequestRef struct {
+	URL      string    `yaml:"url,omitempty" json:"url,omitempty"`
+	Remote   string    `yaml:"remote" json:"remote"`
+	Branch   string    `yaml:"branch" json:"branch"`
+	Base     string    `yaml:"base" json:"base"`
+	Head     string    `yaml:"head" json:"head"` // branch tip when the pull request was opened
+	OpenedAt time.Time `yaml:"opened_at" json:"opened_at"`
+
+	LandedAt      *time.Time    `yaml:"landed_at,omitempty" json:"landed_at,omitempty"`
+	LandedAs      LandingMethod `yaml:"landed_as,omitempty" json:"landed_as,omitempty"`
+	LandedCommits []string      `yaml:"landed_commits,omitempty" json:"landed_commits,omitempty"`
+}
+
+// Landed reports whether the pull request has been detected on its base
+func (p *PullRequestRef) Landed() bool {
+	return p != nil && p.LandedAt != nil
+}
+
+// PullRequestConfig configures 'issuemap pr'. When Command is set the
+// rendered description is piped to it on stdin to open the pull request with
+// a hosting provider's CLI; otherwise the description is printed. Args may use
+// the placeholders {title}, {branch}, {base}, {remote} and {issue}.
+type PullRequestConfig struct {
+	Remote  string   `yaml:"remote,omitempty" json:"remote,omitempty"`
+	Base    string   `yaml:"base,omitempty" json:"base,omitempty"`
+	Command string   `yaml:"command,omitempty" json:"command,omitempty"`
+	Args    []string `yaml:"args,omitempty" json:"args,omitempty"`
+}
+
+// DefaultPullRequestRemote is the remote branches are pushed to when none is
+// configured
+const DefaultPullRequestRemote = "origin"
+
+// ExpandArgs substitutes the placeholders in the configured arguments
+func (c *PullRequestConfig) ExpandArgs(description *PullRequestDescription, ref *PullRequestRef) []string {
+	replacer := strings.NewReplacer(
+		"{title}", description.Title,
+		"{branch}", ref.Branch,
+		"{base}", ref.Base,
+		"{remote}", ref.Remote,
+		"{issue}", string(description.IssueID),
+	)
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = replacer.Replace(arg)
+	}
+	return args
+}
+
+// PullRequestDependency is a related issue listed in a pull request
+// description
+type PullRequestDependency struct {
+	Relation string  `json:"relation"` // "Blocked by" or "Blocks"
+	ID       IssueID `json:"id"`
+	Title    string  `json:"title,omitempty"`
+	Status   Status  `json:"status,omitempty"`
+}
+
+// PullRequestDescription is the title and Markdown body rendered for an
+// issue's pull request
+type PullRequestDescription struct {
+	IssueID IssueID `json:"issue_id"`
+	Title   string  `json:"title"`
+	Body    string  `json:"body"`
+}
+
+// acceptanceHeading matches a Markdown heading introducing acceptance criteria
+var acceptanceHeading = regexp.MustCompile(`(?i)^(#{1,6})\s*acceptance criteria\s*:?\s*$`)
+
+// markdownHeading matches any Markdown heading and captures its level
+var markdownHeading = regexp.MustCompile(`^(#{1,6})\s`)
+
+// SplitAcceptanceCriteria separates an "Acceptance Criteria" section, as
+// written by the built-in templates, from the rest of a description
+func SplitAcceptanceCriteria(description string) (rest, criteria string) {
+	lines := strings.Split(description, "\n")
+	start := -1
+	level := 0
+	for i, line := range lines {
+		if match := acceptanceHeading.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			start, level = i, len(match[1])
+			break
+		}
+	}
+	if start == -1 {
+		return strings.TrimSpace(description), ""
+	}
+
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if match := markdownHeading.FindStringSubmatch(strings.TrimSpace(lines[i])); match != nil && len(match[1]) <= level {
+			end = i
+			break
+		}
+	}
+
+	criteria = strings.TrimSpace(strings.Join(lines[start+1:end], "\n"))
+	remaining := append(append([]string{}, lines[:start]...), lines[end:]...)
+	return strings.TrimSpace(strings.Join(remaining, "\n")), criteria
+}
+
+// RenderPullRequest renders the pull request title and body for an issue.
+// Acceptance criteria come from the description's "Acceptance Criteria"
+// section or, failing that, the acceptance_criteria custom field.
+func RenderPullRequest(issue *Issue, dependencies []PullRequestDependency) *PullRequestDescription {
+	description, criteria := SplitAcceptanceCriteria(issue.Description)
+	if criteria == "" && issue.Metadata.CustomFields != nil {
+		criteria = strings.TrimSpace(issue.Metadata.CustomFields["acceptance_criteria"])
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Resolves %s\n", issue.ID)
+
+	body.WriteString("\n## Description\n\n")
+	if description != "" {
+		body.WriteString(description)
+		body.WriteString("\n")
+	} else {
+		body.WriteString("_No description provided._\n")
+	}
+
+	if criteria != "" {
+		body.WriteString("\n## Acceptance Criteria\n\n")
+		body.WriteString(criteria)
+		body.WriteString("\n")
+	}
+
+	if len(dependencies) > 0 {
+		body.WriteString("\n## Dependencies\n\n")
+		for _, dep := range dependencies {
+			line := fmt.Sprintf("- %s %s", dep.Relation, dep.ID)
+			if dep.Title != "" {
+				line += ": " + dep.Title
+			}
+			if dep.Status != "" {
+				line += fmt.Sprintf(" (%s)", dep.Status)
+			}
+			body.WriteString(line + "\n")
+		}
+	}
+
+	return &PullRequestDescription{
+		IssueID: issue.ID,
+		Title:   fmt.Sprintf("%s: %s", issue.ID, issue.Title),
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,64 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAcceptanceCriteria(t *testing.T) {
+	rest, criteria := SplitAcceptanceCriteria("## Summary\nAdd it.\n\n## Acceptance Criteria\n- [ ] one\n- [ ] two\n\n## Notes\nLater.")
+	assert.Equal(t, "## Summary\nAdd it.\n\n## Notes\nLater.", rest)
+	assert.Equal(t, "- [ ] one\n- [ ] two", criteria)
+
+	// Subsections belong to the criteria
+	rest, criteria = SplitAcceptanceCriteria("Intro\n# Acceptance criteria:\n- a\n## Edge cases\n- b")
+	assert.Equal(t, "Intro", rest)
+	assert.Equal(t, "- a\n## Edge cases\n- b", criteria)
+
+	rest, criteria = SplitAcceptanceCriteria("Just a description\n")
+	assert.Equal(t, "Just a description", rest)
+	assert.Empty(t, criteria)
+}
+
+func TestRenderPullRequest(t *testing.T) {
+	issue := NewIssue("TEST-001", "Add login", "Session cookies.\n\n## Acceptance Criteria\n- [ ] logs in", IssueTypeFeature)
+	description := RenderPullRequest(issue, []PullRequestDependency{
+		{Relation: "Blocked by", ID: "TEST-002", Title: "Add users", Status: StatusDone},
+		{Relation: "Blocks", ID: "TEST-003"},
+	})
+
+	assert.Equal(t, "TEST-001: Add login", description.Title)
+	assert.Equal(t, `Resolves TEST-001
+
+## Description
+
+Session cookies.
+
+## Acceptance Criteria
+
+- [ ] logs in
+
+## Dependencies
+
+- Blocked by TEST-002: Add users (done)
+- Blocks TEST-003
+`, description.Body)
+
+	// Criteria fall back to the template's custom field
+	issue = NewIssue("TEST-004", "Empty", "", IssueTypeTask)
+	issue.Metadata.CustomFields["acceptance_criteria"] = "It works"
+	description = RenderPullRequest(issue, nil)
+	assert.Contains(t, description.Body, "_No description provided._")
+	assert.Contains(t, description.Body, "## Acceptance Criteria\n\nIt works\n")
+	assert.NotContains(t, description.Body, "## Dependencies")
+}
+
+func TestPullRequestConfigExpandArgs(t *testing.T) {
+	config := &PullRequestConfig{Command: "gh", Args: []string{"pr", "create", "--base", "{base}", "--head", "{branch}", "--title", "{title}", "--label={issue}"}}
+	args := config.ExpandArgs(
+		&PullRequestDescription{IssueID: "TEST-001", Title: "TEST-001: Add login"},
+		&PullRequestRef{Remote: "origin", Branch: "feature/TEST-001-add-login", Base: "main"},
+	)
+	assert.Equal(t, []string{"pr", "create", "--base", "main", "--head", "feature/TEST-001-add-login", "--title", "TEST-001: Add login", "--label=TEST-001"}, args)
+}
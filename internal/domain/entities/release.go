@@ -0,0 +1,257 @@
+package entities
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ReleaseConfig configures 'issuemap release notes'
+type ReleaseConfig struct {
+	// Sections group closed issues in the notes. An issue goes in the first
+	// section matching its type or one of its labels; a section without
+	// types and labels matches every issue.
+	Sections []ReleaseSection `yaml:"sections,omitempty" json:"sections,omitempty"`
+	// Template is the notes template, relative to .issuemap
+	Template string `yaml:"template,omitempty" json:"template,omitempty"`
+	// Changelog is the changelog file, relative to the repository root
+	Changelog string `yaml:"changelog,omitempty" json:"changelog,omitempty"`
+}
+
+// ReleaseSection is a heading of the release notes and the issues under it
+type ReleaseSection struct {
+	Title  string      `yaml:"title" json:"title"`
+	Types  []IssueType `yaml:"types,omitempty" json:"types,omitempty"`
+	Labels []string    `yaml:"labels,omitempty" json:"labels,omitempty"`
+}
+
+// Defaults for release notes
+const (
+	DefaultReleaseTemplate  = "templates/release_notes.md.tmpl"
+	DefaultChangelog        = "CHANGELOG.md"
+	UnreleasedVersion       = "Unreleased"
+	keepAChangelogReference = "https://keepachangelog.com/en/1.1.0/"
+)
+
+// DefaultReleaseSections uses the Keep a Changelog change types. Label
+// sections come first so a security bug fix is listed under Security.
+func DefaultReleaseSections() []ReleaseSection {
+	return []ReleaseSection{
+		{Title: "Security", Labels: []string{"security"}},
+		{Title: "Deprecated", Labels: []string{"deprecated", "deprecation"}},
+		{Title: "Removed", Labels: []string{"removed", "removal"}},
+		{Title: "Added", Types: []IssueType{IssueTypeFeature, IssueTypeEpic}},
+		{Title: "Fixed", Types: []IssueType{IssueTypeBug}},
+		{Title: "Changed"},
+	}
+}
+
+// ReleaseSectionsOf returns the configured sections or the defaults
+func ReleaseSectionsOf(config *ReleaseConfig) []ReleaseSection {
+	if config == nil || len(config.Sections) == 0 {
+		return DefaultReleaseSections()
+	}
+	return config.Sections
+}
+
+// Matches reports whether an issue belongs in the section
+func (s ReleaseSection) Matches(issue *Issue) bool {
+	if len(s.Types) == 0 && len(s.Labels) == 0 {
+		return true
+	}
+	for _, issueType := range s.Types {
+		if issue.Type == issueType {
+			return true
+		}
+	}
+	for _, label := range s.Labels {
+		for _, issueLabel := range issue.Labels {
+			if strings.EqualFold(issueLabel.Name, label) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReleaseNote is an issue as listed in release notes
+type ReleaseNote struct {
+	ID       IssueID    `json:"id"`
+	Title    string     `json:"title"`
+	Type     IssueType  `json:"type"`
+	Labels   []string   `json:"labels,omitempty"`
+	Assignee string     `json:"assignee,omitempty"`
+	URL      string     `json:"url,omitempty"`
+	Closed   *time.Time `json:"closed,omitempty"`
+	Commits  []string   `json:"commits,omitempty"`
+}
+
+// ReleaseNotesSection is a section of release notes with its issues
+type ReleaseNotesSection struct {
+	Title  string        `json:"title"`
+	Issues []ReleaseNote `json:"issues"`
+}
+
+// ReleaseNotes are the issues closed between two git refs, grouped into
+// sections. Empty sections are omitted.
+type ReleaseNotes struct {
+	Version  string                `json:"version"`
+	Date     time.Time             `json:"date"`
+	From     string                `json:"from,omitempty"`
+	To       string                `json:"to"`
+	Count    int                   `json:"count"`
+	Sections []ReleaseNotesSection `json:"sections"`
+}
+
+// NewReleaseNotes groups issues into sections in the order given
+func NewReleaseNotes(version, from, to string, date time.Time, sections []ReleaseSection, issues []*Issue) *ReleaseNotes {
+	notes := &ReleaseNotes{Version: version, Date: date, From: from, To: to, Count: len(issues), Sections: []ReleaseNotesSection{}}
+
+	grouped := make([][]ReleaseNote, len(sections))
+	for _, issue := range issues {
+		for i, section := range sections {
+			if section.Matches(issue) {
+				grouped[i] = append(grouped[i], newReleaseNote(issue))
+				break
+			}
+		}
+	}
+	for i, section := range sections {
+		if len(grouped[i]) > 0 {
+			notes.Sections = append(notes.Sections, ReleaseNotesSection{Title: section.Title, Issues: grouped[i]})
+		}
+	}
+	return notes
+}
+
+func newReleaseNote(issue *Issue) ReleaseNote {
+	note := ReleaseNote{ID: issue.ID, Title: issue.Title, Type: issue.Type, Closed: issue.Timestamps.Closed}
+	for _, label := range issue.Labels {
+		note.Labels = append(note.Labels, label.Name)
+	}
+	if issue.Assignee != nil {
+		note.Assignee = issue.Assignee.Username
+	}
+	if issue.PullRequest != nil {
+		note.URL = issue.PullRequest.URL
+	}
+	for _, commit := range issue.Commits {
+		note.Commits = append(note.Commits, commit.Hash)
+	}
+	return note
+}
+
+// DefaultReleaseNotesTemplate renders a Keep a Changelog version section;
+// like the format, it leaves Unreleased undated. Templates are Go
+// text/templates executed with ReleaseNotes.
+const DefaultReleaseNotesTemplate = `## [{{ .Version }}]{{ if ne .Version "` + UnreleasedVersion + `" }} - {{ .Date.Format "2006-01-02" }}{{ end }}
+{{- range .Sections }}
+
+### {{ .Title }}
+{{ range .Issues }}
+- {{ .Title }} ({{ .ID }}{{ if .URL }}, {{ .URL }}{{ end }})
+{{- end }}
+{{- end }}
+{{- if not .Sections }}
+
+No issues were closed in this release.
+{{- end }}
+`
+
+// RenderReleaseNotes executes a notes template
+func RenderReleaseNotes(notes *ReleaseNotes, text string) (string, error) {
+	tmpl, err := template.New("release_notes").Option("missingkey=error").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid release notes template: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, notes); err != nil {
+		return "", fmt.Errorf("failed to render release notes: %w", err)
+	}
+	return strings.TrimRight(out.String(), "\n") + "\n", nil
+}
+
+// changelogVersionHeading matches a Keep a Changelog version heading and
+// captures the version
+var changelogVersionHeading = regexp.MustCompile(`^## \[([^\]]+)\]`)
+
+// NewChangelog returns an empty Keep a Changelog file
+func NewChangelog() string {
+	return "# Changelog\n\n" +
+		"All notable changes to this project will be documented in this file.\n\n" +
+		"The format is based on [Keep a Changelog](" + keepAChangelogReference + ").\n\n" +
+		"## [" + UnreleasedVersion + "]\n"
+}
+
+// changelogLinkReference matches the link reference definitions that end a
+// Keep a Changelog file, such as "[1.0.0]: https://..."
+var changelogLinkReference = regexp.MustCompile(`^\[[^\]]+\]: `)
+
+// UpdateChangelog puts a rendered version section into a Keep a Changelog
+// file: it replaces the section of the same version, or is inserted above
+// the newest release, below [Unreleased]. An empty changelog is created.
+func UpdateChangelog(changelog, version, section string) string {
+	if strings.TrimSpace(changelog) == "" {
+		changelog = NewChangelog()
+	}
+	lines := strings.Split(strings.TrimRight(changelog, "\n"), "\n")
+	sectionLines := strings.Split(strings.TrimRight(section, "\n"), "\n")
+
+	// The file ends with its sections or, if present, link references
+	footer := len(lines)
+	for footer > 0 && (lines[footer-1] == "" || changelogLinkReference.MatchString(lines[footer-1])) {
+		footer--
+	}
+	for footer < len(lines) && lines[footer] == "" {
+		footer++
+	}
+
+	start, end, insertAt := -1, footer, footer
+	for i := 0; i < footer; i++ {
+		match := changelogVersionHeading.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		if start != -1 && end == footer {
+			end = i
+		}
+		switch {
+		case strings.EqualFold(match[1], version):
+			start = i
+		case insertAt == footer && !strings.EqualFold(match[1], UnreleasedVersion):
+			insertAt = i
+		}
+	}
+
+	var out []string
+	if start != -1 {
+		out = append(out, lines[:start]...)
+		out = append(out, sectionLines...)
+		out = append(out, "")
+		out = append(out, lines[end:]...)
+	} else {
+		out = append(out, lines[:insertAt]...)
+		if insertAt > 0 && lines[insertAt-1] != "" {
+			out = append(out, "")
+		}
+		out = append(out, sectionLines...)
+		out = append(out, "")
+		out = append(out, lines[insertAt:]...)
+	}
+
+	// Collapse the blank lines left around replaced sections
+	var result []string
+	for i, line := range out {
+		if line == "" && i > 0 && out[i-1] == "" {
+			continue
+		}
+		result = append(result, line)
+	}
+	return strings.TrimRight(strings.Join(result, "\n"), "\n") + "\n"
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReleaseNotes(t *testing.T) {
+	feature := NewIssue("TEST-001", "Add export", "", IssueTypeFeature)
+	bug := NewIssue("TEST-002", "Fix crash", "", IssueTypeBug)
+	securityBug := NewIssue("TEST-003", "Escape input", "", IssueTypeBug)
+	securityBug.Labels = []Label{{Name: "Security"}}
+	chore := NewIssue("TEST-004", "Bump deps", "", IssueTypeTask)
+
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	notes := NewReleaseNotes("1.2.0", "v1.1.0", "v1.2.0", date, DefaultReleaseSections(),
+		[]*Issue{feature, bug, securityBug, chore})
+
+	assert.Equal(t, 4, notes.Count)
+	require.Len(t, notes.Sections, 4)
+	assert.Equal(t, "Security", notes.Sections[0].Title)
+	assert.Equal(t, IssueID("TEST-003"), notes.Sections[0].Issues[0].ID)
+	assert.Equal(t, "Added", notes.Sections[1].Title)
+	assert.Equal(t, "Fixed", notes.Sections[2].Title)
+	require.Len(t, notes.Sections[2].Issues, 1)
+	assert.Equal(t, "Changed", notes.Sections[3].Title)
+
+	// Issues matching no configured section are left out
+	notes = NewReleaseNotes("1.2.0", "", "HEAD", date, []ReleaseSection{{Title: "Fixes", Types: []IssueType{IssueTypeBug}}},
+		[]*Issue{feature, bug})
+	require.Len(t, notes.Sections, 1)
+	assert.Len(t, notes.Sections[0].Issues, 1)
+}
+
+func TestRenderReleaseNotes(t *testing.T) {
+	bug := NewIssue("TEST-002", "Fix crash", "", IssueTypeBug)
+	bug.PullRequest = &PullRequestRef{URL: "https://example.com/pr/7"}
+	date := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	notes := NewReleaseNotes("1.2.0", "v1.1.0", "v1.2.0", date, DefaultReleaseSections(), []*Issue{bug})
+
+	rendered, err := RenderReleaseNotes(notes, DefaultReleaseNotesTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, "## [1.2.0] - 2026-03-01\n\n### Fixed\n\n- Fix crash (TEST-002, https://example.com/pr/7)\n", rendered)
+
+	empty := NewReleaseNotes("1.2.1", "v1.2.0", "v1.2.1", date, DefaultReleaseSections(), nil)
+	rendered, err = RenderReleaseNotes(empty, DefaultReleaseNotesTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, "## [1.2.1] - 2026-03-01\n\nNo issues were closed in this release.\n", rendered)
+
+	unreleased := NewReleaseNotes(UnreleasedVersion, "v1.2.0", "HEAD", date, DefaultReleaseSections(), []*Issue{bug})
+	rendered, err = RenderReleaseNotes(unreleased, DefaultReleaseNotesTemplate)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rendered, "## [Unreleased]\n\n### Fixed\n"))
+
+	_, err = RenderReleaseNotes(notes, "{{ .Missing }}")
+	assert.Error(t, err)
+	_, err = RenderReleaseNotes(notes, "{{ .Version ")
+	assert.Error(t, err)
+}
+
+func TestUpdateChangelog(t *testing.T) {
+	section := "## [1.0.0] - 2026-01-01\n\n### Added\n\n- First (TEST-001)\n"
+	changelog := UpdateChangelog("", "1.0.0", section)
+	assert.Equal(t, NewChangelog()+"\n"+section, changelog)
+
+	// A newer release goes below Unreleased, above older releases, and
+	// before the link references
+	changelog += "\n[1.0.0]: https://example.com/v1.0.0\n"
+	newer := "## [1.1.0] - 2026-02-01\n\n### Fixed\n\n- Crash (TEST-002)\n"
+	changelog = UpdateChangelog(changelog, "1.1.0", newer)
+	assert.Equal(t, NewChangelog()+"\n"+newer+"\n"+section+"\n[1.0.0]: https://example.com/v1.0.0\n", changelog)
+
+	// Regenerating a release replaces its section
+	replaced := "## [1.1.0] - 2026-02-02\n\n### Fixed\n\n- Crash (TEST-002)\n- Leak (TEST-003)\n"
+	changelog = UpdateChangelog(changelog, "1.1.0", replaced)
+	assert.Equal(t, NewChangelog()+"\n"+replaced+"\n"+section+"\n[1.0.0]: https://example.com/v1.0.0\n", changelog)
+
+	// The last section is replaced up to the link references
+	older := "## [1.0.0] - 2026-01-01\n\n### Added\n\n- First (TEST-001)\n- Second (TEST-004)\n"
+	changelog = UpdateChangelog(changelog, "1.0.0", older)
+	assert.Equal(t, NewChangelog()+"\n"+replaced+"\n"+older+"\n[1.0.0]: https://example.com/v1.0.0\n", changelog)
+}
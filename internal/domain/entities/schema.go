@@ -0,0 +1,160 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SchemaKind identifies an on-disk file format under the .issuemap directory
+type SchemaKind string
+
+const (
+	SchemaKindIssue      SchemaKind = "issue"
+	SchemaKindHistory    SchemaKind = "history"
+	SchemaKindDependency SchemaKind = "dependency"
+	SchemaKindTimeEntry  SchemaKind = "time_entry"
+	SchemaKindConfig     SchemaKind = "config"
+)
+
+// schemaVersions holds the current format version of each kind. Bump a
+// version when its entity type changes incompatibly, add a SchemaMigration
+// from the previous version and regenerate docs/schemas.
+var schemaVersions = map[SchemaKind]int{
+	SchemaKindIssue:      1,
+	SchemaKindHistory:    1,
+	SchemaKindDependency: 1,
+	SchemaKindTimeEntry:  1,
+	SchemaKindConfig:     1,
+}
+
+// SchemaKinds returns every kind in a stable order
+func SchemaKinds() []SchemaKind {
+	return []SchemaKind{SchemaKindIssue, SchemaKindHistory, SchemaKindDependency, SchemaKindTimeEntry, SchemaKindConfig}
+}
+
+// ParseSchemaKind parses a kind name, accepting "time-entry" for time_entry
+func ParseSchemaKind(name string) (SchemaKind, error) {
+	kind := SchemaKind(strings.ReplaceAll(strings.ToLower(name), "-", "_"))
+	if _, ok := schemaVersions[kind]; !ok {
+		return "", fmt.Errorf("unknown schema kind %q (expected issue, history, dependency, time_entry or config)", name)
+	}
+	return kind, nil
+}
+
+// CurrentSchemaVersion returns the version files of a kind are written in
+func CurrentSchemaVersion(kind SchemaKind) int {
+	return schemaVersions[kind]
+}
+
+// SchemaKindForPath returns the kind of file at a path relative to the
+// .issuemap directory, or false when the path is not a versioned file
+func SchemaKindForPath(relPath string) (SchemaKind, bool) {
+	relPath = strings.TrimPrefix(strings.ReplaceAll(relPath, "\\", "/"), "./")
+	if relPath == "config.yaml" {
+		return SchemaKindConfig, true
+	}
+
+	dir, name, ok := strings.Cut(relPath, "/")
+	if !ok || strings.Contains(name, "/") || !strings.HasSuffix(name, ".yaml") {
+		return "", false
+	}
+	switch dir {
+	case "issues":
+		return SchemaKindIssue, true
+	case "history":
+		return SchemaKindHistory, true
+	case "dependencies":
+		return SchemaKindDependency, true
+	case "time_entries":
+		return SchemaKindTimeEntry, true
+	}
+	return "", false
+}
+
+// SchemaVersionError is returned for files written by a newer issuemap
+type SchemaVersionError struct {
+	Kind      SchemaKind
+	Version   int
+	Supported int
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("%s file has schema version %d but this issuemap supports up to version %d; upgrade issuemap to read it",
+		e.Kind, e.Version, e.Supported)
+}
+
+// SchemaMigration upgrades a stored document of one kind from version From
+// to From+1. Documents are generic YAML maps so migrations can rename, move
+// or drop fields that no longer exist on the entity types.
+type SchemaMigration struct {
+	Kind        SchemaKind
+	From        int
+	Description string
+	Migrate     func(doc map[string]interface{}) error
+}
+
+// schemaMigrations lists every migration in order. Version 0 is any file
+// written before formats were versioned; upgrading it only adds the
+// schema_version field.
+var schemaMigrations = []SchemaMigration{
+	{Kind: SchemaKindIssue, From: 0, Description: "Add schema_version to unversioned issues"},
+	{Kind: SchemaKindHistory, From: 0, Description: "Add schema_version to unversioned histories"},
+	{Kind: SchemaKindDependency, From: 0, Description: "Add schema_version to unversioned dependencies"},
+	{Kind: SchemaKindTimeEntry, From: 0, Description: "Add schema_version to unversioned time entries"},
+	{Kind: SchemaKindConfig, From: 0, Description: "Add schema_version to unversioned configs"},
+}
+
+// SchemaMigrations returns the migrations registered for a kind in order
+func SchemaMigrations(kind SchemaKind) []SchemaMigration {
+	var migrations []SchemaMigration
+	for _, migration := range schemaMigrations {
+		if migration.Kind == kind {
+			migrations = append(migrations, migration)
+		}
+	}
+	return migrations
+}
+
+// DocumentSchemaVersion returns the schema_version of a generic document,
+// zero when it has none
+func DocumentSchemaVersion(doc map[string]interface{}) int {
+	switch version := doc["schema_version"].(type) {
+	case int:
+		return version
+	case int64:
+		return int(version)
+	case uint64:
+		return int(version)
+	case float64:
+		return int(version)
+	}
+	return 0
+}
+
+// MigrateDocument upgrades a generic document of a kind to the current
+// schema version in place and returns the version it was written in
+func MigrateDocument(kind SchemaKind, doc map[string]interface{}) (int, error) {
+	from := DocumentSchemaVersion(doc)
+	current := CurrentSchemaVersion(kind)
+	if from > current {
+		return from, &SchemaVersionError{Kind: kind, Version: from, Supported: current}
+	}
+
+	version := from
+	for _, migration := range SchemaMigrations(kind) {
+		if migration.From != version {
+			continue
+		}
+		if migration.Migrate != nil {
+			if err := migration.Migrate(doc); err != nil {
+				return from, fmt.Errorf("migrate %s from version %d: %w", kind, version, err)
+			}
+		}
+		version++
+		doc["schema_version"] = version
+	}
+	if version != current {
+		return from, fmt.Errorf("no migration for %s from version %d", kind, version)
+	}
+	return from, nil
+}
@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaKindForPath(t *testing.T) {
+	tests := []struct {
+		path string
+		kind SchemaKind
+		ok   bool
+	}{
+		{"config.yaml", SchemaKindConfig, true},
+		{"issues/ISSUE-001.yaml", SchemaKindIssue, true},
+		{"history/ISSUE-001.yaml", SchemaKindHistory, true},
+		{"dependencies/A-blocks-B.yaml", SchemaKindDependency, true},
+		{"time_entries/te_1.yaml", SchemaKindTimeEntry, true},
+		{"issues/nested/ISSUE-001.yaml", "", false},
+		{"attachments/.metadata/att.yaml", "", false},
+		{"lint.yaml", "", false},
+		{"issues/README.md", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			kind, ok := SchemaKindForPath(tt.path)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.kind, kind)
+		})
+	}
+}
+
+func TestParseSchemaKind(t *testing.T) {
+	kind, err := ParseSchemaKind("time-entry")
+	require.NoError(t, err)
+	assert.Equal(t, SchemaKindTimeEntry, kind)
+
+	_, err = ParseSchemaKind("attachment")
+	assert.Error(t, err)
+}
+
+func TestMigrateDocument(t *testing.T) {
+	for _, kind := range SchemaKinds() {
+		doc := map[string]interface{}{"id": "TEST-001"}
+		from, err := MigrateDocument(kind, doc)
+		require.NoError(t, err, kind)
+		assert.Equal(t, 0, from)
+		assert.Equal(t, CurrentSchemaVersion(kind), DocumentSchemaVersion(doc))
+	}
+
+	_, err := MigrateDocument(SchemaKindIssue, map[string]interface{}{"schema_version": 99})
+	var versionErr *SchemaVersionError
+	require.ErrorAs(t, err, &versionErr)
+	assert.Equal(t, 99, versionErr.Version)
+	assert.Contains(t, err.Error(), "upgrade issuemap")
+}
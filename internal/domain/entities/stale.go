@@ -0,0 +1,256 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StaleActivityTolerance is how long after the stale bot marks an issue an
+// update is still attributed to the bot rather than to new activity
+const StaleActivityTolerance = time.Minute
+
+// StaleConfig configures the stale issue sweeper
+type StaleConfig struct {
+	// Enable the scheduled sweep; the CLI can always run it manually
+	Enabled bool `yaml:"enabled" json:"enabled"`
+
+	// Label added to issues that have been marked stale
+	Label string `yaml:"label" json:"label"`
+
+	// Author recorded on the bot's comments and history entries
+	Author string `yaml:"author" json:"author"`
+
+	// DaysUntilStale is the number of days without activity before an issue
+	// is marked stale; 0 disables marking
+	DaysUntilStale int `yaml:"days_until_stale" json:"days_until_stale"`
+
+	// DaysUntilClose is the grace period after marking before a stale issue
+	// is closed; 0 leaves stale issues open
+	DaysUntilClose int `yaml:"days_until_close" json:"days_until_close"`
+
+	// Issues with any of these labels or milestones are never marked stale
+	ExemptLabels     []string `yaml:"exempt_labels,omitempty" json:"exempt_labels,omitempty"`
+	ExemptMilestones []string `yaml:"exempt_milestones,omitempty" json:"exempt_milestones,omitempty"`
+
+	// Comments posted when an issue is marked stale and when it is closed.
+	// %d in StaleMessage is replaced with the days until close.
+	StaleMessage string `yaml:"stale_message" json:"stale_message"`
+	CloseMessage string `yaml:"close_message" json:"close_message"`
+
+	// Policies override the thresholds for matching issues. The first
+	// matching policy applies.
+	Policies []StalePolicy `yaml:"policies,omitempty" json:"policies,omitempty"`
+}
+
+// StalePolicy sets stale thresholds for issues matching its labels, types
+// and priorities. Empty criteria match every issue; all non-empty criteria
+// must match. A zero threshold inherits the global value and a negative one
+// disables that step.
+type StalePolicy struct {
+	Labels     []string    `yaml:"labels,omitempty" json:"labels,omitempty"`
+	Types      []IssueType `yaml:"types,omitempty" json:"types,omitempty"`
+	Priorities []Priority  `yaml:"priorities,omitempty" json:"priorities,omitempty"`
+
+	DaysUntilStale int  `yaml:"days_until_stale" json:"days_until_stale"`
+	DaysUntilClose int  `yaml:"days_until_close" json:"days_until_close"`
+	Exempt         bool `yaml:"exempt,omitempty" json:"exempt,omitempty"`
+}
+
+// DefaultStaleConfig returns the default stale configuration
+func DefaultStaleConfig() *StaleConfig {
+	return &StaleConfig{
+		Enabled:        false,
+		Label:          "stale",
+		Author:         "issuemap-stale",
+		DaysUntilStale: 60,
+		DaysUntilClose: 14,
+		ExemptLabels:   []string{"pinned", "security"},
+		StaleMessage: "This issue has had no activity recently and has been marked stale. " +
+			"It will be closed in %d days unless there is new activity.",
+		CloseMessage: "Closed after remaining stale with no further activity.",
+	}
+}
+
+// Matches reports whether the policy applies to an issue
+func (p *StalePolicy) Matches(issue *Issue) bool {
+	if len(p.Types) > 0 {
+		matched := false
+		for _, t := range p.Types {
+			if t == issue.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(p.Priorities) > 0 {
+		matched := false
+		for _, priority := range p.Priorities {
+			if priority == issue.Priority {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(p.Labels) > 0 && !issueHasAnyLabel(issue, p.Labels) {
+		return false
+	}
+
+	return true
+}
+
+// StaleAction is what the sweeper does to an issue
+type StaleAction string
+
+const (
+	StaleActionNone   StaleAction = "none"
+	StaleActionMark   StaleAction = "mark"
+	StaleActionClose  StaleAction = "close"
+	StaleActionUnmark StaleAction = "unmark"
+	StaleActionExempt StaleAction = "exempt"
+)
+
+// StaleDecision is the outcome of evaluating an issue against the policy
+type StaleDecision struct {
+	IssueID      IssueID     `json:"issue_id"`
+	Title        string      `json:"title"`
+	Action       StaleAction `json:"action"`
+	InactiveDays int         `json:"inactive_days"`
+
+	// DaysUntilClose is the grace period used when marking the issue
+	DaysUntilClose int `json:"days_until_close,omitempty"`
+}
+
+// Thresholds returns the stale and close thresholds for an issue and
+// whether it is exempt
+func (c *StaleConfig) Thresholds(issue *Issue) (staleDays, closeDays int, exempt bool) {
+	if issueHasAnyLabel(issue, c.ExemptLabels) {
+		return 0, 0, true
+	}
+	if issue.Milestone != nil {
+		for _, milestone := range c.ExemptMilestones {
+			if strings.EqualFold(milestone, issue.Milestone.Name) {
+				return 0, 0, true
+			}
+		}
+	}
+
+	staleDays, closeDays = c.DaysUntilStale, c.DaysUntilClose
+	for i := range c.Policies {
+		policy := &c.Policies[i]
+		if !policy.Matches(issue) {
+			continue
+		}
+		if policy.Exempt {
+			return 0, 0, true
+		}
+		if policy.DaysUntilStale != 0 {
+			staleDays = policy.DaysUntilStale
+		}
+		if policy.DaysUntilClose != 0 {
+			closeDays = policy.DaysUntilClose
+		}
+		break
+	}
+
+	return max(staleDays, 0), max(closeDays, 0), false
+}
+
+// Evaluate decides what the sweeper should do with an issue at time now.
+// Closed and done issues are left alone. An issue carrying the stale label
+// is unmarked if it was updated after it was marked, and closed once the
+// grace period has passed.
+func (c *StaleConfig) Evaluate(issue *Issue, now time.Time) StaleDecision {
+	decision := StaleDecision{
+		IssueID: issue.ID,
+		Title:   issue.Title,
+		Action:  StaleActionNone,
+	}
+
+	if issue.Status == StatusClosed || issue.Status == StatusDone {
+		return decision
+	}
+
+	staleDays, closeDays, exempt := c.Thresholds(issue)
+	marked := issueHasAnyLabel(issue, []string{c.Label})
+
+	if !marked {
+		decision.InactiveDays = int(now.Sub(issue.Timestamps.Updated).Hours() / 24)
+		switch {
+		case exempt:
+			decision.Action = StaleActionExempt
+		case staleDays > 0 && decision.InactiveDays >= staleDays:
+			decision.Action = StaleActionMark
+			decision.DaysUntilClose = closeDays
+		}
+		return decision
+	}
+
+	markedAt := c.MarkedAt(issue)
+	decision.InactiveDays = int(now.Sub(markedAt).Hours()/24) + staleDays
+
+	switch {
+	case issue.Timestamps.Updated.After(markedAt.Add(StaleActivityTolerance)):
+		decision.Action = StaleActionUnmark
+	case exempt:
+		// An exemption added after marking also lifts the stale label
+		decision.Action = StaleActionUnmark
+	case closeDays > 0 && !now.Before(markedAt.Add(time.Duration(closeDays)*24*time.Hour)):
+		decision.Action = StaleActionClose
+	}
+
+	return decision
+}
+
+// MarkedAt returns when an issue was marked stale: the time of the bot's
+// most recent comment, or the last update when the label was added by hand
+func (c *StaleConfig) MarkedAt(issue *Issue) time.Time {
+	for i := len(issue.Comments) - 1; i >= 0; i-- {
+		if issue.Comments[i].Author == c.Author {
+			return issue.Comments[i].Date
+		}
+	}
+	return issue.Timestamps.Updated
+}
+
+// FormatStaleMessage returns the comment posted when marking an issue
+func (c *StaleConfig) FormatStaleMessage(daysUntilClose int) string {
+	if strings.Contains(c.StaleMessage, "%d") {
+		if daysUntilClose <= 0 {
+			return "This issue has had no activity recently and has been marked stale."
+		}
+		return fmt.Sprintf(c.StaleMessage, daysUntilClose)
+	}
+	return c.StaleMessage
+}
+
+// StaleSweepResult summarises a sweep
+type StaleSweepResult struct {
+	DryRun    bool            `json:"dry_run"`
+	Checked   int             `json:"checked"`
+	Exempt    int             `json:"exempt"`
+	Marked    []StaleDecision `json:"marked"`
+	Closed    []StaleDecision `json:"closed"`
+	Unmarked  []StaleDecision `json:"unmarked"`
+	Errors    []string        `json:"errors,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+func issueHasAnyLabel(issue *Issue, names []string) bool {
+	for _, label := range issue.Labels {
+		for _, name := range names {
+			if strings.EqualFold(label.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func staleTestIssue(updatedDaysAgo int, now time.Time) *Issue {
+	issue := NewIssue("TEST-001", "Old issue", "", IssueTypeBug)
+	issue.Timestamps.Updated = now.Add(-time.Duration(updatedDaysAgo) * 24 * time.Hour)
+	return issue
+}
+
+func TestStaleConfig_EvaluateMarks(t *testing.T) {
+	now := time.Now()
+	config := DefaultStaleConfig()
+
+	assert.Equal(t, StaleActionNone, config.Evaluate(staleTestIssue(30, now), now).Action)
+
+	decision := config.Evaluate(staleTestIssue(61, now), now)
+	assert.Equal(t, StaleActionMark, decision.Action)
+	assert.Equal(t, 61, decision.InactiveDays)
+	assert.Equal(t, 14, decision.DaysUntilClose)
+
+	closed := staleTestIssue(200, now)
+	closed.Status = StatusClosed
+	assert.Equal(t, StaleActionNone, config.Evaluate(closed, now).Action)
+}
+
+func TestStaleConfig_Exemptions(t *testing.T) {
+	now := time.Now()
+	config := DefaultStaleConfig()
+	config.ExemptMilestones = []string{"v2.0"}
+
+	pinned := staleTestIssue(100, now)
+	pinned.AddLabel(Label{Name: "Pinned"})
+	pinned.Timestamps.Updated = now.Add(-100 * 24 * time.Hour)
+	assert.Equal(t, StaleActionExempt, config.Evaluate(pinned, now).Action)
+
+	milestoned := staleTestIssue(100, now)
+	milestoned.Milestone = &Milestone{Name: "v2.0"}
+	assert.Equal(t, StaleActionExempt, config.Evaluate(milestoned, now).Action)
+}
+
+func TestStaleConfig_Policies(t *testing.T) {
+	now := time.Now()
+	config := DefaultStaleConfig()
+	config.Policies = []StalePolicy{
+		{Types: []IssueType{IssueTypeEpic}, Exempt: true},
+		{Priorities: []Priority{PriorityCritical, PriorityHigh}, DaysUntilStale: 120},
+		{Labels: []string{"question"}, DaysUntilStale: 14, DaysUntilClose: -1},
+	}
+
+	epic := staleTestIssue(500, now)
+	epic.Type = IssueTypeEpic
+	assert.Equal(t, StaleActionExempt, config.Evaluate(epic, now).Action)
+
+	high := staleTestIssue(90, now)
+	high.Priority = PriorityHigh
+	assert.Equal(t, StaleActionNone, config.Evaluate(high, now).Action)
+	high.Timestamps.Updated = now.Add(-121 * 24 * time.Hour)
+	decision := config.Evaluate(high, now)
+	assert.Equal(t, StaleActionMark, decision.Action)
+	assert.Equal(t, 14, decision.DaysUntilClose, "close threshold inherited")
+
+	question := staleTestIssue(15, now)
+	question.Labels = []Label{{Name: "question"}}
+	decision = config.Evaluate(question, now)
+	assert.Equal(t, StaleActionMark, decision.Action)
+	assert.Equal(t, 0, decision.DaysUntilClose, "negative threshold disables closing")
+}
+
+func TestStaleConfig_MarkedIssues(t *testing.T) {
+	now := time.Now()
+	config := DefaultStaleConfig()
+
+	markedIssue := func(markedDaysAgo int) *Issue {
+		markedAt := now.Add(-time.Duration(markedDaysAgo) * 24 * time.Hour)
+		issue := staleTestIssue(0, now)
+		issue.Labels = []Label{{Name: "stale"}}
+		issue.Comments = []Comment{{ID: 1, Author: config.Author, Date: markedAt, Text: "stale"}}
+		issue.Timestamps.Updated = markedAt.Add(time.Second)
+		return issue
+	}
+
+	// Within the grace period nothing happens
+	assert.Equal(t, StaleActionNone, config.Evaluate(markedIssue(5), now).Action)
+
+	// After the grace period the issue is closed
+	assert.Equal(t, StaleActionClose, config.Evaluate(markedIssue(14), now).Action)
+
+	// New activity after marking lifts the label
+	active := markedIssue(5)
+	active.AddComment("alice", "still happening")
+	assert.Equal(t, StaleActionUnmark, config.Evaluate(active, now).Action)
+
+	// So does an exemption added after marking
+	pinned := markedIssue(20)
+	pinned.Labels = append(pinned.Labels, Label{Name: "pinned"})
+	assert.Equal(t, StaleActionUnmark, config.Evaluate(pinned, now).Action)
+
+	// Closing can be disabled
+	config.DaysUntilClose = 0
+	assert.Equal(t, StaleActionNone, config.Evaluate(markedIssue(100), now).Action)
+}
+
+func TestStaleConfig_FormatStaleMessage(t *testing.T) {
+	config := DefaultStaleConfig()
+	assert.Contains(t, config.FormatStaleMessage(14), "closed in 14 days")
+	assert.NotContains(t, config.FormatStaleMessage(0), "closed")
+}
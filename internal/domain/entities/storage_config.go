@@ -36,6 +36,12 @@ type StorageConfig struct {
 
 	// Compression configuration
 	CompressionConfig *CompressionConfig `yaml:"compression,omitempty" json:"compression,omitempty"`
+
+	// Attachment blob backend configuration
+	BlobStore *BlobStoreConfig `yaml:"blob_store,omitempty" json:"blob_store,omitempty"`
+
+	// Attachment security policy overrides
+	AttachmentPolicy *AttachmentPolicy `yaml:"attachment_policy,omitempty" json:"attachment_policy,omitempty"`
 }
 
 // DefaultStorageConfig returns default storage configuration
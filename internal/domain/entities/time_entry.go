@@ -26,16 +26,23 @@ func generateUniqueID(issueID IssueID) string {
 
 // TimeEntry represents a single time tracking entry for an issue
 type TimeEntry struct {
-	ID          string        `yaml:"id" json:"id"`
-	IssueID     IssueID       `yaml:"issue_id" json:"issue_id"`
-	Type        TimeEntryType `yaml:"type" json:"type"`
-	Duration    time.Duration `yaml:"duration" json:"duration"`
-	Description string        `yaml:"description,omitempty" json:"description,omitempty"`
-	Author      string        `yaml:"author" json:"author"`
-	StartTime   time.Time     `yaml:"start_time" json:"start_time"`
-	EndTime     *time.Time    `yaml:"end_time,omitempty" json:"end_time,omitempty"`
-	CreatedAt   time.Time     `yaml:"created_at" json:"created_at"`
-	UpdatedAt   time.Time     `yaml:"updated_at" json:"updated_at"`
+	// SchemaVersion is the format version the file was written in
+	SchemaVersion int `yaml:"schema_version,omitempty" json:"schema_version,omitempty"`
+
+	ID          string          `yaml:"id" json:"id"`
+	IssueID     IssueID         `yaml:"issue_id" json:"issue_id"`
+	Type        TimeEntryType   `yaml:"type" json:"type"`
+	Duration    time.Duration   `yaml:"duration" json:"duration"`
+	Description string          `yaml:"description,omitempty" json:"description,omitempty"`
+	Author      string          `yaml:"author" json:"author"`
+	StartTime   time.Time       `yaml:"start_time" json:"start_time"`
+	EndTime     *time.Time      `yaml:"end_time,omitempty" json:"end_time,omitempty"`
+	CommitHash  string          `yaml:"commit_hash,omitempty" json:"commit_hash,omitempty"`
+	Status      TimeEntryStatus `yaml:"status,omitempty" json:"status,omitempty"`
+	ApprovedBy  string          `yaml:"approved_by,omitempty" json:"approved_by,omitempty"`
+	ApprovedAt  *time.Time      `yaml:"approved_at,omitempty" json:"approved_at,omitempty"`
+	CreatedAt   time.Time       `yaml:"created_at" json:"created_at"`
+	UpdatedAt   time.Time       `yaml:"updated_at" json:"updated_at"`
 }
 
 // ActiveTimer represents an active timer session
@@ -80,6 +87,38 @@ func NewTimerEntry(issueID IssueID, description, author string, startTime, endTi
 	}
 }
 
+// NewCommitEntry creates a time entry estimated from a git commit
+func NewCommitEntry(issueID IssueID, commitHash, description, author string, startTime, endTime time.Time, duration time.Duration) *TimeEntry {
+	now := time.Now()
+	return &TimeEntry{
+		ID:          generateUniqueID(issueID),
+		IssueID:     issueID,
+		Type:        TimeEntryTypeCommit,
+		Duration:    duration,
+		Description: description,
+		Author:      author,
+		StartTime:   startTime,
+		EndTime:     &endTime,
+		CommitHash:  commitHash,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// IsEstimated returns true if the duration was estimated rather than measured or logged
+func (te *TimeEntry) IsEstimated() bool {
+	return te.Type == TimeEntryTypeCommit
+}
+
+// Interval returns the wall-clock window covered by the entry. Manual entries
+// without an end time are assumed to end StartTime+Duration.
+func (te *TimeEntry) Interval() (time.Time, time.Time) {
+	if te.EndTime != nil {
+		return te.StartTime, *te.EndTime
+	}
+	return te.StartTime, te.StartTime.Add(te.Duration)
+}
+
 // NewActiveTimer creates a new active timer
 func NewActiveTimer(issueID IssueID, description, author string) *ActiveTimer {
 	now := time.Now()
@@ -0,0 +1,52 @@
+package entities
+
+import "time"
+
+// Default commit time estimation settings
+const (
+	DefaultCommitSessionGapMinutes = 120
+	DefaultCommitLeadInMinutes     = 30
+)
+
+// TimeTrackingConfig contains time tracking settings
+type TimeTrackingConfig struct {
+	// CommitSessionGapMinutes is the longest pause between two commits by the
+	// same author that still counts as one working session
+	CommitSessionGapMinutes int `yaml:"commit_session_gap_minutes" json:"commit_session_gap_minutes"`
+	// CommitLeadInMinutes is the time credited to the first commit of a session
+	CommitLeadInMinutes int `yaml:"commit_lead_in_minutes" json:"commit_lead_in_minutes"`
+	// Billing contains rates and rounding rules for timesheets and invoices
+	Billing *BillingConfig `yaml:"billing,omitempty" json:"billing,omitempty"`
+}
+
+// DefaultTimeTrackingConfig returns the default time tracking settings
+func DefaultTimeTrackingConfig() *TimeTrackingConfig {
+	return &TimeTrackingConfig{
+		CommitSessionGapMinutes: DefaultCommitSessionGapMinutes,
+		CommitLeadInMinutes:     DefaultCommitLeadInMinutes,
+	}
+}
+
+// SessionGap returns the session gap as a duration
+func (c *TimeTrackingConfig) SessionGap() time.Duration {
+	if c == nil || c.CommitSessionGapMinutes <= 0 {
+		return DefaultCommitSessionGapMinutes * time.Minute
+	}
+	return time.Duration(c.CommitSessionGapMinutes) * time.Minute
+}
+
+// LeadIn returns the first-commit lead-in as a duration
+func (c *TimeTrackingConfig) LeadIn() time.Duration {
+	if c == nil || c.CommitLeadInMinutes < 0 {
+		return DefaultCommitLeadInMinutes * time.Minute
+	}
+	return time.Duration(c.CommitLeadInMinutes) * time.Minute
+}
+
+// BillingSettings returns the billing settings, or defaults if none are configured
+func (c *TimeTrackingConfig) BillingSettings() *BillingConfig {
+	if c == nil || c.Billing == nil {
+		return DefaultBillingConfig()
+	}
+	return c.Billing
+}
@@ -0,0 +1,368 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeEntryStatus represents the approval state of a time entry
+type TimeEntryStatus string
+
+const (
+	TimeEntryStatusDraft     TimeEntryStatus = "draft"
+	TimeEntryStatusSubmitted TimeEntryStatus = "submitted"
+	TimeEntryStatusApproved  TimeEntryStatus = "approved"
+	TimeEntryStatusLocked    TimeEntryStatus = "locked"
+)
+
+// timeEntryTransitions lists the allowed approval state changes
+var timeEntryTransitions = map[TimeEntryStatus][]TimeEntryStatus{
+	TimeEntryStatusDraft:     {TimeEntryStatusSubmitted},
+	TimeEntryStatusSubmitted: {TimeEntryStatusApproved, TimeEntryStatusDraft},
+	TimeEntryStatusApproved:  {TimeEntryStatusLocked, TimeEntryStatusDraft},
+	TimeEntryStatusLocked:    {},
+}
+
+// ApprovalStatus returns the approval state, treating entries recorded before
+// approval tracking as drafts
+func (te *TimeEntry) ApprovalStatus() TimeEntryStatus {
+	if te.Status == "" {
+		return TimeEntryStatusDraft
+	}
+	return te.Status
+}
+
+// IsEditable reports whether the entry may still be changed or deleted
+func (te *TimeEntry) IsEditable() bool {
+	status := te.ApprovalStatus()
+	return status == TimeEntryStatusDraft || status == TimeEntryStatusSubmitted
+}
+
+// SetApprovalStatus moves the entry to a new approval state. Approved entries
+// can only be reopened to draft or locked; locked entries are final.
+func (te *TimeEntry) SetApprovalStatus(status TimeEntryStatus, by string) error {
+	current := te.ApprovalStatus()
+	allowed := false
+	for _, next := range timeEntryTransitions[current] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("cannot change time entry %s from %s to %s", te.ID, current, status)
+	}
+
+	now := time.Now()
+	te.Status = status
+	switch status {
+	case TimeEntryStatusApproved:
+		te.ApprovedBy = by
+		te.ApprovedAt = &now
+	case TimeEntryStatusDraft:
+		te.ApprovedBy = ""
+		te.ApprovedAt = nil
+	}
+	return nil
+}
+
+// ParseTimeEntryStatus parses an approval state name
+func ParseTimeEntryStatus(value string) (TimeEntryStatus, error) {
+	status := TimeEntryStatus(strings.ToLower(value))
+	if _, ok := timeEntryTransitions[status]; !ok {
+		return "", fmt.Errorf("invalid time entry status %q (draft, submitted, approved, locked)", value)
+	}
+	return status, nil
+}
+
+// Rounding modes for billed time
+const (
+	RoundingUp      = "up"
+	RoundingDown    = "down"
+	RoundingNearest = "nearest"
+)
+
+// RoundingRule defines how billed time is rounded per entry
+type RoundingRule struct {
+	IncrementMinutes int    `yaml:"increment_minutes" json:"increment_minutes"`
+	Mode             string `yaml:"mode" json:"mode"`
+	MinimumMinutes   int    `yaml:"minimum_minutes,omitempty" json:"minimum_minutes,omitempty"`
+}
+
+// Apply rounds a duration according to the rule
+func (r RoundingRule) Apply(d time.Duration) time.Duration {
+	if r.IncrementMinutes > 0 {
+		increment := time.Duration(r.IncrementMinutes) * time.Minute
+		units := float64(d) / float64(increment)
+		switch r.Mode {
+		case RoundingDown:
+			units = math.Floor(units)
+		case RoundingNearest:
+			units = math.Round(units)
+		default:
+			units = math.Ceil(units)
+		}
+		d = time.Duration(units) * increment
+	}
+	if minimum := time.Duration(r.MinimumMinutes) * time.Minute; d < minimum {
+		d = minimum
+	}
+	return d
+}
+
+// BillingConfig contains rates and rules for timesheets and invoices.
+// A rate for one of the issue's labels takes precedence over the author's
+// rate, which takes precedence over the default rate.
+type BillingConfig struct {
+	Currency          string             `yaml:"currency" json:"currency"`
+	DefaultRate       float64            `yaml:"default_rate" json:"default_rate"`
+	UserRates         map[string]float64 `yaml:"user_rates,omitempty" json:"user_rates,omitempty"`
+	LabelRates        map[string]float64 `yaml:"label_rates,omitempty" json:"label_rates,omitempty"`
+	ClientLabelPrefix string             `yaml:"client_label_prefix,omitempty" json:"client_label_prefix,omitempty"`
+	ClientField       string             `yaml:"client_field,omitempty" json:"client_field,omitempty"`
+	Rounding          RoundingRule       `yaml:"rounding" json:"rounding"`
+}
+
+// DefaultBillingConfig returns billing settings that bill raw time at no rate
+func DefaultBillingConfig() *BillingConfig {
+	return &BillingConfig{
+		Currency:          "USD",
+		ClientLabelPrefix: "client:",
+		Rounding:          RoundingRule{Mode: RoundingUp},
+	}
+}
+
+// RateFor returns the hourly rate for an entry's author on an issue
+func (b *BillingConfig) RateFor(author string, issue *Issue) float64 {
+	if issue != nil {
+		for _, label := range issue.Labels {
+			if rate, ok := b.LabelRates[label.Name]; ok {
+				return rate
+			}
+		}
+	}
+	if rate, ok := b.UserRates[author]; ok {
+		return rate
+	}
+	return b.DefaultRate
+}
+
+// ClientFor returns the billable client of an issue from the configured custom
+// field or client label, or an empty string if there is none
+func (b *BillingConfig) ClientFor(issue *Issue) string {
+	if issue == nil {
+		return ""
+	}
+	if b.ClientField != "" {
+		if client := issue.Metadata.CustomFields[b.ClientField]; client != "" {
+			return client
+		}
+	}
+	if b.ClientLabelPrefix != "" {
+		for _, label := range issue.Labels {
+			if strings.HasPrefix(label.Name, b.ClientLabelPrefix) {
+				return strings.TrimPrefix(label.Name, b.ClientLabelPrefix)
+			}
+		}
+	}
+	return ""
+}
+
+// Timesheet periods
+const (
+	TimesheetWeek  = "week"
+	TimesheetMonth = "month"
+)
+
+// Timesheet grouping keys
+const (
+	TimesheetGroupAuthor = "author"
+	TimesheetGroupClient = "client"
+	TimesheetGroupIssue  = "issue"
+)
+
+// UnassignedClient labels time on issues without a billable client
+const UnassignedClient = "(no client)"
+
+// PeriodBounds returns the start (inclusive) and end (exclusive) of the week
+// (starting Monday) or month containing date
+func PeriodBounds(period string, date time.Time) (time.Time, time.Time, error) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	switch period {
+	case TimesheetWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		start := day.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7), nil
+	case TimesheetMonth:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+		return start, start.AddDate(0, 1, 0), nil
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid period %q (week, month)", period)
+}
+
+// TimesheetOptions selects and groups the entries of a timesheet
+type TimesheetOptions struct {
+	Period  string    `json:"period"`
+	Date    time.Time `json:"date"`
+	GroupBy []string  `json:"group_by"`
+	Author  string    `json:"author,omitempty"`
+	Client  string    `json:"client,omitempty"`
+}
+
+// TimesheetLine is a single billed time entry
+type TimesheetLine struct {
+	EntryID     string          `json:"entry_id"`
+	IssueID     IssueID         `json:"issue_id"`
+	IssueTitle  string          `json:"issue_title"`
+	Client      string          `json:"client"`
+	Author      string          `json:"author"`
+	Date        time.Time       `json:"date"`
+	Description string          `json:"description,omitempty"`
+	Type        TimeEntryType   `json:"type"`
+	Status      TimeEntryStatus `json:"status"`
+	Hours       float64         `json:"hours"`
+	BilledHours float64         `json:"billed_hours"`
+	Rate        float64         `json:"rate"`
+	Amount      float64         `json:"amount"`
+}
+
+// TimesheetGroup collects the lines sharing a group key
+type TimesheetGroup struct {
+	Key         string          `json:"key"`
+	Lines       []TimesheetLine `json:"lines"`
+	Hours       float64         `json:"hours"`
+	BilledHours float64         `json:"billed_hours"`
+	Amount      float64         `json:"amount"`
+}
+
+// TimesheetStatusChange reports the outcome of changing the approval state of
+// the entries on a timesheet
+type TimesheetStatusChange struct {
+	Status    TimeEntryStatus   `json:"status"`
+	Changed   []string          `json:"changed"`
+	Unchanged []string          `json:"unchanged"` // Already in the target state
+	Rejected  map[string]string `json:"rejected,omitempty"`
+}
+
+// Timesheet is the billed time for a period
+type Timesheet struct {
+	Period      string           `json:"period"`
+	Start       time.Time        `json:"start"`
+	End         time.Time        `json:"end"`
+	GroupBy     []string         `json:"group_by"`
+	Currency    string           `json:"currency"`
+	Groups      []TimesheetGroup `json:"groups"`
+	Hours       float64          `json:"hours"`
+	BilledHours float64          `json:"billed_hours"`
+	Amount      float64          `json:"amount"`
+	GeneratedAt time.Time        `json:"generated_at"`
+}
+
+// BuildTimesheet rounds, prices and groups the time entries that fall within
+// the period. Issues are used to resolve clients and label rates.
+func BuildTimesheet(entries []*TimeEntry, issues map[IssueID]*Issue, opts TimesheetOptions, billing *BillingConfig) (*Timesheet, error) {
+	if billing == nil {
+		billing = DefaultBillingConfig()
+	}
+	start, end, err := PeriodBounds(opts.Period, opts.Date)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range opts.GroupBy {
+		switch key {
+		case TimesheetGroupAuthor, TimesheetGroupClient, TimesheetGroupIssue:
+		default:
+			return nil, fmt.Errorf("invalid group %q (author, client, issue)", key)
+		}
+	}
+
+	sheet := &Timesheet{
+		Period:      opts.Period,
+		Start:       start,
+		End:         end,
+		GroupBy:     opts.GroupBy,
+		Currency:    billing.Currency,
+		GeneratedAt: time.Now(),
+	}
+
+	groups := make(map[string]*TimesheetGroup)
+	for _, entry := range entries {
+		if entry.StartTime.Before(start) || !entry.StartTime.Before(end) {
+			continue
+		}
+		if opts.Author != "" && entry.Author != opts.Author {
+			continue
+		}
+
+		issue := issues[entry.IssueID]
+		client := billing.ClientFor(issue)
+		if client == "" {
+			client = UnassignedClient
+		}
+		if opts.Client != "" && client != opts.Client {
+			continue
+		}
+
+		billed := billing.Rounding.Apply(entry.Duration)
+		rate := billing.RateFor(entry.Author, issue)
+		line := TimesheetLine{
+			EntryID:     entry.ID,
+			IssueID:     entry.IssueID,
+			Client:      client,
+			Author:      entry.Author,
+			Date:        entry.StartTime,
+			Description: entry.Description,
+			Type:        entry.Type,
+			Status:      entry.ApprovalStatus(),
+			Hours:       entry.Duration.Hours(),
+			BilledHours: billed.Hours(),
+			Rate:        rate,
+			Amount:      math.Round(billed.Hours()*rate*100) / 100,
+		}
+		if issue != nil {
+			line.IssueTitle = issue.Title
+		}
+
+		keyParts := make([]string, 0, len(opts.GroupBy))
+		for _, key := range opts.GroupBy {
+			switch key {
+			case TimesheetGroupAuthor:
+				keyParts = append(keyParts, line.Author)
+			case TimesheetGroupClient:
+				keyParts = append(keyParts, line.Client)
+			case TimesheetGroupIssue:
+				keyParts = append(keyParts, string(line.IssueID))
+			}
+		}
+		groupKey := strings.Join(keyParts, " / ")
+
+		group, ok := groups[groupKey]
+		if !ok {
+			group = &TimesheetGroup{Key: groupKey}
+			groups[groupKey] = group
+		}
+		group.Lines = append(group.Lines, line)
+		group.Hours += line.Hours
+		group.BilledHours += line.BilledHours
+		group.Amount += line.Amount
+
+		sheet.Hours += line.Hours
+		sheet.BilledHours += line.BilledHours
+		sheet.Amount += line.Amount
+	}
+
+	for _, group := range groups {
+		sort.Slice(group.Lines, func(i, j int) bool {
+			return group.Lines[i].Date.Before(group.Lines[j].Date)
+		})
+		sheet.Groups = append(sheet.Groups, *group)
+	}
+	sort.Slice(sheet.Groups, func(i, j int) bool {
+		return sheet.Groups[i].Key < sheet.Groups[j].Key
+	})
+
+	return sheet, nil
+}
@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeEntryApprovalTransitions(t *testing.T) {
+	entry := &TimeEntry{ID: "e1"}
+	assert.Equal(t, TimeEntryStatusDraft, entry.ApprovalStatus())
+	assert.True(t, entry.IsEditable())
+
+	// Drafts must be submitted before approval
+	assert.Error(t, entry.SetApprovalStatus(TimeEntryStatusApproved, "boss"))
+
+	require.NoError(t, entry.SetApprovalStatus(TimeEntryStatusSubmitted, "alice"))
+	assert.True(t, entry.IsEditable())
+
+	require.NoError(t, entry.SetApprovalStatus(TimeEntryStatusApproved, "boss"))
+	assert.False(t, entry.IsEditable())
+	assert.Equal(t, "boss", entry.ApprovedBy)
+	assert.NotNil(t, entry.ApprovedAt)
+
+	require.NoError(t, entry.SetApprovalStatus(TimeEntryStatusLocked, "boss"))
+	assert.False(t, entry.IsEditable())
+	assert.Error(t, entry.SetApprovalStatus(TimeEntryStatusDraft, "boss"))
+}
+
+func TestRoundingRule(t *testing.T) {
+	d := 20 * time.Minute
+	assert.Equal(t, 30*time.Minute, RoundingRule{IncrementMinutes: 15, Mode: RoundingUp}.Apply(d))
+	assert.Equal(t, 15*time.Minute, RoundingRule{IncrementMinutes: 15, Mode: RoundingDown}.Apply(d))
+	assert.Equal(t, 15*time.Minute, RoundingRule{IncrementMinutes: 15, Mode: RoundingNearest}.Apply(d))
+	assert.Equal(t, 60*time.Minute, RoundingRule{MinimumMinutes: 60}.Apply(d))
+	assert.Equal(t, d, RoundingRule{}.Apply(d))
+}
+
+func TestPeriodBounds(t *testing.T) {
+	wednesday := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC)
+
+	start, end, err := PeriodBounds(TimesheetWeek, wednesday)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), end)
+
+	start, end, err = PeriodBounds(TimesheetMonth, wednesday)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), end)
+
+	_, _, err = PeriodBounds("year", wednesday)
+	assert.Error(t, err)
+}
+
+func TestBuildTimesheet(t *testing.T) {
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	issues := map[IssueID]*Issue{
+		"TEST-001": {ID: "TEST-001", Title: "Acme work", Labels: []Label{{Name: "client:acme"}}},
+		"TEST-002": {ID: "TEST-002", Title: "Internal", Metadata: IssueMetadata{CustomFields: map[string]string{"client": "globex"}}},
+	}
+	entries := []*TimeEntry{
+		{ID: "a", IssueID: "TEST-001", Author: "alice", StartTime: monday, Duration: 50 * time.Minute},
+		{ID: "b", IssueID: "TEST-002", Author: "alice", StartTime: monday.Add(24 * time.Hour), Duration: time.Hour},
+		{ID: "c", IssueID: "TEST-001", Author: "bob", StartTime: monday.Add(48 * time.Hour), Duration: 2 * time.Hour},
+		{ID: "d", IssueID: "TEST-001", Author: "bob", StartTime: monday.AddDate(0, 0, 7), Duration: time.Hour},
+	}
+	billing := &BillingConfig{
+		Currency:          "EUR",
+		DefaultRate:       100,
+		UserRates:         map[string]float64{"alice": 80},
+		LabelRates:        map[string]float64{"client:acme": 150},
+		ClientLabelPrefix: "client:",
+		ClientField:       "client",
+		Rounding:          RoundingRule{IncrementMinutes: 60, Mode: RoundingUp},
+	}
+
+	sheet, err := BuildTimesheet(entries, issues, TimesheetOptions{
+		Period:  TimesheetWeek,
+		Date:    monday,
+		GroupBy: []string{TimesheetGroupClient, TimesheetGroupAuthor},
+	}, billing)
+	require.NoError(t, err)
+
+	require.Len(t, sheet.Groups, 3)
+	assert.Equal(t, "acme / alice", sheet.Groups[0].Key)
+	assert.Equal(t, "acme / bob", sheet.Groups[1].Key)
+	assert.Equal(t, "globex / alice", sheet.Groups[2].Key)
+
+	// Label rate beats user rate; 50 minutes rounds up to an hour
+	assert.Equal(t, 150.0, sheet.Groups[0].Amount)
+	assert.Equal(t, 300.0, sheet.Groups[1].Amount)
+	assert.Equal(t, 80.0, sheet.Groups[2].Amount)
+	assert.Equal(t, 4.0, sheet.BilledHours)
+	assert.Equal(t, 530.0, sheet.Amount)
+
+	_, err = BuildTimesheet(entries, issues, TimesheetOptions{Period: TimesheetWeek, GroupBy: []string{"team"}}, billing)
+	assert.Error(t, err)
+}
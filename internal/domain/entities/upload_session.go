@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultUploadSessionTTL is how long an idle upload session is kept before
+// its partial data is discarded
+const DefaultUploadSessionTTL = 24 * time.Hour
+
+// UploadSession tracks a resumable attachment upload. Chunks are appended to a
+// partial file in order; Offset is the number of bytes received so far.
+type UploadSession struct {
+	ID          string    `yaml:"id" json:"id"`
+	IssueID     IssueID   `yaml:"issue_id" json:"issue_id"`
+	Filename    string    `yaml:"filename" json:"filename"`
+	Size        int64     `yaml:"size" json:"size"`
+	Offset      int64     `yaml:"offset" json:"offset"`
+	UploadedBy  string    `yaml:"uploaded_by" json:"uploaded_by"`
+	Description string    `yaml:"description,omitempty" json:"description,omitempty"`
+	CreatedAt   time.Time `yaml:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `yaml:"updated_at" json:"updated_at"`
+
+	// Source identifies the local file a CLI upload was read from, so an
+	// interrupted upload can be matched and resumed
+	Source        string     `yaml:"source,omitempty" json:"-"`
+	SourceModTime *time.Time `yaml:"source_mod_time,omitempty" json:"-"`
+
+	// HashAlgorithm and HashState hold the running content hash, so the
+	// digest is available without re-reading the file once complete
+	HashAlgorithm string `yaml:"hash_algorithm" json:"-"`
+	HashState     []byte `yaml:"hash_state,omitempty" json:"-"`
+
+	// AttachmentID is set once the upload is complete
+	AttachmentID string `yaml:"attachment_id,omitempty" json:"attachment_id,omitempty"`
+}
+
+// NewUploadSession creates a new upload session
+func NewUploadSession(issueID IssueID, filename string, size int64, uploadedBy string) *UploadSession {
+	now := time.Now()
+	return &UploadSession{
+		ID:         fmt.Sprintf("%s-upl-%d", issueID, now.UnixNano()),
+		IssueID:    issueID,
+		Filename:   filename,
+		Size:       size,
+		UploadedBy: uploadedBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Remaining returns the number of bytes still to be uploaded
+func (u *UploadSession) Remaining() int64 {
+	return u.Size - u.Offset
+}
+
+// IsComplete reports whether every byte has been received
+func (u *UploadSession) IsComplete() bool {
+	return u.Offset >= u.Size
+}
+
+// IsExpired reports whether the session has been idle longer than ttl
+func (u *UploadSession) IsExpired(now time.Time, ttl time.Duration) bool {
+	return now.Sub(u.UpdatedAt) > ttl
+}
+
+// Progress returns the completed fraction of the upload as a percentage
+func (u *UploadSession) Progress() float64 {
+	if u.Size <= 0 {
+		return 100
+	}
+	return float64(u.Offset) / float64(u.Size) * 100
+}
+
+// MatchesSource reports whether the session was started from the given local
+// file and the file has not changed since
+func (u *UploadSession) MatchesSource(source string, size int64, modTime time.Time) bool {
+	return u.Source != "" && u.Source == source && u.Size == size &&
+		u.SourceModTime != nil && u.SourceModTime.Equal(modTime)
+}
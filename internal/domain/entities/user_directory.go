@@ -0,0 +1,334 @@
+package entities
+
+import (
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+)
+
+// UserProfile is a person in the user directory. Every identity the person
+// appears under, such as their git name, emails and old handles, resolves to
+// the canonical handle.
+type UserProfile struct {
+	// Handle is the canonical username written to issues, comments and
+	// time entries
+	Handle  string   `yaml:"handle" json:"handle"`
+	Name    string   `yaml:"name,omitempty" json:"name,omitempty"`
+	Emails  []string `yaml:"emails,omitempty" json:"emails,omitempty"`
+	Aliases []string `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	Teams   []string `yaml:"teams,omitempty" json:"teams,omitempty"`
+	// Capacity is the hours per week the person is available for issues
+	Capacity float64 `yaml:"capacity,omitempty" json:"capacity,omitempty"`
+	Timezone string  `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+}
+
+// Identities returns every identity that resolves to the profile
+func (p UserProfile) Identities() []string {
+	identities := []string{p.Handle}
+	if p.Name != "" {
+		identities = append(identities, p.Name)
+	}
+	identities = append(identities, p.Emails...)
+	return append(identities, p.Aliases...)
+}
+
+// PrimaryEmail returns the first email of the profile, if any
+func (p UserProfile) PrimaryEmail() string {
+	if len(p.Emails) == 0 {
+		return ""
+	}
+	return p.Emails[0]
+}
+
+// InTeam reports whether the person is a member of a team
+func (p UserProfile) InTeam(team string) bool {
+	for _, t := range p.Teams {
+		if normalizeIdentity(t) == normalizeIdentity(team) {
+			return true
+		}
+	}
+	return false
+}
+
+// Absorb folds another profile into this one: its handle, name and aliases
+// become aliases, and its emails and teams are added. Capacity and timezone
+// are only taken when this profile has none.
+func (p *UserProfile) Absorb(other UserProfile) {
+	aliases := append([]string{other.Handle}, other.Aliases...)
+	if other.Name != "" && !strings.EqualFold(other.Name, p.Name) {
+		aliases = append(aliases, other.Name)
+	}
+	for _, alias := range aliases {
+		if normalizeIdentity(alias) != normalizeIdentity(p.Handle) {
+			p.Aliases = appendIdentity(p.Aliases, alias)
+		}
+	}
+	for _, email := range other.Emails {
+		p.Emails = appendIdentity(p.Emails, email)
+	}
+	for _, team := range other.Teams {
+		p.Teams = appendIdentity(p.Teams, team)
+	}
+	if p.Capacity == 0 {
+		p.Capacity = other.Capacity
+	}
+	if p.Timezone == "" {
+		p.Timezone = other.Timezone
+	}
+}
+
+// appendIdentity appends an identity unless an equivalent one is present
+func appendIdentity(identities []string, identity string) []string {
+	for _, existing := range identities {
+		if normalizeIdentity(existing) == normalizeIdentity(identity) {
+			return identities
+		}
+	}
+	return append(identities, identity)
+}
+
+// normalizeIdentity folds case and a leading @ so that "@JDoe" and "jdoe"
+// compare equal
+func normalizeIdentity(identity string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(identity), "@"))
+}
+
+// UserDirectory resolves the identities people appear under to their
+// canonical handles. A nil directory resolves nothing.
+type UserDirectory struct {
+	users []UserProfile
+	index map[string]int
+}
+
+// NewUserDirectory indexes the profiles. When two profiles claim the same
+// identity the first wins; ValidateUserProfiles reports such conflicts.
+func NewUserDirectory(users []UserProfile) *UserDirectory {
+	d := &UserDirectory{users: users, index: make(map[string]int)}
+	for i, user := range users {
+		for _, identity := range user.Identities() {
+			key := normalizeIdentity(identity)
+			if _, ok := d.index[key]; key != "" && !ok {
+				d.index[key] = i
+			}
+		}
+	}
+	return d
+}
+
+// UserDirectory returns the directory of the configured users
+func (c *Config) UserDirectory() *UserDirectory {
+	if c == nil {
+		return nil
+	}
+	return NewUserDirectory(c.Users)
+}
+
+// Users returns the profiles in the directory
+func (d *UserDirectory) Users() []UserProfile {
+	if d == nil {
+		return nil
+	}
+	return d.users
+}
+
+// Lookup finds the profile an identity belongs to. Identities may be a
+// handle, name, email, alias or a "Name <email>" address.
+func (d *UserDirectory) Lookup(identity string) (*UserProfile, bool) {
+	if d == nil {
+		return nil, false
+	}
+	if i, ok := d.index[normalizeIdentity(identity)]; ok {
+		return &d.users[i], true
+	}
+	if address, err := mail.ParseAddress(identity); err == nil {
+		if i, ok := d.index[normalizeIdentity(address.Address)]; ok {
+			return &d.users[i], true
+		}
+		if i, ok := d.index[normalizeIdentity(address.Name)]; ok && address.Name != "" {
+			return &d.users[i], true
+		}
+	}
+	return nil, false
+}
+
+// Canonical returns the handle of the person an identity belongs to, or the
+// identity unchanged when it is not in the directory
+func (d *UserDirectory) Canonical(identity string) string {
+	if profile, ok := d.Lookup(identity); ok {
+		return profile.Handle
+	}
+	return identity
+}
+
+// CanonicalUser resolves a user by username, then by email. Known users
+// get their handle and primary email; unknown users are returned unchanged.
+func (d *UserDirectory) CanonicalUser(user *User) *User {
+	if user == nil {
+		return nil
+	}
+	profile, ok := d.Lookup(user.Username)
+	if !ok && user.Email != "" {
+		profile, ok = d.Lookup(user.Email)
+	}
+	if !ok {
+		return user
+	}
+	email := profile.PrimaryEmail()
+	if email == "" {
+		email = user.Email
+	}
+	return &User{Username: profile.Handle, Email: email}
+}
+
+// Team returns the handles of the members of a team, and whether any
+// profile is in it. The team may be written with a leading @.
+func (d *UserDirectory) Team(team string) ([]string, bool) {
+	var members []string
+	for _, user := range d.Users() {
+		if user.InTeam(team) {
+			members = append(members, user.Handle)
+		}
+	}
+	return members, len(members) > 0
+}
+
+// TeamNames returns the teams people are in, sorted
+func (d *UserDirectory) TeamNames() []string {
+	seen := make(map[string]bool)
+	var teams []string
+	for _, user := range d.Users() {
+		for _, team := range user.Teams {
+			if key := normalizeIdentity(team); !seen[key] {
+				seen[key] = true
+				teams = append(teams, team)
+			}
+		}
+	}
+	sort.Strings(teams)
+	return teams
+}
+
+// ValidateUserProfiles checks that every profile has a handle and that no
+// identity belongs to two people
+func ValidateUserProfiles(users []UserProfile) error {
+	owner := make(map[string]string)
+	for _, user := range users {
+		if strings.TrimSpace(user.Handle) == "" {
+			return fmt.Errorf("user without a handle")
+		}
+		for _, identity := range user.Identities() {
+			key := normalizeIdentity(identity)
+			if key == "" {
+				continue
+			}
+			if other, ok := owner[key]; ok && other != user.Handle {
+				return fmt.Errorf("%q belongs to both %s and %s", identity, other, user.Handle)
+			}
+			owner[key] = user.Handle
+		}
+	}
+	return nil
+}
+
+// UserNormalization reports the identities rewritten to canonical handles
+// across issues, history and time tracking
+type UserNormalization struct {
+	DryRun bool `json:"dry_run,omitempty"`
+	// Issues, HistoryEntries, TimeEntries and Timers count the records
+	// that name an identity other than its handle. Sealed history entries
+	// keep their author unless the history is resealed.
+	Issues         int `json:"issues"`
+	HistoryEntries int `json:"history_entries"`
+	TimeEntries    int `json:"time_entries"`
+	Timers         int `json:"timers"`
+	// Histories counts the histories given an entry that records the
+	// identities of their authors
+	Histories int `json:"histories"`
+	// Resealed is set when history authors were rewritten and their hash
+	// chains recomputed, instead of only recording the mapping
+	Resealed bool `json:"resealed,omitempty"`
+	// LockedTimeEntries lists the approved or locked time entries that name
+	// an identity other than its handle. They cannot be edited and are left
+	// unchanged.
+	LockedTimeEntries []string `json:"locked_time_entries,omitempty"`
+	// Renamed maps each identity that was rewritten to its handle
+	Renamed map[string]string `json:"renamed,omitempty"`
+	// Unknown lists identities found in the data that are not in the
+	// directory, sorted
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// systemIdentities are authors written by issuemap itself, never people
+var systemIdentities = map[string]bool{"": true, "system": true, "unknown": true, "issuemap-cleanup": true}
+
+// Resolve returns the handle of an identity found in the data and records
+// the rename or the unknown identity
+func (n *UserNormalization) Resolve(directory *UserDirectory, identity string) string {
+	if systemIdentities[identity] {
+		return identity
+	}
+	canonical := directory.Canonical(identity)
+	if _, known := directory.Lookup(identity); !known {
+		n.Unknown = appendIdentity(n.Unknown, identity)
+		sort.Strings(n.Unknown)
+	} else if canonical != identity {
+		if n.Renamed == nil {
+			n.Renamed = make(map[string]string)
+		}
+		n.Renamed[identity] = canonical
+	}
+	return canonical
+}
+
+// identityMapMetadata is the history entry metadata key holding the
+// identities mapped to handles
+const identityMapMetadata = "identity_map"
+
+// NewIdentityMappingEntry returns a history entry recording that entries by
+// the given identities were made by the people with the mapped handles.
+// Sealed entries keep the author they were recorded with; this entry says
+// who that author is. resealed notes that the authors were rewritten too.
+func NewIdentityMappingEntry(issueID IssueID, mapping map[string]string, resealed bool) *HistoryEntry {
+	identities := make([]string, 0, len(mapping))
+	for identity := range mapping {
+		identities = append(identities, identity)
+	}
+	sort.Strings(identities)
+	pairs := make([]string, 0, len(identities))
+	for _, identity := range identities {
+		pairs = append(pairs, identity+" -> "+mapping[identity])
+	}
+
+	message := "Mapped identities to handles: " + strings.Join(pairs, ", ")
+	if resealed {
+		message = "Rewrote authors to handles and resealed history: " + strings.Join(pairs, ", ")
+	}
+	entry := NewHistoryEntry(issueID, ChangeTypeUpdated, "system", message)
+	entry.SetMetadata(identityMapMetadata, mapping)
+	if resealed {
+		entry.SetMetadata("resealed", true)
+	}
+	return entry
+}
+
+// MappedIdentities returns the identities that entries of the history
+// record as mapped to handles, later mappings taking precedence
+func (h *IssueHistory) MappedIdentities() map[string]string {
+	mapped := make(map[string]string)
+	for _, entry := range h.Entries {
+		switch mapping := entry.Metadata[identityMapMetadata].(type) {
+		case map[string]string:
+			for identity, handle := range mapping {
+				mapped[identity] = handle
+			}
+		case map[string]interface{}:
+			for identity, handle := range mapping {
+				if handle, ok := handle.(string); ok {
+					mapped[identity] = handle
+				}
+			}
+		}
+	}
+	return mapped
+}
@@ -0,0 +1,121 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUserDirectory() *UserDirectory {
+	return NewUserDirectory([]UserProfile{
+		{Handle: "jdoe", Name: "John Doe", Emails: []string{"jdoe@corp.example", "john@home.example"}, Aliases: []string{"johnd"}, Teams: []string{"team-backend"}},
+		{Handle: "robert", Aliases: []string{"bob"}, Teams: []string{"team-backend", "@team-web"}},
+		{Handle: "carol", Teams: []string{"team-web"}},
+	})
+}
+
+func TestUserDirectory_Canonical(t *testing.T) {
+	directory := testUserDirectory()
+
+	tests := map[string]string{
+		"jdoe":                         "jdoe",
+		"@JDoe":                        "jdoe",
+		"John Doe":                     "jdoe",
+		"JDOE@corp.example":            "jdoe",
+		"john@home.example":            "jdoe",
+		"johnd":                        "jdoe",
+		"John Doe <other@example.com>": "jdoe",
+		"Someone <jdoe@corp.example>":  "jdoe",
+		"bob":                          "robert",
+		"mallory":                      "mallory",
+		"":                             "",
+	}
+	for identity, want := range tests {
+		assert.Equal(t, want, directory.Canonical(identity), identity)
+	}
+
+	var unset *UserDirectory
+	assert.Equal(t, "John Doe", unset.Canonical("John Doe"))
+	_, ok := unset.Team("team-backend")
+	assert.False(t, ok)
+}
+
+func TestUserDirectory_CanonicalUser(t *testing.T) {
+	directory := testUserDirectory()
+
+	assert.Equal(t, &User{Username: "jdoe", Email: "jdoe@corp.example"},
+		directory.CanonicalUser(&User{Username: "John Doe", Email: "john@home.example"}))
+	// Unknown names resolve by email
+	assert.Equal(t, &User{Username: "jdoe", Email: "jdoe@corp.example"},
+		directory.CanonicalUser(&User{Username: "J. Doe", Email: "john@home.example"}))
+	assert.Equal(t, &User{Username: "robert", Email: "bob@example.com"},
+		directory.CanonicalUser(&User{Username: "bob", Email: "bob@example.com"}))
+
+	unknown := &User{Username: "mallory"}
+	assert.Same(t, unknown, directory.CanonicalUser(unknown))
+	assert.Nil(t, directory.CanonicalUser(nil))
+}
+
+func TestUserDirectory_Teams(t *testing.T) {
+	directory := testUserDirectory()
+
+	members, ok := directory.Team("@team-backend")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"jdoe", "robert"}, members)
+
+	members, ok = directory.Team("team-web")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"robert", "carol"}, members)
+
+	_, ok = directory.Team("team-ops")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"@team-web", "team-backend"}, directory.TeamNames())
+}
+
+func TestValidateUserProfiles(t *testing.T) {
+	require.NoError(t, ValidateUserProfiles(testUserDirectory().Users()))
+
+	err := ValidateUserProfiles([]UserProfile{
+		{Handle: "jdoe", Aliases: []string{"bob"}},
+		{Handle: "robert", Aliases: []string{"Bob"}},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "jdoe and robert")
+
+	assert.Error(t, ValidateUserProfiles([]UserProfile{{Name: "No Handle"}}))
+}
+
+func TestUserProfile_Absorb(t *testing.T) {
+	profile := UserProfile{Handle: "jdoe", Name: "John Doe", Emails: []string{"jdoe@corp.example"}, Teams: []string{"team-backend"}}
+	profile.Absorb(UserProfile{
+		Handle:   "johnd",
+		Name:     "Johnny",
+		Emails:   []string{"JDOE@corp.example", "john@home.example"},
+		Aliases:  []string{"jd", "jdoe"},
+		Teams:    []string{"team-backend", "team-web"},
+		Capacity: 20,
+		Timezone: "Europe/Berlin",
+	})
+
+	assert.Equal(t, []string{"johnd", "jd", "Johnny"}, profile.Aliases)
+	assert.Equal(t, []string{"jdoe@corp.example", "john@home.example"}, profile.Emails)
+	assert.Equal(t, []string{"team-backend", "team-web"}, profile.Teams)
+	assert.Equal(t, 20.0, profile.Capacity)
+	assert.Equal(t, "Europe/Berlin", profile.Timezone)
+}
+
+func TestUserNormalization_Resolve(t *testing.T) {
+	directory := testUserDirectory()
+	result := &UserNormalization{}
+
+	assert.Equal(t, "jdoe", result.Resolve(directory, "John Doe"))
+	assert.Equal(t, "jdoe", result.Resolve(directory, "jdoe"))
+	assert.Equal(t, "mallory", result.Resolve(directory, "mallory"))
+	assert.Equal(t, "Eve", result.Resolve(directory, "Eve"))
+	assert.Equal(t, "system", result.Resolve(directory, "system"))
+
+	assert.Equal(t, map[string]string{"John Doe": "jdoe"}, result.Renamed)
+	assert.Equal(t, []string{"Eve", "mallory"}, result.Unknown)
+}
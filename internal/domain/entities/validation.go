@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severities of validation problems. Errors fail validation, warnings are
+// reported but do not.
+const (
+	ValidationSeverityError   = "error"
+	ValidationSeverityWarning = "warning"
+)
+
+// ValidationProblem is a single problem found in a file. Line and Column are
+// 1-based and zero when the position is unknown.
+type ValidationProblem struct {
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Severity string `json:"severity"`
+	Field    string `json:"field,omitempty"`
+	Message  string `json:"message"`
+}
+
+// String formats the problem like a compiler diagnostic
+func (p ValidationProblem) String() string {
+	location := p.File
+	if p.Line > 0 {
+		location = fmt.Sprintf("%s:%d", location, p.Line)
+		if p.Column > 0 {
+			location = fmt.Sprintf("%s:%d", location, p.Column)
+		}
+	}
+	message := p.Message
+	if p.Field != "" {
+		message = p.Field + ": " + message
+	}
+	return fmt.Sprintf("%s: %s: %s", location, p.Severity, message)
+}
+
+// ValidationReport collects the problems found while validating files
+type ValidationReport struct {
+	FilesChecked int                 `json:"files_checked"`
+	Errors       int                 `json:"errors"`
+	Warnings     int                 `json:"warnings"`
+	Problems     []ValidationProblem `json:"problems"`
+}
+
+// NewValidationReport creates an empty report
+func NewValidationReport() *ValidationReport {
+	return &ValidationReport{Problems: []ValidationProblem{}}
+}
+
+// Add records a problem
+func (r *ValidationReport) Add(problem ValidationProblem) {
+	if problem.Severity == ValidationSeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	r.Problems = append(r.Problems, problem)
+}
+
+// Valid reports whether no errors were found
+func (r *ValidationReport) Valid() bool {
+	return r.Errors == 0
+}
+
+// Sort orders problems by file and position
+func (r *ValidationReport) Sort() {
+	sort.SliceStable(r.Problems, func(i, j int) bool {
+		a, b := r.Problems[i], r.Problems[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
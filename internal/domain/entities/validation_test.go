@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationReport(t *testing.T) {
+	report := NewValidationReport()
+	report.Add(ValidationProblem{File: "b.yaml", Line: 2, Severity: ValidationSeverityWarning, Message: "unknown field", Field: "owner"})
+	report.Add(ValidationProblem{File: "a.yaml", Line: 5, Column: 9, Severity: ValidationSeverityError, Message: "invalid status"})
+	report.Sort()
+
+	assert.False(t, report.Valid())
+	assert.Equal(t, 1, report.Errors)
+	assert.Equal(t, 1, report.Warnings)
+	assert.Equal(t, "a.yaml:5:9: error: invalid status", report.Problems[0].String())
+	assert.Equal(t, "b.yaml:2: warning: owner: unknown field", report.Problems[1].String())
+}
@@ -0,0 +1,135 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NotificationReason records why a watcher was notified about an issue
+type NotificationReason string
+
+const (
+	NotificationMentioned     NotificationReason = "mentioned"
+	NotificationAssigned      NotificationReason = "assigned"
+	NotificationCommented     NotificationReason = "commented"
+	NotificationStatusChanged NotificationReason = "status_changed"
+)
+
+// IssueNotification tells a person about activity on an issue they watch
+// or were mentioned in. It carries no issue content, so notifications about
+// confidential issues reveal nothing beyond the issue ID. Status is only set
+// when the issue's status is not sealed.
+type IssueNotification struct {
+	ID        string             `yaml:"id" json:"id"`
+	IssueID   IssueID            `yaml:"issue_id" json:"issue_id"`
+	Recipient string             `yaml:"recipient" json:"recipient"`
+	Actor     string             `yaml:"actor" json:"actor"`
+	Reason    NotificationReason `yaml:"reason" json:"reason"`
+	Status    Status             `yaml:"status,omitempty" json:"status,omitempty"`
+	CreatedAt time.Time          `yaml:"created_at" json:"created_at"`
+	Read      bool               `yaml:"read,omitempty" json:"read,omitempty"`
+	// Digested is set once the notification has been sent in a digest
+	Digested bool `yaml:"digested,omitempty" json:"digested,omitempty"`
+}
+
+// NewIssueNotification creates an unread notification for recipient
+func NewIssueNotification(issueID IssueID, recipient, actor string, reason NotificationReason) *IssueNotification {
+	now := time.Now()
+	return &IssueNotification{
+		ID:        fmt.Sprintf("%s-%s-%d", issueID, reason, now.UnixNano()),
+		IssueID:   issueID,
+		Recipient: recipient,
+		Actor:     actor,
+		Reason:    reason,
+		CreatedAt: now,
+	}
+}
+
+// Summary describes the notification from the recipient's point of view
+func (n *IssueNotification) Summary() string {
+	switch n.Reason {
+	case NotificationMentioned:
+		return fmt.Sprintf("%s mentioned you on %s", n.Actor, n.IssueID)
+	case NotificationAssigned:
+		return fmt.Sprintf("%s assigned %s to you", n.Actor, n.IssueID)
+	case NotificationCommented:
+		return fmt.Sprintf("%s commented on %s", n.Actor, n.IssueID)
+	case NotificationStatusChanged:
+		// The status of a confidential issue may be sealed and left out
+		if n.Status == "" {
+			return fmt.Sprintf("%s changed the status of %s", n.Actor, n.IssueID)
+		}
+		return fmt.Sprintf("%s moved %s to %s", n.Actor, n.IssueID, n.Status)
+	default:
+		return fmt.Sprintf("%s updated %s", n.Actor, n.IssueID)
+	}
+}
+
+// IsWatching reports whether a person watches the issue. Handles compare
+// without case or a leading @.
+func (i *Issue) IsWatching(handle string) bool {
+	return i.watcherIndex(handle) >= 0
+}
+
+// AddWatcher subscribes a person to the issue and reports whether they were
+// not watching already. System identities never watch.
+func (i *Issue) AddWatcher(handle string) bool {
+	handle = strings.TrimPrefix(strings.TrimSpace(handle), "@")
+	if systemIdentities[handle] || i.IsWatching(handle) {
+		return false
+	}
+	i.Watchers = append(i.Watchers, handle)
+	return true
+}
+
+// RemoveWatcher unsubscribes a person and reports whether they were watching
+func (i *Issue) RemoveWatcher(handle string) bool {
+	idx := i.watcherIndex(handle)
+	if idx < 0 {
+		return false
+	}
+	i.Watchers = append(i.Watchers[:idx], i.Watchers[idx+1:]...)
+	return true
+}
+
+func (i *Issue) watcherIndex(handle string) int {
+	handle = normalizeIdentity(strings.TrimSpace(handle))
+	for idx, watcher := range i.Watchers {
+		if normalizeIdentity(watcher) == handle {
+			return idx
+		}
+	}
+	return -1
+}
+
+var (
+	// mentionPattern matches @handle when it is not part of an email
+	// address or a path
+	mentionPattern   = regexp.MustCompile(`(?:^|[^\w@./])@([A-Za-z0-9][\w.-]*)`)
+	codeBlockPattern = regexp.MustCompile("(?s)```.*?```")
+	codeSpanPattern  = regexp.MustCompile("`[^`\n]*`")
+)
+
+// ExtractMentions returns the @handles mentioned in text, without the @,
+// in the order they first appear. Mentions inside code spans and code
+// blocks are ignored.
+func ExtractMentions(text string) []string {
+	text = codeBlockPattern.ReplaceAllString(text, " ")
+	text = codeSpanPattern.ReplaceAllString(text, " ")
+
+	var mentions []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		// Sentence punctuation is not part of the handle
+		handle := strings.TrimRight(match[1], ".-")
+		key := strings.ToLower(handle)
+		if handle == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		mentions = append(mentions, handle)
+	}
+	return mentions
+}
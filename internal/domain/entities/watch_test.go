@@ -0,0 +1,52 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractMentions(t *testing.T) {
+	tests := map[string][]string{
+		"@jdoe please look":                          {"jdoe"},
+		"cc @jdoe, @Robert and @jdoe.":               {"jdoe", "Robert"},
+		"(@team-backend) owns this":                  {"team-backend"},
+		"write to jdoe@corp.example":                 nil,
+		"see docs/@types/node and `@jdoe` in code":   nil,
+		"```\n@Override\nvoid run()\n```\n@carol ok": {"carol"},
+		"no mentions here":                           nil,
+		"@":                                          nil,
+	}
+	for text, want := range tests {
+		assert.Equal(t, want, ExtractMentions(text), text)
+	}
+}
+
+func TestIssue_Watchers(t *testing.T) {
+	issue := NewIssue("TEST-001", "Crash", "", IssueTypeBug)
+
+	assert.True(t, issue.AddWatcher("@jdoe"))
+	assert.False(t, issue.AddWatcher("JDoe"), "handles compare without case")
+	assert.False(t, issue.AddWatcher("system"), "system identities never watch")
+	assert.True(t, issue.AddWatcher("carol"))
+	assert.Equal(t, []string{"jdoe", "carol"}, issue.Watchers)
+
+	assert.True(t, issue.IsWatching("@JDOE"))
+	assert.True(t, issue.RemoveWatcher("jdoe"))
+	assert.False(t, issue.RemoveWatcher("jdoe"))
+	assert.False(t, issue.IsWatching("jdoe"))
+	assert.Equal(t, []string{"carol"}, issue.Watchers)
+}
+
+func TestIssueNotification_Summary(t *testing.T) {
+	notification := NewIssueNotification("TEST-001", "carol", "jdoe", NotificationMentioned)
+	assert.Equal(t, "jdoe mentioned you on TEST-001", notification.Summary())
+	assert.False(t, notification.Read)
+
+	notification.Reason = NotificationStatusChanged
+	notification.Status = StatusDone
+	assert.Equal(t, "jdoe moved TEST-001 to done", notification.Summary())
+
+	notification.Status = ""
+	assert.Equal(t, "jdoe changed the status of TEST-001", notification.Summary())
+}
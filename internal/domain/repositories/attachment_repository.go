@@ -18,6 +18,10 @@ type AttachmentRepository interface {
 	// DeleteFile removes an attachment file from storage
 	DeleteFile(ctx context.Context, storagePath string) error
 
+	// SaveBlob stores content under a key (relative to the .issuemap directory)
+	// in the active blob backend and returns its storage path
+	SaveBlob(ctx context.Context, key string, content io.Reader) (string, error)
+
 	// SaveMetadata saves attachment metadata
 	SaveMetadata(ctx context.Context, attachment *entities.Attachment) error
 
@@ -27,6 +31,9 @@ type AttachmentRepository interface {
 	// ListByIssue retrieves all attachments for an issue
 	ListByIssue(ctx context.Context, issueID entities.IssueID) ([]*entities.Attachment, error)
 
+	// ListAll retrieves the metadata of every attachment
+	ListAll(ctx context.Context) ([]*entities.Attachment, error)
+
 	// DeleteMetadata removes attachment metadata
 	DeleteMetadata(ctx context.Context, attachmentID string) error
 
@@ -42,3 +49,24 @@ type AttachmentStats struct {
 	LargestFile     *entities.Attachment
 	AttachmentTypes map[entities.AttachmentType]int
 }
+
+// BlobStore stores attachment content for one blob backend. Keys are paths
+// relative to the .issuemap directory such as "attachments/ISSUE-1/123_log.txt"
+// or "dedup/ab/cdef..."; storage paths add the backend scheme (see
+// entities.JoinStoragePath).
+type BlobStore interface {
+	// Backend returns the backend this store implements
+	Backend() entities.BlobBackend
+
+	// Put stores content under key and returns its storage path
+	Put(ctx context.Context, key string, content io.Reader) (string, error)
+
+	// Get opens the content stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the content stored under key
+	Delete(ctx context.Context, key string) error
+
+	// Exists reports whether content is stored under key
+	Exists(ctx context.Context, key string) (bool, error)
+}
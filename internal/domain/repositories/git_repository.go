@@ -30,6 +30,12 @@ type BranchStatus struct {
 	LastCommitMsg string `json:"last_commit_msg"`
 }
 
+// BranchLanding describes how a branch's changes reached a base branch
+type BranchLanding struct {
+	Method  entities.LandingMethod `json:"method"`
+	Commits []string               `json:"commits"` // Base commits carrying the changes
+}
+
 // GitRepository defines the interface for git integration operations
 type GitRepository interface {
 	// IsGitRepository checks if the current directory is a git repository
@@ -50,9 +56,15 @@ type GitRepository interface {
 	// GetLatestCommit returns the latest commit on the current branch
 	GetLatestCommit(ctx context.Context) (*Commit, error)
 
+	// GetCommitFiles returns the paths a commit changed
+	GetCommitFiles(ctx context.Context, hash string) ([]string, error)
+
 	// GetCommitsByIssue returns commits that reference a specific issue
 	GetCommitsByIssue(ctx context.Context, issueID entities.IssueID) ([]Commit, error)
 
+	// GetBranchCommits returns commits on branch that are not reachable from base
+	GetBranchCommits(ctx context.Context, branch, base string) ([]Commit, error)
+
 	// ParseIssueReferences extracts issue references from a commit message
 	ParseIssueReferences(message string) []string
 
@@ -86,9 +98,45 @@ type GitRepository interface {
 	// PullBranch pulls changes from the remote repository
 	PullBranch(ctx context.Context, branch string) error
 
+	// PushBranchUpstream pushes a branch to a remote and sets it as the upstream
+	PushBranchUpstream(ctx context.Context, remote, branch string) error
+
+	// FetchBranch updates the remote-tracking branch of a remote branch
+	FetchBranch(ctx context.Context, remote, branch string) error
+
+	// ResolveCommit returns the full hash of the commit a ref points to
+	ResolveCommit(ctx context.Context, ref string) (string, error)
+
+	// FindLanding reports how head's changes reached base, nil if they have not
+	FindLanding(ctx context.Context, head, base string) (*BranchLanding, error)
+
+	// CommitTime returns the committer date of the commit a ref points to
+	CommitTime(ctx context.Context, ref string) (time.Time, error)
+
+	// IsTag reports whether a ref names a tag
+	IsTag(ctx context.Context, ref string) bool
+
+	// PreviousTag returns the newest tag reachable from ref, excluding a tag on ref itself
+	PreviousTag(ctx context.Context, ref string) (string, error)
+
+	// GetRefTips returns the commit each branch, remote-tracking branch and tag points to
+	GetRefTips(ctx context.Context) (map[string]string, error)
+
+	// GetCommitsExcluding returns the commits reachable from any ref but not from the excluded commits
+	GetCommitsExcluding(ctx context.Context, exclude []string) ([]Commit, error)
+
 	// MergeBranch merges a source branch into a target branch
 	MergeBranch(ctx context.Context, sourceBranch, targetBranch string) error
 
 	// GetMainBranch returns the main branch name (main or master)
 	GetMainBranch(ctx context.Context) (string, error)
+
+	// HistorySigningEnabled reports whether history entries should be signed
+	HistorySigningEnabled(ctx context.Context) bool
+
+	// SignData creates a detached signature over data with the committer's signing key
+	SignData(ctx context.Context, data []byte) (string, error)
+
+	// VerifySignature checks a detached signature and returns the signer identity
+	VerifySignature(ctx context.Context, data []byte, signature string) (string, error)
 }
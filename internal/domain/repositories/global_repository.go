@@ -35,6 +35,8 @@ type GlobalRepository interface {
 	GetBackup(ctx context.Context, backupID string) (*entities.ProjectBackup, error)
 	RestoreBackup(ctx context.Context, backupID string, targetPath string) error
 	DeleteBackup(ctx context.Context, backupID string) error
+	VerifyBackup(ctx context.Context, backupID string) (*entities.BackupVerifyResult, error)
+	RotateBackupKey(ctx context.Context) (string, error)
 
 	// Global search and listing
 	GlobalListIssues(ctx context.Context, filter GlobalIssueFilter) (*GlobalIssueList, error)
@@ -13,6 +13,8 @@ type IssueFilter struct {
 	Type         *entities.IssueType `json:"type,omitempty"`
 	Priority     *entities.Priority  `json:"priority,omitempty"`
 	Assignee     *string             `json:"assignee,omitempty"`
+	Assignees    []string            `json:"assignees,omitempty"` // Any of these, such as the members of a team
+	Watcher      *string             `json:"watcher,omitempty"`
 	Labels       []string            `json:"labels,omitempty"`
 	Milestone    *string             `json:"milestone,omitempty"`
 	Branch       *string             `json:"branch,omitempty"`
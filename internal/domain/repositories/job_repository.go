@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// JobRepository persists scheduled job state and run history
+type JobRepository interface {
+	// LoadStates returns the saved state of every job, keyed by job name
+	LoadStates(ctx context.Context) (map[string]*entities.JobState, error)
+
+	// SaveState saves the state of a single job
+	SaveState(ctx context.Context, state *entities.JobState) error
+
+	// AppendRun records a finished run, keeping at most limit runs
+	AppendRun(ctx context.Context, run *entities.JobRun, limit int) error
+
+	// ListRuns returns the most recent runs first, optionally for one job.
+	// A limit of 0 returns every run.
+	ListRuns(ctx context.Context, job string, limit int) ([]*entities.JobRun, error)
+}
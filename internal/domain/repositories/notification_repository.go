@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// NotificationRepository stores the notifications of issue watchers
+type NotificationRepository interface {
+	// Add delivers notifications to their recipients
+	Add(ctx context.Context, notifications ...*entities.IssueNotification) error
+
+	// List returns the notifications of a recipient, newest first
+	List(ctx context.Context, recipient string, unreadOnly bool) ([]*entities.IssueNotification, error)
+
+	// MarkRead marks notifications of a recipient as read, every one of
+	// them when no IDs are given, and returns how many were marked
+	MarkRead(ctx context.Context, recipient string, ids []string) (int, error)
+
+	// MarkDigested records that notifications of a recipient were sent in a
+	// digest
+	MarkDigested(ctx context.Context, recipient string, ids []string) error
+}
@@ -138,6 +138,25 @@ func (g *GitClient) GetCommitMessage(ctx context.Context, hash string) (string,
 	return commit.Message, nil
 }
 
+// GetCommitFiles returns the paths a commit changed, relative to the
+// repository root
+func (g *GitClient) GetCommitFiles(ctx context.Context, hash string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", "--no-renames", "--name-only", "--format=", hash, "--")
+	cmd.Dir = g.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.GetCommitFiles", "show")
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
 // GetLatestCommit returns the latest commit on the current branch
 func (g *GitClient) GetLatestCommit(ctx context.Context) (*repositories.Commit, error) {
 	head, err := g.repo.Head()
@@ -222,6 +241,54 @@ func (g *GitClient) GetCommitsByIssue(ctx context.Context, issueID entities.Issu
 	return result, nil
 }
 
+// GetBranchCommits returns commits on branch that are not reachable from base
+func (g *GitClient) GetBranchCommits(ctx context.Context, branch, base string) ([]repositories.Commit, error) {
+	revRange := branch
+	if base != "" && base != branch {
+		revRange = base + ".." + branch
+	}
+
+	commits, err := g.logCommits(ctx, revRange)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.GetBranchCommits", "log")
+	}
+	return commits, nil
+}
+
+// logCommits runs git log with the given revision arguments and parses the
+// commits it lists, newest first
+func (g *GitClient) logCommits(ctx context.Context, revisions ...string) ([]repositories.Commit, error) {
+	args := append([]string{"log", "--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%B%x1e"}, revisions...)
+	cmd := exec.CommandContext(ctx, "git", append(args, "--")...)
+	cmd.Dir = g.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []repositories.Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 5)
+		if len(fields) < 5 {
+			continue
+		}
+		date, err := time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			continue
+		}
+		result = append(result, repositories.Commit{
+			Hash:      fields[0],
+			Author:    fields[1],
+			Email:     fields[2],
+			Date:      date,
+			Message:   fields[4],
+			IssueRefs: g.ParseIssueReferences(fields[4]),
+		})
+	}
+
+	return result, nil
+}
+
 // ParseIssueReferences extracts issue references from a commit message
 func (g *GitClient) ParseIssueReferences(message string) []string {
 	// Patterns to match:
@@ -339,6 +406,108 @@ fi
 	return nil
 }
 
+// postCommitHookMarker identifies a post-commit hook written by IssueMap
+const postCommitHookMarker = "# IssueMap post-commit hook"
+
+// InstallPostCommitHook installs a post-commit hook that records estimated
+// commit time for the new commit. An existing post-commit hook that was not
+// written by IssueMap is only replaced when force is set.
+func (g *GitClient) InstallPostCommitHook(ctx context.Context, force bool) error {
+	hooksDir := filepath.Join(g.repoPath, ".git", "hooks")
+
+	postCommitHook := `#!/bin/sh
+` + postCommitHookMarker + `
+# Records estimated time for the new commit against the issues it references
+
+if command -v issuemap >/dev/null 2>&1; then
+    issuemap time from-commits --hook >/dev/null 2>&1 || true
+fi
+`
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return errors.Wrap(err, "GitClient.InstallPostCommitHook", "create_hooks_dir")
+	}
+
+	postCommitPath := filepath.Join(hooksDir, "post-commit")
+	if existing, err := os.ReadFile(postCommitPath); err == nil && !force &&
+		!strings.Contains(string(existing), postCommitHookMarker) {
+		return errors.New("GitClient.InstallPostCommitHook", "hook_exists",
+			fmt.Errorf("%s already exists and was not installed by issuemap", postCommitPath))
+	}
+
+	if err := os.WriteFile(postCommitPath, []byte(postCommitHook), 0755); err != nil {
+		return errors.Wrap(err, "GitClient.InstallPostCommitHook", "write_post_commit")
+	}
+
+	return nil
+}
+
+// preCommitHookMarker identifies a pre-commit hook written by IssueMap
+const preCommitHookMarker = "# IssueMap pre-commit hook"
+
+// InstallPreCommitHook installs a pre-commit hook that validates staged
+// .issuemap files. An existing pre-commit hook that was not written by
+// IssueMap is only replaced when force is set.
+func (g *GitClient) InstallPreCommitHook(ctx context.Context, force bool) error {
+	hooksDir := filepath.Join(g.repoPath, ".git", "hooks")
+
+	preCommitHook := `#!/bin/sh
+` + preCommitHookMarker + `
+# Rejects commits whose staged .issuemap files fail validation
+
+if command -v issuemap >/dev/null 2>&1; then
+    issuemap validate --staged || exit 1
+fi
+`
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return errors.Wrap(err, "GitClient.InstallPreCommitHook", "create_hooks_dir")
+	}
+
+	preCommitPath := filepath.Join(hooksDir, "pre-commit")
+	if existing, err := os.ReadFile(preCommitPath); err == nil && !force &&
+		!strings.Contains(string(existing), preCommitHookMarker) {
+		return errors.New("GitClient.InstallPreCommitHook", "hook_exists",
+			fmt.Errorf("%s already exists and was not installed by issuemap", preCommitPath))
+	}
+
+	if err := os.WriteFile(preCommitPath, []byte(preCommitHook), 0755); err != nil {
+		return errors.Wrap(err, "GitClient.InstallPreCommitHook", "write_pre_commit")
+	}
+
+	return nil
+}
+
+// StagedFiles returns the paths of files under pathspec that are added,
+// copied, modified or renamed in the index
+func (g *GitClient) StagedFiles(ctx context.Context, pathspec string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z", "--", pathspec)
+	cmd.Dir = g.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.StagedFiles", "diff")
+	}
+
+	var files []string
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// ReadStagedFile returns the content of a file as staged in the index
+func (g *GitClient) ReadStagedFile(ctx context.Context, path string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", ":"+path)
+	cmd.Dir = g.repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.ReadStagedFile", "show")
+	}
+	return output, nil
+}
+
 // UninstallHooks removes git hooks
 func (g *GitClient) UninstallHooks(ctx context.Context) error {
 	hooksDir := filepath.Join(g.repoPath, ".git", "hooks")
@@ -351,6 +520,21 @@ func (g *GitClient) UninstallHooks(ctx context.Context) error {
 		}
 	}
 
+	// The pre-commit and post-commit hooks are often shared with other
+	// tools, so they are only removed when IssueMap wrote them
+	markedHooks := map[string]string{
+		"pre-commit":  preCommitHookMarker,
+		"post-commit": postCommitHookMarker,
+	}
+	for hook, marker := range markedHooks {
+		hookPath := filepath.Join(hooksDir, hook)
+		if existing, err := os.ReadFile(hookPath); err == nil && strings.Contains(string(existing), marker) {
+			if err := os.Remove(hookPath); err != nil {
+				return errors.Wrap(err, "GitClient.UninstallHooks", "remove_hook")
+			}
+		}
+	}
+
 	return nil
 }
 
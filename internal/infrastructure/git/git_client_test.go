@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitClient_PostCommitHook(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	output, err := exec.Command("git", "init", "-q", dir).CombinedOutput()
+	require.NoError(t, err, string(output))
+	client, err := NewGitClient(dir)
+	require.NoError(t, err)
+
+	hookPath := filepath.Join(dir, ".git", "hooks", "post-commit")
+	ownHook := "#!/bin/sh\necho deploy\n"
+	require.NoError(t, os.WriteFile(hookPath, []byte(ownHook), 0755))
+
+	// Someone else's hook is kept unless replacing it is forced
+	assert.Error(t, client.InstallPostCommitHook(ctx, false))
+	data, err := os.ReadFile(hookPath)
+	require.NoError(t, err)
+	assert.Equal(t, ownHook, string(data))
+
+	// Nor is it removed on uninstall
+	require.NoError(t, client.UninstallHooks(ctx))
+	data, err = os.ReadFile(hookPath)
+	require.NoError(t, err)
+	assert.Equal(t, ownHook, string(data))
+
+	require.NoError(t, client.InstallPostCommitHook(ctx, true))
+	data, err = os.ReadFile(hookPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), postCommitHookMarker)
+
+	// IssueMap's own hook is replaced without force and removed on uninstall
+	require.NoError(t, client.InstallPostCommitHook(ctx, false))
+	require.NoError(t, client.UninstallHooks(ctx))
+	_, err = os.Stat(hookPath)
+	assert.True(t, os.IsNotExist(err))
+}
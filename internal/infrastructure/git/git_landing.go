@@ -0,0 +1,171 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// landingPathspec limits patch comparisons to project files. issuemap
+// commits to .issuemap on both sides of a pull request, so those changes
+// would make otherwise identical patches differ.
+var landingPathspec = []string{"--", ".", ":(exclude).issuemap"}
+
+// PushBranchUpstream pushes a branch to remote and sets it as the upstream
+func (g *GitClient) PushBranchUpstream(ctx context.Context, remote, branch string) error {
+	cmd := exec.CommandContext(ctx, "git", "push", "--set-upstream", remote, branch)
+	cmd.Dir = g.repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrap(err, "GitClient.PushBranchUpstream", "push_failed: "+strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// FetchBranch updates the remote-tracking branch of remote/branch
+func (g *GitClient) FetchBranch(ctx context.Context, remote, branch string) error {
+	cmd := exec.CommandContext(ctx, "git", "fetch", remote, branch)
+	cmd.Dir = g.repoPath
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrap(err, "GitClient.FetchBranch", "fetch_failed: "+strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// ResolveCommit returns the full hash of the commit ref points to
+func (g *GitClient) ResolveCommit(ctx context.Context, ref string) (string, error) {
+	output, err := g.runGit(ctx, nil, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", errors.Wrap(err, "GitClient.ResolveCommit", "rev_parse")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// FindLanding reports whether the changes of head have reached base and how.
+// Besides merge commits and fast-forwards it recognises squash and rebase
+// merges by comparing patch IDs, which stay the same when a change is
+// replayed onto another parent. It returns nil when head has not landed.
+func (g *GitClient) FindLanding(ctx context.Context, head, base string) (*repositories.BranchLanding, error) {
+	headHash, err := g.ResolveCommit(ctx, head)
+	if err != nil {
+		return nil, err
+	}
+	baseHash, err := g.ResolveCommit(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := g.runGit(ctx, nil, "merge-base", "--is-ancestor", headHash, baseHash); err == nil {
+		return &repositories.BranchLanding{Method: entities.LandingMethodMerge, Commits: []string{headHash}}, nil
+	}
+
+	output, err := g.runGit(ctx, nil, "merge-base", headHash, baseHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.FindLanding", "merge_base")
+	}
+	mergeBase := strings.TrimSpace(string(output))
+
+	basePatches, err := g.commitPatchIDs(ctx, mergeBase+".."+baseHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(basePatches) == 0 {
+		return nil, nil
+	}
+	onBase := make(map[string]string, len(basePatches))
+	for _, patch := range basePatches {
+		onBase[patch.id] = patch.commit
+	}
+
+	// Rebase merge: every branch commit has an equivalent on the base
+	branchPatches, err := g.commitPatchIDs(ctx, mergeBase+".."+headHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(branchPatches) > 0 {
+		var commits []string
+		for _, patch := range branchPatches {
+			commit, ok := onBase[patch.id]
+			if !ok {
+				commits = nil
+				break
+			}
+			commits = append(commits, commit)
+		}
+		if commits != nil {
+			return &repositories.BranchLanding{Method: entities.LandingMethodRebase, Commits: commits}, nil
+		}
+	}
+
+	// Squash merge: one base commit carries the whole branch diff
+	diff, err := g.runGit(ctx, nil, append([]string{"diff", "--no-color", "--no-ext-diff", mergeBase, headHash}, landingPathspec...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.FindLanding", "diff")
+	}
+	squash, err := g.patchIDs(ctx, diff)
+	if err != nil {
+		return nil, err
+	}
+	if len(squash) == 1 {
+		if commit, ok := onBase[squash[0].id]; ok {
+			return &repositories.BranchLanding{Method: entities.LandingMethodSquash, Commits: []string{commit}}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// patchID is the patch ID of one commit or diff
+type patchID struct {
+	id     string
+	commit string
+}
+
+// commitPatchIDs returns the patch IDs of the non-merge commits in revRange
+// that change project files, newest first
+func (g *GitClient) commitPatchIDs(ctx context.Context, revRange string) ([]patchID, error) {
+	log, err := g.runGit(ctx, nil, append([]string{"log", "--no-merges", "--no-color", "--no-ext-diff", "-p", revRange}, landingPathspec...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.commitPatchIDs", "log")
+	}
+	return g.patchIDs(ctx, log)
+}
+
+// patchIDs runs git patch-id over a patch series and returns the patch ID
+// of each patch in input order
+func (g *GitClient) patchIDs(ctx context.Context, patches []byte) ([]patchID, error) {
+	if len(bytes.TrimSpace(patches)) == 0 {
+		return nil, nil
+	}
+	output, err := g.runGit(ctx, patches, "patch-id", "--stable")
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.patchIDs", "patch_id")
+	}
+
+	var ids []patchID
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			ids = append(ids, patchID{id: fields[0], commit: fields[1]})
+		}
+	}
+	return ids, nil
+}
+
+// runGit runs a git command in the repository with optional stdin
+func (g *GitClient) runGit(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.repoPath
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	return cmd.Output()
+}
@@ -0,0 +1,108 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// landingTestRepo creates a repository with a main branch and a feature
+// branch holding two commits
+func landingTestRepo(t *testing.T) (*GitClient, func(args ...string)) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	run("init", "-q", "-b", "main")
+	write("README", "readme\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "init")
+
+	run("checkout", "-q", "-b", "feature")
+	write("widget.go", "package widget\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "TEST-001: widget")
+	write("widget.go", "package widget\n\nconst Size = 1\n")
+	write(".issuemap/issues/TEST-001.yaml", "status: review\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "TEST-001: size")
+	run("checkout", "-q", "main")
+
+	// Unrelated work on main after the branch was cut
+	write("other.txt", "other\n")
+	run("add", ".")
+	run("commit", "-q", "-m", "other")
+
+	client, err := NewGitClient(dir)
+	require.NoError(t, err)
+	return client, run
+}
+
+func TestFindLanding(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not landed", func(t *testing.T) {
+		client, _ := landingTestRepo(t)
+		landing, err := client.FindLanding(ctx, "feature", "main")
+		require.NoError(t, err)
+		assert.Nil(t, landing)
+	})
+
+	t.Run("merge commit", func(t *testing.T) {
+		client, run := landingTestRepo(t)
+		run("merge", "-q", "--no-ff", "-m", "Merge feature", "feature")
+		landing, err := client.FindLanding(ctx, "feature", "main")
+		require.NoError(t, err)
+		require.NotNil(t, landing)
+		assert.Equal(t, entities.LandingMethodMerge, landing.Method)
+	})
+
+	t.Run("squash", func(t *testing.T) {
+		client, run := landingTestRepo(t)
+		run("merge", "-q", "--squash", "feature")
+		run("commit", "-q", "-m", "Add widget (#1)")
+		squash, err := client.ResolveCommit(ctx, "main")
+		require.NoError(t, err)
+
+		landing, err := client.FindLanding(ctx, "feature", "main")
+		require.NoError(t, err)
+		require.NotNil(t, landing)
+		assert.Equal(t, entities.LandingMethodSquash, landing.Method)
+		assert.Equal(t, []string{squash}, landing.Commits)
+	})
+
+	t.Run("rebase", func(t *testing.T) {
+		client, run := landingTestRepo(t)
+		run("cherry-pick", "feature~1", "feature")
+		landing, err := client.FindLanding(ctx, "feature", "main")
+		require.NoError(t, err)
+		require.NotNil(t, landing)
+		assert.Equal(t, entities.LandingMethodRebase, landing.Method)
+		assert.Len(t, landing.Commits, 2)
+	})
+}
@@ -0,0 +1,42 @@
+package git
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+// CommitTime returns the committer date of the commit ref points to
+func (g *GitClient) CommitTime(ctx context.Context, ref string) (time.Time, error) {
+	output, err := g.runGit(ctx, nil, "log", "-1", "--format=%cI", ref+"^{commit}", "--")
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "GitClient.CommitTime", "log")
+	}
+	date, err := time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "GitClient.CommitTime", "parse_date")
+	}
+	return date, nil
+}
+
+// IsTag reports whether ref names a tag
+func (g *GitClient) IsTag(ctx context.Context, ref string) bool {
+	_, err := g.runGit(ctx, nil, "rev-parse", "--verify", "--quiet", "refs/tags/"+ref)
+	return err == nil
+}
+
+// PreviousTag returns the newest tag reachable from ref, excluding a tag on
+// ref itself, and "" when there is none
+func (g *GitClient) PreviousTag(ctx context.Context, ref string) (string, error) {
+	if _, err := g.ResolveCommit(ctx, ref); err != nil {
+		return "", err
+	}
+	output, err := g.runGit(ctx, nil, "describe", "--tags", "--abbrev=0", ref+"^")
+	if err != nil {
+		// describe fails when no tag is reachable or ref has no parent
+		return "", nil
+	}
+	return strings.TrimSpace(string(output)), nil
+}
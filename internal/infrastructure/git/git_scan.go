@@ -0,0 +1,57 @@
+package git
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// scanRefs are the refs a commit scan walks
+var scanRefs = []string{"refs/heads", "refs/remotes", "refs/tags"}
+
+// GetRefTips returns the commit each branch, remote-tracking branch and tag
+// points to, keyed by full ref name
+func (g *GitClient) GetRefTips(ctx context.Context) (map[string]string, error) {
+	args := append([]string{"for-each-ref", "--format=%(refname)%09%(objectname)%09%(*objectname)"}, scanRefs...)
+	output, err := g.runGit(ctx, nil, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.GetRefTips", "for_each_ref")
+	}
+
+	tips := make(map[string]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 || strings.HasSuffix(fields[0], "/HEAD") {
+			continue
+		}
+		// Annotated tags point to the tag object; record the commit
+		hash := fields[1]
+		if fields[2] != "" {
+			hash = fields[2]
+		}
+		tips[fields[0]] = hash
+	}
+	return tips, nil
+}
+
+// GetCommitsExcluding returns the commits reachable from any branch,
+// remote-tracking branch or tag but not from the excluded commits, newest
+// first
+func (g *GitClient) GetCommitsExcluding(ctx context.Context, exclude []string) ([]repositories.Commit, error) {
+	revisions := make([]string, 0, len(scanRefs)+len(exclude)+1)
+	for _, ref := range scanRefs {
+		revisions = append(revisions, "--glob="+ref)
+	}
+	if len(exclude) > 0 {
+		revisions = append(revisions, "--not")
+		revisions = append(revisions, exclude...)
+	}
+
+	commits, err := g.logCommits(ctx, revisions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "GitClient.GetCommitsExcluding", "log")
+	}
+	return commits, nil
+}
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+// signatureNamespace is the SSH signature namespace used for issuemap data
+const signatureNamespace = "issuemap"
+
+const sshSignatureHeader = "-----BEGIN SSH SIGNATURE-----"
+
+// HistorySigningEnabled reports whether history entries should be signed.
+// It is controlled by the git config key issuemap.signHistory.
+func (g *GitClient) HistorySigningEnabled(ctx context.Context) bool {
+	if g == nil {
+		return false
+	}
+	value, err := g.getGitConfig("issuemap.signhistory")
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(value) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// SignData creates a detached signature over data using the same key and
+// format git uses for signing commits (user.signingkey, gpg.format)
+func (g *GitClient) SignData(ctx context.Context, data []byte) (string, error) {
+	if g == nil {
+		return "", errors.New("GitClient.SignData", "no_repo", fmt.Errorf("git repository not available"))
+	}
+
+	key, err := g.getGitConfig("user.signingkey")
+	if err != nil || key == "" {
+		return "", errors.New("GitClient.SignData", "no_key", fmt.Errorf("no signing key configured (git config user.signingkey)"))
+	}
+
+	format, _ := g.getGitConfig("gpg.format")
+
+	var cmd *exec.Cmd
+	switch format {
+	case "ssh":
+		keyFile, cleanup, err := sshKeyFile(key)
+		if err != nil {
+			return "", errors.Wrap(err, "GitClient.SignData", "ssh_key")
+		}
+		defer cleanup()
+		cmd = exec.CommandContext(ctx, g.signingProgram("gpg.ssh.program", "ssh-keygen"),
+			"-Y", "sign", "-n", signatureNamespace, "-f", keyFile)
+	case "x509":
+		cmd = exec.CommandContext(ctx, g.signingProgram("gpg.x509.program", "gpgsm"),
+			"--armor", "--detach-sign", "--local-user", key)
+	default:
+		cmd = exec.CommandContext(ctx, g.signingProgram("gpg.program", "gpg"),
+			"--batch", "--armor", "--detach-sign", "--local-user", key)
+	}
+
+	cmd.Dir = g.repoPath
+	cmd.Stdin = bytes.NewReader(data)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrap(fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String())), "GitClient.SignData", "sign")
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// VerifySignature checks a detached signature over data and returns the signer.
+// SSH signatures are checked against gpg.ssh.allowedSignersFile.
+func (g *GitClient) VerifySignature(ctx context.Context, data []byte, signature string) (string, error) {
+	if g == nil {
+		return "", errors.New("GitClient.VerifySignature", "no_repo", fmt.Errorf("git repository not available"))
+	}
+
+	sigFile, err := os.CreateTemp("", "issuemap-sig-*")
+	if err != nil {
+		return "", errors.Wrap(err, "GitClient.VerifySignature", "temp_file")
+	}
+	defer os.Remove(sigFile.Name())
+	if _, err := sigFile.WriteString(signature + "\n"); err != nil {
+		sigFile.Close()
+		return "", errors.Wrap(err, "GitClient.VerifySignature", "write_signature")
+	}
+	sigFile.Close()
+
+	if strings.HasPrefix(signature, sshSignatureHeader) {
+		return g.verifySSHSignature(ctx, data, sigFile.Name())
+	}
+
+	program := g.signingProgram("gpg.program", "gpg")
+	if strings.Contains(signature, "BEGIN SIGNED MESSAGE") {
+		program = g.signingProgram("gpg.x509.program", "gpgsm")
+	}
+
+	cmd := exec.CommandContext(ctx, program, "--batch", "--status-fd=1", "--verify", sigFile.Name(), "-")
+	cmd.Dir = g.repoPath
+	cmd.Stdin = bytes.NewReader(data)
+	output, _ := cmd.Output()
+
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[0] == "[GNUPG:]" && fields[1] == "GOODSIG" {
+			if len(fields) > 3 {
+				return strings.Join(fields[3:], " "), nil
+			}
+			return fields[2], nil
+		}
+	}
+
+	return "", errors.New("GitClient.VerifySignature", "bad_signature", fmt.Errorf("signature could not be verified"))
+}
+
+// verifySSHSignature verifies an SSH signature using ssh-keygen and the allowed signers file
+func (g *GitClient) verifySSHSignature(ctx context.Context, data []byte, sigPath string) (string, error) {
+	allowedSigners, err := g.getGitConfig("gpg.ssh.allowedsignersfile")
+	if err != nil || allowedSigners == "" {
+		return "", errors.New("GitClient.VerifySignature", "no_allowed_signers",
+			fmt.Errorf("gpg.ssh.allowedSignersFile is not configured"))
+	}
+	allowedSigners = expandHome(allowedSigners)
+	program := g.signingProgram("gpg.ssh.program", "ssh-keygen")
+
+	find := exec.CommandContext(ctx, program, "-Y", "find-principals", "-s", sigPath, "-f", allowedSigners)
+	find.Dir = g.repoPath
+	output, err := find.Output()
+	if err != nil {
+		return "", errors.New("GitClient.VerifySignature", "unknown_signer", fmt.Errorf("signer is not in the allowed signers file"))
+	}
+	principal := strings.TrimSpace(strings.Split(string(output), "\n")[0])
+
+	verify := exec.CommandContext(ctx, program, "-Y", "verify", "-f", allowedSigners,
+		"-I", principal, "-n", signatureNamespace, "-s", sigPath)
+	verify.Dir = g.repoPath
+	verify.Stdin = bytes.NewReader(data)
+	if err := verify.Run(); err != nil {
+		return "", errors.New("GitClient.VerifySignature", "bad_signature", fmt.Errorf("signature could not be verified"))
+	}
+
+	return principal, nil
+}
+
+// signingProgram returns the program configured under key, or the default
+func (g *GitClient) signingProgram(key, defaultProgram string) string {
+	if program, err := g.getGitConfig(key); err == nil && program != "" {
+		return program
+	}
+	return defaultProgram
+}
+
+// sshKeyFile returns a path to the SSH signing key. Literal keys ("key::...")
+// are written to a temporary file, mirroring git's behaviour.
+func sshKeyFile(key string) (string, func(), error) {
+	if !strings.HasPrefix(key, "key::") && !strings.HasPrefix(key, "ssh-") {
+		return expandHome(key), func() {}, nil
+	}
+
+	file, err := os.CreateTemp("", "issuemap-key-*")
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := file.WriteString(strings.TrimPrefix(key, "key::") + "\n"); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", nil, err
+	}
+	file.Close()
+	return file.Name(), func() { os.Remove(file.Name()) }, nil
+}
+
+// expandHome expands a leading ~ in a path
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
+		}
+	}
+	return path
+}
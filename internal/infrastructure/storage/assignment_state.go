@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+// assignmentStateFile holds the round-robin positions of owner
+// assignment, relative to the .issuemap directory
+var assignmentStateFile = filepath.Join(app.MetadataDirName, "assignment.yaml")
+
+// ReadAssignmentState returns the assignment state of the .issuemap
+// directory at basePath, empty when nothing has been assigned yet
+func ReadAssignmentState(basePath string) (*entities.AssignmentState, error) {
+	state := &entities.AssignmentState{RoundRobin: map[string]int{}}
+	data, err := os.ReadFile(filepath.Join(basePath, assignmentStateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, errors.Wrap(err, "ReadAssignmentState", "read")
+	}
+	if err := yaml.Unmarshal(data, state); err != nil {
+		return nil, errors.Wrap(err, "ReadAssignmentState", "unmarshal")
+	}
+	if state.RoundRobin == nil {
+		state.RoundRobin = map[string]int{}
+	}
+	return state, nil
+}
+
+// WriteAssignmentState records the assignment state
+func WriteAssignmentState(basePath string, state *entities.AssignmentState) error {
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return errors.Wrap(err, "WriteAssignmentState", "marshal")
+	}
+	path := filepath.Join(basePath, assignmentStateFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "WriteAssignmentState", "mkdir")
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "WriteAssignmentState", "write")
+	}
+	return nil
+}
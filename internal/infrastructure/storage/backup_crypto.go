@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const backupKeySize = 32
+
+// BackupKeyring stores backup encryption keys. Keys are never deleted so
+// that backups sealed with a rotated key can still be restored.
+type BackupKeyring struct {
+	dir string
+}
+
+// NewBackupKeyring creates a keyring stored in dir
+func NewBackupKeyring(dir string) *BackupKeyring {
+	return &BackupKeyring{dir: dir}
+}
+
+// Generate creates a new random key and returns its ID
+func (k *BackupKeyring) Generate() (string, error) {
+	key := make([]byte, backupKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(k.dir, 0700); err != nil {
+		return "", err
+	}
+
+	id := backupKeyID(key)
+	if err := os.WriteFile(k.keyPath(id), []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Key loads a key by ID
+func (k *BackupKeyring) Key(id string) ([]byte, error) {
+	data, err := os.ReadFile(k.keyPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("backup key %s not found in %s", id, k.dir)
+		}
+		return nil, err
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != backupKeySize {
+		return nil, fmt.Errorf("backup key %s is malformed", id)
+	}
+	if backupKeyID(key) != id {
+		return nil, fmt.Errorf("backup key %s does not match its ID", id)
+	}
+	return key, nil
+}
+
+// Has reports whether the keyring holds a key
+func (k *BackupKeyring) Has(id string) bool {
+	_, err := os.Stat(k.keyPath(id))
+	return err == nil
+}
+
+func (k *BackupKeyring) keyPath(id string) string {
+	return filepath.Join(k.dir, id+".key")
+}
+
+// backupKeyID derives a short identifier from a key
+func backupKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
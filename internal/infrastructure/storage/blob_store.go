@@ -0,0 +1,336 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// lfsPointerVersion is the spec line that starts every git-LFS pointer file
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// NewBlobStore creates the blob store for a backend
+func NewBlobStore(basePath string, backend entities.BlobBackend, config *entities.BlobStoreConfig) (repositories.BlobStore, error) {
+	switch backend {
+	case entities.BlobBackendLocal, "":
+		return NewLocalBlobStore(basePath), nil
+	case entities.BlobBackendLFS:
+		return NewLFSBlobStore(basePath), nil
+	case entities.BlobBackendS3:
+		if config == nil || config.S3 == nil {
+			return nil, errors.New("NewBlobStore", "config", fmt.Errorf("s3 backend requires storage.blob_store.s3 settings"))
+		}
+		return NewS3BlobStore(*config.S3)
+	}
+	return nil, errors.New("NewBlobStore", "backend", fmt.Errorf("unknown blob backend %q", backend))
+}
+
+// validateBlobKey rejects keys that could escape the attachment directories
+func validateBlobKey(key string) error {
+	if strings.Contains(key, "..") ||
+		(!strings.HasPrefix(key, "attachments/") && !strings.HasPrefix(key, "dedup/")) {
+		return fmt.Errorf("invalid storage path")
+	}
+	if path.Clean(key) != key {
+		return fmt.Errorf("invalid storage path")
+	}
+	return nil
+}
+
+// LocalBlobStore stores blobs as plain files under the .issuemap directory
+type LocalBlobStore struct {
+	basePath string
+}
+
+// NewLocalBlobStore creates a local blob store
+func NewLocalBlobStore(basePath string) *LocalBlobStore {
+	return &LocalBlobStore{basePath: basePath}
+}
+
+// Backend returns the local backend
+func (s *LocalBlobStore) Backend() entities.BlobBackend {
+	return entities.BlobBackendLocal
+}
+
+// Put writes content to the file at key
+func (s *LocalBlobStore) Put(ctx context.Context, key string, content io.Reader) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", errors.Wrap(err, "LocalBlobStore.Put", "security")
+	}
+
+	fullPath := filepath.Join(s.basePath, filepath.FromSlash(key))
+	if err := writeFileAtomic(fullPath, content); err != nil {
+		return "", errors.Wrap(err, "LocalBlobStore.Put", "write")
+	}
+
+	return key, nil
+}
+
+// Get opens the file at key
+func (s *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	fullPath, err := s.resolve(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "LocalBlobStore.Get", "security")
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrap(errors.ErrAttachmentNotFound, "LocalBlobStore.Get", "not_found")
+		}
+		return nil, errors.Wrap(err, "LocalBlobStore.Get", "open")
+	}
+
+	return file, nil
+}
+
+// Delete removes the file at key
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	fullPath, err := s.resolve(key)
+	if err != nil {
+		return errors.Wrap(err, "LocalBlobStore.Delete", "security")
+	}
+
+	if err := os.Remove(fullPath); err != nil {
+		if os.IsNotExist(err) {
+			return errors.Wrap(errors.ErrAttachmentNotFound, "LocalBlobStore.Delete", "not_found")
+		}
+		return errors.Wrap(err, "LocalBlobStore.Delete", "remove")
+	}
+
+	return nil
+}
+
+// Exists reports whether the file at key exists
+func (s *LocalBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	fullPath, err := s.resolve(key)
+	if err != nil {
+		return false, errors.Wrap(err, "LocalBlobStore.Exists", "security")
+	}
+	if _, err := os.Stat(fullPath); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "LocalBlobStore.Exists", "stat")
+	}
+	return true, nil
+}
+
+// resolve returns the absolute path of key, ensuring it stays within the base directory
+func (s *LocalBlobStore) resolve(key string) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", err
+	}
+
+	absBasePath, err := filepath.Abs(s.basePath)
+	if err != nil {
+		return "", err
+	}
+	absFullPath, err := filepath.Abs(filepath.Join(s.basePath, filepath.FromSlash(key)))
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(absFullPath, absBasePath+string(filepath.Separator)) {
+		return "", fmt.Errorf("path outside base directory")
+	}
+
+	return absFullPath, nil
+}
+
+// LFSBlobStore writes git-LFS pointer files under the .issuemap directory,
+// where they are committed like any other file, and keeps the content in the
+// repository's LFS object store (.git/lfs/objects), which git does not track.
+// Objects can be shared with other clones using git lfs push/fetch.
+type LFSBlobStore struct {
+	basePath string
+	pointers *LocalBlobStore
+}
+
+// NewLFSBlobStore creates a git-LFS pointer blob store
+func NewLFSBlobStore(basePath string) *LFSBlobStore {
+	return &LFSBlobStore{
+		basePath: basePath,
+		pointers: NewLocalBlobStore(basePath),
+	}
+}
+
+// Backend returns the LFS backend
+func (s *LFSBlobStore) Backend() entities.BlobBackend {
+	return entities.BlobBackendLFS
+}
+
+// Put stores content as an LFS object and writes a pointer file at key
+func (s *LFSBlobStore) Put(ctx context.Context, key string, content io.Reader) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", errors.Wrap(err, "LFSBlobStore.Put", "security")
+	}
+
+	objectsDir, err := s.objectsDir()
+	if err != nil {
+		return "", errors.Wrap(err, "LFSBlobStore.Put", "git_dir")
+	}
+
+	tmpDir := filepath.Join(filepath.Dir(objectsDir), "tmp")
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return "", errors.Wrap(err, "LFSBlobStore.Put", "mkdir")
+	}
+	tmp, err := os.CreateTemp(tmpDir, "issuemap-*")
+	if err != nil {
+		return "", errors.Wrap(err, "LFSBlobStore.Put", "create_temp")
+	}
+	defer os.Remove(tmp.Name())
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "LFSBlobStore.Put", "write")
+	}
+
+	oid := hex.EncodeToString(hasher.Sum(nil))
+	objectPath := filepath.Join(objectsDir, oid[0:2], oid[2:4], oid)
+	if _, err := os.Stat(objectPath); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(objectPath), 0755); err != nil {
+			return "", errors.Wrap(err, "LFSBlobStore.Put", "mkdir")
+		}
+		if err := os.Rename(tmp.Name(), objectPath); err != nil {
+			return "", errors.Wrap(err, "LFSBlobStore.Put", "store_object")
+		}
+	}
+
+	pointer := fmt.Sprintf("%s\noid sha256:%s\nsize %d\n", lfsPointerVersion, oid, size)
+	if _, err := s.pointers.Put(ctx, key, strings.NewReader(pointer)); err != nil {
+		return "", errors.Wrap(err, "LFSBlobStore.Put", "write_pointer")
+	}
+
+	return entities.JoinStoragePath(entities.BlobBackendLFS, key), nil
+}
+
+// Get opens the LFS object referenced by the pointer file at key
+func (s *LFSBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	oid, _, err := s.readPointer(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	objectsDir, err := s.objectsDir()
+	if err != nil {
+		return nil, errors.Wrap(err, "LFSBlobStore.Get", "git_dir")
+	}
+
+	file, err := os.Open(filepath.Join(objectsDir, oid[0:2], oid[2:4], oid))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.Wrap(fmt.Errorf("%w: LFS object %s is not available locally (try git lfs fetch)",
+				errors.ErrAttachmentNotFound, oid), "LFSBlobStore.Get", "not_found")
+		}
+		return nil, errors.Wrap(err, "LFSBlobStore.Get", "open")
+	}
+
+	return file, nil
+}
+
+// Delete removes the pointer file at key. The LFS object is left in place since
+// other pointers may share it; git lfs prune reclaims unreferenced objects.
+func (s *LFSBlobStore) Delete(ctx context.Context, key string) error {
+	return s.pointers.Delete(ctx, key)
+}
+
+// Exists reports whether the pointer at key exists and its object is available
+func (s *LFSBlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	if exists, err := s.pointers.Exists(ctx, key); err != nil || !exists {
+		return false, err
+	}
+	content, err := s.Get(ctx, key)
+	if err != nil {
+		return false, nil
+	}
+	content.Close()
+	return true, nil
+}
+
+// readPointer parses the LFS pointer file at key
+func (s *LFSBlobStore) readPointer(ctx context.Context, key string) (string, int64, error) {
+	file, err := s.pointers.Get(ctx, key)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	var oid string
+	var size int64 = -1
+	scanner := bufio.NewScanner(io.LimitReader(file, 1024))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "oid sha256:"):
+			oid = strings.TrimPrefix(line, "oid sha256:")
+		case strings.HasPrefix(line, "size "):
+			size, _ = strconv.ParseInt(strings.TrimPrefix(line, "size "), 10, 64)
+		}
+	}
+
+	if len(oid) != sha256.Size*2 || size < 0 {
+		return "", 0, errors.Wrap(fmt.Errorf("%s is not a git-LFS pointer file", key), "LFSBlobStore.readPointer", "parse")
+	}
+	return oid, size, nil
+}
+
+// objectsDir returns the repository's LFS object directory
+func (s *LFSBlobStore) objectsDir() (string, error) {
+	repoRoot := filepath.Dir(s.basePath)
+
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	cmd.Dir = repoRoot
+	if output, err := cmd.Output(); err == nil {
+		gitDir := strings.TrimSpace(string(output))
+		if !filepath.IsAbs(gitDir) {
+			gitDir = filepath.Join(repoRoot, gitDir)
+		}
+		return filepath.Join(gitDir, "lfs", "objects"), nil
+	}
+
+	gitDir := filepath.Join(repoRoot, ".git")
+	if info, err := os.Stat(gitDir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("git directory not found for %s", repoRoot)
+	}
+	return filepath.Join(gitDir, "lfs", "objects"), nil
+}
+
+// writeFileAtomic writes content to path via a temporary file in the same directory
+func writeFileAtomic(path string, content io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Encrypted streams are written as a header followed by AES-256-GCM sealed
+// chunks. Each chunk's nonce is the random prefix, the chunk counter and a
+// final-chunk flag, so reordered, dropped or truncated chunks fail to open.
+// The header records the ID of the key the stream was sealed with.
+const (
+	cipherMagic           = "IMBKENC1"
+	cipherChunkSize       = 64 * 1024
+	cipherNoncePrefixSize = 7
+)
+
+// encryptWriter seals everything written to it in chunks
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+// newEncryptWriter writes the header and returns a writer that
+// encrypts to w. Close must be called to write the final chunk.
+func newEncryptWriter(w io.Writer, key []byte, keyID string) (io.WriteCloser, error) {
+	aead, err := newCipherAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := make([]byte, cipherNoncePrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, err
+	}
+
+	header := []byte(cipherMagic)
+	header = append(header, byte(len(keyID)))
+	header = append(header, keyID...)
+	header = append(header, prefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		prefix: prefix,
+		buf:    make([]byte, 0, cipherChunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, fmt.Errorf("write to closed encrypter")
+	}
+
+	written := 0
+	for len(p) > 0 {
+		// Only flush a full chunk once more data arrives, so the last chunk
+		// is always written by Close with the final flag set
+		if len(e.buf) == cipherChunkSize {
+			if err := e.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(e.buf[len(e.buf):cipherChunkSize], p)
+		e.buf = e.buf[:len(e.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// Close writes the final chunk. It does not close the underlying writer.
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.flush(true)
+}
+
+func (e *encryptWriter) flush(final bool) error {
+	sealed := e.aead.Seal(nil, cipherChunkNonce(e.prefix, e.counter, final), e.buf, nil)
+
+	var header [5]byte
+	if final {
+		header[0] = 1
+	}
+	binary.BigEndian.PutUint32(header[1:], uint32(len(sealed)))
+	if _, err := e.w.Write(header[:]); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+
+	e.counter++
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// decryptReader opens chunks written by encryptWriter
+type decryptReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	plain   []byte
+	done    bool
+}
+
+// readCipherHeader reads the key ID and nonce prefix from the header of an
+// encrypted stream
+func readCipherHeader(r io.Reader) (string, []byte, error) {
+	magic := make([]byte, len(cipherMagic)+1)
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return "", nil, fmt.Errorf("not an encrypted data: %w", err)
+	}
+	if string(magic[:len(cipherMagic)]) != cipherMagic {
+		return "", nil, fmt.Errorf("not an encrypted data")
+	}
+
+	rest := make([]byte, int(magic[len(cipherMagic)])+cipherNoncePrefixSize)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return "", nil, fmt.Errorf("truncated cipher header: %w", err)
+	}
+	keyLen := len(rest) - cipherNoncePrefixSize
+	return string(rest[:keyLen]), rest[keyLen:], nil
+}
+
+// newDecryptReader reads the header from r and returns a reader of the
+// plaintext. keyFor returns the key for the key ID recorded in the header.
+func newDecryptReader(r io.Reader, keyFor func(keyID string) ([]byte, error)) (io.Reader, error) {
+	keyID, prefix, err := readCipherHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keyFor(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newCipherAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{r: r, aead: aead, prefix: prefix}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.plain) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.plain)
+	d.plain = d.plain[n:]
+	return n, nil
+}
+
+func (d *decryptReader) next() error {
+	var header [5]byte
+	if _, err := io.ReadFull(d.r, header[:]); err != nil {
+		return fmt.Errorf("encrypted data is truncated")
+	}
+
+	final := header[0] == 1
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > cipherChunkSize+uint32(d.aead.Overhead()) {
+		return fmt.Errorf("encrypted data chunk is too large")
+	}
+
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		return fmt.Errorf("encrypted data is truncated")
+	}
+
+	plain, err := d.aead.Open(nil, cipherChunkNonce(d.prefix, d.counter, final), sealed, nil)
+	if err != nil {
+		return fmt.Errorf("encrypted data failed authentication (wrong key or corrupted data)")
+	}
+
+	if final {
+		// Anything after the final chunk has been appended
+		var extra [1]byte
+		if n, _ := d.r.Read(extra[:]); n > 0 {
+			return fmt.Errorf("unexpected data after final chunk")
+		}
+		d.done = true
+	}
+
+	d.counter++
+	d.plain = plain
+	return nil
+}
+
+func newCipherAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func cipherChunkNonce(prefix []byte, counter uint32, final bool) []byte {
+	nonce := bytes.NewBuffer(make([]byte, 0, 12))
+	nonce.Write(prefix)
+	binary.Write(nonce, binary.BigEndian, counter)
+	if final {
+		nonce.WriteByte(1)
+	} else {
+		nonce.WriteByte(0)
+	}
+	return nonce.Bytes()
+}
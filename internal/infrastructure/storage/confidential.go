@@ -0,0 +1,335 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// Confidential content is sealed with a random content key, which is then
+// wrapped for every recipient: an ephemeral X25519 key agreement with the
+// recipient's public key, HKDF-SHA256 and AES-256-GCM. Recipients hold the
+// matching private key as their identity.
+const (
+	confidentialKeyPrefix = "x25519:"
+	confidentialKeyInfo   = "issuemap confidential key v1"
+	confidentialStreamKey = "confidential"
+)
+
+// ErrNotRecipient is returned when content was not sealed to the identity
+var ErrNotRecipient = fmt.Errorf("not a recipient of this confidential content")
+
+// ConfidentialIdentity is the private key used to read confidential issues
+type ConfidentialIdentity struct {
+	key *ecdh.PrivateKey
+}
+
+// GenerateConfidentialIdentity creates a new identity and stores it at path.
+// An existing identity is never overwritten.
+func GenerateConfidentialIdentity(path string) (*ConfidentialIdentity, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("identity already exists at %s", path)
+	}
+
+	key, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(key.Bytes())
+	if err := os.WriteFile(path, []byte(encoded+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	return &ConfidentialIdentity{key: key}, nil
+}
+
+// LoadConfidentialIdentity reads the identity stored at path
+func LoadConfidentialIdentity(path string) (*ConfidentialIdentity, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("identity %s is malformed", path)
+	}
+	key, err := ecdh.X25519().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("identity %s is malformed: %w", path, err)
+	}
+	return &ConfidentialIdentity{key: key}, nil
+}
+
+// PublicKey returns the recipient key to list in the project configuration
+func (id *ConfidentialIdentity) PublicKey() string {
+	return confidentialKeyPrefix + base64.RawURLEncoding.EncodeToString(id.key.PublicKey().Bytes())
+}
+
+// KeyID returns the short identifier of the identity's public key
+func (id *ConfidentialIdentity) KeyID() string {
+	return confidentialKeyID(id.key.PublicKey())
+}
+
+// ParseConfidentialRecipient parses a recipient public key and returns its
+// key ID
+func ParseConfidentialRecipient(publicKey string) (string, error) {
+	key, err := parseRecipientKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	return confidentialKeyID(key), nil
+}
+
+func parseRecipientKey(publicKey string) (*ecdh.PublicKey, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(publicKey), confidentialKeyPrefix)
+	if !ok {
+		return nil, fmt.Errorf("recipient key must start with %q", confidentialKeyPrefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("recipient key is not valid base64")
+	}
+	key, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("recipient key is invalid: %w", err)
+	}
+	return key, nil
+}
+
+func confidentialKeyID(key *ecdh.PublicKey) string {
+	sum := sha256.Sum256(key.Bytes())
+	return hex.EncodeToString(sum[:8])
+}
+
+// SealIssue returns the copy of an issue to write to disk, with the fields
+// named by the configuration encrypted to its recipients. The sealed content
+// is also recorded on the issue itself.
+func SealIssue(issue *entities.Issue, config *entities.ConfidentialConfig) (*entities.Issue, error) {
+	contentKey := make([]byte, backupKeySize)
+	if _, err := rand.Read(contentKey); err != nil {
+		return nil, err
+	}
+	recipients, err := wrapContentKey(contentKey, config)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := config.SealedFields()
+	payload, err := yaml.Marshal(issue.ExtractSealedFields(fields))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newCipherAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	// Binding the issue ID stops sealed content being moved to another issue
+	ciphertext := aead.Seal(nonce, nonce, payload, []byte(issue.ID))
+
+	issue.Sealed = &entities.SealedContent{
+		Fields:     fields,
+		Recipients: recipients,
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+	}
+
+	stored := *issue
+	stored.ClearSealedFields(fields)
+	return &stored, nil
+}
+
+// OpenIssue decrypts the sealed fields of an issue in place
+func OpenIssue(issue *entities.Issue, identity *ConfidentialIdentity) error {
+	if issue.Sealed == nil {
+		return nil
+	}
+
+	contentKey, err := unwrapContentKey(issue.Sealed, identity)
+	if err != nil {
+		return err
+	}
+	aead, err := newCipherAEAD(contentKey)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(issue.Sealed.Ciphertext)
+	if err != nil || len(ciphertext) < aead.NonceSize() {
+		return fmt.Errorf("sealed content of %s is malformed", issue.ID)
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	payload, err := aead.Open(nil, nonce, ciphertext, []byte(issue.ID))
+	if err != nil {
+		return fmt.Errorf("sealed content of %s failed authentication", issue.ID)
+	}
+
+	var fields entities.ConfidentialFields
+	if err := yaml.Unmarshal(payload, &fields); err != nil {
+		return fmt.Errorf("sealed content of %s is malformed: %w", issue.ID, err)
+	}
+	issue.ApplySealedFields(issue.Sealed.Fields, &fields)
+	return nil
+}
+
+// SealContent returns a reader yielding content encrypted to the configured
+// recipients, along with the wrapped content key needed to open it. The
+// reader must be closed if it is not read to the end.
+func SealContent(content io.Reader, config *entities.ConfidentialConfig) (io.ReadCloser, *entities.SealedContent, error) {
+	contentKey := make([]byte, backupKeySize)
+	if _, err := rand.Read(contentKey); err != nil {
+		return nil, nil, err
+	}
+	recipients, err := wrapContentKey(contentKey, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		encrypter, err := newEncryptWriter(writer, contentKey, confidentialStreamKey)
+		if err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(encrypter, content); err != nil {
+			writer.CloseWithError(err)
+			return
+		}
+		writer.CloseWithError(encrypter.Close())
+	}()
+
+	return reader, &entities.SealedContent{Recipients: recipients}, nil
+}
+
+// OpenContent returns a reader decrypting content sealed by SealContent
+func OpenContent(content io.Reader, sealed *entities.SealedContent, identity *ConfidentialIdentity) (io.Reader, error) {
+	contentKey, err := unwrapContentKey(sealed, identity)
+	if err != nil {
+		return nil, err
+	}
+	return newDecryptReader(content, func(keyID string) ([]byte, error) {
+		if keyID != confidentialStreamKey {
+			return nil, fmt.Errorf("unexpected key %q for confidential content", keyID)
+		}
+		return contentKey, nil
+	})
+}
+
+// wrapContentKey seals the content key for every configured recipient
+func wrapContentKey(contentKey []byte, config *entities.ConfidentialConfig) ([]entities.SealedRecipient, error) {
+	if config == nil || len(config.Recipients) == 0 {
+		return nil, fmt.Errorf("no confidential recipients configured")
+	}
+
+	var wrapped []entities.SealedRecipient
+	for _, recipient := range config.Recipients {
+		publicKey, err := parseRecipientKey(recipient.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %w", recipient.Name, err)
+		}
+
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		shared, err := ephemeral.ECDH(publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %w", recipient.Name, err)
+		}
+		aead, err := keyWrapAEAD(shared, ephemeral.PublicKey(), publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %s: %w", recipient.Name, err)
+		}
+
+		// Each wrapping key is derived from a fresh ephemeral key and used
+		// once, so a fixed nonce is safe
+		nonce := make([]byte, aead.NonceSize())
+		wrapped = append(wrapped, entities.SealedRecipient{
+			Name:       recipient.Name,
+			KeyID:      confidentialKeyID(publicKey),
+			Ephemeral:  base64.StdEncoding.EncodeToString(ephemeral.PublicKey().Bytes()),
+			WrappedKey: base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, contentKey, nil)),
+		})
+	}
+	return wrapped, nil
+}
+
+// unwrapContentKey recovers the content key with the identity
+func unwrapContentKey(sealed *entities.SealedContent, identity *ConfidentialIdentity) ([]byte, error) {
+	if identity == nil {
+		return nil, ErrNotRecipient
+	}
+
+	keyID := identity.KeyID()
+	for _, recipient := range sealed.Recipients {
+		if recipient.KeyID != keyID {
+			continue
+		}
+
+		raw, err := base64.StdEncoding.DecodeString(recipient.Ephemeral)
+		if err != nil {
+			return nil, fmt.Errorf("sealed key is malformed")
+		}
+		ephemeral, err := ecdh.X25519().NewPublicKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("sealed key is malformed: %w", err)
+		}
+		wrappedKey, err := base64.StdEncoding.DecodeString(recipient.WrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("sealed key is malformed")
+		}
+
+		shared, err := identity.key.ECDH(ephemeral)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := keyWrapAEAD(shared, ephemeral, identity.key.PublicKey())
+		if err != nil {
+			return nil, err
+		}
+		contentKey, err := aead.Open(nil, make([]byte, aead.NonceSize()), wrappedKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("sealed key failed authentication")
+		}
+		return contentKey, nil
+	}
+	return nil, ErrNotRecipient
+}
+
+// keyWrapAEAD derives the key wrapping cipher from the shared secret of an
+// ephemeral key and a recipient. The salt binds both public keys.
+func keyWrapAEAD(shared []byte, ephemeral, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	salt := append(bytes.Clone(ephemeral.Bytes()), recipient.Bytes()...)
+	key, err := hkdf.Key(sha256.New, shared, salt, confidentialKeyInfo, backupKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	stderrors "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+// newConfidentialTestRepo creates a project whose confidential issues are
+// sealed to a single recipient, and returns that recipient's identity path
+func newConfidentialTestRepo(t *testing.T) (string, string) {
+	t.Helper()
+	basePath := t.TempDir()
+
+	identityPath := filepath.Join(t.TempDir(), "identity.key")
+	identity, err := GenerateConfidentialIdentity(identityPath)
+	require.NoError(t, err)
+
+	config := entities.NewDefaultConfig()
+	config.Confidential = &entities.ConfidentialConfig{
+		Recipients: []entities.ConfidentialRecipient{{Name: "alice", PublicKey: identity.PublicKey()}},
+	}
+	require.NoError(t, NewFileConfigRepository(basePath).Save(context.Background(), config))
+	return basePath, identityPath
+}
+
+func TestFileIssueRepository_ConfidentialIssues(t *testing.T) {
+	ctx := context.Background()
+	basePath, identityPath := newConfidentialTestRepo(t)
+	t.Setenv("ISSUEMAP_IDENTITY", identityPath)
+
+	issue := entities.NewIssue("TEST-001", "Token leak", "the token is hunter2", entities.IssueTypeBug)
+	issue.AddComment("alice", "rotate it today")
+	issue.Confidential = true
+	require.NoError(t, NewFileIssueRepository(basePath).Create(ctx, issue))
+
+	raw, err := os.ReadFile(filepath.Join(basePath, "issues", "TEST-001.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "hunter2")
+	assert.NotContains(t, string(raw), "rotate it today")
+	assert.Contains(t, string(raw), "Token leak", "titles stay in clear by default")
+
+	// A recipient reads the content transparently
+	opened, err := NewFileIssueRepository(basePath).GetByID(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.False(t, opened.Redacted)
+	assert.Equal(t, "the token is hunter2", opened.Description)
+	require.Len(t, opened.Comments, 1)
+	assert.Equal(t, "rotate it today", opened.Comments[0].Text)
+
+	// Anyone else sees placeholders
+	other := filepath.Join(t.TempDir(), "identity.key")
+	_, err = GenerateConfidentialIdentity(other)
+	require.NoError(t, err)
+	t.Setenv("ISSUEMAP_IDENTITY", other)
+	outsiderRepo := NewFileIssueRepository(basePath)
+
+	redacted, err := outsiderRepo.GetByID(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.True(t, redacted.Redacted)
+	assert.Equal(t, "Token leak", redacted.Title)
+	assert.Equal(t, entities.ConfidentialPlaceholder, redacted.Description)
+	assert.Empty(t, redacted.Comments)
+
+	list, err := outsiderRepo.List(ctx, repositories.IssueFilter{})
+	require.NoError(t, err)
+	require.Len(t, list.Issues, 1)
+	assert.True(t, list.Issues[0].Redacted)
+
+	// Clear fields can still be triaged without losing the sealed content
+	redacted.AddLabel(entities.Label{Name: "security"})
+	require.NoError(t, outsiderRepo.Update(ctx, redacted))
+
+	// Sealed content cannot be overwritten blind
+	redacted.Description = "overwritten"
+	err = outsiderRepo.Update(ctx, redacted)
+	require.Error(t, err)
+	assert.True(t, stderrors.Is(err, errors.ErrPermissionDenied))
+
+	t.Setenv("ISSUEMAP_IDENTITY", identityPath)
+	reopened, err := NewFileIssueRepository(basePath).GetByID(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Equal(t, "the token is hunter2", reopened.Description)
+	require.Len(t, reopened.Labels, 1)
+	assert.Equal(t, "security", reopened.Labels[0].Name)
+}
+
+func TestFileIssueRepository_ConfidentialRequiresRecipients(t *testing.T) {
+	basePath := t.TempDir()
+	require.NoError(t, NewFileConfigRepository(basePath).Save(context.Background(), entities.NewDefaultConfig()))
+
+	issue := entities.NewIssue("TEST-001", "Token leak", "secret", entities.IssueTypeBug)
+	issue.Confidential = true
+	assert.Error(t, NewFileIssueRepository(basePath).Create(context.Background(), issue))
+}
+
+func TestSealContent_RoundTrip(t *testing.T) {
+	basePath, identityPath := newConfidentialTestRepo(t)
+	config, err := NewFileConfigRepository(basePath).Load(context.Background())
+	require.NoError(t, err)
+	identity, err := LoadConfidentialIdentity(identityPath)
+	require.NoError(t, err)
+
+	plain := bytes.Repeat([]byte("attachment "), 10000)
+	sealedReader, sealed, err := SealContent(bytes.NewReader(plain), config.Confidential)
+	require.NoError(t, err)
+	ciphertext, err := io.ReadAll(sealedReader)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(ciphertext, []byte("attachment")))
+
+	reader, err := OpenContent(bytes.NewReader(ciphertext), sealed, identity)
+	require.NoError(t, err)
+	opened, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, plain, opened)
+
+	outsider, err := GenerateConfidentialIdentity(filepath.Join(t.TempDir(), "identity.key"))
+	require.NoError(t, err)
+	_, err = OpenContent(bytes.NewReader(ciphertext), sealed, outsider)
+	assert.ErrorIs(t, err, ErrNotRecipient)
+}
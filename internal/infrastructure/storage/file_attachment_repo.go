@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -16,9 +17,17 @@ import (
 	"github.com/ooyeku/issuemap/internal/domain/repositories"
 )
 
-// FileAttachmentRepository implements the AttachmentRepository interface using file storage
+// FileAttachmentRepository implements the AttachmentRepository interface using file storage.
+// Metadata always lives under .issuemap/attachments/.metadata; content is written
+// to the blob backend selected by storage.blob_store in the project config.
 type FileAttachmentRepository struct {
 	basePath string
+
+	mu        sync.Mutex
+	loaded    bool
+	active    repositories.BlobStore
+	stores    map[entities.BlobBackend]repositories.BlobStore
+	storesErr error
 }
 
 // NewFileAttachmentRepository creates a new file-based attachment repository
@@ -28,6 +37,41 @@ func NewFileAttachmentRepository(basePath string) *FileAttachmentRepository {
 	}
 }
 
+// SetBlobStores overrides the configured blob stores. New content is written to
+// active; existing content is read from whichever store matches its storage path.
+func (r *FileAttachmentRepository) SetBlobStores(active repositories.BlobStore, others ...repositories.BlobStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loaded = true
+	r.stores = map[entities.BlobBackend]repositories.BlobStore{
+		entities.BlobBackendLocal: NewLocalBlobStore(r.basePath),
+	}
+	for _, store := range append(others, active) {
+		r.stores[store.Backend()] = store
+	}
+	r.active = active
+	r.storesErr = nil
+}
+
+// storeFor returns the store for a backend, creating it from the project config
+func (r *FileAttachmentRepository) storeFor(backend entities.BlobBackend) (repositories.BlobStore, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loadBlobStores()
+	if store, ok := r.stores[backend]; ok {
+		return store, nil
+	}
+
+	store, err := NewBlobStore(r.basePath, backend, r.blobStoreConfig())
+	if err != nil {
+		return nil, errors.Wrap(err, "FileAttachmentRepository.storeFor", "create")
+	}
+	r.stores[backend] = store
+	return store, nil
+}
+
 // SaveFile saves an attachment file to storage
 func (r *FileAttachmentRepository) SaveFile(ctx context.Context, issueID entities.IssueID, filename string, content io.Reader) (string, error) {
 	// Validate inputs
@@ -44,77 +88,51 @@ func (r *FileAttachmentRepository) SaveFile(ctx context.Context, issueID entitie
 		return "", errors.Wrap(fmt.Errorf("invalid filename"), "FileAttachmentRepository.SaveFile", "validation")
 	}
 
-	// Create attachments directory structure
-	attachmentsDir := filepath.Join(r.basePath, "attachments", string(issueID))
-	if err := os.MkdirAll(attachmentsDir, 0755); err != nil {
-		return "", errors.Wrap(err, "FileAttachmentRepository.SaveFile", "mkdir")
-	}
-
 	// Generate unique filename to avoid collisions
 	timestamp := fmt.Sprintf("%d", time.Now().UnixNano())
 	uniqueFilename := fmt.Sprintf("%s_%s", timestamp, safeFilename)
-	storagePath := filepath.Join("attachments", string(issueID), uniqueFilename)
+	key := filepath.ToSlash(filepath.Join("attachments", string(issueID), uniqueFilename))
 
 	// Validate storage path for security
-	if strings.Contains(storagePath, "..") {
+	if strings.Contains(key, "..") {
 		return "", errors.Wrap(fmt.Errorf("invalid storage path"), "FileAttachmentRepository.SaveFile", "security")
 	}
 
-	fullPath := filepath.Join(r.basePath, storagePath)
-
-	// Create the file
-	file, err := os.Create(fullPath)
+	storagePath, err := r.SaveBlob(ctx, key, content)
 	if err != nil {
-		return "", errors.Wrap(err, "FileAttachmentRepository.SaveFile", "create")
-	}
-	defer file.Close()
-
-	// Copy content to file
-	if _, err := io.Copy(file, content); err != nil {
-		os.Remove(fullPath) // Clean up on error
-		return "", errors.Wrap(err, "FileAttachmentRepository.SaveFile", "copy")
+		return "", errors.Wrap(err, "FileAttachmentRepository.SaveFile", "save")
 	}
 
 	return storagePath, nil
 }
 
-// GetFile retrieves an attachment file from storage
-func (r *FileAttachmentRepository) GetFile(ctx context.Context, storagePath string) (io.ReadCloser, error) {
-	// Validate storage path for security
-	if strings.Contains(storagePath, "..") ||
-		(!strings.HasPrefix(storagePath, "attachments/") && !strings.HasPrefix(storagePath, "dedup/")) {
-		return nil, errors.Wrap(fmt.Errorf("invalid storage path"), "FileAttachmentRepository.GetFile", "security")
+// SaveBlob stores content under a key in the active blob backend
+func (r *FileAttachmentRepository) SaveBlob(ctx context.Context, key string, content io.Reader) (string, error) {
+	active, err := r.activeStore()
+	if err != nil {
+		return "", errors.Wrap(err, "FileAttachmentRepository.SaveBlob", "blob_store")
 	}
 
-	// Clean the path to prevent path traversal
-	cleanPath := filepath.Clean(storagePath)
-	if cleanPath != storagePath {
-		return nil, errors.Wrap(fmt.Errorf("invalid storage path"), "FileAttachmentRepository.GetFile", "security")
+	storagePath, err := active.Put(ctx, key, content)
+	if err != nil {
+		return "", errors.Wrap(err, "FileAttachmentRepository.SaveBlob", "put")
 	}
 
-	fullPath := filepath.Join(r.basePath, storagePath)
+	return storagePath, nil
+}
 
-	// Ensure the resolved path is still within our base directory
-	absBasePath, err := filepath.Abs(r.basePath)
-	if err != nil {
-		return nil, errors.Wrap(err, "FileAttachmentRepository.GetFile", "abs_path")
-	}
+// GetFile retrieves an attachment file from storage
+func (r *FileAttachmentRepository) GetFile(ctx context.Context, storagePath string) (io.ReadCloser, error) {
+	backend, key := entities.SplitStoragePath(storagePath)
 
-	absFullPath, err := filepath.Abs(fullPath)
+	store, err := r.storeFor(backend)
 	if err != nil {
-		return nil, errors.Wrap(err, "FileAttachmentRepository.GetFile", "abs_path")
+		return nil, errors.Wrap(err, "FileAttachmentRepository.GetFile", "blob_store")
 	}
 
-	if !strings.HasPrefix(absFullPath, absBasePath) {
-		return nil, errors.Wrap(fmt.Errorf("path outside base directory"), "FileAttachmentRepository.GetFile", "security")
-	}
-
-	file, err := os.Open(fullPath)
+	file, err := store.Get(ctx, key)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, errors.Wrap(errors.ErrAttachmentNotFound, "FileAttachmentRepository.GetFile", "not_found")
-		}
-		return nil, errors.Wrap(err, "FileAttachmentRepository.GetFile", "open")
+		return nil, errors.Wrap(err, "FileAttachmentRepository.GetFile", "get")
 	}
 
 	return file, nil
@@ -122,13 +140,15 @@ func (r *FileAttachmentRepository) GetFile(ctx context.Context, storagePath stri
 
 // DeleteFile removes an attachment file from storage
 func (r *FileAttachmentRepository) DeleteFile(ctx context.Context, storagePath string) error {
-	fullPath := filepath.Join(r.basePath, storagePath)
+	backend, key := entities.SplitStoragePath(storagePath)
 
-	if err := os.Remove(fullPath); err != nil {
-		if os.IsNotExist(err) {
-			return errors.Wrap(errors.ErrAttachmentNotFound, "FileAttachmentRepository.DeleteFile", "not_found")
-		}
-		return errors.Wrap(err, "FileAttachmentRepository.DeleteFile", "remove")
+	store, err := r.storeFor(backend)
+	if err != nil {
+		return errors.Wrap(err, "FileAttachmentRepository.DeleteFile", "blob_store")
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		return errors.Wrap(err, "FileAttachmentRepository.DeleteFile", "delete")
 	}
 
 	return nil
@@ -219,6 +239,41 @@ func (r *FileAttachmentRepository) ListByIssue(ctx context.Context, issueID enti
 	return attachments, nil
 }
 
+// ListAll retrieves the metadata of every attachment
+func (r *FileAttachmentRepository) ListAll(ctx context.Context) ([]*entities.Attachment, error) {
+	metadataDir := filepath.Join(r.basePath, "attachments", ".metadata")
+
+	if _, err := os.Stat(metadataDir); os.IsNotExist(err) {
+		return []*entities.Attachment{}, nil
+	}
+
+	files, err := os.ReadDir(metadataDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "FileAttachmentRepository.ListAll", "read_dir")
+	}
+
+	var attachments []*entities.Attachment
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(metadataDir, file.Name()))
+		if err != nil {
+			continue
+		}
+
+		var attachment entities.Attachment
+		if err := yaml.Unmarshal(data, &attachment); err != nil {
+			continue
+		}
+
+		attachments = append(attachments, &attachment)
+	}
+
+	return attachments, nil
+}
+
 // DeleteMetadata removes attachment metadata
 func (r *FileAttachmentRepository) DeleteMetadata(ctx context.Context, attachmentID string) error {
 	metadataPath := filepath.Join(r.basePath, "attachments", ".metadata", fmt.Sprintf("%s.yaml", attachmentID))
@@ -287,3 +342,47 @@ func (r *FileAttachmentRepository) GetStorageStats(ctx context.Context) (*reposi
 
 	return stats, nil
 }
+
+// activeStore returns the store new content is written to
+func (r *FileAttachmentRepository) activeStore() (repositories.BlobStore, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.loadBlobStores()
+	return r.active, r.storesErr
+}
+
+// loadBlobStores creates the active blob store from the project config on
+// first use. Callers must hold r.mu.
+func (r *FileAttachmentRepository) loadBlobStores() {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+
+	r.stores = map[entities.BlobBackend]repositories.BlobStore{
+		entities.BlobBackendLocal: NewLocalBlobStore(r.basePath),
+	}
+	r.active = r.stores[entities.BlobBackendLocal]
+
+	config := r.blobStoreConfig()
+	active, err := NewBlobStore(r.basePath, config.Backend, config)
+	if err != nil {
+		r.storesErr = err
+		return
+	}
+	r.stores[active.Backend()] = active
+	r.active = active
+}
+
+// blobStoreConfig reads the blob store settings from the project config
+func (r *FileAttachmentRepository) blobStoreConfig() *entities.BlobStoreConfig {
+	config, err := NewFileConfigRepository(r.basePath).Load(context.Background())
+	if err != nil || config.StorageConfig == nil || config.StorageConfig.BlobStore == nil {
+		return entities.DefaultBlobStoreConfig()
+	}
+	if config.StorageConfig.BlobStore.Backend == "" {
+		config.StorageConfig.BlobStore.Backend = entities.BlobBackendLocal
+	}
+	return config.StorageConfig.BlobStore
+}
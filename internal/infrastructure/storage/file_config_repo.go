@@ -106,7 +106,7 @@ func (r *FileConfigRepository) Load(ctx context.Context) (*entities.Config, erro
 	}
 
 	var config entities.Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
+	if err := unmarshalVersioned(entities.SchemaKindConfig, data, &config); err != nil {
 		return nil, errors.Wrap(err, "FileConfigRepository.Load", "unmarshal")
 	}
 
@@ -126,6 +126,7 @@ func (r *FileConfigRepository) Save(ctx context.Context, config *entities.Config
 		config.Templates.Available = append(config.Templates.Available, "improvement")
 	}
 
+	config.SchemaVersion = entities.CurrentSchemaVersion(entities.SchemaKindConfig)
 	data, err := yaml.Marshal(config)
 	if err != nil {
 		return errors.Wrap(err, "FileConfigRepository.Save", "marshal")
@@ -283,6 +284,16 @@ func (r *FileConfigRepository) Initialize(ctx context.Context, config *entities.
 		return errors.Wrap(err, "FileConfigRepository.Initialize", "save_config")
 	}
 
+	// New repositories are written in the current format. Re-initialising an
+	// existing one leaves it unmarked so its pending migrations still run.
+	if _, err := os.Stat(filepath.Join(r.basePath, formatVersionFile)); os.IsNotExist(err) {
+		if entries, readErr := os.ReadDir(filepath.Join(r.basePath, "issues")); readErr == nil && len(entries) == 0 {
+			if err := WriteFormatVersion(r.basePath, entities.CurrentFormatVersion); err != nil {
+				return errors.Wrap(err, "FileConfigRepository.Initialize", "write_format_version")
+			}
+		}
+	}
+
 	// Write default agent guide if not present
 	agentDocPath := filepath.Join(r.basePath, "agent.md")
 	if _, err := os.Stat(agentDocPath); os.IsNotExist(err) {
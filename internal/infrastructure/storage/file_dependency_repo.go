@@ -45,6 +45,7 @@ func (r *FileDependencyRepository) Create(ctx context.Context, dependency *entit
 	filename := fmt.Sprintf("%s.yaml", dependency.ID)
 	path := filepath.Join(dir, filename)
 
+	dependency.SchemaVersion = entities.CurrentSchemaVersion(entities.SchemaKindDependency)
 	data, err := yaml.Marshal(dependency)
 	if err != nil {
 		return fmt.Errorf("failed to marshal dependency: %w", err)
@@ -70,7 +71,7 @@ func (r *FileDependencyRepository) GetByID(ctx context.Context, id string) (*ent
 	}
 
 	var dependency entities.Dependency
-	if err := yaml.Unmarshal(data, &dependency); err != nil {
+	if err := unmarshalVersioned(entities.SchemaKindDependency, data, &dependency); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal dependency: %w", err)
 	}
 
@@ -92,6 +93,7 @@ func (r *FileDependencyRepository) Update(ctx context.Context, dependency *entit
 
 	dependency.UpdatedAt = time.Now()
 
+	dependency.SchemaVersion = entities.CurrentSchemaVersion(entities.SchemaKindDependency)
 	data, err := yaml.Marshal(dependency)
 	if err != nil {
 		return fmt.Errorf("failed to marshal dependency: %w", err)
@@ -143,7 +145,7 @@ func (r *FileDependencyRepository) List(ctx context.Context, filter repositories
 		}
 
 		var dependency entities.Dependency
-		if err := yaml.Unmarshal(data, &dependency); err != nil {
+		if err := unmarshalVersioned(entities.SchemaKindDependency, data, &dependency); err != nil {
 			continue // Skip files we can't parse
 		}
 
@@ -5,7 +5,10 @@ import (
 	"compress/gzip"
 	"context"
 	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -452,7 +455,11 @@ func (r *FileGlobalRepository) DeleteArchivedIssue(ctx context.Context, issueID
 	return nil
 }
 
-// CreateBackup creates a complete backup of a project
+// CreateBackup creates a backup of a project. Unless full backups are
+// configured, only files that changed since the project's previous backup
+// are stored and the manifest references earlier archives for the rest.
+// Backups are encrypted when encryption is enabled, and the project's oldest
+// backups are pruned beyond MaxBackupRetention.
 func (r *FileGlobalRepository) CreateBackup(ctx context.Context, projectPath string, metadata *entities.BackupMetadata) (*entities.ProjectBackup, error) {
 	config, err := r.GetConfig(ctx)
 	if err != nil {
@@ -463,9 +470,54 @@ func (r *FileGlobalRepository) CreateBackup(ctx context.Context, projectPath str
 	if !exists {
 		return nil, errors.Wrap(errors.ErrIssueNotFound, "FileGlobalRepository.CreateBackup", "project_not_found")
 	}
+	settings := config.GlobalSettings
 
 	// Generate backup ID
-	backupID := fmt.Sprintf("%s-%d", project.Name, time.Now().Unix())
+	backupID := r.newBackupID(project.Name)
+
+	backup := &entities.ProjectBackup{
+		ID:          backupID,
+		ProjectPath: project.Path,
+		ProjectName: project.Name,
+		Encrypted:   settings.EncryptionEnabled,
+	}
+
+	var key []byte
+	if backup.Encrypted {
+		backup.KeyID, key, err = r.activeBackupKey(ctx, config)
+		if err != nil {
+			return nil, errors.Wrap(err, "FileGlobalRepository.CreateBackup", "load_key")
+		}
+	}
+
+	// Hash the project files and compare them with the previous backup
+	files, err := hashBackupFiles(filepath.Join(projectPath, ".issuemap"))
+	if err != nil {
+		return nil, errors.Wrap(err, "FileGlobalRepository.CreateBackup", "hash_files")
+	}
+
+	previous := make(map[string]entities.BackupFile)
+	if !settings.FullBackupsOnly {
+		if base := r.latestBackup(ctx, project.Path); base != nil && r.canIncrementFrom(ctx, base, backup) {
+			backup.Incremental = true
+			backup.BaseBackupID = base.ID
+			for _, file := range base.Files {
+				previous[file.Path] = file
+			}
+		}
+	}
+
+	stored := make(map[string]bool)
+	for i := range files {
+		if prev, ok := previous[files[i].Path]; ok && prev.Hash == files[i].Hash {
+			files[i].BackupID = prev.BackupID
+			continue
+		}
+		files[i].BackupID = backupID
+		stored[files[i].Path] = true
+	}
+	backup.Files = files
+	backup.StoredFiles = len(stored)
 
 	// Create backup directory
 	backupDir := filepath.Join(entities.GetBackupPath(), backupID)
@@ -475,7 +527,11 @@ func (r *FileGlobalRepository) CreateBackup(ctx context.Context, projectPath str
 
 	// Create backup archive
 	backupFile := filepath.Join(backupDir, "backup.tar.gz")
-	if err := r.createBackupArchive(projectPath, backupFile); err != nil {
+	if backup.Encrypted {
+		backupFile += ".enc"
+	}
+	if err := r.createBackupArchive(projectPath, backupFile, stored, key, backup.KeyID); err != nil {
+		os.RemoveAll(backupDir)
 		return nil, errors.Wrap(err, "FileGlobalRepository.CreateBackup", "create_archive")
 	}
 
@@ -498,17 +554,12 @@ func (r *FileGlobalRepository) CreateBackup(ctx context.Context, projectPath str
 	metadata.OperatingSystem = runtime.GOOS
 	metadata.Architecture = runtime.GOARCH
 
-	// Create backup record
-	backup := &entities.ProjectBackup{
-		ID:          backupID,
-		ProjectPath: projectPath,
-		ProjectName: project.Name,
-		BackupPath:  backupFile,
-		CreatedAt:   time.Now(),
-		Size:        stat.Size(),
-		Checksum:    checksum,
-		Metadata:    *metadata,
-	}
+	// Complete backup record
+	backup.BackupPath = backupFile
+	backup.CreatedAt = time.Now()
+	backup.Size = stat.Size()
+	backup.Checksum = checksum
+	backup.Metadata = *metadata
 
 	// Count issues
 	if stats := r.scanProjectStats(projectPath); stats != nil {
@@ -516,13 +567,7 @@ func (r *FileGlobalRepository) CreateBackup(ctx context.Context, projectPath str
 	}
 
 	// Save backup metadata
-	metadataFile := filepath.Join(backupDir, "metadata.yaml")
-	data, err := yaml.Marshal(backup)
-	if err != nil {
-		return nil, errors.Wrap(err, "FileGlobalRepository.CreateBackup", "marshal_metadata")
-	}
-
-	if err := os.WriteFile(metadataFile, data, 0644); err != nil {
+	if err := r.saveBackup(backup); err != nil {
 		return nil, errors.Wrap(err, "FileGlobalRepository.CreateBackup", "write_metadata")
 	}
 
@@ -536,6 +581,12 @@ func (r *FileGlobalRepository) CreateBackup(ctx context.Context, projectPath str
 		return nil, errors.Wrap(err, "FileGlobalRepository.CreateBackup", "save_config")
 	}
 
+	pruned, err := r.pruneBackups(ctx, project.Path, settings.MaxBackupRetention)
+	if err != nil {
+		return nil, errors.Wrap(err, "FileGlobalRepository.CreateBackup", "prune_backups")
+	}
+	backup.Pruned = pruned
+
 	return backup, nil
 }
 
@@ -683,7 +734,9 @@ func (r *FileGlobalRepository) matchesArchiveFilter(archived *entities.ArchivedI
 	return true
 }
 
-func (r *FileGlobalRepository) createBackupArchive(projectPath string, outputPath string) error {
+// createBackupArchive writes the project's .issuemap files listed in include
+// to a gzipped tar, encrypting it when a key is given
+func (r *FileGlobalRepository) createBackupArchive(projectPath string, outputPath string, include map[string]bool, key []byte, keyID string) error {
 	sourceDir := filepath.Join(projectPath, ".issuemap")
 
 	file, err := os.Create(outputPath)
@@ -692,49 +745,70 @@ func (r *FileGlobalRepository) createBackupArchive(projectPath string, outputPat
 	}
 	defer file.Close()
 
-	gzWriter := gzip.NewWriter(file)
-	defer gzWriter.Close()
+	var output io.WriteCloser = file
+	if key != nil {
+		if output, err = newEncryptWriter(file, key, keyID); err != nil {
+			return err
+		}
+	}
 
+	gzWriter := gzip.NewWriter(output)
 	tarWriter := tar.NewWriter(gzWriter)
-	defer tarWriter.Close()
 
-	return filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
+	err = filepath.Walk(sourceDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
-			return nil // Skip files with errors
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
 		}
 
-		// Create tar header
-		header, err := tar.FileInfoHeader(info, "")
+		// Set name relative to source directory
+		relPath, err := filepath.Rel(sourceDir, path)
 		if err != nil {
 			return err
 		}
+		relPath = filepath.ToSlash(relPath)
+		if !include[relPath] {
+			return nil
+		}
 
-		// Set name relative to source directory
-		relPath, err := filepath.Rel(sourceDir, path)
+		// Create tar header
+		header, err := tar.FileInfoHeader(info, "")
 		if err != nil {
 			return err
 		}
 		header.Name = relPath
 
-		// Write header
 		if err := tarWriter.WriteHeader(header); err != nil {
 			return err
 		}
 
-		// Write file content if it's a regular file
-		if info.Mode().IsRegular() {
-			data, err := os.ReadFile(path)
-			if err != nil {
-				return err
-			}
-
-			if _, err := tarWriter.Write(data); err != nil {
-				return err
-			}
+		source, err := os.Open(path)
+		if err != nil {
+			return err
 		}
+		defer source.Close()
 
-		return nil
+		_, err = io.Copy(tarWriter, source)
+		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	if err := gzWriter.Close(); err != nil {
+		return err
+	}
+	if output != file {
+		if err := output.Close(); err != nil {
+			return err
+		}
+	}
+	return file.Close()
 }
 
 func (r *FileGlobalRepository) calculateChecksum(filePath string) (string, error) {
@@ -747,29 +821,560 @@ func (r *FileGlobalRepository) calculateChecksum(filePath string) (string, error
 	return fmt.Sprintf("%x", hash), nil
 }
 
-// Placeholder implementations for remaining interface methods
-// These would need full implementation based on specific requirements
-
+// ListBackups lists backups matching the filter, newest first
 func (r *FileGlobalRepository) ListBackups(ctx context.Context, filter repositories.BackupFilter) ([]*entities.ProjectBackup, error) {
-	// Implementation would scan backup directory and filter results
-	return nil, nil
+	entries, err := os.ReadDir(entities.GetBackupPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*entities.ProjectBackup{}, nil
+		}
+		return nil, errors.Wrap(err, "FileGlobalRepository.ListBackups", "read_dir")
+	}
+
+	backups := []*entities.ProjectBackup{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		backup, err := r.loadBackup(entry.Name())
+		if err != nil {
+			continue // Skip incomplete backups
+		}
+		if r.matchesBackupFilter(backup, filter) {
+			backups = append(backups, backup)
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	if filter.Offset != nil && *filter.Offset > 0 {
+		if *filter.Offset >= len(backups) {
+			return []*entities.ProjectBackup{}, nil
+		}
+		backups = backups[*filter.Offset:]
+	}
+	if filter.Limit != nil && *filter.Limit > 0 && *filter.Limit < len(backups) {
+		backups = backups[:*filter.Limit]
+	}
+
+	return backups, nil
 }
 
+// GetBackup loads a backup's metadata by ID
 func (r *FileGlobalRepository) GetBackup(ctx context.Context, backupID string) (*entities.ProjectBackup, error) {
-	// Implementation would load backup metadata by ID
-	return nil, nil
+	if backupID == "" || strings.ContainsAny(backupID, `/\`) || strings.HasPrefix(backupID, ".") {
+		return nil, errors.Wrap(errors.ErrInvalidInput, "FileGlobalRepository.GetBackup", "invalid_id")
+	}
+
+	backup, err := r.loadBackup(backupID)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("FileGlobalRepository.GetBackup", "not_found",
+				fmt.Errorf("backup %s %w", backupID, errors.ErrNotFound))
+		}
+		return nil, errors.Wrap(err, "FileGlobalRepository.GetBackup", "load_metadata")
+	}
+
+	return backup, nil
 }
 
+// RestoreBackup restores a backup's .issuemap contents into targetPath,
+// reading unchanged files of incremental backups from earlier archives
 func (r *FileGlobalRepository) RestoreBackup(ctx context.Context, backupID string, targetPath string) error {
-	// Implementation would extract backup archive to target path
+	backup, err := r.GetBackup(ctx, backupID)
+	if err != nil {
+		return errors.Wrap(err, "FileGlobalRepository.RestoreBackup", "get_backup")
+	}
+
+	if err := r.restoreBackup(ctx, backup, targetPath); err != nil {
+		return errors.Wrap(err, "FileGlobalRepository.RestoreBackup", "restore")
+	}
+
 	return nil
 }
 
+// DeleteBackup removes a backup. Backups whose archives are still needed by
+// a later incremental backup cannot be deleted.
 func (r *FileGlobalRepository) DeleteBackup(ctx context.Context, backupID string) error {
-	// Implementation would remove backup directory
+	backup, err := r.GetBackup(ctx, backupID)
+	if err != nil {
+		return errors.Wrap(err, "FileGlobalRepository.DeleteBackup", "get_backup")
+	}
+
+	projectPath := backup.ProjectPath
+	backups, err := r.ListBackups(ctx, repositories.BackupFilter{ProjectPath: &projectPath})
+	if err != nil {
+		return errors.Wrap(err, "FileGlobalRepository.DeleteBackup", "list_backups")
+	}
+	for _, other := range backups {
+		for _, id := range other.ReferencedBackups() {
+			if id == backupID {
+				return errors.New("FileGlobalRepository.DeleteBackup", "backup_in_use",
+					fmt.Errorf("backup %s is needed to restore backup %s", backupID, other.ID))
+			}
+		}
+	}
+
+	if err := os.RemoveAll(filepath.Join(entities.GetBackupPath(), backupID)); err != nil {
+		return errors.Wrap(err, "FileGlobalRepository.DeleteBackup", "remove_dir")
+	}
+
+	return nil
+}
+
+// VerifyBackup test-restores a backup into a temporary directory, checks the
+// restored files against the backup's manifest and archive checksums, and
+// diffs them against the current project source
+func (r *FileGlobalRepository) VerifyBackup(ctx context.Context, backupID string) (*entities.BackupVerifyResult, error) {
+	backup, err := r.GetBackup(ctx, backupID)
+	if err != nil {
+		return nil, errors.Wrap(err, "FileGlobalRepository.VerifyBackup", "get_backup")
+	}
+
+	result := &entities.BackupVerifyResult{
+		BackupID:    backup.ID,
+		ProjectPath: backup.ProjectPath,
+		VerifiedAt:  time.Now(),
+	}
+
+	// Check the archives the backup needs against their recorded checksums
+	archives := []*entities.ProjectBackup{backup}
+	for _, id := range backup.ReferencedBackups() {
+		referenced, err := r.GetBackup(ctx, id)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("referenced backup %s is missing", id))
+			continue
+		}
+		archives = append(archives, referenced)
+	}
+	for _, archive := range archives {
+		checksum, err := r.calculateChecksum(archive.BackupPath)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("archive of backup %s is unreadable: %v", archive.ID, err))
+		} else if checksum != archive.Checksum {
+			result.Errors = append(result.Errors, fmt.Sprintf("archive of backup %s does not match its checksum", archive.ID))
+		}
+	}
+
+	tempDir, err := os.MkdirTemp("", "issuemap-verify-")
+	if err != nil {
+		return nil, errors.Wrap(err, "FileGlobalRepository.VerifyBackup", "create_temp_dir")
+	}
+	defer os.RemoveAll(tempDir)
+
+	if err := r.restoreBackup(ctx, backup, tempDir); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("test restore failed: %v", err))
+	}
+
+	restored, err := hashBackupFiles(tempDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "FileGlobalRepository.VerifyBackup", "hash_restored")
+	}
+	restoredHashes := backupFileHashes(restored)
+
+	if len(backup.Files) > 0 {
+		for _, file := range backup.Files {
+			result.FilesChecked++
+			hash, ok := restoredHashes[file.Path]
+			switch {
+			case !ok:
+				result.Missing = append(result.Missing, file.Path)
+			case hash != file.Hash:
+				result.Corrupted = append(result.Corrupted, file.Path)
+			}
+		}
+	} else {
+		// Backups created before manifests were recorded can only be
+		// checked for a successful restore
+		result.FilesChecked = len(restored)
+	}
+
+	// Diff against the project source
+	source, err := hashBackupFiles(filepath.Join(backup.ProjectPath, ".issuemap"))
+	if err != nil && !os.IsNotExist(err) {
+		result.Errors = append(result.Errors, fmt.Sprintf("failed to read project source: %v", err))
+	} else if err == nil {
+		sourceHashes := backupFileHashes(source)
+		for _, file := range source {
+			hash, ok := restoredHashes[file.Path]
+			if !ok {
+				result.Added = append(result.Added, file.Path)
+			} else if hash != file.Hash {
+				result.Modified = append(result.Modified, file.Path)
+			}
+		}
+		for _, file := range restored {
+			if _, ok := sourceHashes[file.Path]; !ok {
+				result.Removed = append(result.Removed, file.Path)
+			}
+		}
+	}
+
+	result.Valid = len(result.Missing) == 0 && len(result.Corrupted) == 0 && len(result.Errors) == 0
+	return result, nil
+}
+
+// RotateBackupKey generates a new backup encryption key and makes it the
+// active key. Earlier keys stay in the keyring for restoring old backups.
+func (r *FileGlobalRepository) RotateBackupKey(ctx context.Context) (string, error) {
+	config, err := r.GetConfig(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "FileGlobalRepository.RotateBackupKey", "get_config")
+	}
+
+	keyID, err := r.generateBackupKey(ctx, config)
+	if err != nil {
+		return "", errors.Wrap(err, "FileGlobalRepository.RotateBackupKey", "generate_key")
+	}
+
+	return keyID, nil
+}
+
+// activeBackupKey returns the key new backups are sealed with, generating
+// one the first time encryption is used
+func (r *FileGlobalRepository) activeBackupKey(ctx context.Context, config *entities.GlobalConfig) (string, []byte, error) {
+	keyring := NewBackupKeyring(entities.GetBackupKeysPath())
+
+	keyID := config.GlobalSettings.BackupKeyID
+	if keyID == "" {
+		var err error
+		if keyID, err = r.generateBackupKey(ctx, config); err != nil {
+			return "", nil, err
+		}
+	}
+
+	key, err := keyring.Key(keyID)
+	if err != nil {
+		return "", nil, err
+	}
+	return keyID, key, nil
+}
+
+func (r *FileGlobalRepository) generateBackupKey(ctx context.Context, config *entities.GlobalConfig) (string, error) {
+	keyID, err := NewBackupKeyring(entities.GetBackupKeysPath()).Generate()
+	if err != nil {
+		return "", err
+	}
+
+	config.GlobalSettings.BackupKeyID = keyID
+	if err := r.SaveConfig(ctx, config); err != nil {
+		return "", err
+	}
+	return keyID, nil
+}
+
+// latestBackup returns the most recent backup of a project, if any
+func (r *FileGlobalRepository) latestBackup(ctx context.Context, projectPath string) *entities.ProjectBackup {
+	limit := 1
+	backups, err := r.ListBackups(ctx, repositories.BackupFilter{ProjectPath: &projectPath, Limit: &limit})
+	if err != nil || len(backups) == 0 {
+		return nil
+	}
+	return backups[0]
+}
+
+// canIncrementFrom reports whether a new backup can reference the archives
+// of base: base must have a manifest, be sealed the same way and every
+// archive it references must still exist
+func (r *FileGlobalRepository) canIncrementFrom(ctx context.Context, base, backup *entities.ProjectBackup) bool {
+	if len(base.Files) == 0 || base.Encrypted != backup.Encrypted || base.KeyID != backup.KeyID {
+		return false
+	}
+	for _, id := range append(base.ReferencedBackups(), base.ID) {
+		referenced, err := r.GetBackup(ctx, id)
+		if err != nil {
+			return false
+		}
+		if _, err := os.Stat(referenced.BackupPath); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// pruneBackups removes a project's oldest backups beyond the retention
+// limit. Archives still referenced by a kept incremental backup are left in
+// place until no kept backup needs them.
+func (r *FileGlobalRepository) pruneBackups(ctx context.Context, projectPath string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+
+	backups, err := r.ListBackups(ctx, repositories.BackupFilter{ProjectPath: &projectPath})
+	if err != nil {
+		return nil, err
+	}
+	if len(backups) <= keep {
+		return nil, nil
+	}
+
+	needed := make(map[string]bool)
+	for _, backup := range backups[:keep] {
+		for _, id := range backup.ReferencedBackups() {
+			needed[id] = true
+		}
+	}
+
+	var pruned []string
+	for _, backup := range backups[keep:] {
+		if needed[backup.ID] {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(entities.GetBackupPath(), backup.ID)); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, backup.ID)
+	}
+
+	return pruned, nil
+}
+
+// newBackupID returns an unused backup ID for a project
+func (r *FileGlobalRepository) newBackupID(projectName string) string {
+	backupID := fmt.Sprintf("%s-%d", projectName, time.Now().Unix())
+	candidate := backupID
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(entities.GetBackupPath(), candidate)); os.IsNotExist(err) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d", backupID, i)
+	}
+}
+
+func (r *FileGlobalRepository) loadBackup(backupID string) (*entities.ProjectBackup, error) {
+	data, err := os.ReadFile(filepath.Join(entities.GetBackupPath(), backupID, "metadata.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	var backup entities.ProjectBackup
+	if err := yaml.Unmarshal(data, &backup); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+func (r *FileGlobalRepository) saveBackup(backup *entities.ProjectBackup) error {
+	data, err := yaml.Marshal(backup)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(entities.GetBackupPath(), backup.ID, "metadata.yaml"), data, 0644)
+}
+
+func (r *FileGlobalRepository) matchesBackupFilter(backup *entities.ProjectBackup, filter repositories.BackupFilter) bool {
+	if filter.ProjectPath != nil {
+		absPath, _ := filepath.Abs(*filter.ProjectPath)
+		if backup.ProjectPath != absPath {
+			return false
+		}
+	}
+
+	if filter.CreatedSince != nil && backup.CreatedAt.Before(*filter.CreatedSince) {
+		return false
+	}
+
+	if filter.CreatedBy != nil && backup.Metadata.CreatedByUser != *filter.CreatedBy {
+		return false
+	}
+
+	if filter.MinSize != nil && backup.Size < *filter.MinSize {
+		return false
+	}
+
+	if filter.MaxSize != nil && backup.Size > *filter.MaxSize {
+		return false
+	}
+
+	for _, tag := range filter.Tags {
+		found := false
+		for _, backupTag := range backup.Tags {
+			if backupTag == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// restoreBackup extracts a backup into targetPath. Files of incremental
+// backups are read from the archive recorded in the manifest; backups
+// without a manifest are extracted whole.
+func (r *FileGlobalRepository) restoreBackup(ctx context.Context, backup *entities.ProjectBackup, targetPath string) error {
+	if err := os.MkdirAll(targetPath, 0755); err != nil {
+		return err
+	}
+
+	if len(backup.Files) == 0 {
+		return r.extractBackupArchive(backup, targetPath, nil)
+	}
+
+	wanted := make(map[string]map[string]bool)
+	for _, file := range backup.Files {
+		if wanted[file.BackupID] == nil {
+			wanted[file.BackupID] = make(map[string]bool)
+		}
+		wanted[file.BackupID][file.Path] = true
+	}
+
+	ids := make([]string, 0, len(wanted))
+	for id := range wanted {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		source := backup
+		if id != backup.ID {
+			var err error
+			if source, err = r.GetBackup(ctx, id); err != nil {
+				return fmt.Errorf("referenced backup %s is missing: %w", id, err)
+			}
+		}
+		if err := r.extractBackupArchive(source, targetPath, wanted[id]); err != nil {
+			return fmt.Errorf("failed to extract backup %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// extractBackupArchive extracts the regular files of a backup archive whose
+// paths are in include, or every file when include is nil
+func (r *FileGlobalRepository) extractBackupArchive(backup *entities.ProjectBackup, targetPath string, include map[string]bool) error {
+	file, err := os.Open(backup.BackupPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if backup.Encrypted {
+		reader, err = newDecryptReader(file, NewBackupKeyring(entities.GetBackupKeysPath()).Key)
+		if err != nil {
+			return err
+		}
+	}
+
+	gzReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return err
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := filepath.ToSlash(filepath.Clean(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("backup contains unsafe path %q", header.Name)
+		}
+		if include != nil && !include[name] {
+			continue
+		}
+
+		outputPath := filepath.Join(targetPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return err
+		}
+
+		output, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		_, copyErr := io.Copy(output, tarReader)
+		closeErr := output.Close()
+		if copyErr != nil {
+			return copyErr
+		}
+		if closeErr != nil {
+			return closeErr
+		}
+	}
+
 	return nil
 }
 
+// hashBackupFiles returns the regular files under dir with their SHA-256
+// hashes and slash-separated relative paths
+func hashBackupFiles(dir string) ([]entities.BackupFile, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	var files []entities.BackupFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		hash, err := hashFile(path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, entities.BackupFile{
+			Path: filepath.ToSlash(relPath),
+			Hash: hash,
+			Size: info.Size(),
+		})
+		return nil
+	})
+
+	return files, err
+}
+
+func backupFileHashes(files []entities.BackupFile) map[string]string {
+	hashes := make(map[string]string, len(files))
+	for _, file := range files {
+		hashes[file.Path] = file.Hash
+	}
+	return hashes
+}
+
+func hashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Placeholder implementations for remaining interface methods
+// These would need full implementation based on specific requirements
+
 func (r *FileGlobalRepository) GlobalListIssues(ctx context.Context, filter repositories.GlobalIssueFilter) (*repositories.GlobalIssueList, error) {
 	// Implementation would aggregate issues from all projects
 	return nil, nil
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/repositories"
+)
+
+func TestBackupEncryption_RoundTrip(t *testing.T) {
+	keyring := NewBackupKeyring(t.TempDir())
+	keyID, err := keyring.Generate()
+	require.NoError(t, err)
+	key, err := keyring.Key(keyID)
+	require.NoError(t, err)
+
+	sizes := []int{0, 1, cipherChunkSize - 1, cipherChunkSize, 3*cipherChunkSize + 17}
+	for _, size := range sizes {
+		plain := make([]byte, size)
+		_, err := rand.Read(plain)
+		require.NoError(t, err)
+
+		var sealed bytes.Buffer
+		writer, err := newEncryptWriter(&sealed, key, keyID)
+		require.NoError(t, err)
+		_, err = writer.Write(plain)
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		reader, err := newDecryptReader(bytes.NewReader(sealed.Bytes()), keyring.Key)
+		require.NoError(t, err)
+		opened, err := io.ReadAll(reader)
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, plain, opened, "size %d", size)
+	}
+}
+
+func TestBackupEncryption_DetectsTampering(t *testing.T) {
+	keyring := NewBackupKeyring(t.TempDir())
+	keyID, err := keyring.Generate()
+	require.NoError(t, err)
+	key, err := keyring.Key(keyID)
+	require.NoError(t, err)
+
+	plain := bytes.Repeat([]byte("issuemap backup "), cipherChunkSize/4)
+	var sealed bytes.Buffer
+	writer, err := newEncryptWriter(&sealed, key, keyID)
+	require.NoError(t, err)
+	_, err = writer.Write(plain)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	open := func(data []byte) error {
+		reader, err := newDecryptReader(bytes.NewReader(data), keyring.Key)
+		if err != nil {
+			return err
+		}
+		_, err = io.ReadAll(reader)
+		return err
+	}
+
+	flipped := bytes.Clone(sealed.Bytes())
+	flipped[len(flipped)/2] ^= 0x01
+	assert.Error(t, open(flipped), "flipped byte")
+
+	// Dropping the final chunk must not look like a shorter backup
+	truncated := sealed.Bytes()[:len(sealed.Bytes())-100]
+	assert.Error(t, open(truncated), "truncated")
+
+	appended := append(bytes.Clone(sealed.Bytes()), 0x00)
+	assert.Error(t, open(appended), "appended data")
+
+	otherKeyring := NewBackupKeyring(t.TempDir())
+	_, err = newDecryptReader(bytes.NewReader(sealed.Bytes()), otherKeyring.Key)
+	assert.Error(t, err, "missing key")
+}
+
+// newBackupTestProject creates a registered project with a few files and an
+// isolated global directory
+func newBackupTestProject(t *testing.T) (*FileGlobalRepository, string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	repo := NewFileGlobalRepository()
+	require.NoError(t, repo.InitializeGlobal(context.Background()))
+
+	projectPath := t.TempDir()
+	writeProjectFile(t, projectPath, "issues/TEST-001.yaml", "title: first\n")
+	writeProjectFile(t, projectPath, "issues/TEST-002.yaml", "title: second\n")
+	writeProjectFile(t, projectPath, "config.yaml", "project:\n  name: test\n")
+
+	_, err := repo.RegisterProject(context.Background(), projectPath, "test")
+	require.NoError(t, err)
+	return repo, projectPath
+}
+
+func writeProjectFile(t *testing.T, projectPath, name, content string) {
+	t.Helper()
+	path := filepath.Join(projectPath, ".issuemap", filepath.FromSlash(name))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestFileGlobalRepository_IncrementalEncryptedBackups(t *testing.T) {
+	ctx := context.Background()
+	repo, projectPath := newBackupTestProject(t)
+
+	config, err := repo.GetConfig(ctx)
+	require.NoError(t, err)
+	config.GlobalSettings.EncryptionEnabled = true
+	require.NoError(t, repo.SaveConfig(ctx, config))
+
+	full, err := repo.CreateBackup(ctx, projectPath, nil)
+	require.NoError(t, err)
+	assert.False(t, full.Incremental)
+	assert.True(t, full.Encrypted)
+	assert.Equal(t, 3, full.StoredFiles)
+
+	// The archive must not contain the plaintext
+	sealed, err := os.ReadFile(full.BackupPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "title: first")
+
+	writeProjectFile(t, projectPath, "issues/TEST-002.yaml", "title: second, edited\n")
+	writeProjectFile(t, projectPath, "issues/TEST-003.yaml", "title: third\n")
+
+	incremental, err := repo.CreateBackup(ctx, projectPath, nil)
+	require.NoError(t, err)
+	assert.True(t, incremental.Incremental)
+	assert.Equal(t, full.ID, incremental.BaseBackupID)
+	assert.Equal(t, 2, incremental.StoredFiles)
+	assert.Len(t, incremental.Files, 4)
+	assert.Equal(t, []string{full.ID}, incremental.ReferencedBackups())
+
+	// Restoring the increment pulls unchanged files from the full backup
+	target := t.TempDir()
+	require.NoError(t, repo.RestoreBackup(ctx, incremental.ID, target))
+	data, err := os.ReadFile(filepath.Join(target, "issues", "TEST-001.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "title: first\n", string(data))
+	data, err = os.ReadFile(filepath.Join(target, "issues", "TEST-002.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "title: second, edited\n", string(data))
+
+	result, err := repo.VerifyBackup(ctx, incremental.ID)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "errors: %v", result.Errors)
+	assert.True(t, result.InSync())
+	assert.Equal(t, 4, result.FilesChecked)
+
+	// Later changes show up as drift, not as corruption
+	writeProjectFile(t, projectPath, "issues/TEST-001.yaml", "title: first, edited\n")
+	result, err = repo.VerifyBackup(ctx, incremental.ID)
+	require.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, []string{"issues/TEST-001.yaml"}, result.Modified)
+
+	// The base backup is still needed by the increment
+	assert.Error(t, repo.DeleteBackup(ctx, full.ID))
+}
+
+func TestFileGlobalRepository_VerifyDetectsCorruption(t *testing.T) {
+	ctx := context.Background()
+	repo, projectPath := newBackupTestProject(t)
+
+	backup, err := repo.CreateBackup(ctx, projectPath, nil)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(backup.BackupPath)
+	require.NoError(t, err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(backup.BackupPath, data, 0644))
+
+	result, err := repo.VerifyBackup(ctx, backup.ID)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.NotEmpty(t, result.Errors)
+}
+
+func TestFileGlobalRepository_BackupRetention(t *testing.T) {
+	ctx := context.Background()
+	repo, projectPath := newBackupTestProject(t)
+
+	config, err := repo.GetConfig(ctx)
+	require.NoError(t, err)
+	config.GlobalSettings.MaxBackupRetention = 2
+	require.NoError(t, repo.SaveConfig(ctx, config))
+
+	first, err := repo.CreateBackup(ctx, projectPath, nil)
+	require.NoError(t, err)
+
+	// Every file changes, so the second backup no longer needs the first
+	writeProjectFile(t, projectPath, "issues/TEST-001.yaml", "title: first, edited\n")
+	writeProjectFile(t, projectPath, "issues/TEST-002.yaml", "title: second, edited\n")
+	writeProjectFile(t, projectPath, "config.yaml", "project:\n  name: renamed\n")
+	second, err := repo.CreateBackup(ctx, projectPath, nil)
+	require.NoError(t, err)
+	assert.Empty(t, second.ReferencedBackups())
+
+	third, err := repo.CreateBackup(ctx, projectPath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{first.ID}, third.Pruned)
+
+	backups, err := repo.ListBackups(ctx, repositories.BackupFilter{ProjectPath: &projectPath})
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, third.ID, backups[0].ID)
+	assert.Equal(t, second.ID, backups[1].ID)
+
+	// A fourth backup would prune the second, but the kept third backup
+	// still restores every file from the second's archive
+	fourth, err := repo.CreateBackup(ctx, projectPath, nil)
+	require.NoError(t, err)
+	assert.Empty(t, fourth.Pruned)
+	_, err = repo.GetBackup(ctx, second.ID)
+	assert.NoError(t, err)
+}
@@ -38,6 +38,8 @@ func (r *FileHistoryRepository) CreateHistory(ctx context.Context, history *enti
 
 	filePath := filepath.Join(historyDir, fmt.Sprintf("%s.yaml", history.IssueID))
 
+	history.SchemaVersion = entities.CurrentSchemaVersion(entities.SchemaKindHistory)
+	history.RecordSealedFrom()
 	data, err := yaml.Marshal(history)
 	if err != nil {
 		return errors.Wrap(err, "FileHistoryRepository.CreateHistory", "marshal")
@@ -64,7 +66,7 @@ func (r *FileHistoryRepository) GetHistory(ctx context.Context, issueID entities
 	}
 
 	var history entities.IssueHistory
-	if err := yaml.Unmarshal(data, &history); err != nil {
+	if err := unmarshalVersioned(entities.SchemaKindHistory, data, &history); err != nil {
 		return nil, errors.Wrap(err, "FileHistoryRepository.GetHistory", "unmarshal")
 	}
 
@@ -111,7 +113,7 @@ func (r *FileHistoryRepository) GetEntry(ctx context.Context, entryID string) (*
 		}
 
 		var history entities.IssueHistory
-		if err := yaml.Unmarshal(data, &history); err != nil {
+		if err := unmarshalVersioned(entities.SchemaKindHistory, data, &history); err != nil {
 			continue
 		}
 
@@ -216,7 +218,7 @@ func (r *FileHistoryRepository) GetAllHistory(ctx context.Context, filter reposi
 		}
 
 		var history entities.IssueHistory
-		if err := yaml.Unmarshal(data, &history); err != nil {
+		if err := unmarshalVersioned(entities.SchemaKindHistory, data, &history); err != nil {
 			continue // Skip files that can't be parsed
 		}
 
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -22,6 +23,9 @@ import (
 // FileIssueRepository implements the IssueRepository interface using file storage
 type FileIssueRepository struct {
 	basePath string
+
+	identityOnce sync.Once
+	identity     *ConfidentialIdentity
 }
 
 // NewFileIssueRepository creates a new file-based issue repository
@@ -50,7 +54,7 @@ func (r *FileIssueRepository) Create(ctx context.Context, issue *entities.Issue)
 		return errors.Wrap(errors.ErrIssueAlreadyExists, "FileIssueRepository.Create", "exists")
 	}
 
-	data, err := yaml.Marshal(issue)
+	data, err := r.encodeIssue(ctx, issue)
 	if err != nil {
 		return errors.Wrap(err, "FileIssueRepository.Create", "marshal")
 	}
@@ -74,12 +78,12 @@ func (r *FileIssueRepository) GetByID(ctx context.Context, id entities.IssueID)
 		return nil, errors.Wrap(err, "FileIssueRepository.GetByID", "read")
 	}
 
-	var issue entities.Issue
-	if err := yaml.Unmarshal(data, &issue); err != nil {
+	issue, err := r.decodeIssue(data)
+	if err != nil {
 		return nil, errors.Wrap(err, "FileIssueRepository.GetByID", "unmarshal")
 	}
 
-	return &issue, nil
+	return issue, nil
 }
 
 // List retrieves issues based on filter criteria
@@ -106,13 +110,13 @@ func (r *FileIssueRepository) List(ctx context.Context, filter repositories.Issu
 			continue // Skip files that can't be read
 		}
 
-		var issue entities.Issue
-		if err := yaml.Unmarshal(data, &issue); err != nil {
+		issue, err := r.decodeIssue(data)
+		if err != nil {
 			continue // Skip files that can't be parsed
 		}
 
-		if r.matchesFilter(&issue, filter) {
-			allIssues = append(allIssues, issue)
+		if r.matchesFilter(issue, filter) {
+			allIssues = append(allIssues, *issue)
 		}
 	}
 
@@ -169,7 +173,7 @@ func (r *FileIssueRepository) Update(ctx context.Context, issue *entities.Issue)
 	// Update the timestamp
 	issue.Timestamps.Updated = time.Now()
 
-	data, err := yaml.Marshal(issue)
+	data, err := r.encodeIssue(ctx, issue)
 	if err != nil {
 		return errors.Wrap(err, "FileIssueRepository.Update", "marshal")
 	}
@@ -181,6 +185,62 @@ func (r *FileIssueRepository) Update(ctx context.Context, issue *entities.Issue)
 	return nil
 }
 
+// encodeIssue marshals an issue for storage. Confidential issues are sealed to
+// the configured recipients; redacted issues keep their existing sealed
+// content and cannot have it changed.
+func (r *FileIssueRepository) encodeIssue(ctx context.Context, issue *entities.Issue) ([]byte, error) {
+	stored := *issue
+	switch {
+	case issue.Redacted:
+		if !issue.Confidential || issue.Sealed == nil || issue.SealedContentChanged() {
+			return nil, errors.New("FileIssueRepository.encodeIssue", "confidential",
+				fmt.Errorf("%w: %s is confidential and cannot be decrypted with this identity", errors.ErrPermissionDenied, issue.ID))
+		}
+		stored.ClearSealedFields(issue.Sealed.Fields)
+	case issue.Confidential:
+		config, err := NewFileConfigRepository(r.basePath).Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sealed, err := SealIssue(issue, config.Confidential)
+		if err != nil {
+			return nil, fmt.Errorf("seal %s: %w", issue.ID, err)
+		}
+		stored = *sealed
+	default:
+		stored.Sealed = nil
+	}
+
+	stored.SchemaVersion = entities.CurrentSchemaVersion(entities.SchemaKindIssue)
+	return yaml.Marshal(&stored)
+}
+
+// decodeIssue unmarshals a stored issue, decrypting confidential content when
+// the local identity is a recipient and redacting it otherwise
+func (r *FileIssueRepository) decodeIssue(data []byte) (*entities.Issue, error) {
+	var issue entities.Issue
+	if err := unmarshalVersioned(entities.SchemaKindIssue, data, &issue); err != nil {
+		return nil, err
+	}
+
+	if issue.Sealed != nil {
+		if err := OpenIssue(&issue, r.loadIdentity()); err != nil {
+			issue.Redact()
+		}
+	}
+	return &issue, nil
+}
+
+// loadIdentity returns the local confidential identity, if there is one
+func (r *FileIssueRepository) loadIdentity() *ConfidentialIdentity {
+	r.identityOnce.Do(func() {
+		if identity, err := LoadConfidentialIdentity(entities.GetIdentityPath()); err == nil {
+			r.identity = identity
+		}
+	})
+	return r.identity
+}
+
 // Delete removes an issue
 func (r *FileIssueRepository) Delete(ctx context.Context, id entities.IssueID) error {
 	filePath := filepath.Join(r.basePath, "issues", fmt.Sprintf("%s.yaml", id))
@@ -411,6 +471,26 @@ func (r *FileIssueRepository) matchesFilter(issue *entities.Issue, filter reposi
 		}
 	}
 
+	if len(filter.Assignees) > 0 {
+		if issue.Assignee == nil {
+			return false
+		}
+		found := false
+		for _, assignee := range filter.Assignees {
+			if issue.Assignee.Username == assignee {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filter.Watcher != nil && !issue.IsWatching(*filter.Watcher) {
+		return false
+	}
+
 	if filter.Branch != nil && issue.Branch != *filter.Branch {
 		return false
 	}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+const (
+	jobsDir         = "jobs"
+	jobStateFile    = "state.yaml"
+	jobHistoryFile  = "history.yaml"
+	jobsIgnoreRules = "# Job state is local to this checkout\n*\n"
+)
+
+// FileJobRepository implements the JobRepository interface using file storage.
+// State and history are local to the checkout and are kept out of git.
+type FileJobRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+// NewFileJobRepository creates a new file-based job repository
+func NewFileJobRepository(basePath string) *FileJobRepository {
+	return &FileJobRepository{
+		basePath: basePath,
+	}
+}
+
+// LoadStates returns the saved state of every job
+func (r *FileJobRepository) LoadStates(ctx context.Context) (map[string]*entities.JobState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.loadStates()
+}
+
+// SaveState saves the state of a single job, preserving the others
+func (r *FileJobRepository) SaveState(ctx context.Context, state *entities.JobState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	states, err := r.loadStates()
+	if err != nil {
+		return err
+	}
+	states[state.Name] = state
+
+	return r.write(jobStateFile, states)
+}
+
+// AppendRun records a finished run, dropping the oldest runs beyond limit
+func (r *FileJobRepository) AppendRun(ctx context.Context, run *entities.JobRun, limit int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runs, err := r.loadRuns()
+	if err != nil {
+		return err
+	}
+
+	runs = append(runs, run)
+	if limit > 0 && len(runs) > limit {
+		runs = runs[len(runs)-limit:]
+	}
+
+	return r.write(jobHistoryFile, runs)
+}
+
+// ListRuns returns the most recent runs first, optionally filtered by job
+func (r *FileJobRepository) ListRuns(ctx context.Context, job string, limit int) ([]*entities.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	runs, err := r.loadRuns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*entities.JobRun
+	for i := len(runs) - 1; i >= 0; i-- {
+		if job != "" && runs[i].Job != job {
+			continue
+		}
+		result = append(result, runs[i])
+		if limit > 0 && len(result) >= limit {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+func (r *FileJobRepository) loadStates() (map[string]*entities.JobState, error) {
+	states := make(map[string]*entities.JobState)
+	if err := r.read(jobStateFile, &states); err != nil {
+		return nil, err
+	}
+	if states == nil {
+		states = make(map[string]*entities.JobState)
+	}
+	for name, state := range states {
+		state.Name = name
+	}
+	return states, nil
+}
+
+func (r *FileJobRepository) loadRuns() ([]*entities.JobRun, error) {
+	var runs []*entities.JobRun
+	if err := r.read(jobHistoryFile, &runs); err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (r *FileJobRepository) read(name string, out interface{}) error {
+	data, err := os.ReadFile(filepath.Join(r.basePath, jobsDir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if err := yaml.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %w", name, err)
+	}
+	return nil
+}
+
+func (r *FileJobRepository) write(name string, value interface{}) error {
+	dir := filepath.Join(r.basePath, jobsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create jobs directory: %w", err)
+	}
+
+	ignorePath := filepath.Join(dir, ".gitignore")
+	if _, err := os.Stat(ignorePath); os.IsNotExist(err) {
+		os.WriteFile(ignorePath, []byte(jobsIgnoreRules), 0644)
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	// Write through a temporary file so a crash never leaves a torn file
+	tmp := filepath.Join(dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
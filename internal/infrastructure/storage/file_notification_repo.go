@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+const (
+	notificationsDir = "notifications"
+
+	// notificationLimit is the number of notifications kept per recipient;
+	// the oldest are dropped beyond it
+	notificationLimit = 500
+)
+
+var unsafeRecipientChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// FileNotificationRepository implements the NotificationRepository interface
+// with one file per recipient, so that notifications travel with the
+// repository like the issues they are about.
+type FileNotificationRepository struct {
+	basePath string
+	mu       sync.Mutex
+}
+
+// NewFileNotificationRepository creates a new file-based notification repository
+func NewFileNotificationRepository(basePath string) *FileNotificationRepository {
+	return &FileNotificationRepository{
+		basePath: basePath,
+	}
+}
+
+// Add appends notifications to the inbox of each recipient
+func (r *FileNotificationRepository) Add(ctx context.Context, notifications ...*entities.IssueNotification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byRecipient := make(map[string][]*entities.IssueNotification)
+	var order []string
+	for _, notification := range notifications {
+		file := r.recipientFile(notification.Recipient)
+		if _, ok := byRecipient[file]; !ok {
+			order = append(order, file)
+		}
+		byRecipient[file] = append(byRecipient[file], notification)
+	}
+
+	for _, file := range order {
+		inbox, err := r.load(file)
+		if err != nil {
+			return err
+		}
+		inbox = append(inbox, byRecipient[file]...)
+		if len(inbox) > notificationLimit {
+			inbox = inbox[len(inbox)-notificationLimit:]
+		}
+		if err := r.write(file, inbox); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the notifications of a recipient, newest first
+func (r *FileNotificationRepository) List(ctx context.Context, recipient string, unreadOnly bool) ([]*entities.IssueNotification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inbox, err := r.load(r.recipientFile(recipient))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*entities.IssueNotification
+	for i := len(inbox) - 1; i >= 0; i-- {
+		if unreadOnly && inbox[i].Read {
+			continue
+		}
+		result = append(result, inbox[i])
+	}
+	return result, nil
+}
+
+// MarkRead marks the given notifications of a recipient as read, or all
+// of them when ids is empty
+func (r *FileNotificationRepository) MarkRead(ctx context.Context, recipient string, ids []string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	file := r.recipientFile(recipient)
+	inbox, err := r.load(file)
+	if err != nil {
+		return 0, err
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	marked := 0
+	for _, notification := range inbox {
+		if notification.Read || (len(ids) > 0 && !wanted[notification.ID]) {
+			continue
+		}
+		notification.Read = true
+		marked++
+	}
+	if marked == 0 {
+		return 0, nil
+	}
+	return marked, r.write(file, inbox)
+}
+
+// MarkDigested marks the given notifications of a recipient as sent in a digest
+func (r *FileNotificationRepository) MarkDigested(ctx context.Context, recipient string, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	file := r.recipientFile(recipient)
+	inbox, err := r.load(file)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	marked := false
+	for _, notification := range inbox {
+		if !notification.Digested && wanted[notification.ID] {
+			notification.Digested = true
+			marked = true
+		}
+	}
+	if !marked {
+		return nil
+	}
+	return r.write(file, inbox)
+}
+
+// recipientFile returns the inbox file name of a recipient. Handles are
+// compared without case or a leading @, like the user directory does.
+func (r *FileNotificationRepository) recipientFile(recipient string) string {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(recipient), "@"))
+	name = strings.Trim(unsafeRecipientChars.ReplaceAllString(name, "_"), "._")
+	if name == "" {
+		name = "_"
+	}
+	return name + ".yaml"
+}
+
+func (r *FileNotificationRepository) load(file string) ([]*entities.IssueNotification, error) {
+	data, err := os.ReadFile(filepath.Join(r.basePath, notificationsDir, file))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notifications: %w", err)
+	}
+
+	var inbox []*entities.IssueNotification
+	if err := yaml.Unmarshal(data, &inbox); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal notifications: %w", err)
+	}
+	return inbox, nil
+}
+
+func (r *FileNotificationRepository) write(file string, inbox []*entities.IssueNotification) error {
+	dir := filepath.Join(r.basePath, notificationsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create notifications directory: %w", err)
+	}
+
+	data, err := yaml.Marshal(inbox)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notifications: %w", err)
+	}
+
+	// Write through a temporary file so a crash never leaves a torn file
+	tmp := filepath.Join(dir, file+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write notifications: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, file)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write notifications: %w", err)
+	}
+	return nil
+}
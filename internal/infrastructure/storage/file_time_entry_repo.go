@@ -58,6 +58,7 @@ func (r *FileTimeEntryRepository) Create(ctx context.Context, entry *entities.Ti
 	filename := fmt.Sprintf("%s.yaml", entry.ID)
 	path := filepath.Join(dir, filename)
 
+	entry.SchemaVersion = entities.CurrentSchemaVersion(entities.SchemaKindTimeEntry)
 	data, err := yaml.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal time entry: %w", err)
@@ -83,7 +84,7 @@ func (r *FileTimeEntryRepository) GetByID(ctx context.Context, id string) (*enti
 	}
 
 	var entry entities.TimeEntry
-	if err := yaml.Unmarshal(data, &entry); err != nil {
+	if err := unmarshalVersioned(entities.SchemaKindTimeEntry, data, &entry); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal time entry: %w", err)
 	}
 
@@ -105,6 +106,7 @@ func (r *FileTimeEntryRepository) Update(ctx context.Context, entry *entities.Ti
 
 	entry.UpdatedAt = time.Now()
 
+	entry.SchemaVersion = entities.CurrentSchemaVersion(entities.SchemaKindTimeEntry)
 	data, err := yaml.Marshal(entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal time entry: %w", err)
@@ -156,7 +158,7 @@ func (r *FileTimeEntryRepository) List(ctx context.Context, filter repositories.
 		}
 
 		var timeEntry entities.TimeEntry
-		if err := yaml.Unmarshal(data, &timeEntry); err != nil {
+		if err := unmarshalVersioned(entities.SchemaKindTimeEntry, data, &timeEntry); err != nil {
 			continue // Skip files we can't parse
 		}
 
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+// formatVersionFile is the marker holding the format version of a
+// repository, relative to the .issuemap directory
+var formatVersionFile = filepath.Join(app.MetadataDirName, "version")
+
+// ReadFormatVersion returns the format version recorded for the .issuemap
+// directory at basePath, zero when no marker has been written yet
+func ReadFormatVersion(basePath string) (int, error) {
+	data, err := os.ReadFile(filepath.Join(basePath, formatVersionFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "ReadFormatVersion", "read")
+	}
+
+	version, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || version < 0 {
+		return 0, errors.New("ReadFormatVersion", "parse", fmt.Errorf("invalid format version %q in %s", strings.TrimSpace(string(data)), formatVersionFile))
+	}
+	return version, nil
+}
+
+// WriteFormatVersion records the format version of the .issuemap directory
+// at basePath
+func WriteFormatVersion(basePath string, version int) error {
+	path := filepath.Join(basePath, formatVersionFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "WriteFormatVersion", "mkdir")
+	}
+	if err := os.WriteFile(path, []byte(strconv.Itoa(version)+"\n"), 0644); err != nil {
+		return errors.Wrap(err, "WriteFormatVersion", "write")
+	}
+	return nil
+}
+
+// CheckFormatVersion refuses repositories migrated by a newer issuemap
+func CheckFormatVersion(basePath string) error {
+	version, err := ReadFormatVersion(basePath)
+	if err != nil {
+		return err
+	}
+	if version > entities.CurrentFormatVersion {
+		return &entities.FormatVersionError{Version: version, Supported: entities.CurrentFormatVersion}
+	}
+	return nil
+}
+
+// RewriteSchemaData decodes a stored file of kind, upgrading it when it was
+// written in an older schema version, and encodes it again stamped with
+// version. Version 0 writes the file without schema_version as issuemap did
+// before formats were versioned. Fields are written in entity order, the
+// same way the repositories save them.
+func RewriteSchemaData(kind entities.SchemaKind, data []byte, version int) ([]byte, error) {
+	value, err := newSchemaEntity(kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := unmarshalVersioned(kind, data, value); err != nil {
+		return nil, err
+	}
+	reflect.ValueOf(value).Elem().FieldByName("SchemaVersion").SetInt(int64(version))
+	return yaml.Marshal(value)
+}
+
+// SchemaVersionOfData returns the schema_version recorded in a stored file,
+// zero when it has none
+func SchemaVersionOfData(data []byte) (int, error) {
+	var header struct {
+		SchemaVersion int `yaml:"schema_version"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	return header.SchemaVersion, nil
+}
+
+// newSchemaEntity returns a pointer to the entity type a kind is stored as
+func newSchemaEntity(kind entities.SchemaKind) (interface{}, error) {
+	switch kind {
+	case entities.SchemaKindIssue:
+		return &entities.Issue{}, nil
+	case entities.SchemaKindHistory:
+		return &entities.IssueHistory{}, nil
+	case entities.SchemaKindDependency:
+		return &entities.Dependency{}, nil
+	case entities.SchemaKindTimeEntry:
+		return &entities.TimeEntry{}, nil
+	case entities.SchemaKindConfig:
+		return &entities.Config{}, nil
+	}
+	return nil, fmt.Errorf("unknown schema kind %q", kind)
+}
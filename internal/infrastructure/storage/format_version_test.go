@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+func TestFormatVersionMarker(t *testing.T) {
+	basePath := t.TempDir()
+
+	version, err := ReadFormatVersion(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, version)
+	assert.NoError(t, CheckFormatVersion(basePath))
+
+	require.NoError(t, WriteFormatVersion(basePath, entities.CurrentFormatVersion))
+	version, err = ReadFormatVersion(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CurrentFormatVersion, version)
+
+	require.NoError(t, WriteFormatVersion(basePath, entities.CurrentFormatVersion+1))
+	var versionErr *entities.FormatVersionError
+	err = CheckFormatVersion(basePath)
+	require.ErrorAs(t, err, &versionErr)
+	assert.Contains(t, err.Error(), "upgrade issuemap")
+
+	require.NoError(t, os.WriteFile(filepath.Join(basePath, formatVersionFile), []byte("two\n"), 0644))
+	_, err = ReadFormatVersion(basePath)
+	assert.Error(t, err)
+}
+
+func TestFileConfigRepository_InitializeWritesFormatVersion(t *testing.T) {
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	repo := NewFileConfigRepository(basePath)
+	require.NoError(t, repo.Initialize(context.Background(), entities.NewDefaultConfig()))
+
+	version, err := ReadFormatVersion(basePath)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CurrentFormatVersion, version)
+}
+
+func TestRewriteSchemaData(t *testing.T) {
+	data := []byte("id: TEST-001\ntitle: Legacy\ntype: bug\nstatus: open\npriority: low\n")
+
+	rewritten, err := RewriteSchemaData(entities.SchemaKindIssue, data, entities.CurrentSchemaVersion(entities.SchemaKindIssue))
+	require.NoError(t, err)
+	version, err := SchemaVersionOfData(rewritten)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CurrentSchemaVersion(entities.SchemaKindIssue), version)
+
+	unversioned, err := RewriteSchemaData(entities.SchemaKindIssue, rewritten, 0)
+	require.NoError(t, err)
+	assert.NotContains(t, string(unversioned), "schema_version")
+	assert.Contains(t, string(unversioned), "title: Legacy")
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/app"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+// gitScanCursorFile holds the cursor of 'issuemap git scan', relative to
+// the .issuemap directory
+var gitScanCursorFile = filepath.Join(app.MetadataDirName, "git_scan.yaml")
+
+// ReadGitScanCursor returns the cursor of the last commit scan of the
+// .issuemap directory at basePath, nil when no scan has run
+func ReadGitScanCursor(basePath string) (*entities.GitScanCursor, error) {
+	data, err := os.ReadFile(filepath.Join(basePath, gitScanCursorFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "ReadGitScanCursor", "read")
+	}
+
+	var cursor entities.GitScanCursor
+	if err := yaml.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.Wrap(err, "ReadGitScanCursor", "unmarshal")
+	}
+	return &cursor, nil
+}
+
+// WriteGitScanCursor records the cursor of a commit scan
+func WriteGitScanCursor(basePath string, cursor *entities.GitScanCursor) error {
+	data, err := yaml.Marshal(cursor)
+	if err != nil {
+		return errors.Wrap(err, "WriteGitScanCursor", "marshal")
+	}
+	path := filepath.Join(basePath, gitScanCursorFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrap(err, "WriteGitScanCursor", "mkdir")
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrap(err, "WriteGitScanCursor", "write")
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+const (
+	s3DefaultRegion = "us-east-1"
+	s3Service       = "s3"
+	s3Algorithm     = "AWS4-HMAC-SHA256"
+
+	// s3EmptyPayloadHash is the SHA-256 of an empty request body
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3BlobStore stores blobs in an S3-compatible object store (AWS S3, MinIO, etc.)
+// using path-style requests signed with AWS Signature Version 4.
+type S3BlobStore struct {
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	prefix    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewS3BlobStore creates an S3 blob store
+func NewS3BlobStore(config entities.S3BlobConfig) (*S3BlobStore, error) {
+	if config.Endpoint == "" || config.Bucket == "" {
+		return nil, errors.New("NewS3BlobStore", "config", fmt.Errorf("s3 endpoint and bucket are required"))
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, errors.New("NewS3BlobStore", "config", fmt.Errorf("invalid s3 endpoint %q", config.Endpoint))
+	}
+
+	store := &S3BlobStore{
+		endpoint:  endpoint,
+		bucket:    config.Bucket,
+		region:    config.Region,
+		prefix:    strings.Trim(config.Prefix, "/"),
+		accessKey: config.AccessKeyID,
+		secretKey: config.SecretAccessKey,
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		now:       time.Now,
+	}
+	if store.region == "" {
+		store.region = s3DefaultRegion
+	}
+	if store.accessKey == "" {
+		store.accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if store.secretKey == "" {
+		store.secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+
+	return store, nil
+}
+
+// SetHTTPClient overrides the HTTP client used for requests
+func (s *S3BlobStore) SetHTTPClient(client *http.Client) {
+	s.client = client
+}
+
+// Backend returns the S3 backend
+func (s *S3BlobStore) Backend() entities.BlobBackend {
+	return entities.BlobBackendS3
+}
+
+// Put uploads content as the object for key
+func (s *S3BlobStore) Put(ctx context.Context, key string, content io.Reader) (string, error) {
+	if err := validateBlobKey(key); err != nil {
+		return "", errors.Wrap(err, "S3BlobStore.Put", "security")
+	}
+
+	// The payload hash is part of the signature, so the body is spooled to a
+	// temporary file while it is hashed instead of being held in memory
+	spool, err := os.CreateTemp("", "issuemap-s3-*")
+	if err != nil {
+		return "", errors.Wrap(err, "S3BlobStore.Put", "spool")
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(spool, hasher), content)
+	if err != nil {
+		return "", errors.Wrap(err, "S3BlobStore.Put", "read")
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, "S3BlobStore.Put", "spool")
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, spool, size, hex.EncodeToString(hasher.Sum(nil)))
+	if err != nil {
+		return "", errors.Wrap(err, "S3BlobStore.Put", "request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Wrap(s3ResponseError(resp), "S3BlobStore.Put", "upload")
+	}
+
+	return entities.JoinStoragePath(entities.BlobBackendS3, key), nil
+}
+
+// Get downloads the object for key
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if err := validateBlobKey(key); err != nil {
+		return nil, errors.Wrap(err, "S3BlobStore.Get", "security")
+	}
+
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "S3BlobStore.Get", "request")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.Wrap(errors.ErrAttachmentNotFound, "S3BlobStore.Get", "not_found")
+	}
+
+	defer resp.Body.Close()
+	return nil, errors.Wrap(s3ResponseError(resp), "S3BlobStore.Get", "download")
+}
+
+// Delete removes the object for key
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	if err := validateBlobKey(key); err != nil {
+		return errors.Wrap(err, "S3BlobStore.Delete", "security")
+	}
+
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return errors.Wrap(err, "S3BlobStore.Delete", "request")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return errors.Wrap(errors.ErrAttachmentNotFound, "S3BlobStore.Delete", "not_found")
+	}
+	return errors.Wrap(s3ResponseError(resp), "S3BlobStore.Delete", "delete")
+}
+
+// Exists reports whether an object exists for key
+func (s *S3BlobStore) Exists(ctx context.Context, key string) (bool, error) {
+	if err := validateBlobKey(key); err != nil {
+		return false, errors.Wrap(err, "S3BlobStore.Exists", "security")
+	}
+
+	resp, err := s.do(ctx, http.MethodHead, key, nil, 0, s3EmptyPayloadHash)
+	if err != nil {
+		return false, errors.Wrap(err, "S3BlobStore.Exists", "request")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	return false, errors.Wrap(s3ResponseError(resp), "S3BlobStore.Exists", "head")
+}
+
+// objectURL returns the path-style URL of the object for key
+func (s *S3BlobStore) objectURL(key string) *url.URL {
+	objectKey := key
+	if s.prefix != "" {
+		objectKey = s.prefix + "/" + key
+	}
+
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.bucket + "/" + objectKey
+	// Send the path encoded exactly as it is signed
+	u.RawPath = s3URIEncode(u.Path, false)
+	u.RawQuery = ""
+	return &u
+}
+
+// do sends a signed request for the object at key. payloadHash is the hex
+// SHA-256 of the size bytes read from body.
+func (s *S3BlobStore) do(ctx context.Context, method, key string, body io.Reader, size int64, payloadHash string) (*http.Response, error) {
+	target := s.objectURL(key)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if body == nil {
+		req.Body = http.NoBody
+	}
+
+	s.sign(req, payloadHash)
+	return s.client.Do(req)
+}
+
+// sign adds AWS Signature Version 4 headers to req
+func (s *S3BlobStore) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	if s.accessKey == "" || s.secretKey == "" {
+		return
+	}
+
+	// net/http sends req.Host, not a Host header
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	canonicalURI := s3URIEncode(req.URL.Path, false)
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := fmt.Sprintf("host:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+		host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, s.region, s3Service)
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	signingKey = hmacSHA256(signingKey, s.region)
+	signingKey = hmacSHA256(signingKey, s3Service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.accessKey, scope, signedHeaders, signature))
+}
+
+// s3URIEncode percent-encodes s the way Signature Version 4 requires: every
+// byte except the RFC 3986 unreserved characters is encoded, and slashes
+// are kept unless encodeSlash is set
+func s3URIEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3CanonicalQuery returns the query parameters sorted and encoded for
+// Signature Version 4
+func s3CanonicalQuery(query url.Values) string {
+	type param struct{ key, value string }
+	var params []param
+	for key, values := range query {
+		for _, value := range values {
+			params = append(params, param{s3URIEncode(key, true), s3URIEncode(value, true)})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].key != params[j].key {
+			return params[i].key < params[j].key
+		}
+		return params[i].value < params[j].value
+	})
+
+	encoded := make([]string, len(params))
+	for i, p := range params {
+		encoded[i] = p.key + "=" + p.value
+	}
+	return strings.Join(encoded, "&")
+}
+
+// s3ResponseError builds an error from an unexpected S3 response
+func s3ResponseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return fmt.Errorf("s3 returned %s: %s", resp.Status, msg)
+	}
+	return fmt.Errorf("s3 returned %s", resp.Status)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// unmarshalVersioned decodes a stored file of kind into out. Files written in
+// an older schema version are upgraded with the registered migrations first;
+// files from a newer version are refused. out must point to the entity type
+// the kind is stored as.
+func unmarshalVersioned(kind entities.SchemaKind, data []byte, out interface{}) error {
+	if err := yaml.Unmarshal(data, out); err != nil {
+		return err
+	}
+
+	version, err := schemaVersionOf(out)
+	if err != nil {
+		return err
+	}
+	current := entities.CurrentSchemaVersion(kind)
+	if version == current {
+		return nil
+	}
+	if version > current {
+		return &entities.SchemaVersionError{Kind: kind, Version: version, Supported: current}
+	}
+
+	migrated, err := MigrateSchemaData(kind, data)
+	if err != nil {
+		return err
+	}
+	value := reflect.ValueOf(out).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	return yaml.Unmarshal(migrated, out)
+}
+
+// MigrateSchemaData upgrades the content of a stored file of kind to the
+// current schema version
+func MigrateSchemaData(kind entities.SchemaKind, data []byte) ([]byte, error) {
+	doc := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if _, err := entities.MigrateDocument(kind, doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(doc)
+}
+
+// schemaVersionOf returns the schema version of a decoded entity
+func schemaVersionOf(value interface{}) (int, error) {
+	switch v := value.(type) {
+	case *entities.Issue:
+		return v.SchemaVersion, nil
+	case *entities.IssueHistory:
+		return v.SchemaVersion, nil
+	case *entities.Dependency:
+		return v.SchemaVersion, nil
+	case *entities.TimeEntry:
+		return v.SchemaVersion, nil
+	case *entities.Config:
+		return v.SchemaVersion, nil
+	}
+	return 0, fmt.Errorf("%T is not a versioned entity", value)
+}
@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+func TestFileIssueRepository_SchemaVersion(t *testing.T) {
+	basePath := t.TempDir()
+	repo := NewFileIssueRepository(basePath)
+	ctx := context.Background()
+	issuePath := filepath.Join(basePath, "issues", "TEST-001.yaml")
+
+	// A file written before formats were versioned
+	require.NoError(t, os.MkdirAll(filepath.Dir(issuePath), 0755))
+	require.NoError(t, os.WriteFile(issuePath, []byte(`id: TEST-001
+title: Legacy issue
+type: bug
+status: open
+priority: low
+`), 0644))
+
+	issue, err := repo.GetByID(ctx, "TEST-001")
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy issue", issue.Title)
+	assert.Equal(t, entities.CurrentSchemaVersion(entities.SchemaKindIssue), issue.SchemaVersion)
+
+	require.NoError(t, repo.Update(ctx, issue))
+	data, err := os.ReadFile(issuePath)
+	require.NoError(t, err)
+	var stored map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &stored))
+	assert.Equal(t, entities.CurrentSchemaVersion(entities.SchemaKindIssue), stored["schema_version"])
+
+	// A file written by a newer issuemap is refused
+	require.NoError(t, os.WriteFile(issuePath, []byte("schema_version: 99\nid: TEST-001\ntitle: Future\n"), 0644))
+	_, err = repo.GetByID(ctx, "TEST-001")
+	var versionErr *entities.SchemaVersionError
+	assert.ErrorAs(t, err, &versionErr)
+}
+
+func TestMigrateSchemaData(t *testing.T) {
+	migrated, err := MigrateSchemaData(entities.SchemaKindDependency, []byte("id: A-blocks-B\nsource_id: A\n"))
+	require.NoError(t, err)
+
+	var dependency entities.Dependency
+	require.NoError(t, yaml.Unmarshal(migrated, &dependency))
+	assert.Equal(t, entities.CurrentSchemaVersion(entities.SchemaKindDependency), dependency.SchemaVersion)
+	assert.Equal(t, entities.IssueID("A"), dependency.SourceID)
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/domain/errors"
+)
+
+// ArchivedIssueDTO is an archived issue together with its index entry
+type ArchivedIssueDTO struct {
+	Entry *entities.ArchiveEntry `json:"entry"`
+	Issue IssueDTO               `json:"issue"`
+}
+
+// ArchiveRestoreRequest selects archived issues to restore
+type ArchiveRestoreRequest struct {
+	IssueIDs []string `json:"issue_ids"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+// ArchiveVerifyResult reports the integrity of an archive file
+type ArchiveVerifyResult struct {
+	ArchiveFile string `json:"archive_file"`
+	Valid       bool   `json:"valid"`
+	Error       string `json:"error,omitempty"`
+}
+
+// reloadArchiveIndex picks up archives written by the CLI since the server
+// started
+func (s *Server) reloadArchiveIndex(w http.ResponseWriter) bool {
+	if err := s.archiveService.ReloadIndex(); err != nil {
+		s.errorResponse(w, "Failed to load archive index: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+	return true
+}
+
+// listArchivesHandler handles GET /api/v1/archives
+func (s *Server) listArchivesHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.reloadArchiveIndex(w) {
+		return
+	}
+
+	summaries, err := s.archiveService.ListArchiveSummaries()
+	if err != nil {
+		s.errorResponse(w, "Failed to list archives: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: summaries, Count: len(summaries)}, http.StatusOK)
+}
+
+// archiveStatsHandler handles GET /api/v1/archives/stats
+func (s *Server) archiveStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.reloadArchiveIndex(w) {
+		return
+	}
+
+	stats, err := s.archiveService.GetArchiveStats()
+	if err != nil {
+		s.errorResponse(w, "Failed to get archive stats: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: stats}, http.StatusOK)
+}
+
+// searchArchivesHandler handles GET /api/v1/archives/search?q=
+func (s *Server) searchArchivesHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		s.errorResponse(w, "Query parameter q is required", http.StatusBadRequest)
+		return
+	}
+	if !s.reloadArchiveIndex(w) {
+		return
+	}
+
+	results, err := s.archiveService.SearchArchives(context.Background(), query)
+	if err != nil {
+		s.errorResponse(w, "Failed to search archives: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: results, Count: len(results)}, http.StatusOK)
+}
+
+// listArchivedIssuesHandler handles GET /api/v1/archives/issues?archive=
+func (s *Server) listArchivedIssuesHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.reloadArchiveIndex(w) {
+		return
+	}
+
+	entries, err := s.archiveService.ListArchivedIssues(r.URL.Query().Get("archive"))
+	if err != nil {
+		s.errorResponse(w, "Failed to list archived issues: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: entries, Count: len(entries)}, http.StatusOK)
+}
+
+// getArchivedIssueHandler handles GET /api/v1/archives/issues/{id}. The
+// issue is read from its archive without being restored.
+func (s *Server) getArchivedIssueHandler(w http.ResponseWriter, r *http.Request) {
+	issueID := entities.IssueID(mux.Vars(r)["id"])
+	if !s.reloadArchiveIndex(w) {
+		return
+	}
+
+	issue, entry, err := s.archiveService.GetArchivedIssue(context.Background(), issueID)
+	if err != nil {
+		if stderrors.Is(err, errors.ErrNotFound) {
+			s.errorResponse(w, "Archived issue not found", http.StatusNotFound)
+			return
+		}
+		s.errorResponse(w, "Failed to read archived issue: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: ArchivedIssueDTO{Entry: entry, Issue: issueToDTO(issue)}}, http.StatusOK)
+}
+
+// restoreArchivedIssuesHandler handles POST /api/v1/archives/restore
+func (s *Server) restoreArchivedIssuesHandler(w http.ResponseWriter, r *http.Request) {
+	var req ArchiveRestoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.errorResponse(w, "Invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+	if len(req.IssueIDs) == 0 {
+		s.errorResponse(w, "issue_ids is required", http.StatusBadRequest)
+		return
+	}
+	if !s.reloadArchiveIndex(w) {
+		return
+	}
+
+	issueIDs := make([]entities.IssueID, 0, len(req.IssueIDs))
+	for _, id := range req.IssueIDs {
+		issueIDs = append(issueIDs, entities.IssueID(id))
+	}
+
+	result, err := s.archiveService.RestoreIssues(context.Background(), issueIDs, req.DryRun)
+	if err != nil {
+		s.errorResponse(w, "Failed to restore issues: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if !req.DryRun && result.IssuesRestored > 0 {
+		if err := s.loadIssuesIntoMemory(); err != nil {
+			s.errorResponse(w, "Issues restored but reload failed: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: result}, http.StatusOK)
+}
+
+// verifyArchiveHandler handles POST /api/v1/archives/{file}/verify
+func (s *Server) verifyArchiveHandler(w http.ResponseWriter, r *http.Request) {
+	archiveFile := mux.Vars(r)["file"]
+	if !s.reloadArchiveIndex(w) {
+		return
+	}
+
+	result := ArchiveVerifyResult{ArchiveFile: archiveFile, Valid: true}
+	if err := s.archiveService.VerifyArchive(context.Background(), archiveFile); err != nil {
+		result.Valid = false
+		result.Error = err.Error()
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: result}, http.StatusOK)
+}
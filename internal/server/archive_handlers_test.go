@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ooyeku/issuemap/internal/app/services"
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+	"github.com/ooyeku/issuemap/internal/infrastructure/storage"
+)
+
+func newArchiveTestServer(t *testing.T) *Server {
+	t.Helper()
+	ctx := context.Background()
+	basePath := filepath.Join(t.TempDir(), ".issuemap")
+	configRepo := storage.NewFileConfigRepository(basePath)
+	require.NoError(t, configRepo.Initialize(ctx, entities.NewDefaultConfig()))
+	issueRepo := storage.NewFileIssueRepository(basePath)
+	attachmentRepo := storage.NewFileAttachmentRepository(basePath)
+
+	for _, id := range []entities.IssueID{"TEST-001", "TEST-002"} {
+		issue := entities.NewIssue(id, "Old "+string(id), "", entities.IssueTypeTask)
+		issue.UpdateStatus(entities.StatusClosed)
+		require.NoError(t, issueRepo.Create(ctx, issue))
+	}
+
+	archiveService := services.NewArchiveService(basePath, issueRepo, configRepo, attachmentRepo)
+	_, err := archiveService.ArchiveIssues(ctx, &entities.ArchiveFilter{IssueIDs: []entities.IssueID{"TEST-001", "TEST-002"}}, false)
+	require.NoError(t, err)
+
+	return &Server{basePath: basePath, archiveService: archiveService}
+}
+
+func TestListArchivesHandler(t *testing.T) {
+	s := newArchiveTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.listArchivesHandler(rec, httptest.NewRequest(http.MethodGet, "/api/v1/archives", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Success bool                       `json:"success"`
+		Data    []*entities.ArchiveSummary `json:"data"`
+		Count   int                        `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.True(t, resp.Success)
+	assert.Equal(t, 1, resp.Count)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, 2, resp.Data[0].TotalIssues)
+	assert.ElementsMatch(t, []entities.IssueID{"TEST-001", "TEST-002"}, resp.Data[0].IssueIDs)
+}
+
+func TestGetArchivedIssueHandler(t *testing.T) {
+	s := newArchiveTestServer(t)
+
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/v1/archives/issues/TEST-001", nil), map[string]string{"id": "TEST-001"})
+	rec := httptest.NewRecorder()
+	s.getArchivedIssueHandler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Data ArchivedIssueDTO `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "TEST-001", resp.Data.Issue.ID)
+	assert.Equal(t, "Old TEST-001", resp.Data.Issue.Title)
+	require.NotNil(t, resp.Data.Entry)
+
+	req = mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/v1/archives/issues/TEST-999", nil), map[string]string{"id": "TEST-999"})
+	rec = httptest.NewRecorder()
+	s.getArchivedIssueHandler(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -920,6 +920,14 @@ func (s *Server) downloadAttachmentHandler(w http.ResponseWriter, r *http.Reques
 		}
 	}()
 
+	// Strip scripts from SVG images before they reach a browser
+	servable, length, err := s.attachmentService.ServableContent(attachment, content)
+	if err != nil {
+		log.Printf("Failed to sanitize attachment %s: %v", attachmentID, err)
+		s.errorResponse(w, "Attachment content could not be sanitized", http.StatusUnprocessableEntity)
+		return
+	}
+
 	// Sanitize filename for header
 	safeFilename := strings.ReplaceAll(attachment.Filename, "\"", "")
 	safeFilename = strings.ReplaceAll(safeFilename, "\n", "")
@@ -928,15 +936,71 @@ func (s *Server) downloadAttachmentHandler(w http.ResponseWriter, r *http.Reques
 	// Set headers for file download
 	w.Header().Set("Content-Type", attachment.ContentType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", safeFilename))
-	w.Header().Set("Content-Length", fmt.Sprintf("%d", attachment.Size))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", length))
 	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src data:; sandbox")
 
 	// Stream the file content
-	if _, err := io.Copy(w, content); err != nil {
+	if _, err := io.Copy(w, servable); err != nil {
 		log.Printf("Error streaming attachment: %v", err)
 	}
 }
 
+// attachmentThumbnailHandler serves the PNG thumbnail of an image attachment
+func (s *Server) attachmentThumbnailHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	attachmentID := vars["id"]
+
+	if attachmentID == "" || len(attachmentID) > 200 {
+		s.errorResponse(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	thumbnail, _, err := s.attachmentService.GetThumbnail(ctx, attachmentID)
+	if err != nil {
+		s.errorResponse(w, "Thumbnail not available", http.StatusNotFound)
+		return
+	}
+	defer func() {
+		if err := thumbnail.Close(); err != nil {
+			log.Printf("Error closing thumbnail: %v", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if _, err := io.Copy(w, thumbnail); err != nil {
+		log.Printf("Error streaming thumbnail: %v", err)
+	}
+}
+
+// attachmentPreviewHandler returns a text, Markdown, CSV or JSON preview of an attachment
+func (s *Server) attachmentPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	attachmentID := vars["id"]
+
+	if attachmentID == "" || len(attachmentID) > 200 {
+		s.errorResponse(w, "Invalid attachment ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	preview, _, err := s.attachmentService.GetPreview(ctx, attachmentID, entities.DefaultPreviewBytes)
+	if err != nil {
+		log.Printf("Failed to build attachment preview: %v", err)
+		s.errorResponse(w, "Attachment not found", http.StatusNotFound)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{
+		Success: true,
+		Data:    preview,
+	}, http.StatusOK)
+}
+
 // deleteAttachmentHandler deletes an attachment
 func (s *Server) deleteAttachmentHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
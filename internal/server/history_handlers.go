@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// IssueVersion summarises one recorded version of an issue
+type IssueVersion struct {
+	Version   int                 `json:"version"`
+	Type      entities.ChangeType `json:"type"`
+	Author    string              `json:"author"`
+	Timestamp time.Time           `json:"timestamp"`
+	Message   string              `json:"message"`
+	Fields    []string            `json:"fields,omitempty"`
+}
+
+// RevertRequest represents the payload for reverting an issue
+type RevertRequest struct {
+	Version int `json:"version"`
+}
+
+// listIssueVersionsHandler handles GET /api/v1/history/{id}/versions
+func (s *Server) listIssueVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	issueID := entities.IssueID(mux.Vars(r)["id"])
+
+	history, err := s.issueService.GetIssueHistory(context.Background(), issueID)
+	if err != nil {
+		s.errorResponse(w, "Failed to get history: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	versions := make([]IssueVersion, 0, len(history.Entries))
+	for _, entry := range history.Entries {
+		version := IssueVersion{
+			Version:   entry.Version,
+			Type:      entry.Type,
+			Author:    entry.Author,
+			Timestamp: entry.Timestamp,
+			Message:   entry.Message,
+		}
+		for _, change := range entry.Changes {
+			version.Fields = append(version.Fields, change.Field)
+		}
+		versions = append(versions, version)
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: versions, Count: len(versions)}, http.StatusOK)
+}
+
+// getIssueVersionHandler handles GET /api/v1/history/{id}/versions/{version}
+func (s *Server) getIssueVersionHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	issueID := entities.IssueID(vars["id"])
+
+	version, err := parseVersionParam(vars["version"])
+	if err != nil {
+		s.errorResponse(w, "Invalid version", http.StatusBadRequest)
+		return
+	}
+
+	issue, err := s.issueService.GetIssueAtVersion(context.Background(), issueID, version)
+	if err != nil {
+		s.errorResponse(w, "Failed to reconstruct issue: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: issue}, http.StatusOK)
+}
+
+// diffIssueVersionsHandler handles GET /api/v1/history/{id}/diff?from=3&to=7
+func (s *Server) diffIssueVersionsHandler(w http.ResponseWriter, r *http.Request) {
+	issueID := entities.IssueID(mux.Vars(r)["id"])
+
+	from, err := parseVersionParam(r.URL.Query().Get("from"))
+	if err != nil {
+		s.errorResponse(w, "Invalid from version", http.StatusBadRequest)
+		return
+	}
+	to, err := parseVersionParam(r.URL.Query().Get("to"))
+	if err != nil {
+		s.errorResponse(w, "Invalid to version", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := s.issueService.DiffIssueVersions(context.Background(), issueID, from, to)
+	if err != nil {
+		s.errorResponse(w, "Failed to diff versions: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{Success: true, Data: changes, Count: len(changes)}, http.StatusOK)
+}
+
+// revertIssueHandler handles POST /api/v1/history/{id}/revert
+func (s *Server) revertIssueHandler(w http.ResponseWriter, r *http.Request) {
+	issueID := entities.IssueID(mux.Vars(r)["id"])
+
+	var req RevertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Version < 1 {
+		s.errorResponse(w, "Invalid JSON payload: a positive version is required", http.StatusBadRequest)
+		return
+	}
+
+	issue, changes, err := s.issueService.RevertIssue(context.Background(), issueID, req.Version)
+	if err != nil {
+		s.errorResponse(w, "Failed to revert issue: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.memoryStorage.Update(issue)
+
+	s.jsonResponse(w, APIResponse{
+		Success: true,
+		Data:    map[string]interface{}{"issue": issue, "changes": changes},
+		Count:   len(changes),
+	}, http.StatusOK)
+}
+
+// parseVersionParam parses a version path or query parameter such as "v5" or "5"
+func parseVersionParam(value string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(value), "v"))
+	if err != nil || version < 1 {
+		return 0, strconv.ErrSyntax
+	}
+	return version, nil
+}
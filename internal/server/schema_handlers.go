@@ -0,0 +1,42 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// listSchemasHandler handles GET /api/v1/schemas
+func (s *Server) listSchemasHandler(w http.ResponseWriter, r *http.Request) {
+	schemas := entities.ListSchemas()
+	s.jsonResponse(w, APIResponse{Success: true, Data: schemas, Count: len(schemas)}, http.StatusOK)
+}
+
+// getSchemaHandler handles GET /api/v1/schemas/{kind}. The schema is served
+// as a plain document rather than an APIResponse so validators can load it
+// directly.
+func (s *Server) getSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	kind, err := entities.ParseSchemaKind(mux.Vars(r)["kind"])
+	if err != nil {
+		s.errorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	schema, err := entities.GenerateJSONSchema(kind)
+	if err != nil {
+		s.errorResponse(w, "Failed to generate schema: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(schema); err != nil {
+		log.Printf("Failed to encode schema response: %v", err)
+	}
+}
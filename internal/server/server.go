@@ -34,10 +34,12 @@ type Server struct {
 	basePath           string
 	issueService       *services.IssueService
 	attachmentService  *services.AttachmentService
+	uploadService      *services.UploadService
 	storageService     *services.StorageService
 	cleanupService     *services.CleanupService
 	schedulerService   *services.SchedulerService
 	compressionService *services.CompressionService
+	archiveService     *services.ArchiveService
 	memoryStorage      *entities.IssueLinkedList
 	syncService        *SyncService
 	pidFile            string
@@ -84,14 +86,28 @@ func NewServer(basePath string) (*Server, error) {
 	storageService.SetArchiveService(archiveService)
 	storageService.SetCompressionService(compressionService)
 
-	// Create scheduler service
-	schedulerService := services.NewSchedulerService(cleanupService, storageService, archiveService)
-
 	// Create attachment service with storage service for quota checking
 	attachmentService := services.NewAttachmentService(attachmentRepo, issueRepo, storageService, basePath)
 
 	// Connect compression service to attachment service
 	attachmentService.SetCompressionService(compressionService)
+	attachmentService.SetThumbnailService(services.NewThumbnailService(attachmentRepo))
+
+	uploadService := services.NewUploadService(basePath, attachmentService)
+
+	// Create scheduler service and register the jobs each service provides
+	schedulerService := services.NewSchedulerService(storage.NewFileJobRepository(basePath), configRepo)
+	schedulerService.RegisterJobs(cleanupService.ScheduledJobs(storageService)...)
+	schedulerService.RegisterJobs(archiveService.ScheduledJobs()...)
+	schedulerService.RegisterJobs(compressionService.ScheduledJobs()...)
+	schedulerService.RegisterJobs(uploadService.ScheduledJobs()...)
+
+	historyService := services.NewHistoryService(storage.NewFileHistoryRepository(basePath), gitRepo)
+	staleService := services.NewStaleService(issueRepo, configRepo, historyService)
+	schedulerService.RegisterJobs(staleService.ScheduledJobs()...)
+
+	digestService := services.NewDigestService(storage.NewFileNotificationRepository(basePath), configRepo)
+	schedulerService.RegisterJobs(digestService.ScheduledJobs()...)
 
 	memoryStorage := entities.NewIssueLinkedList()
 
@@ -106,10 +122,12 @@ func NewServer(basePath string) (*Server, error) {
 		basePath:           basePath,
 		issueService:       issueService,
 		attachmentService:  attachmentService,
+		uploadService:      uploadService,
 		storageService:     storageService,
 		cleanupService:     cleanupService,
 		schedulerService:   schedulerService,
 		compressionService: compressionService,
+		archiveService:     archiveService,
 		memoryStorage:      memoryStorage,
 		pidFile:            filepath.Join(basePath, app.ServerPIDFile),
 		logFile:            filepath.Join(basePath, app.ServerLogFile),
@@ -340,17 +358,30 @@ func (s *Server) setupRouter() http.Handler {
 	issues.HandleFunc("/{id}/comments", s.addCommentHandler).Methods("POST")
 	issues.HandleFunc("/{id}/attachments", s.listAttachmentsHandler).Methods("GET")
 	issues.HandleFunc("/{id}/attachments", s.uploadAttachmentHandler).Methods("POST")
+	issues.HandleFunc("/{id}/uploads", s.createUploadHandler).Methods("POST")
+
+	// Resumable upload endpoints
+	uploads := api.PathPrefix("/uploads").Subrouter()
+	uploads.HandleFunc("/{id}", s.uploadStatusHandler).Methods("GET", "HEAD")
+	uploads.HandleFunc("/{id}", s.appendUploadHandler).Methods("PATCH")
+	uploads.HandleFunc("/{id}", s.cancelUploadHandler).Methods("DELETE")
 
 	// Attachment endpoints
 	attachments := api.PathPrefix("/attachments").Subrouter()
 	attachments.HandleFunc("/{id}", s.getAttachmentHandler).Methods("GET")
 	attachments.HandleFunc("/{id}/download", s.downloadAttachmentHandler).Methods("GET")
+	attachments.HandleFunc("/{id}/thumbnail", s.attachmentThumbnailHandler).Methods("GET")
+	attachments.HandleFunc("/{id}/preview", s.attachmentPreviewHandler).Methods("GET")
 	attachments.HandleFunc("/{id}", s.deleteAttachmentHandler).Methods("DELETE")
 
 	// History endpoints
 	history := api.PathPrefix("/history").Subrouter()
 	history.HandleFunc("", s.listHistoryHandler).Methods("GET")
 	history.HandleFunc("/{id}", s.getIssueHistoryHandler).Methods("GET")
+	history.HandleFunc("/{id}/versions", s.listIssueVersionsHandler).Methods("GET")
+	history.HandleFunc("/{id}/versions/{version}", s.getIssueVersionHandler).Methods("GET")
+	history.HandleFunc("/{id}/diff", s.diffIssueVersionsHandler).Methods("GET")
+	history.HandleFunc("/{id}/revert", s.revertIssueHandler).Methods("POST")
 
 	// Statistics endpoints
 	stats := api.PathPrefix("/stats").Subrouter()
@@ -370,6 +401,21 @@ func (s *Server) setupRouter() http.Handler {
 	cleanup.HandleFunc("/config", s.updateCleanupConfigHandler).Methods("PUT")
 	cleanup.HandleFunc("/status", s.getCleanupStatusHandler).Methods("GET")
 
+	// Archive endpoints
+	archives := api.PathPrefix("/archives").Subrouter()
+	archives.HandleFunc("", s.listArchivesHandler).Methods("GET")
+	archives.HandleFunc("/stats", s.archiveStatsHandler).Methods("GET")
+	archives.HandleFunc("/search", s.searchArchivesHandler).Methods("GET")
+	archives.HandleFunc("/issues", s.listArchivedIssuesHandler).Methods("GET")
+	archives.HandleFunc("/issues/{id}", s.getArchivedIssueHandler).Methods("GET")
+	archives.HandleFunc("/restore", s.restoreArchivedIssuesHandler).Methods("POST")
+	archives.HandleFunc("/{file}/verify", s.verifyArchiveHandler).Methods("POST")
+
+	// Schema endpoints
+	schemas := api.PathPrefix("/schemas").Subrouter()
+	schemas.HandleFunc("", s.listSchemasHandler).Methods("GET")
+	schemas.HandleFunc("/{kind}", s.getSchemaHandler).Methods("GET")
+
 	// Git endpoints
 	gitApi := api.PathPrefix("/git").Subrouter()
 	gitApi.HandleFunc("/commit/{hash}/diff", s.getCommitDiffHandler).Methods("GET")
@@ -381,8 +427,9 @@ func (s *Server) setupRouter() http.Handler {
 	// Setup CORS
 	c := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods: []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders: []string{"*"},
+		ExposedHeaders: []string{"Location", "Upload-Offset", "Upload-Length", "Tus-Resumable"},
 	})
 
 	return c.Handler(router)
@@ -423,6 +470,10 @@ func (s *Server) loadIssuesIntoMemory() error {
 			Comments:    make([]entities.Comment, len(original.Comments)),
 			Metadata:    original.Metadata,
 			Timestamps:  original.Timestamps,
+
+			Confidential: original.Confidential,
+			Sealed:       original.Sealed,
+			Redacted:     original.Redacted,
 		}
 		copy(issue.Labels, original.Labels)
 		copy(issue.Commits, original.Commits)
@@ -0,0 +1,202 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ooyeku/issuemap/internal/domain/entities"
+)
+
+// Resumable uploads follow the core tus protocol: POST creates an upload with
+// an Upload-Length, PATCH appends bytes at Upload-Offset, and HEAD reports
+// how many bytes have been received so an interrupted client can resume.
+const (
+	tusResumable         = "1.0.0"
+	tusOffsetContentType = "application/offset+octet-stream"
+)
+
+// createUploadHandler starts a resumable upload for an issue
+func (s *Server) createUploadHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	issueID := entities.IssueID(vars["id"])
+	w.Header().Set("Tus-Resumable", tusResumable)
+
+	if issueID == "" {
+		s.errorResponse(w, "Invalid issue ID", http.StatusBadRequest)
+		return
+	}
+
+	size, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || size <= 0 {
+		s.errorResponse(w, "Upload-Length header must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	metadata := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	filename := strings.TrimSpace(metadata["filename"])
+	if filename == "" {
+		s.errorResponse(w, "Upload-Metadata must include a filename", http.StatusBadRequest)
+		return
+	}
+
+	description := strings.TrimSpace(metadata["description"])
+	if len(description) > 500 {
+		s.errorResponse(w, "Description too long (max 500 characters)", http.StatusBadRequest)
+		return
+	}
+
+	uploadedBy := strings.TrimSpace(metadata["uploaded_by"])
+	if uploadedBy == "" {
+		uploadedBy = "anonymous"
+	}
+	if len(uploadedBy) > 100 {
+		s.errorResponse(w, "Uploaded by field too long (max 100 characters)", http.StatusBadRequest)
+		return
+	}
+
+	session := entities.NewUploadSession(issueID, filename, size, uploadedBy)
+	session.Description = description
+
+	ctx := context.Background()
+	if err := s.uploadService.CreateUpload(ctx, session); err != nil {
+		log.Printf("Failed to create upload: %v", err)
+		s.errorResponse(w, err.Error(), uploadErrorStatus(err))
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/uploads/"+session.ID)
+	w.Header().Set("Upload-Offset", "0")
+	s.jsonResponse(w, APIResponse{
+		Success: true,
+		Data:    session,
+	}, http.StatusCreated)
+}
+
+// uploadStatusHandler reports the progress of a resumable upload
+func (s *Server) uploadStatusHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	w.Header().Set("Tus-Resumable", tusResumable)
+	w.Header().Set("Cache-Control", "no-store")
+
+	ctx := context.Background()
+	session, err := s.uploadService.GetUpload(ctx, vars["id"])
+	if err != nil {
+		s.errorResponse(w, "Upload not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(session.Size, 10))
+
+	if r.Method == http.MethodHead {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	s.jsonResponse(w, APIResponse{
+		Success: true,
+		Data:    session,
+	}, http.StatusOK)
+}
+
+// appendUploadHandler appends a chunk to a resumable upload
+func (s *Server) appendUploadHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	w.Header().Set("Tus-Resumable", tusResumable)
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), tusOffsetContentType) {
+		s.errorResponse(w, "Content-Type must be "+tusOffsetContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		s.errorResponse(w, "Upload-Offset header must be a non-negative integer", http.StatusBadRequest)
+		return
+	}
+
+	ctx := context.Background()
+	session, attachment, err := s.uploadService.AppendChunk(ctx, vars["id"], offset, r.Body)
+	if session != nil {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(session.Offset, 10))
+	}
+	if err != nil {
+		log.Printf("Failed to append upload chunk: %v", err)
+		s.errorResponse(w, err.Error(), uploadErrorStatus(err))
+		return
+	}
+
+	if attachment == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	// The upload is complete
+	s.jsonResponse(w, APIResponse{
+		Success: true,
+		Data:    attachment,
+	}, http.StatusOK)
+}
+
+// cancelUploadHandler discards a resumable upload
+func (s *Server) cancelUploadHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	w.Header().Set("Tus-Resumable", tusResumable)
+
+	ctx := context.Background()
+	if err := s.uploadService.CancelUpload(ctx, vars["id"]); err != nil {
+		s.errorResponse(w, "Upload not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseUploadMetadata decodes a tus Upload-Metadata header: comma-separated
+// pairs of a key and a base64-encoded value
+func parseUploadMetadata(header string) map[string]string {
+	metadata := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		fields := strings.Fields(pair)
+		if len(fields) == 0 {
+			continue
+		}
+		value := ""
+		if len(fields) > 1 {
+			decoded, err := base64.StdEncoding.DecodeString(fields[1])
+			if err != nil {
+				continue
+			}
+			value = string(decoded)
+		}
+		metadata[fields[0]] = value
+	}
+	return metadata
+}
+
+// uploadErrorStatus maps an upload error to an HTTP status code
+func uploadErrorStatus(err error) int {
+	errMsg := err.Error()
+	switch {
+	case strings.Contains(errMsg, "offset_mismatch"):
+		return http.StatusConflict
+	case strings.Contains(errMsg, "quota_exceeded"),
+		strings.Contains(errMsg, "exceeds maximum allowed size"),
+		strings.Contains(errMsg, "exceeds declared length"):
+		return http.StatusRequestEntityTooLarge
+	case strings.Contains(errMsg, "security_validation"),
+		strings.Contains(errMsg, "mime_validation"),
+		strings.Contains(errMsg, "not allowed"),
+		strings.Contains(errMsg, "invalid"):
+		return http.StatusBadRequest
+	case strings.Contains(errMsg, "not found"):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
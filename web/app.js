@@ -6,6 +6,10 @@
   let issues = [];
   let filtered = [];
   let selectedId = null;
+  let view = 'issues';
+  let archivedEntries = [];
+  let archiveMatches = [];
+  let archiveQuery = '';
 
   // State Management System
   const StateManager = {
@@ -251,14 +255,14 @@
     };
   }
 
-  function renderIssues(list){
+  function renderIssues(list, archivedList = []){
     const tbody = $('#issuesTbody');
     if (!tbody) return;
     tbody.innerHTML = '';
     const badge = $('#countBadge');
-    if (badge) badge.textContent = list.length;
+    if (badge) badge.textContent = list.length + archivedList.length;
 
-    if (!list.length) {
+    if (!list.length && !archivedList.length) {
       const tr = document.createElement('tr');
       tr.innerHTML = '<td colspan="8" class="empty">No issues found</td>';
       tbody.appendChild(tr);
@@ -305,11 +309,61 @@
 
       frag.appendChild(tr);
     });
+    archivedList.forEach(entry => frag.appendChild(archivedRow(entry)));
 
     tbody.appendChild(frag);
     setSelectedRow();
   }
 
+  function archivedBadge(){
+    const span = document.createElement('span');
+    span.className = 'pill archived';
+    span.textContent = 'archived';
+    return span;
+  }
+
+  // archivedRow renders an archive index entry in the issues table
+  function archivedRow(entry){
+    const tr = document.createElement('tr');
+    tr.className = 'archived';
+    tr.dataset.id = String(entry.issue_id);
+    tr.dataset.archived = 'true';
+    tr.innerHTML = `
+      <td><code>${escapeHtml(entry.issue_id)}</code></td>
+      <td class="title-cell">${escapeHtml(entry.title)}</td>
+      <td></td>
+      <td></td>
+      <td class="labels"></td>
+      <td></td>
+      <td></td>
+      <td class="row-actions"></td>
+    `;
+    tr.addEventListener('click', () => openRow(tr));
+    tr.querySelector('.title-cell').appendChild(archivedBadge());
+    tr.querySelector('td:nth-child(3)').appendChild(statusPill(entry.status));
+
+    const action = document.createElement('a');
+    action.href = 'javascript:void(0)';
+    action.className = 'action-link';
+    action.textContent = 'Restore';
+    action.addEventListener('click', (e)=>{ e.stopPropagation(); restoreArchived(entry.issue_id); });
+    tr.querySelector('.row-actions').appendChild(action);
+    return tr;
+  }
+
+  // openRow selects a table row and shows its details. Archived issues are
+  // read from their archive and are not reflected in the URL hash.
+  function openRow(tr){
+    selectedId = tr.dataset.id;
+    setSelectedRow();
+    if (tr.dataset.archived) {
+      loadArchivedDetail(selectedId);
+    } else {
+      updateHashFromSelection();
+      loadDetail(selectedId);
+    }
+  }
+
   function renderDetailEmpty(msg){
     const c = $('#detailContent');
     if (!c) return;
@@ -339,6 +393,63 @@
     }
   }
 
+  async function loadArchivedDetail(id){
+    const c = $('#detailContent');
+    if (!c) return;
+    renderDetailEmpty('Loading…');
+    try {
+      const r = await fetch(`${API_BASE}/archives/issues/${encodeURIComponent(id)}`);
+      if (!r.ok) throw new Error('Failed to fetch archived issue');
+      const j = await r.json();
+      if (j && j.success && j.data) {
+        renderArchivedDetail(j.data.entry || {}, j.data.issue || {});
+      } else {
+        c.innerHTML = `<div class="error">Failed to load archived issue</div>`;
+      }
+    } catch(e){
+      c.innerHTML = `<div class="error">Failed to load archived issue</div>`;
+    }
+  }
+
+  function renderArchivedDetail(entry, iss){
+    const c = $('#detailContent');
+    if (!c) return;
+    c.innerHTML = '';
+
+    const title = document.createElement('h3');
+    title.innerHTML = `${escapeHtml(iss.id)} — ${escapeHtml(iss.title || '(untitled)')}`;
+    title.appendChild(archivedBadge());
+
+    const descr = document.createElement('p');
+    descr.textContent = (iss.description || '').trim() || '(no description)';
+
+    const grid = document.createElement('div');
+    grid.className = 'kv';
+    grid.innerHTML = `
+      <div class="key">Type</div><div class="val"><code>${escapeHtml(iss.type || '')}</code></div>
+      <div class="key">Status</div><div class="val" data-field="status"></div>
+      <div class="key">Priority</div><div class="val"><span class="priority ${escapeHtml(iss.priority || '')}">${escapeHtml(iss.priority || '')}</span></div>
+      <div class="key">Assignee</div><div class="val">${escapeHtml(iss.assignee || '')}</div>
+      <div class="key">Labels</div><div class="val labels" data-field="labels"></div>
+      <div class="key">Created</div><div class="val"><code>${escapeHtml(((iss.timestamps||{}).created)||'')}</code></div>
+      <div class="key">Archived</div><div class="val"><code>${escapeHtml(entry.archived_at || '')}</code></div>
+      <div class="key">Archive</div><div class="val"><code>${escapeHtml(entry.archive_file || '')}</code></div>
+    `;
+    grid.querySelector('[data-field="status"]').appendChild(statusPill(iss.status));
+    const labelsEl = grid.querySelector('[data-field="labels"]');
+    (iss.labels || []).forEach(l => labelsEl.appendChild(labelPill(l)));
+
+    const restoreBtn = document.createElement('button');
+    restoreBtn.className = 'btn';
+    restoreBtn.textContent = 'Restore issue';
+    restoreBtn.addEventListener('click', () => restoreArchived(iss.id));
+
+    c.appendChild(title);
+    c.appendChild(grid);
+    c.appendChild(descr);
+    c.appendChild(restoreBtn);
+  }
+
   async function copyToClipboard(text){
     try {
       await navigator.clipboard.writeText(String(text||''));
@@ -472,6 +583,15 @@
             <a class="attachment-delete action-link" href="javascript:void(0)" data-attachment-id="${escapeHtml(att.id)}" data-filename="${escapeHtml(att.filename)}">Delete</a>
           </div>
         `;
+        if (att.type === 'image') {
+          // Swap the icon for a thumbnail; keep the icon if none can be made
+          const thumb = document.createElement('img');
+          thumb.className = 'attachment-thumb';
+          thumb.alt = '';
+          thumb.loading = 'lazy';
+          thumb.addEventListener('load', () => item.querySelector('.attachment-icon').replaceWith(thumb));
+          thumb.src = `${API_BASE}/attachments/${encodeURIComponent(att.id)}/thumbnail`;
+        }
         container.appendChild(item);
       });
       
@@ -612,6 +732,10 @@
     const status = $('#statusFilter').value;
     const priority = $('#priorityFilter').value;
     const q = $('#searchInput').value.trim().toLowerCase();
+    if (view === 'archived') {
+      renderArchived();
+      return;
+    }
     filtered = issues.filter(iss => {
       if (status && iss.status !== status) return false;
       if (priority && iss.priority !== priority) return false;
@@ -621,10 +745,113 @@
       }
       return true;
     });
-    renderIssues(filtered);
+    if (!q) {
+      archiveMatches = [];
+      archiveQuery = '';
+    } else if (q !== archiveQuery) {
+      archiveMatches = [];
+      searchArchives(q);
+    }
+    renderIssues(filtered, archivedMatchesFor(status, priority));
     restoreSelection();
   }
 
+  // archivedMatchesFor narrows archive search results to the status filter.
+  // Archive entries carry no priority, so they are hidden when one is set.
+  function archivedMatchesFor(status, priority){
+    if (priority) return [];
+    return archiveMatches.filter(entry => !status || entry.status === status);
+  }
+
+  async function searchArchives(q){
+    archiveQuery = q;
+    try {
+      const r = await fetch(`${API_BASE}/archives/search?q=${encodeURIComponent(q)}`);
+      if (!r.ok) throw new Error('archive search failed');
+      const j = await r.json().catch(() => ({ success: false }));
+      // Drop responses for queries that have since changed
+      if (!j.success || q !== archiveQuery || view !== 'issues') return;
+      archiveMatches = (j.data || []).map(result => result.entry);
+      renderIssues(filtered, archivedMatchesFor($('#statusFilter').value, $('#priorityFilter').value));
+    } catch (e) {
+      // Archived matches are a supplement; active issues are already shown
+    }
+  }
+
+  function renderArchived(){
+    const status = $('#statusFilter').value;
+    const q = $('#searchInput').value.trim().toLowerCase();
+    const list = archivedEntries.filter(entry => {
+      if (status && entry.status !== status) return false;
+      if (q) {
+        const hay = (entry.issue_id + ' ' + (entry.title||'')).toLowerCase();
+        if (!hay.includes(q)) return false;
+      }
+      return true;
+    });
+    renderIssues([], list);
+  }
+
+  async function fetchArchived(){
+    try {
+      const r = await fetch(API_BASE + '/archives/issues');
+      if (!r.ok) throw new Error('archived issues fetch failed');
+      const j = await r.json().catch(() => ({ success: false }));
+      if (j.success) {
+        archivedEntries = j.data || [];
+        renderArchived();
+      } else {
+        toast('Failed to load archived issues');
+      }
+    } catch (e) {
+      toast('Network error while loading archived issues');
+    }
+  }
+
+  async function restoreArchived(id){
+    try {
+      const r = await fetch(`${API_BASE}/archives/restore`, { method: 'POST', headers: { 'Content-Type': 'application/json' }, body: JSON.stringify({ issue_ids: [id] }) });
+      const j = await r.json().catch(() => ({ success: false }));
+      const result = j.data || {};
+      if (!r.ok || !j.success || !result.issues_restored) {
+        toast((result.errors && result.errors[0]) || j.error || 'Failed to restore');
+        return;
+      }
+      toast('Issue restored');
+      archiveQuery = '';
+      await fetchIssues();
+      if (view === 'archived') {
+        await fetchArchived();
+        selectedId = null;
+        setSelectedRow();
+        renderDetailEmpty();
+      } else {
+        location.hash = '#' + encodeURIComponent(id);
+      }
+    } catch(e) { toast('Network error'); }
+  }
+
+  function setView(next){
+    if (view === next) return;
+    view = next;
+    $$('.tab').forEach(t => t.classList.toggle('active', t.dataset.view === next));
+    selectedId = null;
+    renderDetailEmpty();
+    if (next === 'archived') {
+      if (location.hash) history.replaceState(null, '', location.pathname + location.search);
+      renderArchived();
+      fetchArchived();
+    } else {
+      applyFilters();
+    }
+  }
+
+  function refresh(){
+    if (view === 'archived') return fetchArchived();
+    archiveQuery = '';
+    return fetchIssues();
+  }
+
   async function fetchInfo(){
     try {
       const r = await fetch(API_BASE + '/info');
@@ -730,7 +957,8 @@
   }
 
   function wire(){
-    $('#refreshBtn').addEventListener('click', fetchIssues);
+    $('#refreshBtn').addEventListener('click', refresh);
+    $$('.tab').forEach(t => t.addEventListener('click', () => setView(t.dataset.view)));
     $('#statusFilter').addEventListener('change', fetchIssues);
     $('#priorityFilter').addEventListener('change', fetchIssues);
     const searchEl = $('#searchInput');
@@ -746,13 +974,11 @@
       let idx = rows.findIndex(r => r.dataset.id === String(selectedId));
       if (e.key === 'ArrowDown') {
         idx = Math.min(rows.length - 1, Math.max(0, idx + 1));
-        selectedId = rows[idx].dataset.id;
-        setSelectedRow(); updateHashFromSelection(); loadDetail(selectedId);
+        openRow(rows[idx]);
         e.preventDefault();
       } else if (e.key === 'ArrowUp') {
         idx = Math.max(0, (idx <= 0 ? 0 : idx - 1));
-        selectedId = rows[idx].dataset.id;
-        setSelectedRow(); updateHashFromSelection(); loadDetail(selectedId);
+        openRow(rows[idx]);
         e.preventDefault();
       }
     });